@@ -43,6 +43,7 @@ type WebhookItem = {
   replayCount: number;
   receivedAt: string;
   lastReplayAt?: string;
+  rejectionReason?: string;
 };
 
 const safeText = (value: unknown, fallback = '') => {
@@ -164,6 +165,7 @@ const normalizeWebhooks = (data: unknown): { items: WebhookItem[]; total: number
           replayCount?: number;
           receivedAt?: string;
           lastReplayAt?: string;
+          rejectionReason?: string;
         };
         if (!record.id) {
           return null;
@@ -176,7 +178,8 @@ const normalizeWebhooks = (data: unknown): { items: WebhookItem[]; total: number
           status: safeText(record.status, '-'),
           replayCount: safeNumber(record.replayCount, 0),
           receivedAt: safeText(record.receivedAt, ''),
-          lastReplayAt: safeText(record.lastReplayAt)
+          lastReplayAt: safeText(record.lastReplayAt),
+          rejectionReason: safeText(record.rejectionReason)
         } as WebhookItem;
       })
       .filter(Boolean) as WebhookItem[],
@@ -568,17 +571,24 @@ export const PaymentsPage = () => {
                       <td className="px-4 py-3 text-xs text-text-secondary-light dark:text-text-secondary-dark">{item.provider}</td>
                       <td className="px-4 py-3 text-xs text-text-secondary-light dark:text-text-secondary-dark">{item.eventType}</td>
                       <td className="px-4 py-3 text-xs text-text-secondary-light dark:text-text-secondary-dark">{item.transactionId}</td>
-                      <td className="px-4 py-3 text-xs text-text-secondary-light dark:text-text-secondary-dark">{item.status}</td>
+                      <td className="px-4 py-3 text-xs text-text-secondary-light dark:text-text-secondary-dark">
+                        <p>{item.status}</p>
+                        {item.rejectionReason ? (
+                          <p className="text-red-600 dark:text-red-400" data-testid={`webhook-rejection-${item.id}`}>{item.rejectionReason}</p>
+                        ) : null}
+                      </td>
                       <td className="px-4 py-3 text-xs text-text-secondary-light dark:text-text-secondary-dark">{item.replayCount}</td>
                       <td className="px-4 py-3">
-                        <button
-                          className="rounded border border-primary px-2.5 py-1 text-xs font-semibold text-primary transition-colors hover:bg-primary/10"
-                          data-testid={`webhook-replay-${item.id}`}
-                          onClick={() => void handleReplayWebhook(item)}
-                          type="button"
-                        >
-                          重放
-                        </button>
+                        {item.status === 'rejected' ? null : (
+                          <button
+                            className="rounded border border-primary px-2.5 py-1 text-xs font-semibold text-primary transition-colors hover:bg-primary/10"
+                            data-testid={`webhook-replay-${item.id}`}
+                            onClick={() => void handleReplayWebhook(item)}
+                            type="button"
+                          >
+                            重放
+                          </button>
+                        )}
                       </td>
                     </tr>
                   ))}
@@ -5,7 +5,7 @@ import Navbar from '@taroify/core/navbar'
 import Tabs from '@taroify/core/tabs'
 import Cell from '@taroify/core/cell'
 import Tag from '@taroify/core/tag'
-import type { AfterSalesTicket, PriceInquiry } from '@tmo/api-client'
+import { QuotationStatus, type AfterSalesTicket, type PriceInquiry } from '@tmo/api-client'
 import { getNavbarStyle } from '../../utils/navbar'
 import { commerceServices } from '../../services/commerce'
 import { ROUTES } from '../../routes'
@@ -41,6 +41,38 @@ export default function SupportCustomerView({ onBack }: SupportCustomerViewProps
     })()
   }, [])
 
+  const handleOpenQuotation = async (inquiry: PriceInquiry) => {
+    try {
+      const { items } = await commerceServices.inquiries.listQuotations(inquiry.id)
+      const quotation = items[0]
+      if (!quotation) {
+        await Taro.showToast({ title: '暂无报价', icon: 'none' })
+        return
+      }
+      if (quotation.status !== QuotationStatus.SENT) {
+        await Taro.showToast({ title: `报价 v${quotation.version}：${quotation.status}`, icon: 'none' })
+        return
+      }
+
+      const result = await Taro.showActionSheet({
+        itemList: [`接受报价（¥${(quotation.totalFen / 100).toFixed(2)}）`, '拒绝报价']
+      })
+      if (result.tapIndex === 0) {
+        await commerceServices.inquiries.acceptQuotation(quotation.id)
+        await Taro.showToast({ title: '已接受报价', icon: 'success' })
+      } else if (result.tapIndex === 1) {
+        await commerceServices.inquiries.rejectQuotation(quotation.id)
+        await Taro.showToast({ title: '已拒绝报价', icon: 'success' })
+      }
+    } catch (error) {
+      if ((error as { errMsg?: string })?.errMsg?.includes('cancel')) {
+        return
+      }
+      console.warn('handle quotation failed', error)
+      await Taro.showToast({ title: '报价操作失败', icon: 'none' })
+    }
+  }
+
   return (
     <View className='page'>
       <Navbar bordered fixed placeholder style={navbarStyle} className='app-navbar app-navbar--secondary'>
@@ -82,6 +114,7 @@ export default function SupportCustomerView({ onBack }: SupportCustomerViewProps
                 title={inquiry.message}
                 brief={`状态：${inquiry.status}`}
                 rightIcon={<Tag size='small' color='primary'>询价</Tag>}
+                onClick={() => void handleOpenQuotation(inquiry)}
               />
             ))}
             {inquiries.length === 0 ? (
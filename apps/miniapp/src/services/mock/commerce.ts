@@ -436,6 +436,7 @@ export const createMockCommerceServices = (): CommerceServices => {
       })
       const order = applyPaymentSessionToOrder({
         id: orderId,
+        orderNo: `TMO${createdAt.slice(0, 10).replace(/-/g, '')}-${String(Date.now() % 1000000).padStart(6, '0')}`,
         status: OrderStatus.SUBMITTED,
         paymentStatus: 'UNPAID',
         address: request.address,
@@ -1070,6 +1071,19 @@ export const createMockCommerceServices = (): CommerceServices => {
         }
       }))
       return message
+    },
+    listQuotations: async () => ({ items: [] }),
+    createQuotation: async () => {
+      throw new Error('quotations are not supported in mock mode')
+    },
+    getQuotation: async (quotationId) => {
+      throw new Error(`quotation not found: ${quotationId}`)
+    },
+    acceptQuotation: async (quotationId) => {
+      throw new Error(`quotation not found: ${quotationId}`)
+    },
+    rejectQuotation: async (quotationId) => {
+      throw new Error(`quotation not found: ${quotationId}`)
     }
   }
 
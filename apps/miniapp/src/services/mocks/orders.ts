@@ -35,6 +35,7 @@ const cloneTracking = (tracking: any): TrackingInfo => {
 export const buildSeedOrders = (): Order[] => {
   return (canonicalOrderFixtures as any[]).map((fixture) => ({
     id: String(fixture?.id || ''),
+    orderNo: String(fixture?.orderNo || fixture?.id || ''),
     status: String(fixture?.status || 'SUBMITTED') as Order['status'],
     paymentStatus: String(fixture?.paymentStatus || 'UNPAID') as Order['paymentStatus'],
     latestPaymentId: typeof fixture?.latestPaymentId === 'string' ? fixture.latestPaymentId : undefined,
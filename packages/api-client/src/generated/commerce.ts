@@ -135,6 +135,16 @@ export interface CreateSkuRequest {
   priceTiers?: PriceTier[];
   unit?: string;
   isActive?: boolean;
+  /**
+   * Shipping weight of one unit in grams
+   * @minimum 0
+   */
+  weightGrams?: number;
+  /**
+   * Shipping volume of one unit in cubic centimetres
+   * @minimum 0
+   */
+  volumeCm3?: number;
 }
 
 export type UpdateSkuRequestAttributes = {[key: string]: string};
@@ -148,6 +158,16 @@ export interface UpdateSkuRequest {
   priceTiers?: PriceTier[];
   unit?: string;
   isActive?: boolean;
+  /**
+   * Shipping weight of one unit in grams
+   * @minimum 0
+   */
+  weightGrams?: number;
+  /**
+   * Shipping volume of one unit in cubic centimetres
+   * @minimum 0
+   */
+  volumeCm3?: number;
 }
 
 export interface PagedProductList {
@@ -157,6 +177,63 @@ export interface PagedProductList {
   total: number;
 }
 
+export interface CatalogSearchResult {
+  items: CatalogSearchHit[];
+  page: number;
+  pageSize: number;
+  total: number;
+  facets: CatalogSearchFacets;
+}
+
+export interface CatalogSearchHit {
+  product: ProductSummary;
+  /** Relevance; higher is better and only comparable within one search */
+  score: number;
+  highlights: CatalogSearchHighlights;
+  matchedSku?: CatalogSearchMatchedSku;
+}
+
+/**
+ * HTML-escaped text with matches wrapped in <em> tags
+ */
+export interface CatalogSearchHighlights {
+  name: string;
+  /** Excerpt of the description around its first match; omitted when the description does not match */
+  description?: string;
+}
+
+/**
+ * The first active SKU whose name, code or spec contains the query
+ */
+export interface CatalogSearchMatchedSku {
+  id: string;
+  name: string;
+  skuCode?: string;
+  spec?: string;
+  /** HTML-escaped SKU name with matches wrapped in <em> tags */
+  highlight: string;
+}
+
+/**
+ * Match counts over all matching products, at most 20 values per facet
+ */
+export interface CatalogSearchFacets {
+  categories: CatalogSearchCategoryFacet[];
+  tags: CatalogSearchFacetValue[];
+  filterDimensions: CatalogSearchFacetValue[];
+}
+
+export interface CatalogSearchCategoryFacet {
+  categoryId: string;
+  name: string;
+  count: number;
+}
+
+export interface CatalogSearchFacetValue {
+  value: string;
+  count: number;
+}
+
 export interface PriceTier {
   /** @minimum 1 */
   minQty: number;
@@ -169,6 +246,14 @@ export interface PriceTier {
   unitPriceFen: number;
 }
 
+/**
+ * Customer price list that set the SKU's priceTiers; omitted at catalog prices
+ */
+export interface AppliedPriceList {
+  id: string;
+  name: string;
+}
+
 export type SkuAttributes = {[key: string]: string};
 
 export interface Sku {
@@ -179,9 +264,21 @@ export interface Sku {
   /** Canonical spec label for matching; do not duplicate in attributes */
   spec?: string;
   attributes?: SkuAttributes;
+  /** Tiers the caller pays; a customer's price list replaces or discounts the catalog tiers */
   priceTiers?: PriceTier[];
+  appliedPriceList?: AppliedPriceList;
   unit?: string;
   isActive: boolean;
+  /**
+   * Shipping weight of one unit in grams
+   * @minimum 0
+   */
+  weightGrams?: number;
+  /**
+   * Shipping volume of one unit in cubic centimetres
+   * @minimum 0
+   */
+  volumeCm3?: number;
 }
 
 export type ProductDetailProduct = {
@@ -303,10 +400,98 @@ export type CreateOrderRequestItemsItem = {
   qty: number;
 };
 
+/**
+ * Orders either cart items at tier prices or an open quotation at its quoted prices; exactly one of items and quotationId is required.
+ */
 export interface CreateOrderRequest {
   address: Address;
   remark?: string;
-  items: CreateOrderRequestItemsItem[];
+  items?: CreateOrderRequestItemsItem[];
+  /** Converts a SENT or ACCEPTED quotation of the caller into this order. The quotation's lines and prices are used and it becomes CONVERTED. */
+  quotationId?: string;
+  /**
+   * Coupon to redeem, compared case-insensitively. Only cart orders take coupons.
+   * @maxLength 64
+   */
+  couponCode?: string;
+}
+
+export type CheckoutPreviewRequestItemsItem = {
+  cartItemId: string;
+  skuId: string;
+  /** @minimum 1 */
+  qty: number;
+  /** Unit price the customer was shown. A different current price is reported as PRICE_CHANGED. */
+  unitPriceFen?: number;
+};
+
+export interface CheckoutPreviewRequest {
+  address: Address;
+  /** @minItems 1 */
+  items: CheckoutPreviewRequestItemsItem[];
+  /** @maxLength 64 */
+  couponCode?: string;
+}
+
+export interface CheckoutPreview {
+  items: CheckoutPreviewLine[];
+  /** Sum of the lines that can be ordered, in fen. */
+  subtotalFen: number;
+  discountFen: number;
+  discounts: OrderDiscount[];
+  /** Freight to the address for the lines that can be ordered, in fen. */
+  shippingFen: number;
+  totalFen: number;
+  /** False when POST /orders would reject these items or coupon; the blocking warnings say why. */
+  orderable: boolean;
+  warnings: CheckoutWarning[];
+}
+
+export interface CheckoutPreviewLine {
+  cartItemId: string;
+  skuId: string;
+  sku?: Sku;
+  qty: number;
+  priceTier?: PriceTier;
+  unitPriceFen?: number;
+  lineTotalFen?: number;
+}
+
+export type CheckoutWarningCode = typeof CheckoutWarningCode[keyof typeof CheckoutWarningCode];
+
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const CheckoutWarningCode = {
+  SKU_NOT_FOUND: 'SKU_NOT_FOUND',
+  SKU_INACTIVE: 'SKU_INACTIVE',
+  PRICE_UNAVAILABLE: 'PRICE_UNAVAILABLE',
+  CART_ITEM_CHANGED: 'CART_ITEM_CHANGED',
+  PRICE_CHANGED: 'PRICE_CHANGED',
+  TIER_BREAKPOINT_NEAR: 'TIER_BREAKPOINT_NEAR',
+  COUPON_INVALID: 'COUPON_INVALID',
+  COUPON_UNAVAILABLE: 'COUPON_UNAVAILABLE',
+  INSUFFICIENT_STOCK: 'INSUFFICIENT_STOCK',
+  CREDIT_LIMIT_EXCEEDED: 'CREDIT_LIMIT_EXCEEDED',
+} as const;
+
+/**
+ * SKU_NOT_FOUND, SKU_INACTIVE, PRICE_UNAVAILABLE, CART_ITEM_CHANGED, INSUFFICIENT_STOCK, COUPON_INVALID, COUPON_UNAVAILABLE and CREDIT_LIMIT_EXCEEDED block the order.
+PRICE_CHANGED and TIER_BREAKPOINT_NEAR are advisory; TIER_BREAKPOINT_NEAR means ordering additionalQty more of the SKU, at most a fifth of nextTierMinQty, reaches a cheaper tier.
+INSUFFICIENT_STOCK carries availableQty of the SKU; CREDIT_LIMIT_EXCEEDED carries creditLimitFen and outstandingFen of a customer on monthly terms.
+ */
+export interface CheckoutWarning {
+  code: CheckoutWarningCode;
+  message: string;
+  blocking: boolean;
+  cartItemId?: string;
+  skuId?: string;
+  nextTierMinQty?: number;
+  nextTierUnitPriceFen?: number;
+  additionalQty?: number;
+  availableQty?: number;
+  creditLimitFen?: number;
+  /** Open receivables of the customer before this order. */
+  outstandingFen?: number;
 }
 
 export type OrderStatus = typeof OrderStatus[keyof typeof OrderStatus];
@@ -335,6 +520,8 @@ export interface OrderItem {
 
 export interface Order {
   id: string;
+  /** Sequential order number prefixed with the Asia/Shanghai creation date, for example TMO20261017-000123. */
+  orderNo: string;
   status: OrderStatus;
   paymentStatus: OrderPaymentStatus;
   /** @nullable */
@@ -346,11 +533,28 @@ export interface Order {
   paidAt?: string | null;
   address?: Address;
   items: OrderItem[];
+  /** Sum of the order lines in fen, before discounts. */
+  subtotalFen?: number;
+  /** Promotion discounts taken off subtotalFen, in fen. */
+  discountFen?: number;
+  /** Freight charged for the order, in fen. */
+  shippingFen?: number;
+  /** Amount the customer pays, subtotalFen less discountFen plus shippingFen, in fen. */
+  totalFen?: number;
+  /** Discount lines of the order. Only returned when creating an order and by GET /orders/{orderId}. */
+  discounts?: OrderDiscount[];
   remark?: string;
   createdAt: string;
   updatedAt?: string;
 }
 
+export interface OrderDiscount {
+  promotionId: string;
+  label: string;
+  couponCode?: string;
+  amountFen: number;
+}
+
 export interface UpdateOrderFulfillmentRequest {
   ownerSalesUserId: string;
   /**
@@ -399,6 +603,8 @@ export const OrderPaymentStatus = {
   PAY_PENDING: 'PAY_PENDING',
   PAID: 'PAID',
   PAY_FAILED: 'PAY_FAILED',
+  PARTIALLY_REFUNDED: 'PARTIALLY_REFUNDED',
+  REFUNDED: 'REFUNDED',
 } as const;
 
 export interface PagedOrderList {
@@ -679,6 +885,74 @@ export interface PagedInquiryMessageList {
   total: number;
 }
 
+/**
+ * EXPIRED is reported for SENT or ACCEPTED quotations past their validity date.
+ */
+export type QuotationStatus = typeof QuotationStatus[keyof typeof QuotationStatus];
+
+
+// eslint-disable-next-line @typescript-eslint/no-redeclare
+export const QuotationStatus = {
+  SENT: 'SENT',
+  ACCEPTED: 'ACCEPTED',
+  REJECTED: 'REJECTED',
+  SUPERSEDED: 'SUPERSEDED',
+  CONVERTED: 'CONVERTED',
+  EXPIRED: 'EXPIRED',
+} as const;
+
+export interface QuotationItem {
+  skuId: string;
+  skuName: string;
+  skuCode?: string;
+  spec?: string;
+  qty: number;
+  /** Negotiated unit price, locked when the quotation is converted to an order. */
+  unitPriceFen: number;
+  amountFen: number;
+}
+
+export interface Quotation {
+  id: string;
+  inquiryId: string;
+  version: number;
+  status: QuotationStatus;
+  customerId: string;
+  createdByUserId: string;
+  /** Last day, in China Standard Time, the quotation can be accepted or converted. */
+  validUntil: string;
+  terms?: string;
+  decisionNote?: string;
+  decidedAt?: string;
+  orderId?: string;
+  items: QuotationItem[];
+  totalFen: number;
+  createdAt: string;
+}
+
+export interface QuotationList {
+  items: Quotation[];
+}
+
+export type CreateQuotationRequestItemsItem = {
+  skuId: string;
+  /** @minimum 1 */
+  qty: number;
+  /** @minimum 0 */
+  unitPriceFen: number;
+};
+
+export interface CreateQuotationRequest {
+  /** @minItems 1 */
+  items: CreateQuotationRequestItemsItem[];
+  validUntil: string;
+  terms?: string;
+}
+
+export interface QuotationDecisionRequest {
+  note?: string;
+}
+
 export type SupportConversationStatus = typeof SupportConversationStatus[keyof typeof SupportConversationStatus];
 
 
@@ -975,6 +1249,26 @@ page?: number;
 pageSize?: number;
 };
 
+export type GetCatalogSearchParams = {
+/**
+ * Words, Chinese text, SKU codes or pinyin initials
+ * @maxLength 100
+ */
+q: string;
+categoryId?: string;
+tag?: string;
+filterDimension?: string;
+/**
+ * @minimum 1
+ */
+page?: number;
+/**
+ * @minimum 1
+ * @maximum 100
+ */
+pageSize?: number;
+};
+
 export type GetWishlist200 = {
   items: WishlistItem[];
 };
@@ -996,6 +1290,10 @@ export type GetOrdersParams = {
 customerId?: string;
 ownerSalesUserId?: string;
 status?: OrderStatus;
+/**
+ * Case-insensitive substring of the order number.
+ */
+orderNo?: string;
 /**
  * @minimum 1
  */
@@ -1818,6 +2116,57 @@ export const patchCatalogProductsSpuIdSkusSkuId = async (spuId: string,
 
 
 
+/**
+ * Matches product and SKU names, SKU codes, specs, attributes, tags, filter dimensions and descriptions. Chinese text matches by character pairs, latin words by prefix, pinyin initials such as "bxgls" match Chinese names, and longer latin queries also match misspellings. Results are ranked by relevance. The index is refreshed on every catalog write and by a background indexer.
+ * @summary Search active products by text, SKU code or pinyin initials
+ */
+export type getCatalogSearchResponse200 = {
+  data: CatalogSearchResult
+  status: 200
+}
+
+export type getCatalogSearchResponse400 = {
+  data: BadRequestResponse
+  status: 400
+}
+
+export type getCatalogSearchResponseSuccess = (getCatalogSearchResponse200) & {
+  headers: Headers;
+};
+export type getCatalogSearchResponseError = (getCatalogSearchResponse400) & {
+  headers: Headers;
+};
+
+export type getCatalogSearchResponse = (getCatalogSearchResponseSuccess | getCatalogSearchResponseError)
+
+export const getGetCatalogSearchUrl = (params: GetCatalogSearchParams,) => {
+  const normalizedParams = new URLSearchParams();
+
+  Object.entries(params || {}).forEach(([key, value]) => {
+
+    if (value !== undefined) {
+      normalizedParams.append(key, value === null ? 'null' : value.toString())
+    }
+  });
+
+  const stringifiedParams = normalizedParams.toString();
+
+  return stringifiedParams.length > 0 ? `/catalog/search?${stringifiedParams}` : `/catalog/search`
+}
+
+export const getCatalogSearch = async (params: GetCatalogSearchParams, options?: RequestInit): Promise<getCatalogSearchResponse> => {
+
+  return apiMutator<getCatalogSearchResponse>(getGetCatalogSearchUrl(params),
+  {
+    ...options,
+    method: 'GET'
+
+
+  }
+);}
+
+
+
 /**
  * @summary Get wishlist (favorites)
  */
@@ -2189,6 +2538,56 @@ export const postCartImportJobsJobIdConfirm = async (jobId: string,
 
 
 
+/**
+ * Prices the lines exactly as POST /orders would, with the tier applied per line, line totals, discounts, freight and totals, and reports what would stop or change the order as warnings. Nothing is stored.
+ * @summary Price selected cart items without ordering
+ */
+export type postCheckoutPreviewResponse200 = {
+  data: CheckoutPreview
+  status: 200
+}
+
+export type postCheckoutPreviewResponse400 = {
+  data: BadRequestResponse
+  status: 400
+}
+
+export type postCheckoutPreviewResponse502 = {
+  data: ErrorResponse
+  status: 502
+}
+
+export type postCheckoutPreviewResponseSuccess = (postCheckoutPreviewResponse200) & {
+  headers: Headers;
+};
+export type postCheckoutPreviewResponseError = (postCheckoutPreviewResponse400 | postCheckoutPreviewResponse502) & {
+  headers: Headers;
+};
+
+export type postCheckoutPreviewResponse = (postCheckoutPreviewResponseSuccess | postCheckoutPreviewResponseError)
+
+export const getPostCheckoutPreviewUrl = () => {
+
+
+
+
+  return `/checkout/preview`
+}
+
+export const postCheckoutPreview = async (checkoutPreviewRequest: CheckoutPreviewRequest, options?: RequestInit): Promise<postCheckoutPreviewResponse> => {
+
+  return apiMutator<postCheckoutPreviewResponse>(getPostCheckoutPreviewUrl(),
+  {
+    ...options,
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json', ...options?.headers },
+    body: JSON.stringify(
+      checkoutPreviewRequest,)
+  }
+);}
+
+
+
 /**
  * @summary Submit intent order
  */
@@ -3490,6 +3889,247 @@ export const postInquiriesPriceInquiryIdMessages = async (inquiryId: string,
 
 
 
+/**
+ * @summary List quotation versions of a price inquiry
+ */
+export type getInquiriesPriceInquiryIdQuotationsResponse200 = {
+  data: QuotationList
+  status: 200
+}
+
+export type getInquiriesPriceInquiryIdQuotationsResponse404 = {
+  data: NotFoundResponse
+  status: 404
+}
+
+export type getInquiriesPriceInquiryIdQuotationsResponseSuccess = (getInquiriesPriceInquiryIdQuotationsResponse200) & {
+  headers: Headers;
+};
+export type getInquiriesPriceInquiryIdQuotationsResponseError = (getInquiriesPriceInquiryIdQuotationsResponse404) & {
+  headers: Headers;
+};
+
+export type getInquiriesPriceInquiryIdQuotationsResponse = (getInquiriesPriceInquiryIdQuotationsResponseSuccess | getInquiriesPriceInquiryIdQuotationsResponseError)
+
+export const getGetInquiriesPriceInquiryIdQuotationsUrl = (inquiryId: string,) => {
+
+
+
+
+  return `/inquiries/price/${inquiryId}/quotations`
+}
+
+export const getInquiriesPriceInquiryIdQuotations = async (inquiryId: string, options?: RequestInit): Promise<getInquiriesPriceInquiryIdQuotationsResponse> => {
+
+  return apiMutator<getInquiriesPriceInquiryIdQuotationsResponse>(getGetInquiriesPriceInquiryIdQuotationsUrl(inquiryId),
+  {
+    ...options,
+    method: 'GET'
+
+
+  }
+);}
+
+
+
+/**
+ * @summary Send a quotation, or revise the current one
+ */
+export type postInquiriesPriceInquiryIdQuotationsResponse201 = {
+  data: Quotation
+  status: 201
+}
+
+export type postInquiriesPriceInquiryIdQuotationsResponse400 = {
+  data: BadRequestResponse
+  status: 400
+}
+
+export type postInquiriesPriceInquiryIdQuotationsResponse404 = {
+  data: NotFoundResponse
+  status: 404
+}
+
+export type postInquiriesPriceInquiryIdQuotationsResponse409 = {
+  data: ConflictResponse
+  status: 409
+}
+
+export type postInquiriesPriceInquiryIdQuotationsResponseSuccess = (postInquiriesPriceInquiryIdQuotationsResponse201) & {
+  headers: Headers;
+};
+export type postInquiriesPriceInquiryIdQuotationsResponseError = (postInquiriesPriceInquiryIdQuotationsResponse400 | postInquiriesPriceInquiryIdQuotationsResponse404 | postInquiriesPriceInquiryIdQuotationsResponse409) & {
+  headers: Headers;
+};
+
+export type postInquiriesPriceInquiryIdQuotationsResponse = (postInquiriesPriceInquiryIdQuotationsResponseSuccess | postInquiriesPriceInquiryIdQuotationsResponseError)
+
+export const getPostInquiriesPriceInquiryIdQuotationsUrl = (inquiryId: string,) => {
+
+
+
+
+  return `/inquiries/price/${inquiryId}/quotations`
+}
+
+export const postInquiriesPriceInquiryIdQuotations = async (inquiryId: string,
+    createQuotationRequest: CreateQuotationRequest, options?: RequestInit): Promise<postInquiriesPriceInquiryIdQuotationsResponse> => {
+
+  return apiMutator<postInquiriesPriceInquiryIdQuotationsResponse>(getPostInquiriesPriceInquiryIdQuotationsUrl(inquiryId),
+  {
+    ...options,
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json', ...options?.headers },
+    body: JSON.stringify(
+      createQuotationRequest,)
+  }
+);}
+
+
+
+/**
+ * @summary Get a quotation
+ */
+export type getQuotationsQuotationIdResponse200 = {
+  data: Quotation
+  status: 200
+}
+
+export type getQuotationsQuotationIdResponse404 = {
+  data: NotFoundResponse
+  status: 404
+}
+
+export type getQuotationsQuotationIdResponseSuccess = (getQuotationsQuotationIdResponse200) & {
+  headers: Headers;
+};
+export type getQuotationsQuotationIdResponseError = (getQuotationsQuotationIdResponse404) & {
+  headers: Headers;
+};
+
+export type getQuotationsQuotationIdResponse = (getQuotationsQuotationIdResponseSuccess | getQuotationsQuotationIdResponseError)
+
+export const getGetQuotationsQuotationIdUrl = (quotationId: string,) => {
+
+
+
+
+  return `/quotations/${quotationId}`
+}
+
+export const getQuotationsQuotationId = async (quotationId: string, options?: RequestInit): Promise<getQuotationsQuotationIdResponse> => {
+
+  return apiMutator<getQuotationsQuotationIdResponse>(getGetQuotationsQuotationIdUrl(quotationId),
+  {
+    ...options,
+    method: 'GET'
+
+
+  }
+);}
+
+
+
+/**
+ * @summary Accept a quotation (customer)
+ */
+export type postQuotationsQuotationIdAcceptResponse200 = {
+  data: Quotation
+  status: 200
+}
+
+export type postQuotationsQuotationIdAcceptResponse404 = {
+  data: NotFoundResponse
+  status: 404
+}
+
+export type postQuotationsQuotationIdAcceptResponse409 = {
+  data: ConflictResponse
+  status: 409
+}
+
+export type postQuotationsQuotationIdAcceptResponseSuccess = (postQuotationsQuotationIdAcceptResponse200) & {
+  headers: Headers;
+};
+export type postQuotationsQuotationIdAcceptResponseError = (postQuotationsQuotationIdAcceptResponse404 | postQuotationsQuotationIdAcceptResponse409) & {
+  headers: Headers;
+};
+
+export type postQuotationsQuotationIdAcceptResponse = (postQuotationsQuotationIdAcceptResponseSuccess | postQuotationsQuotationIdAcceptResponseError)
+
+export const getPostQuotationsQuotationIdAcceptUrl = (quotationId: string,) => {
+
+
+
+
+  return `/quotations/${quotationId}/accept`
+}
+
+export const postQuotationsQuotationIdAccept = async (quotationId: string,
+    quotationDecisionRequest?: QuotationDecisionRequest, options?: RequestInit): Promise<postQuotationsQuotationIdAcceptResponse> => {
+
+  return apiMutator<postQuotationsQuotationIdAcceptResponse>(getPostQuotationsQuotationIdAcceptUrl(quotationId),
+  {
+    ...options,
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json', ...options?.headers },
+    body: JSON.stringify(
+      quotationDecisionRequest,)
+  }
+);}
+
+
+
+/**
+ * @summary Reject a quotation (customer)
+ */
+export type postQuotationsQuotationIdRejectResponse200 = {
+  data: Quotation
+  status: 200
+}
+
+export type postQuotationsQuotationIdRejectResponse404 = {
+  data: NotFoundResponse
+  status: 404
+}
+
+export type postQuotationsQuotationIdRejectResponse409 = {
+  data: ConflictResponse
+  status: 409
+}
+
+export type postQuotationsQuotationIdRejectResponseSuccess = (postQuotationsQuotationIdRejectResponse200) & {
+  headers: Headers;
+};
+export type postQuotationsQuotationIdRejectResponseError = (postQuotationsQuotationIdRejectResponse404 | postQuotationsQuotationIdRejectResponse409) & {
+  headers: Headers;
+};
+
+export type postQuotationsQuotationIdRejectResponse = (postQuotationsQuotationIdRejectResponseSuccess | postQuotationsQuotationIdRejectResponseError)
+
+export const getPostQuotationsQuotationIdRejectUrl = (quotationId: string,) => {
+
+
+
+
+  return `/quotations/${quotationId}/reject`
+}
+
+export const postQuotationsQuotationIdReject = async (quotationId: string,
+    quotationDecisionRequest?: QuotationDecisionRequest, options?: RequestInit): Promise<postQuotationsQuotationIdRejectResponse> => {
+
+  return apiMutator<postQuotationsQuotationIdRejectResponse>(getPostQuotationsQuotationIdRejectUrl(quotationId),
+  {
+    ...options,
+    method: 'POST',
+    headers: { 'Content-Type': 'application/json', ...options?.headers },
+    body: JSON.stringify(
+      quotationDecisionRequest,)
+  }
+);}
+
+
+
 /**
  * @summary Get or create the current customer's active support conversation
  */
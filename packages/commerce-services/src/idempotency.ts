@@ -7,6 +7,7 @@ export interface OrderFingerprintInput {
   items: OrderFingerprintItem[]
   address: unknown
   remark?: string | null
+  quotationId?: string | null
 }
 
 export interface OrderIdempotency {
@@ -28,7 +29,8 @@ const fingerprintDraft = (draft: OrderFingerprintInput): string => {
   return JSON.stringify({
     items,
     address: draft.address,
-    remark: draft.remark ?? null
+    remark: draft.remark ?? null,
+    quotationId: draft.quotationId ?? null
   })
 }
 
@@ -2,17 +2,26 @@ import {
   getInquiriesPrice,
   getInquiriesPriceInquiryId,
   getInquiriesPriceInquiryIdMessages,
+  getInquiriesPriceInquiryIdQuotations,
+  getQuotationsQuotationId,
   patchInquiriesPriceInquiryId,
   postInquiriesPrice,
   postInquiriesPriceInquiryIdMessages,
+  postInquiriesPriceInquiryIdQuotations,
+  postQuotationsQuotationIdAccept,
+  postQuotationsQuotationIdReject,
   type CreateInquiryMessage,
   type CreatePriceInquiry,
+  type CreateQuotationRequest,
   type GetInquiriesPriceInquiryIdMessagesParams,
   type GetInquiriesPriceParams,
   type InquiryMessage,
   type PagedInquiryMessageList,
   type PagedPriceInquiryList,
   type PriceInquiry,
+  type Quotation,
+  type QuotationDecisionRequest,
+  type QuotationList,
   type UpdatePriceInquiryRequest
 } from '@tmo/api-client'
 
@@ -23,6 +32,11 @@ export interface InquiryService {
   update: (inquiryId: string, payload: UpdatePriceInquiryRequest) => Promise<PriceInquiry>
   listMessages: (inquiryId: string, params?: GetInquiriesPriceInquiryIdMessagesParams) => Promise<PagedInquiryMessageList>
   postMessage: (inquiryId: string, payload: CreateInquiryMessage) => Promise<InquiryMessage>
+  listQuotations: (inquiryId: string) => Promise<QuotationList>
+  createQuotation: (inquiryId: string, payload: CreateQuotationRequest) => Promise<Quotation>
+  getQuotation: (quotationId: string) => Promise<Quotation>
+  acceptQuotation: (quotationId: string, payload?: QuotationDecisionRequest) => Promise<Quotation>
+  rejectQuotation: (quotationId: string, payload?: QuotationDecisionRequest) => Promise<Quotation>
 }
 
 export const createInquiryService = (): InquiryService => {
@@ -32,6 +46,11 @@ export const createInquiryService = (): InquiryService => {
     get: async (inquiryId) => (await getInquiriesPriceInquiryId(inquiryId)).data,
     update: async (inquiryId, payload) => (await patchInquiriesPriceInquiryId(inquiryId, payload)).data,
     listMessages: async (inquiryId, params) => (await getInquiriesPriceInquiryIdMessages(inquiryId, params)).data,
-    postMessage: async (inquiryId, payload) => (await postInquiriesPriceInquiryIdMessages(inquiryId, payload)).data
+    postMessage: async (inquiryId, payload) => (await postInquiriesPriceInquiryIdMessages(inquiryId, payload)).data,
+    listQuotations: async (inquiryId) => (await getInquiriesPriceInquiryIdQuotations(inquiryId)).data as QuotationList,
+    createQuotation: async (inquiryId, payload) => (await postInquiriesPriceInquiryIdQuotations(inquiryId, payload)).data as Quotation,
+    getQuotation: async (quotationId) => (await getQuotationsQuotationId(quotationId)).data as Quotation,
+    acceptQuotation: async (quotationId, payload) => (await postQuotationsQuotationIdAccept(quotationId, payload)).data as Quotation,
+    rejectQuotation: async (quotationId, payload) => (await postQuotationsQuotationIdReject(quotationId, payload)).data as Quotation
   }
 }
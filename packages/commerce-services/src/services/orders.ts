@@ -32,9 +32,10 @@ export const createOrdersService = (idempotency: OrderIdempotency): OrdersServic
     submit: async (request, options) => {
       const idempotencyKey = options?.idempotencyKey
         ?? idempotency.getKey({
-          items: request.items.map((item) => ({ skuId: item.skuId, qty: item.qty })),
+          items: (request.items ?? []).map((item) => ({ skuId: item.skuId, qty: item.qty })),
           address: request.address,
-          remark: request.remark ?? null
+          remark: request.remark ?? null,
+          quotationId: request.quotationId ?? null
         })
 
       const response = await postOrders(request, {
@@ -0,0 +1,119 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultRefreshInterval bounds how long a role permission edited in identity
+// takes to apply to requests served here.
+const DefaultRefreshInterval = time.Minute
+
+// Authorizer answers permission checks from a cached matrix. Until the first
+// successful load it uses DefaultGrants, and when a refresh fails it keeps
+// the last matrix it loaded.
+type Authorizer struct {
+	source Source
+
+	mu       sync.RWMutex
+	matrix   Matrix
+	loadedAt time.Time
+}
+
+var defaultMatrix = NewMatrix(DefaultGrants())
+
+// NewAuthorizer returns an Authorizer that refreshes from source. A nil
+// source keeps the built-in defaults forever.
+func NewAuthorizer(source Source) *Authorizer {
+	return &Authorizer{source: source, matrix: defaultMatrix}
+}
+
+// Refresh reloads the matrix from the source. An empty answer is rejected so
+// a misconfigured identity cannot lock every caller out.
+func (a *Authorizer) Refresh(ctx context.Context) error {
+	if a == nil || a.source == nil {
+		return nil
+	}
+	grants, err := a.source.Grants(ctx)
+	if err != nil {
+		return err
+	}
+	matrix := NewMatrix(grants)
+	if matrix.Len() == 0 {
+		return fmt.Errorf("%w: empty grant matrix", ErrUnavailable)
+	}
+	a.mu.Lock()
+	a.matrix = matrix
+	a.loadedAt = time.Now()
+	a.mu.Unlock()
+	return nil
+}
+
+// LoadedAt returns when the matrix was last loaded from the source, or the
+// zero time while the defaults are still in use.
+func (a *Authorizer) LoadedAt() time.Time {
+	if a == nil {
+		return time.Time{}
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.loadedAt
+}
+
+// Resolve returns the scope role holds for permission.
+func (a *Authorizer) Resolve(role, permission string) (Scope, bool) {
+	return a.snapshot().Scope(role, permission)
+}
+
+// Allows reports whether role holds permission with at least the required
+// scope, and returns the scope it holds. An empty required scope accepts any
+// grant.
+func (a *Authorizer) Allows(role, permission string, required Scope) (Scope, bool) {
+	scope, ok := a.Resolve(role, permission)
+	if !ok || !scope.Covers(required) {
+		return "", false
+	}
+	return scope, true
+}
+
+// Start loads the matrix immediately and then every interval until ctx is
+// cancelled. Failures are logged and the previous matrix stays in use.
+func (a *Authorizer) Start(ctx context.Context, interval time.Duration, logger *slog.Logger) {
+	if a == nil || a.source == nil {
+		return
+	}
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	go func() {
+		a.refresh(ctx, logger)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				a.refresh(ctx, logger)
+			}
+		}
+	}()
+}
+
+func (a *Authorizer) refresh(ctx context.Context, logger *slog.Logger) {
+	if err := a.Refresh(ctx); err != nil && logger != nil && ctx.Err() == nil {
+		logger.Warn("rbac matrix refresh failed", "error", err)
+	}
+}
+
+func (a *Authorizer) snapshot() Matrix {
+	if a == nil {
+		return defaultMatrix
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.matrix
+}
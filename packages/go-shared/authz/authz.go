@@ -0,0 +1,111 @@
+// Package authz resolves identity RBAC permissions for the caller's role.
+//
+// Identity owns the role-permission matrix. Services load it through the
+// identity internal API, cache it, and refresh it periodically so a change
+// made in the admin UI takes effect without a redeploy.
+package authz
+
+import "strings"
+
+// Scope limits which records a granted permission covers.
+type Scope string
+
+const (
+	// ScopeSelf covers records that belong to the caller.
+	ScopeSelf Scope = "SELF"
+	// ScopeOwned covers records of customers owned by the caller.
+	ScopeOwned Scope = "OWNED"
+	// ScopeAll covers every record.
+	ScopeAll Scope = "ALL"
+)
+
+// ParseScope normalizes raw and reports whether it names a known scope.
+func ParseScope(raw string) (Scope, bool) {
+	scope := Scope(strings.ToUpper(strings.TrimSpace(raw)))
+	switch scope {
+	case ScopeSelf, ScopeOwned, ScopeAll:
+		return scope, true
+	default:
+		return "", false
+	}
+}
+
+func (s Scope) rank() int {
+	switch s {
+	case ScopeSelf:
+		return 1
+	case ScopeOwned:
+		return 2
+	case ScopeAll:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Covers reports whether a grant with scope s satisfies required. An empty
+// required scope accepts any grant.
+func (s Scope) Covers(required Scope) bool {
+	if s.rank() == 0 {
+		return false
+	}
+	return s.rank() >= required.rank()
+}
+
+// Grant gives a role one permission with a scope.
+type Grant struct {
+	Role       string `json:"role"`
+	Permission string `json:"permission"`
+	Scope      Scope  `json:"scope"`
+}
+
+// Matrix is an immutable role-permission lookup table.
+type Matrix struct {
+	grants map[string]map[string]Scope
+}
+
+// NewMatrix indexes grants by role and permission. Grants with an unknown
+// scope are dropped; duplicates keep the widest scope.
+func NewMatrix(grants []Grant) Matrix {
+	index := make(map[string]map[string]Scope)
+	for _, grant := range grants {
+		role := normalizeRole(grant.Role)
+		permission := normalizePermission(grant.Permission)
+		scope, ok := ParseScope(string(grant.Scope))
+		if role == "" || permission == "" || !ok {
+			continue
+		}
+		permissions, exists := index[role]
+		if !exists {
+			permissions = make(map[string]Scope)
+			index[role] = permissions
+		}
+		if current, exists := permissions[permission]; !exists || scope.rank() > current.rank() {
+			permissions[permission] = scope
+		}
+	}
+	return Matrix{grants: index}
+}
+
+// Scope returns the scope role holds for permission.
+func (m Matrix) Scope(role, permission string) (Scope, bool) {
+	scope, ok := m.grants[normalizeRole(role)][normalizePermission(permission)]
+	return scope, ok
+}
+
+// Len returns the number of grants in the matrix.
+func (m Matrix) Len() int {
+	total := 0
+	for _, permissions := range m.grants {
+		total += len(permissions)
+	}
+	return total
+}
+
+func normalizeRole(role string) string {
+	return strings.ToUpper(strings.TrimSpace(role))
+}
+
+func normalizePermission(permission string) string {
+	return strings.ToLower(strings.TrimSpace(permission))
+}
@@ -0,0 +1,136 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type stubSource struct {
+	grants []Grant
+	err    error
+}
+
+func (s *stubSource) Grants(context.Context) ([]Grant, error) {
+	return s.grants, s.err
+}
+
+func TestScopeCovers(t *testing.T) {
+	cases := []struct {
+		scope    Scope
+		required Scope
+		want     bool
+	}{
+		{ScopeAll, ScopeOwned, true},
+		{ScopeOwned, ScopeOwned, true},
+		{ScopeSelf, ScopeAll, false},
+		{ScopeSelf, "", true},
+		{Scope("BOGUS"), "", false},
+	}
+	for _, tc := range cases {
+		if got := tc.scope.Covers(tc.required); got != tc.want {
+			t.Fatalf("%s covers %q = %v, want %v", tc.scope, tc.required, got, tc.want)
+		}
+	}
+}
+
+func TestNewMatrixKeepsWidestScope(t *testing.T) {
+	matrix := NewMatrix([]Grant{
+		{Role: "sales", Permission: "Order:Read", Scope: "owned"},
+		{Role: "SALES", Permission: "order:read", Scope: ScopeSelf},
+		{Role: "SALES", Permission: "order:manage", Scope: "nope"},
+	})
+	scope, ok := matrix.Scope("Sales", "order:read")
+	if !ok || scope != ScopeOwned {
+		t.Fatalf("expected OWNED, got %q %v", scope, ok)
+	}
+	if _, ok := matrix.Scope("SALES", "order:manage"); ok {
+		t.Fatal("expected grant with unknown scope to be dropped")
+	}
+	if matrix.Len() != 1 {
+		t.Fatalf("expected 1 grant, got %d", matrix.Len())
+	}
+}
+
+func TestAuthorizerRefreshReplacesDefaults(t *testing.T) {
+	source := &stubSource{grants: []Grant{{Role: "CS", Permission: PermissionSupplierManage, Scope: ScopeAll}}}
+	authorizer := NewAuthorizer(source)
+
+	if _, ok := authorizer.Allows("CS", PermissionSupplierManage, ""); ok {
+		t.Fatal("expected default matrix to deny supplier:manage to CS")
+	}
+	if _, ok := authorizer.Allows("CS", PermissionOrderRead, ScopeAll); !ok {
+		t.Fatal("expected default matrix to grant order:read to CS")
+	}
+
+	if err := authorizer.Refresh(context.Background()); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if _, ok := authorizer.Allows("CS", PermissionSupplierManage, ScopeAll); !ok {
+		t.Fatal("expected refreshed matrix to grant supplier:manage to CS")
+	}
+	if _, ok := authorizer.Allows("CS", PermissionOrderRead, ""); ok {
+		t.Fatal("expected refreshed matrix to drop order:read from CS")
+	}
+	if authorizer.LoadedAt().IsZero() {
+		t.Fatal("expected loadedAt to be set")
+	}
+}
+
+func TestAuthorizerKeepsMatrixWhenRefreshFails(t *testing.T) {
+	source := &stubSource{err: errors.New("boom")}
+	authorizer := NewAuthorizer(source)
+	if err := authorizer.Refresh(context.Background()); err == nil {
+		t.Fatal("expected refresh error")
+	}
+	source.err = nil
+	if err := authorizer.Refresh(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("expected empty matrix to be rejected, got %v", err)
+	}
+	if _, ok := authorizer.Allows("ADMIN", PermissionProductManage, ScopeAll); !ok {
+		t.Fatal("expected defaults to stay in use")
+	}
+}
+
+func TestAuthorizerAllowsRequiredScope(t *testing.T) {
+	var authorizer *Authorizer
+	scope, ok := authorizer.Allows("SALES", PermissionOrderRead, "")
+	if !ok || scope != ScopeOwned {
+		t.Fatalf("expected OWNED order:read for SALES, got %q %v", scope, ok)
+	}
+	if _, ok := authorizer.Allows("SALES", PermissionOrderRead, ScopeAll); ok {
+		t.Fatal("expected OWNED to fail an ALL requirement")
+	}
+	if _, ok := authorizer.Allows("GUEST", PermissionCatalogRead, ""); ok {
+		t.Fatal("expected unknown role to be denied")
+	}
+}
+
+func TestClientGrants(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/internal/rbac/grants" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("X-Internal-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"items":[{"role":"CS","permission":"shipment:manage","scope":"ALL"}]}`))
+	}))
+	defer server.Close()
+
+	grants, err := NewClient(server.URL+"/", "secret", nil).Grants(context.Background())
+	if err != nil {
+		t.Fatalf("grants: %v", err)
+	}
+	if len(grants) != 1 || grants[0].Permission != PermissionShipmentManage || grants[0].Scope != ScopeAll {
+		t.Fatalf("unexpected grants: %+v", grants)
+	}
+
+	if _, err := NewClient(server.URL, "wrong", nil).Grants(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("expected ErrUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrUnavailable is returned when the grant matrix cannot be loaded.
+var ErrUnavailable = errors.New("authz: identity unavailable")
+
+// Source loads the full role-permission matrix.
+type Source interface {
+	Grants(context.Context) ([]Grant, error)
+}
+
+// Client loads grants from the identity internal API. It authenticates
+// with the shared internal token rather than a user JWT.
+type Client struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewClient returns a Client for the identity service at baseURL. A nil
+// client gets a default with a short timeout.
+func NewClient(baseURL, token string, client *http.Client) *Client {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &Client{
+		baseURL: strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		token:   strings.TrimSpace(token),
+		client:  client,
+	}
+}
+
+// Grants fetches every role-permission grant from identity.
+func (c *Client) Grants(ctx context.Context) ([]Grant, error) {
+	if c == nil || c.baseURL == "" {
+		return nil, fmt.Errorf("%w: base URL is not configured", ErrUnavailable)
+	}
+	endpoint, err := url.JoinPath(c.baseURL, "internal", "rbac", "grants")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if c.token != "" {
+		req.Header.Set("X-Internal-Token", c.token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("%w: identity returned %d", ErrUnavailable, resp.StatusCode)
+	}
+	var payload struct {
+		Items []Grant `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("%w: invalid response", ErrUnavailable)
+	}
+	return payload.Items, nil
+}
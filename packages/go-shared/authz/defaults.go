@@ -0,0 +1,136 @@
+package authz
+
+// Permission codes checked outside identity. They match the rows seeded in
+// the identity permissions table.
+const (
+	PermissionCatalogRead          = "catalog:read"
+	PermissionWishlistManage       = "wishlist:manage"
+	PermissionCartManage           = "cart:manage"
+	PermissionImportCart           = "import:cart"
+	PermissionOrderCreate          = "order:create"
+	PermissionOrderRead            = "order:read"
+	PermissionOrderManage          = "order:manage"
+	PermissionTrackingRead         = "tracking:read"
+	PermissionShipmentManage       = "shipment:manage"
+	PermissionImportShipment       = "import:shipment"
+	PermissionProductManage        = "product:manage"
+	PermissionImportProduct        = "import:product"
+	PermissionProductRequestCreate = "product_request:create"
+	PermissionProductRequestRead   = "product_request:read"
+	PermissionProductRequestExport = "product_request:export"
+	PermissionAfterSalesCreate     = "after_sales:create"
+	PermissionAfterSalesRead       = "after_sales:read"
+	PermissionAfterSalesManage     = "after_sales:manage"
+	PermissionInquiryCreate        = "inquiry:create"
+	PermissionInquiryRead          = "inquiry:read"
+	PermissionInquiryManage        = "inquiry:manage"
+	PermissionSupplierRead         = "supplier:read"
+	PermissionSupplierManage       = "supplier:manage"
+	PermissionInventoryRead        = "inventory:read"
+	PermissionInventoryManage      = "inventory:manage"
+	PermissionReceivableRead       = "receivable:read"
+	PermissionStatementRead        = "statement:read"
+	PermissionStatementManage      = "statement:manage"
+	PermissionWebhookManage        = "webhook:manage"
+	PermissionPricingManage        = "pricing:manage"
+	PermissionPromotionManage      = "promotion:manage"
+	PermissionSupportCreate        = "support:create"
+	PermissionSupportManage        = "support:manage"
+	PermissionPaymentRead          = "payment:read"
+	PermissionPaymentManage        = "payment:manage"
+	PermissionCustomerRead         = "customer:read"
+	PermissionCustomerTransfer     = "customer:transfer"
+	PermissionCustomerTag          = "customer:tag"
+	PermissionStaffRead            = "staff:read"
+	PermissionStaffStatusManage    = "staff:status_manage"
+	PermissionConfigFeatureFlags   = "config:feature_flags"
+	PermissionRBACManage           = "rbac:manage"
+)
+
+// DefaultGrants mirrors the matrix seeded by the identity migrations. It is
+// used until identity has answered once, and in tests and local runs that do
+// not talk to identity.
+func DefaultGrants() []Grant {
+	grants := make([]Grant, 0, 128)
+	add := func(role string, scope Scope, permissions ...string) {
+		for _, permission := range permissions {
+			grants = append(grants, Grant{Role: role, Permission: permission, Scope: scope})
+		}
+	}
+
+	add("CUSTOMER", ScopeAll, PermissionCatalogRead)
+	add("CUSTOMER", ScopeSelf,
+		PermissionWishlistManage, PermissionCartManage, PermissionImportCart,
+		PermissionOrderCreate, PermissionOrderRead, PermissionTrackingRead,
+		PermissionProductRequestCreate, PermissionProductRequestRead,
+		PermissionAfterSalesCreate, PermissionAfterSalesRead,
+		PermissionInquiryCreate, PermissionInquiryRead,
+		PermissionPaymentRead, PermissionStatementRead, PermissionSupportCreate,
+	)
+
+	add("SALES", ScopeAll, PermissionCatalogRead, PermissionSupplierRead)
+	add("SALES", ScopeOwned,
+		PermissionOrderRead, PermissionTrackingRead,
+		PermissionInquiryRead, PermissionInquiryManage,
+		PermissionProductRequestRead,
+		PermissionAfterSalesRead, PermissionAfterSalesManage,
+		PermissionCustomerRead,
+	)
+
+	add("CS", ScopeAll,
+		PermissionOrderRead, PermissionTrackingRead,
+		PermissionImportShipment, PermissionShipmentManage,
+		PermissionInquiryRead, PermissionInquiryManage,
+		PermissionProductRequestRead,
+		PermissionAfterSalesRead, PermissionAfterSalesManage,
+		PermissionSupplierRead, PermissionInventoryRead,
+		PermissionPaymentRead, PermissionSupportManage,
+	)
+
+	add("MANAGER", ScopeAll,
+		PermissionCatalogRead,
+		PermissionOrderRead, PermissionOrderManage, PermissionTrackingRead,
+		PermissionImportShipment, PermissionShipmentManage,
+		PermissionInquiryCreate, PermissionInquiryRead, PermissionInquiryManage,
+		PermissionProductRequestRead,
+		PermissionAfterSalesCreate, PermissionAfterSalesRead, PermissionAfterSalesManage,
+		PermissionSupplierRead, PermissionSupplierManage,
+		PermissionInventoryRead, PermissionInventoryManage,
+		PermissionReceivableRead, PermissionStatementRead, PermissionStatementManage,
+		PermissionSupportManage,
+		PermissionPaymentRead, PermissionPaymentManage,
+		PermissionCustomerRead, PermissionCustomerTransfer, PermissionCustomerTag,
+		PermissionStaffRead, PermissionStaffStatusManage,
+		PermissionPricingManage, PermissionPromotionManage,
+	)
+
+	add("BOSS", ScopeAll, allPermissions()...)
+	for _, permission := range allPermissions() {
+		if permission == PermissionStaffRead || permission == PermissionStaffStatusManage {
+			continue
+		}
+		add("ADMIN", ScopeAll, permission)
+	}
+	return grants
+}
+
+func allPermissions() []string {
+	return []string{
+		PermissionCatalogRead, PermissionWishlistManage, PermissionCartManage, PermissionImportCart,
+		PermissionOrderCreate, PermissionOrderRead, PermissionOrderManage,
+		PermissionTrackingRead, PermissionShipmentManage, PermissionImportShipment,
+		PermissionProductManage, PermissionImportProduct,
+		PermissionProductRequestCreate, PermissionProductRequestRead, PermissionProductRequestExport,
+		PermissionAfterSalesCreate, PermissionAfterSalesRead, PermissionAfterSalesManage,
+		PermissionInquiryCreate, PermissionInquiryRead, PermissionInquiryManage,
+		PermissionSupplierRead, PermissionSupplierManage,
+		PermissionInventoryRead, PermissionInventoryManage,
+		PermissionReceivableRead, PermissionStatementRead, PermissionStatementManage,
+		PermissionWebhookManage, PermissionPricingManage, PermissionPromotionManage,
+		PermissionSupportCreate, PermissionSupportManage,
+		PermissionPaymentRead, PermissionPaymentManage,
+		PermissionCustomerRead, PermissionCustomerTransfer, PermissionCustomerTag,
+		PermissionStaffRead, PermissionStaffStatusManage,
+		PermissionConfigFeatureFlags, PermissionRBACManage,
+	}
+}
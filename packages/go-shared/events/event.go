@@ -0,0 +1,100 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types published by the services. The payload of each type is
+// described by a JSON Schema in contracts/events.
+const (
+	TypeOrderCreated       = "order.created"
+	TypeOrderStatusChanged = "order.status_changed"
+	TypeOrderClosed        = "order.closed"
+	TypeShipmentAdded      = "shipment.added"
+	TypeTicketUpdated      = "ticket.updated"
+	TypeStatementGenerated = "statement.generated"
+	TypePaymentSucceeded   = "payment.succeeded"
+	TypePaymentFailed      = "payment.failed"
+	TypePaymentCancelled   = "payment.cancelled"
+	TypePaymentRefunded    = "payment.refunded"
+)
+
+// ErrInvalidEvent is returned for an envelope without an ID or type.
+var ErrInvalidEvent = errors.New("events: invalid event")
+
+// Event is the envelope every event is stored and delivered in. Consumers
+// deduplicate on ID; the same event may be delivered more than once.
+type Event struct {
+	ID          uuid.UUID       `json:"id"`
+	Type        string          `json:"type"`
+	Source      string          `json:"source"`
+	AggregateID string          `json:"aggregateId"`
+	OccurredAt  time.Time       `json:"occurredAt"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// New returns an event of eventType with a fresh ID and payload encoded as
+// JSON.
+func New(source, eventType, aggregateID string, payload any) (Event, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, fmt.Errorf("%w: encode payload: %v", ErrInvalidEvent, err)
+	}
+	event := Event{
+		ID:          uuid.New(),
+		Type:        strings.TrimSpace(eventType),
+		Source:      strings.TrimSpace(source),
+		AggregateID: strings.TrimSpace(aggregateID),
+		OccurredAt:  time.Now().UTC(),
+		Payload:     raw,
+	}
+	return event, event.Validate()
+}
+
+// Validate reports whether the envelope can be stored and delivered.
+func (e Event) Validate() error {
+	if e.ID == uuid.Nil {
+		return fmt.Errorf("%w: missing id", ErrInvalidEvent)
+	}
+	if strings.TrimSpace(e.Type) == "" {
+		return fmt.Errorf("%w: missing type", ErrInvalidEvent)
+	}
+	return nil
+}
+
+// Decode unmarshals the event payload into v.
+func (e Event) Decode(v any) error {
+	if len(e.Payload) == 0 {
+		return fmt.Errorf("%w: empty payload", ErrInvalidEvent)
+	}
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return fmt.Errorf("%w: decode payload: %v", ErrInvalidEvent, err)
+	}
+	return nil
+}
+
+// Matches reports whether eventType is selected by one of patterns. A
+// pattern is an exact type, a prefix ending in ".*" such as "payment.*", or
+// "*" for every type.
+func Matches(patterns []string, eventType string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		switch {
+		case pattern == "*":
+			return true
+		case strings.HasSuffix(pattern, ".*"):
+			if strings.HasPrefix(eventType, strings.TrimSuffix(pattern, "*")) {
+				return true
+			}
+		case pattern == eventType:
+			return true
+		}
+	}
+	return false
+}
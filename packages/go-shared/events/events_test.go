@@ -0,0 +1,191 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type execCall struct {
+	sql  string
+	args []any
+}
+
+type recordingTx struct {
+	calls []execCall
+	tag   string
+}
+
+func (r *recordingTx) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	r.calls = append(r.calls, execCall{sql: sql, args: args})
+	return pgconn.NewCommandTag(r.tag), nil
+}
+
+func TestMatches(t *testing.T) {
+	cases := []struct {
+		patterns  []string
+		eventType string
+		want      bool
+	}{
+		{[]string{"*"}, TypeOrderCreated, true},
+		{[]string{"payment.*"}, TypePaymentSucceeded, true},
+		{[]string{"payment.*"}, TypeOrderCreated, false},
+		{[]string{"payment.*"}, "payments.other", false},
+		{[]string{TypeOrderClosed}, TypeOrderClosed, true},
+		{[]string{TypeOrderClosed}, TypeOrderCreated, false},
+		{nil, TypeOrderCreated, false},
+	}
+	for _, tc := range cases {
+		if got := Matches(tc.patterns, tc.eventType); got != tc.want {
+			t.Errorf("Matches(%v, %q) = %v, want %v", tc.patterns, tc.eventType, got, tc.want)
+		}
+	}
+}
+
+func TestNewEventRoundTrip(t *testing.T) {
+	paidAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	event, err := New("payment", TypePaymentSucceeded, "pay-1", PaymentStatus{PaymentID: "pay-1", OrderID: "order-1", Status: "PAID", PaidAt: &paidAt})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if event.ID == uuid.Nil || event.Source != "payment" || event.AggregateID != "pay-1" {
+		t.Fatalf("unexpected envelope %+v", event)
+	}
+	raw, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded Event
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	var payload PaymentStatus
+	if err := decoded.Decode(&payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload.OrderID != "order-1" || payload.PaidAt == nil || !payload.PaidAt.Equal(paidAt) {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+
+	if _, err := New("payment", " ", "pay-1", nil); !errors.Is(err, ErrInvalidEvent) {
+		t.Fatalf("expected ErrInvalidEvent for a missing type, got %v", err)
+	}
+	if err := (Event{ID: uuid.New(), Type: TypeOrderCreated}).Decode(&payload); !errors.Is(err, ErrInvalidEvent) {
+		t.Fatalf("expected ErrInvalidEvent for an empty payload, got %v", err)
+	}
+}
+
+func TestPublishCreatesDeliveriesForAcceptingSubscribers(t *testing.T) {
+	outbox := NewOutbox(nil, "commerce",
+		NewHTTPSubscriber("payment", "http://payment/internal/events", "", []string{TypeOrderClosed}, nil),
+		NewLocalSubscriber("audit", []string{"order.*"}, nil),
+	)
+	tx := &recordingTx{}
+	if err := outbox.Publish(context.Background(), tx, TypeOrderCreated, "order-1", OrderCreated{OrderID: "order-1"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if len(tx.calls) != 2 {
+		t.Fatalf("expected event and delivery inserts, got %d calls", len(tx.calls))
+	}
+	if !strings.Contains(tx.calls[0].sql, "outbox_events") || tx.calls[0].args[1] != TypeOrderCreated || tx.calls[0].args[2] != "commerce" {
+		t.Fatalf("unexpected event insert %+v", tx.calls[0])
+	}
+	names, ok := tx.calls[1].args[1].([]string)
+	if !ok || len(names) != 1 || names[0] != "audit" {
+		t.Fatalf("expected a delivery for audit only, got %v", tx.calls[1].args[1])
+	}
+
+	tx = &recordingTx{}
+	if err := outbox.Publish(context.Background(), tx, TypeTicketUpdated, "ticket-1", TicketUpdated{TicketID: "ticket-1"}); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if len(tx.calls) != 1 {
+		t.Fatalf("expected an event without deliveries, got %d calls", len(tx.calls))
+	}
+
+	var nilOutbox *Outbox
+	tx = &recordingTx{}
+	if err := nilOutbox.Publish(context.Background(), tx, TypeOrderCreated, "order-1", nil); err != nil || len(tx.calls) != 0 {
+		t.Fatalf("expected nil outbox to publish nothing, got %v and %d calls", err, len(tx.calls))
+	}
+}
+
+func TestMarkProcessed(t *testing.T) {
+	event := Event{ID: uuid.New(), Type: TypePaymentSucceeded}
+	fresh, err := MarkProcessed(context.Background(), &recordingTx{tag: "INSERT 0 1"}, "commerce", event)
+	if err != nil || !fresh {
+		t.Fatalf("expected first delivery to be fresh, got %v %v", fresh, err)
+	}
+	fresh, err = MarkProcessed(context.Background(), &recordingTx{tag: "INSERT 0 0"}, "commerce", event)
+	if err != nil || fresh {
+		t.Fatalf("expected redelivery to be skipped, got %v %v", fresh, err)
+	}
+	if _, err := MarkProcessed(context.Background(), &recordingTx{}, "commerce", Event{}); !errors.Is(err, ErrInvalidEvent) {
+		t.Fatalf("expected ErrInvalidEvent, got %v", err)
+	}
+}
+
+func TestHTTPSubscriberDeliver(t *testing.T) {
+	status := http.StatusNoContent
+	var received Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Internal-Token") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("unmarshal: %v", err)
+		}
+		if r.Header.Get("X-Event-Id") != received.ID.String() || r.Header.Get("X-Event-Type") != received.Type {
+			t.Errorf("unexpected event headers %v", r.Header)
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	subscriber := NewHTTPSubscriber("commerce", server.URL, "token", []string{"payment.*"}, nil)
+	if !subscriber.Accepts(TypePaymentRefunded) || subscriber.Accepts(TypeOrderCreated) {
+		t.Fatal("unexpected type filter")
+	}
+	event, err := New("payment", TypePaymentRefunded, "pay-1", PaymentStatus{PaymentID: "pay-1"})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	if err := subscriber.Deliver(context.Background(), event); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if received.ID != event.ID {
+		t.Fatalf("unexpected event delivered %+v", received)
+	}
+
+	status = http.StatusInternalServerError
+	if err := subscriber.Deliver(context.Background(), event); !errors.Is(err, ErrDeliveryFailed) {
+		t.Fatalf("expected ErrDeliveryFailed, got %v", err)
+	}
+	wrongToken := NewHTTPSubscriber("commerce", server.URL, "other", []string{"*"}, nil)
+	if err := wrongToken.Deliver(context.Background(), event); !errors.Is(err, ErrDeliveryFailed) {
+		t.Fatalf("expected rejected token to fail delivery, got %v", err)
+	}
+}
+
+func TestRetryDelayBacksOff(t *testing.T) {
+	if got := retryDelay(1); got != retryBaseDelay {
+		t.Fatalf("first retry after %s, want %s", got, retryBaseDelay)
+	}
+	if got := retryDelay(3); got != 4*retryBaseDelay {
+		t.Fatalf("third retry after %s, want %s", got, 4*retryBaseDelay)
+	}
+	if got := retryDelay(DefaultMaxAttempts * 10); got != retryMaxDelay {
+		t.Fatalf("expected delay to be capped at %s, got %s", retryMaxDelay, got)
+	}
+}
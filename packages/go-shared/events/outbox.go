@@ -0,0 +1,362 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// DefaultPollInterval is roughly how long a new or retried delivery waits
+	// before it is sent.
+	DefaultPollInterval = 2 * time.Second
+	// DefaultMaxAttempts is how often a delivery is tried before it is
+	// marked dead.
+	DefaultMaxAttempts = 12
+	// DefaultRetention is how long delivered events and processed event IDs
+	// are kept.
+	DefaultRetention = 7 * 24 * time.Hour
+
+	defaultBatchSize = 20
+	retryBaseDelay   = 5 * time.Second
+	retryMaxDelay    = time.Hour
+	// deliveryTimeout times defaultBatchSize stays below deliveryLease, so a
+	// claimed batch is finished before another relay may claim it again.
+	deliveryTimeout    = 10 * time.Second
+	deliveryLease      = 5 * time.Minute
+	pruneInterval      = time.Hour
+	maxLastErrorLength = 1000
+)
+
+// Delivery statuses stored in outbox_deliveries.
+const (
+	DeliveryPending   = "PENDING"
+	DeliveryDelivered = "DELIVERED"
+	DeliveryDead      = "DEAD"
+)
+
+// DBTX is satisfied by pgx transactions and pools. Publish and MarkProcessed
+// take one so they run inside the caller's transaction.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// Outbox records events in the same transaction as the change they describe
+// and relays them to subscribers afterwards. Each subscriber gets its own
+// delivery row, so a slow or failing subscriber does not hold up the others.
+// Deliveries are at least once and not ordered.
+//
+// A nil Outbox publishes nothing.
+type Outbox struct {
+	pool        *pgxpool.Pool
+	source      string
+	subscribers []Subscriber
+	maxAttempts int
+	retention   time.Duration
+	lastPruned  time.Time
+}
+
+// NewOutbox returns an Outbox for the service named source that relays to
+// subscribers.
+func NewOutbox(pool *pgxpool.Pool, source string, subscribers ...Subscriber) *Outbox {
+	return &Outbox{
+		pool:        pool,
+		source:      strings.TrimSpace(source),
+		subscribers: subscribers,
+		maxAttempts: DefaultMaxAttempts,
+		retention:   DefaultRetention,
+	}
+}
+
+// WithMaxAttempts sets how often a delivery is tried before it is marked
+// dead. Values below one keep the default.
+func (o *Outbox) WithMaxAttempts(attempts int) *Outbox {
+	if attempts > 0 {
+		o.maxAttempts = attempts
+	}
+	return o
+}
+
+// WithRetention sets how long delivered events are kept. Values of zero or
+// less keep the default.
+func (o *Outbox) WithRetention(retention time.Duration) *Outbox {
+	if retention > 0 {
+		o.retention = retention
+	}
+	return o
+}
+
+// Publish records an event of eventType in tx together with one pending
+// delivery per subscriber that accepts it. The event is only relayed once
+// tx commits.
+func (o *Outbox) Publish(ctx context.Context, tx DBTX, eventType, aggregateID string, payload any) error {
+	if o == nil {
+		return nil
+	}
+	event, err := New(o.source, eventType, aggregateID, payload)
+	if err != nil {
+		return err
+	}
+	return o.PublishEvent(ctx, tx, event)
+}
+
+// PublishEvent records a prepared event in tx. See Publish.
+func (o *Outbox) PublishEvent(ctx context.Context, tx DBTX, event Event) error {
+	if o == nil {
+		return nil
+	}
+	if err := event.Validate(); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+INSERT INTO outbox_events (id, event_type, source, aggregate_id, payload, occurred_at)
+VALUES ($1, $2, $3, $4, $5, $6)`,
+		event.ID, event.Type, event.Source, event.AggregateID, []byte(event.Payload), event.OccurredAt,
+	); err != nil {
+		return fmt.Errorf("insert outbox event: %w", err)
+	}
+	names := make([]string, 0, len(o.subscribers))
+	for _, subscriber := range o.subscribers {
+		if subscriber.Accepts(event.Type) {
+			names = append(names, subscriber.Name())
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, `
+INSERT INTO outbox_deliveries (event_id, subscriber)
+SELECT $1, unnest($2::text[])`,
+		event.ID, names,
+	); err != nil {
+		return fmt.Errorf("insert outbox deliveries: %w", err)
+	}
+	return nil
+}
+
+type delivery struct {
+	subscriber string
+	attempts   int
+	event      Event
+}
+
+// RunOnce delivers the deliveries that are due and returns how many it
+// attempted. Each delivery is leased before it is attempted, so several
+// replicas can relay concurrently and a crashed relay's deliveries are
+// retried once the lease expires.
+func (o *Outbox) RunOnce(ctx context.Context) (int, error) {
+	if o == nil || o.pool == nil {
+		return 0, nil
+	}
+	due, err := o.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, item := range due {
+		deliverErr := o.deliver(ctx, item)
+		if err := o.record(ctx, item, deliverErr); err != nil {
+			return len(due), err
+		}
+	}
+	return len(due), nil
+}
+
+// Start relays due deliveries every interval until ctx is cancelled and
+// prunes old events once an hour. Failures are logged.
+func (o *Outbox) Start(ctx context.Context, interval time.Duration, logger *slog.Logger) {
+	if o == nil || o.pool == nil {
+		return
+	}
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				o.tick(ctx, logger)
+			}
+		}
+	}()
+}
+
+func (o *Outbox) tick(ctx context.Context, logger *slog.Logger) {
+	for {
+		count, err := o.RunOnce(ctx)
+		if err != nil {
+			if logger != nil && ctx.Err() == nil {
+				logger.Warn("outbox relay failed", "error", err)
+			}
+			return
+		}
+		// A full batch means more deliveries may be due right away.
+		if count < defaultBatchSize {
+			break
+		}
+	}
+	if time.Since(o.lastPruned) < pruneInterval {
+		return
+	}
+	o.lastPruned = time.Now()
+	if _, err := o.Prune(ctx, time.Now().Add(-o.retention)); err != nil && logger != nil && ctx.Err() == nil {
+		logger.Warn("outbox prune failed", "error", err)
+	}
+}
+
+// Prune deletes events recorded before cutoff whose deliveries all
+// succeeded, and processed event IDs older than cutoff. Dead deliveries are
+// kept for inspection.
+func (o *Outbox) Prune(ctx context.Context, cutoff time.Time) (int64, error) {
+	if o == nil || o.pool == nil {
+		return 0, nil
+	}
+	tag, err := o.pool.Exec(ctx, `
+DELETE FROM outbox_events e
+WHERE e.created_at < $1
+  AND NOT EXISTS (
+    SELECT 1 FROM outbox_deliveries d
+    WHERE d.event_id = e.id AND d.status <> 'DELIVERED'
+  )`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("prune outbox events: %w", err)
+	}
+	if _, err := o.pool.Exec(ctx, `DELETE FROM processed_events WHERE processed_at < $1`, cutoff); err != nil {
+		return tag.RowsAffected(), fmt.Errorf("prune processed events: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+func (o *Outbox) claim(ctx context.Context) ([]delivery, error) {
+	rows, err := o.pool.Query(ctx, `
+UPDATE outbox_deliveries d
+SET attempts = d.attempts + 1,
+    next_attempt_at = now() + make_interval(secs => $2),
+    updated_at = now()
+FROM outbox_events e
+WHERE e.id = d.event_id
+  AND (d.event_id, d.subscriber) IN (
+    SELECT event_id, subscriber
+    FROM outbox_deliveries
+    WHERE status = 'PENDING' AND next_attempt_at <= now()
+    ORDER BY next_attempt_at
+    LIMIT $1
+    FOR UPDATE SKIP LOCKED
+  )
+RETURNING d.subscriber, d.attempts, e.id, e.event_type, e.source, e.aggregate_id, e.occurred_at, e.payload`,
+		defaultBatchSize, deliveryLease.Seconds(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("claim outbox deliveries: %w", err)
+	}
+	defer rows.Close()
+	var due []delivery
+	for rows.Next() {
+		var item delivery
+		var payload []byte
+		if err := rows.Scan(&item.subscriber, &item.attempts, &item.event.ID, &item.event.Type, &item.event.Source, &item.event.AggregateID, &item.event.OccurredAt, &payload); err != nil {
+			return nil, fmt.Errorf("scan outbox delivery: %w", err)
+		}
+		item.event.Payload = payload
+		due = append(due, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("claim outbox deliveries: %w", err)
+	}
+	return due, nil
+}
+
+func (o *Outbox) deliver(ctx context.Context, item delivery) error {
+	subscriber := o.subscriber(item.subscriber)
+	if subscriber == nil {
+		return fmt.Errorf("%w: subscriber %q is not configured", ErrDeliveryFailed, item.subscriber)
+	}
+	deliverCtx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+	return subscriber.Deliver(deliverCtx, item.event)
+}
+
+func (o *Outbox) record(ctx context.Context, item delivery, deliverErr error) error {
+	if deliverErr == nil {
+		_, err := o.pool.Exec(ctx, `
+UPDATE outbox_deliveries
+SET status = 'DELIVERED', delivered_at = now(), last_error = NULL, updated_at = now()
+WHERE event_id = $1 AND subscriber = $2`, item.event.ID, item.subscriber)
+		if err != nil {
+			return fmt.Errorf("record outbox delivery: %w", err)
+		}
+		return nil
+	}
+	status := DeliveryPending
+	if item.attempts >= o.maxAttempts {
+		status = DeliveryDead
+	}
+	_, err := o.pool.Exec(ctx, `
+UPDATE outbox_deliveries
+SET status = $3, next_attempt_at = now() + make_interval(secs => $4), last_error = $5, updated_at = now()
+WHERE event_id = $1 AND subscriber = $2`,
+		item.event.ID, item.subscriber, status, retryDelay(item.attempts).Seconds(), truncateError(deliverErr),
+	)
+	if err != nil {
+		return fmt.Errorf("record outbox delivery: %w", err)
+	}
+	return nil
+}
+
+func (o *Outbox) subscriber(name string) Subscriber {
+	for _, subscriber := range o.subscribers {
+		if subscriber.Name() == name {
+			return subscriber
+		}
+	}
+	return nil
+}
+
+// retryDelay doubles the wait after every failed attempt, capped at an hour.
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
+
+func truncateError(err error) string {
+	message := err.Error()
+	if len(message) > maxLastErrorLength {
+		return message[:maxLastErrorLength]
+	}
+	return message
+}
+
+// MarkProcessed records that consumer handled event and reports whether it
+// had not been recorded before. Consumers call it in the transaction that
+// applies the event and skip the event when it returns false, which makes
+// redelivered events no-ops.
+func MarkProcessed(ctx context.Context, tx DBTX, consumer string, event Event) (bool, error) {
+	if event.ID == uuid.Nil {
+		return false, ErrInvalidEvent
+	}
+	tag, err := tx.Exec(ctx, `
+INSERT INTO processed_events (event_id, consumer, event_type)
+VALUES ($1, $2, $3)
+ON CONFLICT (event_id, consumer) DO NOTHING`,
+		event.ID, consumer, event.Type,
+	)
+	if err != nil {
+		return false, fmt.Errorf("mark event processed: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
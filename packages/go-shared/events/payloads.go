@@ -0,0 +1,98 @@
+package events
+
+import "time"
+
+// OrderItem is an order line as carried by order events.
+type OrderItem struct {
+	SkuID        string `json:"skuId"`
+	Qty          int32  `json:"qty"`
+	UnitPriceFen int64  `json:"unitPriceFen"`
+}
+
+// OrderCreated is the payload of TypeOrderCreated.
+type OrderCreated struct {
+	OrderID          string      `json:"orderId"`
+	OrderNo          string      `json:"orderNo,omitempty"`
+	CustomerID       string      `json:"customerId"`
+	OwnerSalesUserID *string     `json:"ownerSalesUserId,omitempty"`
+	Status           string      `json:"status"`
+	PaymentStatus    string      `json:"paymentStatus"`
+	DiscountFen      int64       `json:"discountFen,omitempty"`
+	ShippingFen      int64       `json:"shippingFen,omitempty"`
+	TotalFen         int64       `json:"totalFen"`
+	Items            []OrderItem `json:"items"`
+	CreatedAt        time.Time   `json:"createdAt"`
+}
+
+// OrderStatusChanged is the payload of TypeOrderStatusChanged. It is published
+// for every status or payment status change except closing, which has its own
+// event.
+type OrderStatusChanged struct {
+	OrderID               string    `json:"orderId"`
+	CustomerID            string    `json:"customerId"`
+	Status                string    `json:"status"`
+	PreviousStatus        string    `json:"previousStatus"`
+	PaymentStatus         string    `json:"paymentStatus"`
+	PreviousPaymentStatus string    `json:"previousPaymentStatus"`
+	ChangedAt             time.Time `json:"changedAt"`
+}
+
+// OrderClosed is the payload of TypeOrderClosed.
+type OrderClosed struct {
+	OrderID    string    `json:"orderId"`
+	Status     string    `json:"status"`
+	ReasonCode string    `json:"reasonCode"`
+	ClosedAt   time.Time `json:"closedAt"`
+}
+
+// ShipmentItem is the quantity of one order line packed in a parcel.
+type ShipmentItem struct {
+	OrderItemID string `json:"orderItemId"`
+	SkuID       string `json:"skuId"`
+	Qty         int32  `json:"qty"`
+}
+
+// ShipmentAdded is the payload of TypeShipmentAdded. Items is empty for
+// waybills recorded without shipment lines.
+type ShipmentAdded struct {
+	ShipmentID string         `json:"shipmentId"`
+	OrderID    string         `json:"orderId"`
+	WaybillNo  string         `json:"waybillNo"`
+	Carrier    *string        `json:"carrier,omitempty"`
+	ShippedAt  time.Time      `json:"shippedAt"`
+	Items      []ShipmentItem `json:"items,omitempty"`
+}
+
+// TicketUpdated is the payload of TypeTicketUpdated.
+type TicketUpdated struct {
+	TicketID            string    `json:"ticketId"`
+	OrderID             *string   `json:"orderId,omitempty"`
+	CreatedByUserID     string    `json:"createdByUserId"`
+	Status              string    `json:"status"`
+	AssignedStaffUserID *string   `json:"assignedStaffUserId,omitempty"`
+	UpdatedAt           time.Time `json:"updatedAt"`
+}
+
+// StatementGenerated is the payload of TypeStatementGenerated.
+type StatementGenerated struct {
+	StatementID       string    `json:"statementId"`
+	CustomerID        string    `json:"customerId"`
+	Period            string    `json:"period"`
+	OpeningBalanceFen int64     `json:"openingBalanceFen"`
+	ChargesFen        int64     `json:"chargesFen"`
+	PaymentsFen       int64     `json:"paymentsFen"`
+	AdjustmentsFen    int64     `json:"adjustmentsFen"`
+	ClosingBalanceFen int64     `json:"closingBalanceFen"`
+	GeneratedAt       time.Time `json:"generatedAt"`
+}
+
+// PaymentStatus is the payload shared by the payment.* events.
+type PaymentStatus struct {
+	PaymentID       string     `json:"paymentId"`
+	OrderID         string     `json:"orderId"`
+	Channel         string     `json:"channel"`
+	Status          string     `json:"status"`
+	AmountFen       int64      `json:"amountFen"`
+	ProviderTradeNo *string    `json:"providerTradeNo,omitempty"`
+	PaidAt          *time.Time `json:"paidAt,omitempty"`
+}
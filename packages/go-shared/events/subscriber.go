@@ -0,0 +1,109 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrDeliveryFailed is returned when a subscriber did not accept an event.
+var ErrDeliveryFailed = errors.New("events: delivery failed")
+
+// Subscriber receives the events whose type it accepts. Deliver must be safe
+// to call again with an event it already handled.
+type Subscriber interface {
+	Name() string
+	Accepts(eventType string) bool
+	Deliver(ctx context.Context, event Event) error
+}
+
+// HTTPSubscriber pushes events as JSON to another service. It authenticates
+// with the shared internal token; any 2xx response acknowledges the event.
+type HTTPSubscriber struct {
+	name   string
+	url    string
+	token  string
+	types  []string
+	client *http.Client
+}
+
+// NewHTTPSubscriber returns a subscriber that posts events matching types to
+// url. A nil client gets a default with a short timeout.
+func NewHTTPSubscriber(name, url, token string, types []string, client *http.Client) *HTTPSubscriber {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &HTTPSubscriber{
+		name:   strings.TrimSpace(name),
+		url:    strings.TrimSpace(url),
+		token:  strings.TrimSpace(token),
+		types:  types,
+		client: client,
+	}
+}
+
+func (s *HTTPSubscriber) Name() string { return s.name }
+
+func (s *HTTPSubscriber) Accepts(eventType string) bool { return Matches(s.types, eventType) }
+
+func (s *HTTPSubscriber) Deliver(ctx context.Context, event Event) error {
+	if s.url == "" {
+		return fmt.Errorf("%w: url is not configured", ErrDeliveryFailed)
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDeliveryFailed, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDeliveryFailed, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Id", event.ID.String())
+	req.Header.Set("X-Event-Type", event.Type)
+	if s.token != "" {
+		req.Header.Set("X-Internal-Token", s.token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrDeliveryFailed, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %s returned %d", ErrDeliveryFailed, s.name, resp.StatusCode)
+	}
+	return nil
+}
+
+// LocalSubscriber hands events to a function in the publishing service. The
+// outbox tables act as its queue, so work queued in a transaction survives a
+// restart and is retried like a remote delivery.
+type LocalSubscriber struct {
+	name   string
+	types  []string
+	handle func(ctx context.Context, event Event) error
+}
+
+// NewLocalSubscriber returns a subscriber that calls handle for events
+// matching types.
+func NewLocalSubscriber(name string, types []string, handle func(ctx context.Context, event Event) error) *LocalSubscriber {
+	return &LocalSubscriber{name: strings.TrimSpace(name), types: types, handle: handle}
+}
+
+func (s *LocalSubscriber) Name() string { return s.name }
+
+func (s *LocalSubscriber) Accepts(eventType string) bool { return Matches(s.types, eventType) }
+
+func (s *LocalSubscriber) Deliver(ctx context.Context, event Event) error {
+	if s.handle == nil {
+		return fmt.Errorf("%w: %s has no handler", ErrDeliveryFailed, s.name)
+	}
+	return s.handle(ctx, event)
+}
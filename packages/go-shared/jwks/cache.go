@@ -0,0 +1,181 @@
+package jwks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRefreshInterval is how often the key set is refetched on schedule.
+// New keys are picked up sooner through unknown kids, so this mainly drops
+// keys identity has retired.
+const DefaultRefreshInterval = 10 * time.Minute
+
+// minRefetchInterval bounds how often an unknown kid triggers a fetch, so a
+// flood of tokens with made-up key IDs cannot hammer identity.
+const minRefetchInterval = 10 * time.Second
+
+var (
+	// ErrUnknownKey is returned when no key with the requested ID is known.
+	ErrUnknownKey = errors.New("jwks: unknown key")
+	// ErrUnavailable is returned when the key set cannot be fetched.
+	ErrUnavailable = errors.New("jwks: key set unavailable")
+)
+
+// Cache holds the verification keys published by identity. A key ID it has
+// not seen triggers a refetch, so tokens signed with a freshly rotated key
+// verify without waiting for the next scheduled refresh.
+type Cache struct {
+	url    string
+	client *http.Client
+
+	mu   sync.RWMutex
+	keys map[string]Key
+
+	fetchMu     sync.Mutex
+	lastFetched time.Time
+}
+
+// NewCache returns a Cache that fetches the key set from url. A nil client
+// gets a default with a short timeout.
+func NewCache(url string, client *http.Client) *Cache {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &Cache{
+		url:    strings.TrimSpace(url),
+		client: client,
+		keys:   make(map[string]Key),
+	}
+}
+
+// NewStatic returns a Cache that only knows keys and never fetches.
+func NewStatic(keys ...Key) *Cache {
+	cache := &Cache{keys: make(map[string]Key, len(keys))}
+	for _, key := range keys {
+		cache.keys[key.ID] = key
+	}
+	return cache
+}
+
+// Key returns the key with the given ID, fetching the key set if the ID is
+// not known yet.
+func (c *Cache) Key(ctx context.Context, kid string) (Key, error) {
+	if c == nil {
+		return Key{}, ErrUnknownKey
+	}
+	if key, ok := c.lookup(kid); ok {
+		return key, nil
+	}
+	if c.url == "" || kid == "" {
+		return Key{}, ErrUnknownKey
+	}
+
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+	// Another request may have fetched the key while this one waited.
+	if key, ok := c.lookup(kid); ok {
+		return key, nil
+	}
+	if time.Since(c.lastFetched) < minRefetchInterval {
+		return Key{}, ErrUnknownKey
+	}
+	if err := c.fetch(ctx); err != nil {
+		return Key{}, err
+	}
+	if key, ok := c.lookup(kid); ok {
+		return key, nil
+	}
+	return Key{}, ErrUnknownKey
+}
+
+// Refresh replaces the cached keys with the currently published set. Keys
+// identity stopped publishing are dropped; on failure the cached keys stay in
+// use.
+func (c *Cache) Refresh(ctx context.Context) error {
+	if c == nil || c.url == "" {
+		return nil
+	}
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+	return c.fetch(ctx)
+}
+
+// Start loads the key set immediately and then every interval until ctx is
+// cancelled. Failures are logged and the cached keys stay in use.
+func (c *Cache) Start(ctx context.Context, interval time.Duration, logger *slog.Logger) {
+	if c == nil || c.url == "" {
+		return
+	}
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	go func() {
+		c.refresh(ctx, logger)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				c.refresh(ctx, logger)
+			}
+		}
+	}()
+}
+
+func (c *Cache) refresh(ctx context.Context, logger *slog.Logger) {
+	if err := c.Refresh(ctx); err != nil && logger != nil && ctx.Err() == nil {
+		logger.Warn("jwks refresh failed", "error", err)
+	}
+}
+
+func (c *Cache) lookup(kid string) (Key, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	key, ok := c.keys[kid]
+	return key, ok
+}
+
+// fetch must be called with fetchMu held.
+func (c *Cache) fetch(ctx context.Context) error {
+	c.lastFetched = time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return fmt.Errorf("%w: identity returned %d", ErrUnavailable, resp.StatusCode)
+	}
+	var set Set
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("%w: invalid response", ErrUnavailable)
+	}
+	keys := set.Decode()
+	if len(keys) == 0 {
+		return fmt.Errorf("%w: no usable keys", ErrUnavailable)
+	}
+	next := make(map[string]Key, len(keys))
+	for _, key := range keys {
+		next[key.ID] = key
+	}
+	c.mu.Lock()
+	c.keys = next
+	c.mu.Unlock()
+	return nil
+}
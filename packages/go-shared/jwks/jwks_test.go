@@ -0,0 +1,184 @@
+package jwks
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestNewKeyUsesRFC8037Thumbprint(t *testing.T) {
+	x, err := base64.RawURLEncoding.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	key, err := NewKey(ed25519.PublicKey(x))
+	if err != nil {
+		t.Fatalf("new key: %v", err)
+	}
+	if key.ID != "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k" {
+		t.Fatalf("unexpected kid %q", key.ID)
+	}
+	if key.Algorithm != AlgorithmEdDSA {
+		t.Fatalf("unexpected algorithm %q", key.Algorithm)
+	}
+}
+
+func TestSetRoundTrip(t *testing.T) {
+	edPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ed25519: %v", err)
+	}
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa: %v", err)
+	}
+	edKey, err := NewKey(edPublic)
+	if err != nil {
+		t.Fatalf("ed key: %v", err)
+	}
+	rsaKey, err := NewKey(&rsaPrivate.PublicKey)
+	if err != nil {
+		t.Fatalf("rsa key: %v", err)
+	}
+	if rsaKey.Algorithm != AlgorithmRS256 {
+		t.Fatalf("unexpected rsa algorithm %q", rsaKey.Algorithm)
+	}
+
+	set, err := NewSet(edKey, rsaKey)
+	if err != nil {
+		t.Fatalf("new set: %v", err)
+	}
+	raw, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var decoded Set
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	decoded.Keys = append(decoded.Keys, JWK{Kty: "EC", Kid: "ignored"})
+	keys := decoded.Decode()
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 keys, got %d", len(keys))
+	}
+	if keys[0].ID != edKey.ID || !edPublic.Equal(keys[0].Public) {
+		t.Fatalf("ed25519 key did not round trip: %+v", keys[0])
+	}
+	if keys[1].ID != rsaKey.ID || !rsaPrivate.PublicKey.Equal(keys[1].Public) {
+		t.Fatalf("rsa key did not round trip: %+v", keys[1])
+	}
+
+	if _, err := NewKey("not a key"); !errors.Is(err, ErrUnsupportedKey) {
+		t.Fatalf("expected ErrUnsupportedKey, got %v", err)
+	}
+}
+
+func TestCacheFetchesUnknownKeys(t *testing.T) {
+	first := mustKey(t)
+	second := mustKey(t)
+	published := []Key{first}
+	var fetches atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		set, err := NewSet(published...)
+		if err != nil {
+			t.Errorf("new set: %v", err)
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	defer server.Close()
+
+	cache := NewCache(server.URL, nil)
+	key, err := cache.Key(context.Background(), first.ID)
+	if err != nil {
+		t.Fatalf("key: %v", err)
+	}
+	if key.ID != first.ID {
+		t.Fatalf("unexpected key %q", key.ID)
+	}
+	if _, err := cache.Key(context.Background(), first.ID); err != nil {
+		t.Fatalf("cached key: %v", err)
+	}
+	if fetches.Load() != 1 {
+		t.Fatalf("expected a single fetch, got %d", fetches.Load())
+	}
+
+	// A key published after the last fetch is not refetched within the
+	// rate limit, but a scheduled refresh picks it up.
+	published = []Key{second}
+	if _, err := cache.Key(context.Background(), second.ID); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey within the refetch interval, got %v", err)
+	}
+	if err := cache.Refresh(context.Background()); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if _, err := cache.Key(context.Background(), second.ID); err != nil {
+		t.Fatalf("rotated key: %v", err)
+	}
+	if _, err := cache.Key(context.Background(), first.ID); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected retired key to be dropped, got %v", err)
+	}
+}
+
+func TestCacheKeepsKeysWhenRefreshFails(t *testing.T) {
+	key := mustKey(t)
+	healthy := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		set, _ := NewSet(key)
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	defer server.Close()
+
+	cache := NewCache(server.URL, nil)
+	if err := cache.Refresh(context.Background()); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	healthy = false
+	if err := cache.Refresh(context.Background()); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("expected ErrUnavailable, got %v", err)
+	}
+	if _, err := cache.Key(context.Background(), key.ID); err != nil {
+		t.Fatalf("expected cached key to stay in use: %v", err)
+	}
+
+	var nilCache *Cache
+	if _, err := nilCache.Key(context.Background(), key.ID); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected nil cache to know no keys, got %v", err)
+	}
+}
+
+func TestStaticCache(t *testing.T) {
+	key := mustKey(t)
+	cache := NewStatic(key)
+	if _, err := cache.Key(context.Background(), key.ID); err != nil {
+		t.Fatalf("key: %v", err)
+	}
+	if _, err := cache.Key(context.Background(), "other"); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func mustKey(t *testing.T) Key {
+	t.Helper()
+	public, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	key, err := NewKey(public)
+	if err != nil {
+		t.Fatalf("new key: %v", err)
+	}
+	return key
+}
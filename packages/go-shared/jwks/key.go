@@ -0,0 +1,178 @@
+// Package jwks publishes and consumes the JSON Web Key Set identity signs
+// access tokens with. Identity holds the only private key; other services
+// fetch the public keys from identity's /.well-known/jwks.json, look them up
+// by the token's kid header and can verify tokens but never mint them.
+package jwks
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// Signing algorithms a Key can be used with, as named in the JWT alg header.
+const (
+	AlgorithmEdDSA = "EdDSA"
+	AlgorithmRS256 = "RS256"
+)
+
+// Algorithms lists every algorithm a Key can carry, for restricting the
+// methods a JWT parser accepts.
+var Algorithms = []string{AlgorithmEdDSA, AlgorithmRS256}
+
+// ErrUnsupportedKey is returned for keys other than Ed25519 and RSA.
+var ErrUnsupportedKey = errors.New("jwks: unsupported key type")
+
+// Key is a public verification key together with its key ID and algorithm.
+type Key struct {
+	ID        string
+	Algorithm string
+	Public    crypto.PublicKey
+}
+
+// NewKey wraps public, deriving the algorithm from the key type and the key
+// ID from its RFC 7638 thumbprint so the same key always gets the same kid.
+func NewKey(public crypto.PublicKey) (Key, error) {
+	jwk, err := encode(public)
+	if err != nil {
+		return Key{}, err
+	}
+	kid, err := jwk.thumbprint()
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{ID: kid, Algorithm: jwk.Alg, Public: public}, nil
+}
+
+// JWK is the JSON form of a single public key.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// Set is the JSON document served at /.well-known/jwks.json.
+type Set struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewSet encodes keys for publishing.
+func NewSet(keys ...Key) (Set, error) {
+	set := Set{Keys: make([]JWK, 0, len(keys))}
+	for _, key := range keys {
+		jwk, err := encode(key.Public)
+		if err != nil {
+			return Set{}, err
+		}
+		jwk.Kid = key.ID
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
+// Decode returns the keys in the set. Keys of unsupported types or meant for
+// something other than signatures are skipped so a new key type published
+// by identity does not break older verifiers.
+func (s Set) Decode() []Key {
+	keys := make([]Key, 0, len(s.Keys))
+	for _, jwk := range s.Keys {
+		if jwk.Kid == "" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := jwk.decode()
+		if err != nil {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func encode(public crypto.PublicKey) (JWK, error) {
+	switch typed := public.(type) {
+	case ed25519.PublicKey:
+		return JWK{
+			Kty: "OKP",
+			Use: "sig",
+			Alg: AlgorithmEdDSA,
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(typed),
+		}, nil
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: AlgorithmRS256,
+			N:   base64.RawURLEncoding.EncodeToString(typed.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(typed.E)).Bytes()),
+		}, nil
+	default:
+		return JWK{}, fmt.Errorf("%w: %T", ErrUnsupportedKey, public)
+	}
+}
+
+func (k JWK) decode() (Key, error) {
+	switch k.Kty {
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return Key{}, fmt.Errorf("%w: curve %q", ErrUnsupportedKey, k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return Key{}, fmt.Errorf("jwks: invalid Ed25519 key %q", k.Kid)
+		}
+		return Key{ID: k.Kid, Algorithm: AlgorithmEdDSA, Public: ed25519.PublicKey(x)}, nil
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil || len(n) == 0 {
+			return Key{}, fmt.Errorf("jwks: invalid RSA key %q", k.Kid)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return Key{}, fmt.Errorf("jwks: invalid RSA key %q", k.Kid)
+		}
+		public := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		return Key{ID: k.Kid, Algorithm: AlgorithmRS256, Public: public}, nil
+	default:
+		return Key{}, fmt.Errorf("%w: %q", ErrUnsupportedKey, k.Kty)
+	}
+}
+
+// thumbprint computes the RFC 7638 thumbprint: the SHA-256 of the required
+// members in lexicographic order without whitespace.
+func (k JWK) thumbprint() (string, error) {
+	var members interface{}
+	switch k.Kty {
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{k.Crv, k.Kty, k.X}
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{k.E, k.Kty, k.N}
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedKey, k.Kty)
+	}
+	raw, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
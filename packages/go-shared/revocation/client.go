@@ -0,0 +1,91 @@
+package revocation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnavailable is returned when token versions cannot be loaded.
+var ErrUnavailable = errors.New("revocation: identity unavailable")
+
+// Entry is the current token version of a user. Access tokens carrying an
+// older version have been revoked.
+type Entry struct {
+	UserID       uuid.UUID `json:"userId"`
+	TokenVersion int       `json:"tokenVersion"`
+}
+
+// Source loads token versions that changed after since, together with the
+// time the answer is valid for.
+type Source interface {
+	Changes(ctx context.Context, since time.Time) ([]Entry, time.Time, error)
+}
+
+// Client loads token versions from the identity internal API. It
+// authenticates with the shared internal token rather than a user JWT.
+type Client struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewClient returns a Client for the identity service at baseURL. A nil
+// client gets a default with a short timeout.
+func NewClient(baseURL, token string, client *http.Client) *Client {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &Client{
+		baseURL: strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		token:   strings.TrimSpace(token),
+		client:  client,
+	}
+}
+
+// Changes fetches the token versions identity changed after since. A zero
+// since returns every user whose tokens were ever revoked.
+func (c *Client) Changes(ctx context.Context, since time.Time) ([]Entry, time.Time, error) {
+	if c == nil || c.baseURL == "" {
+		return nil, time.Time{}, fmt.Errorf("%w: base URL is not configured", ErrUnavailable)
+	}
+	endpoint, err := url.JoinPath(c.baseURL, "internal", "token-versions")
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if !since.IsZero() {
+		endpoint += "?since=" + url.QueryEscape(since.UTC().Format(time.RFC3339Nano))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if c.token != "" {
+		req.Header.Set("X-Internal-Token", c.token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, time.Time{}, fmt.Errorf("%w: identity returned %d", ErrUnavailable, resp.StatusCode)
+	}
+	var payload struct {
+		Items []Entry   `json:"items"`
+		AsOf  time.Time `json:"asOf"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, time.Time{}, fmt.Errorf("%w: invalid response", ErrUnavailable)
+	}
+	return payload.Items, payload.AsOf, nil
+}
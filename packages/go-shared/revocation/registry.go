@@ -0,0 +1,111 @@
+// Package revocation lets services reject access tokens identity has
+// revoked without calling identity on every request. Identity stamps a token
+// version into each access token and bumps the user's version on logout from
+// all devices or when the account is disabled; the Registry polls the bumped
+// versions and compares them in memory.
+package revocation
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultRefreshInterval bounds how long a revoked token keeps working here
+// after identity bumps the user's token version.
+const DefaultRefreshInterval = 30 * time.Second
+
+// changeOverlap is subtracted from the previous answer's timestamp so a
+// change committed while that answer was being built is not missed.
+const changeOverlap = 30 * time.Second
+
+// Registry caches the latest token version of every user whose tokens were
+// revoked. Users it has not heard about are on version zero.
+type Registry struct {
+	source Source
+
+	mu       sync.RWMutex
+	versions map[uuid.UUID]int
+	asOf     time.Time
+	loaded   bool
+}
+
+// NewRegistry returns a Registry that polls source. A nil source never
+// revokes anything.
+func NewRegistry(source Source) *Registry {
+	return &Registry{source: source, versions: make(map[uuid.UUID]int)}
+}
+
+// Refresh pulls the versions changed since the previous refresh.
+func (r *Registry) Refresh(ctx context.Context) error {
+	if r == nil || r.source == nil {
+		return nil
+	}
+	r.mu.RLock()
+	since := time.Time{}
+	if r.loaded {
+		since = r.asOf.Add(-changeOverlap)
+	}
+	r.mu.RUnlock()
+
+	entries, asOf, err := r.source.Changes(ctx, since)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, entry := range entries {
+		if entry.TokenVersion > r.versions[entry.UserID] {
+			r.versions[entry.UserID] = entry.TokenVersion
+		}
+	}
+	if asOf.After(r.asOf) {
+		r.asOf = asOf
+	}
+	r.loaded = true
+	return nil
+}
+
+// Revoked reports whether an access token issued to userID with
+// tokenVersion has been revoked since.
+func (r *Registry) Revoked(userID uuid.UUID, tokenVersion int) bool {
+	if r == nil {
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return tokenVersion < r.versions[userID]
+}
+
+// Start loads versions immediately and then every interval until ctx is
+// cancelled. Failures are logged and the cached versions stay in use.
+func (r *Registry) Start(ctx context.Context, interval time.Duration, logger *slog.Logger) {
+	if r == nil || r.source == nil {
+		return
+	}
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	go func() {
+		r.refresh(ctx, logger)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.refresh(ctx, logger)
+			}
+		}
+	}()
+}
+
+func (r *Registry) refresh(ctx context.Context, logger *slog.Logger) {
+	if err := r.Refresh(ctx); err != nil && logger != nil && ctx.Err() == nil {
+		logger.Warn("token revocation refresh failed", "error", err)
+	}
+}
@@ -0,0 +1,121 @@
+package revocation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type stubSource struct {
+	entries []Entry
+	asOf    time.Time
+	err     error
+	since   []time.Time
+}
+
+func (s *stubSource) Changes(_ context.Context, since time.Time) ([]Entry, time.Time, error) {
+	s.since = append(s.since, since)
+	return s.entries, s.asOf, s.err
+}
+
+func TestRegistryRevokesOlderVersions(t *testing.T) {
+	userID := uuid.New()
+	asOf := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	source := &stubSource{entries: []Entry{{UserID: userID, TokenVersion: 2}}, asOf: asOf}
+	registry := NewRegistry(source)
+
+	if registry.Revoked(userID, 0) {
+		t.Fatal("expected nothing revoked before the first refresh")
+	}
+	if err := registry.Refresh(context.Background()); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if !registry.Revoked(userID, 1) {
+		t.Fatal("expected version 1 to be revoked")
+	}
+	if registry.Revoked(userID, 2) {
+		t.Fatal("expected current version to stay valid")
+	}
+	if registry.Revoked(uuid.New(), 0) {
+		t.Fatal("expected unknown users to stay valid")
+	}
+
+	source.entries = []Entry{{UserID: userID, TokenVersion: 1}}
+	if err := registry.Refresh(context.Background()); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if !registry.Revoked(userID, 1) {
+		t.Fatal("expected a stale entry not to lower the version")
+	}
+	if len(source.since) != 2 || !source.since[0].IsZero() || !source.since[1].Equal(asOf.Add(-changeOverlap)) {
+		t.Fatalf("unexpected since values: %v", source.since)
+	}
+}
+
+func TestRegistryKeepsVersionsWhenRefreshFails(t *testing.T) {
+	userID := uuid.New()
+	source := &stubSource{entries: []Entry{{UserID: userID, TokenVersion: 1}}, asOf: time.Now()}
+	registry := NewRegistry(source)
+	if err := registry.Refresh(context.Background()); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	source.err = errors.New("boom")
+	if err := registry.Refresh(context.Background()); err == nil {
+		t.Fatal("expected refresh error")
+	}
+	if !registry.Revoked(userID, 0) {
+		t.Fatal("expected cached version to stay in use")
+	}
+
+	var nilRegistry *Registry
+	if nilRegistry.Revoked(userID, 0) {
+		t.Fatal("expected nil registry to revoke nothing")
+	}
+}
+
+func TestClientChanges(t *testing.T) {
+	userID := uuid.New()
+	asOf := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var gotSince string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/internal/token-versions" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("X-Internal-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		gotSince = r.URL.Query().Get("since")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"items": []Entry{{UserID: userID, TokenVersion: 3}},
+			"asOf":  asOf,
+		})
+	}))
+	defer server.Close()
+
+	since := asOf.Add(-time.Minute)
+	entries, gotAsOf, err := NewClient(server.URL+"/", "secret", nil).Changes(context.Background(), since)
+	if err != nil {
+		t.Fatalf("changes: %v", err)
+	}
+	if len(entries) != 1 || entries[0].UserID != userID || entries[0].TokenVersion != 3 {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if !gotAsOf.Equal(asOf) {
+		t.Fatalf("unexpected asOf: %v", gotAsOf)
+	}
+	if gotSince != since.Format(time.RFC3339Nano) {
+		t.Fatalf("unexpected since query: %q", gotSince)
+	}
+
+	if _, _, err := NewClient(server.URL, "wrong", nil).Changes(context.Background(), time.Time{}); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("expected ErrUnavailable, got %v", err)
+	}
+}
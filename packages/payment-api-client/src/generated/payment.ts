@@ -735,6 +735,8 @@ export const PaymentStatus = {
   PAID: 'PAID',
   PAY_FAILED: 'PAY_FAILED',
   CANCELLED: 'CANCELLED',
+  PARTIALLY_REFUNDED: 'PARTIALLY_REFUNDED',
+  REFUNDED: 'REFUNDED',
 } as const;
 
 export type PaymentClientResult = typeof PaymentClientResult[keyof typeof PaymentClientResult];
@@ -1060,13 +1062,25 @@ export type postPaymentsWechatNotifyResponse200 = {
   data: void
   status: 200
 }
+
+export type postPaymentsWechatNotifyResponse401 = {
+  data: UnauthorizedResponse
+  status: 401
+}
+
+export type postPaymentsWechatNotifyResponse409 = {
+  data: ConflictResponse
+  status: 409
+}
     
 export type postPaymentsWechatNotifyResponseSuccess = (postPaymentsWechatNotifyResponse200) & {
   headers: Headers;
 };
-;
+export type postPaymentsWechatNotifyResponseError = (postPaymentsWechatNotifyResponse401 | postPaymentsWechatNotifyResponse409) & {
+  headers: Headers;
+};
 
-export type postPaymentsWechatNotifyResponse = (postPaymentsWechatNotifyResponseSuccess)
+export type postPaymentsWechatNotifyResponse = (postPaymentsWechatNotifyResponseSuccess | postPaymentsWechatNotifyResponseError)
 
 export const getPostPaymentsWechatNotifyUrl = () => {
 
@@ -1097,13 +1111,25 @@ export type postPaymentsAlipayNotifyResponse200 = {
   data: void
   status: 200
 }
+
+export type postPaymentsAlipayNotifyResponse401 = {
+  data: UnauthorizedResponse
+  status: 401
+}
+
+export type postPaymentsAlipayNotifyResponse409 = {
+  data: ConflictResponse
+  status: 409
+}
     
 export type postPaymentsAlipayNotifyResponseSuccess = (postPaymentsAlipayNotifyResponse200) & {
   headers: Headers;
 };
-;
+export type postPaymentsAlipayNotifyResponseError = (postPaymentsAlipayNotifyResponse401 | postPaymentsAlipayNotifyResponse409) & {
+  headers: Headers;
+};
 
-export type postPaymentsAlipayNotifyResponse = (postPaymentsAlipayNotifyResponseSuccess)
+export type postPaymentsAlipayNotifyResponse = (postPaymentsAlipayNotifyResponseSuccess | postPaymentsAlipayNotifyResponseError)
 
 export const getPostPaymentsAlipayNotifyUrl = () => {
 
@@ -10,7 +10,8 @@ const (
 	defaultHTTPAddr                 = ":8084"
 	defaultLogLevel                 = "info"
 	defaultAuthEnabled              = false
-	defaultJWTSecret                = "dev-secret"
+	defaultJWKSURL                  = "http://localhost:8081/.well-known/jwks.json"
+	defaultJWKSRefreshEvery         = 10 * time.Minute
 	defaultJWTIssuer                = ""
 	defaultCommerceBaseURL          = "http://localhost:8082"
 	defaultRequestTimeout           = 10 * time.Second
@@ -25,7 +26,8 @@ type Config struct {
 	HTTPAddr                 string
 	LogLevel                 string
 	AuthEnabled              bool
-	JWTSecret                string
+	JWKSURL                  string
+	JWKSRefreshEvery         time.Duration
 	JWTIssuer                string
 	CommerceBaseURL          string
 	RequestTimeout           time.Duration
@@ -51,7 +53,8 @@ func Load() Config {
 		HTTPAddr:                 sharedconfig.String("AI_HTTP_ADDR", defaultHTTPAddr),
 		LogLevel:                 sharedconfig.String("AI_LOG_LEVEL", defaultLogLevel),
 		AuthEnabled:              sharedconfig.Bool("AI_AUTH_ENABLED", defaultAuthEnabled),
-		JWTSecret:                sharedconfig.String("AI_JWT_SECRET", defaultJWTSecret),
+		JWKSURL:                  sharedconfig.String("AI_JWKS_URL", defaultJWKSURL),
+		JWKSRefreshEvery:         sharedconfig.Duration("AI_JWKS_REFRESH_EVERY", defaultJWKSRefreshEvery),
 		JWTIssuer:                sharedconfig.String("AI_JWT_ISSUER", defaultJWTIssuer),
 		CommerceBaseURL:          sharedconfig.String("AI_COMMERCE_BASE_URL", defaultCommerceBaseURL),
 		RequestTimeout:           requestTimeout,
@@ -9,7 +9,7 @@ func TestLoadReturnsDefaults(t *testing.T) {
 	t.Setenv("AI_HTTP_ADDR", "")
 	t.Setenv("AI_LOG_LEVEL", "")
 	t.Setenv("AI_AUTH_ENABLED", "")
-	t.Setenv("AI_JWT_SECRET", "")
+	t.Setenv("AI_JWKS_URL", "")
 	t.Setenv("AI_JWT_ISSUER", "")
 	t.Setenv("AI_COMMERCE_BASE_URL", "")
 	t.Setenv("AI_REQUEST_TIMEOUT", "")
@@ -32,7 +32,7 @@ func TestLoadRespectsEnvAndFallsBackOnInvalidDurations(t *testing.T) {
 	t.Setenv("AI_HTTP_ADDR", ":18084")
 	t.Setenv("AI_LOG_LEVEL", "debug")
 	t.Setenv("AI_AUTH_ENABLED", "true")
-	t.Setenv("AI_JWT_SECRET", "secret-1")
+	t.Setenv("AI_JWKS_URL", "http://identity.internal/.well-known/jwks.json")
 	t.Setenv("AI_JWT_ISSUER", "issuer-1")
 	t.Setenv("AI_COMMERCE_BASE_URL", "http://commerce.internal")
 	t.Setenv("AI_REQUEST_TIMEOUT", "-1s")
@@ -43,7 +43,7 @@ func TestLoadRespectsEnvAndFallsBackOnInvalidDurations(t *testing.T) {
 	t.Setenv("AI_KNOWLEDGE_REFRESH_INTERVAL", "0s")
 
 	cfg := Load()
-	if cfg.HTTPAddr != ":18084" || !cfg.AuthEnabled || cfg.JWTSecret != "secret-1" || cfg.JWTIssuer != "issuer-1" {
+	if cfg.HTTPAddr != ":18084" || !cfg.AuthEnabled || cfg.JWKSURL != "http://identity.internal/.well-known/jwks.json" || cfg.JWTIssuer != "issuer-1" {
 		t.Fatalf("unexpected env config %#v", cfg)
 	}
 	if cfg.ProviderBaseURL != "http://provider.internal" || cfg.ProviderAPIKey != "key-1" || cfg.ProviderModel != "model-1" {
@@ -3,6 +3,8 @@ package handler_test
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -16,6 +18,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 
+	"github.com/teamdsb/tmo/packages/go-shared/jwks"
 	"github.com/teamdsb/tmo/services/ai/internal/commerce"
 	httpserver "github.com/teamdsb/tmo/services/ai/internal/http"
 	"github.com/teamdsb/tmo/services/ai/internal/http/handler"
@@ -45,7 +48,7 @@ func TestPostAiAfterSalesSuggestionsUnauthorizedWithoutBearerToken(t *testing.T)
 	gin.SetMode(gin.TestMode)
 
 	router := httpserver.NewRouter(&handler.Handler{
-		Auth:        middleware.NewAuthenticator(true, "dev-secret", "test-issuer"),
+		Auth:        middleware.NewAuthenticator(true, testKeys, "test-issuer"),
 		Commerce:    nil,
 		Knowledge:   staticKnowledge{},
 		Suggestions: staticProvider{},
@@ -110,7 +113,7 @@ func TestPostAiAfterSalesSuggestionsForwardsAuthAndRequestIDToCommerce(t *testin
 	defer upstream.Close()
 
 	router := httpserver.NewRouter(&handler.Handler{
-		Auth:     middleware.NewAuthenticator(true, "dev-secret", "test-issuer"),
+		Auth:     middleware.NewAuthenticator(true, testKeys, "test-issuer"),
 		Commerce: commerce.NewClient(upstream.URL, time.Second),
 		Knowledge: staticKnowledge{result: knowledge.SearchResult{
 			Products: []knowledge.ProductMatch{{Document: knowledge.ProductDocument{Name: "阻燃电缆 3x2.5"}}},
@@ -126,7 +129,7 @@ func TestPostAiAfterSalesSuggestionsForwardsAuthAndRequestIDToCommerce(t *testin
 	body := bytes.NewBufferString(`{"ticketId":"11111111-1111-1111-1111-111111111111","latestMessageId":"22222222-2222-2222-2222-222222222222"}`)
 	req := httptest.NewRequest(http.MethodPost, "/ai/after-sales/suggestions", body)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+signToken(t, "dev-secret", "test-issuer", "CUSTOMER"))
+	req.Header.Set("Authorization", "Bearer "+signToken(t, "test-issuer", "CUSTOMER"))
 	req.Header.Set("X-Request-ID", "req-test-1")
 
 	recorder := httptest.NewRecorder()
@@ -168,7 +171,7 @@ func TestPostAiAfterSalesSuggestionsReturnsTicketNotFound(t *testing.T) {
 	defer upstream.Close()
 
 	router := httpserver.NewRouter(&handler.Handler{
-		Auth:        middleware.NewAuthenticator(true, "dev-secret", "test-issuer"),
+		Auth:        middleware.NewAuthenticator(true, testKeys, "test-issuer"),
 		Commerce:    commerce.NewClient(upstream.URL, time.Second),
 		Knowledge:   staticKnowledge{},
 		Suggestions: staticProvider{suggestions: []string{"unused"}},
@@ -176,7 +179,7 @@ func TestPostAiAfterSalesSuggestionsReturnsTicketNotFound(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPost, "/ai/after-sales/suggestions", strings.NewReader(`{"ticketId":"`+ticketID.String()+`"}`))
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+signToken(t, "dev-secret", "test-issuer", "CUSTOMER"))
+	req.Header.Set("Authorization", "Bearer "+signToken(t, "test-issuer", "CUSTOMER"))
 
 	recorder := httptest.NewRecorder()
 	router.ServeHTTP(recorder, req)
@@ -228,7 +231,7 @@ func TestPostAiAfterSalesSuggestionsRejectsUnknownLatestMessageID(t *testing.T)
 	defer upstream.Close()
 
 	router := httpserver.NewRouter(&handler.Handler{
-		Auth:        middleware.NewAuthenticator(true, "dev-secret", "test-issuer"),
+		Auth:        middleware.NewAuthenticator(true, testKeys, "test-issuer"),
 		Commerce:    commerce.NewClient(upstream.URL, time.Second),
 		Knowledge:   staticKnowledge{},
 		Suggestions: staticProvider{suggestions: []string{"unused"}},
@@ -236,7 +239,7 @@ func TestPostAiAfterSalesSuggestionsRejectsUnknownLatestMessageID(t *testing.T)
 
 	req := httptest.NewRequest(http.MethodPost, "/ai/after-sales/suggestions", strings.NewReader(`{"ticketId":"`+ticketID.String()+`","latestMessageId":"`+unknownMessageID.String()+`"}`))
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+signToken(t, "dev-secret", "test-issuer", "CUSTOMER"))
+	req.Header.Set("Authorization", "Bearer "+signToken(t, "test-issuer", "CUSTOMER"))
 
 	recorder := httptest.NewRecorder()
 	router.ServeHTTP(recorder, req)
@@ -261,7 +264,7 @@ func TestPostAiAfterSalesSuggestionsMapsCommerceUpstreamFailure(t *testing.T) {
 	defer upstream.Close()
 
 	router := httpserver.NewRouter(&handler.Handler{
-		Auth:        middleware.NewAuthenticator(true, "dev-secret", "test-issuer"),
+		Auth:        middleware.NewAuthenticator(true, testKeys, "test-issuer"),
 		Commerce:    commerce.NewClient(upstream.URL, time.Second),
 		Knowledge:   staticKnowledge{},
 		Suggestions: staticProvider{suggestions: []string{"unused"}},
@@ -269,7 +272,7 @@ func TestPostAiAfterSalesSuggestionsMapsCommerceUpstreamFailure(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPost, "/ai/after-sales/suggestions", strings.NewReader(`{"ticketId":"`+ticketID.String()+`"}`))
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+signToken(t, "dev-secret", "test-issuer", "CUSTOMER"))
+	req.Header.Set("Authorization", "Bearer "+signToken(t, "test-issuer", "CUSTOMER"))
 
 	recorder := httptest.NewRecorder()
 	router.ServeHTTP(recorder, req)
@@ -318,7 +321,7 @@ func TestPostAiAfterSalesSuggestionsWithoutLatestMessageIDUsesFullConversation(t
 	defer upstream.Close()
 
 	router := httpserver.NewRouter(&handler.Handler{
-		Auth:        middleware.NewAuthenticator(true, "dev-secret", "test-issuer"),
+		Auth:        middleware.NewAuthenticator(true, testKeys, "test-issuer"),
 		Commerce:    commerce.NewClient(upstream.URL, time.Second),
 		Knowledge:   staticKnowledge{},
 		Suggestions: staticProvider{suggestions: []string{"建议 1", "建议 2"}},
@@ -326,7 +329,7 @@ func TestPostAiAfterSalesSuggestionsWithoutLatestMessageIDUsesFullConversation(t
 
 	req := httptest.NewRequest(http.MethodPost, "/ai/after-sales/suggestions", strings.NewReader(`{"ticketId":"`+ticketID.String()+`"}`))
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+signToken(t, "dev-secret", "test-issuer", "CUSTOMER"))
+	req.Header.Set("Authorization", "Bearer "+signToken(t, "test-issuer", "CUSTOMER"))
 
 	recorder := httptest.NewRecorder()
 	router.ServeHTTP(recorder, req)
@@ -364,7 +367,7 @@ func TestPostAiAfterSalesSuggestionsReturnsProviderUnavailable(t *testing.T) {
 	defer upstream.Close()
 
 	router := httpserver.NewRouter(&handler.Handler{
-		Auth:        middleware.NewAuthenticator(true, "dev-secret", "test-issuer"),
+		Auth:        middleware.NewAuthenticator(true, testKeys, "test-issuer"),
 		Commerce:    commerce.NewClient(upstream.URL, time.Second),
 		Knowledge:   staticKnowledge{},
 		Suggestions: staticProvider{err: errors.New("provider down")},
@@ -372,7 +375,7 @@ func TestPostAiAfterSalesSuggestionsReturnsProviderUnavailable(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodPost, "/ai/after-sales/suggestions", strings.NewReader(`{"ticketId":"`+ticketID.String()+`"}`))
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+signToken(t, "dev-secret", "test-issuer", "CUSTOMER"))
+	req.Header.Set("Authorization", "Bearer "+signToken(t, "test-issuer", "CUSTOMER"))
 
 	recorder := httptest.NewRecorder()
 	router.ServeHTTP(recorder, req)
@@ -385,16 +388,31 @@ func TestPostAiAfterSalesSuggestionsReturnsProviderUnavailable(t *testing.T) {
 	}
 }
 
-func signToken(t *testing.T, secret, issuer, role string) string {
+var testSigner, testKeyID, testKeys = newTestKeys()
+
+func newTestKeys() (ed25519.PrivateKey, string, *jwks.Cache) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	key, err := jwks.NewKey(public)
+	if err != nil {
+		panic(err)
+	}
+	return private, key.ID, jwks.NewStatic(key)
+}
+
+func signToken(t *testing.T, issuer, role string) string {
 	t.Helper()
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{
 		"iss":  issuer,
 		"sub":  "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa",
 		"role": role,
 	})
+	token.Header["kid"] = testKeyID
 
-	signed, err := token.SignedString([]byte(secret))
+	signed, err := token.SignedString(testSigner)
 	if err != nil {
 		t.Fatalf("SignedString() error = %v", err)
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 
 	apierrors "github.com/teamdsb/tmo/packages/go-shared/errors"
+	"github.com/teamdsb/tmo/packages/go-shared/jwks"
 )
 
 type Claims struct {
@@ -20,14 +21,16 @@ type Claims struct {
 
 type Authenticator struct {
 	enabled bool
-	secret  []byte
+	keys    *jwks.Cache
 	issuer  string
 }
 
-func NewAuthenticator(enabled bool, secret, issuer string) *Authenticator {
+// NewAuthenticator verifies access tokens against the public keys identity
+// publishes in keys; the service never holds anything that can sign a token.
+func NewAuthenticator(enabled bool, keys *jwks.Cache, issuer string) *Authenticator {
 	return &Authenticator{
 		enabled: enabled,
-		secret:  []byte(secret),
+		keys:    keys,
 		issuer:  issuer,
 	}
 }
@@ -70,11 +73,16 @@ func (a *Authenticator) parseClaims(c *gin.Context) (Claims, bool) {
 	}
 
 	token, err := jwt.Parse(parts[1], func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unsupported signing method")
+		kid, _ := token.Header["kid"].(string)
+		key, err := a.keys.Key(c.Request.Context(), kid)
+		if err != nil {
+			return nil, err
 		}
-		return a.secret, nil
-	})
+		if token.Method.Alg() != key.Algorithm {
+			return nil, errors.New("signing method does not match key")
+		}
+		return key.Public, nil
+	}, jwt.WithValidMethods(jwks.Algorithms))
 	if err != nil || !token.Valid {
 		writeError(c, http.StatusUnauthorized, "unauthorized", "invalid token")
 		return Claims{}, false
@@ -1,18 +1,22 @@
 package middleware
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/teamdsb/tmo/packages/go-shared/jwks"
 )
 
 func TestRequireRoleReturnsAdminWhenAuthDisabled(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	auth := NewAuthenticator(false, "unused", "")
+	auth := NewAuthenticator(false, nil, "")
 	recorder := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(recorder)
 	ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
@@ -29,11 +33,11 @@ func TestRequireRoleReturnsAdminWhenAuthDisabled(t *testing.T) {
 func TestRequireRoleAcceptsValidTokenAndParsesOwnerSalesUserID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	auth := NewAuthenticator(true, "secret-1", "issuer-1")
+	auth := NewAuthenticator(true, testKeys, "issuer-1")
 	recorder := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(recorder)
 	ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
-	ctx.Request.Header.Set("Authorization", "Bearer "+signAuthToken(t, "secret-1", "issuer-1", "CS", "bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"))
+	ctx.Request.Header.Set("Authorization", "Bearer "+signAuthToken(t, "issuer-1", "CS", "bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb"))
 
 	claims, ok := auth.RequireRole(ctx, "CS")
 	if !ok {
@@ -47,11 +51,11 @@ func TestRequireRoleAcceptsValidTokenAndParsesOwnerSalesUserID(t *testing.T) {
 func TestRequireRoleRejectsWrongRole(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	auth := NewAuthenticator(true, "secret-1", "issuer-1")
+	auth := NewAuthenticator(true, testKeys, "issuer-1")
 	recorder := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(recorder)
 	ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
-	ctx.Request.Header.Set("Authorization", "Bearer "+signAuthToken(t, "secret-1", "issuer-1", "CUSTOMER", ""))
+	ctx.Request.Header.Set("Authorization", "Bearer "+signAuthToken(t, "issuer-1", "CUSTOMER", ""))
 
 	_, ok := auth.RequireRole(ctx, "CS")
 	if ok {
@@ -65,11 +69,11 @@ func TestRequireRoleRejectsWrongRole(t *testing.T) {
 func TestRequireRoleRejectsInvalidIssuer(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	auth := NewAuthenticator(true, "secret-1", "issuer-1")
+	auth := NewAuthenticator(true, testKeys, "issuer-1")
 	recorder := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(recorder)
 	ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
-	ctx.Request.Header.Set("Authorization", "Bearer "+signAuthToken(t, "secret-1", "wrong-issuer", "CS", ""))
+	ctx.Request.Header.Set("Authorization", "Bearer "+signAuthToken(t, "wrong-issuer", "CS", ""))
 
 	_, ok := auth.RequireRole(ctx, "CS")
 	if ok {
@@ -83,17 +87,13 @@ func TestRequireRoleRejectsInvalidIssuer(t *testing.T) {
 func TestRequireRoleRejectsInvalidOwnerSalesUserID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
-	auth := NewAuthenticator(true, "secret-1", "issuer-1")
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	auth := NewAuthenticator(true, testKeys, "issuer-1")
+	signed := signClaims(t, jwt.MapClaims{
 		"iss":              "issuer-1",
 		"sub":              "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa",
 		"role":             "CS",
 		"ownerSalesUserId": "not-a-uuid",
 	})
-	signed, err := token.SignedString([]byte("secret-1"))
-	if err != nil {
-		t.Fatalf("SignedString() error = %v", err)
-	}
 
 	recorder := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(recorder)
@@ -109,7 +109,49 @@ func TestRequireRoleRejectsInvalidOwnerSalesUserID(t *testing.T) {
 	}
 }
 
-func signAuthToken(t *testing.T, secret, issuer, role, ownerSalesUserID string) string {
+func TestRequireRoleRejectsHMACToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	auth := NewAuthenticator(true, testKeys, "issuer-1")
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss":  "issuer-1",
+		"sub":  "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa",
+		"role": "CS",
+	})
+	token.Header["kid"] = testKeyID
+	signed, err := token.SignedString([]byte("dev-secret"))
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx.Request.Header.Set("Authorization", "Bearer "+signed)
+
+	if _, ok := auth.RequireRole(ctx, "CS"); ok {
+		t.Fatalf("expected HMAC signed token to fail")
+	}
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", recorder.Code)
+	}
+}
+
+var testSigner, testKeyID, testKeys = newTestKeys()
+
+func newTestKeys() (ed25519.PrivateKey, string, *jwks.Cache) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	key, err := jwks.NewKey(public)
+	if err != nil {
+		panic(err)
+	}
+	return private, key.ID, jwks.NewStatic(key)
+}
+
+func signAuthToken(t *testing.T, issuer, role, ownerSalesUserID string) string {
 	t.Helper()
 
 	claims := jwt.MapClaims{
@@ -120,8 +162,15 @@ func signAuthToken(t *testing.T, secret, issuer, role, ownerSalesUserID string)
 	if ownerSalesUserID != "" {
 		claims["ownerSalesUserId"] = ownerSalesUserID
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signed, err := token.SignedString([]byte(secret))
+	return signClaims(t, claims)
+}
+
+func signClaims(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = testKeyID
+	signed, err := token.SignedString(testSigner)
 	if err != nil {
 		t.Fatalf("SignedString() error = %v", err)
 	}
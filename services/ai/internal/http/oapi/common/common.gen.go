@@ -52,7 +52,13 @@ const (
 type AuthResponse struct {
 	AccessToken string `json:"accessToken"`
 	ExpiresIn   int    `json:"expiresIn"`
-	User        User   `json:"user"`
+
+	// RefreshExpiresIn Seconds until the refresh token expires.
+	RefreshExpiresIn *int `json:"refreshExpiresIn,omitempty"`
+
+	// RefreshToken Opaque refresh token for POST /auth/refresh. Returned on login and refresh; each use rotates it.
+	RefreshToken *string `json:"refreshToken,omitempty"`
+	User         User    `json:"user"`
 }
 
 // ErrorResponse defines model for ErrorResponse.
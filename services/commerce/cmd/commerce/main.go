@@ -20,8 +20,17 @@ import (
 	ordermodule "github.com/teamdsb/tmo/services/commerce/internal/modules/order"
 	"github.com/teamdsb/tmo/services/commerce/internal/modules/productimport"
 	"github.com/teamdsb/tmo/services/commerce/internal/modules/productrequestexport"
-
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/search"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/shipmentimport"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/statement"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/tracking"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/webhook"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/packages/go-shared/jwks"
 	"github.com/teamdsb/tmo/packages/go-shared/observability"
+	"github.com/teamdsb/tmo/packages/go-shared/revocation"
 )
 
 func main() {
@@ -74,10 +83,51 @@ func run(ctx context.Context, cfg config.Config, logger *slog.Logger) error {
 	defer pool.Close()
 
 	store := db.New(pool)
-	auth := middleware.NewAuthenticator(cfg.AuthEnabled, cfg.JWTSecret, cfg.JWTIssuer)
+	authorizer := authz.NewAuthorizer(authz.NewClient(cfg.IdentityBaseURL, cfg.IdentityToken, nil))
+	authorizer.Start(ctx, cfg.RBACRefreshEvery, logger)
+	revocations := revocation.NewRegistry(revocation.NewClient(cfg.IdentityBaseURL, cfg.IdentityToken, nil))
+	revocations.Start(ctx, cfg.RevocationRefreshEvery, logger)
+	keys := jwks.NewCache(cfg.JWKSURL, nil)
+	if cfg.AuthEnabled {
+		keys.Start(ctx, cfg.JWKSRefreshEvery, logger)
+	}
+	auth := middleware.NewAuthenticator(cfg.AuthEnabled, keys, cfg.JWTIssuer).
+		WithAuthorizer(authorizer).
+		WithRevocations(revocations)
 	productImportService := productimport.NewService(pool, cfg.MediaLocalOutputDir, cfg.MediaPublicBaseURL, logger)
 	productRequestExportService := productrequestexport.NewService(pool, cfg.MediaLocalOutputDir, cfg.MediaPublicBaseURL)
+	statementService := statement.NewService(store, cfg.MediaLocalOutputDir)
 	supportHub := handler.NewSupportHub()
+	identityClient := handler.NewIdentityClient(cfg.IdentityBaseURL, cfg.IdentityToken, nil)
+	// Payment closes the pending sessions of closed orders it has not been
+	// told about synchronously. Customer webhooks are queued from the same
+	// events and sent by their own dispatcher.
+	outbox := events.NewOutbox(pool, "commerce",
+		events.NewHTTPSubscriber("payment", strings.TrimRight(cfg.PaymentBaseURL, "/")+"/internal/events", cfg.InternalSyncToken, []string{events.TypeOrderClosed}, nil),
+		webhook.NewSubscriber(store),
+	).WithMaxAttempts(cfg.OutboxMaxAttempts)
+	outbox.Start(ctx, cfg.OutboxPollEvery, logger)
+	statementService.DB = pool
+	statementService.Outbox = outbox
+	shipmentImportService := shipmentimport.NewService(pool, outbox, cfg.MediaLocalOutputDir, cfg.MediaPublicBaseURL, logger)
+	webhookDispatcher := &webhook.Dispatcher{
+		Store:        store,
+		MaxAttempts:  cfg.WebhookMaxAttempts,
+		PollInterval: cfg.WebhookPollEvery,
+		Logger:       logger,
+	}
+	webhookDispatcher.Start(ctx)
+	// No production carrier is integrated yet; a fixture directory lets local
+	// environments exercise tracking for any carrier name.
+	carriers := tracking.NewRegistry()
+	if cfg.CarrierFixtureDir != "" {
+		// The fallback answers pushes for every carrier name, so it must not
+		// be reachable without a token.
+		if cfg.CarrierPushToken == "" {
+			return errors.New("COMMERCE_CARRIER_FIXTURE_DIR requires COMMERCE_CARRIER_PUSH_TOKEN")
+		}
+		carriers.SetFallback(&tracking.FixtureAdapter{Dir: cfg.CarrierFixtureDir, Token: cfg.CarrierPushToken})
+	}
 	apiHandler := &handler.Handler{
 		AddressStore:         store,
 		CatalogStore:         store,
@@ -88,16 +138,32 @@ func run(ctx context.Context, cfg config.Config, logger *slog.Logger) error {
 		ProductRequestStore:  store,
 		AfterSalesStore:      store,
 		InquiryStore:         store,
+		QuotationStore:       store,
+		InventoryStore:       store,
+		ReceivableStore:      store,
+		StatementStore:       store,
 		SupportStore:         store,
+		WebhookStore:         store,
+		SearchStore:          store,
+		PricingStore:         store,
+		PromotionStore:       store,
+		ShippingStore:        store,
 		ProductImport:        productImportService,
 		ProductRequestExport: productRequestExportService,
+		ShipmentImport:       shipmentImportService,
 		SupportHub:           supportHub,
+		Webhooks:             webhookDispatcher,
+		Carriers:             carriers,
 		MediaLocalOutputDir:  cfg.MediaLocalOutputDir,
 		MediaPublicBaseURL:   cfg.MediaPublicBaseURL,
 		InternalSyncToken:    cfg.InternalSyncToken,
 		DB:                   pool,
+		Outbox:               outbox,
 		Auth:                 auth,
-		SalesValidator:       handler.NewIdentityClient(cfg.IdentityBaseURL, nil),
+		SalesValidator:       identityClient,
+		FinanceProfiles:      identityClient,
+		CustomerTags:         identityClient,
+		Payments:             handler.NewPaymentClient(cfg.PaymentBaseURL, cfg.InternalSyncToken, nil),
 		Logger:               logger,
 	}
 	(&productimport.Worker{
@@ -108,12 +174,43 @@ func run(ctx context.Context, cfg config.Config, logger *slog.Logger) error {
 		Runner: productRequestExportService,
 		Logger: logger,
 	}).Start(ctx)
+	(&shipmentimport.Worker{
+		Runner: shipmentImportService,
+		Logger: logger,
+	}).Start(ctx)
+	(&statement.Worker{
+		Runner: statementService,
+		Logger: logger,
+	}).Start(ctx)
 	(&ordermodule.AutoDeliveryWorker{
 		Store:         store,
+		DB:            pool,
+		Outbox:        outbox,
 		After:         cfg.AutoDeliveryAfter,
 		CheckInterval: cfg.AutoDeliveryEvery,
 		Logger:        logger,
 	}).Start(ctx)
+	(&tracking.Poller{
+		DB:           pool,
+		Outbox:       outbox,
+		Carriers:     carriers,
+		Interval:     cfg.TrackingPollEvery,
+		RefreshAfter: cfg.TrackingRefreshAfter,
+		Logger:       logger,
+	}).Start(ctx)
+	(&search.Indexer{
+		DB:       pool,
+		Interval: cfg.SearchReindexEvery,
+		Logger:   logger,
+	}).Start(ctx)
+	(&ordermodule.AutoCloseWorker{
+		DB:            pool,
+		Payments:      apiHandler.Payments,
+		Outbox:        outbox,
+		After:         cfg.AutoCloseAfter,
+		CheckInterval: cfg.AutoCloseEvery,
+		Logger:        logger,
+	}).Start(ctx)
 
 	router := httpserver.NewRouter(apiHandler, logger, func(checkCtx context.Context) error {
 		return db.Ready(checkCtx, pool)
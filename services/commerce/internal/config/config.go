@@ -14,43 +14,92 @@ const (
 	defaultAuthEnabled         = false
 	defaultMediaLocalOutputDir = "./infra/dev/media"
 	defaultMediaPublicBaseURL  = "http://localhost:8080/assets/media"
-	// #nosec G101 -- local dev JWT defaults are safe for test environments.
-	defaultJWTSecret         = "dev-secret"
-	defaultJWTIssuer         = ""
-	defaultInternalSyncToken = "dev-payment-sync-token"
-	defaultIdentityBaseURL   = "http://localhost:8081"
-	defaultAutoDeliveryAfter = 7 * 24 * time.Hour
-	defaultAutoDeliveryEvery = time.Hour
+	// Access tokens are verified against the keys identity publishes.
+	defaultJWKSURL                = "http://localhost:8081/.well-known/jwks.json"
+	defaultJWKSRefreshEvery       = 10 * time.Minute
+	defaultJWTIssuer              = ""
+	defaultInternalSyncToken      = "dev-payment-sync-token"
+	defaultIdentityBaseURL        = "http://localhost:8081"
+	defaultPaymentBaseURL         = "http://localhost:8083"
+	defaultAutoDeliveryAfter      = 7 * 24 * time.Hour
+	defaultAutoDeliveryEvery      = time.Hour
+	defaultAutoCloseAfter         = 24 * time.Hour
+	defaultAutoCloseEvery         = 5 * time.Minute
+	defaultRBACRefreshEvery       = time.Minute
+	defaultRevocationRefreshEvery = 30 * time.Second
+	defaultOutboxPollEvery        = 2 * time.Second
+	defaultOutboxMaxAttempts      = 12
+	defaultWebhookPollEvery       = 5 * time.Second
+	defaultWebhookMaxAttempts     = 12
+	defaultTrackingPollEvery      = 5 * time.Minute
+	defaultTrackingRefreshAfter   = 30 * time.Minute
+	defaultSearchReindexEvery     = 10 * time.Minute
+	defaultCarrierFixtureDir      = ""
+	defaultCarrierPushToken       = ""
+	// #nosec G101 -- local dev internal token default is safe for test environments.
+	defaultIdentityToken = "dev-identity-internal-token"
 )
 
 type Config struct {
-	HTTPAddr            string
-	DBDSN               string
-	LogLevel            string
-	AuthEnabled         bool
-	JWTSecret           string
-	JWTIssuer           string
-	MediaLocalOutputDir string
-	MediaPublicBaseURL  string
-	InternalSyncToken   string
-	IdentityBaseURL     string
-	AutoDeliveryAfter   time.Duration
-	AutoDeliveryEvery   time.Duration
+	HTTPAddr               string
+	DBDSN                  string
+	LogLevel               string
+	AuthEnabled            bool
+	JWKSURL                string
+	JWKSRefreshEvery       time.Duration
+	JWTIssuer              string
+	MediaLocalOutputDir    string
+	MediaPublicBaseURL     string
+	InternalSyncToken      string
+	IdentityBaseURL        string
+	IdentityToken          string
+	PaymentBaseURL         string
+	AutoDeliveryAfter      time.Duration
+	AutoDeliveryEvery      time.Duration
+	AutoCloseAfter         time.Duration
+	AutoCloseEvery         time.Duration
+	RBACRefreshEvery       time.Duration
+	RevocationRefreshEvery time.Duration
+	OutboxPollEvery        time.Duration
+	OutboxMaxAttempts      int
+	WebhookPollEvery       time.Duration
+	WebhookMaxAttempts     int
+	TrackingPollEvery      time.Duration
+	TrackingRefreshAfter   time.Duration
+	SearchReindexEvery     time.Duration
+	CarrierFixtureDir      string
+	CarrierPushToken       string
 }
 
 func Load() Config {
 	return Config{
-		HTTPAddr:            sharedconfig.String("COMMERCE_HTTP_ADDR", defaultHTTPAddr),
-		DBDSN:               sharedconfig.String("COMMERCE_DB_DSN", defaultDBDSN),
-		LogLevel:            sharedconfig.String("COMMERCE_LOG_LEVEL", defaultLogLevel),
-		AuthEnabled:         sharedconfig.Bool("COMMERCE_AUTH_ENABLED", defaultAuthEnabled),
-		JWTSecret:           sharedconfig.String("COMMERCE_JWT_SECRET", defaultJWTSecret),
-		JWTIssuer:           sharedconfig.String("COMMERCE_JWT_ISSUER", defaultJWTIssuer),
-		MediaLocalOutputDir: sharedconfig.String("MEDIA_LOCAL_OUTPUT_DIR", defaultMediaLocalOutputDir),
-		MediaPublicBaseURL:  sharedconfig.String("MEDIA_PUBLIC_BASE_URL", defaultMediaPublicBaseURL),
-		InternalSyncToken:   sharedconfig.String("COMMERCE_INTERNAL_SYNC_TOKEN", defaultInternalSyncToken),
-		IdentityBaseURL:     sharedconfig.String("COMMERCE_IDENTITY_BASE_URL", defaultIdentityBaseURL),
-		AutoDeliveryAfter:   sharedconfig.Duration("COMMERCE_AUTO_DELIVERY_AFTER", defaultAutoDeliveryAfter),
-		AutoDeliveryEvery:   sharedconfig.Duration("COMMERCE_AUTO_DELIVERY_EVERY", defaultAutoDeliveryEvery),
+		HTTPAddr:               sharedconfig.String("COMMERCE_HTTP_ADDR", defaultHTTPAddr),
+		DBDSN:                  sharedconfig.String("COMMERCE_DB_DSN", defaultDBDSN),
+		LogLevel:               sharedconfig.String("COMMERCE_LOG_LEVEL", defaultLogLevel),
+		AuthEnabled:            sharedconfig.Bool("COMMERCE_AUTH_ENABLED", defaultAuthEnabled),
+		JWKSURL:                sharedconfig.String("COMMERCE_JWKS_URL", defaultJWKSURL),
+		JWKSRefreshEvery:       sharedconfig.Duration("COMMERCE_JWKS_REFRESH_EVERY", defaultJWKSRefreshEvery),
+		JWTIssuer:              sharedconfig.String("COMMERCE_JWT_ISSUER", defaultJWTIssuer),
+		MediaLocalOutputDir:    sharedconfig.String("MEDIA_LOCAL_OUTPUT_DIR", defaultMediaLocalOutputDir),
+		MediaPublicBaseURL:     sharedconfig.String("MEDIA_PUBLIC_BASE_URL", defaultMediaPublicBaseURL),
+		InternalSyncToken:      sharedconfig.String("COMMERCE_INTERNAL_SYNC_TOKEN", defaultInternalSyncToken),
+		IdentityBaseURL:        sharedconfig.String("COMMERCE_IDENTITY_BASE_URL", defaultIdentityBaseURL),
+		IdentityToken:          sharedconfig.String("COMMERCE_IDENTITY_INTERNAL_TOKEN", defaultIdentityToken),
+		PaymentBaseURL:         sharedconfig.String("COMMERCE_PAYMENT_BASE_URL", defaultPaymentBaseURL),
+		AutoDeliveryAfter:      sharedconfig.Duration("COMMERCE_AUTO_DELIVERY_AFTER", defaultAutoDeliveryAfter),
+		AutoDeliveryEvery:      sharedconfig.Duration("COMMERCE_AUTO_DELIVERY_EVERY", defaultAutoDeliveryEvery),
+		AutoCloseAfter:         sharedconfig.Duration("COMMERCE_AUTO_CLOSE_AFTER", defaultAutoCloseAfter),
+		AutoCloseEvery:         sharedconfig.Duration("COMMERCE_AUTO_CLOSE_EVERY", defaultAutoCloseEvery),
+		RBACRefreshEvery:       sharedconfig.Duration("COMMERCE_RBAC_REFRESH_EVERY", defaultRBACRefreshEvery),
+		RevocationRefreshEvery: sharedconfig.Duration("COMMERCE_REVOCATION_REFRESH_EVERY", defaultRevocationRefreshEvery),
+		OutboxPollEvery:        sharedconfig.Duration("COMMERCE_OUTBOX_POLL_EVERY", defaultOutboxPollEvery),
+		OutboxMaxAttempts:      sharedconfig.Int("COMMERCE_OUTBOX_MAX_ATTEMPTS", defaultOutboxMaxAttempts),
+		WebhookPollEvery:       sharedconfig.Duration("COMMERCE_WEBHOOK_POLL_EVERY", defaultWebhookPollEvery),
+		WebhookMaxAttempts:     sharedconfig.Int("COMMERCE_WEBHOOK_MAX_ATTEMPTS", defaultWebhookMaxAttempts),
+		TrackingPollEvery:      sharedconfig.Duration("COMMERCE_TRACKING_POLL_EVERY", defaultTrackingPollEvery),
+		TrackingRefreshAfter:   sharedconfig.Duration("COMMERCE_TRACKING_REFRESH_AFTER", defaultTrackingRefreshAfter),
+		SearchReindexEvery:     sharedconfig.Duration("COMMERCE_SEARCH_REINDEX_EVERY", defaultSearchReindexEvery),
+		CarrierFixtureDir:      sharedconfig.String("COMMERCE_CARRIER_FIXTURE_DIR", defaultCarrierFixtureDir),
+		CarrierPushToken:       sharedconfig.String("COMMERCE_CARRIER_PUSH_TOKEN", defaultCarrierPushToken),
 	}
 }
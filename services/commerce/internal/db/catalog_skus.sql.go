@@ -20,7 +20,9 @@ INSERT INTO catalog_skus (
     spec,
     attributes,
     unit,
-    is_active
+    is_active,
+    weight_grams,
+    volume_cm3
 ) VALUES (
     $1,
     $2,
@@ -28,19 +30,23 @@ INSERT INTO catalog_skus (
     $4,
     $5,
     $6,
-    $7
+    $7,
+    $8,
+    $9
 )
-RETURNING id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at
+RETURNING id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at, weight_grams, volume_cm3
 `
 
 type CreateSkuParams struct {
-	ProductID  uuid.UUID       `db:"product_id" json:"product_id"`
-	SkuCode    *string         `db:"sku_code" json:"sku_code"`
-	Name       string          `db:"name" json:"name"`
-	Spec       *string         `db:"spec" json:"spec"`
-	Attributes json.RawMessage `db:"attributes" json:"attributes"`
-	Unit       *string         `db:"unit" json:"unit"`
-	IsActive   bool            `db:"is_active" json:"is_active"`
+	ProductID   uuid.UUID       `db:"product_id" json:"product_id"`
+	SkuCode     *string         `db:"sku_code" json:"sku_code"`
+	Name        string          `db:"name" json:"name"`
+	Spec        *string         `db:"spec" json:"spec"`
+	Attributes  json.RawMessage `db:"attributes" json:"attributes"`
+	Unit        *string         `db:"unit" json:"unit"`
+	IsActive    bool            `db:"is_active" json:"is_active"`
+	WeightGrams *int32          `db:"weight_grams" json:"weight_grams"`
+	VolumeCm3   *int32          `db:"volume_cm3" json:"volume_cm3"`
 }
 
 func (q *Queries) CreateSku(ctx context.Context, arg CreateSkuParams) (CatalogSku, error) {
@@ -52,6 +58,8 @@ func (q *Queries) CreateSku(ctx context.Context, arg CreateSkuParams) (CatalogSk
 		arg.Attributes,
 		arg.Unit,
 		arg.IsActive,
+		arg.WeightGrams,
+		arg.VolumeCm3,
 	)
 	var i CatalogSku
 	err := row.Scan(
@@ -65,12 +73,14 @@ func (q *Queries) CreateSku(ctx context.Context, arg CreateSkuParams) (CatalogSk
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WeightGrams,
+		&i.VolumeCm3,
 	)
 	return i, err
 }
 
 const listSkusByIDs = `-- name: ListSkusByIDs :many
-SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at
+SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at, weight_grams, volume_cm3
 FROM catalog_skus
 WHERE id = ANY($1::uuid[])
 `
@@ -95,6 +105,8 @@ func (q *Queries) ListSkusByIDs(ctx context.Context, dollar_1 []uuid.UUID) ([]Ca
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.WeightGrams,
+			&i.VolumeCm3,
 		); err != nil {
 			return nil, err
 		}
@@ -107,7 +119,7 @@ func (q *Queries) ListSkusByIDs(ctx context.Context, dollar_1 []uuid.UUID) ([]Ca
 }
 
 const listSkusByName = `-- name: ListSkusByName :many
-SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at
+SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at, weight_grams, volume_cm3
 FROM catalog_skus
 WHERE name = $1
 `
@@ -132,6 +144,8 @@ func (q *Queries) ListSkusByName(ctx context.Context, name string) ([]CatalogSku
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.WeightGrams,
+			&i.VolumeCm3,
 		); err != nil {
 			return nil, err
 		}
@@ -144,7 +158,7 @@ func (q *Queries) ListSkusByName(ctx context.Context, name string) ([]CatalogSku
 }
 
 const listSkusByNameAndSpec = `-- name: ListSkusByNameAndSpec :many
-SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at
+SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at, weight_grams, volume_cm3
 FROM catalog_skus
 WHERE name = $1
   AND spec = $2
@@ -175,6 +189,8 @@ func (q *Queries) ListSkusByNameAndSpec(ctx context.Context, arg ListSkusByNameA
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.WeightGrams,
+			&i.VolumeCm3,
 		); err != nil {
 			return nil, err
 		}
@@ -187,7 +203,7 @@ func (q *Queries) ListSkusByNameAndSpec(ctx context.Context, arg ListSkusByNameA
 }
 
 const listSkusByProduct = `-- name: ListSkusByProduct :many
-SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at
+SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at, weight_grams, volume_cm3
 FROM catalog_skus
 WHERE product_id = $1
 ORDER BY created_at ASC
@@ -213,6 +229,8 @@ func (q *Queries) ListSkusByProduct(ctx context.Context, productID uuid.UUID) ([
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.WeightGrams,
+			&i.VolumeCm3,
 		); err != nil {
 			return nil, err
 		}
@@ -225,7 +243,7 @@ func (q *Queries) ListSkusByProduct(ctx context.Context, productID uuid.UUID) ([
 }
 
 const listSkusBySkuCode = `-- name: ListSkusBySkuCode :many
-SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at
+SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at, weight_grams, volume_cm3
 FROM catalog_skus
 WHERE sku_code = $1
 `
@@ -250,6 +268,8 @@ func (q *Queries) ListSkusBySkuCode(ctx context.Context, skuCode *string) ([]Cat
 			&i.IsActive,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.WeightGrams,
+			&i.VolumeCm3,
 		); err != nil {
 			return nil, err
 		}
@@ -269,19 +289,23 @@ SET sku_code = $2,
     attributes = $5,
     unit = $6,
     is_active = $7,
+    weight_grams = $8,
+    volume_cm3 = $9,
     updated_at = now()
 WHERE id = $1
-RETURNING id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at
+RETURNING id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at, weight_grams, volume_cm3
 `
 
 type UpdateSkuParams struct {
-	ID         uuid.UUID       `db:"id" json:"id"`
-	SkuCode    *string         `db:"sku_code" json:"sku_code"`
-	Name       string          `db:"name" json:"name"`
-	Spec       *string         `db:"spec" json:"spec"`
-	Attributes json.RawMessage `db:"attributes" json:"attributes"`
-	Unit       *string         `db:"unit" json:"unit"`
-	IsActive   bool            `db:"is_active" json:"is_active"`
+	ID          uuid.UUID       `db:"id" json:"id"`
+	SkuCode     *string         `db:"sku_code" json:"sku_code"`
+	Name        string          `db:"name" json:"name"`
+	Spec        *string         `db:"spec" json:"spec"`
+	Attributes  json.RawMessage `db:"attributes" json:"attributes"`
+	Unit        *string         `db:"unit" json:"unit"`
+	IsActive    bool            `db:"is_active" json:"is_active"`
+	WeightGrams *int32          `db:"weight_grams" json:"weight_grams"`
+	VolumeCm3   *int32          `db:"volume_cm3" json:"volume_cm3"`
 }
 
 func (q *Queries) UpdateSku(ctx context.Context, arg UpdateSkuParams) (CatalogSku, error) {
@@ -293,6 +317,8 @@ func (q *Queries) UpdateSku(ctx context.Context, arg UpdateSkuParams) (CatalogSk
 		arg.Attributes,
 		arg.Unit,
 		arg.IsActive,
+		arg.WeightGrams,
+		arg.VolumeCm3,
 	)
 	var i CatalogSku
 	err := row.Scan(
@@ -306,6 +332,8 @@ func (q *Queries) UpdateSku(ctx context.Context, arg UpdateSkuParams) (CatalogSk
 		&i.IsActive,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.WeightGrams,
+		&i.VolumeCm3,
 	)
 	return i, err
 }
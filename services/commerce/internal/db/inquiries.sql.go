@@ -174,6 +174,32 @@ func (q *Queries) GetPriceInquiry(ctx context.Context, id uuid.UUID) (PriceInqui
 	return i, err
 }
 
+const getPriceInquiryForUpdate = `-- name: GetPriceInquiryForUpdate :one
+SELECT id, created_by_user_id, owner_sales_user_id, assigned_sales_user_id, sku_id, order_id, message, status, response_note, created_at, updated_at
+FROM price_inquiries
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetPriceInquiryForUpdate(ctx context.Context, id uuid.UUID) (PriceInquiry, error) {
+	row := q.db.QueryRow(ctx, getPriceInquiryForUpdate, id)
+	var i PriceInquiry
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedByUserID,
+		&i.OwnerSalesUserID,
+		&i.AssignedSalesUserID,
+		&i.SkuID,
+		&i.OrderID,
+		&i.Message,
+		&i.Status,
+		&i.ResponseNote,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const listInquiryMessages = `-- name: ListInquiryMessages :many
 SELECT id, inquiry_id, sender_type, sender_user_id, content, created_at
 FROM inquiry_messages
@@ -155,17 +155,55 @@ type CatalogProduct struct {
 	Status           string             `db:"status" json:"status"`
 }
 
+type CatalogSearchDocument struct {
+	ProductID      uuid.UUID          `db:"product_id" json:"product_id"`
+	SearchText     string             `db:"search_text" json:"search_text"`
+	PinyinInitials string             `db:"pinyin_initials" json:"pinyin_initials"`
+	Document       interface{}        `db:"document" json:"document"`
+	UpdatedAt      pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
 type CatalogSku struct {
-	ID         uuid.UUID          `db:"id" json:"id"`
-	ProductID  uuid.UUID          `db:"product_id" json:"product_id"`
-	SkuCode    *string            `db:"sku_code" json:"sku_code"`
-	Name       string             `db:"name" json:"name"`
-	Spec       *string            `db:"spec" json:"spec"`
-	Attributes json.RawMessage    `db:"attributes" json:"attributes"`
-	Unit       *string            `db:"unit" json:"unit"`
-	IsActive   bool               `db:"is_active" json:"is_active"`
-	CreatedAt  pgtype.Timestamptz `db:"created_at" json:"created_at"`
-	UpdatedAt  pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	ID          uuid.UUID          `db:"id" json:"id"`
+	ProductID   uuid.UUID          `db:"product_id" json:"product_id"`
+	SkuCode     *string            `db:"sku_code" json:"sku_code"`
+	Name        string             `db:"name" json:"name"`
+	Spec        *string            `db:"spec" json:"spec"`
+	Attributes  json.RawMessage    `db:"attributes" json:"attributes"`
+	Unit        *string            `db:"unit" json:"unit"`
+	IsActive    bool               `db:"is_active" json:"is_active"`
+	CreatedAt   pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	WeightGrams *int32             `db:"weight_grams" json:"weight_grams"`
+	VolumeCm3   *int32             `db:"volume_cm3" json:"volume_cm3"`
+}
+
+type Coupon struct {
+	ID             uuid.UUID          `db:"id" json:"id"`
+	PromotionID    uuid.UUID          `db:"promotion_id" json:"promotion_id"`
+	Code           string             `db:"code" json:"code"`
+	MaxRedemptions int32              `db:"max_redemptions" json:"max_redemptions"`
+	CreatedAt      pgtype.Timestamptz `db:"created_at" json:"created_at"`
+}
+
+type CustomerStatement struct {
+	ID                uuid.UUID          `db:"id" json:"id"`
+	CustomerID        uuid.UUID          `db:"customer_id" json:"customer_id"`
+	Period            string             `db:"period" json:"period"`
+	PeriodStart       pgtype.Timestamptz `db:"period_start" json:"period_start"`
+	PeriodEnd         pgtype.Timestamptz `db:"period_end" json:"period_end"`
+	Status            string             `db:"status" json:"status"`
+	OpeningBalanceFen int64              `db:"opening_balance_fen" json:"opening_balance_fen"`
+	ChargesFen        int64              `db:"charges_fen" json:"charges_fen"`
+	PaymentsFen       int64              `db:"payments_fen" json:"payments_fen"`
+	AdjustmentsFen    int64              `db:"adjustments_fen" json:"adjustments_fen"`
+	ClosingBalanceFen int64              `db:"closing_balance_fen" json:"closing_balance_fen"`
+	FilePath          *string            `db:"file_path" json:"file_path"`
+	ErrorMessage      *string            `db:"error_message" json:"error_message"`
+	RequestedBy       uuid.UUID          `db:"requested_by" json:"requested_by"`
+	GeneratedAt       pgtype.Timestamptz `db:"generated_at" json:"generated_at"`
+	CreatedAt         pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
 }
 
 type ImportJob struct {
@@ -213,6 +251,9 @@ type Order struct {
 	LatestPaymentID  pgtype.UUID        `db:"latest_payment_id" json:"latest_payment_id"`
 	PaymentChannel   *string            `db:"payment_channel" json:"payment_channel"`
 	PaidAt           pgtype.Timestamptz `db:"paid_at" json:"paid_at"`
+	OrderNo          string             `db:"order_no" json:"order_no"`
+	DiscountFen      int64              `db:"discount_fen" json:"discount_fen"`
+	ShippingFen      int64              `db:"shipping_fen" json:"shipping_fen"`
 }
 
 type OrderAdminEvent struct {
@@ -227,8 +268,21 @@ type OrderAdminEvent struct {
 	PreviousPaymentStatus    string             `db:"previous_payment_status" json:"previous_payment_status"`
 	NewPaymentStatus         string             `db:"new_payment_status" json:"new_payment_status"`
 	PreviousOwnerSalesUserID pgtype.UUID        `db:"previous_owner_sales_user_id" json:"previous_owner_sales_user_id"`
-	NewOwnerSalesUserID      uuid.UUID          `db:"new_owner_sales_user_id" json:"new_owner_sales_user_id"`
+	NewOwnerSalesUserID      pgtype.UUID        `db:"new_owner_sales_user_id" json:"new_owner_sales_user_id"`
 	CreatedAt                pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	ReasonCode               *string            `db:"reason_code" json:"reason_code"`
+}
+
+type OrderDiscount struct {
+	ID          uuid.UUID          `db:"id" json:"id"`
+	OrderID     uuid.UUID          `db:"order_id" json:"order_id"`
+	PromotionID uuid.UUID          `db:"promotion_id" json:"promotion_id"`
+	CouponID    pgtype.UUID        `db:"coupon_id" json:"coupon_id"`
+	CouponCode  *string            `db:"coupon_code" json:"coupon_code"`
+	Label       string             `db:"label" json:"label"`
+	AmountFen   int64              `db:"amount_fen" json:"amount_fen"`
+	Position    int32              `db:"position" json:"position"`
+	CreatedAt   pgtype.Timestamptz `db:"created_at" json:"created_at"`
 }
 
 type OrderItem struct {
@@ -242,14 +296,31 @@ type OrderItem struct {
 	SourceCartItemID pgtype.UUID        `db:"source_cart_item_id" json:"source_cart_item_id"`
 }
 
+type OrderNumberSequence struct {
+	Day       pgtype.Date `db:"day" json:"day"`
+	LastValue int64       `db:"last_value" json:"last_value"`
+}
+
+type OrderShipmentLine struct {
+	ID          uuid.UUID          `db:"id" json:"id"`
+	ShipmentID  uuid.UUID          `db:"shipment_id" json:"shipment_id"`
+	OrderItemID uuid.UUID          `db:"order_item_id" json:"order_item_id"`
+	Qty         int32              `db:"qty" json:"qty"`
+	CreatedAt   pgtype.Timestamptz `db:"created_at" json:"created_at"`
+}
+
 type OrderTrackingShipment struct {
-	ID        uuid.UUID          `db:"id" json:"id"`
-	OrderID   uuid.UUID          `db:"order_id" json:"order_id"`
-	WaybillNo string             `db:"waybill_no" json:"waybill_no"`
-	Carrier   *string            `db:"carrier" json:"carrier"`
-	ShippedAt pgtype.Timestamptz `db:"shipped_at" json:"shipped_at"`
-	CreatedAt pgtype.Timestamptz `db:"created_at" json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	ID             uuid.UUID          `db:"id" json:"id"`
+	OrderID        uuid.UUID          `db:"order_id" json:"order_id"`
+	WaybillNo      string             `db:"waybill_no" json:"waybill_no"`
+	Carrier        *string            `db:"carrier" json:"carrier"`
+	ShippedAt      pgtype.Timestamptz `db:"shipped_at" json:"shipped_at"`
+	CreatedAt      pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt      pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	TrackingStatus string             `db:"tracking_status" json:"tracking_status"`
+	LastEventAt    pgtype.Timestamptz `db:"last_event_at" json:"last_event_at"`
+	DeliveredAt    pgtype.Timestamptz `db:"delivered_at" json:"delivered_at"`
+	LastPolledAt   pgtype.Timestamptz `db:"last_polled_at" json:"last_polled_at"`
 }
 
 type PriceInquiry struct {
@@ -266,6 +337,45 @@ type PriceInquiry struct {
 	UpdatedAt           pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
 }
 
+type PriceList struct {
+	ID          uuid.UUID          `db:"id" json:"id"`
+	Name        string             `db:"name" json:"name"`
+	Description *string            `db:"description" json:"description"`
+	Priority    int32              `db:"priority" json:"priority"`
+	IsActive    bool               `db:"is_active" json:"is_active"`
+	ValidFrom   pgtype.Timestamptz `db:"valid_from" json:"valid_from"`
+	ValidUntil  pgtype.Timestamptz `db:"valid_until" json:"valid_until"`
+	CreatedBy   uuid.UUID          `db:"created_by" json:"created_by"`
+	CreatedAt   pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type PriceListAssignment struct {
+	ID            uuid.UUID          `db:"id" json:"id"`
+	PriceListID   uuid.UUID          `db:"price_list_id" json:"price_list_id"`
+	CustomerID    pgtype.UUID        `db:"customer_id" json:"customer_id"`
+	CustomerTagID pgtype.UUID        `db:"customer_tag_id" json:"customer_tag_id"`
+	CreatedAt     pgtype.Timestamptz `db:"created_at" json:"created_at"`
+}
+
+type PriceListRule struct {
+	ID          uuid.UUID          `db:"id" json:"id"`
+	PriceListID uuid.UUID          `db:"price_list_id" json:"price_list_id"`
+	SkuID       pgtype.UUID        `db:"sku_id" json:"sku_id"`
+	CategoryID  pgtype.UUID        `db:"category_id" json:"category_id"`
+	Kind        string             `db:"kind" json:"kind"`
+	DiscountBps *int32             `db:"discount_bps" json:"discount_bps"`
+	CreatedAt   pgtype.Timestamptz `db:"created_at" json:"created_at"`
+}
+
+type PriceListRuleTier struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	RuleID       uuid.UUID `db:"rule_id" json:"rule_id"`
+	MinQty       int32     `db:"min_qty" json:"min_qty"`
+	MaxQty       *int32    `db:"max_qty" json:"max_qty"`
+	UnitPriceFen int64     `db:"unit_price_fen" json:"unit_price_fen"`
+}
+
 type ProductImportJob struct {
 	JobID         uuid.UUID          `db:"job_id" json:"job_id"`
 	ExcelFilePath string             `db:"excel_file_path" json:"excel_file_path"`
@@ -322,6 +432,159 @@ type ProductRequestExportJob struct {
 	UpdatedAt     pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
 }
 
+type Promotion struct {
+	ID               uuid.UUID          `db:"id" json:"id"`
+	Name             string             `db:"name" json:"name"`
+	Description      *string            `db:"description" json:"description"`
+	Kind             string             `db:"kind" json:"kind"`
+	DiscountType     string             `db:"discount_type" json:"discount_type"`
+	DiscountValue    int64              `db:"discount_value" json:"discount_value"`
+	MaxDiscountFen   *int64             `db:"max_discount_fen" json:"max_discount_fen"`
+	MinSpendFen      int64              `db:"min_spend_fen" json:"min_spend_fen"`
+	CategoryID       pgtype.UUID        `db:"category_id" json:"category_id"`
+	Stackable        bool               `db:"stackable" json:"stackable"`
+	PerCustomerLimit *int32             `db:"per_customer_limit" json:"per_customer_limit"`
+	IsActive         bool               `db:"is_active" json:"is_active"`
+	StartsAt         pgtype.Timestamptz `db:"starts_at" json:"starts_at"`
+	EndsAt           pgtype.Timestamptz `db:"ends_at" json:"ends_at"`
+	CreatedBy        uuid.UUID          `db:"created_by" json:"created_by"`
+	CreatedAt        pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type Quotation struct {
+	ID               uuid.UUID          `db:"id" json:"id"`
+	InquiryID        uuid.UUID          `db:"inquiry_id" json:"inquiry_id"`
+	Version          int32              `db:"version" json:"version"`
+	CustomerID       uuid.UUID          `db:"customer_id" json:"customer_id"`
+	OwnerSalesUserID pgtype.UUID        `db:"owner_sales_user_id" json:"owner_sales_user_id"`
+	CreatedByUserID  uuid.UUID          `db:"created_by_user_id" json:"created_by_user_id"`
+	Status           string             `db:"status" json:"status"`
+	ValidUntil       pgtype.Date        `db:"valid_until" json:"valid_until"`
+	Terms            *string            `db:"terms" json:"terms"`
+	DecisionNote     *string            `db:"decision_note" json:"decision_note"`
+	DecidedAt        pgtype.Timestamptz `db:"decided_at" json:"decided_at"`
+	OrderID          pgtype.UUID        `db:"order_id" json:"order_id"`
+	CreatedAt        pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type QuotationItem struct {
+	ID           uuid.UUID `db:"id" json:"id"`
+	QuotationID  uuid.UUID `db:"quotation_id" json:"quotation_id"`
+	SkuID        uuid.UUID `db:"sku_id" json:"sku_id"`
+	Qty          int32     `db:"qty" json:"qty"`
+	UnitPriceFen int64     `db:"unit_price_fen" json:"unit_price_fen"`
+	LineNo       int32     `db:"line_no" json:"line_no"`
+}
+
+type Receivable struct {
+	ID               uuid.UUID          `db:"id" json:"id"`
+	OrderID          uuid.UUID          `db:"order_id" json:"order_id"`
+	CustomerID       uuid.UUID          `db:"customer_id" json:"customer_id"`
+	OwnerSalesUserID pgtype.UUID        `db:"owner_sales_user_id" json:"owner_sales_user_id"`
+	AmountFen        int64              `db:"amount_fen" json:"amount_fen"`
+	Status           string             `db:"status" json:"status"`
+	TermDays         int32              `db:"term_days" json:"term_days"`
+	PostedAt         pgtype.Timestamptz `db:"posted_at" json:"posted_at"`
+	DueAt            pgtype.Timestamptz `db:"due_at" json:"due_at"`
+	SettledAt        pgtype.Timestamptz `db:"settled_at" json:"settled_at"`
+	SettledBy        pgtype.UUID        `db:"settled_by" json:"settled_by"`
+	VoidedAt         pgtype.Timestamptz `db:"voided_at" json:"voided_at"`
+	CreatedAt        pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type ShipmentImportJob struct {
+	JobID         uuid.UUID          `db:"job_id" json:"job_id"`
+	ExcelFilePath string             `db:"excel_file_path" json:"excel_file_path"`
+	ExcelFileName string             `db:"excel_file_name" json:"excel_file_name"`
+	TotalRows     int32              `db:"total_rows" json:"total_rows"`
+	SuccessRows   int32              `db:"success_rows" json:"success_rows"`
+	FailedRows    int32              `db:"failed_rows" json:"failed_rows"`
+	CreatedAt     pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt     pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type ShipmentImportRow struct {
+	ID           uuid.UUID          `db:"id" json:"id"`
+	JobID        uuid.UUID          `db:"job_id" json:"job_id"`
+	LineNo       int32              `db:"line_no" json:"line_no"`
+	OrderRef     *string            `db:"order_ref" json:"order_ref"`
+	WaybillNo    *string            `db:"waybill_no" json:"waybill_no"`
+	RowData      json.RawMessage    `db:"row_data" json:"row_data"`
+	Status       string             `db:"status" json:"status"`
+	ErrorMessage *string            `db:"error_message" json:"error_message"`
+	OrderID      pgtype.UUID        `db:"order_id" json:"order_id"`
+	ShipmentID   pgtype.UUID        `db:"shipment_id" json:"shipment_id"`
+	CreatedAt    pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt    pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type ShipmentEvent struct {
+	ID          uuid.UUID          `db:"id" json:"id"`
+	ShipmentID  uuid.UUID          `db:"shipment_id" json:"shipment_id"`
+	Status      string             `db:"status" json:"status"`
+	Description string             `db:"description" json:"description"`
+	Location    *string            `db:"location" json:"location"`
+	OccurredAt  pgtype.Timestamptz `db:"occurred_at" json:"occurred_at"`
+	Source      string             `db:"source" json:"source"`
+	CreatedAt   pgtype.Timestamptz `db:"created_at" json:"created_at"`
+}
+
+type ShippingRule struct {
+	ID                    uuid.UUID          `db:"id" json:"id"`
+	Name                  string             `db:"name" json:"name"`
+	Province              *string            `db:"province" json:"province"`
+	City                  *string            `db:"city" json:"city"`
+	ChargeType            string             `db:"charge_type" json:"charge_type"`
+	FirstWeightGrams      *int32             `db:"first_weight_grams" json:"first_weight_grams"`
+	FirstFeeFen           *int64             `db:"first_fee_fen" json:"first_fee_fen"`
+	AdditionalWeightGrams *int32             `db:"additional_weight_grams" json:"additional_weight_grams"`
+	AdditionalFeeFen      *int64             `db:"additional_fee_fen" json:"additional_fee_fen"`
+	VolumetricDivisor     *int32             `db:"volumetric_divisor" json:"volumetric_divisor"`
+	FlatFeeFen            *int64             `db:"flat_fee_fen" json:"flat_fee_fen"`
+	FreeShippingMinFen    *int64             `db:"free_shipping_min_fen" json:"free_shipping_min_fen"`
+	IsActive              bool               `db:"is_active" json:"is_active"`
+	CreatedBy             uuid.UUID          `db:"created_by" json:"created_by"`
+	CreatedAt             pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt             pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type SkuInventory struct {
+	SkuID             uuid.UUID          `db:"sku_id" json:"sku_id"`
+	OnHand            int32              `db:"on_hand" json:"on_hand"`
+	Reserved          int32              `db:"reserved" json:"reserved"`
+	LowStockThreshold int32              `db:"low_stock_threshold" json:"low_stock_threshold"`
+	CreatedAt         pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt         pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type SkuInventoryMovement struct {
+	ID            uuid.UUID          `db:"id" json:"id"`
+	SkuID         uuid.UUID          `db:"sku_id" json:"sku_id"`
+	MovementType  string             `db:"movement_type" json:"movement_type"`
+	OnHandDelta   int32              `db:"on_hand_delta" json:"on_hand_delta"`
+	ReservedDelta int32              `db:"reserved_delta" json:"reserved_delta"`
+	OnHandAfter   int32              `db:"on_hand_after" json:"on_hand_after"`
+	ReservedAfter int32              `db:"reserved_after" json:"reserved_after"`
+	OrderID       pgtype.UUID        `db:"order_id" json:"order_id"`
+	ActorUserID   pgtype.UUID        `db:"actor_user_id" json:"actor_user_id"`
+	Reason        *string            `db:"reason" json:"reason"`
+	Note          *string            `db:"note" json:"note"`
+	CreatedAt     pgtype.Timestamptz `db:"created_at" json:"created_at"`
+}
+
+type SkuInventoryReservation struct {
+	ID        uuid.UUID          `db:"id" json:"id"`
+	OrderID   uuid.UUID          `db:"order_id" json:"order_id"`
+	SkuID     uuid.UUID          `db:"sku_id" json:"sku_id"`
+	Qty       int32              `db:"qty" json:"qty"`
+	Status    string             `db:"status" json:"status"`
+	CreatedAt pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
 type SupportConversation struct {
 	ID                  uuid.UUID          `db:"id" json:"id"`
 	CustomerUserID      uuid.UUID          `db:"customer_user_id" json:"customer_user_id"`
@@ -390,6 +653,37 @@ type UserAddress struct {
 	UpdatedAt     pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
 }
 
+type WebhookDelivery struct {
+	ID                 uuid.UUID          `db:"id" json:"id"`
+	SubscriptionID     uuid.UUID          `db:"subscription_id" json:"subscription_id"`
+	EventID            uuid.UUID          `db:"event_id" json:"event_id"`
+	EventType          string             `db:"event_type" json:"event_type"`
+	Payload            json.RawMessage    `db:"payload" json:"payload"`
+	Status             string             `db:"status" json:"status"`
+	Attempts           int32              `db:"attempts" json:"attempts"`
+	ReplayCount        int32              `db:"replay_count" json:"replay_count"`
+	NextAttemptAt      pgtype.Timestamptz `db:"next_attempt_at" json:"next_attempt_at"`
+	LastResponseStatus *int32             `db:"last_response_status" json:"last_response_status"`
+	LastError          *string            `db:"last_error" json:"last_error"`
+	DeliveredAt        pgtype.Timestamptz `db:"delivered_at" json:"delivered_at"`
+	LastReplayedAt     pgtype.Timestamptz `db:"last_replayed_at" json:"last_replayed_at"`
+	CreatedAt          pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt          pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
+type WebhookSubscription struct {
+	ID          uuid.UUID          `db:"id" json:"id"`
+	CustomerID  uuid.UUID          `db:"customer_id" json:"customer_id"`
+	URL         string             `db:"url" json:"url"`
+	Secret      string             `db:"secret" json:"secret"`
+	EventTypes  []string           `db:"event_types" json:"event_types"`
+	Description *string            `db:"description" json:"description"`
+	IsActive    bool               `db:"is_active" json:"is_active"`
+	CreatedBy   uuid.UUID          `db:"created_by" json:"created_by"`
+	CreatedAt   pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+}
+
 type WishlistItem struct {
 	ID          uuid.UUID          `db:"id" json:"id"`
 	OwnerUserID uuid.UUID          `db:"owner_user_id" json:"owner_user_id"`
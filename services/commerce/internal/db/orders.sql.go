@@ -24,7 +24,7 @@ WHERE o.status = $1
     WHERE s.order_id = o.id
       AND s.shipped_at <= $3
   )
-RETURNING o.id, o.status, o.customer_id, o.owner_sales_user_id, o.address, o.remark, o.idempotency_key, o.created_at, o.updated_at, o.payment_status, o.latest_payment_id, o.payment_channel, o.paid_at
+RETURNING o.id, o.status, o.customer_id, o.owner_sales_user_id, o.address, o.remark, o.idempotency_key, o.created_at, o.updated_at, o.payment_status, o.latest_payment_id, o.payment_channel, o.paid_at, o.order_no, o.discount_fen, o.shipping_fen
 `
 
 type AutoDeliverShippedOrdersParams struct {
@@ -56,6 +56,9 @@ func (q *Queries) AutoDeliverShippedOrders(ctx context.Context, arg AutoDeliverS
 			&i.LatestPaymentID,
 			&i.PaymentChannel,
 			&i.PaidAt,
+			&i.OrderNo,
+			&i.DiscountFen,
+			&i.ShippingFen,
 		); err != nil {
 			return nil, err
 		}
@@ -73,22 +76,36 @@ FROM orders
 WHERE ($1::uuid IS NULL OR customer_id = $1)
   AND ($2::uuid IS NULL OR owner_sales_user_id = $2)
   AND ($3::text IS NULL OR status = $3)
+  AND ($4::text IS NULL OR order_no ILIKE '%' || $4 || '%' ESCAPE '\')
 `
 
 type CountOrdersParams struct {
 	CustomerID       pgtype.UUID `db:"customer_id" json:"customer_id"`
 	OwnerSalesUserID pgtype.UUID `db:"owner_sales_user_id" json:"owner_sales_user_id"`
 	Status           *string     `db:"status" json:"status"`
+	OrderNo          *string     `db:"order_no" json:"order_no"`
 }
 
 func (q *Queries) CountOrders(ctx context.Context, arg CountOrdersParams) (int64, error) {
-	row := q.db.QueryRow(ctx, countOrders, arg.CustomerID, arg.OwnerSalesUserID, arg.Status)
+	row := q.db.QueryRow(ctx, countOrders,
+		arg.CustomerID,
+		arg.OwnerSalesUserID,
+		arg.Status,
+		arg.OrderNo,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
 const createOrder = `-- name: CreateOrder :one
+WITH next_order_no AS (
+    INSERT INTO order_number_sequences (day, last_value)
+    VALUES ((now() AT TIME ZONE 'Asia/Shanghai')::date, 1)
+    ON CONFLICT (day) DO UPDATE
+    SET last_value = order_number_sequences.last_value + 1
+    RETURNING 'TMO' || to_char(day, 'YYYYMMDD') || '-' || lpad(last_value::text, 6, '0') AS order_no
+)
 INSERT INTO orders (
     status,
     customer_id,
@@ -96,7 +113,10 @@ INSERT INTO orders (
     address,
     remark,
     idempotency_key,
-    payment_status
+    payment_status,
+    discount_fen,
+    shipping_fen,
+    order_no
 ) VALUES (
     $1,
     $2,
@@ -104,9 +124,12 @@ INSERT INTO orders (
     $4,
     $5,
     $6,
-    $7
+    $7,
+    $8,
+    $9,
+    (SELECT order_no FROM next_order_no)
 )
-RETURNING id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at
+RETURNING id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at, order_no, discount_fen, shipping_fen
 `
 
 type CreateOrderParams struct {
@@ -117,6 +140,8 @@ type CreateOrderParams struct {
 	Remark           *string         `db:"remark" json:"remark"`
 	IdempotencyKey   *string         `db:"idempotency_key" json:"idempotency_key"`
 	PaymentStatus    string          `db:"payment_status" json:"payment_status"`
+	DiscountFen      int64           `db:"discount_fen" json:"discount_fen"`
+	ShippingFen      int64           `db:"shipping_fen" json:"shipping_fen"`
 }
 
 func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error) {
@@ -128,6 +153,8 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 		arg.Remark,
 		arg.IdempotencyKey,
 		arg.PaymentStatus,
+		arg.DiscountFen,
+		arg.ShippingFen,
 	)
 	var i Order
 	err := row.Scan(
@@ -144,6 +171,9 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Order
 		&i.LatestPaymentID,
 		&i.PaymentChannel,
 		&i.PaidAt,
+		&i.OrderNo,
+		&i.DiscountFen,
+		&i.ShippingFen,
 	)
 	return i, err
 }
@@ -152,9 +182,9 @@ const createOrderAdminEvent = `-- name: CreateOrderAdminEvent :one
 INSERT INTO order_admin_events (
     order_id, idempotency_key, actor_user_id, action, note,
     previous_status, new_status, previous_payment_status, new_payment_status,
-    previous_owner_sales_user_id, new_owner_sales_user_id
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
-RETURNING id, order_id, idempotency_key, actor_user_id, action, note, previous_status, new_status, previous_payment_status, new_payment_status, previous_owner_sales_user_id, new_owner_sales_user_id, created_at
+    previous_owner_sales_user_id, new_owner_sales_user_id, reason_code
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id, order_id, idempotency_key, actor_user_id, action, note, previous_status, new_status, previous_payment_status, new_payment_status, previous_owner_sales_user_id, new_owner_sales_user_id, created_at, reason_code
 `
 
 type CreateOrderAdminEventParams struct {
@@ -168,7 +198,8 @@ type CreateOrderAdminEventParams struct {
 	PreviousPaymentStatus    string      `db:"previous_payment_status" json:"previous_payment_status"`
 	NewPaymentStatus         string      `db:"new_payment_status" json:"new_payment_status"`
 	PreviousOwnerSalesUserID pgtype.UUID `db:"previous_owner_sales_user_id" json:"previous_owner_sales_user_id"`
-	NewOwnerSalesUserID      uuid.UUID   `db:"new_owner_sales_user_id" json:"new_owner_sales_user_id"`
+	NewOwnerSalesUserID      pgtype.UUID `db:"new_owner_sales_user_id" json:"new_owner_sales_user_id"`
+	ReasonCode               *string     `db:"reason_code" json:"reason_code"`
 }
 
 func (q *Queries) CreateOrderAdminEvent(ctx context.Context, arg CreateOrderAdminEventParams) (OrderAdminEvent, error) {
@@ -184,6 +215,7 @@ func (q *Queries) CreateOrderAdminEvent(ctx context.Context, arg CreateOrderAdmi
 		arg.NewPaymentStatus,
 		arg.PreviousOwnerSalesUserID,
 		arg.NewOwnerSalesUserID,
+		arg.ReasonCode,
 	)
 	var i OrderAdminEvent
 	err := row.Scan(
@@ -200,6 +232,7 @@ func (q *Queries) CreateOrderAdminEvent(ctx context.Context, arg CreateOrderAdmi
 		&i.PreviousOwnerSalesUserID,
 		&i.NewOwnerSalesUserID,
 		&i.CreatedAt,
+		&i.ReasonCode,
 	)
 	return i, err
 }
@@ -252,7 +285,7 @@ func (q *Queries) CreateOrderItem(ctx context.Context, arg CreateOrderItemParams
 }
 
 const getOrder = `-- name: GetOrder :one
-SELECT id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at
+SELECT id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at, order_no, discount_fen, shipping_fen
 FROM orders
 WHERE id = $1
 `
@@ -274,12 +307,15 @@ func (q *Queries) GetOrder(ctx context.Context, id uuid.UUID) (Order, error) {
 		&i.LatestPaymentID,
 		&i.PaymentChannel,
 		&i.PaidAt,
+		&i.OrderNo,
+		&i.DiscountFen,
+		&i.ShippingFen,
 	)
 	return i, err
 }
 
 const getOrderAdminEventByIdempotencyKey = `-- name: GetOrderAdminEventByIdempotencyKey :one
-SELECT id, order_id, idempotency_key, actor_user_id, action, note, previous_status, new_status, previous_payment_status, new_payment_status, previous_owner_sales_user_id, new_owner_sales_user_id, created_at FROM order_admin_events
+SELECT id, order_id, idempotency_key, actor_user_id, action, note, previous_status, new_status, previous_payment_status, new_payment_status, previous_owner_sales_user_id, new_owner_sales_user_id, created_at, reason_code FROM order_admin_events
 WHERE order_id = $1 AND idempotency_key = $2
 `
 
@@ -305,12 +341,13 @@ func (q *Queries) GetOrderAdminEventByIdempotencyKey(ctx context.Context, arg Ge
 		&i.PreviousOwnerSalesUserID,
 		&i.NewOwnerSalesUserID,
 		&i.CreatedAt,
+		&i.ReasonCode,
 	)
 	return i, err
 }
 
 const getOrderByIdempotencyKey = `-- name: GetOrderByIdempotencyKey :one
-SELECT id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at
+SELECT id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at, order_no, discount_fen, shipping_fen
 FROM orders
 WHERE customer_id = $1 AND idempotency_key = $2
 `
@@ -337,12 +374,45 @@ func (q *Queries) GetOrderByIdempotencyKey(ctx context.Context, arg GetOrderById
 		&i.LatestPaymentID,
 		&i.PaymentChannel,
 		&i.PaidAt,
+		&i.OrderNo,
+		&i.DiscountFen,
+		&i.ShippingFen,
+	)
+	return i, err
+}
+
+const getOrderByOrderNo = `-- name: GetOrderByOrderNo :one
+SELECT id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at, order_no, discount_fen, shipping_fen
+FROM orders
+WHERE order_no = $1
+`
+
+func (q *Queries) GetOrderByOrderNo(ctx context.Context, orderNo string) (Order, error) {
+	row := q.db.QueryRow(ctx, getOrderByOrderNo, orderNo)
+	var i Order
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.CustomerID,
+		&i.OwnerSalesUserID,
+		&i.Address,
+		&i.Remark,
+		&i.IdempotencyKey,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.PaymentStatus,
+		&i.LatestPaymentID,
+		&i.PaymentChannel,
+		&i.PaidAt,
+		&i.OrderNo,
+		&i.DiscountFen,
+		&i.ShippingFen,
 	)
 	return i, err
 }
 
 const getOrderForUpdate = `-- name: GetOrderForUpdate :one
-SELECT id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at
+SELECT id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at, order_no, discount_fen, shipping_fen
 FROM orders
 WHERE id = $1
 FOR UPDATE
@@ -365,12 +435,15 @@ func (q *Queries) GetOrderForUpdate(ctx context.Context, id uuid.UUID) (Order, e
 		&i.LatestPaymentID,
 		&i.PaymentChannel,
 		&i.PaidAt,
+		&i.OrderNo,
+		&i.DiscountFen,
+		&i.ShippingFen,
 	)
 	return i, err
 }
 
 const listOrderAdminEvents = `-- name: ListOrderAdminEvents :many
-SELECT id, order_id, idempotency_key, actor_user_id, action, note, previous_status, new_status, previous_payment_status, new_payment_status, previous_owner_sales_user_id, new_owner_sales_user_id, created_at FROM order_admin_events
+SELECT id, order_id, idempotency_key, actor_user_id, action, note, previous_status, new_status, previous_payment_status, new_payment_status, previous_owner_sales_user_id, new_owner_sales_user_id, created_at, reason_code FROM order_admin_events
 WHERE order_id = $1
 ORDER BY created_at DESC, id DESC
 `
@@ -398,6 +471,7 @@ func (q *Queries) ListOrderAdminEvents(ctx context.Context, orderID uuid.UUID) (
 			&i.PreviousOwnerSalesUserID,
 			&i.NewOwnerSalesUserID,
 			&i.CreatedAt,
+			&i.ReasonCode,
 		); err != nil {
 			return nil, err
 		}
@@ -485,19 +559,21 @@ func (q *Queries) ListOrderStatusStats(ctx context.Context, arg ListOrderStatusS
 }
 
 const listOrders = `-- name: ListOrders :many
-SELECT id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at
+SELECT id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at, order_no, discount_fen, shipping_fen
 FROM orders
 WHERE ($1::uuid IS NULL OR customer_id = $1)
   AND ($2::uuid IS NULL OR owner_sales_user_id = $2)
   AND ($3::text IS NULL OR status = $3)
+  AND ($4::text IS NULL OR order_no ILIKE '%' || $4 || '%' ESCAPE '\')
 ORDER BY created_at DESC
-LIMIT $5 OFFSET $4
+LIMIT $6 OFFSET $5
 `
 
 type ListOrdersParams struct {
 	CustomerID       pgtype.UUID `db:"customer_id" json:"customer_id"`
 	OwnerSalesUserID pgtype.UUID `db:"owner_sales_user_id" json:"owner_sales_user_id"`
 	Status           *string     `db:"status" json:"status"`
+	OrderNo          *string     `db:"order_no" json:"order_no"`
 	Offset           int32       `db:"offset" json:"offset"`
 	Limit            int32       `db:"limit" json:"limit"`
 }
@@ -507,6 +583,7 @@ func (q *Queries) ListOrders(ctx context.Context, arg ListOrdersParams) ([]Order
 		arg.CustomerID,
 		arg.OwnerSalesUserID,
 		arg.Status,
+		arg.OrderNo,
 		arg.Offset,
 		arg.Limit,
 	)
@@ -531,6 +608,68 @@ func (q *Queries) ListOrders(ctx context.Context, arg ListOrdersParams) ([]Order
 			&i.LatestPaymentID,
 			&i.PaymentChannel,
 			&i.PaidAt,
+			&i.OrderNo,
+			&i.DiscountFen,
+			&i.ShippingFen,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUnpaidOrdersCreatedBefore = `-- name: ListUnpaidOrdersCreatedBefore :many
+SELECT id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at, order_no, discount_fen, shipping_fen
+FROM orders
+WHERE status = ANY($1::text[])
+  AND payment_status <> 'PAID'
+  AND created_at <= $2
+  AND NOT EXISTS (
+      SELECT 1
+      FROM receivables r
+      WHERE r.order_id = orders.id
+        AND r.status = 'OPEN'
+  )
+ORDER BY created_at ASC
+LIMIT $3
+`
+
+type ListUnpaidOrdersCreatedBeforeParams struct {
+	Statuses      []string           `db:"statuses" json:"statuses"`
+	CreatedBefore pgtype.Timestamptz `db:"created_before" json:"created_before"`
+	Limit         int32              `db:"limit" json:"limit"`
+}
+
+func (q *Queries) ListUnpaidOrdersCreatedBefore(ctx context.Context, arg ListUnpaidOrdersCreatedBeforeParams) ([]Order, error) {
+	rows, err := q.db.Query(ctx, listUnpaidOrdersCreatedBefore, arg.Statuses, arg.CreatedBefore, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Order
+	for rows.Next() {
+		var i Order
+		if err := rows.Scan(
+			&i.ID,
+			&i.Status,
+			&i.CustomerID,
+			&i.OwnerSalesUserID,
+			&i.Address,
+			&i.Remark,
+			&i.IdempotencyKey,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.PaymentStatus,
+			&i.LatestPaymentID,
+			&i.PaymentChannel,
+			&i.PaidAt,
+			&i.OrderNo,
+			&i.DiscountFen,
+			&i.ShippingFen,
 		); err != nil {
 			return nil, err
 		}
@@ -552,7 +691,7 @@ SET status = $2,
     owner_sales_user_id = $7,
     updated_at = now()
 WHERE id = $1
-RETURNING id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at
+RETURNING id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at, order_no, discount_fen, shipping_fen
 `
 
 type UpdateOrderFulfillmentParams struct {
@@ -590,6 +729,9 @@ func (q *Queries) UpdateOrderFulfillment(ctx context.Context, arg UpdateOrderFul
 		&i.LatestPaymentID,
 		&i.PaymentChannel,
 		&i.PaidAt,
+		&i.OrderNo,
+		&i.DiscountFen,
+		&i.ShippingFen,
 	)
 	return i, err
 }
@@ -603,7 +745,7 @@ SET status = $2,
     paid_at = $6,
     updated_at = now()
 WHERE id = $1
-RETURNING id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at
+RETURNING id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at, order_no, discount_fen, shipping_fen
 `
 
 type UpdateOrderPaymentSummaryParams struct {
@@ -639,6 +781,9 @@ func (q *Queries) UpdateOrderPaymentSummary(ctx context.Context, arg UpdateOrder
 		&i.LatestPaymentID,
 		&i.PaymentChannel,
 		&i.PaidAt,
+		&i.OrderNo,
+		&i.DiscountFen,
+		&i.ShippingFen,
 	)
 	return i, err
 }
@@ -648,7 +793,7 @@ UPDATE orders
 SET status = $2,
     updated_at = now()
 WHERE id = $1
-RETURNING id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at
+RETURNING id, status, customer_id, owner_sales_user_id, address, remark, idempotency_key, created_at, updated_at, payment_status, latest_payment_id, payment_channel, paid_at, order_no, discount_fen, shipping_fen
 `
 
 type UpdateOrderStatusParams struct {
@@ -673,6 +818,9 @@ func (q *Queries) UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusPa
 		&i.LatestPaymentID,
 		&i.PaymentChannel,
 		&i.PaidAt,
+		&i.OrderNo,
+		&i.DiscountFen,
+		&i.ShippingFen,
 	)
 	return i, err
 }
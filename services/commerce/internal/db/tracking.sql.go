@@ -12,8 +12,252 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const claimTrackingShipmentsForPoll = `-- name: ClaimTrackingShipmentsForPoll :many
+UPDATE order_tracking_shipments s
+SET last_polled_at = now()
+WHERE s.id IN (
+    SELECT t.id
+    FROM order_tracking_shipments t
+    JOIN orders o ON o.id = t.order_id
+    WHERE o.status = ANY($1::text[])
+      AND t.carrier IS NOT NULL
+      AND t.tracking_status <> 'DELIVERED'
+      AND (t.last_polled_at IS NULL OR t.last_polled_at <= $2)
+    ORDER BY t.last_polled_at ASC NULLS FIRST
+    LIMIT $3
+    FOR UPDATE OF t SKIP LOCKED
+)
+RETURNING s.id, s.order_id, s.waybill_no, s.carrier, s.shipped_at, s.created_at, s.updated_at, s.tracking_status, s.last_event_at, s.delivered_at, s.last_polled_at
+`
+
+type ClaimTrackingShipmentsForPollParams struct {
+	OrderStatuses []string           `db:"order_statuses" json:"order_statuses"`
+	PolledBefore  pgtype.Timestamptz `db:"polled_before" json:"polled_before"`
+	BatchSize     int32              `db:"batch_size" json:"batch_size"`
+}
+
+func (q *Queries) ClaimTrackingShipmentsForPoll(ctx context.Context, arg ClaimTrackingShipmentsForPollParams) ([]OrderTrackingShipment, error) {
+	rows, err := q.db.Query(ctx, claimTrackingShipmentsForPoll, arg.OrderStatuses, arg.PolledBefore, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OrderTrackingShipment
+	for rows.Next() {
+		var i OrderTrackingShipment
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.WaybillNo,
+			&i.Carrier,
+			&i.ShippedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TrackingStatus,
+			&i.LastEventAt,
+			&i.DeliveredAt,
+			&i.LastPolledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createShipmentEvent = `-- name: CreateShipmentEvent :execrows
+INSERT INTO shipment_events (
+    shipment_id,
+    status,
+    description,
+    location,
+    occurred_at,
+    source
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+)
+ON CONFLICT (shipment_id, occurred_at, status, description) DO NOTHING
+`
+
+type CreateShipmentEventParams struct {
+	ShipmentID  uuid.UUID          `db:"shipment_id" json:"shipment_id"`
+	Status      string             `db:"status" json:"status"`
+	Description string             `db:"description" json:"description"`
+	Location    *string            `db:"location" json:"location"`
+	OccurredAt  pgtype.Timestamptz `db:"occurred_at" json:"occurred_at"`
+	Source      string             `db:"source" json:"source"`
+}
+
+func (q *Queries) CreateShipmentEvent(ctx context.Context, arg CreateShipmentEventParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createShipmentEvent,
+		arg.ShipmentID,
+		arg.Status,
+		arg.Description,
+		arg.Location,
+		arg.OccurredAt,
+		arg.Source,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createShipmentLine = `-- name: CreateShipmentLine :one
+INSERT INTO order_shipment_lines (
+    shipment_id,
+    order_item_id,
+    qty
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+RETURNING id, shipment_id, order_item_id, qty, created_at
+`
+
+type CreateShipmentLineParams struct {
+	ShipmentID  uuid.UUID `db:"shipment_id" json:"shipment_id"`
+	OrderItemID uuid.UUID `db:"order_item_id" json:"order_item_id"`
+	Qty         int32     `db:"qty" json:"qty"`
+}
+
+func (q *Queries) CreateShipmentLine(ctx context.Context, arg CreateShipmentLineParams) (OrderShipmentLine, error) {
+	row := q.db.QueryRow(ctx, createShipmentLine, arg.ShipmentID, arg.OrderItemID, arg.Qty)
+	var i OrderShipmentLine
+	err := row.Scan(
+		&i.ID,
+		&i.ShipmentID,
+		&i.OrderItemID,
+		&i.Qty,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getTrackingShipmentForUpdate = `-- name: GetTrackingShipmentForUpdate :one
+SELECT id, order_id, waybill_no, carrier, shipped_at, created_at, updated_at, tracking_status, last_event_at, delivered_at, last_polled_at
+FROM order_tracking_shipments
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetTrackingShipmentForUpdate(ctx context.Context, id uuid.UUID) (OrderTrackingShipment, error) {
+	row := q.db.QueryRow(ctx, getTrackingShipmentForUpdate, id)
+	var i OrderTrackingShipment
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.WaybillNo,
+		&i.Carrier,
+		&i.ShippedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TrackingStatus,
+		&i.LastEventAt,
+		&i.DeliveredAt,
+		&i.LastPolledAt,
+	)
+	return i, err
+}
+
+const listShipmentEventsByOrder = `-- name: ListShipmentEventsByOrder :many
+SELECT e.id, e.shipment_id, e.status, e.description, e.location, e.occurred_at, e.source, e.created_at
+FROM shipment_events e
+JOIN order_tracking_shipments s ON s.id = e.shipment_id
+WHERE s.order_id = $1
+ORDER BY e.occurred_at DESC, e.created_at DESC
+`
+
+func (q *Queries) ListShipmentEventsByOrder(ctx context.Context, orderID uuid.UUID) ([]ShipmentEvent, error) {
+	rows, err := q.db.Query(ctx, listShipmentEventsByOrder, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ShipmentEvent
+	for rows.Next() {
+		var i ShipmentEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShipmentID,
+			&i.Status,
+			&i.Description,
+			&i.Location,
+			&i.OccurredAt,
+			&i.Source,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listShipmentLinesByOrder = `-- name: ListShipmentLinesByOrder :many
+SELECT l.id, l.shipment_id, l.order_item_id, l.qty, l.created_at, s.waybill_no, oi.sku_id, cs.name AS sku_name
+FROM order_shipment_lines l
+JOIN order_tracking_shipments s ON s.id = l.shipment_id
+JOIN order_items oi ON oi.id = l.order_item_id
+JOIN catalog_skus cs ON cs.id = oi.sku_id
+WHERE s.order_id = $1
+ORDER BY s.created_at ASC, l.created_at ASC, l.id ASC
+`
+
+type ListShipmentLinesByOrderRow struct {
+	ID          uuid.UUID          `db:"id" json:"id"`
+	ShipmentID  uuid.UUID          `db:"shipment_id" json:"shipment_id"`
+	OrderItemID uuid.UUID          `db:"order_item_id" json:"order_item_id"`
+	Qty         int32              `db:"qty" json:"qty"`
+	CreatedAt   pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	WaybillNo   string             `db:"waybill_no" json:"waybill_no"`
+	SkuID       uuid.UUID          `db:"sku_id" json:"sku_id"`
+	SkuName     string             `db:"sku_name" json:"sku_name"`
+}
+
+func (q *Queries) ListShipmentLinesByOrder(ctx context.Context, orderID uuid.UUID) ([]ListShipmentLinesByOrderRow, error) {
+	rows, err := q.db.Query(ctx, listShipmentLinesByOrder, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListShipmentLinesByOrderRow
+	for rows.Next() {
+		var i ListShipmentLinesByOrderRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ShipmentID,
+			&i.OrderItemID,
+			&i.Qty,
+			&i.CreatedAt,
+			&i.WaybillNo,
+			&i.SkuID,
+			&i.SkuName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listTrackingShipments = `-- name: ListTrackingShipments :many
-SELECT id, order_id, waybill_no, carrier, shipped_at, created_at, updated_at
+SELECT id, order_id, waybill_no, carrier, shipped_at, created_at, updated_at, tracking_status, last_event_at, delivered_at, last_polled_at
 FROM order_tracking_shipments
 WHERE order_id = $1
 ORDER BY created_at ASC
@@ -36,6 +280,49 @@ func (q *Queries) ListTrackingShipments(ctx context.Context, orderID uuid.UUID)
 			&i.ShippedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.TrackingStatus,
+			&i.LastEventAt,
+			&i.DeliveredAt,
+			&i.LastPolledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrackingShipmentsByWaybill = `-- name: ListTrackingShipmentsByWaybill :many
+SELECT id, order_id, waybill_no, carrier, shipped_at, created_at, updated_at, tracking_status, last_event_at, delivered_at, last_polled_at
+FROM order_tracking_shipments
+WHERE waybill_no = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListTrackingShipmentsByWaybill(ctx context.Context, waybillNo string) ([]OrderTrackingShipment, error) {
+	rows, err := q.db.Query(ctx, listTrackingShipmentsByWaybill, waybillNo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OrderTrackingShipment
+	for rows.Next() {
+		var i OrderTrackingShipment
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.WaybillNo,
+			&i.Carrier,
+			&i.ShippedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.TrackingStatus,
+			&i.LastEventAt,
+			&i.DeliveredAt,
+			&i.LastPolledAt,
 		); err != nil {
 			return nil, err
 		}
@@ -47,6 +334,47 @@ func (q *Queries) ListTrackingShipments(ctx context.Context, orderID uuid.UUID)
 	return items, nil
 }
 
+const updateTrackingShipmentStatus = `-- name: UpdateTrackingShipmentStatus :one
+UPDATE order_tracking_shipments
+SET tracking_status = $1,
+    last_event_at = $2,
+    delivered_at = $3,
+    updated_at = now()
+WHERE id = $4
+RETURNING id, order_id, waybill_no, carrier, shipped_at, created_at, updated_at, tracking_status, last_event_at, delivered_at, last_polled_at
+`
+
+type UpdateTrackingShipmentStatusParams struct {
+	TrackingStatus string             `db:"tracking_status" json:"tracking_status"`
+	LastEventAt    pgtype.Timestamptz `db:"last_event_at" json:"last_event_at"`
+	DeliveredAt    pgtype.Timestamptz `db:"delivered_at" json:"delivered_at"`
+	ID             uuid.UUID          `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateTrackingShipmentStatus(ctx context.Context, arg UpdateTrackingShipmentStatusParams) (OrderTrackingShipment, error) {
+	row := q.db.QueryRow(ctx, updateTrackingShipmentStatus,
+		arg.TrackingStatus,
+		arg.LastEventAt,
+		arg.DeliveredAt,
+		arg.ID,
+	)
+	var i OrderTrackingShipment
+	err := row.Scan(
+		&i.ID,
+		&i.OrderID,
+		&i.WaybillNo,
+		&i.Carrier,
+		&i.ShippedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.TrackingStatus,
+		&i.LastEventAt,
+		&i.DeliveredAt,
+		&i.LastPolledAt,
+	)
+	return i, err
+}
+
 const upsertTrackingShipment = `-- name: UpsertTrackingShipment :one
 INSERT INTO order_tracking_shipments (
     order_id,
@@ -63,7 +391,7 @@ ON CONFLICT (order_id, waybill_no)
 DO UPDATE SET carrier = EXCLUDED.carrier,
               shipped_at = EXCLUDED.shipped_at,
               updated_at = now()
-RETURNING id, order_id, waybill_no, carrier, shipped_at, created_at, updated_at
+RETURNING id, order_id, waybill_no, carrier, shipped_at, created_at, updated_at, tracking_status, last_event_at, delivered_at, last_polled_at
 `
 
 type UpsertTrackingShipmentParams struct {
@@ -89,6 +417,10 @@ func (q *Queries) UpsertTrackingShipment(ctx context.Context, arg UpsertTracking
 		&i.ShippedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TrackingStatus,
+		&i.LastEventAt,
+		&i.DeliveredAt,
+		&i.LastPolledAt,
 	)
 	return i, err
 }
@@ -40,6 +40,8 @@ func ShipmentImportTemplate() TemplateSpec {
 			{Key: "waybillno", Header: "Waybill No"},
 			{Key: "carrier", Header: "Carrier"},
 			{Key: "shippedat", Header: "Shipped At"},
+			{Key: "orderitemid", Header: "Order Item ID"},
+			{Key: "qty", Header: "Qty"},
 		},
 		Required: []string{"orderid", "waybillno"},
 	}
@@ -93,6 +95,68 @@ func ProductRequestExportTemplate() TemplateSpec {
 	}
 }
 
+func StatementOrdersTemplate() TemplateSpec {
+	return TemplateSpec{
+		Name:      "statement_orders",
+		SheetName: "订单",
+		Columns: []TemplateColumn{
+			{Key: "orderid", Header: "订单ID"},
+			{Key: "orderno", Header: "订单号"},
+			{Key: "createdat", Header: "下单时间"},
+			{Key: "status", Header: "订单状态"},
+			{Key: "paymentstatus", Header: "支付状态"},
+			{Key: "paymentchannel", Header: "支付渠道"},
+			{Key: "amount", Header: "订单金额(元)"},
+			{Key: "remark", Header: "备注"},
+		},
+	}
+}
+
+func StatementItemsTemplate() TemplateSpec {
+	return TemplateSpec{
+		Name:      "statement_items",
+		SheetName: "商品明细",
+		Columns: []TemplateColumn{
+			{Key: "orderid", Header: "订单ID"},
+			{Key: "skucode", Header: "SKU编码"},
+			{Key: "productname", Header: "商品名称"},
+			{Key: "skuname", Header: "SKU名称"},
+			{Key: "spec", Header: "规格"},
+			{Key: "unit", Header: "单位"},
+			{Key: "qty", Header: "数量"},
+			{Key: "unitprice", Header: "单价(元)"},
+			{Key: "amount", Header: "金额(元)"},
+		},
+	}
+}
+
+func StatementPaymentsTemplate() TemplateSpec {
+	return TemplateSpec{
+		Name:      "statement_payments",
+		SheetName: "收款",
+		Columns: []TemplateColumn{
+			{Key: "orderid", Header: "订单ID"},
+			{Key: "settledat", Header: "收款时间"},
+			{Key: "paymentchannel", Header: "支付渠道"},
+			{Key: "paymentid", Header: "支付单ID"},
+			{Key: "amount", Header: "金额(元)"},
+		},
+	}
+}
+
+func StatementAdjustmentsTemplate() TemplateSpec {
+	return TemplateSpec{
+		Name:      "statement_adjustments",
+		SheetName: "调整",
+		Columns: []TemplateColumn{
+			{Key: "orderid", Header: "订单ID"},
+			{Key: "adjustedat", Header: "调整时间"},
+			{Key: "reason", Header: "原因"},
+			{Key: "amount", Header: "金额(元)"},
+		},
+	}
+}
+
 func TemplateHeaders(spec TemplateSpec) []string {
 	headers := make([]string, 0, len(spec.Columns))
 	for _, column := range spec.Columns {
@@ -12,6 +12,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
 	"github.com/teamdsb/tmo/services/commerce/internal/modules/productimport"
 	"github.com/teamdsb/tmo/services/commerce/internal/modules/productrequestexport"
@@ -23,7 +24,7 @@ type productRequestExportJobRequest struct {
 }
 
 func (h *Handler) PostAdminProductsImportJobs(c *gin.Context) {
-	claims, ok := h.requireRole(c, "BOSS", "ADMIN")
+	claims, ok := h.requireAllScope(c, authz.PermissionImportProduct)
 	if !ok {
 		return
 	}
@@ -90,7 +91,7 @@ func (h *Handler) PostAdminProductsImportJobs(c *gin.Context) {
 }
 
 func (h *Handler) PostAdminProductRequestsExportJobs(c *gin.Context) {
-	claims, ok := h.requireRole(c, "BOSS", "ADMIN")
+	claims, ok := h.requireAllScope(c, authz.PermissionProductRequestExport)
 	if !ok {
 		return
 	}
@@ -130,8 +131,7 @@ func (h *Handler) PostAdminProductRequestsExportJobs(c *gin.Context) {
 }
 
 func (h *Handler) GetAdminImportJobsJobId(c *gin.Context) {
-	_, ok := h.requireRole(c, "BOSS", "ADMIN")
-	if !ok {
+	if _, ok := h.requireUser(c); !ok {
 		return
 	}
 
@@ -151,6 +151,9 @@ func (h *Handler) GetAdminImportJobsJobId(c *gin.Context) {
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch import job")
 		return
 	}
+	if _, ok := h.requireAllScope(c, importJobPermission(job.Type)); !ok {
+		return
+	}
 
 	createdAt := job.CreatedAt.Time
 	c.JSON(http.StatusOK, oapi.ImportJob{
@@ -163,3 +166,12 @@ func (h *Handler) GetAdminImportJobsJobId(c *gin.Context) {
 		CreatedAt:      createdAt,
 	})
 }
+
+// importJobPermission is the permission that lets a caller follow a job of
+// jobType; staff who may import shipments need not be able to import products.
+func importJobPermission(jobType string) string {
+	if jobType == string(oapi.ImportJobTypeSHIPMENTIMPORT) {
+		return authz.PermissionImportShipment
+	}
+	return authz.PermissionImportProduct
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -10,6 +11,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/oapi-codegen/runtime/types"
 
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
 	"github.com/teamdsb/tmo/packages/go-shared/httpx"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/middleware"
 )
@@ -23,7 +25,7 @@ func TestAdminImportEndpointsAllowBossRole(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := httpx.NewRouter()
 	handler := &Handler{
-		Auth: middleware.NewAuthenticator(true, testJWTSecret, testJWTIssuer),
+		Auth: middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer),
 	}
 	router.POST("/admin/products/import-jobs", handler.PostAdminProductsImportJobs)
 	router.POST("/admin/product-requests/export-jobs", handler.PostAdminProductRequestsExportJobs)
@@ -70,7 +72,7 @@ func TestAdminImportEndpointsRejectSalesRole(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := httpx.NewRouter()
 	handler := &Handler{
-		Auth: middleware.NewAuthenticator(true, testJWTSecret, testJWTIssuer),
+		Auth: middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer),
 	}
 	router.POST("/admin/products/import-jobs", handler.PostAdminProductsImportJobs)
 	router.POST("/admin/product-requests/export-jobs", handler.PostAdminProductRequestsExportJobs)
@@ -94,7 +96,7 @@ func TestCatalogWriteEndpointsAllowBossRole(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := httpx.NewRouter()
 	handler := &Handler{
-		Auth: middleware.NewAuthenticator(true, testJWTSecret, testJWTIssuer),
+		Auth: middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer),
 	}
 	router.POST("/catalog/categories", handler.PostCatalogCategories)
 	router.PATCH("/catalog/categories/:categoryId", func(c *gin.Context) {
@@ -139,7 +141,7 @@ func TestCatalogWriteEndpointsRejectSalesRole(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := httpx.NewRouter()
 	handler := &Handler{
-		Auth: middleware.NewAuthenticator(true, testJWTSecret, testJWTIssuer),
+		Auth: middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer),
 	}
 	router.POST("/catalog/categories", handler.PostCatalogCategories)
 	router.PATCH("/catalog/products/:spuId", func(c *gin.Context) {
@@ -166,3 +168,43 @@ func TestCatalogWriteEndpointsRejectSalesRole(t *testing.T) {
 		})
 	}
 }
+
+type staticGrantSource []authz.Grant
+
+func (s staticGrantSource) Grants(context.Context) ([]authz.Grant, error) {
+	return s, nil
+}
+
+func TestCatalogWriteFollowsRefreshedPermissionMatrix(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authorizer := authz.NewAuthorizer(staticGrantSource{
+		{Role: "SALES", Permission: authz.PermissionProductManage, Scope: authz.ScopeAll},
+	})
+	router := httpx.NewRouter()
+	handler := &Handler{
+		Auth: middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer).WithAuthorizer(authorizer),
+	}
+	router.POST("/catalog/categories", handler.PostCatalogCategories)
+
+	request := func(role string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/catalog/categories", bytes.NewReader([]byte(`{}`)))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+makeAuthToken(t, uuid.New(), role, nil))
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	if recorder := request("SALES"); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected SALES to be forbidden before refresh, got %d", recorder.Code)
+	}
+	if err := authorizer.Refresh(context.Background()); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+	if recorder := request("SALES"); recorder.Code == http.StatusForbidden {
+		t.Fatalf("expected granted SALES to pass authorization, got 403: %s", recorder.Body.String())
+	}
+	if recorder := request("BOSS"); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected BOSS without product:manage in the matrix to be forbidden, got %d", recorder.Code)
+	}
+}
@@ -182,7 +182,7 @@ func TestAdminProductImportJobRejectsNonAdminRoles(t *testing.T) {
 func newAuthRouterWithProductImport(pool *pgxpool.Pool, store *db.Queries, mediaLocalDir, mediaBaseURL string) (*gin.Engine, *productimport.Service) {
 	gin.SetMode(gin.TestMode)
 	router := httpx.NewRouter()
-	authenticator := middleware.NewAuthenticator(true, testJWTSecret, testJWTIssuer)
+	authenticator := middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer)
 	productImportService := productimport.NewService(pool, mediaLocalDir, mediaBaseURL, nil)
 	handler := &Handler{
 		CatalogStore:        store,
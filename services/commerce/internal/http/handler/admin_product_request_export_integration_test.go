@@ -157,7 +157,7 @@ func TestAdminProductRequestExportRejectsNonAdminRoles(t *testing.T) {
 func newAuthRouterWithProductRequestExport(pool *pgxpool.Pool, store *db.Queries, mediaLocalDir, mediaBaseURL string) (*gin.Engine, *productrequestexport.Service) {
 	gin.SetMode(gin.TestMode)
 	router := httpx.NewRouter()
-	authenticator := middleware.NewAuthenticator(true, testJWTSecret, testJWTIssuer)
+	authenticator := middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer)
 	exportService := productrequestexport.NewService(pool, mediaLocalDir, mediaBaseURL)
 	handler := &Handler{
 		CatalogStore:         store,
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"strings"
@@ -11,12 +12,15 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/oapi-codegen/runtime/types"
 
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	"github.com/teamdsb/tmo/packages/go-shared/events"
 	"github.com/teamdsb/tmo/services/commerce/internal/db"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
 )
 
 func (h *Handler) GetAfterSalesTickets(c *gin.Context, params oapi.GetAfterSalesTicketsParams) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "SALES", "CS", "MANAGER", "BOSS", "ADMIN")
+	claims, scope, ok := h.requirePermission(c, authz.PermissionAfterSalesRead)
 	if !ok {
 		return
 	}
@@ -34,15 +38,7 @@ func (h *Handler) GetAfterSalesTickets(c *gin.Context, params oapi.GetAfterSales
 	}
 	offset := (page - 1) * pageSize
 
-	createdByFilter := pgtype.UUID{}
-	ownerSalesFilter := pgtype.UUID{}
-	role := strings.ToUpper(claims.Role)
-	switch role {
-	case "CUSTOMER":
-		createdByFilter = pgtype.UUID{Bytes: claims.UserID, Valid: true}
-	case "SALES":
-		ownerSalesFilter = pgtype.UUID{Bytes: claims.UserID, Valid: true}
-	}
+	createdByFilter, ownerSalesFilter := scopeFilters(claims, scope)
 
 	status := (*string)(nil)
 	if params.Status != nil {
@@ -95,7 +91,7 @@ func (h *Handler) GetAfterSalesTickets(c *gin.Context, params oapi.GetAfterSales
 }
 
 func (h *Handler) PostAfterSalesTickets(c *gin.Context) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "BOSS", "MANAGER", "ADMIN")
+	claims, scope, ok := h.requirePermission(c, authz.PermissionAfterSalesCreate)
 	if !ok {
 		return
 	}
@@ -128,7 +124,7 @@ func (h *Handler) PostAfterSalesTickets(c *gin.Context) {
 			h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to create after-sales ticket")
 			return
 		}
-		if strings.EqualFold(claims.Role, "CUSTOMER") && order.CustomerID != claims.UserID {
+		if !scopeCovers(claims, scope, order.CustomerID, order.OwnerSalesUserID) {
 			h.writeError(c, http.StatusNotFound, "not_found", "order not found")
 			return
 		}
@@ -162,7 +158,7 @@ func (h *Handler) PostAfterSalesTickets(c *gin.Context) {
 }
 
 func (h *Handler) GetAfterSalesTicketsTicketId(c *gin.Context, ticketId types.UUID) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "SALES", "CS", "MANAGER", "BOSS", "ADMIN")
+	claims, scope, ok := h.requirePermission(c, authz.PermissionAfterSalesRead)
 	if !ok {
 		return
 	}
@@ -178,7 +174,7 @@ func (h *Handler) GetAfterSalesTicketsTicketId(c *gin.Context, ticketId types.UU
 		return
 	}
 
-	if !canAccessAfterSalesTicket(claims.Role, claims.UserID, ticket) {
+	if !canAccessAfterSalesTicket(scope, claims.UserID, ticket) {
 		h.writeError(c, http.StatusNotFound, "not_found", "after-sales ticket not found")
 		return
 	}
@@ -187,7 +183,7 @@ func (h *Handler) GetAfterSalesTicketsTicketId(c *gin.Context, ticketId types.UU
 }
 
 func (h *Handler) PatchAfterSalesTicketsTicketId(c *gin.Context, ticketId types.UUID) {
-	claims, ok := h.requireRole(c, "SALES", "CS", "MANAGER", "BOSS", "ADMIN")
+	claims, scope, ok := h.requirePermission(c, authz.PermissionAfterSalesManage)
 	if !ok {
 		return
 	}
@@ -217,7 +213,7 @@ func (h *Handler) PatchAfterSalesTicketsTicketId(c *gin.Context, ticketId types.
 		return
 	}
 
-	if strings.EqualFold(claims.Role, "SALES") && !canAccessAfterSalesTicket(claims.Role, claims.UserID, ticket) {
+	if !canAccessAfterSalesTicket(scope, claims.UserID, ticket) {
 		h.writeError(c, http.StatusNotFound, "not_found", "after-sales ticket not found")
 		return
 	}
@@ -233,7 +229,7 @@ func (h *Handler) PatchAfterSalesTicketsTicketId(c *gin.Context, ticketId types.
 		assigned = pgtype.UUID{Bytes: uuid.UUID(*payload.AssignedStaffUserId), Valid: true}
 	}
 
-	updated, err := h.AfterSalesStore.UpdateAfterSalesTicket(c.Request.Context(), db.UpdateAfterSalesTicketParams{
+	updated, err := h.updateAfterSalesTicket(c.Request.Context(), db.UpdateAfterSalesTicketParams{
 		ID:                     uuid.UUID(ticketId),
 		Status:                 status,
 		AssignedStaffUserID:    assigned,
@@ -248,12 +244,33 @@ func (h *Handler) PatchAfterSalesTicketsTicketId(c *gin.Context, ticketId types.
 	c.JSON(http.StatusOK, afterSalesTicketFromModel(updated))
 }
 
+// updateAfterSalesTicket saves the ticket and publishes ticket.updated in the
+// same transaction.
+func (h *Handler) updateAfterSalesTicket(ctx context.Context, update db.UpdateAfterSalesTicketParams) (db.AfterSalesTicket, error) {
+	if h.DB == nil {
+		return h.AfterSalesStore.UpdateAfterSalesTicket(ctx, update)
+	}
+	var updated db.AfterSalesTicket
+	err := shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		var err error
+		updated, err = db.New(tx).UpdateAfterSalesTicket(ctx, update)
+		if err != nil {
+			return err
+		}
+		return h.Outbox.Publish(ctx, tx, events.TypeTicketUpdated, updated.ID.String(), ticketUpdatedEvent(updated))
+	})
+	if err != nil {
+		return db.AfterSalesTicket{}, err
+	}
+	return updated, nil
+}
+
 func (h *Handler) GetAfterSalesTicketsTicketIdMessages(
 	c *gin.Context,
 	ticketId types.UUID,
 	params oapi.GetAfterSalesTicketsTicketIdMessagesParams,
 ) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "SALES", "CS", "MANAGER", "BOSS", "ADMIN")
+	claims, scope, ok := h.requirePermission(c, authz.PermissionAfterSalesRead)
 	if !ok {
 		return
 	}
@@ -269,7 +286,7 @@ func (h *Handler) GetAfterSalesTicketsTicketIdMessages(
 		return
 	}
 
-	if !canAccessAfterSalesTicket(claims.Role, claims.UserID, ticket) {
+	if !canAccessAfterSalesTicket(scope, claims.UserID, ticket) {
 		h.writeError(c, http.StatusNotFound, "not_found", "after-sales ticket not found")
 		return
 	}
@@ -319,7 +336,7 @@ func (h *Handler) GetAfterSalesTicketsTicketIdMessages(
 }
 
 func (h *Handler) PostAfterSalesTicketsTicketIdMessages(c *gin.Context, ticketId types.UUID) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "SALES", "CS", "MANAGER", "BOSS", "ADMIN")
+	claims, scope, ok := h.requirePermission(c, authz.PermissionAfterSalesRead)
 	if !ok {
 		return
 	}
@@ -345,7 +362,7 @@ func (h *Handler) PostAfterSalesTicketsTicketIdMessages(c *gin.Context, ticketId
 		return
 	}
 
-	if !canAccessAfterSalesTicket(claims.Role, claims.UserID, ticket) {
+	if !canAccessAfterSalesTicket(scope, claims.UserID, ticket) {
 		h.writeError(c, http.StatusNotFound, "not_found", "after-sales ticket not found")
 		return
 	}
@@ -405,11 +422,11 @@ func afterSalesMessageFromModel(message db.AfterSalesMessage) oapi.AfterSalesMes
 	return response
 }
 
-func canAccessAfterSalesTicket(role string, userID uuid.UUID, ticket db.AfterSalesTicket) bool {
-	switch strings.ToUpper(role) {
-	case "CUSTOMER":
+func canAccessAfterSalesTicket(scope authz.Scope, userID uuid.UUID, ticket db.AfterSalesTicket) bool {
+	switch scope {
+	case authz.ScopeSelf:
 		return ticket.CreatedByUserID == userID
-	case "SALES":
+	case authz.ScopeOwned:
 		return ticket.OwnerSalesUserID.Valid && ticket.OwnerSalesUserID.Bytes == userID
 	default:
 		return true
@@ -3,7 +3,9 @@ package handler
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/middleware"
 )
 
@@ -14,9 +16,56 @@ func (h *Handler) requireUser(c *gin.Context) (middleware.Claims, bool) {
 	return h.Auth.RequireUser(c)
 }
 
-func (h *Handler) requireRole(c *gin.Context, roles ...string) (middleware.Claims, bool) {
+// optionalUser authenticates the caller on public routes; anonymous callers
+// pass with authenticated false.
+func (h *Handler) optionalUser(c *gin.Context) (claims middleware.Claims, authenticated bool, ok bool) {
+	if h.Auth == nil {
+		return middleware.Claims{}, false, true
+	}
+	return h.Auth.OptionalUser(c)
+}
+
+// requirePermission checks that the caller's role holds permission with any
+// scope and returns that scope; handlers use it to narrow what is visible.
+func (h *Handler) requirePermission(c *gin.Context, permission string) (middleware.Claims, authz.Scope, bool) {
+	if h.Auth == nil {
+		return middleware.Claims{UserID: uuid.Nil, Role: "ADMIN"}, authz.ScopeAll, true
+	}
+	return h.Auth.RequirePermission(c, permission, "")
+}
+
+// requireAllScope checks that the caller's role holds permission with ALL
+// scope. It guards resources that have no per-customer ownership.
+func (h *Handler) requireAllScope(c *gin.Context, permission string) (middleware.Claims, bool) {
 	if h.Auth == nil {
 		return middleware.Claims{UserID: uuid.Nil, Role: "ADMIN"}, true
 	}
-	return h.Auth.RequireRole(c, roles...)
+	claims, _, ok := h.Auth.RequirePermission(c, permission, authz.ScopeAll)
+	return claims, ok
+}
+
+// scopeFilters turns a permission scope into the customer and owner sales
+// filters of list queries: SELF sees the caller's own records and OWNED the
+// records of customers the caller owns.
+func scopeFilters(claims middleware.Claims, scope authz.Scope) (customer pgtype.UUID, owner pgtype.UUID) {
+	switch scope {
+	case authz.ScopeSelf:
+		customer = pgtype.UUID{Bytes: claims.UserID, Valid: true}
+	case authz.ScopeOwned:
+		owner = pgtype.UUID{Bytes: claims.UserID, Valid: true}
+	}
+	return customer, owner
+}
+
+// scopeCovers reports whether a record owned by customerID and ownerSales is
+// visible under scope.
+func scopeCovers(claims middleware.Claims, scope authz.Scope, customerID uuid.UUID, ownerSales pgtype.UUID) bool {
+	switch scope {
+	case authz.ScopeAll:
+		return true
+	case authz.ScopeOwned:
+		return ownerSales.Valid && ownerSales.Bytes == claims.UserID
+	default:
+		return customerID == claims.UserID
+	}
 }
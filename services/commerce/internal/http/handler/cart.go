@@ -15,6 +15,7 @@ import (
 
 	"github.com/teamdsb/tmo/services/commerce/internal/db"
 	"github.com/teamdsb/tmo/services/commerce/internal/excel"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/middleware"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
 )
 
@@ -39,7 +40,7 @@ func (h *Handler) GetCart(c *gin.Context) {
 		return
 	}
 
-	cart, err := h.buildCartResponse(c.Request.Context(), claims.UserID)
+	cart, err := h.buildCartResponse(c.Request.Context(), claims)
 	if err != nil {
 		h.logError("get cart failed", err)
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch cart")
@@ -76,7 +77,7 @@ func (h *Handler) PostCartItems(c *gin.Context) {
 		return
 	}
 
-	cart, err := h.buildCartResponse(c.Request.Context(), claims.UserID)
+	cart, err := h.buildCartResponse(c.Request.Context(), claims)
 	if err != nil {
 		h.logError("get cart failed", err)
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch cart")
@@ -115,7 +116,7 @@ func (h *Handler) PatchCartItemsItemId(c *gin.Context, itemId types.UUID) {
 		return
 	}
 
-	cart, err := h.buildCartResponse(c.Request.Context(), claims.UserID)
+	cart, err := h.buildCartResponse(c.Request.Context(), claims)
 	if err != nil {
 		h.logError("get cart failed", err)
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch cart")
@@ -486,7 +487,7 @@ func (h *Handler) PostCartImportJobsJobIdConfirm(c *gin.Context, jobId types.UUI
 		return
 	}
 
-	cart, err := h.buildCartResponse(c.Request.Context(), claims.UserID)
+	cart, err := h.buildCartResponse(c.Request.Context(), claims)
 	if err != nil {
 		h.logError("get cart failed", err)
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch cart")
@@ -496,8 +497,9 @@ func (h *Handler) PostCartImportJobsJobIdConfirm(c *gin.Context, jobId types.UUI
 	c.JSON(http.StatusOK, cart)
 }
 
-func (h *Handler) buildCartResponse(ctx context.Context, ownerID uuid.UUID) (oapi.Cart, error) {
-	items, err := h.CartStore.ListCartItems(ctx, ownerID)
+// buildCartResponse shows the caller's cart at the prices they pay.
+func (h *Handler) buildCartResponse(ctx context.Context, claims middleware.Claims) (oapi.Cart, error) {
+	items, err := h.CartStore.ListCartItems(ctx, claims.UserID)
 	if err != nil {
 		return oapi.Cart{}, err
 	}
@@ -507,7 +509,7 @@ func (h *Handler) buildCartResponse(ctx context.Context, ownerID uuid.UUID) (oap
 		skuIDs = append(skuIDs, item.SkuID)
 	}
 
-	skuMap, err := h.loadSkusWithTiers(ctx, skuIDs)
+	skuMap, err := h.loadSkusForBuyer(ctx, h.displayBuyer(ctx, claims), skuIDs)
 	if err != nil {
 		return oapi.Cart{}, err
 	}
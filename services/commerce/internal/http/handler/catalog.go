@@ -13,10 +13,13 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/oapi-codegen/runtime/types"
 
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
 	apierrors "github.com/teamdsb/tmo/packages/go-shared/errors"
 	sharedmoney "github.com/teamdsb/tmo/packages/go-shared/money"
 	"github.com/teamdsb/tmo/services/commerce/internal/db"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/inventory"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/pricing"
 )
 
 type catalogCategoriesResponse struct {
@@ -101,7 +104,7 @@ func (h *Handler) GetCatalogCategoriesCategoryId(c *gin.Context, categoryId type
 }
 
 func (h *Handler) PostCatalogCategories(c *gin.Context) {
-	if _, ok := h.requireRole(c, "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionProductManage); !ok {
 		return
 	}
 
@@ -141,7 +144,7 @@ func (h *Handler) PostCatalogCategories(c *gin.Context) {
 }
 
 func (h *Handler) PatchCatalogCategoriesCategoryId(c *gin.Context, categoryId types.UUID) {
-	if _, ok := h.requireRole(c, "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionProductManage); !ok {
 		return
 	}
 
@@ -207,7 +210,7 @@ func (h *Handler) PatchCatalogCategoriesCategoryId(c *gin.Context, categoryId ty
 }
 
 func (h *Handler) DeleteCatalogCategoriesCategoryId(c *gin.Context, categoryId types.UUID) {
-	if _, ok := h.requireRole(c, "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionProductManage); !ok {
 		return
 	}
 
@@ -261,7 +264,7 @@ func (h *Handler) GetCatalogProducts(c *gin.Context, params oapi.GetCatalogProdu
 		statusParam = productStatusPtr(statusFilter)
 	}
 	if statusFilter != productStatusActive {
-		if _, ok := h.requireRole(c, "BOSS", "ADMIN"); !ok {
+		if _, ok := h.requireAllScope(c, authz.PermissionProductManage); !ok {
 			return
 		}
 	}
@@ -304,7 +307,7 @@ func (h *Handler) GetCatalogProducts(c *gin.Context, params oapi.GetCatalogProdu
 }
 
 func (h *Handler) PostCatalogProducts(c *gin.Context) {
-	if _, ok := h.requireRole(c, "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionProductManage); !ok {
 		return
 	}
 
@@ -354,8 +357,9 @@ func (h *Handler) PostCatalogProducts(c *gin.Context) {
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to create product")
 		return
 	}
+	h.reindexProduct(c.Request.Context(), product.ID)
 
-	detail, err := productDetailFromModel(product, nil, nil)
+	detail, err := productDetailFromModel(product, nil, nil, nil)
 	if err != nil {
 		h.logError("map product detail failed", err)
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to create product")
@@ -377,10 +381,18 @@ func (h *Handler) GetCatalogProductsSpuId(c *gin.Context, spuId types.UUID) {
 		return
 	}
 	if product.Status != productStatusActive {
-		if _, ok := h.requireRole(c, "BOSS", "ADMIN"); !ok {
+		if _, ok := h.requireAllScope(c, authz.PermissionProductManage); !ok {
 			return
 		}
 	}
+	claims, authenticated, ok := h.optionalUser(c)
+	if !ok {
+		return
+	}
+	var buyer pricing.Buyer
+	if authenticated {
+		buyer = h.displayBuyer(c.Request.Context(), claims)
+	}
 
 	skus, err := h.CatalogStore.ListSkusByProduct(c.Request.Context(), product.ID)
 	if err != nil {
@@ -403,7 +415,21 @@ func (h *Handler) GetCatalogProductsSpuId(c *gin.Context, spuId types.UUID) {
 		}
 	}
 
-	detail, err := productDetailFromModel(product, skus, priceTiers)
+	prices, err := h.resolvePrices(c.Request.Context(), buyer, skuIDsOf(skus), tiersBySkuID(priceTiers))
+	if err != nil {
+		h.logError("resolve sku prices failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch product")
+		return
+	}
+
+	stock, err := h.loadSkuInventory(c.Request.Context(), skuIDsOf(skus))
+	if err != nil {
+		h.logError("list sku inventory failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch product")
+		return
+	}
+
+	detail, err := productDetailFromModel(product, skus, prices, stock)
 	if err != nil {
 		h.logError("map product detail failed", err)
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch product")
@@ -414,7 +440,7 @@ func (h *Handler) GetCatalogProductsSpuId(c *gin.Context, spuId types.UUID) {
 }
 
 func (h *Handler) PatchCatalogProductsSpuId(c *gin.Context, spuId types.UUID) {
-	if _, ok := h.requireRole(c, "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionProductManage); !ok {
 		return
 	}
 
@@ -528,6 +554,7 @@ func (h *Handler) PatchCatalogProductsSpuId(c *gin.Context, spuId types.UUID) {
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to update product")
 		return
 	}
+	h.reindexProduct(c.Request.Context(), product.ID)
 
 	skus, err := h.CatalogStore.ListSkusByProduct(c.Request.Context(), product.ID)
 	if err != nil {
@@ -550,7 +577,21 @@ func (h *Handler) PatchCatalogProductsSpuId(c *gin.Context, spuId types.UUID) {
 		}
 	}
 
-	detail, err := productDetailFromModel(product, skus, priceTiers)
+	prices, err := h.resolvePrices(c.Request.Context(), pricing.Buyer{}, skuIDsOf(skus), tiersBySkuID(priceTiers))
+	if err != nil {
+		h.logError("resolve sku prices failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to update product")
+		return
+	}
+
+	stock, err := h.loadSkuInventory(c.Request.Context(), skuIDsOf(skus))
+	if err != nil {
+		h.logError("list sku inventory failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to update product")
+		return
+	}
+
+	detail, err := productDetailFromModel(product, skus, prices, stock)
 	if err != nil {
 		h.logError("map product detail failed", err)
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to update product")
@@ -561,7 +602,7 @@ func (h *Handler) PatchCatalogProductsSpuId(c *gin.Context, spuId types.UUID) {
 }
 
 func (h *Handler) DeleteCatalogProductsSpuId(c *gin.Context, spuId types.UUID) {
-	if _, ok := h.requireRole(c, "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionProductManage); !ok {
 		return
 	}
 
@@ -580,7 +621,7 @@ func (h *Handler) DeleteCatalogProductsSpuId(c *gin.Context, spuId types.UUID) {
 }
 
 func (h *Handler) PatchCatalogProductsSpuIdSkusSkuId(c *gin.Context, spuId types.UUID, skuId types.UUID) {
-	if _, ok := h.requireRole(c, "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionProductManage); !ok {
 		return
 	}
 
@@ -634,15 +675,22 @@ func (h *Handler) PatchCatalogProductsSpuIdSkusSkuId(c *gin.Context, spuId types
 	if request.IsActive != nil {
 		isActive = *request.IsActive
 	}
+	weightGrams, volumeCm3, message := skuShippingSize(request.WeightGrams, request.VolumeCm3)
+	if message != "" {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", message)
+		return
+	}
 
 	sku, err := h.CatalogStore.UpdateSku(c.Request.Context(), db.UpdateSkuParams{
-		ID:         uuid.UUID(skuId),
-		SkuCode:    request.SkuCode,
-		Name:       name,
-		Spec:       specPtr,
-		Attributes: attributesJSON,
-		Unit:       request.Unit,
-		IsActive:   isActive,
+		ID:          uuid.UUID(skuId),
+		SkuCode:     request.SkuCode,
+		Name:        name,
+		Spec:        specPtr,
+		Attributes:  attributesJSON,
+		Unit:        request.Unit,
+		IsActive:    isActive,
+		WeightGrams: weightGrams,
+		VolumeCm3:   volumeCm3,
 	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -663,8 +711,16 @@ func (h *Handler) PatchCatalogProductsSpuIdSkusSkuId(c *gin.Context, spuId types
 	if !ok {
 		return
 	}
+	h.reindexProduct(c.Request.Context(), sku.ProductID)
+
+	stock, err := h.loadSkuInventory(c.Request.Context(), []uuid.UUID{sku.ID})
+	if err != nil {
+		h.logError("get sku inventory failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to update sku")
+		return
+	}
 
-	response, err := skuFromModel(sku, tiers)
+	response, err := skuFromModel(sku, tiers, stockFor(stock, sku.ID))
 	if err != nil {
 		h.logError("map sku failed", err)
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to update sku")
@@ -675,7 +731,7 @@ func (h *Handler) PatchCatalogProductsSpuIdSkusSkuId(c *gin.Context, spuId types
 }
 
 func (h *Handler) PostCatalogProductsSpuIdSkus(c *gin.Context, spuId types.UUID) {
-	if _, ok := h.requireRole(c, "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionProductManage); !ok {
 		return
 	}
 
@@ -717,15 +773,22 @@ func (h *Handler) PostCatalogProductsSpuIdSkus(c *gin.Context, spuId types.UUID)
 	if request.IsActive != nil {
 		isActive = *request.IsActive
 	}
+	weightGrams, volumeCm3, message := skuShippingSize(request.WeightGrams, request.VolumeCm3)
+	if message != "" {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", message)
+		return
+	}
 
 	sku, err := h.CatalogStore.CreateSku(c.Request.Context(), db.CreateSkuParams{
-		ProductID:  uuid.UUID(spuId),
-		SkuCode:    request.SkuCode,
-		Name:       request.Name,
-		Spec:       specPtr,
-		Attributes: attributesJSON,
-		Unit:       request.Unit,
-		IsActive:   isActive,
+		ProductID:   uuid.UUID(spuId),
+		SkuCode:     request.SkuCode,
+		Name:        request.Name,
+		Spec:        specPtr,
+		Attributes:  attributesJSON,
+		Unit:        request.Unit,
+		IsActive:    isActive,
+		WeightGrams: weightGrams,
+		VolumeCm3:   volumeCm3,
 	})
 	if err != nil {
 		h.logError("create sku failed", err)
@@ -737,8 +800,9 @@ func (h *Handler) PostCatalogProductsSpuIdSkus(c *gin.Context, spuId types.UUID)
 	if !ok {
 		return
 	}
+	h.reindexProduct(c.Request.Context(), sku.ProductID)
 
-	response, err := skuFromModel(sku, tiers)
+	response, err := skuFromModel(sku, tiers, nil)
 	if err != nil {
 		h.logError("map sku failed", err)
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to create sku")
@@ -826,7 +890,7 @@ func productSummaryFromModel(product db.CatalogProduct) oapi.ProductSummary {
 	return summary
 }
 
-func productDetailFromModel(product db.CatalogProduct, skus []db.CatalogSku, tiers []db.CatalogPriceTier) (oapi.ProductDetail, error) {
+func productDetailFromModel(product db.CatalogProduct, skus []db.CatalogSku, prices map[uuid.UUID]pricing.Price, stock map[uuid.UUID]db.SkuInventory) (oapi.ProductDetail, error) {
 	var detail oapi.ProductDetail
 	if len(product.Images) > 0 {
 		images := make([]string, len(product.Images))
@@ -845,14 +909,9 @@ func productDetailFromModel(product db.CatalogProduct, skus []db.CatalogSku, tie
 	detail.Product.Description = product.Description
 	detail.Product.Status = oapi.ProductStatus(product.Status)
 
-	tiersBySku := map[uuid.UUID][]db.CatalogPriceTier{}
-	for _, tier := range tiers {
-		tiersBySku[tier.SkuID] = append(tiersBySku[tier.SkuID], tier)
-	}
-
 	detail.Skus = make([]oapi.SKU, 0, len(skus))
 	for _, sku := range skus {
-		mapped, err := skuFromModel(sku, tiersBySku[sku.ID])
+		mapped, err := pricedSkuFromModel(sku, prices[sku.ID], stockFor(stock, sku.ID))
 		if err != nil {
 			return oapi.ProductDetail{}, err
 		}
@@ -886,7 +945,7 @@ func productStatusPtr(value string) *string {
 	return &value
 }
 
-func skuFromModel(sku db.CatalogSku, tiers []db.CatalogPriceTier) (oapi.SKU, error) {
+func skuFromModel(sku db.CatalogSku, tiers []db.CatalogPriceTier, stock *db.SkuInventory) (oapi.SKU, error) {
 	response := oapi.SKU{
 		Id:       sku.ID,
 		SpuId:    sku.ProductID,
@@ -912,24 +971,61 @@ func skuFromModel(sku db.CatalogSku, tiers []db.CatalogPriceTier) (oapi.SKU, err
 		}
 	}
 	if len(tiers) > 0 {
-		mapped := make([]oapi.PriceTier, 0, len(tiers))
-		for _, tier := range tiers {
-			unitPrice := sharedmoney.FromInt64(tier.UnitPriceFen)
-			entry := oapi.PriceTier{
-				MinQty:       int(tier.MinQty),
-				UnitPriceFen: unitPrice.Int64(),
-			}
-			if tier.MaxQty != nil {
-				value := int(*tier.MaxQty)
-				entry.MaxQty = &value
-			}
-			mapped = append(mapped, entry)
-		}
+		mapped := priceTiersFromModel(tiers)
 		response.PriceTiers = &mapped
 	}
+	if stock != nil {
+		available := max(int(inventory.Available(*stock)), 0)
+		response.AvailableQty = &available
+	}
+	if sku.WeightGrams != nil {
+		weight := int(*sku.WeightGrams)
+		response.WeightGrams = &weight
+	}
+	if sku.VolumeCm3 != nil {
+		volume := int(*sku.VolumeCm3)
+		response.VolumeCm3 = &volume
+	}
 	return response, nil
 }
 
+// skuShippingSize validates the weight and volume of a SKU request.
+func skuShippingSize(weightGrams, volumeCm3 *int) (*int32, *int32, string) {
+	var weight, volume *int32
+	if weightGrams != nil {
+		if *weightGrams < 0 || *weightGrams > math.MaxInt32 {
+			return nil, nil, "weightGrams must be between 0 and 2147483647"
+		}
+		value := int32(*weightGrams)
+		weight = &value
+	}
+	if volumeCm3 != nil {
+		if *volumeCm3 < 0 || *volumeCm3 > math.MaxInt32 {
+			return nil, nil, "volumeCm3 must be between 0 and 2147483647"
+		}
+		value := int32(*volumeCm3)
+		volume = &value
+	}
+	return weight, volume, ""
+}
+
+func priceTiersFromModel(tiers []db.CatalogPriceTier) []oapi.PriceTier {
+	mapped := make([]oapi.PriceTier, 0, len(tiers))
+	for _, tier := range tiers {
+		unitPrice := sharedmoney.FromInt64(tier.UnitPriceFen)
+		entry := oapi.PriceTier{
+			MinQty:       int(tier.MinQty),
+			UnitPriceFen: unitPrice.Int64(),
+		}
+		if tier.MaxQty != nil {
+			value := int(*tier.MaxQty)
+			entry.MaxQty = &value
+		}
+		mapped = append(mapped, entry)
+	}
+	return mapped
+}
+
 func derefStringSlice(value *[]string) []string {
 	if value == nil {
 		return []string{}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/oapi-codegen/runtime/types"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/search"
+)
+
+const (
+	maxCatalogSearchQueryLength = 100
+	descriptionSnippetWidth     = 80
+)
+
+func (h *Handler) GetCatalogSearch(c *gin.Context, params oapi.GetCatalogSearchParams) {
+	if h.SearchStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "catalog search unavailable")
+		return
+	}
+	q := strings.TrimSpace(params.Q)
+	if q == "" {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "q is required")
+		return
+	}
+	if utf8.RuneCountInString(q) > maxCatalogSearchQueryLength {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "q supports at most 100 characters")
+		return
+	}
+
+	page := 1
+	pageSize := 20
+	if params.Page != nil && *params.Page > 0 {
+		page = *params.Page
+	}
+	if params.PageSize != nil && *params.PageSize > 0 {
+		pageSize = *params.PageSize
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	query := search.Query{
+		Text:            q,
+		Tag:             trimmedOptional(params.Tag),
+		FilterDimension: trimmedOptional(params.FilterDimension),
+		Limit:           clampInt32(pageSize),
+		Offset:          clampInt32((page - 1) * pageSize),
+	}
+	if params.CategoryId != nil {
+		categoryID := uuid.UUID(*params.CategoryId)
+		query.CategoryID = &categoryID
+	}
+
+	result, err := search.Search(c.Request.Context(), h.SearchStore, query)
+	if err != nil {
+		h.logError("search catalog failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to search catalog")
+		return
+	}
+
+	items := make([]oapi.CatalogSearchHit, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		items = append(items, catalogSearchHitFromResult(hit, q))
+	}
+	c.JSON(http.StatusOK, oapi.CatalogSearchResult{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    int(result.Total),
+		Facets:   catalogSearchFacetsFromResult(result),
+	})
+}
+
+// reindexProduct refreshes the search document after a catalog write. A
+// failure only delays search results until the background indexer catches
+// up, so it does not fail the write.
+func (h *Handler) reindexProduct(ctx context.Context, productID uuid.UUID) {
+	if h.SearchStore == nil {
+		return
+	}
+	if err := search.IndexProduct(ctx, h.SearchStore, productID); err != nil {
+		h.logError("index product for search failed", err)
+	}
+}
+
+func catalogSearchHitFromResult(hit search.Hit, q string) oapi.CatalogSearchHit {
+	response := oapi.CatalogSearchHit{
+		Product: productSummaryFromModel(hit.Product),
+		Score:   hit.Score,
+		Highlights: oapi.CatalogSearchHighlights{
+			Name: search.Highlight(hit.Product.Name, q),
+		},
+	}
+	if hit.Product.Description != nil {
+		if snippet, ok := search.Snippet(*hit.Product.Description, q, descriptionSnippetWidth); ok {
+			response.Highlights.Description = &snippet
+		}
+	}
+	if hit.MatchedSku != nil {
+		response.MatchedSku = &oapi.CatalogSearchMatchedSku{
+			Id:        types.UUID(hit.MatchedSku.ID),
+			Name:      hit.MatchedSku.Name,
+			SkuCode:   hit.MatchedSku.SkuCode,
+			Spec:      hit.MatchedSku.Spec,
+			Highlight: search.Highlight(hit.MatchedSku.Name, q),
+		}
+	}
+	return response
+}
+
+func catalogSearchFacetsFromResult(result search.Result) oapi.CatalogSearchFacets {
+	facets := oapi.CatalogSearchFacets{
+		Categories:       make([]oapi.CatalogSearchCategoryFacet, 0, len(result.Categories)),
+		Tags:             catalogSearchFacetValues(result.Tags),
+		FilterDimensions: catalogSearchFacetValues(result.FilterDimensions),
+	}
+	for _, category := range result.Categories {
+		categoryID, err := uuid.Parse(category.Value)
+		if err != nil {
+			continue
+		}
+		facets.Categories = append(facets.Categories, oapi.CatalogSearchCategoryFacet{
+			CategoryId: categoryID,
+			Name:       category.Label,
+			Count:      int(category.Count),
+		})
+	}
+	return facets
+}
+
+func catalogSearchFacetValues(values []search.FacetValue) []oapi.CatalogSearchFacetValue {
+	response := make([]oapi.CatalogSearchFacetValue, 0, len(values))
+	for _, value := range values {
+		response = append(response, oapi.CatalogSearchFacetValue{Value: value.Value, Count: int(value.Count)})
+	}
+	return response
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/search"
+)
+
+func TestGetCatalogSearchMatchesChineseInitialsAndSkuCodes(t *testing.T) {
+	pool := openHandlerTestPool(t)
+	resetCommerceTables(t, pool)
+
+	queries := db.New(pool)
+	ctx := context.Background()
+	category, err := queries.CreateCategory(ctx, db.CreateCategoryParams{Name: "紧固件", ParentID: pgtype.UUID{}, Sort: 1})
+	if err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	description := "304 不锈钢材质，耐腐蚀，适用于户外设备"
+	bolt := seedSearchProduct(t, queries, db.CreateProductParams{
+		Name:             "不锈钢六角螺栓",
+		Description:      &description,
+		CategoryID:       category.ID,
+		Tags:             []string{"热销"},
+		FilterDimensions: []string{"M8"},
+		Status:           "ACTIVE",
+	}, "BOLT-M8-20", "螺栓 M8×20")
+	seedSearchProduct(t, queries, db.CreateProductParams{
+		Name:             "碳钢平垫圈",
+		CategoryID:       category.ID,
+		Tags:             []string{},
+		FilterDimensions: []string{"M8"},
+		Status:           "ACTIVE",
+	}, "WASHER-M8", "垫圈 M8")
+	seedSearchProduct(t, queries, db.CreateProductParams{
+		Name:             "不锈钢螺栓（停售）",
+		CategoryID:       category.ID,
+		Tags:             []string{},
+		FilterDimensions: []string{},
+		Status:           "INACTIVE",
+	}, "BOLT-OLD", "螺栓 旧款")
+
+	router := newIntegrationRouter(pool, queries)
+	for _, q := range []string{"不锈钢", "bxg", "bolt-m8"} {
+		result := getCatalogSearch(t, router, url.Values{"q": {q}})
+		if result.Total != 1 || len(result.Items) != 1 || result.Items[0].Product.Id != bolt.ID {
+			t.Fatalf("expected only the active bolt for %q, got %+v", q, result)
+		}
+	}
+
+	result := getCatalogSearch(t, router, url.Values{"q": {"不锈钢"}})
+	hit := result.Items[0]
+	if hit.Highlights.Name != "<em>不锈钢</em>六角螺栓" {
+		t.Fatalf("unexpected name highlight %q", hit.Highlights.Name)
+	}
+	if hit.Highlights.Description == nil {
+		t.Fatal("expected a description highlight")
+	}
+	if len(result.Facets.Categories) != 1 || result.Facets.Categories[0].Name != "紧固件" || result.Facets.Categories[0].Count != 1 {
+		t.Fatalf("unexpected category facets %+v", result.Facets.Categories)
+	}
+	if len(result.Facets.Tags) != 1 || result.Facets.Tags[0].Value != "热销" {
+		t.Fatalf("unexpected tag facets %+v", result.Facets.Tags)
+	}
+
+	skuHit := getCatalogSearch(t, router, url.Values{"q": {"bolt-m8"}}).Items[0]
+	if skuHit.MatchedSku == nil || skuHit.MatchedSku.SkuCode == nil || *skuHit.MatchedSku.SkuCode != "BOLT-M8-20" {
+		t.Fatalf("expected the SKU to be reported as matched, got %+v", skuHit.MatchedSku)
+	}
+
+	m8 := getCatalogSearch(t, router, url.Values{"q": {"m8"}, "filterDimension": {"M8"}, "tag": {"热销"}})
+	if m8.Total != 1 || m8.Items[0].Product.Id != bolt.ID {
+		t.Fatalf("expected the tag filter to narrow the results, got %+v", m8)
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/catalog/search?q=%20", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected blank query to be rejected, got %d", recorder.Code)
+	}
+}
+
+func seedSearchProduct(t *testing.T, queries *db.Queries, params db.CreateProductParams, skuCode, skuName string) db.CatalogProduct {
+	t.Helper()
+
+	ctx := context.Background()
+	product, err := queries.CreateProduct(ctx, params)
+	if err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	if _, err := queries.CreateSku(ctx, db.CreateSkuParams{
+		ProductID:  product.ID,
+		SkuCode:    stringPtr(skuCode),
+		Name:       skuName,
+		Attributes: json.RawMessage(`{}`),
+		IsActive:   true,
+	}); err != nil {
+		t.Fatalf("create sku: %v", err)
+	}
+	if err := search.IndexProduct(ctx, queries, product.ID); err != nil {
+		t.Fatalf("index product: %v", err)
+	}
+	return product
+}
+
+func getCatalogSearch(t *testing.T, router http.Handler, query url.Values) oapi.CatalogSearchResult {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/catalog/search?"+query.Encode(), nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200 for %v, got %d: %s", query, recorder.Code, recorder.Body.String())
+	}
+	var result oapi.CatalogSearchResult
+	if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+		t.Fatalf("decode search response: %v", err)
+	}
+	return result
+}
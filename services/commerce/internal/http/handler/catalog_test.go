@@ -329,7 +329,7 @@ func TestGetCatalogProductsSpuId_InactiveProductRequiresAdmin(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 	oapi.RegisterHandlers(router, &Handler{
-		Auth:         middleware.NewAuthenticator(true, "secret", ""),
+		Auth:         middleware.NewAuthenticator(true, testJWTKeys, ""),
 		CatalogStore: store,
 	})
 	req := httptest.NewRequest(http.MethodGet, "/catalog/products/"+productID.String(), nil)
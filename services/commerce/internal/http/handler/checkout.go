@@ -0,0 +1,274 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/inventory"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/promotion"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/receivable"
+)
+
+// PostCheckoutPreview prices the selected cart lines the way PostOrders
+// would without storing anything. What PostOrders would reject, including a
+// stock shortage or a monthly-terms order over the credit limit, is reported
+// as blocking warnings instead of an error, so the customer sees every
+// problem at once. Neither stock nor credit is held, so the order can still
+// lose a race for them.
+func (h *Handler) PostCheckoutPreview(c *gin.Context) {
+	claims, ok := h.requireUser(c)
+	if !ok {
+		return
+	}
+
+	var request oapi.CheckoutPreviewRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	if len(request.Items) == 0 {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "items is required")
+		return
+	}
+	var couponCode string
+	if request.CouponCode != nil {
+		couponCode = promotion.NormalizeCode(*request.CouponCode)
+	}
+	if len(couponCode) > maxCouponCodeLength {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "couponCode supports at most 64 characters")
+		return
+	}
+	requestedItems := make([]requestedOrderItem, 0, len(request.Items))
+	for _, item := range request.Items {
+		requestedItems = append(requestedItems, requestedOrderItem{
+			cartItemID: uuid.UUID(item.CartItemId),
+			skuID:      uuid.UUID(item.SkuId),
+			qty:        clampInt32(item.Qty),
+		})
+	}
+	if message := validateRequestedOrderItems(requestedItems); message != "" {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", message)
+		return
+	}
+
+	ctx := c.Request.Context()
+	pricing, err := h.priceCartLines(ctx, claims, requestedItems)
+	if err != nil {
+		if errors.Is(err, errCustomerPricesUnavailable) {
+			h.logError("get customer tags failed", err)
+			h.writeError(c, http.StatusBadGateway, "identity_unavailable", "unable to resolve customer prices")
+			return
+		}
+		h.logError("price cart lines failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to preview checkout")
+		return
+	}
+	cartItems, err := h.CartStore.ListCartItems(ctx, claims.UserID)
+	if err != nil {
+		h.logError("list cart items failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to preview checkout")
+		return
+	}
+	cartByID := make(map[uuid.UUID]db.CartItem, len(cartItems))
+	for _, item := range cartItems {
+		cartByID[item.ID] = item
+	}
+	skuIDs := make([]uuid.UUID, 0, len(pricing.qtyBySku))
+	for skuID := range pricing.qtyBySku {
+		skuIDs = append(skuIDs, skuID)
+	}
+	stock, err := h.loadSkuInventory(ctx, skuIDs)
+	if err != nil {
+		h.logError("list sku inventory failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to preview checkout")
+		return
+	}
+
+	response := oapi.CheckoutPreview{
+		Items:     make([]oapi.CheckoutPreviewLine, 0, len(pricing.lines)),
+		Discounts: []oapi.OrderDiscount{},
+		Warnings:  []oapi.CheckoutWarning{},
+	}
+	nudgedSkus := make(map[uuid.UUID]struct{}, len(pricing.lines))
+	shortSkus := make(map[uuid.UUID]struct{}, len(pricing.lines))
+	for i, priced := range pricing.lines {
+		line := priced.line
+		previewLine := oapi.CheckoutPreviewLine{
+			CartItemId: line.sourceCartItemID,
+			SkuId:      line.sku.ID,
+			Qty:        int(line.qty),
+		}
+		if priced.problem != &cartLineSkuNotFound {
+			sku, err := skuFromModel(line.sku, pricing.tiersBySku[line.sku.ID], stockFor(stock, line.sku.ID))
+			if err != nil {
+				h.logError("map sku failed", err)
+				h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to preview checkout")
+				return
+			}
+			previewLine.Sku = &sku
+		}
+
+		if priced.problem != nil {
+			response.Warnings = append(response.Warnings, lineWarning(oapi.CheckoutWarningCode(priced.problem.code), priced.problem.message, true, line))
+		}
+		cartItem, found := cartByID[line.sourceCartItemID]
+		if message := cartItemMismatch(cartItem, found, line.sku.ID, line.qty); message != "" {
+			response.Warnings = append(response.Warnings, lineWarning(oapi.CARTITEMCHANGED, message, true, line))
+		}
+		if priced.problem != nil {
+			response.Items = append(response.Items, previewLine)
+			continue
+		}
+
+		// Stock is reserved per SKU, so a shortage is reported once on the
+		// SKU's first line.
+		if record, tracked := stock[line.sku.ID]; tracked {
+			qty := pricing.qtyBySku[line.sku.ID]
+			if _, reported := shortSkus[line.sku.ID]; !reported && qty > inventory.Available(record) {
+				shortSkus[line.sku.ID] = struct{}{}
+				available := max(int(inventory.Available(record)), 0)
+				warning := lineWarning(oapi.INSUFFICIENTSTOCK, fmt.Sprintf("requested %d, only %d available", qty, available), true, line)
+				warning.AvailableQty = &available
+				response.Warnings = append(response.Warnings, warning)
+			}
+		}
+
+		unitPriceFen := line.unitPriceFen.Int64()
+		lineTotalFen := unitPriceFen * int64(line.qty)
+		tier := priceTiersFromModel([]db.CatalogPriceTier{priced.tier})[0]
+		previewLine.PriceTier = &tier
+		previewLine.UnitPriceFen = &unitPriceFen
+		previewLine.LineTotalFen = &lineTotalFen
+		response.Items = append(response.Items, previewLine)
+		response.SubtotalFen += lineTotalFen
+
+		if shown := request.Items[i].UnitPriceFen; shown != nil && *shown != unitPriceFen {
+			message := fmt.Sprintf("unit price changed from %d to %d fen", *shown, unitPriceFen)
+			response.Warnings = append(response.Warnings, lineWarning(oapi.PRICECHANGED, message, false, line))
+		}
+		if _, nudged := nudgedSkus[line.sku.ID]; nudged {
+			continue
+		}
+		nudgedSkus[line.sku.ID] = struct{}{}
+		qty := pricing.qtyBySku[line.sku.ID]
+		if next, ok := nearPriceBreak(pricing.tiersBySku[line.sku.ID], priced.tier, qty); ok {
+			additionalQty := int(next.MinQty - qty)
+			nextTierMinQty := int(next.MinQty)
+			nextUnitPriceFen := next.UnitPriceFen
+			warning := lineWarning(oapi.TIERBREAKPOINTNEAR, fmt.Sprintf("order %d more to pay %d fen each", additionalQty, next.UnitPriceFen), false, line)
+			warning.AdditionalQty = &additionalQty
+			warning.NextTierMinQty = &nextTierMinQty
+			warning.NextTierUnitPriceFen = &nextUnitPriceFen
+			response.Warnings = append(response.Warnings, warning)
+		}
+	}
+
+	orderItems := pricing.orderLines()
+	discounts, err := h.resolveOrderDiscounts(ctx, claims.UserID, couponCode, orderItems)
+	if code, _, ok := couponErrorCode(err); ok {
+		response.Warnings = append(response.Warnings, oapi.CheckoutWarning{
+			Code:     oapi.CheckoutWarningCode(strings.ToUpper(code)),
+			Message:  err.Error(),
+			Blocking: true,
+		})
+		discounts, err = h.resolveOrderDiscounts(ctx, claims.UserID, "", orderItems)
+	}
+	if err != nil {
+		h.logError("resolve order discounts failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to preview checkout")
+		return
+	}
+	for _, discount := range discounts {
+		item := oapi.OrderDiscount{
+			PromotionId: discount.Promotion.ID,
+			Label:       discount.Promotion.Name,
+			AmountFen:   discount.AmountFen,
+		}
+		if discount.Coupon != nil {
+			code := discount.Coupon.Code
+			item.CouponCode = &code
+		}
+		response.Discounts = append(response.Discounts, item)
+	}
+	response.DiscountFen = promotion.TotalFen(discounts)
+	response.ShippingFen, err = h.orderShippingFen(ctx, request.Address, orderItems, orderGoodsFen(orderItems, response.DiscountFen))
+	if err != nil {
+		h.logError("compute shipping fee failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to preview checkout")
+		return
+	}
+	response.TotalFen = response.SubtotalFen - response.DiscountFen + response.ShippingFen
+
+	if h.FinanceProfiles != nil && h.ReceivableStore != nil && strings.ToUpper(claims.Role) == "CUSTOMER" {
+		financeProfile, err := h.FinanceProfiles.GetCustomerFinanceProfile(ctx, claims.UserID)
+		if err != nil {
+			h.logError("get customer finance profile failed", err)
+			h.writeError(c, http.StatusBadGateway, "identity_unavailable", "unable to check customer payment terms")
+			return
+		}
+		if financeProfile.IsMonthly() {
+			err := receivable.CheckCredit(ctx, h.ReceivableStore, claims.UserID, financeProfile.CreditLimitFen, response.TotalFen)
+			var creditErr receivable.CreditLimitExceededError
+			if errors.As(err, &creditErr) {
+				response.Warnings = append(response.Warnings, oapi.CheckoutWarning{
+					Code:           oapi.CREDITLIMITEXCEEDED,
+					Message:        fmt.Sprintf("order of %d fen exceeds the credit limit of %d fen with %d fen outstanding", creditErr.RequestedFen, creditErr.LimitFen, creditErr.OutstandingFen),
+					Blocking:       true,
+					CreditLimitFen: &creditErr.LimitFen,
+					OutstandingFen: &creditErr.OutstandingFen,
+				})
+			} else if err != nil {
+				h.logError("check credit limit failed", err)
+				h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to preview checkout")
+				return
+			}
+		}
+	}
+
+	response.Orderable = true
+	for _, warning := range response.Warnings {
+		if warning.Blocking {
+			response.Orderable = false
+			break
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func lineWarning(code oapi.CheckoutWarningCode, message string, blocking bool, line orderLine) oapi.CheckoutWarning {
+	cartItemID := line.sourceCartItemID
+	skuID := line.sku.ID
+	return oapi.CheckoutWarning{
+		Code:       code,
+		Message:    message,
+		Blocking:   blocking,
+		CartItemId: &cartItemID,
+		SkuId:      &skuID,
+	}
+}
+
+// nearPriceBreak finds the first tier above qty that is cheaper than the
+// current one and reports it when qty is within a fifth of reaching it.
+func nearPriceBreak(tiers []db.CatalogPriceTier, current db.CatalogPriceTier, qty int32) (db.CatalogPriceTier, bool) {
+	var next *db.CatalogPriceTier
+	for i := range tiers {
+		tier := tiers[i]
+		if tier.MinQty <= qty || tier.UnitPriceFen >= current.UnitPriceFen {
+			continue
+		}
+		if next == nil || tier.MinQty < next.MinQty {
+			next = &tier
+		}
+	}
+	if next == nil || next.MinQty-qty > max(next.MinQty/5, 1) {
+		return db.CatalogPriceTier{}, false
+	}
+	return *next, true
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/packages/go-shared/httpx"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/middleware"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/inventory"
+)
+
+func TestCheckoutPreviewMatchesOrder(t *testing.T) {
+	pool := openHandlerTestPool(t)
+	resetCommerceTables(t, pool)
+
+	queries := db.New(pool)
+	skuA, skuB := seedCatalog(t, queries)
+	ctx := context.Background()
+	if _, err := queries.CreatePriceTier(ctx, db.CreatePriceTierParams{SkuID: skuA.ID, MinQty: 10, UnitPriceFen: 10000}); err != nil {
+		t.Fatalf("create price tier: %v", err)
+	}
+	customerID := uuid.New()
+	cartA, err := queries.UpsertCartItem(ctx, db.UpsertCartItemParams{OwnerUserID: customerID, SkuID: skuA.ID, Qty: 9})
+	if err != nil {
+		t.Fatalf("seed cart item: %v", err)
+	}
+	cartB, err := queries.UpsertCartItem(ctx, db.UpsertCartItemParams{OwnerUserID: customerID, SkuID: skuB.ID, Qty: 1})
+	if err != nil {
+		t.Fatalf("seed cart item: %v", err)
+	}
+	router := newServerIntegrationRouter(pool, queries, nil)
+	token := makeAuthToken(t, customerID, "CUSTOMER", nil)
+	address := `{"receiverName":"A","receiverPhone":"1","detail":"X"}`
+
+	doJSONRequest(t, router, http.MethodPost, "/checkout/preview", token,
+		fmt.Sprintf(`{"address":%s,"items":[]}`, address), http.StatusBadRequest, nil)
+
+	// 9 of skuA is one short of the cheaper tier; skuB asks for more than
+	// the cart holds at a price the customer no longer pays.
+	var preview oapi.CheckoutPreview
+	doJSONRequest(t, router, http.MethodPost, "/checkout/preview", token,
+		fmt.Sprintf(`{"address":%s,"couponCode":"nope","items":[{"cartItemId":"%s","skuId":"%s","qty":9,"unitPriceFen":12000},{"cartItemId":"%s","skuId":"%s","qty":2,"unitPriceFen":17000}]}`,
+			address, cartA.ID, skuA.ID, cartB.ID, skuB.ID), http.StatusOK, &preview)
+	if preview.Orderable || preview.SubtotalFen != 144000 || preview.TotalFen != 144000 || len(preview.Items) != 2 {
+		t.Fatalf("unexpected preview %+v", preview)
+	}
+	if *preview.Items[0].UnitPriceFen != 12000 || *preview.Items[0].LineTotalFen != 108000 || preview.Items[0].PriceTier.MinQty != 1 {
+		t.Fatalf("unexpected preview line %+v", preview.Items[0])
+	}
+	warnings := map[oapi.CheckoutWarningCode]oapi.CheckoutWarning{}
+	for _, warning := range preview.Warnings {
+		warnings[warning.Code] = warning
+	}
+	if len(warnings) != 4 || !warnings[oapi.CARTITEMCHANGED].Blocking || !warnings[oapi.COUPONINVALID].Blocking || warnings[oapi.PRICECHANGED].Blocking {
+		t.Fatalf("unexpected warnings %+v", preview.Warnings)
+	}
+	if near := warnings[oapi.TIERBREAKPOINTNEAR]; near.Blocking || *near.AdditionalQty != 1 || *near.NextTierUnitPriceFen != 10000 || *near.SkuId != skuA.ID {
+		t.Fatalf("unexpected breakpoint warning %+v", near)
+	}
+
+	body := fmt.Sprintf(`{"address":%s,"items":[{"cartItemId":"%s","skuId":"%s","qty":9},{"cartItemId":"%s","skuId":"%s","qty":1}]}`,
+		address, cartA.ID, skuA.ID, cartB.ID, skuB.ID)
+	doJSONRequest(t, router, http.MethodPost, "/checkout/preview", token, body, http.StatusOK, &preview)
+	if !preview.Orderable || preview.SubtotalFen != 126000 {
+		t.Fatalf("unexpected preview %+v", preview)
+	}
+	cartItems, err := queries.ListCartItems(ctx, customerID)
+	if err != nil || len(cartItems) != 2 {
+		t.Fatalf("expected the preview to leave the cart alone, got %d items, %v", len(cartItems), err)
+	}
+
+	var order oapi.Order
+	doJSONRequest(t, router, http.MethodPost, "/orders", token, body, http.StatusCreated, &order)
+	if *order.SubtotalFen != preview.SubtotalFen || *order.TotalFen != preview.TotalFen {
+		t.Fatalf("order totals %d/%d differ from preview %d/%d", *order.SubtotalFen, *order.TotalFen, preview.SubtotalFen, preview.TotalFen)
+	}
+}
+
+func TestCheckoutPreviewBlocksWhatOrdersReject(t *testing.T) {
+	pool := openHandlerTestPool(t)
+	resetCommerceTables(t, pool)
+
+	queries := db.New(pool)
+	skuA, skuB := seedCatalog(t, queries)
+	ctx := context.Background()
+	if _, _, err := inventory.Adjust(ctx, queries, inventory.Adjustment{SkuID: skuA.ID, Delta: 2, Reason: "RESTOCK"}); err != nil {
+		t.Fatalf("seed stock: %v", err)
+	}
+	customerID := uuid.New()
+	creditLimitFen := int64(40000)
+	gin.SetMode(gin.TestMode)
+	router := httpx.NewRouter()
+	oapi.RegisterHandlers(router, &Handler{
+		CatalogStore:    queries,
+		CartStore:       queries,
+		OrderStore:      queries,
+		InventoryStore:  queries,
+		ReceivableStore: queries,
+		DB:              pool,
+		Auth:            middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer),
+		SalesValidator:  allowSalesValidator{},
+		FinanceProfiles: staticFinanceProfiles{profile: CustomerFinanceProfile{PaymentTermType: "MONTHLY", PaymentTermDays: 30, CreditLimitFen: &creditLimitFen}},
+	})
+	token := makeAuthToken(t, customerID, "CUSTOMER", nil)
+	preview := func(skuID uuid.UUID, qty int32) oapi.CheckoutPreview {
+		t.Helper()
+		cartItem, err := queries.UpsertCartItem(ctx, db.UpsertCartItemParams{OwnerUserID: customerID, SkuID: skuID, Qty: qty})
+		if err != nil {
+			t.Fatalf("seed cart item: %v", err)
+		}
+		var preview oapi.CheckoutPreview
+		doJSONRequest(t, router, http.MethodPost, "/checkout/preview", token,
+			fmt.Sprintf(`{"address":{"receiverName":"A","receiverPhone":"1","detail":"X"},"items":[{"cartItemId":"%s","skuId":"%s","qty":%d}]}`, cartItem.ID, skuID, qty), http.StatusOK, &preview)
+		if err := queries.DeleteCartItem(ctx, db.DeleteCartItemParams{ID: cartItem.ID, OwnerUserID: customerID}); err != nil {
+			t.Fatalf("delete cart item: %v", err)
+		}
+		return preview
+	}
+
+	// Three of skuA are more than the two in stock.
+	short := preview(skuA.ID, 3)
+	if short.Orderable || len(short.Warnings) != 1 || short.Warnings[0].Code != oapi.INSUFFICIENTSTOCK || !short.Warnings[0].Blocking || *short.Warnings[0].AvailableQty != 2 {
+		t.Fatalf("unexpected stock warnings %+v", short)
+	}
+	if ok := preview(skuA.ID, 2); !ok.Orderable {
+		t.Fatalf("expected the stocked quantity to be orderable, got %+v", ok.Warnings)
+	}
+
+	// Three of skuB come to 54000 fen, over the 40000 fen credit limit.
+	over := preview(skuB.ID, 3)
+	if over.Orderable || len(over.Warnings) != 1 || over.Warnings[0].Code != oapi.CREDITLIMITEXCEEDED || *over.Warnings[0].CreditLimitFen != creditLimitFen || *over.Warnings[0].OutstandingFen != 0 {
+		t.Fatalf("unexpected credit warnings %+v", over)
+	}
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+func TestNearPriceBreak(t *testing.T) {
+	tiers := []db.CatalogPriceTier{
+		{MinQty: 1, UnitPriceFen: 12000},
+		{MinQty: 10, UnitPriceFen: 10000},
+		{MinQty: 50, UnitPriceFen: 9000},
+		{MinQty: 60, UnitPriceFen: 9500},
+	}
+
+	cases := []struct {
+		qty     int32
+		current int
+		wantMin int32
+		ok      bool
+	}{
+		{qty: 7, current: 0, ok: false},
+		{qty: 8, current: 0, wantMin: 10, ok: true},
+		{qty: 9, current: 0, wantMin: 10, ok: true},
+		{qty: 40, current: 1, wantMin: 50, ok: true},
+		{qty: 39, current: 1, ok: false},
+		{qty: 55, current: 2, ok: false},
+	}
+	for _, tc := range cases {
+		next, ok := nearPriceBreak(tiers, tiers[tc.current], tc.qty)
+		if ok != tc.ok || (ok && next.MinQty != tc.wantMin) {
+			t.Fatalf("qty %d: nearPriceBreak = %d, %v, want %d, %v", tc.qty, next.MinQty, ok, tc.wantMin, tc.ok)
+		}
+	}
+
+	single := []db.CatalogPriceTier{{MinQty: 2, UnitPriceFen: 500}, {MinQty: 3, UnitPriceFen: 400}}
+	if next, ok := nearPriceBreak(single, single[0], 2); !ok || next.MinQty != 3 {
+		t.Fatalf("expected a one-unit breakpoint to be near, got %d, %v", next.MinQty, ok)
+	}
+}
@@ -5,18 +5,29 @@ import (
 
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/teamdsb/tmo/packages/go-shared/events"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/middleware"
 	"github.com/teamdsb/tmo/services/commerce/internal/modules/address"
 	"github.com/teamdsb/tmo/services/commerce/internal/modules/aftersales"
 	"github.com/teamdsb/tmo/services/commerce/internal/modules/cart"
 	"github.com/teamdsb/tmo/services/commerce/internal/modules/catalog"
 	"github.com/teamdsb/tmo/services/commerce/internal/modules/inquiry"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/inventory"
 	"github.com/teamdsb/tmo/services/commerce/internal/modules/order"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/pricing"
 	"github.com/teamdsb/tmo/services/commerce/internal/modules/productimport"
 	"github.com/teamdsb/tmo/services/commerce/internal/modules/productrequest"
 	"github.com/teamdsb/tmo/services/commerce/internal/modules/productrequestexport"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/promotion"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/quotation"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/receivable"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/search"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/shipmentimport"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/shipping"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/statement"
 	"github.com/teamdsb/tmo/services/commerce/internal/modules/support"
 	"github.com/teamdsb/tmo/services/commerce/internal/modules/tracking"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/webhook"
 	"github.com/teamdsb/tmo/services/commerce/internal/modules/wishlist"
 )
 
@@ -30,15 +41,31 @@ type Handler struct {
 	ProductRequestStore  productrequest.Store
 	AfterSalesStore      aftersales.Store
 	InquiryStore         inquiry.Store
+	QuotationStore       quotation.Store
+	PricingStore         pricing.Store
+	PromotionStore       promotion.Store
+	ShippingStore        shipping.Store
+	InventoryStore       inventory.Store
+	ReceivableStore      receivable.Store
+	StatementStore       statement.Store
 	SupportStore         support.Store
+	WebhookStore         webhook.Store
+	SearchStore          search.Store
 	ProductImport        *productimport.Service
 	ProductRequestExport *productrequestexport.Service
+	ShipmentImport       *shipmentimport.Service
 	SupportHub           *SupportHub
+	Webhooks             *webhook.Dispatcher
+	Carriers             *tracking.Registry
 	MediaLocalOutputDir  string
 	MediaPublicBaseURL   string
 	InternalSyncToken    string
 	DB                   *pgxpool.Pool
+	Outbox               *events.Outbox
 	Auth                 *middleware.Authenticator
 	SalesValidator       SalesAssigneeValidator
+	FinanceProfiles      CustomerFinanceProfileFetcher
+	CustomerTags         CustomerTagFetcher
+	Payments             PaymentCloser
 	Logger               *slog.Logger
 }
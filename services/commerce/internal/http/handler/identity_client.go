@@ -23,16 +23,38 @@ type SalesAssigneeValidator interface {
 	ValidateActiveSales(context.Context, string, uuid.UUID) error
 }
 
+// CustomerFinanceProfile is the part of an identity finance profile commerce
+// needs to post receivables. A nil CreditLimitFen means no limit.
+type CustomerFinanceProfile struct {
+	PaymentTermType string
+	PaymentTermDays int32
+	CreditLimitFen  *int64
+}
+
+// IsMonthly reports whether the customer settles orders on monthly terms.
+func (p CustomerFinanceProfile) IsMonthly() bool {
+	return strings.EqualFold(p.PaymentTermType, "MONTHLY")
+}
+
+type CustomerFinanceProfileFetcher interface {
+	GetCustomerFinanceProfile(context.Context, uuid.UUID) (CustomerFinanceProfile, error)
+}
+
+type CustomerTagFetcher interface {
+	GetCustomerTagIDs(context.Context, uuid.UUID) ([]uuid.UUID, error)
+}
+
 type IdentityClient struct {
 	baseURL string
+	token   string
 	client  *http.Client
 }
 
-func NewIdentityClient(baseURL string, client *http.Client) *IdentityClient {
+func NewIdentityClient(baseURL, token string, client *http.Client) *IdentityClient {
 	if client == nil {
 		client = &http.Client{Timeout: 5 * time.Second}
 	}
-	return &IdentityClient{baseURL: strings.TrimRight(strings.TrimSpace(baseURL), "/"), client: client}
+	return &IdentityClient{baseURL: strings.TrimRight(strings.TrimSpace(baseURL), "/"), token: strings.TrimSpace(token), client: client}
 }
 
 func (c *IdentityClient) ValidateActiveSales(ctx context.Context, authorization string, userID uuid.UUID) error {
@@ -79,3 +101,89 @@ func (c *IdentityClient) ValidateActiveSales(ctx context.Context, authorization
 	}
 	return fmt.Errorf("%w: user does not have SALES role", errSalesAssigneeInvalid)
 }
+
+// GetCustomerFinanceProfile reads the payment term and credit limit of a
+// customer through the identity internal API. A customer unknown to identity
+// has no payment term.
+func (c *IdentityClient) GetCustomerFinanceProfile(ctx context.Context, customerID uuid.UUID) (CustomerFinanceProfile, error) {
+	if c == nil || c.baseURL == "" {
+		return CustomerFinanceProfile{}, fmt.Errorf("%w: base URL is not configured", errIdentityUnavailable)
+	}
+	endpoint, err := url.JoinPath(c.baseURL, "internal", "customers", customerID.String(), "finance-profile")
+	if err != nil {
+		return CustomerFinanceProfile{}, fmt.Errorf("%w: %v", errIdentityUnavailable, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return CustomerFinanceProfile{}, fmt.Errorf("%w: %v", errIdentityUnavailable, err)
+	}
+	if c.token != "" {
+		req.Header.Set("X-Internal-Token", c.token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return CustomerFinanceProfile{}, fmt.Errorf("%w: %v", errIdentityUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return CustomerFinanceProfile{}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return CustomerFinanceProfile{}, fmt.Errorf("%w: identity returned %d", errIdentityUnavailable, resp.StatusCode)
+	}
+	var profile struct {
+		PaymentTerm *struct {
+			Type                  string `json:"type"`
+			MonthlySettlementDays *int32 `json:"monthlySettlementDays"`
+		} `json:"paymentTerm"`
+		CreditLimitFen *int64 `json:"creditLimitFen"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+		return CustomerFinanceProfile{}, fmt.Errorf("%w: invalid response", errIdentityUnavailable)
+	}
+	result := CustomerFinanceProfile{CreditLimitFen: profile.CreditLimitFen}
+	if profile.PaymentTerm != nil {
+		result.PaymentTermType = strings.ToUpper(strings.TrimSpace(profile.PaymentTerm.Type))
+		if profile.PaymentTerm.MonthlySettlementDays != nil {
+			result.PaymentTermDays = *profile.PaymentTerm.MonthlySettlementDays
+		}
+	}
+	return result, nil
+}
+
+// GetCustomerTagIDs lists the active customer tags of a customer through the
+// identity internal API; price lists can be assigned to tags.
+func (c *IdentityClient) GetCustomerTagIDs(ctx context.Context, customerID uuid.UUID) ([]uuid.UUID, error) {
+	if c == nil || c.baseURL == "" {
+		return nil, fmt.Errorf("%w: base URL is not configured", errIdentityUnavailable)
+	}
+	endpoint, err := url.JoinPath(c.baseURL, "internal", "customers", customerID.String(), "tags")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errIdentityUnavailable, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errIdentityUnavailable, err)
+	}
+	if c.token != "" {
+		req.Header.Set("X-Internal-Token", c.token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errIdentityUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("%w: identity returned %d", errIdentityUnavailable, resp.StatusCode)
+	}
+	var body struct {
+		TagIDs []uuid.UUID `json:"tagIds"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: invalid response", errIdentityUnavailable)
+	}
+	return body.TagIDs, nil
+}
@@ -35,7 +35,7 @@ func TestIdentityClientValidateActiveSales(t *testing.T) {
 				_, _ = w.Write([]byte(test.body))
 			}))
 			defer server.Close()
-			err := NewIdentityClient(server.URL, server.Client()).ValidateActiveSales(context.Background(), "Bearer token", userID)
+			err := NewIdentityClient(server.URL, "", server.Client()).ValidateActiveSales(context.Background(), "Bearer token", userID)
 			if test.want == nil && err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
@@ -45,3 +45,80 @@ func TestIdentityClientValidateActiveSales(t *testing.T) {
 		})
 	}
 }
+
+func TestIdentityClientGetCustomerFinanceProfile(t *testing.T) {
+	customerID := uuid.New()
+	creditLimitFen := int64(500000)
+	tests := []struct {
+		name, body string
+		status     int
+		want       CustomerFinanceProfile
+		wantErr    error
+	}{
+		{name: "monthly with limit", status: http.StatusOK, body: `{"paymentTerm":{"type":"MONTHLY","monthlySettlementDays":30},"creditLimitFen":500000}`, want: CustomerFinanceProfile{PaymentTermType: "MONTHLY", PaymentTermDays: 30, CreditLimitFen: &creditLimitFen}},
+		{name: "no payment term", status: http.StatusOK, body: `{"paymentTerm":null,"creditLimitFen":null}`},
+		{name: "missing customer", status: http.StatusNotFound, body: `{}`},
+		{name: "identity error", status: http.StatusUnauthorized, body: `{}`, wantErr: errIdentityUnavailable},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/internal/customers/"+customerID.String()+"/finance-profile" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				if r.Header.Get("X-Internal-Token") != "internal-token" {
+					t.Errorf("internal token not sent")
+				}
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+			got, err := NewIdentityClient(server.URL, "internal-token", server.Client()).GetCustomerFinanceProfile(context.Background(), customerID)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("got %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.PaymentTermType != test.want.PaymentTermType || got.PaymentTermDays != test.want.PaymentTermDays {
+				t.Fatalf("got %+v, want %+v", got, test.want)
+			}
+			if (got.CreditLimitFen == nil) != (test.want.CreditLimitFen == nil) || (got.CreditLimitFen != nil && *got.CreditLimitFen != *test.want.CreditLimitFen) {
+				t.Fatalf("got credit limit %v, want %v", got.CreditLimitFen, test.want.CreditLimitFen)
+			}
+		})
+	}
+}
+
+func TestIdentityClientGetCustomerTagIDs(t *testing.T) {
+	customerID := uuid.New()
+	tagID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/internal/customers/"+customerID.String()+"/tags" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if r.Header.Get("X-Internal-Token") != "internal-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"customerId":"` + customerID.String() + `","tagIds":["` + tagID.String() + `"]}`))
+	}))
+	defer server.Close()
+
+	got, err := NewIdentityClient(server.URL, "internal-token", server.Client()).GetCustomerTagIDs(context.Background(), customerID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0] != tagID {
+		t.Fatalf("got %v, want [%s]", got, tagID)
+	}
+
+	_, err = NewIdentityClient(server.URL, "wrong-token", server.Client()).GetCustomerTagIDs(context.Background(), customerID)
+	if !errors.Is(err, errIdentityUnavailable) {
+		t.Fatalf("got %v, want %v", err, errIdentityUnavailable)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/oapi-codegen/runtime/types"
 
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
 	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
 	"github.com/teamdsb/tmo/services/commerce/internal/db"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/middleware"
@@ -20,7 +21,7 @@ import (
 )
 
 func (h *Handler) GetInquiriesPrice(c *gin.Context, params oapi.GetInquiriesPriceParams) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "SALES", "CS", "MANAGER", "BOSS", "ADMIN")
+	claims, scope, ok := h.requirePermission(c, authz.PermissionInquiryRead)
 	if !ok {
 		return
 	}
@@ -38,15 +39,7 @@ func (h *Handler) GetInquiriesPrice(c *gin.Context, params oapi.GetInquiriesPric
 	}
 	offset := (page - 1) * pageSize
 
-	role := strings.ToUpper(claims.Role)
-	createdByFilter := pgtype.UUID{}
-	ownerSalesFilter := pgtype.UUID{}
-	switch role {
-	case "CUSTOMER":
-		createdByFilter = pgtype.UUID{Bytes: claims.UserID, Valid: true}
-	case "SALES":
-		ownerSalesFilter = pgtype.UUID{Bytes: claims.UserID, Valid: true}
-	}
+	createdByFilter, ownerSalesFilter := scopeFilters(claims, scope)
 
 	var status *string
 	if params.Status != nil {
@@ -92,7 +85,7 @@ func (h *Handler) GetInquiriesPrice(c *gin.Context, params oapi.GetInquiriesPric
 }
 
 func (h *Handler) PostInquiriesPrice(c *gin.Context) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "BOSS", "MANAGER", "ADMIN")
+	claims, scope, ok := h.requirePermission(c, authz.PermissionInquiryCreate)
 	if !ok {
 		return
 	}
@@ -122,7 +115,7 @@ func (h *Handler) PostInquiriesPrice(c *gin.Context) {
 			h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to create inquiry")
 			return
 		}
-		if strings.EqualFold(claims.Role, "CUSTOMER") && order.CustomerID != claims.UserID {
+		if !scopeCovers(claims, scope, order.CustomerID, order.OwnerSalesUserID) {
 			h.writeError(c, http.StatusNotFound, "not_found", "order not found")
 			return
 		}
@@ -180,7 +173,7 @@ func (h *Handler) PostInquiriesPrice(c *gin.Context) {
 }
 
 func (h *Handler) GetInquiriesPriceInquiryId(c *gin.Context, inquiryId types.UUID) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "SALES", "CS", "MANAGER", "BOSS", "ADMIN")
+	claims, scope, ok := h.requirePermission(c, authz.PermissionInquiryRead)
 	if !ok {
 		return
 	}
@@ -196,7 +189,7 @@ func (h *Handler) GetInquiriesPriceInquiryId(c *gin.Context, inquiryId types.UUI
 		return
 	}
 
-	if !canAccessPriceInquiry(claims.Role, claims.UserID, inquiry) {
+	if !canAccessPriceInquiry(scope, claims.UserID, inquiry) {
 		h.writeError(c, http.StatusNotFound, "not_found", "inquiry not found")
 		return
 	}
@@ -205,7 +198,7 @@ func (h *Handler) GetInquiriesPriceInquiryId(c *gin.Context, inquiryId types.UUI
 }
 
 func (h *Handler) PatchInquiriesPriceInquiryId(c *gin.Context, inquiryId types.UUID) {
-	claims, ok := h.requireRole(c, "SALES", "CS", "MANAGER", "BOSS", "ADMIN")
+	claims, scope, ok := h.requirePermission(c, authz.PermissionInquiryManage)
 	if !ok {
 		return
 	}
@@ -236,7 +229,7 @@ func (h *Handler) PatchInquiriesPriceInquiryId(c *gin.Context, inquiryId types.U
 		return
 	}
 
-	if strings.EqualFold(claims.Role, "SALES") && !canAccessPriceInquiry(claims.Role, claims.UserID, inquiry) {
+	if !canAccessPriceInquiry(scope, claims.UserID, inquiry) {
 		h.writeError(c, http.StatusNotFound, "not_found", "inquiry not found")
 		return
 	}
@@ -274,7 +267,7 @@ func (h *Handler) GetInquiriesPriceInquiryIdMessages(
 	inquiryId types.UUID,
 	params oapi.GetInquiriesPriceInquiryIdMessagesParams,
 ) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "SALES", "CS", "MANAGER", "BOSS", "ADMIN")
+	claims, scope, ok := h.requirePermission(c, authz.PermissionInquiryRead)
 	if !ok {
 		return
 	}
@@ -290,7 +283,7 @@ func (h *Handler) GetInquiriesPriceInquiryIdMessages(
 		return
 	}
 
-	if !canAccessPriceInquiry(claims.Role, claims.UserID, inquiry) {
+	if !canAccessPriceInquiry(scope, claims.UserID, inquiry) {
 		h.writeError(c, http.StatusNotFound, "not_found", "inquiry not found")
 		return
 	}
@@ -340,7 +333,7 @@ func (h *Handler) GetInquiriesPriceInquiryIdMessages(
 }
 
 func (h *Handler) PostInquiriesPriceInquiryIdMessages(c *gin.Context, inquiryId types.UUID) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "SALES", "CS", "MANAGER", "BOSS", "ADMIN")
+	claims, scope, ok := h.requirePermission(c, authz.PermissionInquiryRead)
 	if !ok {
 		return
 	}
@@ -366,7 +359,7 @@ func (h *Handler) PostInquiriesPriceInquiryIdMessages(c *gin.Context, inquiryId
 		return
 	}
 
-	if !canAccessPriceInquiry(claims.Role, claims.UserID, inquiry) {
+	if !canAccessPriceInquiry(scope, claims.UserID, inquiry) {
 		h.writeError(c, http.StatusNotFound, "not_found", "inquiry not found")
 		return
 	}
@@ -435,11 +428,11 @@ func inquiryMessageFromModel(message db.InquiryMessage) oapi.InquiryMessage {
 	return response
 }
 
-func canAccessPriceInquiry(role string, userID uuid.UUID, inquiry db.PriceInquiry) bool {
-	switch strings.ToUpper(role) {
-	case "CUSTOMER":
+func canAccessPriceInquiry(scope authz.Scope, userID uuid.UUID, inquiry db.PriceInquiry) bool {
+	switch scope {
+	case authz.ScopeSelf:
 		return inquiry.CreatedByUserID == userID
-	case "SALES":
+	case authz.ScopeOwned:
 		if inquiry.OwnerSalesUserID.Valid && inquiry.OwnerSalesUserID.Bytes == userID {
 			return true
 		}
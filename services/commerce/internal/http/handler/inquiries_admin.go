@@ -9,6 +9,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
 )
 
 type requirementProfileResponse struct {
@@ -26,7 +28,7 @@ type requirementProfileResponse struct {
 }
 
 func (h *Handler) GetAdminInquiriesInquiryIdRequirementProfile(c *gin.Context) {
-	if _, ok := h.requireRole(c, "SALES", "CS", "MANAGER", "BOSS", "ADMIN"); !ok {
+	if _, _, ok := h.requirePermission(c, authz.PermissionInquiryManage); !ok {
 		return
 	}
 
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/teamdsb/tmo/packages/go-shared/httpx"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/middleware"
+)
+
+// newServerIntegrationRouter serves the routes the commerce server registers,
+// backed by the integration database. tags resolves customer tags for price
+// lists and may be nil.
+func newServerIntegrationRouter(pool *pgxpool.Pool, store *db.Queries, tags CustomerTagFetcher) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := httpx.NewRouter()
+	handler := &Handler{
+		CatalogStore:   store,
+		CartStore:      store,
+		OrderStore:     store,
+		InventoryStore: store,
+		PricingStore:   store,
+		PromotionStore: store,
+		ShippingStore:  store,
+		CustomerTags:   tags,
+		DB:             pool,
+		Auth:           middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer),
+		SalesValidator: allowSalesValidator{},
+	}
+	handler.RegisterRoutes(router)
+	return router
+}
+
+// doJSONRequest sends body as JSON with token as the bearer, fails the test
+// unless the response has wantStatus, and decodes the response into out when
+// it is not nil.
+func doJSONRequest(t *testing.T, router *gin.Engine, method, path, token, body string, wantStatus int, out interface{}) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	if recorder.Code != wantStatus {
+		t.Fatalf("%s %s: expected status %d, got %d: %s", method, path, wantStatus, recorder.Code, recorder.Body.String())
+	}
+	if out != nil {
+		if err := json.Unmarshal(recorder.Body.Bytes(), out); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+	}
+}
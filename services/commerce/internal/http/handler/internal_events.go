@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	ordermodule "github.com/teamdsb/tmo/services/commerce/internal/modules/order"
+)
+
+// paymentEventsConsumer names commerce's order payment projection in
+// processed_events.
+const paymentEventsConsumer = "commerce.order-payment"
+
+// PostInternalEvents receives events pushed by the outbox relay of another
+// service. A 2xx response acknowledges the event; anything else makes the
+// relay retry it later, so every event must be safe to apply twice.
+func (h *Handler) PostInternalEvents(c *gin.Context) {
+	if !h.authorizeInternalSync(c) {
+		h.writeError(c, http.StatusUnauthorized, "unauthorized", "invalid internal sync token")
+		return
+	}
+	var event events.Event
+	if err := c.ShouldBindJSON(&event); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	if err := event.Validate(); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	var err error
+	switch event.Type {
+	case events.TypePaymentSucceeded, events.TypePaymentFailed, events.TypePaymentCancelled, events.TypePaymentRefunded:
+		err = h.consumePaymentEvent(c.Request.Context(), event)
+	default:
+		// Unknown types are acknowledged so that a producer can add events
+		// before every subscriber handles them.
+		if h.Logger != nil {
+			h.Logger.Debug("ignoring event", "eventId", event.ID, "type", event.Type)
+		}
+	}
+	if err != nil {
+		h.logError("consume event failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to process event")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// consumePaymentEvent applies a payment status change to its order. The
+// event ID is recorded in the same transaction, so a redelivered event
+// leaves the order untouched.
+func (h *Handler) consumePaymentEvent(ctx context.Context, event events.Event) error {
+	if h.DB == nil {
+		return errors.New("db pool is nil")
+	}
+	var payload events.PaymentStatus
+	if err := event.Decode(&payload); err != nil {
+		h.logEventSkipped(event, err.Error())
+		return nil
+	}
+	orderID, orderErr := uuid.Parse(strings.TrimSpace(payload.OrderID))
+	paymentID, paymentErr := uuid.Parse(strings.TrimSpace(payload.PaymentID))
+	if orderErr != nil || paymentErr != nil {
+		h.logEventSkipped(event, "invalid orderId or paymentId")
+		return nil
+	}
+	paymentStatus := strings.ToUpper(strings.TrimSpace(payload.Status))
+	transition, ok := paymentStatusTransition(paymentStatus)
+	if !ordermodule.IsRefunded(paymentStatus) && !ok {
+		h.logEventSkipped(event, "invalid payment status")
+		return nil
+	}
+
+	err := shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		fresh, err := events.MarkProcessed(ctx, tx, paymentEventsConsumer, event)
+		if err != nil || !fresh {
+			return err
+		}
+		if ordermodule.IsRefunded(paymentStatus) {
+			_, err = h.applyOrderRefundStatus(ctx, tx, orderID, paymentStatus)
+		} else {
+			_, err = h.applyOrderPaymentSummary(ctx, tx, orderID, transition.Event, paymentSummaryUpdate(orderID, transition, paymentStatus, paymentID, payload.Channel, payload.PaidAt))
+		}
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Retrying cannot make an unknown order appear.
+		h.logEventSkipped(event, "order not found")
+		return nil
+	}
+	return err
+}
+
+func (h *Handler) logEventSkipped(event events.Event, reason string) {
+	if h.Logger != nil {
+		h.Logger.Warn("skipping event", "eventId", event.ID, "type", event.Type, "reason", reason)
+	}
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+)
+
+func postInternalEvent(router *gin.Engine, token string, event events.Event) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(event)
+	req := httptest.NewRequest(http.MethodPost, "/internal/events", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Internal-Token", token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestPostInternalEventsRequiresTokenAndAcknowledgesUnknownTypes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := &Handler{InternalSyncToken: "sync-token"}
+	router := gin.New()
+	router.POST("/internal/events", handler.PostInternalEvents)
+
+	event, err := events.New("payment", "payment.created", uuid.NewString(), map[string]string{})
+	if err != nil {
+		t.Fatalf("new event: %v", err)
+	}
+	if rec := postInternalEvent(router, "wrong", event); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+	if rec := postInternalEvent(router, "sync-token", events.Event{Type: events.TypePaymentSucceeded}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an event without id, got %d", rec.Code)
+	}
+	if rec := postInternalEvent(router, "sync-token", event); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 for an unknown type, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestPostInternalEventsAppliesPaymentEventOnce(t *testing.T) {
+	pool := openHandlerTestPool(t)
+	resetCommerceTables(t, pool)
+
+	queries := db.New(pool)
+	ctx := context.Background()
+	address, _ := json.Marshal(oapi.Address{ReceiverName: "A", ReceiverPhone: "1", Detail: "X"})
+	order, err := queries.CreateOrder(ctx, db.CreateOrderParams{
+		Status:           string(oapi.OrderStatusSUBMITTED),
+		CustomerID:       uuid.New(),
+		OwnerSalesUserID: pgtype.UUID{},
+		Address:          address,
+		PaymentStatus:    "UNPAID",
+	})
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+
+	handler := &Handler{OrderStore: queries, DB: pool, InternalSyncToken: "sync-token"}
+	router := gin.New()
+	router.POST("/internal/events", handler.PostInternalEvents)
+
+	paidAt := time.Now().UTC().Truncate(time.Second)
+	paid, err := events.New("payment", events.TypePaymentSucceeded, uuid.NewString(), events.PaymentStatus{
+		PaymentID: uuid.NewString(), OrderID: order.ID.String(), Channel: "WECHAT", Status: "PAID", AmountFen: 100, PaidAt: &paidAt,
+	})
+	if err != nil {
+		t.Fatalf("new event: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if rec := postInternalEvent(router, "sync-token", paid); rec.Code != http.StatusNoContent {
+			t.Fatalf("delivery %d: expected 204, got %d: %s", i+1, rec.Code, rec.Body.String())
+		}
+	}
+	updated, err := queries.GetOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("get order: %v", err)
+	}
+	if updated.PaymentStatus != "PAID" || !updated.PaidAt.Valid {
+		t.Fatalf("expected order to be paid, got %s", updated.PaymentStatus)
+	}
+
+	// A redelivered event must not undo changes made after it was applied.
+	if _, err := queries.UpdateOrderPaymentSummary(ctx, db.UpdateOrderPaymentSummaryParams{
+		ID: order.ID, Status: updated.Status, PaymentStatus: "REFUNDED", LatestPaymentID: updated.LatestPaymentID, PaymentChannel: updated.PaymentChannel, PaidAt: updated.PaidAt,
+	}); err != nil {
+		t.Fatalf("mark refunded: %v", err)
+	}
+	if rec := postInternalEvent(router, "sync-token", paid); rec.Code != http.StatusNoContent {
+		t.Fatalf("redelivery: expected 204, got %d", rec.Code)
+	}
+	again, err := queries.GetOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("get order: %v", err)
+	}
+	if again.PaymentStatus != "REFUNDED" {
+		t.Fatalf("expected redelivered event to be skipped, got %s", again.PaymentStatus)
+	}
+}
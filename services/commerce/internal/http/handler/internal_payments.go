@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"crypto/subtle"
+	"errors"
 	"net/http"
 	"strings"
 	"time"
@@ -14,6 +15,7 @@ import (
 
 	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
 	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	ordermodule "github.com/teamdsb/tmo/services/commerce/internal/modules/order"
 )
 
 type internalOrderPaymentSyncRequest struct {
@@ -48,26 +50,19 @@ func (h *Handler) PostInternalOrdersOrderIdPaymentStatus(c *gin.Context) {
 		return
 	}
 
-	orderStatus, ok := paymentStatusToOrderStatus(request.Status)
-	if !ok {
-		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid payment status")
-		return
-	}
+	paymentStatus := strings.ToUpper(strings.TrimSpace(request.Status))
+	var order db.Order
+	if ordermodule.IsRefunded(paymentStatus) {
+		order, err = h.syncOrderRefundStatus(c.Request.Context(), orderID, paymentStatus)
+	} else {
+		transition, ok := paymentStatusTransition(paymentStatus)
+		if !ok {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid payment status")
+			return
+		}
 
-	latestPaymentID := pgtype.UUID{Bytes: paymentID, Valid: true}
-	paidAt := pgtype.Timestamptz{}
-	if request.PaidAt != nil {
-		paidAt = pgtype.Timestamptz{Time: request.PaidAt.UTC(), Valid: true}
+		order, err = h.syncOrderPaymentSummary(c.Request.Context(), orderID, transition.Event, paymentSummaryUpdate(orderID, transition, paymentStatus, paymentID, request.Channel, request.PaidAt))
 	}
-
-	order, err := h.syncOrderPaymentSummary(c.Request.Context(), orderID, db.UpdateOrderPaymentSummaryParams{
-		ID:              orderID,
-		Status:          orderStatus,
-		PaymentStatus:   strings.ToUpper(strings.TrimSpace(request.Status)),
-		LatestPaymentID: latestPaymentID,
-		PaymentChannel:  normalizeOptionalText(request.Channel),
-		PaidAt:          paidAt,
-	})
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			h.writeError(c, http.StatusNotFound, "not_found", "order not found")
@@ -112,34 +107,89 @@ func (h *Handler) PostInternalOrdersOrderIdPaymentStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-func (h *Handler) syncOrderPaymentSummary(ctx context.Context, orderID uuid.UUID, update db.UpdateOrderPaymentSummaryParams) (db.Order, error) {
+// paymentSummaryUpdate builds the order update for a payment status reported
+// by the payment service.
+func paymentSummaryUpdate(orderID uuid.UUID, transition ordermodule.Transition, paymentStatus string, paymentID uuid.UUID, channel string, paidAt *time.Time) db.UpdateOrderPaymentSummaryParams {
+	update := db.UpdateOrderPaymentSummaryParams{
+		ID:              orderID,
+		Status:          transition.To,
+		PaymentStatus:   paymentStatus,
+		LatestPaymentID: pgtype.UUID{Bytes: paymentID, Valid: true},
+		PaymentChannel:  normalizeOptionalText(channel),
+	}
+	if paidAt != nil {
+		update.PaidAt = pgtype.Timestamptz{Time: paidAt.UTC(), Valid: true}
+	}
+	return update
+}
+
+func (h *Handler) syncOrderPaymentSummary(ctx context.Context, orderID uuid.UUID, event ordermodule.Event, update db.UpdateOrderPaymentSummaryParams) (db.Order, error) {
 	if h.DB == nil {
 		return h.OrderStore.UpdateOrderPaymentSummary(ctx, update)
 	}
 
 	var order db.Order
 	err := shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
-		q := db.New(tx)
+		var err error
+		order, err = h.applyOrderPaymentSummary(ctx, tx, orderID, event, update)
+		return err
+	})
+	if err != nil {
+		return db.Order{}, err
+	}
+	return order, nil
+}
 
-		current, err := q.GetOrderForUpdate(ctx, orderID)
-		if err != nil {
-			return err
-		}
+// applyOrderPaymentSummary moves the order along event, stores the payment
+// summary and publishes the status change in tx.
+func (h *Handler) applyOrderPaymentSummary(ctx context.Context, tx pgx.Tx, orderID uuid.UUID, event ordermodule.Event, update db.UpdateOrderPaymentSummaryParams) (db.Order, error) {
+	q := db.New(tx)
+	current, err := q.GetOrderForUpdate(ctx, orderID)
+	if err != nil {
+		return db.Order{}, err
+	}
 
-		if strings.EqualFold(current.PaymentStatus, "PAID") || strings.EqualFold(current.Status, "PAID") {
-			order = current
-			return nil
+	// Payment callbacks are retried and may arrive out of order, so an
+	// update the state machine rejects is ignored instead of failing.
+	transition, err := ordermodule.Resolve(current.Status, current.PaymentStatus, event)
+	if err != nil {
+		if !errors.Is(err, ordermodule.ErrAlreadyPaid) && !errors.Is(err, ordermodule.ErrInvalidTransition) {
+			return db.Order{}, err
+		}
+		if event == ordermodule.EventPaymentSucceeded && errors.Is(err, ordermodule.ErrInvalidTransition) && h.Logger != nil {
+			h.Logger.Warn("payment succeeded for a closed order", "orderId", current.ID, "status", current.Status, "paymentId", uuid.UUID(update.LatestPaymentID.Bytes))
 		}
+		return current, nil
+	}
+
+	order, err := q.UpdateOrderPaymentSummary(ctx, update)
+	if err != nil {
+		return db.Order{}, err
+	}
+	if err := ordermodule.ApplyReceivableEffects(ctx, q, transition, current.ID, ordermodule.SystemActorID); err != nil {
+		return db.Order{}, err
+	}
+	if err := ordermodule.PublishStatusChanged(ctx, h.Outbox, tx, current, order); err != nil {
+		return db.Order{}, err
+	}
+	return order, nil
+}
 
-		order, err = q.UpdateOrderPaymentSummary(ctx, update)
+// syncOrderRefundStatus records a refund reported by the payment service.
+func (h *Handler) syncOrderRefundStatus(ctx context.Context, orderID uuid.UUID, paymentStatus string) (db.Order, error) {
+	if h.DB == nil {
+		current, err := h.OrderStore.GetOrder(ctx, orderID)
 		if err != nil {
-			return err
-		}
-		if !strings.EqualFold(update.PaymentStatus, "PAID") {
-			return nil
+			return db.Order{}, err
 		}
+		return recordOrderRefund(ctx, h.OrderStore, current, paymentStatus)
+	}
 
-		return nil
+	var order db.Order
+	err := shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		var err error
+		order, err = h.applyOrderRefundStatus(ctx, tx, orderID, paymentStatus)
+		return err
 	})
 	if err != nil {
 		return db.Order{}, err
@@ -147,6 +197,41 @@ func (h *Handler) syncOrderPaymentSummary(ctx context.Context, orderID uuid.UUID
 	return order, nil
 }
 
+// applyOrderRefundStatus locks the order, records the refund and publishes
+// the payment status change in tx.
+func (h *Handler) applyOrderRefundStatus(ctx context.Context, tx pgx.Tx, orderID uuid.UUID, paymentStatus string) (db.Order, error) {
+	q := db.New(tx)
+	current, err := q.GetOrderForUpdate(ctx, orderID)
+	if err != nil {
+		return db.Order{}, err
+	}
+	order, err := recordOrderRefund(ctx, q, current, paymentStatus)
+	if err != nil {
+		return db.Order{}, err
+	}
+	if err := ordermodule.PublishStatusChanged(ctx, h.Outbox, tx, current, order); err != nil {
+		return db.Order{}, err
+	}
+	return order, nil
+}
+
+// recordOrderRefund keeps the order status and payment details; only paid
+// orders are updated so that a late refund notice cannot revive a closed or
+// unpaid order.
+func recordOrderRefund(ctx context.Context, store ordermodule.Store, current db.Order, paymentStatus string) (db.Order, error) {
+	if !ordermodule.IsPaid(current.PaymentStatus) || strings.EqualFold(current.PaymentStatus, paymentStatus) {
+		return current, nil
+	}
+	return store.UpdateOrderPaymentSummary(ctx, db.UpdateOrderPaymentSummaryParams{
+		ID:              current.ID,
+		Status:          current.Status,
+		PaymentStatus:   paymentStatus,
+		LatestPaymentID: current.LatestPaymentID,
+		PaymentChannel:  current.PaymentChannel,
+		PaidAt:          current.PaidAt,
+	})
+}
+
 func (h *Handler) authorizeInternalSync(c *gin.Context) bool {
 	expected := strings.TrimSpace(h.InternalSyncToken)
 	if expected == "" {
@@ -156,17 +241,13 @@ func (h *Handler) authorizeInternalSync(c *gin.Context) bool {
 	return subtle.ConstantTimeCompare([]byte(expected), []byte(provided)) == 1
 }
 
-func paymentStatusToOrderStatus(status string) (string, bool) {
-	switch strings.ToUpper(strings.TrimSpace(status)) {
-	case "PAY_PENDING":
-		return "PAY_PENDING", true
-	case "PAID":
-		return "PAID", true
-	case "PAY_FAILED", "CANCELLED":
-		return "PAY_FAILED", true
-	default:
-		return "", false
+func paymentStatusTransition(status string) (ordermodule.Transition, bool) {
+	event, ok := ordermodule.PaymentEvent(status)
+	if !ok {
+		return ordermodule.Transition{}, false
 	}
+	transition, err := ordermodule.Lookup(event)
+	return transition, err == nil
 }
 
 func normalizeOptionalText(raw string) *string {
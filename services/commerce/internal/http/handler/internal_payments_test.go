@@ -313,6 +313,90 @@ func TestPostInternalOrdersOrderIdPaymentStatusMapsStatuses(t *testing.T) {
 	}
 }
 
+func TestPostInternalOrdersOrderIdPaymentStatusRecordsRefunds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	orderID := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	paymentID := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	channel := "ALIPAY"
+	paidAt := pgtype.Timestamptz{Time: time.Date(2026, 3, 6, 8, 0, 0, 0, time.UTC), Valid: true}
+
+	cases := []struct {
+		name          string
+		orderStatus   string
+		paymentStatus string
+		inputStatus   string
+		expectUpdate  bool
+	}{
+		{name: "partial refund keeps order status", orderStatus: "SHIPPED", paymentStatus: "PAID", inputStatus: "PARTIALLY_REFUNDED", expectUpdate: true},
+		{name: "full refund after partial refund", orderStatus: "CONFIRMED", paymentStatus: "PARTIALLY_REFUNDED", inputStatus: "REFUNDED", expectUpdate: true},
+		{name: "unpaid order ignores refunds", orderStatus: "CLOSED", paymentStatus: "PAY_FAILED", inputStatus: "REFUNDED"},
+		{name: "repeated refund status is ignored", orderStatus: "PAID", paymentStatus: "REFUNDED", inputStatus: "REFUNDED"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			current := db.Order{
+				ID:              orderID,
+				Status:          tc.orderStatus,
+				PaymentStatus:   tc.paymentStatus,
+				LatestPaymentID: pgtype.UUID{Bytes: paymentID, Valid: true},
+				PaymentChannel:  &channel,
+				PaidAt:          paidAt,
+				CreatedAt:       paidAt,
+				UpdatedAt:       paidAt,
+			}
+			updated := false
+			orderStore := &internalPaymentOrderStoreStub{
+				getFn: func(context.Context, uuid.UUID) (db.Order, error) {
+					return current, nil
+				},
+				updateFn: func(_ context.Context, arg db.UpdateOrderPaymentSummaryParams) (db.Order, error) {
+					updated = true
+					next := current
+					next.Status = arg.Status
+					next.PaymentStatus = arg.PaymentStatus
+					return next, nil
+				},
+				listItemsFn: func(context.Context, uuid.UUID) ([]db.OrderItem, error) {
+					return nil, nil
+				},
+			}
+			handler := &Handler{
+				OrderStore:        orderStore,
+				CatalogStore:      &stubStore{},
+				InternalSyncToken: "sync-token",
+			}
+			router := gin.New()
+			router.POST("/internal/orders/:orderId/payment-status", handler.PostInternalOrdersOrderIdPaymentStatus)
+
+			body := `{"paymentId":"` + uuid.NewString() + `","channel":"WECHAT","status":"` + tc.inputStatus + `"}`
+			req := httptest.NewRequest(http.MethodPost, "/internal/orders/"+orderID.String()+"/payment-status", bytes.NewReader([]byte(body)))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Internal-Token", "sync-token")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			if updated != tc.expectUpdate {
+				t.Fatalf("expected update %v, got %v", tc.expectUpdate, updated)
+			}
+			if !tc.expectUpdate {
+				return
+			}
+			last := orderStore.lastUpdate
+			if last.Status != tc.orderStatus || last.PaymentStatus != tc.inputStatus {
+				t.Fatalf("expected %s/%s, got %s/%s", tc.orderStatus, tc.inputStatus, last.Status, last.PaymentStatus)
+			}
+			if uuid.UUID(last.LatestPaymentID.Bytes) != paymentID || last.PaymentChannel == nil || *last.PaymentChannel != channel || last.PaidAt != paidAt {
+				t.Fatalf("expected payment details to be kept, got %+v", last)
+			}
+		})
+	}
+}
+
 func TestPostInternalOrdersOrderIdPaymentStatusRejectsUnauthorizedRequests(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -389,6 +473,7 @@ func TestPostInternalOrdersOrderIdPaymentStatusRejectsInvalidIdentifiers(t *test
 }
 
 type internalPaymentOrderStoreStub struct {
+	getFn       func(context.Context, uuid.UUID) (db.Order, error)
 	updateFn    func(context.Context, db.UpdateOrderPaymentSummaryParams) (db.Order, error)
 	listItemsFn func(context.Context, uuid.UUID) ([]db.OrderItem, error)
 	lastUpdate  db.UpdateOrderPaymentSummaryParams
@@ -414,8 +499,11 @@ func (s *internalPaymentOrderStoreStub) ListOrderStatusStats(context.Context, db
 	return nil, pgx.ErrTxClosed
 }
 
-func (s *internalPaymentOrderStoreStub) GetOrder(context.Context, uuid.UUID) (db.Order, error) {
-	return db.Order{}, pgx.ErrTxClosed
+func (s *internalPaymentOrderStoreStub) GetOrder(ctx context.Context, id uuid.UUID) (db.Order, error) {
+	if s.getFn == nil {
+		return db.Order{}, pgx.ErrTxClosed
+	}
+	return s.getFn(ctx, id)
 }
 
 func (s *internalPaymentOrderStoreStub) GetOrderForUpdate(context.Context, uuid.UUID) (db.Order, error) {
@@ -0,0 +1,353 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/inventory"
+)
+
+const adminInventoryMovementLimit = 50
+
+type adminSkuInventory struct {
+	SkuID             string    `json:"skuId"`
+	OnHand            int       `json:"onHand"`
+	Reserved          int       `json:"reserved"`
+	Available         int       `json:"available"`
+	LowStockThreshold int       `json:"lowStockThreshold"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+type adminInventoryMovement struct {
+	ID            string    `json:"id"`
+	SkuID         string    `json:"skuId"`
+	Type          string    `json:"type"`
+	OnHandDelta   int       `json:"onHandDelta"`
+	ReservedDelta int       `json:"reservedDelta"`
+	OnHandAfter   int       `json:"onHandAfter"`
+	ReservedAfter int       `json:"reservedAfter"`
+	OrderID       *string   `json:"orderId,omitempty"`
+	ActorUserID   *string   `json:"actorUserId,omitempty"`
+	Reason        *string   `json:"reason,omitempty"`
+	Note          *string   `json:"note,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+type adminSkuInventoryDetail struct {
+	Tracked   bool                     `json:"tracked"`
+	Inventory *adminSkuInventory       `json:"inventory,omitempty"`
+	Movements []adminInventoryMovement `json:"movements"`
+}
+
+type adminInventoryAdjustmentRequest struct {
+	Delta  int     `json:"delta"`
+	Reason string  `json:"reason"`
+	Note   *string `json:"note"`
+}
+
+type adminInventoryAdjustmentResponse struct {
+	Inventory adminSkuInventory      `json:"inventory"`
+	Movement  adminInventoryMovement `json:"movement"`
+}
+
+type adminInventorySettingsRequest struct {
+	LowStockThreshold *int `json:"lowStockThreshold"`
+}
+
+type adminLowStockItem struct {
+	adminSkuInventory
+	SpuID    string  `json:"spuId"`
+	SkuCode  *string `json:"skuCode,omitempty"`
+	Name     string  `json:"name"`
+	Spec     *string `json:"spec,omitempty"`
+	IsActive bool    `json:"isActive"`
+}
+
+type adminLowStockListResponse struct {
+	Items    []adminLowStockItem `json:"items"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"pageSize"`
+	Total    int64               `json:"total"`
+}
+
+func (h *Handler) GetAdminInventoryLowStock(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionInventoryRead); !ok {
+		return
+	}
+	if h.InventoryStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "inventory is not configured")
+		return
+	}
+
+	page := parseAdminPositiveInt(c.Query("page"), 1)
+	pageSize := parseAdminPositiveInt(c.Query("pageSize"), 20)
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	offset := (page - 1) * pageSize
+
+	rows, err := h.InventoryStore.ListLowStockSkuInventory(c.Request.Context(), db.ListLowStockSkuInventoryParams{
+		Offset: clampInt32(offset),
+		Limit:  clampInt32(pageSize),
+	})
+	if err != nil {
+		h.logError("list low stock inventory failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list low stock skus")
+		return
+	}
+	total, err := h.InventoryStore.CountLowStockSkuInventory(c.Request.Context())
+	if err != nil {
+		h.logError("count low stock inventory failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list low stock skus")
+		return
+	}
+
+	items := make([]adminLowStockItem, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, adminLowStockItem{
+			adminSkuInventory: adminSkuInventoryFromModel(db.SkuInventory{
+				SkuID:             row.SkuID,
+				OnHand:            row.OnHand,
+				Reserved:          row.Reserved,
+				LowStockThreshold: row.LowStockThreshold,
+				UpdatedAt:         row.UpdatedAt,
+			}),
+			SpuID:    row.ProductID.String(),
+			SkuCode:  row.SkuCode,
+			Name:     row.Name,
+			Spec:     row.Spec,
+			IsActive: row.IsActive,
+		})
+	}
+
+	c.JSON(http.StatusOK, adminLowStockListResponse{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	})
+}
+
+func (h *Handler) GetAdminInventorySkusSkuId(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionInventoryRead); !ok {
+		return
+	}
+	if h.InventoryStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "inventory is not configured")
+		return
+	}
+	skuID, ok := h.parseInventorySkuID(c)
+	if !ok {
+		return
+	}
+
+	response := adminSkuInventoryDetail{Movements: []adminInventoryMovement{}}
+	record, err := h.InventoryStore.GetSkuInventory(c.Request.Context(), skuID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusOK, response)
+			return
+		}
+		h.logError("get sku inventory failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch sku inventory")
+		return
+	}
+	movements, err := h.InventoryStore.ListSkuInventoryMovements(c.Request.Context(), db.ListSkuInventoryMovementsParams{
+		SkuID: skuID,
+		Limit: adminInventoryMovementLimit,
+	})
+	if err != nil {
+		h.logError("list sku inventory movements failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch sku inventory")
+		return
+	}
+
+	mapped := adminSkuInventoryFromModel(record)
+	response.Tracked = true
+	response.Inventory = &mapped
+	for _, movement := range movements {
+		response.Movements = append(response.Movements, adminInventoryMovementFromModel(movement))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) PostAdminInventorySkusSkuIdAdjustments(c *gin.Context) {
+	claims, ok := h.requireAllScope(c, authz.PermissionInventoryManage)
+	if !ok {
+		return
+	}
+	skuID, ok := h.parseInventorySkuID(c)
+	if !ok {
+		return
+	}
+	var request adminInventoryAdjustmentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	if request.Delta == 0 {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", inventory.ErrZeroAdjustment.Error())
+		return
+	}
+	if _, ok := inventory.NormalizeAdjustmentReason(request.Reason); !ok {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", inventory.ErrInvalidReason.Error())
+		return
+	}
+	if h.DB == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to adjust inventory")
+		return
+	}
+
+	ctx := c.Request.Context()
+	var record db.SkuInventory
+	var movement db.SkuInventoryMovement
+	err := shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		q := db.New(tx)
+		skus, err := q.ListSkusByIDs(ctx, []uuid.UUID{skuID})
+		if err != nil {
+			return err
+		}
+		if len(skus) == 0 {
+			return pgx.ErrNoRows
+		}
+		record, movement, err = inventory.Adjust(ctx, q, inventory.Adjustment{
+			SkuID:       skuID,
+			Delta:       clampInt32(request.Delta),
+			Reason:      request.Reason,
+			Note:        trimmedOptional(request.Note),
+			ActorUserID: pgtype.UUID{Bytes: claims.UserID, Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			h.writeError(c, http.StatusNotFound, "not_found", "sku not found")
+		case errors.Is(err, inventory.ErrNegativeOnHand), errors.Is(err, inventory.ErrOnHandBelowReserved):
+			h.writeError(c, http.StatusConflict, "invalid_inventory_state", err.Error())
+		default:
+			h.logError("adjust sku inventory failed", err)
+			h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to adjust inventory")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, adminInventoryAdjustmentResponse{
+		Inventory: adminSkuInventoryFromModel(record),
+		Movement:  adminInventoryMovementFromModel(movement),
+	})
+}
+
+func (h *Handler) PatchAdminInventorySkusSkuId(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionInventoryManage); !ok {
+		return
+	}
+	skuID, ok := h.parseInventorySkuID(c)
+	if !ok {
+		return
+	}
+	var request adminInventorySettingsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	if request.LowStockThreshold == nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "lowStockThreshold is required")
+		return
+	}
+	if *request.LowStockThreshold < 0 {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", inventory.ErrNegativeLowThreshold.Error())
+		return
+	}
+	if h.DB == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to update inventory")
+		return
+	}
+
+	ctx := c.Request.Context()
+	var record db.SkuInventory
+	err := shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		q := db.New(tx)
+		skus, err := q.ListSkusByIDs(ctx, []uuid.UUID{skuID})
+		if err != nil {
+			return err
+		}
+		if len(skus) == 0 {
+			return pgx.ErrNoRows
+		}
+		record, err = inventory.SetLowStockThreshold(ctx, q, skuID, clampInt32(*request.LowStockThreshold))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusNotFound, "not_found", "sku not found")
+			return
+		}
+		h.logError("update sku inventory failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to update inventory")
+		return
+	}
+
+	c.JSON(http.StatusOK, adminSkuInventoryFromModel(record))
+}
+
+func (h *Handler) parseInventorySkuID(c *gin.Context) (uuid.UUID, bool) {
+	skuID, err := uuid.Parse(strings.TrimSpace(c.Param("skuId")))
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid skuId")
+		return uuid.Nil, false
+	}
+	return skuID, true
+}
+
+func adminSkuInventoryFromModel(record db.SkuInventory) adminSkuInventory {
+	return adminSkuInventory{
+		SkuID:             record.SkuID.String(),
+		OnHand:            int(record.OnHand),
+		Reserved:          int(record.Reserved),
+		Available:         int(inventory.Available(record)),
+		LowStockThreshold: int(record.LowStockThreshold),
+		UpdatedAt:         record.UpdatedAt.Time,
+	}
+}
+
+func adminInventoryMovementFromModel(movement db.SkuInventoryMovement) adminInventoryMovement {
+	result := adminInventoryMovement{
+		ID:            movement.ID.String(),
+		SkuID:         movement.SkuID.String(),
+		Type:          movement.MovementType,
+		OnHandDelta:   int(movement.OnHandDelta),
+		ReservedDelta: int(movement.ReservedDelta),
+		OnHandAfter:   int(movement.OnHandAfter),
+		ReservedAfter: int(movement.ReservedAfter),
+		Reason:        movement.Reason,
+		Note:          movement.Note,
+		CreatedAt:     movement.CreatedAt.Time,
+	}
+	if movement.OrderID.Valid {
+		orderID := uuid.UUID(movement.OrderID.Bytes).String()
+		result.OrderID = &orderID
+	}
+	if movement.ActorUserID.Valid {
+		actorID := uuid.UUID(movement.ActorUserID.Bytes).String()
+		result.ActorUserID = &actorID
+	}
+	return result
+}
+
+func trimmedOptional(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	return normalizeOptionalText(*value)
+}
@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
 )
 
 type miniappDisplayCategory struct {
@@ -82,7 +84,7 @@ func (h *Handler) GetCatalogDisplayCategories(c *gin.Context) {
 }
 
 func (h *Handler) GetAdminMiniappDisplayCategories(c *gin.Context) {
-	if _, ok := h.requireRole(c, "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionProductManage); !ok {
 		return
 	}
 
@@ -97,7 +99,7 @@ func (h *Handler) GetAdminMiniappDisplayCategories(c *gin.Context) {
 }
 
 func (h *Handler) PutAdminMiniappDisplayCategories(c *gin.Context) {
-	if _, ok := h.requireRole(c, "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionProductManage); !ok {
 		return
 	}
 
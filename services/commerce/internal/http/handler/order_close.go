@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/oapi-codegen/runtime/types"
+
+	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	ordermodule "github.com/teamdsb/tmo/services/commerce/internal/modules/order"
+)
+
+const (
+	orderActionCustomerCancel = "CUSTOMER_CANCEL"
+	orderActionAdminClose     = "ADMIN_CLOSE"
+)
+
+var (
+	errInvalidCloseTransition = errors.New("order can only be cancelled or closed before payment")
+	errOrderAlreadyPaid       = errors.New("order is already paid")
+)
+
+// orderCloseCommand describes a terminal transition of an unpaid order. When
+// customerID is set the order must belong to that customer.
+type orderCloseCommand struct {
+	orderID        uuid.UUID
+	customerID     uuid.UUID
+	actorUserID    uuid.UUID
+	idempotencyKey string
+	event          ordermodule.Event
+	action         string
+	reasonCode     string
+	note           string
+}
+
+func resolveOrderClose(order db.Order, event ordermodule.Event) (ordermodule.Transition, error) {
+	transition, err := ordermodule.Resolve(order.Status, order.PaymentStatus, event)
+	return transition, orderCloseError(err)
+}
+
+func orderCloseError(err error) error {
+	switch {
+	case errors.Is(err, ordermodule.ErrAlreadyPaid):
+		return errOrderAlreadyPaid
+	case errors.Is(err, ordermodule.ErrInvalidTransition):
+		return errInvalidCloseTransition
+	}
+	return err
+}
+
+func isCancelReasonCode(code oapi.OrderCancelReasonCode) bool {
+	switch code {
+	case oapi.OrderCancelReasonCodeCHANGEDMIND, oapi.OrderCancelReasonCodeDUPLICATEORDER, oapi.OrderCancelReasonCodeWRONGITEMS,
+		oapi.OrderCancelReasonCodePRICECHANGED, oapi.OrderCancelReasonCodeOTHER:
+		return true
+	}
+	return false
+}
+
+func isCloseReasonCode(code oapi.OrderCloseReasonCode) bool {
+	switch code {
+	case oapi.OrderCloseReasonCodeCUSTOMERREQUEST, oapi.OrderCloseReasonCodeOUTOFSTOCK, oapi.OrderCloseReasonCodePAYMENTTIMEOUT,
+		oapi.OrderCloseReasonCodeSUSPECTEDFRAUD, oapi.OrderCloseReasonCodeDUPLICATEORDER, oapi.OrderCloseReasonCodeOTHER:
+		return true
+	}
+	return false
+}
+
+func (h *Handler) PostOrdersOrderIdCancel(c *gin.Context, orderID types.UUID, params oapi.PostOrdersOrderIdCancelParams) {
+	claims, _, ok := h.requirePermission(c, ordermodule.Permission(ordermodule.EventCustomerCancelled))
+	if !ok {
+		return
+	}
+	var request oapi.CancelOrderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	request.ReasonCode = oapi.OrderCancelReasonCode(strings.ToUpper(strings.TrimSpace(string(request.ReasonCode))))
+	if !isCancelReasonCode(request.ReasonCode) {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid reasonCode")
+		return
+	}
+	note := strings.TrimSpace(optionalString(request.Note))
+	if len([]rune(note)) > 1000 {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "note must be at most 1000 characters")
+		return
+	}
+	if note == "" {
+		note = string(request.ReasonCode)
+	}
+	key := orderActionCustomerCancel
+	if params.IdempotencyKey != nil && strings.TrimSpace(*params.IdempotencyKey) != "" {
+		key = strings.TrimSpace(*params.IdempotencyKey)
+	}
+
+	h.respondClosedOrder(c, orderCloseCommand{
+		orderID:        uuid.UUID(orderID),
+		customerID:     claims.UserID,
+		actorUserID:    claims.UserID,
+		idempotencyKey: key,
+		event:          ordermodule.EventCustomerCancelled,
+		action:         orderActionCustomerCancel,
+		reasonCode:     string(request.ReasonCode),
+		note:           note,
+	})
+}
+
+func (h *Handler) PostAdminOrdersOrderIdClose(c *gin.Context, orderID types.UUID, params oapi.PostAdminOrdersOrderIdCloseParams) {
+	claims, ok := h.requireAllScope(c, ordermodule.Permission(ordermodule.EventAdminClosed))
+	if !ok {
+		return
+	}
+	var request oapi.CloseOrderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	request.ReasonCode = oapi.OrderCloseReasonCode(strings.ToUpper(strings.TrimSpace(string(request.ReasonCode))))
+	if !isCloseReasonCode(request.ReasonCode) {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid reasonCode")
+		return
+	}
+	note := strings.TrimSpace(request.Note)
+	switch {
+	case note == "":
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "note is required")
+		return
+	case len([]rune(note)) > 1000:
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "note must be at most 1000 characters")
+		return
+	}
+	key := strings.TrimSpace(params.IdempotencyKey)
+	if key == "" {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "Idempotency-Key is required")
+		return
+	}
+
+	h.respondClosedOrder(c, orderCloseCommand{
+		orderID:        uuid.UUID(orderID),
+		actorUserID:    claims.UserID,
+		idempotencyKey: key,
+		event:          ordermodule.EventAdminClosed,
+		action:         orderActionAdminClose,
+		reasonCode:     string(request.ReasonCode),
+		note:           note,
+	})
+}
+
+func (h *Handler) respondClosedOrder(c *gin.Context, command orderCloseCommand) {
+	if h.DB == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to close order")
+		return
+	}
+	ctx := c.Request.Context()
+	updated, err := h.closeOrder(ctx, command)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			h.writeError(c, http.StatusNotFound, "not_found", "order not found")
+		case errors.Is(err, errInvalidCloseTransition):
+			h.writeError(c, http.StatusConflict, "invalid_order_state", err.Error())
+		case errors.Is(err, errOrderAlreadyPaid), errors.Is(err, errPaymentAlreadyPaid):
+			h.writeError(c, http.StatusConflict, "invalid_order_state", errOrderAlreadyPaid.Error())
+		case errors.Is(err, errPaymentUnavailable):
+			h.logError("close order payments failed", err)
+			h.writeError(c, http.StatusBadGateway, "payment_unavailable", "unable to close pending payments")
+		default:
+			h.logError("close order failed", err)
+			h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to close order")
+		}
+		return
+	}
+	response, err := h.orderResponse(ctx, updated)
+	if err != nil {
+		h.logError("map closed order failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch updated order")
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// closeOrder closes pending payment sessions in the payment service first and
+// only then moves the order into its terminal status, so a payment can no
+// longer succeed for an order that has been cancelled.
+func (h *Handler) closeOrder(ctx context.Context, command orderCloseCommand) (db.Order, error) {
+	lookup := db.New(h.DB)
+	current, err := lookup.GetOrder(ctx, command.orderID)
+	if err != nil {
+		return db.Order{}, err
+	}
+	if command.customerID != uuid.Nil && current.CustomerID != command.customerID {
+		return db.Order{}, pgx.ErrNoRows
+	}
+	if _, err := lookup.GetOrderAdminEventByIdempotencyKey(ctx, db.GetOrderAdminEventByIdempotencyKeyParams{OrderID: current.ID, IdempotencyKey: command.idempotencyKey}); err == nil {
+		return current, nil
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return db.Order{}, err
+	}
+	transition, err := resolveOrderClose(current, command.event)
+	if err != nil {
+		return db.Order{}, err
+	}
+	if transition.HasEffect(ordermodule.EffectClosePayments) && h.Payments != nil && current.LatestPaymentID.Valid {
+		if err := h.Payments.ClosePendingPayments(ctx, current.ID, command.reasonCode); err != nil {
+			return db.Order{}, err
+		}
+	}
+
+	var updated db.Order
+	err = shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		closed, err := ordermodule.Close(ctx, tx, ordermodule.CloseRequest{
+			OrderID:        command.orderID,
+			Event:          command.event,
+			ActorUserID:    command.actorUserID,
+			IdempotencyKey: command.idempotencyKey,
+			Action:         command.action,
+			ReasonCode:     command.reasonCode,
+			Note:           command.note,
+			Outbox:         h.Outbox,
+		})
+		updated = closed
+		return orderCloseError(err)
+	})
+	if err != nil {
+		return db.Order{}, err
+	}
+	return updated, nil
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	ordermodule "github.com/teamdsb/tmo/services/commerce/internal/modules/order"
+)
+
+func TestResolveOrderClose(t *testing.T) {
+	tests := []struct {
+		name  string
+		order db.Order
+		want  error
+	}{
+		{name: "submitted unpaid order", order: db.Order{Status: "SUBMITTED", PaymentStatus: "UNPAID"}},
+		{name: "pending payment", order: db.Order{Status: "PAY_PENDING", PaymentStatus: "PAY_PENDING"}},
+		{name: "failed payment", order: db.Order{Status: "PAY_FAILED", PaymentStatus: "PAY_FAILED"}},
+		{name: "paid order", order: db.Order{Status: "PAID", PaymentStatus: "PAID"}, want: errOrderAlreadyPaid},
+		{name: "offline confirmed order", order: db.Order{Status: "CONFIRMED", PaymentStatus: "PAID"}, want: errOrderAlreadyPaid},
+		{name: "already cancelled", order: db.Order{Status: "CANCELLED", PaymentStatus: "UNPAID"}, want: errInvalidCloseTransition},
+		{name: "already closed", order: db.Order{Status: "CLOSED", PaymentStatus: "PAY_FAILED"}, want: errInvalidCloseTransition},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := resolveOrderClose(test.order, ordermodule.EventAdminClosed); !errors.Is(err, test.want) {
+				t.Fatalf("got %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestPaymentClientClosePendingPayments(t *testing.T) {
+	orderID := uuid.New()
+	tests := []struct {
+		name   string
+		status int
+		want   error
+	}{
+		{name: "closed", status: http.StatusOK},
+		{name: "already paid", status: http.StatusConflict, want: errPaymentAlreadyPaid},
+		{name: "unavailable", status: http.StatusInternalServerError, want: errPaymentUnavailable},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/internal/orders/"+orderID.String()+"/payments/close" {
+					t.Errorf("unexpected path %s", r.URL.Path)
+				}
+				if r.Header.Get("X-Internal-Token") != "sync-token" {
+					t.Errorf("internal token not forwarded")
+				}
+				body, _ := io.ReadAll(r.Body)
+				if !strings.Contains(string(body), `"reasonCode":"CHANGED_MIND"`) {
+					t.Errorf("unexpected body %s", body)
+				}
+				w.WriteHeader(test.status)
+			}))
+			defer server.Close()
+			err := NewPaymentClient(server.URL, "sync-token", server.Client()).ClosePendingPayments(context.Background(), orderID, "CHANGED_MIND")
+			if test.want == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if test.want != nil && !errors.Is(err, test.want) {
+				t.Fatalf("got %v, want %v", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+// Payloads of the events commerce publishes; contracts/events holds their
+// schemas.
+
+func orderCreatedEvent(order db.Order, items []events.OrderItem) events.OrderCreated {
+	var totalFen int64
+	for _, item := range items {
+		totalFen += item.UnitPriceFen * int64(item.Qty)
+	}
+	totalFen += order.ShippingFen - order.DiscountFen
+	return events.OrderCreated{
+		OrderID:          order.ID.String(),
+		OrderNo:          order.OrderNo,
+		CustomerID:       order.CustomerID.String(),
+		OwnerSalesUserID: optionalEventID(order.OwnerSalesUserID),
+		Status:           order.Status,
+		PaymentStatus:    order.PaymentStatus,
+		DiscountFen:      order.DiscountFen,
+		ShippingFen:      order.ShippingFen,
+		TotalFen:         totalFen,
+		Items:            items,
+		CreatedAt:        order.CreatedAt.Time.UTC(),
+	}
+}
+
+func ticketUpdatedEvent(ticket db.AfterSalesTicket) events.TicketUpdated {
+	return events.TicketUpdated{
+		TicketID:            ticket.ID.String(),
+		OrderID:             optionalEventID(ticket.OrderID),
+		CreatedByUserID:     ticket.CreatedByUserID.String(),
+		Status:              ticket.Status,
+		AssignedStaffUserID: optionalEventID(ticket.AssignedStaffUserID),
+		UpdatedAt:           ticket.UpdatedAt.Time.UTC(),
+	}
+}
+
+func optionalEventID(value pgtype.UUID) *string {
+	if !value.Valid {
+		return nil
+	}
+	id := uuid.UUID(value.Bytes).String()
+	return &id
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/shipping"
+)
+
+// orderShippingFen works out the freight of order lines shipped to address.
+// goodsFen is what the customer pays for the goods after discounts.
+// Destinations without an active rule, not even a default one, ship free.
+func (h *Handler) orderShippingFen(ctx context.Context, address oapi.Address, orderItems []orderLine, goodsFen int64) (int64, error) {
+	if h.ShippingStore == nil || len(orderItems) == 0 {
+		return 0, nil
+	}
+	var province, city string
+	if address.Province != nil {
+		province = *address.Province
+	}
+	if address.City != nil {
+		city = *address.City
+	}
+	rules, err := h.ShippingStore.ListActiveShippingRules(ctx, strings.TrimSpace(province))
+	if err != nil {
+		return 0, err
+	}
+	rule, ok := shipping.Match(rules, province, city)
+	if !ok {
+		return 0, nil
+	}
+	items := make([]shipping.Item, 0, len(orderItems))
+	for _, item := range orderItems {
+		items = append(items, shipping.Item{
+			WeightGrams: item.sku.WeightGrams,
+			VolumeCm3:   item.sku.VolumeCm3,
+			Qty:         item.qty,
+		})
+	}
+	return shipping.Fee(rule, items, goodsFen), nil
+}
+
+// orderGoodsFen is the amount of the order lines after discounts.
+func orderGoodsFen(orderItems []orderLine, discountFen int64) int64 {
+	var subtotalFen int64
+	for _, item := range orderItems {
+		subtotalFen += item.unitPriceFen.Int64() * int64(item.qty)
+	}
+	return subtotalFen - discountFen
+}
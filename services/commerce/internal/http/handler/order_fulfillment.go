@@ -13,14 +13,20 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/oapi-codegen/runtime/types"
 
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
 	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
 	"github.com/teamdsb/tmo/services/commerce/internal/db"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	ordermodule "github.com/teamdsb/tmo/services/commerce/internal/modules/order"
 )
 
-var errInvalidFulfillmentTransition = errors.New("order cannot be assigned in its current state")
+var (
+	errInvalidFulfillmentTransition = errors.New("order cannot be assigned in its current state")
+	errFulfillmentPaymentRequired   = errors.New("order must be paid before assignment")
+)
 
 type orderFulfillmentTransition struct {
+	transition      ordermodule.Transition
 	status          string
 	paymentStatus   string
 	latestPaymentID pgtype.UUID
@@ -45,24 +51,26 @@ func validateOrderFulfillmentInput(owner uuid.UUID, note, key string) error {
 }
 
 func resolveOrderFulfillmentTransition(order db.Order, confirmOffline bool) (orderFulfillmentTransition, error) {
-	switch strings.ToUpper(order.Status) {
-	case "SHIPPED", "DELIVERED", "CANCELLED", "CLOSED":
+	event := ordermodule.EventAssigned
+	if confirmOffline {
+		event = ordermodule.EventOfflinePaymentConfirmed
+	}
+	transition, err := ordermodule.Resolve(order.Status, order.PaymentStatus, event)
+	switch {
+	case errors.Is(err, ordermodule.ErrInvalidTransition):
 		return orderFulfillmentTransition{}, errInvalidFulfillmentTransition
+	case errors.Is(err, ordermodule.ErrPaymentRequired):
+		return orderFulfillmentTransition{}, errFulfillmentPaymentRequired
+	case errors.Is(err, ordermodule.ErrAlreadyPaid):
+		return orderFulfillmentTransition{}, errOrderAlreadyPaid
+	case err != nil:
+		return orderFulfillmentTransition{}, err
 	}
 	if confirmOffline {
-		if strings.EqualFold(order.PaymentStatus, "PAID") {
-			return orderFulfillmentTransition{}, errors.New("order is already paid")
-		}
 		channel := "OFFLINE"
-		return orderFulfillmentTransition{status: "CONFIRMED", paymentStatus: "PAID", paymentChannel: &channel, paidAt: pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true}}, nil
-	}
-	if !strings.EqualFold(order.PaymentStatus, "PAID") {
-		return orderFulfillmentTransition{}, errors.New("order must be paid before assignment")
-	}
-	if !strings.EqualFold(order.Status, "PAID") && !strings.EqualFold(order.Status, "CONFIRMED") {
-		return orderFulfillmentTransition{}, errInvalidFulfillmentTransition
+		return orderFulfillmentTransition{transition: transition, status: transition.To, paymentStatus: ordermodule.PaymentStatusPaid, paymentChannel: &channel, paidAt: pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true}}, nil
 	}
-	return orderFulfillmentTransition{status: "CONFIRMED", paymentStatus: order.PaymentStatus, latestPaymentID: order.LatestPaymentID, paymentChannel: order.PaymentChannel, paidAt: order.PaidAt}, nil
+	return orderFulfillmentTransition{transition: transition, status: transition.To, paymentStatus: order.PaymentStatus, latestPaymentID: order.LatestPaymentID, paymentChannel: order.PaymentChannel, paidAt: order.PaidAt}, nil
 }
 
 func fulfillmentAction(order db.Order, confirmOffline bool) string {
@@ -76,7 +84,7 @@ func fulfillmentAction(order db.Order, confirmOffline bool) string {
 }
 
 func (h *Handler) PatchAdminOrdersOrderIdFulfillment(c *gin.Context, orderID types.UUID, params oapi.PatchAdminOrdersOrderIdFulfillmentParams) {
-	claims, ok := h.requireRole(c, "BOSS", "MANAGER", "ADMIN")
+	claims, ok := h.requireAllScope(c, ordermodule.Permission(ordermodule.EventAssigned))
 	if !ok {
 		return
 	}
@@ -153,11 +161,17 @@ func (h *Handler) PatchAdminOrdersOrderIdFulfillment(c *gin.Context, orderID typ
 		if err != nil {
 			return err
 		}
+		if err := ordermodule.ApplyReceivableEffects(ctx, q, transition.transition, current.ID, claims.UserID); err != nil {
+			return err
+		}
+		if err := ordermodule.PublishStatusChanged(ctx, h.Outbox, tx, current, updated); err != nil {
+			return err
+		}
 		action := fulfillmentAction(current, request.ConfirmOfflinePayment)
 		_, err = q.CreateOrderAdminEvent(ctx, db.CreateOrderAdminEventParams{
 			OrderID: current.ID, IdempotencyKey: key, ActorUserID: claims.UserID, Action: action, Note: note,
 			PreviousStatus: current.Status, NewStatus: updated.Status, PreviousPaymentStatus: current.PaymentStatus, NewPaymentStatus: updated.PaymentStatus,
-			PreviousOwnerSalesUserID: current.OwnerSalesUserID, NewOwnerSalesUserID: ownerID,
+			PreviousOwnerSalesUserID: current.OwnerSalesUserID, NewOwnerSalesUserID: pgtype.UUID{Bytes: ownerID, Valid: true},
 		})
 		return err
 	})
@@ -165,7 +179,7 @@ func (h *Handler) PatchAdminOrdersOrderIdFulfillment(c *gin.Context, orderID typ
 		switch {
 		case errors.Is(err, pgx.ErrNoRows):
 			h.writeError(c, http.StatusNotFound, "not_found", "order not found")
-		case errors.Is(err, errInvalidFulfillmentTransition), strings.Contains(err.Error(), "paid"):
+		case errors.Is(err, errInvalidFulfillmentTransition), errors.Is(err, errFulfillmentPaymentRequired), errors.Is(err, errOrderAlreadyPaid):
 			h.writeError(c, http.StatusConflict, "invalid_order_state", err.Error())
 		default:
 			h.logError("update order fulfillment failed", err)
@@ -183,7 +197,7 @@ func (h *Handler) PatchAdminOrdersOrderIdFulfillment(c *gin.Context, orderID typ
 }
 
 func (h *Handler) GetAdminOrdersOrderIdEvents(c *gin.Context, orderID types.UUID) {
-	if _, ok := h.requireRole(c, "BOSS", "MANAGER", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionOrderManage); !ok {
 		return
 	}
 	if h.DB == nil {
@@ -232,10 +246,14 @@ func (h *Handler) orderResponse(ctx context.Context, order db.Order) (oapi.Order
 }
 
 func orderAdminEventFromModel(event db.OrderAdminEvent) oapi.OrderAdminEvent {
-	result := oapi.OrderAdminEvent{Id: event.ID, OrderId: event.OrderID, ActorUserId: event.ActorUserID, Action: event.Action, Note: event.Note, PreviousStatus: oapi.OrderStatus(event.PreviousStatus), NewStatus: oapi.OrderStatus(event.NewStatus), PreviousPaymentStatus: oapi.OrderPaymentStatus(event.PreviousPaymentStatus), NewPaymentStatus: oapi.OrderPaymentStatus(event.NewPaymentStatus), NewOwnerSalesUserId: event.NewOwnerSalesUserID, CreatedAt: event.CreatedAt.Time}
+	result := oapi.OrderAdminEvent{Id: event.ID, OrderId: event.OrderID, ActorUserId: event.ActorUserID, Action: event.Action, Note: event.Note, PreviousStatus: oapi.OrderStatus(event.PreviousStatus), NewStatus: oapi.OrderStatus(event.NewStatus), PreviousPaymentStatus: oapi.OrderPaymentStatus(event.PreviousPaymentStatus), NewPaymentStatus: oapi.OrderPaymentStatus(event.NewPaymentStatus), ReasonCode: event.ReasonCode, CreatedAt: event.CreatedAt.Time}
 	if event.PreviousOwnerSalesUserID.Valid {
 		id := types.UUID(event.PreviousOwnerSalesUserID.Bytes)
 		result.PreviousOwnerSalesUserId = &id
 	}
+	if event.NewOwnerSalesUserID.Valid {
+		id := types.UUID(event.NewOwnerSalesUserID.Bytes)
+		result.NewOwnerSalesUserId = &id
+	}
 	return result
 }
@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/promotion"
+)
+
+var errPromotionUnavailable = errors.New("promotion is no longer available")
+
+// orderDiscounts works out the discounts of a cart order for PostOrders. It
+// writes the error response and reports false when the coupon cannot be
+// redeemed.
+func (h *Handler) orderDiscounts(c *gin.Context, customerID uuid.UUID, couponCode string, orderItems []orderLine) ([]promotion.Discount, bool) {
+	discounts, err := h.resolveOrderDiscounts(c.Request.Context(), customerID, couponCode, orderItems)
+	if err != nil {
+		if code, status, ok := couponErrorCode(err); ok {
+			h.writeError(c, status, code, err.Error())
+			return nil, false
+		}
+		h.logError("resolve order discounts failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to submit order")
+		return nil, false
+	}
+	return discounts, true
+}
+
+// resolveOrderDiscounts works out the promotions a cart order gets: the
+// coupon the customer entered and the automatic promotions the lines
+// qualify for. Automatic promotions the customer used up are skipped; a
+// coupon that cannot be redeemed fails with one of the promotion errors.
+func (h *Handler) resolveOrderDiscounts(ctx context.Context, customerID uuid.UUID, couponCode string, orderItems []orderLine) ([]promotion.Discount, error) {
+	if h.PromotionStore == nil {
+		if couponCode != "" {
+			return nil, promotion.ErrCouponNotFound
+		}
+		return nil, nil
+	}
+
+	now := time.Now()
+	lines, subtotalFen, err := h.promotionLines(ctx, orderItems)
+	if err != nil {
+		return nil, fmt.Errorf("list sku categories: %w", err)
+	}
+
+	var couponDiscount *promotion.Discount
+	if couponCode != "" {
+		discount, err := h.couponDiscount(ctx, customerID, couponCode, lines, now)
+		if err != nil {
+			return nil, err
+		}
+		couponDiscount = &discount
+	}
+
+	promotions, err := h.PromotionStore.ListActiveAutomaticPromotions(ctx, pgtype.Timestamptz{Time: now, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("list automatic promotions: %w", err)
+	}
+	automatic := make([]promotion.Discount, 0, len(promotions))
+	for _, candidate := range promotions {
+		discount, ok := promotion.Evaluate(candidate, lines)
+		if !ok {
+			continue
+		}
+		if err := promotion.CheckLimits(ctx, h.PromotionStore, discount, customerID); err != nil {
+			if errors.Is(err, promotion.ErrCustomerLimitReached) {
+				continue
+			}
+			return nil, err
+		}
+		automatic = append(automatic, discount)
+	}
+	return promotion.Combine(couponDiscount, automatic, subtotalFen), nil
+}
+
+// couponErrorCode maps why a coupon cannot be redeemed to the error code
+// and status it is reported with. It reports false for other errors.
+func couponErrorCode(err error) (string, int, bool) {
+	switch {
+	case errors.Is(err, promotion.ErrCouponRedeemed), errors.Is(err, promotion.ErrCustomerLimitReached):
+		return "coupon_unavailable", http.StatusConflict, true
+	case errors.Is(err, promotion.ErrCouponNotFound), errors.Is(err, promotion.ErrInactive),
+		errors.Is(err, promotion.ErrNotStarted), errors.Is(err, promotion.ErrEnded),
+		errors.Is(err, promotion.ErrMinSpendNotMet):
+		return "coupon_invalid", http.StatusBadRequest, true
+	}
+	return "", 0, false
+}
+
+// promotionLines turns order lines into the lines promotions discount,
+// tagging each with the categories its product sits under.
+func (h *Handler) promotionLines(ctx context.Context, orderItems []orderLine) ([]promotion.Line, int64, error) {
+	skuIDs := make([]uuid.UUID, 0, len(orderItems))
+	for _, item := range orderItems {
+		skuIDs = append(skuIDs, item.sku.ID)
+	}
+	rows, err := h.PromotionStore.ListSkuCategoryAncestors(ctx, uniqueUUIDs(skuIDs))
+	if err != nil {
+		return nil, 0, err
+	}
+	categoriesBySku := make(map[uuid.UUID][]uuid.UUID, len(skuIDs))
+	for _, row := range rows {
+		categoriesBySku[row.SkuID] = append(categoriesBySku[row.SkuID], row.CategoryID)
+	}
+
+	var subtotalFen int64
+	lines := make([]promotion.Line, 0, len(orderItems))
+	for _, item := range orderItems {
+		amountFen := item.unitPriceFen.Int64() * int64(item.qty)
+		subtotalFen += amountFen
+		lines = append(lines, promotion.Line{
+			SkuID:       item.sku.ID,
+			CategoryIDs: categoriesBySku[item.sku.ID],
+			AmountFen:   amountFen,
+		})
+	}
+	return lines, subtotalFen, nil
+}
+
+func (h *Handler) couponDiscount(ctx context.Context, customerID uuid.UUID, code string, lines []promotion.Line, now time.Time) (promotion.Discount, error) {
+	coupon, err := h.PromotionStore.GetCouponByCode(ctx, code)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return promotion.Discount{}, promotion.ErrCouponNotFound
+	}
+	if err != nil {
+		return promotion.Discount{}, fmt.Errorf("get coupon: %w", err)
+	}
+	record, err := h.PromotionStore.GetPromotion(ctx, coupon.PromotionID)
+	if err != nil {
+		return promotion.Discount{}, fmt.Errorf("get promotion: %w", err)
+	}
+	if err := promotion.Available(record, now); err != nil {
+		return promotion.Discount{}, err
+	}
+	discount, ok := promotion.Evaluate(record, lines)
+	if !ok {
+		return promotion.Discount{}, promotion.ErrMinSpendNotMet
+	}
+	discount.Coupon = &coupon
+	if err := promotion.CheckLimits(ctx, h.PromotionStore, discount, customerID); err != nil {
+		return promotion.Discount{}, err
+	}
+	return discount, nil
+}
+
+// lockOrderDiscounts locks the promotions and coupons with redemption
+// limits an order redeems and checks them again, so concurrent orders
+// cannot redeem past a limit. Promotions are locked before coupons and each
+// in id order to keep concurrent orders from deadlocking.
+func lockOrderDiscounts(ctx context.Context, q *db.Queries, customerID uuid.UUID, discounts []promotion.Discount, now time.Time) error {
+	var promotionIDs, couponIDs []uuid.UUID
+	for _, discount := range discounts {
+		if discount.Promotion.PerCustomerLimit != nil || discount.Coupon != nil {
+			promotionIDs = append(promotionIDs, discount.Promotion.ID)
+		}
+		if discount.Coupon != nil {
+			couponIDs = append(couponIDs, discount.Coupon.ID)
+		}
+	}
+	for _, ids := range [][]uuid.UUID{promotionIDs, couponIDs} {
+		sort.Slice(ids, func(i, j int) bool {
+			return bytes.Compare(ids[i][:], ids[j][:]) < 0
+		})
+	}
+
+	for _, id := range promotionIDs {
+		locked, err := q.LockPromotion(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := promotion.Available(locked, now); err != nil {
+			return fmt.Errorf("%w: %w", errPromotionUnavailable, err)
+		}
+	}
+	for _, id := range couponIDs {
+		if _, err := q.LockCoupon(ctx, id); err != nil {
+			return err
+		}
+	}
+	for _, discount := range discounts {
+		if err := promotion.CheckLimits(ctx, q, discount, customerID); err != nil {
+			if errors.Is(err, promotion.ErrCouponRedeemed) || errors.Is(err, promotion.ErrCustomerLimitReached) {
+				return fmt.Errorf("%w: %w", errPromotionUnavailable, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// createOrderDiscounts stores the discount lines of an order in the order
+// they were applied.
+func createOrderDiscounts(ctx context.Context, q *db.Queries, orderID uuid.UUID, discounts []promotion.Discount) ([]db.OrderDiscount, error) {
+	rows := make([]db.OrderDiscount, 0, len(discounts))
+	for i, discount := range discounts {
+		params := db.CreateOrderDiscountParams{
+			OrderID:     orderID,
+			PromotionID: discount.Promotion.ID,
+			Label:       discount.Promotion.Name,
+			AmountFen:   discount.AmountFen,
+			Position:    int32(i),
+		}
+		if discount.Coupon != nil {
+			params.CouponID = pgtype.UUID{Bytes: discount.Coupon.ID, Valid: true}
+			params.CouponCode = &discount.Coupon.Code
+		}
+		row, err := q.CreateOrderDiscount(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func orderDiscountsFromModel(rows []db.OrderDiscount) []oapi.OrderDiscount {
+	items := make([]oapi.OrderDiscount, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, oapi.OrderDiscount{
+			PromotionId: row.PromotionID,
+			Label:       row.Label,
+			CouponCode:  row.CouponCode,
+			AmountFen:   row.AmountFen,
+		})
+	}
+	return items
+}
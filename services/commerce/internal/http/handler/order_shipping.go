@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"math"
 	"net/http"
 	"strings"
 	"time"
@@ -9,21 +10,19 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/oapi-codegen/runtime/types"
 
 	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
 	"github.com/teamdsb/tmo/services/commerce/internal/db"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	ordermodule "github.com/teamdsb/tmo/services/commerce/internal/modules/order"
 )
 
-var (
-	errInvalidShipTransition    = errors.New("order must be confirmed and paid before shipping")
-	errInvalidReceiptTransition = errors.New("order must be shipped before receipt confirmation")
-)
+var errInvalidReceiptTransition = errors.New("order must be shipped before receipt confirmation")
 
 func (h *Handler) PostAdminOrdersOrderIdShip(c *gin.Context, orderID types.UUID) {
-	if _, ok := h.requireRole(c, "PROCUREMENT", "CS", "MANAGER", "BOSS", "ADMIN"); !ok {
+	claims, ok := h.requireAllScope(c, ordermodule.Permission(ordermodule.EventShipped))
+	if !ok {
 		return
 	}
 
@@ -55,28 +54,32 @@ func (h *Handler) PostAdminOrdersOrderIdShip(c *gin.Context, orderID types.UUID)
 		}
 	}
 
-	ctx := c.Request.Context()
-	var updated db.Order
-	err := shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
-		q := db.New(tx)
-		current, err := q.GetOrderForUpdate(ctx, uuid.UUID(orderID))
-		if err != nil {
-			return err
+	var lines []ordermodule.ShipmentLine
+	if request.Lines != nil {
+		if len(*request.Lines) == 0 {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "lines must not be empty")
+			return
 		}
-		if !strings.EqualFold(current.Status, string(oapi.OrderStatusCONFIRMED)) || !strings.EqualFold(current.PaymentStatus, string(oapi.OrderPaymentStatusPAID)) {
-			return errInvalidShipTransition
-		}
-		if _, err := q.UpsertTrackingShipment(ctx, db.UpsertTrackingShipmentParams{
-			OrderID:   current.ID,
-			WaybillNo: waybillNo,
-			Carrier:   carrier,
-			ShippedAt: pgtype.Timestamptz{Time: shippedAt, Valid: true},
-		}); err != nil {
-			return err
+		for _, line := range *request.Lines {
+			if line.Qty <= 0 || line.Qty > math.MaxInt32 {
+				h.writeError(c, http.StatusBadRequest, "invalid_request", "qty must be positive")
+				return
+			}
+			lines = append(lines, ordermodule.ShipmentLine{OrderItemID: uuid.UUID(line.OrderItemId), Qty: int32(line.Qty)})
 		}
-		updated, err = q.UpdateOrderStatus(ctx, db.UpdateOrderStatusParams{
-			ID:     current.ID,
-			Status: string(oapi.OrderStatusSHIPPED),
+	}
+
+	ctx := c.Request.Context()
+	var result ordermodule.ShipmentResult
+	err := shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		var err error
+		result, err = ordermodule.Ship(ctx, tx, h.Outbox, ordermodule.ShipmentRequest{
+			OrderID:     uuid.UUID(orderID),
+			WaybillNo:   waybillNo,
+			Carrier:     carrier,
+			ShippedAt:   shippedAt,
+			ActorUserID: claims.UserID,
+			Lines:       lines,
 		})
 		return err
 	})
@@ -84,7 +87,13 @@ func (h *Handler) PostAdminOrdersOrderIdShip(c *gin.Context, orderID types.UUID)
 		switch {
 		case errors.Is(err, pgx.ErrNoRows):
 			h.writeError(c, http.StatusNotFound, "not_found", "order not found")
-		case errors.Is(err, errInvalidShipTransition):
+		case errors.Is(err, ordermodule.ErrUnknownOrderItem), errors.Is(err, ordermodule.ErrInvalidShipmentQty):
+			h.writeError(c, http.StatusBadRequest, "invalid_request", err.Error())
+		case errors.Is(err, ordermodule.ErrShipmentQtyExceeded):
+			h.writeError(c, http.StatusConflict, "shipment_qty_exceeded", err.Error())
+		case errors.Is(err, ordermodule.ErrWaybillShipped):
+			h.writeError(c, http.StatusConflict, "waybill_exists", err.Error())
+		case errors.Is(err, ordermodule.ErrNotShippable), errors.Is(err, ordermodule.ErrNothingToShip):
 			h.writeError(c, http.StatusConflict, "invalid_order_state", err.Error())
 		default:
 			h.logError("ship order failed", err)
@@ -93,7 +102,7 @@ func (h *Handler) PostAdminOrdersOrderIdShip(c *gin.Context, orderID types.UUID)
 		return
 	}
 
-	response, err := h.orderResponse(ctx, updated)
+	response, err := h.orderResponse(ctx, result.Order)
 	if err != nil {
 		h.logError("map shipped order failed", err)
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch updated order")
@@ -103,7 +112,7 @@ func (h *Handler) PostAdminOrdersOrderIdShip(c *gin.Context, orderID types.UUID)
 }
 
 func (h *Handler) PostOrdersOrderIdConfirmReceipt(c *gin.Context, orderID types.UUID) {
-	claims, ok := h.requireRole(c, "CUSTOMER")
+	claims, _, ok := h.requirePermission(c, ordermodule.Permission(ordermodule.EventReceiptConfirmed))
 	if !ok {
 		return
 	}
@@ -123,14 +132,18 @@ func (h *Handler) PostOrdersOrderIdConfirmReceipt(c *gin.Context, orderID types.
 		if current.CustomerID != claims.UserID {
 			return pgx.ErrNoRows
 		}
-		if !strings.EqualFold(current.Status, string(oapi.OrderStatusSHIPPED)) {
+		transition, err := ordermodule.Resolve(current.Status, current.PaymentStatus, ordermodule.EventReceiptConfirmed)
+		if err != nil {
 			return errInvalidReceiptTransition
 		}
 		updated, err = q.UpdateOrderStatus(ctx, db.UpdateOrderStatusParams{
 			ID:     current.ID,
-			Status: string(oapi.OrderStatusDELIVERED),
+			Status: transition.To,
 		})
-		return err
+		if err != nil {
+			return err
+		}
+		return ordermodule.PublishStatusChanged(ctx, h.Outbox, tx, current, updated)
 	})
 	if err != nil {
 		switch {
@@ -155,7 +168,7 @@ func (h *Handler) PostOrdersOrderIdConfirmReceipt(c *gin.Context, orderID types.
 }
 
 func (h *Handler) PostAdminOrdersOrderIdConfirmDelivery(c *gin.Context, orderID types.UUID) {
-	if _, ok := h.requireRole(c, "PROCUREMENT", "CS", "MANAGER", "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, ordermodule.Permission(ordermodule.EventDeliveryConfirmed)); !ok {
 		return
 	}
 	if h.DB == nil {
@@ -171,14 +184,18 @@ func (h *Handler) PostAdminOrdersOrderIdConfirmDelivery(c *gin.Context, orderID
 		if err != nil {
 			return err
 		}
-		if !strings.EqualFold(current.Status, string(oapi.OrderStatusSHIPPED)) {
+		transition, err := ordermodule.Resolve(current.Status, current.PaymentStatus, ordermodule.EventDeliveryConfirmed)
+		if err != nil {
 			return errInvalidReceiptTransition
 		}
 		updated, err = q.UpdateOrderStatus(ctx, db.UpdateOrderStatusParams{
 			ID:     current.ID,
-			Status: string(oapi.OrderStatusDELIVERED),
+			Status: transition.To,
 		})
-		return err
+		if err != nil {
+			return err
+		}
+		return ordermodule.PublishStatusChanged(ctx, h.Outbox, tx, current, updated)
 	})
 	if err != nil {
 		switch {
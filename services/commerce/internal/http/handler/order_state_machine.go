@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+	ordermodule "github.com/teamdsb/tmo/services/commerce/internal/modules/order"
+)
+
+type adminOrderState struct {
+	Status   string `json:"status"`
+	Terminal bool   `json:"terminal"`
+}
+
+type adminOrderTransition struct {
+	Event      string   `json:"event"`
+	From       []string `json:"from"`
+	To         string   `json:"to"`
+	Roles      []string `json:"roles"`
+	Permission string   `json:"permission,omitempty"`
+	Payment    string   `json:"payment"`
+	Effects    []string `json:"effects"`
+}
+
+type adminOrderStateMachine struct {
+	States      []adminOrderState      `json:"states"`
+	Transitions []adminOrderTransition `json:"transitions"`
+}
+
+func (h *Handler) GetAdminOrdersStateMachine(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionOrderRead); !ok {
+		return
+	}
+	c.JSON(http.StatusOK, orderStateMachineResponse())
+}
+
+func orderStateMachineResponse() adminOrderStateMachine {
+	states := make([]adminOrderState, 0, len(ordermodule.States()))
+	for _, status := range ordermodule.States() {
+		states = append(states, adminOrderState{Status: status, Terminal: ordermodule.IsTerminal(status)})
+	}
+	transitions := make([]adminOrderTransition, 0)
+	for _, transition := range ordermodule.Transitions() {
+		effects := make([]string, 0, len(transition.Effects))
+		for _, effect := range transition.Effects {
+			effects = append(effects, string(effect))
+		}
+		transitions = append(transitions, adminOrderTransition{
+			Event:      string(transition.Event),
+			From:       transition.From,
+			To:         transition.To,
+			Roles:      transition.Roles,
+			Permission: transition.Permission,
+			Payment:    string(transition.Payment),
+			Effects:    effects,
+		})
+	}
+	return adminOrderStateMachine{States: states, Transitions: transitions}
+}
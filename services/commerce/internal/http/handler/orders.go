@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -12,12 +15,22 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/oapi-codegen/runtime/types"
 
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
 	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	"github.com/teamdsb/tmo/packages/go-shared/events"
 	sharedmoney "github.com/teamdsb/tmo/packages/go-shared/money"
 	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/middleware"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/inventory"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/promotion"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/quotation"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/receivable"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/search"
 )
 
+const maxCouponCodeLength = 64
+
 type orderRequestValidationError struct {
 	message string
 }
@@ -26,6 +39,22 @@ func (e orderRequestValidationError) Error() string {
 	return e.message
 }
 
+// requestedOrderItem is a cart line the customer asked to order.
+type requestedOrderItem struct {
+	cartItemID uuid.UUID
+	skuID      uuid.UUID
+	qty        int32
+}
+
+// orderLine is a priced line of an order being submitted. sourceCartItemID
+// is uuid.Nil for lines that do not come from the cart.
+type orderLine struct {
+	sourceCartItemID uuid.UUID
+	sku              db.CatalogSku
+	qty              int32
+	unitPriceFen     sharedmoney.Fen
+}
+
 func (h *Handler) PostOrders(c *gin.Context, params oapi.PostOrdersParams) {
 	claims, ok := h.requireUser(c)
 	if !ok {
@@ -37,10 +66,27 @@ func (h *Handler) PostOrders(c *gin.Context, params oapi.PostOrdersParams) {
 		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
 		return
 	}
-	if len(request.Items) == 0 {
+	hasItems := request.Items != nil && len(*request.Items) > 0
+	if request.QuotationId != nil && hasItems {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "items and quotationId cannot be combined")
+		return
+	}
+	if request.QuotationId == nil && !hasItems {
 		h.writeError(c, http.StatusBadRequest, "invalid_request", "items is required")
 		return
 	}
+	var couponCode string
+	if request.CouponCode != nil {
+		couponCode = promotion.NormalizeCode(*request.CouponCode)
+	}
+	if couponCode != "" && request.QuotationId != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "couponCode and quotationId cannot be combined")
+		return
+	}
+	if len(couponCode) > maxCouponCodeLength {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "couponCode supports at most 64 characters")
+		return
+	}
 
 	if params.IdempotencyKey != nil {
 		order, err := h.OrderStore.GetOrderByIdempotencyKey(c.Request.Context(), db.GetOrderByIdempotencyKeyParams{
@@ -61,98 +107,53 @@ func (h *Handler) PostOrders(c *gin.Context, params oapi.PostOrdersParams) {
 		}
 	}
 
-	type requestedOrderItem struct {
-		cartItemID uuid.UUID
-		skuID      uuid.UUID
-		qty        int32
-	}
-
-	requestedItems := make([]requestedOrderItem, 0, len(request.Items))
-	skuIDs := make([]uuid.UUID, 0, len(request.Items))
-	qtyBySku := make(map[uuid.UUID]int32, len(request.Items))
-	seenCartItemIDs := make(map[uuid.UUID]struct{}, len(request.Items))
-	for _, item := range request.Items {
-		if item.Qty < 1 {
-			h.writeError(c, http.StatusBadRequest, "invalid_request", "qty must be >= 1")
-			return
+	var (
+		orderItems  []orderLine
+		tiersBySku  map[uuid.UUID][]db.CatalogPriceTier
+		quotationID uuid.UUID
+	)
+	if request.QuotationId != nil {
+		quotationID = uuid.UUID(*request.QuotationId)
+		orderItems, tiersBySku, ok = h.quotationOrderLines(c, claims.UserID, quotationID)
+	} else {
+		requestedItems := make([]requestedOrderItem, 0, len(*request.Items))
+		for _, item := range *request.Items {
+			requestedItems = append(requestedItems, requestedOrderItem{
+				cartItemID: uuid.UUID(item.CartItemId),
+				skuID:      uuid.UUID(item.SkuId),
+				qty:        clampInt32(item.Qty),
+			})
 		}
-		cartItemID := uuid.UUID(item.CartItemId)
-		if _, exists := seenCartItemIDs[cartItemID]; exists {
-			h.writeError(c, http.StatusBadRequest, "invalid_request", "duplicate cartItemId")
+		if message := validateRequestedOrderItems(requestedItems); message != "" {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", message)
 			return
 		}
-		seenCartItemIDs[cartItemID] = struct{}{}
-		skuID := uuid.UUID(item.SkuId)
-		qty := clampInt32(item.Qty)
-		requestedItems = append(requestedItems, requestedOrderItem{
-			cartItemID: cartItemID,
-			skuID:      skuID,
-			qty:        qty,
-		})
-		skuIDs = append(skuIDs, skuID)
-		qtyBySku[skuID] += qty
+		orderItems, tiersBySku, ok = h.cartOrderLines(c, claims, requestedItems)
 	}
-
-	uniqueSkuIDs := uniqueUUIDs(skuIDs)
-	skus, err := h.CatalogStore.ListSkusByIDs(c.Request.Context(), uniqueSkuIDs)
-	if err != nil {
-		h.logError("list skus failed", err)
-		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to submit order")
+	if !ok {
 		return
 	}
-	if len(skus) != len(uniqueSkuIDs) {
-		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid skuId")
-		return
+	qtyBySku := make(map[uuid.UUID]int32, len(orderItems))
+	for _, item := range orderItems {
+		qtyBySku[item.sku.ID] += item.qty
+	}
+	// Quotations are already negotiated prices; promotions only apply to
+	// cart orders.
+	var discounts []promotion.Discount
+	if quotationID == uuid.Nil {
+		discounts, ok = h.orderDiscounts(c, claims.UserID, couponCode, orderItems)
+		if !ok {
+			return
+		}
 	}
 
-	tiers, err := h.CatalogStore.ListPriceTiersBySkus(c.Request.Context(), uniqueSkuIDs)
+	shippingFen, err := h.orderShippingFen(c.Request.Context(), request.Address, orderItems, orderGoodsFen(orderItems, promotion.TotalFen(discounts)))
 	if err != nil {
-		h.logError("list price tiers failed", err)
+		h.logError("compute shipping fee failed", err)
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to submit order")
 		return
 	}
 
-	tiersBySku := map[uuid.UUID][]db.CatalogPriceTier{}
-	for _, tier := range tiers {
-		tiersBySku[tier.SkuID] = append(tiersBySku[tier.SkuID], tier)
-	}
-
-	skuByID := make(map[uuid.UUID]db.CatalogSku, len(skus))
-	for _, sku := range skus {
-		skuByID[sku.ID] = sku
-	}
-
-	orderItems := make([]struct {
-		sourceCartItemID uuid.UUID
-		sku              db.CatalogSku
-		qty              int32
-		unitPriceFen     sharedmoney.Fen
-	}, 0, len(requestedItems))
-	for _, item := range requestedItems {
-		sku := skuByID[item.skuID]
-		qty := qtyBySku[sku.ID]
-		if !sku.IsActive {
-			h.writeError(c, http.StatusBadRequest, "invalid_request", "sku is inactive")
-			return
-		}
-		priceFen, ok := selectUnitPrice(tiersBySku[sku.ID], qty)
-		if !ok {
-			h.writeError(c, http.StatusBadRequest, "invalid_request", "price tier not found")
-			return
-		}
-		orderItems = append(orderItems, struct {
-			sourceCartItemID uuid.UUID
-			sku              db.CatalogSku
-			qty              int32
-			unitPriceFen     sharedmoney.Fen
-		}{
-			sourceCartItemID: item.cartItemID,
-			sku:              sku,
-			qty:              item.qty,
-			unitPriceFen:     priceFen,
-		})
-	}
-
 	addressJSON, err := json.Marshal(request.Address)
 	if err != nil {
 		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid address")
@@ -165,40 +166,39 @@ func (h *Handler) PostOrders(c *gin.Context, params oapi.PostOrdersParams) {
 	}
 
 	ctx := c.Request.Context()
-	var order db.Order
+	var (
+		order          db.Order
+		orderDiscounts []db.OrderDiscount
+	)
 	ownerSalesUserID := pgtype.UUID{}
 	if strings.ToUpper(claims.Role) == "CUSTOMER" && claims.OwnerSalesUserID != uuid.Nil {
 		ownerSalesUserID = pgtype.UUID{Bytes: claims.OwnerSalesUserID, Valid: true}
 	}
-	err = shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
-		q := db.New(tx)
-		cartItemIDs := make([]uuid.UUID, 0, len(orderItems))
-		for _, item := range orderItems {
-			cartItemIDs = append(cartItemIDs, item.sourceCartItemID)
-		}
-		cartItems, err := q.ListCartItemsByIDsForUpdate(ctx, db.ListCartItemsByIDsForUpdateParams{
-			OwnerUserID: claims.UserID,
-			Ids:         cartItemIDs,
-		})
+	var financeProfile CustomerFinanceProfile
+	if h.FinanceProfiles != nil && strings.ToUpper(claims.Role) == "CUSTOMER" {
+		financeProfile, err = h.FinanceProfiles.GetCustomerFinanceProfile(ctx, claims.UserID)
 		if err != nil {
-			return err
-		}
-		cartByID := make(map[uuid.UUID]db.CartItem, len(cartItems))
-		for _, item := range cartItems {
-			cartByID[item.ID] = item
+			h.logError("get customer finance profile failed", err)
+			h.writeError(c, http.StatusBadGateway, "identity_unavailable", "unable to check customer payment terms")
+			return
 		}
-		for _, item := range orderItems {
-			cartItem, ok := cartByID[item.sourceCartItemID]
-			if !ok {
-				return orderRequestValidationError{message: "invalid cartItemId"}
-			}
-			if cartItem.SkuID != item.sku.ID {
-				return orderRequestValidationError{message: "cart item sku does not match"}
+	}
+	err = shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		q := db.New(tx)
+		var cartByID map[uuid.UUID]db.CartItem
+		if quotationID != uuid.Nil {
+			if err := lockOpenQuotation(ctx, q, claims.UserID, quotationID, time.Now()); err != nil {
+				return err
 			}
-			if cartItem.Qty < item.qty {
-				return orderRequestValidationError{message: "qty exceeds cart item quantity"}
+		} else {
+			cartByID, err = lockOrderCartItems(ctx, q, claims.UserID, orderItems)
+			if err != nil {
+				return err
 			}
 		}
+		if err := lockOrderDiscounts(ctx, q, claims.UserID, discounts, time.Now()); err != nil {
+			return err
+		}
 
 		order, err = q.CreateOrder(ctx, db.CreateOrderParams{
 			Status:           string(oapi.OrderStatusSUBMITTED),
@@ -208,41 +208,60 @@ func (h *Handler) PostOrders(c *gin.Context, params oapi.PostOrdersParams) {
 			Remark:           request.Remark,
 			IdempotencyKey:   params.IdempotencyKey,
 			PaymentStatus:    "UNPAID",
+			DiscountFen:      promotion.TotalFen(discounts),
+			ShippingFen:      shippingFen,
 		})
 		if err != nil {
 			return err
 		}
+		if err := inventory.Reserve(ctx, q, order.ID, qtyBySku); err != nil {
+			return err
+		}
 		for _, item := range orderItems {
 			if _, err := q.CreateOrderItem(ctx, db.CreateOrderItemParams{
 				OrderID:          order.ID,
 				SkuID:            item.sku.ID,
-				SourceCartItemID: pgtype.UUID{Bytes: item.sourceCartItemID, Valid: true},
+				SourceCartItemID: pgtype.UUID{Bytes: item.sourceCartItemID, Valid: item.sourceCartItemID != uuid.Nil},
 				Qty:              item.qty,
 				UnitPriceFen:     item.unitPriceFen.Int64(),
 			}); err != nil {
 				return err
 			}
-
-			cartItem := cartByID[item.sourceCartItemID]
-			remainingQty := cartItem.Qty - item.qty
-			if remainingQty > 0 {
-				if _, err := q.UpdateCartItemQty(ctx, db.UpdateCartItemQtyParams{
-					ID:          cartItem.ID,
-					Qty:         remainingQty,
-					OwnerUserID: claims.UserID,
-				}); err != nil {
-					return err
-				}
-				continue
+		}
+		orderDiscounts, err = createOrderDiscounts(ctx, q, order.ID, discounts)
+		if err != nil {
+			return err
+		}
+		if quotationID != uuid.Nil {
+			if _, err := q.ConvertQuotation(ctx, db.ConvertQuotationParams{
+				Status:       quotation.StatusConverted,
+				OrderID:      pgtype.UUID{Bytes: order.ID, Valid: true},
+				ID:           quotationID,
+				FromStatuses: quotation.OpenStatuses,
+			}); err != nil {
+				return err
 			}
-			if err := q.DeleteCartItem(ctx, db.DeleteCartItemParams{
-				ID:          cartItem.ID,
-				OwnerUserID: claims.UserID,
+		} else if err := consumeOrderCartItems(ctx, q, claims.UserID, cartByID, orderItems); err != nil {
+			return err
+		}
+		if financeProfile.IsMonthly() {
+			if _, err := receivable.Post(ctx, q, receivable.Posting{
+				OrderID:          order.ID,
+				CustomerID:       claims.UserID,
+				OwnerSalesUserID: ownerSalesUserID,
+				AmountFen:        orderGoodsFen(orderItems, order.DiscountFen) + order.ShippingFen,
+				TermDays:         financeProfile.PaymentTermDays,
+				CreditLimitFen:   financeProfile.CreditLimitFen,
+				PostedAt:         order.CreatedAt.Time,
 			}); err != nil {
 				return err
 			}
 		}
-		return nil
+		eventItems := make([]events.OrderItem, 0, len(orderItems))
+		for _, item := range orderItems {
+			eventItems = append(eventItems, events.OrderItem{SkuID: item.sku.ID.String(), Qty: item.qty, UnitPriceFen: item.unitPriceFen.Int64()})
+		}
+		return h.Outbox.Publish(ctx, tx, events.TypeOrderCreated, order.ID.String(), orderCreatedEvent(order, eventItems))
 	})
 	if err != nil {
 		var validationErr orderRequestValidationError
@@ -250,6 +269,36 @@ func (h *Handler) PostOrders(c *gin.Context, params oapi.PostOrdersParams) {
 			h.writeError(c, http.StatusBadRequest, "invalid_request", validationErr.message)
 			return
 		}
+		if errors.Is(err, errQuotationNotOpen) {
+			h.writeError(c, http.StatusConflict, "quotation_not_open", err.Error())
+			return
+		}
+		if errors.Is(err, errQuotationExpired) {
+			h.writeError(c, http.StatusConflict, "quotation_expired", err.Error())
+			return
+		}
+		if errors.Is(err, errPromotionUnavailable) {
+			h.writeError(c, http.StatusConflict, "promotion_unavailable", err.Error())
+			return
+		}
+		var stockErr inventory.InsufficientStockError
+		if errors.As(err, &stockErr) {
+			h.writeErrorWithDetails(c, http.StatusConflict, "insufficient_stock", "insufficient stock", map[string]interface{}{
+				"skuId":     stockErr.SkuID,
+				"requested": stockErr.Requested,
+				"available": stockErr.Available,
+			})
+			return
+		}
+		var creditErr receivable.CreditLimitExceededError
+		if errors.As(err, &creditErr) {
+			h.writeErrorWithDetails(c, http.StatusConflict, "credit_limit_exceeded", "credit limit exceeded", map[string]interface{}{
+				"creditLimitFen": creditErr.LimitFen,
+				"outstandingFen": creditErr.OutstandingFen,
+				"orderAmountFen": creditErr.RequestedFen,
+			})
+			return
+		}
 		if shareddb.IsUniqueViolation(err) && params.IdempotencyKey != nil {
 			h.logError("idempotency key conflict", err)
 			var details map[string]interface{}
@@ -271,7 +320,7 @@ func (h *Handler) PostOrders(c *gin.Context, params oapi.PostOrdersParams) {
 
 	items := make([]oapi.OrderItem, 0, len(orderItems))
 	for _, item := range orderItems {
-		mapped, err := skuFromModel(item.sku, tiersBySku[item.sku.ID])
+		mapped, err := skuFromModel(item.sku, tiersBySku[item.sku.ID], nil)
 		if err != nil {
 			h.logError("map sku failed", err)
 			h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to submit order")
@@ -290,12 +339,314 @@ func (h *Handler) PostOrders(c *gin.Context, params oapi.PostOrdersParams) {
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to submit order")
 		return
 	}
+	discountLines := orderDiscountsFromModel(orderDiscounts)
+	response.Discounts = &discountLines
 
 	c.JSON(http.StatusCreated, response)
 }
 
+// validateRequestedOrderItems returns why the requested cart lines are not
+// a valid request, or "" when they are.
+func validateRequestedOrderItems(items []requestedOrderItem) string {
+	seenCartItemIDs := make(map[uuid.UUID]struct{}, len(items))
+	for _, item := range items {
+		if item.qty < 1 {
+			return "qty must be >= 1"
+		}
+		if _, exists := seenCartItemIDs[item.cartItemID]; exists {
+			return "duplicate cartItemId"
+		}
+		seenCartItemIDs[item.cartItemID] = struct{}{}
+	}
+	return ""
+}
+
+// cartLineProblem is why a requested cart line cannot be ordered. message
+// is what PostOrders rejects the order with; checkout preview reports code.
+type cartLineProblem struct {
+	code    string
+	message string
+}
+
+var (
+	cartLineSkuNotFound      = cartLineProblem{code: "SKU_NOT_FOUND", message: "invalid skuId"}
+	cartLineSkuInactive      = cartLineProblem{code: "SKU_INACTIVE", message: "sku is inactive"}
+	cartLinePriceUnavailable = cartLineProblem{code: "PRICE_UNAVAILABLE", message: "price tier not found"}
+)
+
+// errCustomerPricesUnavailable means identity could not tell which price
+// lists reach the customer; pricing without them could undercharge or
+// overcharge.
+var errCustomerPricesUnavailable = errors.New("unable to resolve customer prices")
+
+// pricedCartLine is a requested cart line with its price. line.sku is only
+// set when the SKU exists; line.unitPriceFen and tier only without problem.
+type pricedCartLine struct {
+	line    orderLine
+	tier    db.CatalogPriceTier
+	problem *cartLineProblem
+}
+
+// cartPricing prices requested cart lines. PostOrders and checkout preview
+// both price through it so a preview never disagrees with the order.
+type cartPricing struct {
+	lines      []pricedCartLine
+	tiersBySku map[uuid.UUID][]db.CatalogPriceTier
+	qtyBySku   map[uuid.UUID]int32
+}
+
+// orderLines returns the lines that can be ordered.
+func (p cartPricing) orderLines() []orderLine {
+	lines := make([]orderLine, 0, len(p.lines))
+	for _, line := range p.lines {
+		if line.problem == nil {
+			lines = append(lines, line.line)
+		}
+	}
+	return lines
+}
+
+// priceCartLines prices the requested cart lines from the tiers the caller
+// pays, picking each tier by the total quantity ordered of the SKU. Lines
+// that cannot be ordered carry a problem instead of failing the call.
+func (h *Handler) priceCartLines(ctx context.Context, claims middleware.Claims, requestedItems []requestedOrderItem) (cartPricing, error) {
+	skuIDs := make([]uuid.UUID, 0, len(requestedItems))
+	qtyBySku := make(map[uuid.UUID]int32, len(requestedItems))
+	for _, item := range requestedItems {
+		skuIDs = append(skuIDs, item.skuID)
+		qtyBySku[item.skuID] += item.qty
+	}
+
+	uniqueSkuIDs := uniqueUUIDs(skuIDs)
+	skus, err := h.CatalogStore.ListSkusByIDs(ctx, uniqueSkuIDs)
+	if err != nil {
+		return cartPricing{}, fmt.Errorf("list skus: %w", err)
+	}
+	skuByID := make(map[uuid.UUID]db.CatalogSku, len(skus))
+	foundSkuIDs := make([]uuid.UUID, 0, len(skus))
+	for _, sku := range skus {
+		skuByID[sku.ID] = sku
+		foundSkuIDs = append(foundSkuIDs, sku.ID)
+	}
+
+	tiers, err := h.CatalogStore.ListPriceTiersBySkus(ctx, foundSkuIDs)
+	if err != nil {
+		return cartPricing{}, fmt.Errorf("list price tiers: %w", err)
+	}
+	tiersBySku := tiersBySkuID(tiers)
+	buyer, err := h.pricingBuyer(ctx, claims)
+	if err != nil {
+		return cartPricing{}, fmt.Errorf("%w: %w", errCustomerPricesUnavailable, err)
+	}
+	prices, err := h.resolvePrices(ctx, buyer, foundSkuIDs, tiersBySku)
+	if err != nil {
+		return cartPricing{}, fmt.Errorf("resolve sku prices: %w", err)
+	}
+	for skuID, price := range prices {
+		tiersBySku[skuID] = price.Tiers
+	}
+
+	pricing := cartPricing{
+		lines:      make([]pricedCartLine, 0, len(requestedItems)),
+		tiersBySku: tiersBySku,
+		qtyBySku:   qtyBySku,
+	}
+	for _, item := range requestedItems {
+		priced := pricedCartLine{line: orderLine{sourceCartItemID: item.cartItemID, qty: item.qty}}
+		sku, found := skuByID[item.skuID]
+		switch {
+		case !found:
+			priced.line.sku.ID = item.skuID
+			priced.problem = &cartLineSkuNotFound
+		case !sku.IsActive:
+			priced.line.sku = sku
+			priced.problem = &cartLineSkuInactive
+		default:
+			priced.line.sku = sku
+			tier, ok := selectPriceTier(tiersBySku[sku.ID], qtyBySku[sku.ID])
+			if !ok {
+				priced.problem = &cartLinePriceUnavailable
+				break
+			}
+			priced.tier = tier
+			priced.line.unitPriceFen = sharedmoney.FromInt64(tier.UnitPriceFen)
+		}
+		pricing.lines = append(pricing.lines, priced)
+	}
+	return pricing, nil
+}
+
+// cartOrderLines prices the requested cart lines for PostOrders, rejecting
+// the order when any line cannot be ordered.
+func (h *Handler) cartOrderLines(c *gin.Context, claims middleware.Claims, requestedItems []requestedOrderItem) ([]orderLine, map[uuid.UUID][]db.CatalogPriceTier, bool) {
+	pricing, err := h.priceCartLines(c.Request.Context(), claims, requestedItems)
+	if err != nil {
+		if errors.Is(err, errCustomerPricesUnavailable) {
+			h.logError("get customer tags failed", err)
+			h.writeError(c, http.StatusBadGateway, "identity_unavailable", "unable to resolve customer prices")
+			return nil, nil, false
+		}
+		h.logError("price cart lines failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to submit order")
+		return nil, nil, false
+	}
+	for _, line := range pricing.lines {
+		if line.problem != nil {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", line.problem.message)
+			return nil, nil, false
+		}
+	}
+	return pricing.orderLines(), pricing.tiersBySku, true
+}
+
+// quotationOrderLines turns the lines of an open quotation of the customer
+// into order lines at the quoted prices. Each line's SKU is returned with
+// the quoted price as its only tier, since catalog tiers do not apply.
+func (h *Handler) quotationOrderLines(c *gin.Context, customerID, quotationID uuid.UUID) ([]orderLine, map[uuid.UUID][]db.CatalogPriceTier, bool) {
+	record, err := h.QuotationStore.GetQuotation(c.Request.Context(), quotationID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		h.logError("get quotation failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to submit order")
+		return nil, nil, false
+	}
+	if err != nil || record.CustomerID != customerID {
+		h.writeError(c, http.StatusNotFound, "not_found", "quotation not found")
+		return nil, nil, false
+	}
+	if err := checkQuotationOpen(record, time.Now()); err != nil {
+		code := "quotation_not_open"
+		if errors.Is(err, errQuotationExpired) {
+			code = "quotation_expired"
+		}
+		h.writeError(c, http.StatusConflict, code, err.Error())
+		return nil, nil, false
+	}
+
+	quotedItems, err := h.QuotationStore.ListQuotationItems(c.Request.Context(), record.ID)
+	if err != nil {
+		h.logError("list quotation items failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to submit order")
+		return nil, nil, false
+	}
+	skuIDs := make([]uuid.UUID, 0, len(quotedItems))
+	for _, item := range quotedItems {
+		skuIDs = append(skuIDs, item.SkuID)
+	}
+	skus, err := h.CatalogStore.ListSkusByIDs(c.Request.Context(), skuIDs)
+	if err != nil {
+		h.logError("list skus failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to submit order")
+		return nil, nil, false
+	}
+	skuByID := make(map[uuid.UUID]db.CatalogSku, len(skus))
+	for _, sku := range skus {
+		skuByID[sku.ID] = sku
+	}
+
+	tiersBySku := make(map[uuid.UUID][]db.CatalogPriceTier, len(quotedItems))
+	orderItems := make([]orderLine, 0, len(quotedItems))
+	for _, item := range quotedItems {
+		sku, found := skuByID[item.SkuID]
+		if !found || !sku.IsActive {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "sku is inactive")
+			return nil, nil, false
+		}
+		orderItems = append(orderItems, orderLine{
+			sku:          sku,
+			qty:          item.Qty,
+			unitPriceFen: sharedmoney.FromInt64(item.UnitPriceFen),
+		})
+		tiersBySku[sku.ID] = []db.CatalogPriceTier{{
+			SkuID:        sku.ID,
+			MinQty:       1,
+			UnitPriceFen: item.UnitPriceFen,
+		}}
+	}
+	return orderItems, tiersBySku, true
+}
+
+// lockOrderCartItems locks the cart items being ordered and checks they
+// still hold the ordered SKUs and quantities.
+func lockOrderCartItems(ctx context.Context, q *db.Queries, ownerUserID uuid.UUID, orderItems []orderLine) (map[uuid.UUID]db.CartItem, error) {
+	cartItemIDs := make([]uuid.UUID, 0, len(orderItems))
+	for _, item := range orderItems {
+		cartItemIDs = append(cartItemIDs, item.sourceCartItemID)
+	}
+	cartItems, err := q.ListCartItemsByIDsForUpdate(ctx, db.ListCartItemsByIDsForUpdateParams{
+		OwnerUserID: ownerUserID,
+		Ids:         cartItemIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	cartByID := make(map[uuid.UUID]db.CartItem, len(cartItems))
+	for _, item := range cartItems {
+		cartByID[item.ID] = item
+	}
+	for _, item := range orderItems {
+		cartItem, ok := cartByID[item.sourceCartItemID]
+		if message := cartItemMismatch(cartItem, ok, item.sku.ID, item.qty); message != "" {
+			return nil, orderRequestValidationError{message: message}
+		}
+	}
+	return cartByID, nil
+}
+
+// cartItemMismatch returns why a cart item no longer holds the line being
+// ordered, or "" when it does. found is false when the cart item is gone.
+func cartItemMismatch(cartItem db.CartItem, found bool, skuID uuid.UUID, qty int32) string {
+	switch {
+	case !found:
+		return "invalid cartItemId"
+	case cartItem.SkuID != skuID:
+		return "cart item sku does not match"
+	case cartItem.Qty < qty:
+		return "qty exceeds cart item quantity"
+	}
+	return ""
+}
+
+// consumeOrderCartItems takes the ordered quantities out of the cart,
+// removing cart items that were ordered in full.
+func consumeOrderCartItems(ctx context.Context, q *db.Queries, ownerUserID uuid.UUID, cartByID map[uuid.UUID]db.CartItem, orderItems []orderLine) error {
+	for _, item := range orderItems {
+		cartItem := cartByID[item.sourceCartItemID]
+		remainingQty := cartItem.Qty - item.qty
+		if remainingQty > 0 {
+			if _, err := q.UpdateCartItemQty(ctx, db.UpdateCartItemQtyParams{
+				ID:          cartItem.ID,
+				Qty:         remainingQty,
+				OwnerUserID: ownerUserID,
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := q.DeleteCartItem(ctx, db.DeleteCartItemParams{
+			ID:          cartItem.ID,
+			OwnerUserID: ownerUserID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lockOpenQuotation locks the quotation being converted and checks it is
+// still open, so two orders cannot convert the same quotation.
+func lockOpenQuotation(ctx context.Context, q *db.Queries, customerID, quotationID uuid.UUID, now time.Time) error {
+	record, err := q.GetQuotationForUpdate(ctx, quotationID)
+	if err != nil {
+		return err
+	}
+	if record.CustomerID != customerID {
+		return errQuotationNotOpen
+	}
+	return checkQuotationOpen(record, now)
+}
+
 func (h *Handler) GetOrders(c *gin.Context, params oapi.GetOrdersParams) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "SALES", "PROCUREMENT", "CS", "MANAGER", "BOSS", "ADMIN")
+	claims, scope, ok := h.requirePermission(c, authz.PermissionOrderRead)
 	if !ok {
 		return
 	}
@@ -313,24 +664,12 @@ func (h *Handler) GetOrders(c *gin.Context, params oapi.GetOrdersParams) {
 	}
 	offset := (page - 1) * pageSize
 
-	customerFilter := pgtype.UUID{}
-	ownerFilter := pgtype.UUID{}
-	role := strings.ToUpper(claims.Role)
-	switch role {
-	case "CUSTOMER":
-		customerFilter = pgtype.UUID{Bytes: claims.UserID, Valid: true}
-	case "SALES":
-		ownerFilter = pgtype.UUID{Bytes: claims.UserID, Valid: true}
-		if params.CustomerId != nil {
-			customerFilter = pgtype.UUID{Bytes: uuid.UUID(*params.CustomerId), Valid: true}
-		}
-	case "PROCUREMENT", "CS", "MANAGER", "BOSS", "ADMIN":
-		if params.CustomerId != nil {
-			customerFilter = pgtype.UUID{Bytes: uuid.UUID(*params.CustomerId), Valid: true}
-		}
-		if params.OwnerSalesUserId != nil {
-			ownerFilter = pgtype.UUID{Bytes: uuid.UUID(*params.OwnerSalesUserId), Valid: true}
-		}
+	customerFilter, ownerFilter := scopeFilters(claims, scope)
+	if scope != authz.ScopeSelf && params.CustomerId != nil {
+		customerFilter = pgtype.UUID{Bytes: uuid.UUID(*params.CustomerId), Valid: true}
+	}
+	if scope == authz.ScopeAll && params.OwnerSalesUserId != nil {
+		ownerFilter = pgtype.UUID{Bytes: uuid.UUID(*params.OwnerSalesUserId), Valid: true}
 	}
 
 	var status *string
@@ -338,11 +677,19 @@ func (h *Handler) GetOrders(c *gin.Context, params oapi.GetOrdersParams) {
 		value := string(*params.Status)
 		status = &value
 	}
+	var orderNo *string
+	if params.OrderNo != nil {
+		if value := strings.TrimSpace(*params.OrderNo); value != "" {
+			value = search.EscapeLike(value)
+			orderNo = &value
+		}
+	}
 
 	orders, err := h.OrderStore.ListOrders(c.Request.Context(), db.ListOrdersParams{
 		CustomerID:       customerFilter,
 		OwnerSalesUserID: ownerFilter,
 		Status:           status,
+		OrderNo:          orderNo,
 		Offset:           clampInt32(offset),
 		Limit:            clampInt32(pageSize),
 	})
@@ -356,6 +703,7 @@ func (h *Handler) GetOrders(c *gin.Context, params oapi.GetOrdersParams) {
 		CustomerID:       customerFilter,
 		OwnerSalesUserID: ownerFilter,
 		Status:           status,
+		OrderNo:          orderNo,
 	})
 	if err != nil {
 		h.logError("count orders failed", err)
@@ -411,19 +759,12 @@ func (h *Handler) GetOrders(c *gin.Context, params oapi.GetOrdersParams) {
 }
 
 func (h *Handler) GetOrdersStats(c *gin.Context) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "SALES", "PROCUREMENT", "CS", "MANAGER", "BOSS", "ADMIN")
+	claims, scope, ok := h.requirePermission(c, authz.PermissionOrderRead)
 	if !ok {
 		return
 	}
 
-	customerFilter := pgtype.UUID{}
-	ownerFilter := pgtype.UUID{}
-	switch strings.ToUpper(claims.Role) {
-	case "CUSTOMER":
-		customerFilter = pgtype.UUID{Bytes: claims.UserID, Valid: true}
-	case "SALES":
-		ownerFilter = pgtype.UUID{Bytes: claims.UserID, Valid: true}
-	}
+	customerFilter, ownerFilter := scopeFilters(claims, scope)
 
 	stats, err := h.OrderStore.ListOrderStatusStats(c.Request.Context(), db.ListOrderStatusStatsParams{
 		CustomerID:       customerFilter,
@@ -449,7 +790,7 @@ func (h *Handler) GetOrdersStats(c *gin.Context) {
 }
 
 func (h *Handler) GetOrdersOrderId(c *gin.Context, orderId types.UUID) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "SALES", "PROCUREMENT", "CS", "MANAGER", "BOSS", "ADMIN")
+	claims, scope, ok := h.requirePermission(c, authz.PermissionOrderRead)
 	if !ok {
 		return
 	}
@@ -464,17 +805,9 @@ func (h *Handler) GetOrdersOrderId(c *gin.Context, orderId types.UUID) {
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch order")
 		return
 	}
-	switch strings.ToUpper(claims.Role) {
-	case "CUSTOMER":
-		if order.CustomerID != claims.UserID {
-			h.writeError(c, http.StatusNotFound, "not_found", "order not found")
-			return
-		}
-	case "SALES":
-		if !order.OwnerSalesUserID.Valid || order.OwnerSalesUserID.Bytes != claims.UserID {
-			h.writeError(c, http.StatusNotFound, "not_found", "order not found")
-			return
-		}
+	if !scopeCovers(claims, scope, order.CustomerID, order.OwnerSalesUserID) {
+		h.writeError(c, http.StatusNotFound, "not_found", "order not found")
+		return
 	}
 
 	orderItems, err := h.OrderStore.ListOrderItems(c.Request.Context(), order.ID)
@@ -509,6 +842,16 @@ func (h *Handler) GetOrdersOrderId(c *gin.Context, orderId types.UUID) {
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch order")
 		return
 	}
+	if h.PromotionStore != nil {
+		discounts, err := h.PromotionStore.ListOrderDiscounts(c.Request.Context(), order.ID)
+		if err != nil {
+			h.logError("list order discounts failed", err)
+			h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch order")
+			return
+		}
+		discountLines := orderDiscountsFromModel(discounts)
+		response.Discounts = &discountLines
+	}
 
 	c.JSON(http.StatusOK, response)
 }
@@ -538,11 +881,24 @@ func orderFromModel(order db.Order, items []oapi.OrderItem) (oapi.Order, error)
 		}
 	}
 
+	var subtotalFen int64
+	for _, item := range items {
+		subtotalFen += item.UnitPriceFen * int64(item.Qty)
+	}
+	discountFen := order.DiscountFen
+	shippingFen := order.ShippingFen
+	totalFen := subtotalFen - discountFen + shippingFen
+
 	response := oapi.Order{
 		Id:            order.ID,
+		OrderNo:       order.OrderNo,
 		Status:        oapi.OrderStatus(order.Status),
 		PaymentStatus: oapi.OrderPaymentStatus(order.PaymentStatus),
 		Items:         items,
+		SubtotalFen:   &subtotalFen,
+		DiscountFen:   &discountFen,
+		ShippingFen:   &shippingFen,
+		TotalFen:      &totalFen,
 		CreatedAt:     order.CreatedAt.Time,
 		UpdatedAt:     timeFromTimestamptz(order.UpdatedAt),
 	}
@@ -570,6 +926,16 @@ func orderFromModel(order db.Order, items []oapi.OrderItem) (oapi.Order, error)
 }
 
 func selectUnitPrice(tiers []db.CatalogPriceTier, qty int32) (sharedmoney.Fen, bool) {
+	tier, ok := selectPriceTier(tiers, qty)
+	if !ok {
+		return sharedmoney.Zero, false
+	}
+	return sharedmoney.FromInt64(tier.UnitPriceFen), true
+}
+
+// selectPriceTier picks the tier that prices qty; later tiers win when
+// tiers overlap.
+func selectPriceTier(tiers []db.CatalogPriceTier, qty int32) (db.CatalogPriceTier, bool) {
 	var selected *db.CatalogPriceTier
 	for i := range tiers {
 		tier := tiers[i]
@@ -582,7 +948,7 @@ func selectUnitPrice(tiers []db.CatalogPriceTier, qty int32) (sharedmoney.Fen, b
 		selected = &tier
 	}
 	if selected == nil {
-		return sharedmoney.Zero, false
+		return db.CatalogPriceTier{}, false
 	}
-	return sharedmoney.FromInt64(selected.UnitPriceFen), true
+	return *selected, true
 }
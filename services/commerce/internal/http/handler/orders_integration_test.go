@@ -3,12 +3,15 @@ package handler
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -20,21 +23,32 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/teamdsb/tmo/packages/go-shared/httpx"
+	"github.com/teamdsb/tmo/packages/go-shared/jwks"
 	"github.com/teamdsb/tmo/services/commerce/internal/db"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/middleware"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/inventory"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/tracking"
 )
 
 type allowSalesValidator struct{}
 
 func (allowSalesValidator) ValidateActiveSales(context.Context, string, uuid.UUID) error { return nil }
 
+type staticFinanceProfiles struct {
+	profile CustomerFinanceProfile
+}
+
+func (s staticFinanceProfiles) GetCustomerFinanceProfile(context.Context, uuid.UUID) (CustomerFinanceProfile, error) {
+	return s.profile, nil
+}
+
 func TestAdminOrderFulfillmentRejectsUnauthorizedRoles(t *testing.T) {
 	for _, role := range []string{"CS", "SALES", "CUSTOMER"} {
 		t.Run(role, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			router := httpx.NewRouter()
-			oapi.RegisterHandlers(router, &Handler{Auth: middleware.NewAuthenticator(true, testJWTSecret, testJWTIssuer)})
+			oapi.RegisterHandlers(router, &Handler{Auth: middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer)})
 			body := `{"ownerSalesUserId":"11111111-1111-1111-1111-111111111111","note":"paid in cash","confirmOfflinePayment":true}`
 			req := httptest.NewRequest(http.MethodPatch, "/admin/orders/22222222-2222-2222-2222-222222222222/fulfillment", strings.NewReader(body))
 			req.Header.Set("Content-Type", "application/json")
@@ -54,7 +68,7 @@ func TestAdminOrderFulfillmentAllowsManagementRoles(t *testing.T) {
 		t.Run(role, func(t *testing.T) {
 			gin.SetMode(gin.TestMode)
 			router := httpx.NewRouter()
-			oapi.RegisterHandlers(router, &Handler{Auth: middleware.NewAuthenticator(true, testJWTSecret, testJWTIssuer), SalesValidator: allowSalesValidator{}})
+			oapi.RegisterHandlers(router, &Handler{Auth: middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer), SalesValidator: allowSalesValidator{}})
 			body := `{"ownerSalesUserId":"11111111-1111-1111-1111-111111111111","note":"paid in cash","confirmOfflinePayment":true}`
 			req := httptest.NewRequest(http.MethodPatch, "/admin/orders/22222222-2222-2222-2222-222222222222/fulfillment", strings.NewReader(body))
 			req.Header.Set("Content-Type", "application/json")
@@ -236,6 +250,97 @@ func TestAdminShipOrderRejectsInvalidStateAndRole(t *testing.T) {
 	}
 }
 
+func TestAdminShipOrderInPartsTracksParcelItems(t *testing.T) {
+	pool := openHandlerTestPool(t)
+	resetCommerceTables(t, pool)
+	queries := db.New(pool)
+	ctx := context.Background()
+	sku, _ := seedCatalog(t, queries)
+	order := seedOrderWithItem(t, queries, uuid.New(), nil, sku.ID)
+	bulk, err := queries.CreateOrderItem(ctx, db.CreateOrderItemParams{
+		OrderID:      order.ID,
+		SkuID:        sku.ID,
+		Qty:          3,
+		UnitPriceFen: 12000,
+	})
+	if err != nil {
+		t.Fatalf("create order item: %v", err)
+	}
+	if _, err := queries.UpdateOrderPaymentSummary(ctx, db.UpdateOrderPaymentSummaryParams{
+		ID:              order.ID,
+		Status:          string(oapi.OrderStatusCONFIRMED),
+		PaymentStatus:   string(oapi.OrderPaymentStatusPAID),
+		LatestPaymentID: pgtype.UUID{},
+		PaymentChannel:  stringPtr("OFFLINE"),
+		PaidAt:          pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+	}); err != nil {
+		t.Fatalf("prepare confirmed order: %v", err)
+	}
+	router := newAuthIntegrationRouter(pool, queries)
+	token := makeAuthToken(t, uuid.New(), "MANAGER", nil)
+	ship := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/orders/"+order.ID.String()+"/ship", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+	status := func() string {
+		stored, err := queries.GetOrder(ctx, order.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return stored.Status
+	}
+
+	if recorder := ship(`{"waybillNo":"SF-PART-1","lines":[{"orderItemId":"` + bulk.ID.String() + `","qty":2}]}`); recorder.Code != http.StatusOK {
+		t.Fatalf("first parcel expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if got := status(); got != string(oapi.OrderStatusPARTIALLYSHIPPED) {
+		t.Fatalf("expected PARTIALLY_SHIPPED, got %s", got)
+	}
+	if recorder := ship(`{"waybillNo":"SF-PART-2","lines":[{"orderItemId":"` + bulk.ID.String() + `","qty":2}]}`); recorder.Code != http.StatusConflict {
+		t.Fatalf("over-shipping expected 409, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if recorder := ship(`{"waybillNo":"SF-PART-1"}`); recorder.Code != http.StatusConflict {
+		t.Fatalf("reused waybill expected 409, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if recorder := ship(`{"waybillNo":"SF-PART-2","lines":[{"orderItemId":"` + uuid.NewString() + `","qty":1}]}`); recorder.Code != http.StatusBadRequest {
+		t.Fatalf("foreign order item expected 400, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if recorder := ship(`{"waybillNo":"SF-PART-2"}`); recorder.Code != http.StatusOK {
+		t.Fatalf("remaining parcel expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if got := status(); got != string(oapi.OrderStatusSHIPPED) {
+		t.Fatalf("expected SHIPPED, got %s", got)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/"+order.ID.String()+"/tracking", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	var info oapi.TrackingInfo
+	if err := json.Unmarshal(recorder.Body.Bytes(), &info); err != nil {
+		t.Fatalf("decode tracking: %v", err)
+	}
+	if len(info.Shipments) != 2 {
+		t.Fatalf("expected two parcels, got %s", recorder.Body.String())
+	}
+	first, second := info.Shipments[0], info.Shipments[1]
+	if len(first.Items) != 1 || first.Items[0].OrderItemId != bulk.ID || first.Items[0].Qty != 2 || first.Items[0].SkuName == "" {
+		t.Fatalf("unexpected first parcel items %+v", first.Items)
+	}
+	if len(second.Items) != 2 {
+		t.Fatalf("expected the rest of both lines in the second parcel, got %+v", second.Items)
+	}
+	for _, item := range second.Items {
+		if item.OrderItemId == bulk.ID && item.Qty != 1 {
+			t.Fatalf("expected one remaining unit of the bulk line, got %+v", item)
+		}
+	}
+}
+
 func TestCustomerConfirmReceiptRequiresOwnedShippedOrder(t *testing.T) {
 	pool := openHandlerTestPool(t)
 	resetCommerceTables(t, pool)
@@ -283,6 +388,69 @@ func TestCustomerConfirmReceiptRequiresOwnedShippedOrder(t *testing.T) {
 	}
 }
 
+func TestCustomerCancelReleasesStockAndRecordsReason(t *testing.T) {
+	pool := openHandlerTestPool(t)
+	resetCommerceTables(t, pool)
+	queries := db.New(pool)
+	sku, _ := seedCatalog(t, queries)
+	ctx := context.Background()
+	if _, _, err := inventory.Adjust(ctx, queries, inventory.Adjustment{SkuID: sku.ID, Delta: 5, Reason: "RESTOCK"}); err != nil {
+		t.Fatalf("seed stock: %v", err)
+	}
+	customerID := uuid.New()
+	order := seedOrderWithItem(t, queries, customerID, nil, sku.ID)
+	if err := inventory.Reserve(ctx, queries, order.ID, map[uuid.UUID]int32{sku.ID: 1}); err != nil {
+		t.Fatalf("reserve stock: %v", err)
+	}
+	router := newAuthIntegrationRouter(pool, queries)
+	cancel := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/orders/"+order.ID.String()+"/cancel", strings.NewReader(`{"reasonCode":"CHANGED_MIND"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+makeAuthToken(t, customerID, "CUSTOMER", nil))
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	if recorder := cancel(); recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if recorder := cancel(); recorder.Code != http.StatusOK {
+		t.Fatalf("repeated cancel expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	stored, err := queries.GetOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != string(oapi.OrderStatusCANCELLED) {
+		t.Fatalf("expected CANCELLED, got %s", stored.Status)
+	}
+	record, err := queries.GetSkuInventory(ctx, sku.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Reserved != 0 || record.OnHand != 5 {
+		t.Fatalf("expected reservation to be released, got %#v", record)
+	}
+	events, err := queries.ListOrderAdminEvents(ctx, order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Action != "CUSTOMER_CANCEL" || events[0].ReasonCode == nil || *events[0].ReasonCode != "CHANGED_MIND" {
+		t.Fatalf("unexpected events: %#v", events)
+	}
+
+	closeReq := httptest.NewRequest(http.MethodPost, "/admin/orders/"+order.ID.String()+"/close", strings.NewReader(`{"reasonCode":"OTHER","note":"duplicate"}`))
+	closeReq.Header.Set("Content-Type", "application/json")
+	closeReq.Header.Set("Idempotency-Key", "close-1")
+	closeReq.Header.Set("Authorization", "Bearer "+makeAuthToken(t, uuid.New(), "MANAGER", nil))
+	closeRecorder := httptest.NewRecorder()
+	router.ServeHTTP(closeRecorder, closeReq)
+	if closeRecorder.Code != http.StatusConflict {
+		t.Fatalf("closing a cancelled order expected 409, got %d: %s", closeRecorder.Code, closeRecorder.Body.String())
+	}
+}
+
 func TestAdminConfirmDeliveryMarksShippedOrderDelivered(t *testing.T) {
 	pool := openHandlerTestPool(t)
 	resetCommerceTables(t, pool)
@@ -371,6 +539,84 @@ func TestAutoDeliverShippedOrdersUsesSevenDayCutoff(t *testing.T) {
 	}
 }
 
+func TestCarrierPushDeliversOrderOnceAllParcelsArrive(t *testing.T) {
+	pool := openHandlerTestPool(t)
+	resetCommerceTables(t, pool)
+	queries := db.New(pool)
+	sku, _ := seedCatalog(t, queries)
+	order := seedOrderWithItem(t, queries, uuid.New(), nil, sku.ID)
+	ctx := context.Background()
+	if _, err := queries.UpdateOrderStatus(ctx, db.UpdateOrderStatusParams{
+		ID:     order.ID,
+		Status: string(oapi.OrderStatusSHIPPED),
+	}); err != nil {
+		t.Fatalf("prepare shipped order: %v", err)
+	}
+	for _, waybillNo := range []string{"SF-PUSH-1", "SF-PUSH-2"} {
+		if _, err := queries.UpsertTrackingShipment(ctx, db.UpsertTrackingShipmentParams{
+			OrderID:   order.ID,
+			WaybillNo: waybillNo,
+			Carrier:   stringPtr("顺丰"),
+			ShippedAt: pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+		}); err != nil {
+			t.Fatalf("seed shipment: %v", err)
+		}
+	}
+
+	carriers := tracking.NewRegistry()
+	carriers.Register(&tracking.FixtureAdapter{Name: "SF", Token: "push-token"}, "顺丰")
+	handler := &Handler{OrderStore: queries, TrackingStore: queries, DB: pool, Carriers: carriers}
+	router := httpx.NewRouter()
+	router.POST("/tracking/carriers/:carrier/push", handler.PostTrackingCarriersCarrierPush)
+	router.GET("/orders/:orderId/tracking", func(c *gin.Context) {
+		handler.GetOrdersOrderIdTracking(c, uuid.MustParse(c.Param("orderId")))
+	})
+	push := func(body string) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/tracking/carriers/SF/push", strings.NewReader(body))
+		req.Header.Set(tracking.FixtureTokenHeader, "push-token")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"accepted":1`) {
+			t.Fatalf("expected push to be accepted, got %d: %s", recorder.Code, recorder.Body.String())
+		}
+	}
+
+	push(`{"waybillNo":"SF-PUSH-1","events":[{"status":"IN_TRANSIT","description":"已揽收","occurredAt":"2026-09-01T09:00:00+08:00"},{"status":"DELIVERED","description":"已签收","location":"上海","occurredAt":"2026-09-02T15:00:00+08:00"}]}`)
+	stored, err := queries.GetOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != string(oapi.OrderStatusSHIPPED) {
+		t.Fatalf("expected order to wait for the second parcel, got %s", stored.Status)
+	}
+
+	push(`{"waybillNo":"SF-PUSH-2","events":[{"status":"DELIVERED","description":"已签收","occurredAt":"2026-09-03T10:00:00+08:00"}]}`)
+	push(`{"waybillNo":"SF-PUSH-2","events":[{"status":"DELIVERED","description":"已签收","occurredAt":"2026-09-03T10:00:00+08:00"}]}`)
+	stored, err = queries.GetOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != string(oapi.OrderStatusDELIVERED) {
+		t.Fatalf("expected DELIVERED, got %s", stored.Status)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/"+order.ID.String()+"/tracking", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	var info oapi.TrackingInfo
+	if err := json.Unmarshal(recorder.Body.Bytes(), &info); err != nil {
+		t.Fatalf("decode tracking: %v", err)
+	}
+	if len(info.Shipments) != 2 || len(info.Shipments[0].Events) != 2 || len(info.Shipments[1].Events) != 1 {
+		t.Fatalf("unexpected tracking %s", recorder.Body.String())
+	}
+	first := info.Shipments[0]
+	if first.Status != oapi.ShipmentTrackingStatusDELIVERED || first.DeliveredAt == nil || first.Events[0].Status != oapi.ShipmentTrackingStatusDELIVERED {
+		t.Fatalf("expected newest delivered event first, got %+v", first)
+	}
+}
+
 func TestPostOrdersRemovesOrderedCartItemsAfterOrderSucceeds(t *testing.T) {
 	pool := openHandlerTestPool(t)
 	resetCommerceTables(t, pool)
@@ -420,6 +666,123 @@ func TestPostOrdersRemovesOrderedCartItemsAfterOrderSucceeds(t *testing.T) {
 	}
 }
 
+func TestPostOrdersReservesTrackedStock(t *testing.T) {
+	pool := openHandlerTestPool(t)
+	resetCommerceTables(t, pool)
+
+	queries := db.New(pool)
+	skuA, _ := seedCatalog(t, queries)
+
+	ctx := context.Background()
+	if _, _, err := inventory.Adjust(ctx, queries, inventory.Adjustment{SkuID: skuA.ID, Delta: 3, Reason: "RESTOCK"}); err != nil {
+		t.Fatalf("seed stock: %v", err)
+	}
+
+	router := newIntegrationRouter(pool, queries)
+	submit := func(qty int) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"address":{"receiverName":"A","receiverPhone":"1","detail":"X"},"items":[{"skuId":"%s","qty":%d}]}`, skuA.ID.String(), qty)
+		req := httptest.NewRequest(http.MethodPost, "/orders", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	if recorder := submit(2); recorder.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	recorder := submit(2)
+	if recorder.Code != http.StatusConflict || !strings.Contains(recorder.Body.String(), "insufficient_stock") {
+		t.Fatalf("expected insufficient stock conflict, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	record, err := queries.GetSkuInventory(ctx, skuA.ID)
+	if err != nil {
+		t.Fatalf("get inventory: %v", err)
+	}
+	if record.OnHand != 3 || record.Reserved != 2 {
+		t.Fatalf("unexpected inventory after orders: %#v", record)
+	}
+}
+
+func TestPostOrdersPostsReceivablesWithinCreditLimit(t *testing.T) {
+	pool := openHandlerTestPool(t)
+	resetCommerceTables(t, pool)
+
+	queries := db.New(pool)
+	skuA, _ := seedCatalog(t, queries)
+	ctx := context.Background()
+	customerID, salesID := uuid.New(), uuid.New()
+	creditLimitFen := int64(20000)
+
+	gin.SetMode(gin.TestMode)
+	router := httpx.NewRouter()
+	oapi.RegisterHandlers(router, &Handler{
+		CatalogStore:    queries,
+		CartStore:       queries,
+		OrderStore:      queries,
+		InventoryStore:  queries,
+		DB:              pool,
+		Auth:            middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer),
+		SalesValidator:  allowSalesValidator{},
+		FinanceProfiles: staticFinanceProfiles{profile: CustomerFinanceProfile{PaymentTermType: "MONTHLY", PaymentTermDays: 30, CreditLimitFen: &creditLimitFen}},
+	})
+	submit := func() *httptest.ResponseRecorder {
+		cartItem, err := queries.UpsertCartItem(ctx, db.UpsertCartItemParams{OwnerUserID: customerID, SkuID: skuA.ID, Qty: 1})
+		if err != nil {
+			t.Fatalf("seed cart item: %v", err)
+		}
+		body := fmt.Sprintf(`{"address":{"receiverName":"A","receiverPhone":"1","detail":"X"},"items":[{"cartItemId":"%s","skuId":"%s","qty":1}]}`, cartItem.ID, skuA.ID)
+		req := httptest.NewRequest(http.MethodPost, "/orders", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+makeAuthToken(t, customerID, "CUSTOMER", &salesID))
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	first := submit()
+	if first.Code != http.StatusCreated {
+		t.Fatalf("expected first order 201, got %d: %s", first.Code, first.Body.String())
+	}
+	var created oapi.Order
+	if err := json.Unmarshal(first.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode order: %v", err)
+	}
+	outstanding, err := queries.SumOpenReceivablesByCustomer(ctx, customerID)
+	if err != nil || outstanding != 12000 {
+		t.Fatalf("expected 12000 outstanding, got %d (%v)", outstanding, err)
+	}
+	unpaid, err := queries.ListUnpaidOrdersCreatedBefore(ctx, db.ListUnpaidOrdersCreatedBeforeParams{
+		Statuses:      []string{"SUBMITTED"},
+		CreatedBefore: pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+		Limit:         10,
+	})
+	if err != nil || len(unpaid) != 0 {
+		t.Fatalf("expected monthly order to be skipped by auto close, got %d (%v)", len(unpaid), err)
+	}
+
+	second := submit()
+	if second.Code != http.StatusConflict || !strings.Contains(second.Body.String(), "credit_limit_exceeded") {
+		t.Fatalf("expected credit limit conflict, got %d: %s", second.Code, second.Body.String())
+	}
+
+	body := fmt.Sprintf(`{"ownerSalesUserId":"%s","note":"monthly statement paid","confirmOfflinePayment":true}`, salesID)
+	req := httptest.NewRequest(http.MethodPatch, "/admin/orders/"+uuid.UUID(created.Id).String()+"/fulfillment", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "settle-1")
+	req.Header.Set("Authorization", "Bearer "+makeAuthToken(t, uuid.New(), "MANAGER", nil))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected offline payment 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if third := submit(); third.Code != http.StatusCreated {
+		t.Fatalf("expected settled receivable to free credit, got %d: %s", third.Code, third.Body.String())
+	}
+}
+
 func TestPostOrdersIdempotencyConflict(t *testing.T) {
 	pool := openHandlerTestPool(t)
 	resetCommerceTables(t, pool)
@@ -694,6 +1057,61 @@ func TestGetOrdersCustomerFiltersSelf(t *testing.T) {
 	}
 }
 
+func TestGetOrdersSearchesByOrderNo(t *testing.T) {
+	pool := openHandlerTestPool(t)
+	resetCommerceTables(t, pool)
+
+	queries := db.New(pool)
+	skuA, _ := seedCatalog(t, queries)
+
+	customerID := uuid.New()
+	orders := make([]db.Order, 0, 3)
+	for range 3 {
+		orders = append(orders, seedOrderWithItem(t, queries, customerID, nil, skuA.ID))
+	}
+	pattern := regexp.MustCompile(`^TMO\d{8}-\d{6}$`)
+	for index, order := range orders {
+		if !pattern.MatchString(order.OrderNo) {
+			t.Fatalf("unexpected order number %q", order.OrderNo)
+		}
+		if want := fmt.Sprintf("-%06d", index+1); !strings.HasSuffix(order.OrderNo, want) {
+			t.Fatalf("expected order number %q to end with %q", order.OrderNo, want)
+		}
+	}
+
+	router := newAuthIntegrationRouter(pool, queries)
+	req := httptest.NewRequest(http.MethodGet, "/orders?orderNo="+strings.ToLower(orders[1].OrderNo[len(orders[1].OrderNo)-8:]), nil)
+	req.Header.Set("Authorization", "Bearer "+makeAuthToken(t, customerID, "CUSTOMER", nil))
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var list oapi.PagedOrderList
+	if err := json.Unmarshal(recorder.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode orders response: %v", err)
+	}
+	if list.Total != 1 || len(list.Items) != 1 || list.Items[0].OrderNo != orders[1].OrderNo {
+		t.Fatalf("expected only %s, got %+v", orders[1].OrderNo, list)
+	}
+
+	// LIKE wildcards in the search match literally.
+	for _, search := range []string{"_", "%25"} {
+		req := httptest.NewRequest(http.MethodGet, "/orders?orderNo="+search, nil)
+		req.Header.Set("Authorization", "Bearer "+makeAuthToken(t, customerID, "CUSTOMER", nil))
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		var list oapi.PagedOrderList
+		if err := json.Unmarshal(recorder.Body.Bytes(), &list); err != nil {
+			t.Fatalf("decode orders response: %v", err)
+		}
+		if recorder.Code != http.StatusOK || list.Total != 0 {
+			t.Fatalf("expected no orders for %q, got %d: %s", search, recorder.Code, recorder.Body.String())
+		}
+	}
+}
+
 func TestGetOrdersSalesRequiresOwnership(t *testing.T) {
 	pool := openHandlerTestPool(t)
 	resetCommerceTables(t, pool)
@@ -776,21 +1194,44 @@ func resetCommerceTables(t *testing.T, pool *pgxpool.Pool) {
 	defer cancel()
 
 	_, err := pool.Exec(ctx, `
-TRUNCATE order_tracking_shipments,
+TRUNCATE processed_events,
+outbox_deliveries,
+outbox_events,
+receivables,
+sku_inventory_movements,
+sku_inventory_reservations,
+sku_inventory,
+shipment_import_rows,
+shipment_import_jobs,
+shipment_events,
+order_shipment_lines,
+order_tracking_shipments,
 import_jobs,
 product_requests,
 support_conversation_transfers,
 support_messages,
 support_message_assets,
 support_conversations,
+price_list_rule_tiers,
+price_list_rules,
+price_list_assignments,
+price_lists,
+quotation_items,
+quotations,
 price_inquiries,
 after_sales_tickets,
+order_discounts,
+coupons,
+promotions,
+shipping_rules,
 order_items,
 orders,
+order_number_sequences,
 cart_items,
 cart_import_rows,
 cart_import_jobs,
 catalog_price_tiers,
+catalog_search_documents,
 catalog_skus,
 catalog_products,
 catalog_categories
@@ -889,7 +1330,10 @@ func newIntegrationRouter(pool *pgxpool.Pool, store *db.Queries) *gin.Engine {
 		ProductRequestStore: store,
 		AfterSalesStore:     store,
 		InquiryStore:        store,
+		QuotationStore:      store,
+		InventoryStore:      store,
 		SupportStore:        store,
+		SearchStore:         store,
 		DB:                  pool,
 	})
 	return router
@@ -898,7 +1342,7 @@ func newIntegrationRouter(pool *pgxpool.Pool, store *db.Queries) *gin.Engine {
 func newAuthIntegrationRouter(pool *pgxpool.Pool, store *db.Queries) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := httpx.NewRouter()
-	authenticator := middleware.NewAuthenticator(true, testJWTSecret, testJWTIssuer)
+	authenticator := middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer)
 	oapi.RegisterHandlers(router, &Handler{
 		CatalogStore:        store,
 		CartStore:           store,
@@ -907,6 +1351,8 @@ func newAuthIntegrationRouter(pool *pgxpool.Pool, store *db.Queries) *gin.Engine
 		ProductRequestStore: store,
 		AfterSalesStore:     store,
 		InquiryStore:        store,
+		QuotationStore:      store,
+		InventoryStore:      store,
 		SupportStore:        store,
 		DB:                  pool,
 		Auth:                authenticator,
@@ -953,10 +1399,23 @@ func seedOrderWithItem(t *testing.T, queries *db.Queries, customerID uuid.UUID,
 	return order
 }
 
-const (
-	testJWTSecret = "test-secret"
-	testJWTIssuer = "test-issuer"
-)
+const testJWTIssuer = "test-issuer"
+
+// testJWTSigner stands in for identity's signing key; handlers verify with
+// the matching public key through testJWTKeys.
+var testJWTSigner, testJWTKeyID, testJWTKeys = newTestJWTKeys()
+
+func newTestJWTKeys() (ed25519.PrivateKey, string, *jwks.Cache) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	key, err := jwks.NewKey(public)
+	if err != nil {
+		panic(err)
+	}
+	return private, key.ID, jwks.NewStatic(key)
+}
 
 func makeAuthToken(t *testing.T, userID uuid.UUID, role string, ownerSalesUserID *uuid.UUID) string {
 	t.Helper()
@@ -980,8 +1439,9 @@ func makeAuthTokenWithProfile(t *testing.T, userID uuid.UUID, role string, owner
 	if strings.TrimSpace(phone) != "" {
 		claims["phone"] = strings.TrimSpace(phone)
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signed, err := token.SignedString([]byte(testJWTSecret))
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = testJWTKeyID
+	signed, err := token.SignedString(testJWTSigner)
 	if err != nil {
 		t.Fatalf("sign token: %v", err)
 	}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	errPaymentAlreadyPaid = errors.New("order has a paid payment")
+	errPaymentUnavailable = errors.New("payment service unavailable")
+)
+
+type PaymentCloser interface {
+	ClosePendingPayments(ctx context.Context, orderID uuid.UUID, reasonCode string) error
+}
+
+type PaymentClient struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+func NewPaymentClient(baseURL, token string, client *http.Client) *PaymentClient {
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &PaymentClient{baseURL: strings.TrimRight(strings.TrimSpace(baseURL), "/"), token: strings.TrimSpace(token), client: client}
+}
+
+func (c *PaymentClient) ClosePendingPayments(ctx context.Context, orderID uuid.UUID, reasonCode string) error {
+	if c == nil || c.baseURL == "" {
+		return fmt.Errorf("%w: base URL is not configured", errPaymentUnavailable)
+	}
+	endpoint, err := url.JoinPath(c.baseURL, "internal", "orders", orderID.String(), "payments", "close")
+	if err != nil {
+		return fmt.Errorf("%w: %v", errPaymentUnavailable, err)
+	}
+	body, err := json.Marshal(map[string]string{"reasonCode": reasonCode})
+	if err != nil {
+		return fmt.Errorf("%w: %v", errPaymentUnavailable, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %v", errPaymentUnavailable, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("X-Internal-Token", c.token)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errPaymentUnavailable, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	switch {
+	case resp.StatusCode == http.StatusConflict:
+		return errPaymentAlreadyPaid
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return fmt.Errorf("%w: payment returned %d", errPaymentUnavailable, resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,737 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/pricing"
+)
+
+const maxPriceListNameLength = 100
+
+var errPriceListRuleExists = errors.New("price list already has a rule for this target")
+
+type priceListRequest struct {
+	Name        *string    `json:"name"`
+	Description *string    `json:"description"`
+	Priority    *int32     `json:"priority"`
+	IsActive    *bool      `json:"isActive"`
+	ValidFrom   *time.Time `json:"validFrom"`
+	ValidUntil  *time.Time `json:"validUntil"`
+}
+
+type priceListAssignmentsRequest struct {
+	CustomerIDs    []uuid.UUID `json:"customerIds"`
+	CustomerTagIDs []uuid.UUID `json:"customerTagIds"`
+}
+
+type priceListRuleTierRequest struct {
+	MinQty       int32  `json:"minQty"`
+	MaxQty       *int32 `json:"maxQty"`
+	UnitPriceFen int64  `json:"unitPriceFen"`
+}
+
+type priceListRuleRequest struct {
+	SkuID       *uuid.UUID                 `json:"skuId"`
+	CategoryID  *uuid.UUID                 `json:"categoryId"`
+	Kind        string                     `json:"kind"`
+	DiscountBps *int32                     `json:"discountBps"`
+	Tiers       []priceListRuleTierRequest `json:"tiers"`
+}
+
+type priceListResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description,omitempty"`
+	Priority    int32      `json:"priority"`
+	IsActive    bool       `json:"isActive"`
+	ValidFrom   *time.Time `json:"validFrom,omitempty"`
+	ValidUntil  *time.Time `json:"validUntil,omitempty"`
+	CreatedBy   uuid.UUID  `json:"createdBy"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+type priceListListResponse struct {
+	Items    []priceListResponse `json:"items"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"pageSize"`
+	Total    int64               `json:"total"`
+}
+
+type priceListRuleResponse struct {
+	ID          uuid.UUID        `json:"id"`
+	SkuID       *uuid.UUID       `json:"skuId,omitempty"`
+	CategoryID  *uuid.UUID       `json:"categoryId,omitempty"`
+	Kind        string           `json:"kind"`
+	DiscountBps *int32           `json:"discountBps,omitempty"`
+	Tiers       []oapi.PriceTier `json:"tiers"`
+	CreatedAt   time.Time        `json:"createdAt"`
+}
+
+type priceListDetailResponse struct {
+	priceListResponse
+	CustomerIDs    []uuid.UUID             `json:"customerIds"`
+	CustomerTagIDs []uuid.UUID             `json:"customerTagIds"`
+	Rules          []priceListRuleResponse `json:"rules"`
+}
+
+type pricingCandidateResponse struct {
+	PriceListID   uuid.UUID  `json:"priceListId"`
+	PriceListName string     `json:"priceListName"`
+	Priority      int32      `json:"priority"`
+	RuleID        uuid.UUID  `json:"ruleId"`
+	Kind          string     `json:"kind"`
+	DiscountBps   *int32     `json:"discountBps,omitempty"`
+	SkuID         *uuid.UUID `json:"skuId,omitempty"`
+	CategoryID    *uuid.UUID `json:"categoryId,omitempty"`
+	MatchedBy     string     `json:"matchedBy"`
+	CustomerTagID *uuid.UUID `json:"customerTagId,omitempty"`
+	Applied       bool       `json:"applied"`
+}
+
+type pricingPreviewResponse struct {
+	CustomerID       uuid.UUID                  `json:"customerId"`
+	CustomerTagIDs   []uuid.UUID                `json:"customerTagIds"`
+	SkuID            uuid.UUID                  `json:"skuId"`
+	Qty              int32                      `json:"qty"`
+	BasePriceTiers   []oapi.PriceTier           `json:"basePriceTiers"`
+	BaseUnitPriceFen *int64                     `json:"baseUnitPriceFen"`
+	PriceTiers       []oapi.PriceTier           `json:"priceTiers"`
+	UnitPriceFen     *int64                     `json:"unitPriceFen"`
+	AmountFen        *int64                     `json:"amountFen"`
+	AppliedRule      *pricingCandidateResponse  `json:"appliedRule"`
+	Candidates       []pricingCandidateResponse `json:"candidates"`
+}
+
+func (h *Handler) PostAdminPriceLists(c *gin.Context) {
+	claims, ok := h.requireAllScope(c, authz.PermissionPricingManage)
+	if !ok {
+		return
+	}
+	if h.PricingStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "price lists are not configured")
+		return
+	}
+
+	var request priceListRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	params := db.CreatePriceListParams{IsActive: true, CreatedBy: claims.UserID}
+	if request.Name != nil {
+		params.Name = strings.TrimSpace(*request.Name)
+	}
+	if request.Description != nil {
+		params.Description = normalizeOptionalText(*request.Description)
+	}
+	if request.Priority != nil {
+		params.Priority = *request.Priority
+	}
+	if request.IsActive != nil {
+		params.IsActive = *request.IsActive
+	}
+	params.ValidFrom = timestamptzFromPtr(request.ValidFrom)
+	params.ValidUntil = timestamptzFromPtr(request.ValidUntil)
+	if message := validatePriceList(params.Name, params.ValidFrom, params.ValidUntil); message != "" {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", message)
+		return
+	}
+
+	row, err := h.PricingStore.CreatePriceList(c.Request.Context(), params)
+	if err != nil {
+		h.logError("create price list failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to create price list")
+		return
+	}
+	c.JSON(http.StatusCreated, priceListFromModel(row))
+}
+
+func (h *Handler) GetAdminPriceLists(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionPricingManage); !ok {
+		return
+	}
+	if h.PricingStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "price lists are not configured")
+		return
+	}
+
+	page := parseAdminPositiveInt(c.Query("page"), 1)
+	pageSize := parseAdminPositiveInt(c.Query("pageSize"), 20)
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	rows, err := h.PricingStore.ListPriceLists(c.Request.Context(), db.ListPriceListsParams{
+		Limit:  clampInt32(pageSize),
+		Offset: clampInt32((page - 1) * pageSize),
+	})
+	if err != nil {
+		h.logError("list price lists failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list price lists")
+		return
+	}
+	total, err := h.PricingStore.CountPriceLists(c.Request.Context())
+	if err != nil {
+		h.logError("count price lists failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list price lists")
+		return
+	}
+
+	items := make([]priceListResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, priceListFromModel(row))
+	}
+	c.JSON(http.StatusOK, priceListListResponse{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	})
+}
+
+func (h *Handler) GetAdminPriceListsPriceListId(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionPricingManage); !ok {
+		return
+	}
+	row, ok := h.loadPriceList(c)
+	if !ok {
+		return
+	}
+	h.writePriceListDetail(c, row)
+}
+
+func (h *Handler) PatchAdminPriceListsPriceListId(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionPricingManage); !ok {
+		return
+	}
+	current, ok := h.loadPriceList(c)
+	if !ok {
+		return
+	}
+
+	var request priceListRequest
+	fields, err := decodeJSONFields(c, &request)
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	update := db.UpdatePriceListParams{
+		ID:          current.ID,
+		Name:        current.Name,
+		Description: current.Description,
+		Priority:    current.Priority,
+		IsActive:    current.IsActive,
+		ValidFrom:   current.ValidFrom,
+		ValidUntil:  current.ValidUntil,
+	}
+	if request.Name != nil {
+		update.Name = strings.TrimSpace(*request.Name)
+	}
+	if hasJSONField(fields, "description") {
+		update.Description = nil
+		if request.Description != nil {
+			update.Description = normalizeOptionalText(*request.Description)
+		}
+	}
+	if request.Priority != nil {
+		update.Priority = *request.Priority
+	}
+	if request.IsActive != nil {
+		update.IsActive = *request.IsActive
+	}
+	// An explicit null opens the validity window on that side.
+	if hasJSONField(fields, "validFrom") {
+		update.ValidFrom = timestamptzFromPtr(request.ValidFrom)
+	}
+	if hasJSONField(fields, "validUntil") {
+		update.ValidUntil = timestamptzFromPtr(request.ValidUntil)
+	}
+	if message := validatePriceList(update.Name, update.ValidFrom, update.ValidUntil); message != "" {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", message)
+		return
+	}
+
+	row, err := h.PricingStore.UpdatePriceList(c.Request.Context(), update)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusNotFound, "not_found", "price list not found")
+			return
+		}
+		h.logError("update price list failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to update price list")
+		return
+	}
+	c.JSON(http.StatusOK, priceListFromModel(row))
+}
+
+// PutAdminPriceListsPriceListIdAssignments replaces the customers and
+// customer tags a price list applies to.
+func (h *Handler) PutAdminPriceListsPriceListIdAssignments(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionPricingManage); !ok {
+		return
+	}
+	current, ok := h.loadPriceList(c)
+	if !ok {
+		return
+	}
+
+	var request priceListAssignmentsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	customerIDs := uniqueUUIDs(request.CustomerIDs)
+	tagIDs := uniqueUUIDs(request.CustomerTagIDs)
+	for _, id := range append(append([]uuid.UUID{}, customerIDs...), tagIDs...) {
+		if id == uuid.Nil {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid customerIds or customerTagIds")
+			return
+		}
+	}
+	if h.DB == nil {
+		h.logError("assign price list failed", errors.New("db pool is nil"))
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to assign price list")
+		return
+	}
+
+	ctx := c.Request.Context()
+	err := shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		q := db.New(tx)
+		if err := q.DeletePriceListAssignments(ctx, current.ID); err != nil {
+			return err
+		}
+		for _, customerID := range customerIDs {
+			if _, err := q.CreatePriceListAssignment(ctx, db.CreatePriceListAssignmentParams{
+				PriceListID: current.ID,
+				CustomerID:  pgtype.UUID{Bytes: customerID, Valid: true},
+			}); err != nil {
+				return err
+			}
+		}
+		for _, tagID := range tagIDs {
+			if _, err := q.CreatePriceListAssignment(ctx, db.CreatePriceListAssignmentParams{
+				PriceListID:   current.ID,
+				CustomerTagID: pgtype.UUID{Bytes: tagID, Valid: true},
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		h.logError("assign price list failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to assign price list")
+		return
+	}
+	h.writePriceListDetail(c, current)
+}
+
+func (h *Handler) PostAdminPriceListsPriceListIdRules(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionPricingManage); !ok {
+		return
+	}
+	current, ok := h.loadPriceList(c)
+	if !ok {
+		return
+	}
+
+	var request priceListRuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	params, tiers, message := priceListRuleParams(current.ID, request)
+	if message != "" {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", message)
+		return
+	}
+	if h.DB == nil {
+		h.logError("create price list rule failed", errors.New("db pool is nil"))
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to create price list rule")
+		return
+	}
+
+	ctx := c.Request.Context()
+	var (
+		rule      db.PriceListRule
+		ruleTiers []db.PriceListRuleTier
+	)
+	err := shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		q := db.New(tx)
+		var err error
+		rule, err = q.CreatePriceListRule(ctx, params)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return errPriceListRuleExists
+			}
+			return err
+		}
+		for _, tier := range tiers {
+			created, err := q.CreatePriceListRuleTier(ctx, db.CreatePriceListRuleTierParams{
+				RuleID:       rule.ID,
+				MinQty:       tier.MinQty,
+				MaxQty:       tier.MaxQty,
+				UnitPriceFen: tier.UnitPriceFen,
+			})
+			if err != nil {
+				return err
+			}
+			ruleTiers = append(ruleTiers, created)
+		}
+		return nil
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		switch {
+		case errors.Is(err, errPriceListRuleExists):
+			h.writeError(c, http.StatusConflict, "price_list_rule_exists", err.Error())
+		case errors.As(err, &pgErr) && pgErr.Code == "23503":
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "sku or category not found")
+		default:
+			h.logError("create price list rule failed", err)
+			h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to create price list rule")
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, priceListRuleFromModel(rule, ruleTiers))
+}
+
+func (h *Handler) DeleteAdminPriceListsPriceListIdRulesRuleId(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionPricingManage); !ok {
+		return
+	}
+	priceListID, ok := h.parsePriceListPathID(c, "priceListId")
+	if !ok {
+		return
+	}
+	ruleID, ok := h.parsePriceListPathID(c, "ruleId")
+	if !ok {
+		return
+	}
+	deleted, err := h.PricingStore.DeletePriceListRule(c.Request.Context(), db.DeletePriceListRuleParams{
+		ID:          ruleID,
+		PriceListID: priceListID,
+	})
+	if err != nil {
+		h.logError("delete price list rule failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to delete price list rule")
+		return
+	}
+	if deleted == 0 {
+		h.writeError(c, http.StatusNotFound, "not_found", "price list rule not found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetAdminPricingPreview shows what a customer pays for a SKU at a quantity
+// right now and which price list rule decided it, with every rule that
+// matched in the order they were considered.
+func (h *Handler) GetAdminPricingPreview(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionPricingManage); !ok {
+		return
+	}
+	if h.PricingStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "price lists are not configured")
+		return
+	}
+	customerID, err := uuid.Parse(strings.TrimSpace(c.Query("customerId")))
+	if err != nil || customerID == uuid.Nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid customerId")
+		return
+	}
+	skuID, err := uuid.Parse(strings.TrimSpace(c.Query("skuId")))
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid skuId")
+		return
+	}
+	qty := int32(1)
+	if raw := strings.TrimSpace(c.Query("qty")); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "qty must be >= 1")
+			return
+		}
+		qty = clampInt32(parsed)
+	}
+
+	ctx := c.Request.Context()
+	skus, err := h.CatalogStore.ListSkusByIDs(ctx, []uuid.UUID{skuID})
+	if err != nil {
+		h.logError("list skus failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to preview price")
+		return
+	}
+	if len(skus) == 0 {
+		h.writeError(c, http.StatusNotFound, "not_found", "sku not found")
+		return
+	}
+	tiers, err := h.CatalogStore.ListPriceTiersBySkus(ctx, []uuid.UUID{skuID})
+	if err != nil {
+		h.logError("list price tiers failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to preview price")
+		return
+	}
+	buyer, err := h.customerBuyer(ctx, customerID)
+	if err != nil {
+		h.logError("get customer tags failed", err)
+		h.writeError(c, http.StatusBadGateway, "identity_unavailable", "unable to list customer tags")
+		return
+	}
+	prices, err := h.resolvePrices(ctx, buyer, []uuid.UUID{skuID}, tiersBySkuID(tiers))
+	if err != nil {
+		h.logError("resolve sku prices failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to preview price")
+		return
+	}
+	price := prices[skuID]
+
+	response := pricingPreviewResponse{
+		CustomerID:     customerID,
+		CustomerTagIDs: buyer.TagIDs,
+		SkuID:          skuID,
+		Qty:            qty,
+		BasePriceTiers: priceTiersFromModel(price.BaseTiers),
+		PriceTiers:     priceTiersFromModel(price.Tiers),
+		Candidates:     make([]pricingCandidateResponse, 0, len(price.Candidates)),
+	}
+	if response.CustomerTagIDs == nil {
+		response.CustomerTagIDs = []uuid.UUID{}
+	}
+	if unitPrice, ok := selectUnitPrice(price.BaseTiers, qty); ok {
+		value := unitPrice.Int64()
+		response.BaseUnitPriceFen = &value
+	}
+	if unitPrice, ok := selectUnitPrice(price.Tiers, qty); ok {
+		value := unitPrice.Int64()
+		amount := value * int64(qty)
+		response.UnitPriceFen = &value
+		response.AmountFen = &amount
+	}
+	for _, candidate := range price.Candidates {
+		mapped := pricingCandidateFromModel(candidate)
+		mapped.Applied = price.Rule != nil && price.Rule.RuleID == candidate.RuleID
+		if mapped.Applied {
+			applied := mapped
+			response.AppliedRule = &applied
+		}
+		response.Candidates = append(response.Candidates, mapped)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// loadPriceList fetches the price list named in the path.
+func (h *Handler) loadPriceList(c *gin.Context) (db.PriceList, bool) {
+	priceListID, ok := h.parsePriceListPathID(c, "priceListId")
+	if !ok {
+		return db.PriceList{}, false
+	}
+	row, err := h.PricingStore.GetPriceList(c.Request.Context(), priceListID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusNotFound, "not_found", "price list not found")
+			return db.PriceList{}, false
+		}
+		h.logError("get price list failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch price list")
+		return db.PriceList{}, false
+	}
+	return row, true
+}
+
+func (h *Handler) parsePriceListPathID(c *gin.Context, name string) (uuid.UUID, bool) {
+	if h.PricingStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "price lists are not configured")
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(strings.TrimSpace(c.Param(name)))
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid "+name)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *Handler) writePriceListDetail(c *gin.Context, row db.PriceList) {
+	ctx := c.Request.Context()
+	assignments, err := h.PricingStore.ListPriceListAssignments(ctx, row.ID)
+	if err != nil {
+		h.logError("list price list assignments failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch price list")
+		return
+	}
+	rules, err := h.PricingStore.ListPriceListRules(ctx, row.ID)
+	if err != nil {
+		h.logError("list price list rules failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch price list")
+		return
+	}
+	tiersByRule := map[uuid.UUID][]db.PriceListRuleTier{}
+	if len(rules) > 0 {
+		ruleIDs := make([]uuid.UUID, 0, len(rules))
+		for _, rule := range rules {
+			ruleIDs = append(ruleIDs, rule.ID)
+		}
+		tiers, err := h.PricingStore.ListPriceListRuleTiersByRules(ctx, ruleIDs)
+		if err != nil {
+			h.logError("list price list rule tiers failed", err)
+			h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch price list")
+			return
+		}
+		for _, tier := range tiers {
+			tiersByRule[tier.RuleID] = append(tiersByRule[tier.RuleID], tier)
+		}
+	}
+
+	response := priceListDetailResponse{
+		priceListResponse: priceListFromModel(row),
+		CustomerIDs:       []uuid.UUID{},
+		CustomerTagIDs:    []uuid.UUID{},
+		Rules:             make([]priceListRuleResponse, 0, len(rules)),
+	}
+	for _, assignment := range assignments {
+		if assignment.CustomerID.Valid {
+			response.CustomerIDs = append(response.CustomerIDs, uuid.UUID(assignment.CustomerID.Bytes))
+		}
+		if assignment.CustomerTagID.Valid {
+			response.CustomerTagIDs = append(response.CustomerTagIDs, uuid.UUID(assignment.CustomerTagID.Bytes))
+		}
+	}
+	for _, rule := range rules {
+		response.Rules = append(response.Rules, priceListRuleFromModel(rule, tiersByRule[rule.ID]))
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func validatePriceList(name string, validFrom, validUntil pgtype.Timestamptz) string {
+	if name == "" {
+		return "name is required"
+	}
+	if len([]rune(name)) > maxPriceListNameLength {
+		return "name supports at most 100 characters"
+	}
+	if validFrom.Valid && validUntil.Valid && !validUntil.Time.After(validFrom.Time) {
+		return "validUntil must be after validFrom"
+	}
+	return ""
+}
+
+// priceListRuleParams validates a rule request. A rule targets either a SKU
+// or a category; fixed tiers only make sense for a single SKU.
+func priceListRuleParams(priceListID uuid.UUID, request priceListRuleRequest) (db.CreatePriceListRuleParams, []pricing.Tier, string) {
+	params := db.CreatePriceListRuleParams{
+		PriceListID: priceListID,
+		Kind:        strings.ToUpper(strings.TrimSpace(request.Kind)),
+	}
+	if (request.SkuID == nil) == (request.CategoryID == nil) {
+		return params, nil, "exactly one of skuId and categoryId is required"
+	}
+	if request.SkuID != nil {
+		params.SkuID = pgtype.UUID{Bytes: *request.SkuID, Valid: true}
+	}
+	if request.CategoryID != nil {
+		params.CategoryID = pgtype.UUID{Bytes: *request.CategoryID, Valid: true}
+	}
+
+	switch params.Kind {
+	case pricing.KindFixedTiers:
+		if request.SkuID == nil {
+			return params, nil, "FIXED_TIERS rules require skuId"
+		}
+		if request.DiscountBps != nil {
+			return params, nil, "discountBps is only allowed for PERCENT_OFF"
+		}
+		tiers := make([]pricing.Tier, 0, len(request.Tiers))
+		for _, tier := range request.Tiers {
+			tiers = append(tiers, pricing.Tier{MinQty: tier.MinQty, MaxQty: tier.MaxQty, UnitPriceFen: tier.UnitPriceFen})
+		}
+		if err := pricing.ValidateTiers(tiers); err != nil {
+			return params, nil, err.Error()
+		}
+		return params, tiers, ""
+	case pricing.KindPercentOff:
+		if request.DiscountBps == nil || *request.DiscountBps < 1 || *request.DiscountBps > pricing.MaxDiscountBps {
+			return params, nil, "discountBps must be between 1 and 10000"
+		}
+		if len(request.Tiers) > 0 {
+			return params, nil, "tiers are only allowed for FIXED_TIERS"
+		}
+		params.DiscountBps = request.DiscountBps
+		return params, nil, ""
+	default:
+		return params, nil, "kind must be FIXED_TIERS or PERCENT_OFF"
+	}
+}
+
+func timestamptzFromPtr(value *time.Time) pgtype.Timestamptz {
+	if value == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *value, Valid: true}
+}
+
+func priceListFromModel(row db.PriceList) priceListResponse {
+	return priceListResponse{
+		ID:          row.ID,
+		Name:        row.Name,
+		Description: row.Description,
+		Priority:    row.Priority,
+		IsActive:    row.IsActive,
+		ValidFrom:   timePtrFromPg(row.ValidFrom),
+		ValidUntil:  timePtrFromPg(row.ValidUntil),
+		CreatedBy:   row.CreatedBy,
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}
+}
+
+func priceListRuleFromModel(rule db.PriceListRule, tiers []db.PriceListRuleTier) priceListRuleResponse {
+	response := priceListRuleResponse{
+		ID:          rule.ID,
+		SkuID:       uuidPtrFromPgtype(rule.SkuID),
+		CategoryID:  uuidPtrFromPgtype(rule.CategoryID),
+		Kind:        rule.Kind,
+		DiscountBps: rule.DiscountBps,
+		Tiers:       make([]oapi.PriceTier, 0, len(tiers)),
+		CreatedAt:   rule.CreatedAt.Time,
+	}
+	for _, tier := range tiers {
+		entry := oapi.PriceTier{MinQty: int(tier.MinQty), UnitPriceFen: tier.UnitPriceFen}
+		if tier.MaxQty != nil {
+			value := int(*tier.MaxQty)
+			entry.MaxQty = &value
+		}
+		response.Tiers = append(response.Tiers, entry)
+	}
+	return response
+}
+
+func pricingCandidateFromModel(row db.ListApplicablePriceListRulesRow) pricingCandidateResponse {
+	return pricingCandidateResponse{
+		PriceListID:   row.PriceListID,
+		PriceListName: row.PriceListName,
+		Priority:      row.Priority,
+		RuleID:        row.RuleID,
+		Kind:          row.Kind,
+		DiscountBps:   row.DiscountBps,
+		SkuID:         uuidPtrFromPgtype(row.SkuID),
+		CategoryID:    uuidPtrFromPgtype(row.CategoryID),
+		MatchedBy:     pricing.MatchedBy(row),
+		CustomerTagID: uuidPtrFromPgtype(row.MatchedCustomerTagID),
+	}
+}
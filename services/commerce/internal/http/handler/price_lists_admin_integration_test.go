@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/pricing"
+)
+
+type stubCustomerTags map[uuid.UUID][]uuid.UUID
+
+func (s stubCustomerTags) GetCustomerTagIDs(_ context.Context, customerID uuid.UUID) ([]uuid.UUID, error) {
+	return s[customerID], nil
+}
+
+func TestPriceListsApplyToCustomerPrices(t *testing.T) {
+	pool := openHandlerTestPool(t)
+	resetCommerceTables(t, pool)
+
+	queries := db.New(pool)
+	skuA, skuB := seedCatalog(t, queries)
+	ctx := context.Background()
+	product, err := queries.GetProduct(ctx, skuA.ProductID)
+	if err != nil {
+		t.Fatalf("get product: %v", err)
+	}
+	customerID := uuid.New()
+	otherCustomerID := uuid.New()
+	vipTagID := uuid.New()
+	router := newServerIntegrationRouter(pool, queries, stubCustomerTags{customerID: {vipTagID}})
+	adminToken := makeAuthToken(t, uuid.New(), "ADMIN", nil)
+	customerToken := makeAuthToken(t, customerID, "CUSTOMER", nil)
+	otherToken := makeAuthToken(t, otherCustomerID, "CUSTOMER", nil)
+
+	doJSONRequest(t, router, http.MethodPost, "/admin/price-lists", makeAuthToken(t, uuid.New(), "CUSTOMER", nil), `{"name":"VIP"}`, http.StatusForbidden, nil)
+	doJSONRequest(t, router, http.MethodPost, "/admin/price-lists", adminToken, `{"name":"  "}`, http.StatusBadRequest, nil)
+
+	// A VIP tag list takes 10% off the whole category, and a list for the
+	// customer alone fixes the price of skuB.
+	var vip priceListResponse
+	doJSONRequest(t, router, http.MethodPost, "/admin/price-lists", adminToken, `{"name":"VIP","priority":10}`, http.StatusCreated, &vip)
+	doJSONRequest(t, router, http.MethodPut, "/admin/price-lists/"+vip.ID.String()+"/assignments", adminToken,
+		fmt.Sprintf(`{"customerTagIds":["%s"]}`, vipTagID), http.StatusOK, nil)
+	doJSONRequest(t, router, http.MethodPost, "/admin/price-lists/"+vip.ID.String()+"/rules", adminToken,
+		fmt.Sprintf(`{"categoryId":"%s","kind":"PERCENT_OFF","discountBps":1000}`, product.CategoryID), http.StatusCreated, nil)
+	doJSONRequest(t, router, http.MethodPost, "/admin/price-lists/"+vip.ID.String()+"/rules", adminToken,
+		fmt.Sprintf(`{"categoryId":"%s","kind":"PERCENT_OFF","discountBps":2000}`, product.CategoryID), http.StatusConflict, nil)
+	doJSONRequest(t, router, http.MethodPost, "/admin/price-lists/"+vip.ID.String()+"/rules", adminToken,
+		fmt.Sprintf(`{"categoryId":"%s","kind":"FIXED_TIERS","tiers":[{"minQty":1,"unitPriceFen":100}]}`, product.CategoryID), http.StatusBadRequest, nil)
+
+	var contract priceListResponse
+	doJSONRequest(t, router, http.MethodPost, "/admin/price-lists", adminToken, `{"name":"Contract"}`, http.StatusCreated, &contract)
+	doJSONRequest(t, router, http.MethodPut, "/admin/price-lists/"+contract.ID.String()+"/assignments", adminToken,
+		fmt.Sprintf(`{"customerIds":["%s"]}`, customerID), http.StatusOK, nil)
+	doJSONRequest(t, router, http.MethodPost, "/admin/price-lists/"+contract.ID.String()+"/rules", adminToken,
+		fmt.Sprintf(`{"skuId":"%s","kind":"FIXED_TIERS","tiers":[{"minQty":1,"maxQty":9,"unitPriceFen":17000},{"minQty":1,"unitPriceFen":1}]}`, skuB.ID), http.StatusBadRequest, nil)
+	var fixedRule priceListRuleResponse
+	doJSONRequest(t, router, http.MethodPost, "/admin/price-lists/"+contract.ID.String()+"/rules", adminToken,
+		fmt.Sprintf(`{"skuId":"%s","kind":"FIXED_TIERS","tiers":[{"minQty":10,"unitPriceFen":15000},{"minQty":1,"maxQty":9,"unitPriceFen":17000}]}`, skuB.ID), http.StatusCreated, &fixedRule)
+
+	var detail priceListDetailResponse
+	doJSONRequest(t, router, http.MethodGet, "/admin/price-lists/"+contract.ID.String(), adminToken, "", http.StatusOK, &detail)
+	if len(detail.CustomerIDs) != 1 || detail.CustomerIDs[0] != customerID || len(detail.Rules) != 1 || len(detail.Rules[0].Tiers) != 2 || detail.Rules[0].Tiers[0].MinQty != 1 {
+		t.Fatalf("unexpected price list detail %+v", detail)
+	}
+
+	var preview pricingPreviewResponse
+	doJSONRequest(t, router, http.MethodGet, fmt.Sprintf("/admin/pricing/preview?customerId=%s&skuId=%s&qty=10", customerID, skuB.ID), adminToken, "", http.StatusOK, &preview)
+	if preview.UnitPriceFen == nil || *preview.UnitPriceFen != 15000 || *preview.BaseUnitPriceFen != 18000 || *preview.AmountFen != 150000 {
+		t.Fatalf("unexpected preview prices %+v", preview)
+	}
+	// The customer's own list beats the higher-priority tag list.
+	if preview.AppliedRule == nil || preview.AppliedRule.RuleID != fixedRule.ID || preview.AppliedRule.MatchedBy != pricing.MatchedByCustomer || len(preview.Candidates) != 2 {
+		t.Fatalf("unexpected preview rules %+v", preview)
+	}
+
+	var productDetail oapi.ProductDetail
+	doJSONRequest(t, router, http.MethodGet, "/catalog/products/"+product.ID.String(), customerToken, "", http.StatusOK, &productDetail)
+	for _, sku := range productDetail.Skus {
+		if sku.Id == skuA.ID && (sku.PriceTiers == nil || (*sku.PriceTiers)[0].UnitPriceFen != 10800 || sku.AppliedPriceList == nil || sku.AppliedPriceList.Name != "VIP") {
+			t.Fatalf("expected VIP price on sku A, got %+v", sku)
+		}
+	}
+	doJSONRequest(t, router, http.MethodGet, "/catalog/products/"+product.ID.String(), otherToken, "", http.StatusOK, &productDetail)
+	for _, sku := range productDetail.Skus {
+		if sku.AppliedPriceList != nil {
+			t.Fatalf("expected catalog prices for other customer, got %+v", sku)
+		}
+	}
+
+	cartItem, err := queries.UpsertCartItem(ctx, db.UpsertCartItemParams{OwnerUserID: customerID, SkuID: skuA.ID, Qty: 2})
+	if err != nil {
+		t.Fatalf("seed cart item: %v", err)
+	}
+	var order oapi.Order
+	doJSONRequest(t, router, http.MethodPost, "/orders", customerToken,
+		fmt.Sprintf(`{"address":{"receiverName":"A","receiverPhone":"1","detail":"X"},"items":[{"cartItemId":"%s","skuId":"%s","qty":2}]}`, cartItem.ID, skuA.ID), http.StatusCreated, &order)
+	if len(order.Items) != 1 || order.Items[0].UnitPriceFen != 10800 {
+		t.Fatalf("expected order at the VIP price, got %+v", order.Items)
+	}
+
+	// Deactivating the list restores catalog prices.
+	doJSONRequest(t, router, http.MethodPatch, "/admin/price-lists/"+vip.ID.String(), adminToken, `{"isActive":false}`, http.StatusOK, nil)
+	doJSONRequest(t, router, http.MethodGet, fmt.Sprintf("/admin/pricing/preview?customerId=%s&skuId=%s", customerID, skuA.ID), adminToken, "", http.StatusOK, &preview)
+	if preview.AppliedRule != nil || *preview.UnitPriceFen != 12000 {
+		t.Fatalf("expected catalog price after deactivation, got %+v", preview)
+	}
+
+	doJSONRequest(t, router, http.MethodDelete, "/admin/price-lists/"+contract.ID.String()+"/rules/"+fixedRule.ID.String(), adminToken, "", http.StatusNoContent, nil)
+	doJSONRequest(t, router, http.MethodDelete, "/admin/price-lists/"+contract.ID.String()+"/rules/"+fixedRule.ID.String(), adminToken, "", http.StatusNotFound, nil)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/middleware"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/pricing"
+)
+
+// pricingBuyer is whose price lists apply to a caller. Only customers get
+// customer prices; staff browse at catalog prices.
+func (h *Handler) pricingBuyer(ctx context.Context, claims middleware.Claims) (pricing.Buyer, error) {
+	if h.PricingStore == nil || claims.UserID == uuid.Nil || !strings.EqualFold(claims.Role, "CUSTOMER") {
+		return pricing.Buyer{}, nil
+	}
+	return h.customerBuyer(ctx, claims.UserID)
+}
+
+func (h *Handler) customerBuyer(ctx context.Context, customerID uuid.UUID) (pricing.Buyer, error) {
+	buyer := pricing.Buyer{CustomerID: customerID}
+	if h.CustomerTags == nil {
+		return buyer, nil
+	}
+	tagIDs, err := h.CustomerTags.GetCustomerTagIDs(ctx, customerID)
+	if err != nil {
+		return buyer, err
+	}
+	buyer.TagIDs = tagIDs
+	return buyer, nil
+}
+
+// displayBuyer is pricingBuyer for responses that only show prices: when
+// identity cannot list the customer's tags, the price lists assigned to the
+// customer directly still apply.
+func (h *Handler) displayBuyer(ctx context.Context, claims middleware.Claims) pricing.Buyer {
+	buyer, err := h.pricingBuyer(ctx, claims)
+	if err != nil {
+		h.logError("get customer tags failed", err)
+	}
+	return buyer
+}
+
+// resolvePrices prices skuIDs for buyer from their catalog tiers.
+func (h *Handler) resolvePrices(ctx context.Context, buyer pricing.Buyer, skuIDs []uuid.UUID, tiersBySku map[uuid.UUID][]db.CatalogPriceTier) (map[uuid.UUID]pricing.Price, error) {
+	if h.PricingStore == nil {
+		buyer = pricing.Buyer{}
+	}
+	return pricing.Resolve(ctx, h.PricingStore, buyer, skuIDs, tiersBySku, time.Now())
+}
+
+func tiersBySkuID(tiers []db.CatalogPriceTier) map[uuid.UUID][]db.CatalogPriceTier {
+	grouped := map[uuid.UUID][]db.CatalogPriceTier{}
+	for _, tier := range tiers {
+		grouped[tier.SkuID] = append(grouped[tier.SkuID], tier)
+	}
+	return grouped
+}
+
+// pricedSkuFromModel maps a SKU with the tiers its buyer pays.
+func pricedSkuFromModel(sku db.CatalogSku, price pricing.Price, stock *db.SkuInventory) (oapi.SKU, error) {
+	response, err := skuFromModel(sku, price.Tiers, stock)
+	if err != nil {
+		return oapi.SKU{}, err
+	}
+	if price.Rule != nil {
+		response.AppliedPriceList = &oapi.AppliedPriceList{
+			Id:   price.Rule.PriceListID,
+			Name: price.Rule.PriceListName,
+		}
+	}
+	return response, nil
+}
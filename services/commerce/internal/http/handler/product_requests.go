@@ -15,6 +15,7 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
 	"github.com/teamdsb/tmo/services/commerce/internal/db"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
 	"github.com/teamdsb/tmo/services/commerce/internal/mediaopt"
@@ -39,7 +40,7 @@ func isCategoryForeignKeyViolation(err error) bool {
 }
 
 func (h *Handler) GetProductRequests(c *gin.Context, params oapi.GetProductRequestsParams) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "SALES", "CS", "MANAGER", "BOSS", "ADMIN")
+	claims, scope, ok := h.requirePermission(c, authz.PermissionProductRequestRead)
 	if !ok {
 		return
 	}
@@ -57,15 +58,7 @@ func (h *Handler) GetProductRequests(c *gin.Context, params oapi.GetProductReque
 	}
 	offset := (page - 1) * pageSize
 
-	role := strings.ToUpper(claims.Role)
-	createdByFilter := pgtype.UUID{}
-	ownerSalesFilter := pgtype.UUID{}
-	switch role {
-	case "CUSTOMER":
-		createdByFilter = pgtype.UUID{Bytes: claims.UserID, Valid: true}
-	case "SALES":
-		ownerSalesFilter = pgtype.UUID{Bytes: claims.UserID, Valid: true}
-	}
+	createdByFilter, ownerSalesFilter := scopeFilters(claims, scope)
 
 	createdAfter := pgtype.Timestamptz{}
 	if params.CreatedAfter != nil {
@@ -125,7 +118,7 @@ func (h *Handler) GetProductRequests(c *gin.Context, params oapi.GetProductReque
 }
 
 func (h *Handler) PostProductRequests(c *gin.Context) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "ADMIN")
+	claims, _, ok := h.requirePermission(c, authz.PermissionProductRequestCreate)
 	if !ok {
 		return
 	}
@@ -208,7 +201,7 @@ func productRequestFromModel(request db.ProductRequest) oapi.ProductRequest {
 }
 
 func (h *Handler) PostProductRequestsAssets(c *gin.Context) {
-	if _, ok := h.requireRole(c, "CUSTOMER", "ADMIN"); !ok {
+	if _, _, ok := h.requirePermission(c, authz.PermissionProductRequestCreate); !ok {
 		return
 	}
 
@@ -216,7 +209,7 @@ func (h *Handler) PostProductRequestsAssets(c *gin.Context) {
 }
 
 func (h *Handler) PostAdminCatalogProductsAssets(c *gin.Context) {
-	if _, ok := h.requireRole(c, "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionProductManage); !ok {
 		return
 	}
 
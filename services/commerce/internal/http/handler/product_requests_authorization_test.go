@@ -63,7 +63,7 @@ func TestGetProductRequestsAuthorizationScopes(t *testing.T) {
 			store := &productRequestAuthorizationStore{}
 			handler := &Handler{
 				ProductRequestStore: store,
-				Auth:                middleware.NewAuthenticator(true, testJWTSecret, testJWTIssuer),
+				Auth:                middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer),
 			}
 			router := httpx.NewRouter()
 			router.GET("/product-requests", func(c *gin.Context) {
@@ -419,7 +419,7 @@ func TestAdminCatalogProductAssetUpload(t *testing.T) {
 func newAuthRouterWithMedia(store *db.Queries, mediaLocalDir, mediaBaseURL string) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := httpx.NewRouter()
-	authenticator := middleware.NewAuthenticator(true, testJWTSecret, testJWTIssuer)
+	authenticator := middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer)
 	oapi.RegisterHandlers(router, &Handler{
 		CatalogStore:        store,
 		CartStore:           store,
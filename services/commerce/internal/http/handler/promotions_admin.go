@@ -0,0 +1,549 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/promotion"
+)
+
+const (
+	maxPromotionNameLength = 100
+	maxCouponIssueCount    = 1000
+	// maxCouponCodeAttempts bounds how often a code is regenerated when it
+	// collides with an existing one.
+	maxCouponCodeAttempts = 5
+)
+
+var errCouponCodeCollision = errors.New("unable to generate unique coupon codes")
+
+type promotionRequest struct {
+	Name             *string    `json:"name"`
+	Description      *string    `json:"description"`
+	Kind             string     `json:"kind"`
+	DiscountType     string     `json:"discountType"`
+	DiscountValue    int64      `json:"discountValue"`
+	MaxDiscountFen   *int64     `json:"maxDiscountFen"`
+	MinSpendFen      *int64     `json:"minSpendFen"`
+	CategoryID       *uuid.UUID `json:"categoryId"`
+	Stackable        *bool      `json:"stackable"`
+	PerCustomerLimit *int32     `json:"perCustomerLimit"`
+	IsActive         *bool      `json:"isActive"`
+	StartsAt         *time.Time `json:"startsAt"`
+	EndsAt           *time.Time `json:"endsAt"`
+}
+
+// promotionUpdateRequest holds what can change once a promotion exists; the
+// discount itself is fixed so orders that redeemed it stay explainable.
+type promotionUpdateRequest struct {
+	Name             *string    `json:"name"`
+	Description      *string    `json:"description"`
+	Stackable        *bool      `json:"stackable"`
+	PerCustomerLimit *int32     `json:"perCustomerLimit"`
+	IsActive         *bool      `json:"isActive"`
+	StartsAt         *time.Time `json:"startsAt"`
+	EndsAt           *time.Time `json:"endsAt"`
+}
+
+type couponIssueRequest struct {
+	Count          int    `json:"count"`
+	Prefix         string `json:"prefix"`
+	MaxRedemptions *int32 `json:"maxRedemptions"`
+}
+
+type promotionResponse struct {
+	ID               uuid.UUID  `json:"id"`
+	Name             string     `json:"name"`
+	Description      *string    `json:"description,omitempty"`
+	Kind             string     `json:"kind"`
+	DiscountType     string     `json:"discountType"`
+	DiscountValue    int64      `json:"discountValue"`
+	MaxDiscountFen   *int64     `json:"maxDiscountFen,omitempty"`
+	MinSpendFen      int64      `json:"minSpendFen"`
+	CategoryID       *uuid.UUID `json:"categoryId,omitempty"`
+	Stackable        bool       `json:"stackable"`
+	PerCustomerLimit *int32     `json:"perCustomerLimit,omitempty"`
+	IsActive         bool       `json:"isActive"`
+	StartsAt         *time.Time `json:"startsAt,omitempty"`
+	EndsAt           *time.Time `json:"endsAt,omitempty"`
+	CreatedBy        uuid.UUID  `json:"createdBy"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+}
+
+type promotionListResponse struct {
+	Items    []promotionResponse `json:"items"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"pageSize"`
+	Total    int64               `json:"total"`
+}
+
+type couponResponse struct {
+	ID             uuid.UUID `json:"id"`
+	PromotionID    uuid.UUID `json:"promotionId"`
+	Code           string    `json:"code"`
+	MaxRedemptions int32     `json:"maxRedemptions"`
+	RedeemedCount  int64     `json:"redeemedCount"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type couponIssueResponse struct {
+	Items []couponResponse `json:"items"`
+}
+
+type couponListResponse struct {
+	Items    []couponResponse `json:"items"`
+	Page     int              `json:"page"`
+	PageSize int              `json:"pageSize"`
+	Total    int64            `json:"total"`
+}
+
+func (h *Handler) PostAdminPromotions(c *gin.Context) {
+	claims, ok := h.requireAllScope(c, authz.PermissionPromotionManage)
+	if !ok {
+		return
+	}
+	if h.PromotionStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "promotions are not configured")
+		return
+	}
+
+	var request promotionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	params := db.CreatePromotionParams{
+		Kind:             strings.ToUpper(strings.TrimSpace(request.Kind)),
+		DiscountType:     strings.ToUpper(strings.TrimSpace(request.DiscountType)),
+		DiscountValue:    request.DiscountValue,
+		MaxDiscountFen:   request.MaxDiscountFen,
+		PerCustomerLimit: request.PerCustomerLimit,
+		IsActive:         true,
+		StartsAt:         timestamptzFromPtr(request.StartsAt),
+		EndsAt:           timestamptzFromPtr(request.EndsAt),
+		CreatedBy:        claims.UserID,
+	}
+	if request.Name != nil {
+		params.Name = strings.TrimSpace(*request.Name)
+	}
+	if request.Description != nil {
+		params.Description = normalizeOptionalText(*request.Description)
+	}
+	if request.MinSpendFen != nil {
+		params.MinSpendFen = *request.MinSpendFen
+	}
+	if request.CategoryID != nil {
+		params.CategoryID = pgtype.UUID{Bytes: *request.CategoryID, Valid: true}
+	}
+	if request.Stackable != nil {
+		params.Stackable = *request.Stackable
+	}
+	if request.IsActive != nil {
+		params.IsActive = *request.IsActive
+	}
+	if message := validatePromotionDiscount(params); message != "" {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", message)
+		return
+	}
+	if message := validatePromotionSettings(params.Name, params.PerCustomerLimit, params.StartsAt, params.EndsAt); message != "" {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", message)
+		return
+	}
+
+	row, err := h.PromotionStore.CreatePromotion(c.Request.Context(), params)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "category not found")
+			return
+		}
+		h.logError("create promotion failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to create promotion")
+		return
+	}
+	c.JSON(http.StatusCreated, promotionFromModel(row))
+}
+
+func (h *Handler) GetAdminPromotions(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionPromotionManage); !ok {
+		return
+	}
+	if h.PromotionStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "promotions are not configured")
+		return
+	}
+
+	var kind *string
+	if raw := strings.ToUpper(strings.TrimSpace(c.Query("kind"))); raw != "" {
+		if raw != promotion.KindCoupon && raw != promotion.KindAutomatic {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "kind must be COUPON or AUTOMATIC")
+			return
+		}
+		kind = &raw
+	}
+	page := parseAdminPositiveInt(c.Query("page"), 1)
+	pageSize := parseAdminPositiveInt(c.Query("pageSize"), 20)
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	rows, err := h.PromotionStore.ListPromotions(c.Request.Context(), db.ListPromotionsParams{
+		Kind:   kind,
+		Limit:  clampInt32(pageSize),
+		Offset: clampInt32((page - 1) * pageSize),
+	})
+	if err != nil {
+		h.logError("list promotions failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list promotions")
+		return
+	}
+	total, err := h.PromotionStore.CountPromotions(c.Request.Context(), kind)
+	if err != nil {
+		h.logError("count promotions failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list promotions")
+		return
+	}
+
+	items := make([]promotionResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, promotionFromModel(row))
+	}
+	c.JSON(http.StatusOK, promotionListResponse{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	})
+}
+
+func (h *Handler) GetAdminPromotionsPromotionId(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionPromotionManage); !ok {
+		return
+	}
+	row, ok := h.loadPromotion(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, promotionFromModel(row))
+}
+
+func (h *Handler) PatchAdminPromotionsPromotionId(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionPromotionManage); !ok {
+		return
+	}
+	current, ok := h.loadPromotion(c)
+	if !ok {
+		return
+	}
+
+	var request promotionUpdateRequest
+	fields, err := decodeJSONFields(c, &request)
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	update := db.UpdatePromotionParams{
+		ID:               current.ID,
+		Name:             current.Name,
+		Description:      current.Description,
+		Stackable:        current.Stackable,
+		PerCustomerLimit: current.PerCustomerLimit,
+		IsActive:         current.IsActive,
+		StartsAt:         current.StartsAt,
+		EndsAt:           current.EndsAt,
+	}
+	if request.Name != nil {
+		update.Name = strings.TrimSpace(*request.Name)
+	}
+	if hasJSONField(fields, "description") {
+		update.Description = nil
+		if request.Description != nil {
+			update.Description = normalizeOptionalText(*request.Description)
+		}
+	}
+	if request.Stackable != nil {
+		update.Stackable = *request.Stackable
+	}
+	// An explicit null lifts the limit or opens the window on that side.
+	if hasJSONField(fields, "perCustomerLimit") {
+		update.PerCustomerLimit = request.PerCustomerLimit
+	}
+	if request.IsActive != nil {
+		update.IsActive = *request.IsActive
+	}
+	if hasJSONField(fields, "startsAt") {
+		update.StartsAt = timestamptzFromPtr(request.StartsAt)
+	}
+	if hasJSONField(fields, "endsAt") {
+		update.EndsAt = timestamptzFromPtr(request.EndsAt)
+	}
+	if message := validatePromotionSettings(update.Name, update.PerCustomerLimit, update.StartsAt, update.EndsAt); message != "" {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", message)
+		return
+	}
+
+	row, err := h.PromotionStore.UpdatePromotion(c.Request.Context(), update)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusNotFound, "not_found", "promotion not found")
+			return
+		}
+		h.logError("update promotion failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to update promotion")
+		return
+	}
+	c.JSON(http.StatusOK, promotionFromModel(row))
+}
+
+// PostAdminPromotionsPromotionIdCoupons issues a batch of random coupon
+// codes for a coupon promotion. The batch is created atomically.
+func (h *Handler) PostAdminPromotionsPromotionIdCoupons(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionPromotionManage); !ok {
+		return
+	}
+	current, ok := h.loadPromotion(c)
+	if !ok {
+		return
+	}
+	if current.Kind != promotion.KindCoupon {
+		h.writeError(c, http.StatusConflict, "promotion_not_coupon", "coupons can only be issued for COUPON promotions")
+		return
+	}
+
+	var request couponIssueRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	if request.Count < 1 || request.Count > maxCouponIssueCount {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "count must be between 1 and 1000")
+		return
+	}
+	prefix := promotion.NormalizeCode(request.Prefix)
+	if !promotion.ValidCodePrefix(prefix) {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "prefix supports at most 12 letters, digits or dashes")
+		return
+	}
+	maxRedemptions := int32(1)
+	if request.MaxRedemptions != nil {
+		maxRedemptions = *request.MaxRedemptions
+	}
+	if maxRedemptions < 1 {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "maxRedemptions must be >= 1")
+		return
+	}
+	if h.DB == nil {
+		h.logError("issue coupons failed", errors.New("db pool is nil"))
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to issue coupons")
+		return
+	}
+
+	ctx := c.Request.Context()
+	items := make([]couponResponse, 0, request.Count)
+	err := shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		q := db.New(tx)
+		for len(items) < request.Count {
+			coupon, err := createUniqueCoupon(ctx, q, current.ID, prefix, maxRedemptions)
+			if err != nil {
+				return err
+			}
+			items = append(items, couponFromModel(coupon, 0))
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errCouponCodeCollision) {
+			h.writeError(c, http.StatusConflict, "coupon_code_exhausted", err.Error())
+			return
+		}
+		h.logError("issue coupons failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to issue coupons")
+		return
+	}
+	c.JSON(http.StatusCreated, couponIssueResponse{Items: items})
+}
+
+func (h *Handler) GetAdminPromotionsPromotionIdCoupons(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionPromotionManage); !ok {
+		return
+	}
+	current, ok := h.loadPromotion(c)
+	if !ok {
+		return
+	}
+
+	page := parseAdminPositiveInt(c.Query("page"), 1)
+	pageSize := parseAdminPositiveInt(c.Query("pageSize"), 20)
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	rows, err := h.PromotionStore.ListCoupons(c.Request.Context(), db.ListCouponsParams{
+		PromotionID: current.ID,
+		Limit:       clampInt32(pageSize),
+		Offset:      clampInt32((page - 1) * pageSize),
+	})
+	if err != nil {
+		h.logError("list coupons failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list coupons")
+		return
+	}
+	total, err := h.PromotionStore.CountCoupons(c.Request.Context(), current.ID)
+	if err != nil {
+		h.logError("count coupons failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list coupons")
+		return
+	}
+
+	items := make([]couponResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, couponFromModel(db.Coupon{
+			ID:             row.ID,
+			PromotionID:    row.PromotionID,
+			Code:           row.Code,
+			MaxRedemptions: row.MaxRedemptions,
+			CreatedAt:      row.CreatedAt,
+		}, row.RedeemedCount))
+	}
+	c.JSON(http.StatusOK, couponListResponse{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	})
+}
+
+// createUniqueCoupon inserts a coupon with a fresh random code, generating
+// another one when the code is already taken.
+func createUniqueCoupon(ctx context.Context, q *db.Queries, promotionID uuid.UUID, prefix string, maxRedemptions int32) (db.Coupon, error) {
+	for attempt := 0; attempt < maxCouponCodeAttempts; attempt++ {
+		code, err := promotion.GenerateCode(prefix)
+		if err != nil {
+			return db.Coupon{}, err
+		}
+		coupon, err := q.CreateCoupon(ctx, db.CreateCouponParams{
+			PromotionID:    promotionID,
+			Code:           code,
+			MaxRedemptions: maxRedemptions,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		return coupon, err
+	}
+	return db.Coupon{}, errCouponCodeCollision
+}
+
+// loadPromotion fetches the promotion named in the path.
+func (h *Handler) loadPromotion(c *gin.Context) (db.Promotion, bool) {
+	if h.PromotionStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "promotions are not configured")
+		return db.Promotion{}, false
+	}
+	promotionID, err := uuid.Parse(strings.TrimSpace(c.Param("promotionId")))
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid promotionId")
+		return db.Promotion{}, false
+	}
+	row, err := h.PromotionStore.GetPromotion(c.Request.Context(), promotionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusNotFound, "not_found", "promotion not found")
+			return db.Promotion{}, false
+		}
+		h.logError("get promotion failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch promotion")
+		return db.Promotion{}, false
+	}
+	return row, true
+}
+
+// validatePromotionDiscount checks what a promotion takes off. A percentage
+// may be capped at maxDiscountFen; a fixed amount is its own cap.
+func validatePromotionDiscount(params db.CreatePromotionParams) string {
+	if params.Kind != promotion.KindCoupon && params.Kind != promotion.KindAutomatic {
+		return "kind must be COUPON or AUTOMATIC"
+	}
+	switch params.DiscountType {
+	case promotion.DiscountFixed:
+		if params.DiscountValue < 1 {
+			return "discountValue must be >= 1 for FIXED"
+		}
+		if params.MaxDiscountFen != nil {
+			return "maxDiscountFen is only allowed for PERCENT"
+		}
+	case promotion.DiscountPercent:
+		if params.DiscountValue < 1 || params.DiscountValue > promotion.MaxPercentBps {
+			return "discountValue must be between 1 and 10000 for PERCENT"
+		}
+		if params.MaxDiscountFen != nil && *params.MaxDiscountFen < 1 {
+			return "maxDiscountFen must be >= 1"
+		}
+	default:
+		return "discountType must be FIXED or PERCENT"
+	}
+	if params.MinSpendFen < 0 {
+		return "minSpendFen must be >= 0"
+	}
+	return ""
+}
+
+func validatePromotionSettings(name string, perCustomerLimit *int32, startsAt, endsAt pgtype.Timestamptz) string {
+	if name == "" {
+		return "name is required"
+	}
+	if len([]rune(name)) > maxPromotionNameLength {
+		return "name supports at most 100 characters"
+	}
+	if perCustomerLimit != nil && *perCustomerLimit < 1 {
+		return "perCustomerLimit must be >= 1"
+	}
+	if startsAt.Valid && endsAt.Valid && !endsAt.Time.After(startsAt.Time) {
+		return "endsAt must be after startsAt"
+	}
+	return ""
+}
+
+func promotionFromModel(row db.Promotion) promotionResponse {
+	return promotionResponse{
+		ID:               row.ID,
+		Name:             row.Name,
+		Description:      row.Description,
+		Kind:             row.Kind,
+		DiscountType:     row.DiscountType,
+		DiscountValue:    row.DiscountValue,
+		MaxDiscountFen:   row.MaxDiscountFen,
+		MinSpendFen:      row.MinSpendFen,
+		CategoryID:       uuidPtrFromPgtype(row.CategoryID),
+		Stackable:        row.Stackable,
+		PerCustomerLimit: row.PerCustomerLimit,
+		IsActive:         row.IsActive,
+		StartsAt:         timePtrFromPg(row.StartsAt),
+		EndsAt:           timePtrFromPg(row.EndsAt),
+		CreatedBy:        row.CreatedBy,
+		CreatedAt:        row.CreatedAt.Time,
+		UpdatedAt:        row.UpdatedAt.Time,
+	}
+}
+
+func couponFromModel(row db.Coupon, redeemedCount int64) couponResponse {
+	return couponResponse{
+		ID:             row.ID,
+		PromotionID:    row.PromotionID,
+		Code:           row.Code,
+		MaxRedemptions: row.MaxRedemptions,
+		RedeemedCount:  redeemedCount,
+		CreatedAt:      row.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+)
+
+func TestPromotionsDiscountOrders(t *testing.T) {
+	pool := openHandlerTestPool(t)
+	resetCommerceTables(t, pool)
+
+	queries := db.New(pool)
+	skuA, _ := seedCatalog(t, queries)
+	ctx := context.Background()
+	product, err := queries.GetProduct(ctx, skuA.ProductID)
+	if err != nil {
+		t.Fatalf("get product: %v", err)
+	}
+	customerID := uuid.New()
+	router := newServerIntegrationRouter(pool, queries, nil)
+	adminToken := makeAuthToken(t, uuid.New(), "ADMIN", nil)
+	customerToken := makeAuthToken(t, customerID, "CUSTOMER", nil)
+
+	doJSONRequest(t, router, http.MethodPost, "/admin/promotions", customerToken,
+		`{"name":"Spring","kind":"AUTOMATIC","discountType":"FIXED","discountValue":1000}`, http.StatusForbidden, nil)
+	doJSONRequest(t, router, http.MethodPost, "/admin/promotions", adminToken,
+		`{"name":"Spring","kind":"AUTOMATIC","discountType":"PERCENT","discountValue":0}`, http.StatusBadRequest, nil)
+	doJSONRequest(t, router, http.MethodPost, "/admin/promotions", adminToken,
+		`{"name":"Spring","kind":"AUTOMATIC","discountType":"FIXED","discountValue":1000,"maxDiscountFen":500}`, http.StatusBadRequest, nil)
+	doJSONRequest(t, router, http.MethodPost, "/admin/promotions", adminToken,
+		fmt.Sprintf(`{"name":"Spring","kind":"AUTOMATIC","discountType":"FIXED","discountValue":1000,"categoryId":"%s"}`, uuid.New()), http.StatusBadRequest, nil)
+
+	// Two stackable automatic promotions: 10 yuan off 200 yuan and 5% off
+	// the category.
+	var threshold promotionResponse
+	doJSONRequest(t, router, http.MethodPost, "/admin/promotions", adminToken,
+		`{"name":"Spend 200 save 10","kind":"AUTOMATIC","discountType":"FIXED","discountValue":1000,"minSpendFen":20000,"stackable":true}`, http.StatusCreated, &threshold)
+	doJSONRequest(t, router, http.MethodPost, "/admin/promotions", adminToken,
+		fmt.Sprintf(`{"name":"Metals 5%%","kind":"AUTOMATIC","discountType":"PERCENT","discountValue":500,"categoryId":"%s","stackable":true}`, product.CategoryID), http.StatusCreated, nil)
+
+	var campaign promotionResponse
+	doJSONRequest(t, router, http.MethodPost, "/admin/promotions", adminToken,
+		`{"name":"Welcome","kind":"COUPON","discountType":"FIXED","discountValue":3000,"perCustomerLimit":1}`, http.StatusCreated, &campaign)
+	doJSONRequest(t, router, http.MethodPost, "/admin/promotions/"+threshold.ID.String()+"/coupons", adminToken, `{"count":1}`, http.StatusConflict, nil)
+	doJSONRequest(t, router, http.MethodPost, "/admin/promotions/"+campaign.ID.String()+"/coupons", adminToken, `{"count":1,"prefix":"vip_"}`, http.StatusBadRequest, nil)
+	var issued couponIssueResponse
+	doJSONRequest(t, router, http.MethodPost, "/admin/promotions/"+campaign.ID.String()+"/coupons", adminToken, `{"count":2,"prefix":"vip-"}`, http.StatusCreated, &issued)
+	if len(issued.Items) != 2 || !strings.HasPrefix(issued.Items[0].Code, "VIP-") || issued.Items[0].Code == issued.Items[1].Code {
+		t.Fatalf("unexpected coupons %+v", issued.Items)
+	}
+
+	placeOrder := func(couponCode string, wantStatus int) oapi.Order {
+		t.Helper()
+		cartItem, err := queries.UpsertCartItem(ctx, db.UpsertCartItemParams{OwnerUserID: customerID, SkuID: skuA.ID, Qty: 2})
+		if err != nil {
+			t.Fatalf("seed cart item: %v", err)
+		}
+		coupon := ""
+		if couponCode != "" {
+			coupon = fmt.Sprintf(`,"couponCode":"%s"`, couponCode)
+		}
+		var order oapi.Order
+		doJSONRequest(t, router, http.MethodPost, "/orders", customerToken,
+			fmt.Sprintf(`{"address":{"receiverName":"A","receiverPhone":"1","detail":"X"},"items":[{"cartItemId":"%s","skuId":"%s","qty":2}]%s}`, cartItem.ID, skuA.ID, coupon), wantStatus, &order)
+		return order
+	}
+
+	// 24000 fen qualifies for both automatic promotions, larger first.
+	order := placeOrder("", http.StatusCreated)
+	if *order.SubtotalFen != 24000 || *order.DiscountFen != 2200 || *order.TotalFen != 21800 {
+		t.Fatalf("unexpected order totals %d/%d/%d", *order.SubtotalFen, *order.DiscountFen, *order.TotalFen)
+	}
+	if order.Discounts == nil || len(*order.Discounts) != 2 || (*order.Discounts)[0].AmountFen != 1200 || (*order.Discounts)[1].PromotionId != threshold.ID {
+		t.Fatalf("unexpected discount lines %+v", order.Discounts)
+	}
+
+	// The coupon does not stack, so it replaces the automatic promotions.
+	order = placeOrder(strings.ToLower(issued.Items[0].Code), http.StatusCreated)
+	if *order.DiscountFen != 3000 || *order.TotalFen != 21000 || (*order.Discounts)[0].CouponCode == nil || *(*order.Discounts)[0].CouponCode != issued.Items[0].Code {
+		t.Fatalf("unexpected coupon order %+v", order)
+	}
+	var fetched oapi.Order
+	doJSONRequest(t, router, http.MethodGet, "/orders/"+order.Id.String(), customerToken, "", http.StatusOK, &fetched)
+	if fetched.Discounts == nil || len(*fetched.Discounts) != 1 || *fetched.TotalFen != 21000 {
+		t.Fatalf("unexpected fetched order %+v", fetched)
+	}
+
+	placeOrder(issued.Items[0].Code, http.StatusConflict)
+	placeOrder(issued.Items[1].Code, http.StatusConflict)
+	placeOrder("NOPE", http.StatusBadRequest)
+
+	var coupons couponListResponse
+	doJSONRequest(t, router, http.MethodGet, "/admin/promotions/"+campaign.ID.String()+"/coupons", adminToken, "", http.StatusOK, &coupons)
+	redeemed := map[string]int64{}
+	for _, coupon := range coupons.Items {
+		redeemed[coupon.Code] = coupon.RedeemedCount
+	}
+	if coupons.Total != 2 || redeemed[issued.Items[0].Code] != 1 || redeemed[issued.Items[1].Code] != 0 {
+		t.Fatalf("unexpected coupon redemptions %+v", coupons)
+	}
+
+	// Once deactivated a coupon is rejected as invalid.
+	doJSONRequest(t, router, http.MethodPatch, "/admin/promotions/"+campaign.ID.String(), adminToken, `{"isActive":false,"perCustomerLimit":null}`, http.StatusOK, nil)
+	placeOrder(issued.Items[1].Code, http.StatusBadRequest)
+}
@@ -0,0 +1,419 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/oapi-codegen/runtime/types"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/quotation"
+)
+
+var (
+	errInquiryClosed      = errors.New("inquiry is closed")
+	errQuotationConverted = errors.New("a quotation of this inquiry was already converted to an order")
+	errQuotationNotOpen   = errors.New("quotation is no longer open")
+	errQuotationExpired   = errors.New("quotation has expired")
+)
+
+func (h *Handler) GetInquiriesPriceInquiryIdQuotations(c *gin.Context, inquiryId types.UUID) {
+	claims, scope, ok := h.requirePermission(c, authz.PermissionInquiryRead)
+	if !ok {
+		return
+	}
+
+	inquiry, ok := h.loadAccessiblePriceInquiry(c, claims.UserID, scope, uuid.UUID(inquiryId), "failed to list quotations")
+	if !ok {
+		return
+	}
+
+	quotations, err := h.QuotationStore.ListQuotationsByInquiry(c.Request.Context(), inquiry.ID)
+	if err != nil {
+		h.logError("list quotations failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list quotations")
+		return
+	}
+
+	items, err := h.quotationsResponse(c.Request.Context(), quotations)
+	if err != nil {
+		h.logError("map quotations failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list quotations")
+		return
+	}
+
+	c.JSON(http.StatusOK, oapi.QuotationList{Items: items})
+}
+
+func (h *Handler) PostInquiriesPriceInquiryIdQuotations(c *gin.Context, inquiryId types.UUID) {
+	claims, scope, ok := h.requirePermission(c, authz.PermissionInquiryManage)
+	if !ok {
+		return
+	}
+
+	var request oapi.CreateQuotationRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	if len(request.Items) == 0 {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "items is required")
+		return
+	}
+	now := time.Now()
+	if request.ValidUntil.Time.Before(quotation.Today(now)) {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "validUntil must not be in the past")
+		return
+	}
+
+	skuIDs := make([]uuid.UUID, 0, len(request.Items))
+	seenSkuIDs := make(map[uuid.UUID]struct{}, len(request.Items))
+	for _, item := range request.Items {
+		if item.Qty < 1 {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "qty must be >= 1")
+			return
+		}
+		if item.UnitPriceFen < 0 {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "unitPriceFen must be >= 0")
+			return
+		}
+		skuID := uuid.UUID(item.SkuId)
+		if _, exists := seenSkuIDs[skuID]; exists {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "duplicate skuId")
+			return
+		}
+		seenSkuIDs[skuID] = struct{}{}
+		skuIDs = append(skuIDs, skuID)
+	}
+
+	skus, err := h.CatalogStore.ListSkusByIDs(c.Request.Context(), skuIDs)
+	if err != nil {
+		h.logError("list skus failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to create quotation")
+		return
+	}
+	if len(skus) != len(skuIDs) {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid skuId")
+		return
+	}
+	for _, sku := range skus {
+		if !sku.IsActive {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "sku is inactive")
+			return
+		}
+	}
+
+	inquiry, ok := h.loadAccessiblePriceInquiry(c, claims.UserID, scope, uuid.UUID(inquiryId), "failed to create quotation")
+	if !ok {
+		return
+	}
+	if h.DB == nil {
+		h.logError("create quotation failed", errors.New("db pool is nil"))
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to create quotation")
+		return
+	}
+
+	ctx := c.Request.Context()
+	var created db.Quotation
+	err = shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		q := db.New(tx)
+		locked, err := q.GetPriceInquiryForUpdate(ctx, inquiry.ID)
+		if err != nil {
+			return err
+		}
+		if locked.Status == string(oapi.PriceInquiryStatusCLOSED) {
+			return errInquiryClosed
+		}
+		converted, err := q.CountQuotationsByInquiryAndStatus(ctx, db.CountQuotationsByInquiryAndStatusParams{
+			InquiryID: locked.ID,
+			Status:    quotation.StatusConverted,
+		})
+		if err != nil {
+			return err
+		}
+		if converted > 0 {
+			return errQuotationConverted
+		}
+
+		if _, err := q.SupersedeQuotations(ctx, db.SupersedeQuotationsParams{
+			Status:       quotation.StatusSuperseded,
+			InquiryID:    locked.ID,
+			FromStatuses: quotation.OpenStatuses,
+		}); err != nil {
+			return err
+		}
+		created, err = q.CreateQuotation(ctx, db.CreateQuotationParams{
+			InquiryID:        locked.ID,
+			CustomerID:       locked.CreatedByUserID,
+			OwnerSalesUserID: locked.OwnerSalesUserID,
+			CreatedByUserID:  claims.UserID,
+			Status:           quotation.StatusSent,
+			ValidUntil:       pgtype.Date{Time: request.ValidUntil.Time, Valid: true},
+			Terms:            trimmedOptional(request.Terms),
+		})
+		if err != nil {
+			return err
+		}
+		for i, item := range request.Items {
+			if _, err := q.CreateQuotationItem(ctx, db.CreateQuotationItemParams{
+				QuotationID:  created.ID,
+				SkuID:        uuid.UUID(item.SkuId),
+				Qty:          clampInt32(item.Qty),
+				UnitPriceFen: item.UnitPriceFen,
+				LineNo:       clampInt32(i + 1),
+			}); err != nil {
+				return err
+			}
+		}
+
+		responded := string(oapi.PriceInquiryStatusRESPONDED)
+		_, err = q.UpdatePriceInquiry(ctx, db.UpdatePriceInquiryParams{
+			ID:     locked.ID,
+			Status: &responded,
+		})
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, errInquiryClosed):
+			h.writeError(c, http.StatusConflict, "inquiry_closed", err.Error())
+		case errors.Is(err, errQuotationConverted):
+			h.writeError(c, http.StatusConflict, "quotation_converted", err.Error())
+		default:
+			h.logError("create quotation failed", err)
+			h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to create quotation")
+		}
+		return
+	}
+
+	h.writeQuotation(c, http.StatusCreated, created, "failed to create quotation")
+}
+
+func (h *Handler) GetQuotationsQuotationId(c *gin.Context, quotationId types.UUID) {
+	claims, scope, ok := h.requirePermission(c, authz.PermissionInquiryRead)
+	if !ok {
+		return
+	}
+
+	record, err := h.QuotationStore.GetQuotation(c.Request.Context(), uuid.UUID(quotationId))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusNotFound, "not_found", "quotation not found")
+			return
+		}
+		h.logError("get quotation failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch quotation")
+		return
+	}
+
+	inquiry, err := h.InquiryStore.GetPriceInquiry(c.Request.Context(), record.InquiryID)
+	if err != nil {
+		h.logError("get price inquiry failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch quotation")
+		return
+	}
+	if !canAccessPriceInquiry(scope, claims.UserID, inquiry) {
+		h.writeError(c, http.StatusNotFound, "not_found", "quotation not found")
+		return
+	}
+
+	h.writeQuotation(c, http.StatusOK, record, "failed to fetch quotation")
+}
+
+func (h *Handler) PostQuotationsQuotationIdAccept(c *gin.Context, quotationId types.UUID) {
+	h.decideQuotation(c, uuid.UUID(quotationId), quotation.StatusAccepted, []string{quotation.StatusSent})
+}
+
+func (h *Handler) PostQuotationsQuotationIdReject(c *gin.Context, quotationId types.UUID) {
+	h.decideQuotation(c, uuid.UUID(quotationId), quotation.StatusRejected, quotation.OpenStatuses)
+}
+
+// decideQuotation records the customer's answer to a quotation. Only the
+// customer the quotation was sent to can answer it, and an expired quotation
+// can only be rejected.
+func (h *Handler) decideQuotation(c *gin.Context, quotationID uuid.UUID, status string, fromStatuses []string) {
+	claims, ok := h.requireUser(c)
+	if !ok {
+		return
+	}
+
+	// The body is optional; an empty one carries no note.
+	var request oapi.QuotationDecisionRequest
+	if err := c.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+
+	record, err := h.QuotationStore.GetQuotation(c.Request.Context(), quotationID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusNotFound, "not_found", "quotation not found")
+			return
+		}
+		h.logError("get quotation failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to update quotation")
+		return
+	}
+	if record.CustomerID != claims.UserID {
+		h.writeError(c, http.StatusNotFound, "not_found", "quotation not found")
+		return
+	}
+	if status == quotation.StatusAccepted && quotation.Expired(record.ValidUntil, time.Now()) {
+		h.writeError(c, http.StatusConflict, "quotation_expired", errQuotationExpired.Error())
+		return
+	}
+
+	updated, err := h.QuotationStore.DecideQuotation(c.Request.Context(), db.DecideQuotationParams{
+		Status:       status,
+		DecisionNote: trimmedOptional(request.Note),
+		ID:           record.ID,
+		FromStatuses: fromStatuses,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusConflict, "quotation_not_open", errQuotationNotOpen.Error())
+			return
+		}
+		h.logError("decide quotation failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to update quotation")
+		return
+	}
+
+	h.writeQuotation(c, http.StatusOK, updated, "failed to update quotation")
+}
+
+func checkQuotationOpen(record db.Quotation, now time.Time) error {
+	switch {
+	case quotation.IsOpen(record, now):
+		return nil
+	case quotation.EffectiveStatus(record, now) == quotation.StatusExpired:
+		return errQuotationExpired
+	default:
+		return errQuotationNotOpen
+	}
+}
+
+func (h *Handler) loadAccessiblePriceInquiry(
+	c *gin.Context,
+	userID uuid.UUID,
+	scope authz.Scope,
+	inquiryID uuid.UUID,
+	failureMessage string,
+) (db.PriceInquiry, bool) {
+	inquiry, err := h.InquiryStore.GetPriceInquiry(c.Request.Context(), inquiryID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusNotFound, "not_found", "inquiry not found")
+			return db.PriceInquiry{}, false
+		}
+		h.logError("get price inquiry failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", failureMessage)
+		return db.PriceInquiry{}, false
+	}
+	if !canAccessPriceInquiry(scope, userID, inquiry) {
+		h.writeError(c, http.StatusNotFound, "not_found", "inquiry not found")
+		return db.PriceInquiry{}, false
+	}
+	return inquiry, true
+}
+
+func (h *Handler) writeQuotation(c *gin.Context, status int, record db.Quotation, failureMessage string) {
+	items, err := h.quotationsResponse(c.Request.Context(), []db.Quotation{record})
+	if err != nil {
+		h.logError("map quotation failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", failureMessage)
+		return
+	}
+	c.JSON(status, items[0])
+}
+
+// quotationsResponse maps quotations together with their lines, loading the
+// lines and SKUs of all of them at once.
+func (h *Handler) quotationsResponse(ctx context.Context, quotations []db.Quotation) ([]oapi.Quotation, error) {
+	response := make([]oapi.Quotation, 0, len(quotations))
+	if len(quotations) == 0 {
+		return response, nil
+	}
+
+	quotationIDs := make([]uuid.UUID, 0, len(quotations))
+	for _, record := range quotations {
+		quotationIDs = append(quotationIDs, record.ID)
+	}
+	items, err := h.QuotationStore.ListQuotationItemsByQuotations(ctx, quotationIDs)
+	if err != nil {
+		return nil, err
+	}
+	itemsByQuotation := make(map[uuid.UUID][]db.QuotationItem, len(quotations))
+	skuIDs := make([]uuid.UUID, 0, len(items))
+	for _, item := range items {
+		itemsByQuotation[item.QuotationID] = append(itemsByQuotation[item.QuotationID], item)
+		skuIDs = append(skuIDs, item.SkuID)
+	}
+
+	skuByID := map[uuid.UUID]db.CatalogSku{}
+	if unique := uniqueUUIDs(skuIDs); len(unique) > 0 {
+		skus, err := h.CatalogStore.ListSkusByIDs(ctx, unique)
+		if err != nil {
+			return nil, err
+		}
+		for _, sku := range skus {
+			skuByID[sku.ID] = sku
+		}
+	}
+
+	now := time.Now()
+	for _, record := range quotations {
+		response = append(response, quotationFromModel(record, itemsByQuotation[record.ID], skuByID, now))
+	}
+	return response, nil
+}
+
+func quotationFromModel(record db.Quotation, items []db.QuotationItem, skuByID map[uuid.UUID]db.CatalogSku, now time.Time) oapi.Quotation {
+	response := oapi.Quotation{
+		Id:              record.ID,
+		InquiryId:       record.InquiryID,
+		Version:         int(record.Version),
+		Status:          oapi.QuotationStatus(quotation.EffectiveStatus(record, now)),
+		CustomerId:      record.CustomerID,
+		CreatedByUserId: record.CreatedByUserID,
+		ValidUntil:      types.Date{Time: record.ValidUntil.Time},
+		Terms:           record.Terms,
+		DecisionNote:    record.DecisionNote,
+		Items:           make([]oapi.QuotationItem, 0, len(items)),
+		CreatedAt:       record.CreatedAt.Time,
+	}
+	if record.DecidedAt.Valid {
+		response.DecidedAt = &record.DecidedAt.Time
+	}
+	if record.OrderID.Valid {
+		orderID := types.UUID(record.OrderID.Bytes)
+		response.OrderId = &orderID
+	}
+	for _, item := range items {
+		amountFen := item.UnitPriceFen * int64(item.Qty)
+		mapped := oapi.QuotationItem{
+			SkuId:        item.SkuID,
+			Qty:          int(item.Qty),
+			UnitPriceFen: item.UnitPriceFen,
+			AmountFen:    amountFen,
+		}
+		if sku, ok := skuByID[item.SkuID]; ok {
+			mapped.SkuName = sku.Name
+			mapped.SkuCode = sku.SkuCode
+			mapped.Spec = sku.Spec
+		}
+		response.Items = append(response.Items, mapped)
+		response.TotalFen += amountFen
+	}
+	return response
+}
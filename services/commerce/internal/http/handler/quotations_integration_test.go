@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/quotation"
+)
+
+func TestQuotationRevisionAcceptAndConvertToOrder(t *testing.T) {
+	pool := openHandlerTestPool(t)
+	resetCommerceTables(t, pool)
+
+	queries := db.New(pool)
+	skuA, skuB := seedCatalog(t, queries)
+	ctx := context.Background()
+	customerID := uuid.New()
+	salesID := uuid.New()
+	inquiry, err := queries.CreatePriceInquiry(ctx, db.CreatePriceInquiryParams{
+		Status:           string(oapi.PriceInquiryStatusOPEN),
+		CreatedByUserID:  customerID,
+		OwnerSalesUserID: pgtype.UUID{Bytes: salesID, Valid: true},
+		Message:          "批量采购报价",
+	})
+	if err != nil {
+		t.Fatalf("create inquiry: %v", err)
+	}
+
+	router := newAuthIntegrationRouter(pool, queries)
+	salesToken := makeAuthToken(t, salesID, "SALES", nil)
+	customerToken := makeAuthToken(t, customerID, "CUSTOMER", &salesID)
+	validUntil := quotation.Today(time.Now()).AddDate(0, 0, 7).Format("2006-01-02")
+
+	first := postQuotation(t, router, salesToken, inquiry.ID, fmt.Sprintf(
+		`{"items":[{"skuId":"%s","qty":10,"unitPriceFen":11000}],"validUntil":"%s"}`, skuA.ID, validUntil))
+	if first.Version != 1 || first.Status != oapi.QuotationStatusSENT || first.TotalFen != 110000 {
+		t.Fatalf("unexpected first quotation %+v", first)
+	}
+	second := postQuotation(t, router, salesToken, inquiry.ID, fmt.Sprintf(
+		`{"items":[{"skuId":"%s","qty":10,"unitPriceFen":10500},{"skuId":"%s","qty":2,"unitPriceFen":17000}],"validUntil":"%s","terms":"含税含运"}`,
+		skuA.ID, skuB.ID, validUntil))
+	if second.Version != 2 || len(second.Items) != 2 || second.Items[0].SkuName != skuA.Name {
+		t.Fatalf("unexpected revised quotation %+v", second)
+	}
+
+	var list oapi.QuotationList
+	doJSONRequest(t, router, http.MethodGet, "/inquiries/price/"+inquiry.ID.String()+"/quotations", customerToken, "", http.StatusOK, &list)
+	if len(list.Items) != 2 || list.Items[0].Version != 2 || list.Items[1].Status != oapi.QuotationStatusSUPERSEDED {
+		t.Fatalf("expected the first version to be superseded, got %+v", list.Items)
+	}
+	updatedInquiry, err := queries.GetPriceInquiry(ctx, inquiry.ID)
+	if err != nil {
+		t.Fatalf("get inquiry: %v", err)
+	}
+	if updatedInquiry.Status != string(oapi.PriceInquiryStatusRESPONDED) {
+		t.Fatalf("expected inquiry to be RESPONDED, got %s", updatedInquiry.Status)
+	}
+
+	doJSONRequest(t, router, http.MethodPost, "/quotations/"+first.Id.String()+"/accept", customerToken, "", http.StatusConflict, nil)
+	doJSONRequest(t, router, http.MethodPost, "/quotations/"+second.Id.String()+"/accept", salesToken, "", http.StatusNotFound, nil)
+	var accepted oapi.Quotation
+	doJSONRequest(t, router, http.MethodPost, "/quotations/"+second.Id.String()+"/accept", customerToken, `{"note":"同意"}`, http.StatusOK, &accepted)
+	if accepted.Status != oapi.QuotationStatusACCEPTED || accepted.DecisionNote == nil || *accepted.DecisionNote != "同意" {
+		t.Fatalf("unexpected accepted quotation %+v", accepted)
+	}
+
+	orderBody := fmt.Sprintf(`{"address":{"receiverName":"A","receiverPhone":"1","detail":"X"},"quotationId":"%s"}`, second.Id)
+	var order oapi.Order
+	doJSONRequest(t, router, http.MethodPost, "/orders", customerToken, orderBody, http.StatusCreated, &order)
+	if len(order.Items) != 2 {
+		t.Fatalf("expected the quoted lines on the order, got %+v", order.Items)
+	}
+	for _, item := range order.Items {
+		want := int64(10500)
+		if item.Sku.Id == skuB.ID {
+			want = 17000
+		}
+		if item.UnitPriceFen != want {
+			t.Fatalf("expected quoted price %d for %s, got %d", want, item.Sku.Id, item.UnitPriceFen)
+		}
+		if item.Sku.PriceTiers == nil || len(*item.Sku.PriceTiers) != 1 || (*item.Sku.PriceTiers)[0].UnitPriceFen != want {
+			t.Fatalf("expected the quoted price as the only tier for %s, got %+v", item.Sku.Id, item.Sku.PriceTiers)
+		}
+	}
+
+	converted, err := queries.GetQuotation(ctx, second.Id)
+	if err != nil {
+		t.Fatalf("get quotation: %v", err)
+	}
+	if converted.Status != quotation.StatusConverted || !converted.OrderID.Valid || converted.OrderID.Bytes != order.Id {
+		t.Fatalf("expected quotation to be converted into the order, got %+v", converted)
+	}
+
+	doJSONRequest(t, router, http.MethodPost, "/orders", customerToken, orderBody, http.StatusConflict, nil)
+	doJSONRequest(t, router, http.MethodPost, "/inquiries/price/"+inquiry.ID.String()+"/quotations", salesToken, fmt.Sprintf(
+		`{"items":[{"skuId":"%s","qty":1,"unitPriceFen":1}],"validUntil":"%s"}`, skuA.ID, validUntil), http.StatusConflict, nil)
+}
+
+func TestPostOrdersRejectsExpiredQuotation(t *testing.T) {
+	pool := openHandlerTestPool(t)
+	resetCommerceTables(t, pool)
+
+	queries := db.New(pool)
+	skuA, _ := seedCatalog(t, queries)
+	ctx := context.Background()
+	customerID := uuid.New()
+	inquiry, err := queries.CreatePriceInquiry(ctx, db.CreatePriceInquiryParams{
+		Status:          string(oapi.PriceInquiryStatusRESPONDED),
+		CreatedByUserID: customerID,
+		Message:         "报价",
+	})
+	if err != nil {
+		t.Fatalf("create inquiry: %v", err)
+	}
+	expired, err := queries.CreateQuotation(ctx, db.CreateQuotationParams{
+		InquiryID:       inquiry.ID,
+		CustomerID:      customerID,
+		CreatedByUserID: uuid.New(),
+		Status:          quotation.StatusSent,
+		ValidUntil:      pgtype.Date{Time: quotation.Today(time.Now()).AddDate(0, 0, -1), Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("create quotation: %v", err)
+	}
+	if _, err := queries.CreateQuotationItem(ctx, db.CreateQuotationItemParams{
+		QuotationID:  expired.ID,
+		SkuID:        skuA.ID,
+		Qty:          1,
+		UnitPriceFen: 100,
+		LineNo:       1,
+	}); err != nil {
+		t.Fatalf("create quotation item: %v", err)
+	}
+
+	router := newAuthIntegrationRouter(pool, queries)
+	token := makeAuthToken(t, customerID, "CUSTOMER", nil)
+	body := fmt.Sprintf(`{"address":{"receiverName":"A","receiverPhone":"1","detail":"X"},"quotationId":"%s"}`, expired.ID)
+	doJSONRequest(t, router, http.MethodPost, "/orders", token, body, http.StatusConflict, nil)
+	doJSONRequest(t, router, http.MethodPost, "/orders", makeAuthToken(t, uuid.New(), "CUSTOMER", nil), body, http.StatusNotFound, nil)
+
+	var rejected oapi.Quotation
+	doJSONRequest(t, router, http.MethodPost, "/quotations/"+expired.ID.String()+"/reject", token, "", http.StatusOK, &rejected)
+	if rejected.Status != oapi.QuotationStatusREJECTED {
+		t.Fatalf("expected an expired quotation to be rejectable, got %s", rejected.Status)
+	}
+}
+
+func postQuotation(t *testing.T, router *gin.Engine, token string, inquiryID uuid.UUID, body string) oapi.Quotation {
+	t.Helper()
+
+	var response oapi.Quotation
+	doJSONRequest(t, router, http.MethodPost, "/inquiries/price/"+inquiryID.String()+"/quotations", token, body, http.StatusCreated, &response)
+	return response
+}
@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/receivable"
+)
+
+type adminReceivable struct {
+	ID               string     `json:"id"`
+	OrderID          string     `json:"orderId"`
+	CustomerID       string     `json:"customerId"`
+	OwnerSalesUserID *string    `json:"ownerSalesUserId,omitempty"`
+	AmountFen        int64      `json:"amountFen"`
+	Status           string     `json:"status"`
+	TermDays         int        `json:"termDays"`
+	PostedAt         time.Time  `json:"postedAt"`
+	DueAt            time.Time  `json:"dueAt"`
+	SettledAt        *time.Time `json:"settledAt,omitempty"`
+	VoidedAt         *time.Time `json:"voidedAt,omitempty"`
+}
+
+type adminReceivableListResponse struct {
+	Items    []adminReceivable `json:"items"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"pageSize"`
+	Total    int64             `json:"total"`
+}
+
+type adminReceivableAgingBuckets struct {
+	Days0To30Fen  int64 `json:"days0To30Fen"`
+	Days31To60Fen int64 `json:"days31To60Fen"`
+	Days61To90Fen int64 `json:"days61To90Fen"`
+	Over90Fen     int64 `json:"over90Fen"`
+}
+
+type adminReceivableAgingItem struct {
+	CustomerID  *string                     `json:"customerId,omitempty"`
+	SalesUserID *string                     `json:"salesUserId,omitempty"`
+	OpenCount   int                         `json:"openCount"`
+	TotalFen    int64                       `json:"totalFen"`
+	OverdueFen  int64                       `json:"overdueFen"`
+	Buckets     adminReceivableAgingBuckets `json:"buckets"`
+	Unassigned  bool                        `json:"unassigned,omitempty"`
+}
+
+type adminReceivableAgingResponse struct {
+	GroupBy string                     `json:"groupBy"`
+	AsOf    time.Time                  `json:"asOf"`
+	Items   []adminReceivableAgingItem `json:"items"`
+	Total   adminReceivableAgingItem   `json:"total"`
+}
+
+func (h *Handler) GetAdminReceivables(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionReceivableRead); !ok {
+		return
+	}
+	if h.ReceivableStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "receivables are not configured")
+		return
+	}
+
+	customerID, ok := parseOptionalUUIDQuery(c.Query("customerId"))
+	if !ok {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid customerId")
+		return
+	}
+	salesUserID, ok := parseOptionalUUIDQuery(c.Query("salesUserId"))
+	if !ok {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid salesUserId")
+		return
+	}
+	var status *string
+	if raw := strings.ToUpper(strings.TrimSpace(c.Query("status"))); raw != "" {
+		switch raw {
+		case receivable.StatusOpen, receivable.StatusSettled, receivable.StatusVoid:
+			status = &raw
+		default:
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "status must be one of OPEN, SETTLED, VOID")
+			return
+		}
+	}
+
+	page := parseAdminPositiveInt(c.Query("page"), 1)
+	pageSize := parseAdminPositiveInt(c.Query("pageSize"), 20)
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	offset := (page - 1) * pageSize
+
+	rows, err := h.ReceivableStore.ListReceivables(c.Request.Context(), db.ListReceivablesParams{
+		CustomerID:       customerID,
+		OwnerSalesUserID: salesUserID,
+		Status:           status,
+		Offset:           clampInt32(offset),
+		Limit:            clampInt32(pageSize),
+	})
+	if err != nil {
+		h.logError("list receivables failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list receivables")
+		return
+	}
+	total, err := h.ReceivableStore.CountReceivables(c.Request.Context(), db.CountReceivablesParams{
+		CustomerID:       customerID,
+		OwnerSalesUserID: salesUserID,
+		Status:           status,
+	})
+	if err != nil {
+		h.logError("count receivables failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list receivables")
+		return
+	}
+
+	items := make([]adminReceivable, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, adminReceivableFromModel(row))
+	}
+	c.JSON(http.StatusOK, adminReceivableListResponse{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	})
+}
+
+func (h *Handler) GetAdminReceivablesAging(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionReceivableRead); !ok {
+		return
+	}
+	if h.ReceivableStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "receivables are not configured")
+		return
+	}
+
+	groupBy := strings.ToLower(strings.TrimSpace(c.DefaultQuery("groupBy", receivable.GroupByCustomer)))
+	if groupBy != receivable.GroupByCustomer && groupBy != receivable.GroupBySales {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "groupBy must be customer or sales")
+		return
+	}
+
+	rows, err := h.ReceivableStore.ListOpenReceivables(c.Request.Context())
+	if err != nil {
+		h.logError("list open receivables failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to build receivable aging")
+		return
+	}
+
+	asOf := time.Now().UTC()
+	groups := receivable.Age(rows, asOf, groupBy)
+	response := adminReceivableAgingResponse{
+		GroupBy: groupBy,
+		AsOf:    asOf,
+		Items:   make([]adminReceivableAgingItem, 0, len(groups)),
+	}
+	for _, group := range groups {
+		item := adminReceivableAgingItemFromGroup(group)
+		groupID := group.GroupID.String()
+		switch {
+		case groupBy == receivable.GroupByCustomer:
+			item.CustomerID = &groupID
+		case group.GroupID == uuid.Nil:
+			item.Unassigned = true
+		default:
+			item.SalesUserID = &groupID
+		}
+		response.Items = append(response.Items, item)
+
+		response.Total.OpenCount += item.OpenCount
+		response.Total.TotalFen += item.TotalFen
+		response.Total.OverdueFen += item.OverdueFen
+		response.Total.Buckets.Days0To30Fen += item.Buckets.Days0To30Fen
+		response.Total.Buckets.Days31To60Fen += item.Buckets.Days31To60Fen
+		response.Total.Buckets.Days61To90Fen += item.Buckets.Days61To90Fen
+		response.Total.Buckets.Over90Fen += item.Buckets.Over90Fen
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func adminReceivableAgingItemFromGroup(group receivable.AgingGroup) adminReceivableAgingItem {
+	return adminReceivableAgingItem{
+		OpenCount:  group.OpenCount,
+		TotalFen:   group.TotalFen,
+		OverdueFen: group.OverdueFen,
+		Buckets: adminReceivableAgingBuckets{
+			Days0To30Fen:  group.Buckets.Days0To30,
+			Days31To60Fen: group.Buckets.Days31To60,
+			Days61To90Fen: group.Buckets.Days61To90,
+			Over90Fen:     group.Buckets.Over90,
+		},
+	}
+}
+
+func adminReceivableFromModel(row db.Receivable) adminReceivable {
+	item := adminReceivable{
+		ID:         row.ID.String(),
+		OrderID:    row.OrderID.String(),
+		CustomerID: row.CustomerID.String(),
+		AmountFen:  row.AmountFen,
+		Status:     row.Status,
+		TermDays:   int(row.TermDays),
+		PostedAt:   row.PostedAt.Time,
+		DueAt:      row.DueAt.Time,
+		SettledAt:  timePtrFromPg(row.SettledAt),
+		VoidedAt:   timePtrFromPg(row.VoidedAt),
+	}
+	if row.OwnerSalesUserID.Valid {
+		owner := uuid.UUID(row.OwnerSalesUserID.Bytes).String()
+		item.OwnerSalesUserID = &owner
+	}
+	return item
+}
+
+func parseOptionalUUIDQuery(raw string) (pgtype.UUID, bool) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return pgtype.UUID{}, true
+	}
+	id, err := uuid.Parse(trimmed)
+	if err != nil {
+		return pgtype.UUID{}, false
+	}
+	return pgtype.UUID{Bytes: id, Valid: true}, true
+}
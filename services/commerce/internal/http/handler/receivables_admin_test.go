@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/packages/go-shared/httpx"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/middleware"
+)
+
+type stubReceivableStore struct {
+	open []db.Receivable
+}
+
+func (s stubReceivableStore) ListOpenReceivables(context.Context) ([]db.Receivable, error) {
+	return s.open, nil
+}
+
+func (s stubReceivableStore) ListReceivables(context.Context, db.ListReceivablesParams) ([]db.Receivable, error) {
+	return s.open, nil
+}
+
+func (s stubReceivableStore) CountReceivables(context.Context, db.CountReceivablesParams) (int64, error) {
+	return int64(len(s.open)), nil
+}
+
+func (s stubReceivableStore) SumOpenReceivablesByCustomer(_ context.Context, customerID uuid.UUID) (int64, error) {
+	var total int64
+	for _, item := range s.open {
+		if item.CustomerID == customerID {
+			total += item.AmountFen
+		}
+	}
+	return total, nil
+}
+
+func TestGetAdminReceivablesAging(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	customerID, salesID := uuid.New(), uuid.New()
+	now := time.Now().UTC()
+	openReceivable := func(amount int64, ageDays int, owner pgtype.UUID) db.Receivable {
+		postedAt := now.AddDate(0, 0, -ageDays)
+		return db.Receivable{
+			ID: uuid.New(), OrderID: uuid.New(), CustomerID: customerID, OwnerSalesUserID: owner,
+			AmountFen: amount, Status: "OPEN", TermDays: 30,
+			PostedAt: pgtype.Timestamptz{Time: postedAt, Valid: true},
+			DueAt:    pgtype.Timestamptz{Time: postedAt.AddDate(0, 0, 30), Valid: true},
+		}
+	}
+	handler := &Handler{
+		Auth: middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer),
+		ReceivableStore: stubReceivableStore{open: []db.Receivable{
+			openReceivable(1000, 5, pgtype.UUID{Bytes: salesID, Valid: true}),
+			openReceivable(2000, 45, pgtype.UUID{}),
+		}},
+	}
+	router := httpx.NewRouter()
+	router.GET("/admin/receivables/aging", handler.GetAdminReceivablesAging)
+
+	request := func(query, role string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin/receivables/aging"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+makeAuthToken(t, uuid.New(), role, nil))
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	if recorder := request("", "SALES"); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected SALES to be rejected, got %d", recorder.Code)
+	}
+	if recorder := request("?groupBy=region", "BOSS"); recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected invalid groupBy 400, got %d", recorder.Code)
+	}
+
+	recorder := request("?groupBy=sales", "BOSS")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var response adminReceivableAgingResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(response.Items) != 2 {
+		t.Fatalf("expected 2 sales groups, got %d", len(response.Items))
+	}
+	if !response.Items[0].Unassigned || response.Items[0].Buckets.Days31To60Fen != 2000 || response.Items[0].OverdueFen != 2000 {
+		t.Fatalf("unexpected unassigned group %+v", response.Items[0])
+	}
+	if response.Items[1].SalesUserID == nil || *response.Items[1].SalesUserID != salesID.String() || response.Items[1].Buckets.Days0To30Fen != 1000 {
+		t.Fatalf("unexpected sales group %+v", response.Items[1])
+	}
+	if response.Total.TotalFen != 3000 || response.Total.OpenCount != 2 {
+		t.Fatalf("unexpected totals %+v", response.Total)
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+)
+
+// RegisterRoutes mounts the OpenAPI handlers and the routes served outside the
+// generated interface on router.
+func (h *Handler) RegisterRoutes(router gin.IRouter) {
+	oapi.RegisterHandlers(router, h)
+	router.GET("/ws/support", h.GetSupportWebSocket)
+	router.GET("/support/conversations/current", h.GetSupportConversationsCurrent)
+	router.GET("/support/conversations/:conversationId/messages", h.GetSupportConversationsConversationIdMessages)
+	router.POST("/support/conversations/:conversationId/messages", h.PostSupportConversationsConversationIdMessages)
+	router.POST("/support/conversations/:conversationId/messages/image", h.PostSupportConversationsConversationIdMessagesImage)
+	router.POST("/support/conversations/:conversationId/read", h.PostSupportConversationsConversationIdRead)
+	router.GET("/admin/support/conversations", h.GetAdminSupportConversations)
+	router.GET("/admin/support/conversations/:conversationId", h.GetAdminSupportConversationsConversationId)
+	router.POST("/admin/support/conversations/:conversationId/claim", h.PostAdminSupportConversationsConversationIdClaim)
+	router.POST("/admin/support/conversations/:conversationId/release", h.PostAdminSupportConversationsConversationIdRelease)
+	router.POST("/admin/support/conversations/:conversationId/transfer", h.PostAdminSupportConversationsConversationIdTransfer)
+	router.POST("/admin/products/import-jobs", h.PostAdminProductsImportJobs)
+	router.POST("/admin/shipments/import-jobs", h.PostShipmentsImportJobs)
+	router.POST("/admin/product-requests/export-jobs", h.PostAdminProductRequestsExportJobs)
+	router.GET("/admin/import-jobs/:jobId", h.GetAdminImportJobsJobId)
+	router.GET("/admin/inquiries/:inquiryId/requirement-profile", h.GetAdminInquiriesInquiryIdRequirementProfile)
+	router.GET("/admin/suppliers", h.GetAdminSuppliers)
+	router.GET("/admin/suppliers/:supplierId", h.GetAdminSuppliersSupplierId)
+	router.PATCH("/admin/suppliers/:supplierId", h.PatchAdminSuppliersSupplierId)
+	router.GET("/admin/suppliers/:supplierId/contacts", h.GetAdminSuppliersSupplierIdContacts)
+	router.GET("/admin/suppliers/:supplierId/scorecards", h.GetAdminSuppliersSupplierIdScorecards)
+	router.GET("/admin/orders/state-machine", h.GetAdminOrdersStateMachine)
+	router.GET("/admin/inventory/low-stock", h.GetAdminInventoryLowStock)
+	router.GET("/admin/inventory/skus/:skuId", h.GetAdminInventorySkusSkuId)
+	router.PATCH("/admin/inventory/skus/:skuId", h.PatchAdminInventorySkusSkuId)
+	router.POST("/admin/inventory/skus/:skuId/adjustments", h.PostAdminInventorySkusSkuIdAdjustments)
+	router.GET("/admin/receivables", h.GetAdminReceivables)
+	router.GET("/admin/receivables/aging", h.GetAdminReceivablesAging)
+	router.POST("/admin/statements", h.PostAdminStatements)
+	router.GET("/admin/statements", h.GetAdminStatements)
+	router.GET("/admin/statements/:statementId", h.GetAdminStatementsStatementId)
+	router.GET("/admin/statements/:statementId/download", h.GetAdminStatementsStatementIdDownload)
+	router.GET("/statements", h.GetStatements)
+	router.GET("/statements/:statementId/download", h.GetStatementsStatementIdDownload)
+	router.POST("/admin/webhooks/subscriptions", h.PostAdminWebhookSubscriptions)
+	router.GET("/admin/webhooks/subscriptions", h.GetAdminWebhookSubscriptions)
+	router.GET("/admin/webhooks/subscriptions/:subscriptionId", h.GetAdminWebhookSubscriptionsSubscriptionId)
+	router.PATCH("/admin/webhooks/subscriptions/:subscriptionId", h.PatchAdminWebhookSubscriptionsSubscriptionId)
+	router.DELETE("/admin/webhooks/subscriptions/:subscriptionId", h.DeleteAdminWebhookSubscriptionsSubscriptionId)
+	router.POST("/admin/webhooks/subscriptions/:subscriptionId/rotate-secret", h.PostAdminWebhookSubscriptionsSubscriptionIdRotateSecret)
+	router.GET("/admin/webhooks/deliveries", h.GetAdminWebhookDeliveries)
+	router.POST("/admin/webhooks/deliveries/:deliveryId/replay", h.PostAdminWebhookDeliveriesDeliveryIdReplay)
+	router.POST("/admin/price-lists", h.PostAdminPriceLists)
+	router.GET("/admin/price-lists", h.GetAdminPriceLists)
+	router.GET("/admin/price-lists/:priceListId", h.GetAdminPriceListsPriceListId)
+	router.PATCH("/admin/price-lists/:priceListId", h.PatchAdminPriceListsPriceListId)
+	router.PUT("/admin/price-lists/:priceListId/assignments", h.PutAdminPriceListsPriceListIdAssignments)
+	router.POST("/admin/price-lists/:priceListId/rules", h.PostAdminPriceListsPriceListIdRules)
+	router.DELETE("/admin/price-lists/:priceListId/rules/:ruleId", h.DeleteAdminPriceListsPriceListIdRulesRuleId)
+	router.GET("/admin/pricing/preview", h.GetAdminPricingPreview)
+	router.POST("/admin/promotions", h.PostAdminPromotions)
+	router.GET("/admin/promotions", h.GetAdminPromotions)
+	router.GET("/admin/promotions/:promotionId", h.GetAdminPromotionsPromotionId)
+	router.PATCH("/admin/promotions/:promotionId", h.PatchAdminPromotionsPromotionId)
+	router.POST("/admin/promotions/:promotionId/coupons", h.PostAdminPromotionsPromotionIdCoupons)
+	router.GET("/admin/promotions/:promotionId/coupons", h.GetAdminPromotionsPromotionIdCoupons)
+	router.POST("/admin/shipping-rules", h.PostAdminShippingRules)
+	router.GET("/admin/shipping-rules", h.GetAdminShippingRules)
+	router.GET("/admin/shipping-rules/:ruleId", h.GetAdminShippingRulesRuleId)
+	router.PATCH("/admin/shipping-rules/:ruleId", h.PatchAdminShippingRulesRuleId)
+	router.DELETE("/admin/shipping-rules/:ruleId", h.DeleteAdminShippingRulesRuleId)
+	router.GET("/admin/miniapp/display-categories", h.GetAdminMiniappDisplayCategories)
+	router.PUT("/admin/miniapp/display-categories", h.PutAdminMiniappDisplayCategories)
+	router.POST("/internal/orders/:orderId/payment-status", h.PostInternalOrdersOrderIdPaymentStatus)
+	router.POST("/internal/events", h.PostInternalEvents)
+	router.POST("/tracking/carriers/:carrier/push", h.PostTrackingCarriersCarrierPush)
+
+}
@@ -0,0 +1,418 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/shipping"
+)
+
+const maxShippingRuleNameLength = 100
+
+type shippingRuleRequest struct {
+	Name                  *string `json:"name"`
+	Province              *string `json:"province"`
+	City                  *string `json:"city"`
+	ChargeType            *string `json:"chargeType"`
+	FirstWeightGrams      *int32  `json:"firstWeightGrams"`
+	FirstFeeFen           *int64  `json:"firstFeeFen"`
+	AdditionalWeightGrams *int32  `json:"additionalWeightGrams"`
+	AdditionalFeeFen      *int64  `json:"additionalFeeFen"`
+	VolumetricDivisor     *int32  `json:"volumetricDivisor"`
+	FlatFeeFen            *int64  `json:"flatFeeFen"`
+	FreeShippingMinFen    *int64  `json:"freeShippingMinFen"`
+	IsActive              *bool   `json:"isActive"`
+}
+
+type shippingRuleResponse struct {
+	ID                    uuid.UUID `json:"id"`
+	Name                  string    `json:"name"`
+	Province              *string   `json:"province,omitempty"`
+	City                  *string   `json:"city,omitempty"`
+	ChargeType            string    `json:"chargeType"`
+	FirstWeightGrams      *int32    `json:"firstWeightGrams,omitempty"`
+	FirstFeeFen           *int64    `json:"firstFeeFen,omitempty"`
+	AdditionalWeightGrams *int32    `json:"additionalWeightGrams,omitempty"`
+	AdditionalFeeFen      *int64    `json:"additionalFeeFen,omitempty"`
+	VolumetricDivisor     *int32    `json:"volumetricDivisor,omitempty"`
+	FlatFeeFen            *int64    `json:"flatFeeFen,omitempty"`
+	FreeShippingMinFen    *int64    `json:"freeShippingMinFen,omitempty"`
+	IsActive              bool      `json:"isActive"`
+	CreatedBy             uuid.UUID `json:"createdBy"`
+	CreatedAt             time.Time `json:"createdAt"`
+	UpdatedAt             time.Time `json:"updatedAt"`
+}
+
+type shippingRuleListResponse struct {
+	Items    []shippingRuleResponse `json:"items"`
+	Page     int                    `json:"page"`
+	PageSize int                    `json:"pageSize"`
+	Total    int64                  `json:"total"`
+}
+
+func (h *Handler) PostAdminShippingRules(c *gin.Context) {
+	claims, ok := h.requireAllScope(c, authz.PermissionPricingManage)
+	if !ok {
+		return
+	}
+	if h.ShippingStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "shipping rules are not configured")
+		return
+	}
+
+	var request shippingRuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	rule := db.UpdateShippingRuleParams{
+		FirstWeightGrams:      request.FirstWeightGrams,
+		FirstFeeFen:           request.FirstFeeFen,
+		AdditionalWeightGrams: request.AdditionalWeightGrams,
+		AdditionalFeeFen:      request.AdditionalFeeFen,
+		VolumetricDivisor:     request.VolumetricDivisor,
+		FlatFeeFen:            request.FlatFeeFen,
+		FreeShippingMinFen:    request.FreeShippingMinFen,
+		IsActive:              true,
+	}
+	if request.Name != nil {
+		rule.Name = strings.TrimSpace(*request.Name)
+	}
+	if request.Province != nil {
+		rule.Province = normalizeOptionalText(*request.Province)
+	}
+	if request.City != nil {
+		rule.City = normalizeOptionalText(*request.City)
+	}
+	if request.ChargeType != nil {
+		rule.ChargeType = strings.ToUpper(strings.TrimSpace(*request.ChargeType))
+	}
+	if request.IsActive != nil {
+		rule.IsActive = *request.IsActive
+	}
+	if message := validateShippingRule(rule); message != "" {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", message)
+		return
+	}
+
+	row, err := h.ShippingStore.CreateShippingRule(c.Request.Context(), db.CreateShippingRuleParams{
+		Name:                  rule.Name,
+		Province:              rule.Province,
+		City:                  rule.City,
+		ChargeType:            rule.ChargeType,
+		FirstWeightGrams:      rule.FirstWeightGrams,
+		FirstFeeFen:           rule.FirstFeeFen,
+		AdditionalWeightGrams: rule.AdditionalWeightGrams,
+		AdditionalFeeFen:      rule.AdditionalFeeFen,
+		VolumetricDivisor:     rule.VolumetricDivisor,
+		FlatFeeFen:            rule.FlatFeeFen,
+		FreeShippingMinFen:    rule.FreeShippingMinFen,
+		IsActive:              rule.IsActive,
+		CreatedBy:             claims.UserID,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			h.writeError(c, http.StatusConflict, "shipping_rule_exists", "a shipping rule already covers this zone")
+			return
+		}
+		h.logError("create shipping rule failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to create shipping rule")
+		return
+	}
+	c.JSON(http.StatusCreated, shippingRuleFromModel(row))
+}
+
+func (h *Handler) GetAdminShippingRules(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionPricingManage); !ok {
+		return
+	}
+	if h.ShippingStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "shipping rules are not configured")
+		return
+	}
+
+	page := parseAdminPositiveInt(c.Query("page"), 1)
+	pageSize := parseAdminPositiveInt(c.Query("pageSize"), 20)
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	rows, err := h.ShippingStore.ListShippingRules(c.Request.Context(), db.ListShippingRulesParams{
+		Limit:  clampInt32(pageSize),
+		Offset: clampInt32((page - 1) * pageSize),
+	})
+	if err != nil {
+		h.logError("list shipping rules failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list shipping rules")
+		return
+	}
+	total, err := h.ShippingStore.CountShippingRules(c.Request.Context())
+	if err != nil {
+		h.logError("count shipping rules failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list shipping rules")
+		return
+	}
+
+	items := make([]shippingRuleResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, shippingRuleFromModel(row))
+	}
+	c.JSON(http.StatusOK, shippingRuleListResponse{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	})
+}
+
+func (h *Handler) GetAdminShippingRulesRuleId(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionPricingManage); !ok {
+		return
+	}
+	row, ok := h.loadShippingRule(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, shippingRuleFromModel(row))
+}
+
+// PatchAdminShippingRulesRuleId changes the fields present in the body. When
+// chargeType changes, the settings of the previous charge type are dropped
+// so the body only has to describe the new one.
+func (h *Handler) PatchAdminShippingRulesRuleId(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionPricingManage); !ok {
+		return
+	}
+	current, ok := h.loadShippingRule(c)
+	if !ok {
+		return
+	}
+
+	var request shippingRuleRequest
+	fields, err := decodeJSONFields(c, &request)
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	update := db.UpdateShippingRuleParams{
+		ID:                    current.ID,
+		Name:                  current.Name,
+		Province:              current.Province,
+		City:                  current.City,
+		ChargeType:            current.ChargeType,
+		FirstWeightGrams:      current.FirstWeightGrams,
+		FirstFeeFen:           current.FirstFeeFen,
+		AdditionalWeightGrams: current.AdditionalWeightGrams,
+		AdditionalFeeFen:      current.AdditionalFeeFen,
+		VolumetricDivisor:     current.VolumetricDivisor,
+		FlatFeeFen:            current.FlatFeeFen,
+		FreeShippingMinFen:    current.FreeShippingMinFen,
+		IsActive:              current.IsActive,
+	}
+	if request.Name != nil {
+		update.Name = strings.TrimSpace(*request.Name)
+	}
+	// An explicit null widens the zone to the whole province or the default.
+	if hasJSONField(fields, "province") {
+		update.Province = nil
+		if request.Province != nil {
+			update.Province = normalizeOptionalText(*request.Province)
+		}
+	}
+	if hasJSONField(fields, "city") {
+		update.City = nil
+		if request.City != nil {
+			update.City = normalizeOptionalText(*request.City)
+		}
+	}
+	if request.ChargeType != nil {
+		chargeType := strings.ToUpper(strings.TrimSpace(*request.ChargeType))
+		if chargeType != update.ChargeType {
+			update.FirstWeightGrams = nil
+			update.FirstFeeFen = nil
+			update.AdditionalWeightGrams = nil
+			update.AdditionalFeeFen = nil
+			update.VolumetricDivisor = nil
+			update.FlatFeeFen = nil
+		}
+		update.ChargeType = chargeType
+	}
+	if hasJSONField(fields, "firstWeightGrams") {
+		update.FirstWeightGrams = request.FirstWeightGrams
+	}
+	if hasJSONField(fields, "firstFeeFen") {
+		update.FirstFeeFen = request.FirstFeeFen
+	}
+	if hasJSONField(fields, "additionalWeightGrams") {
+		update.AdditionalWeightGrams = request.AdditionalWeightGrams
+	}
+	if hasJSONField(fields, "additionalFeeFen") {
+		update.AdditionalFeeFen = request.AdditionalFeeFen
+	}
+	if hasJSONField(fields, "volumetricDivisor") {
+		update.VolumetricDivisor = request.VolumetricDivisor
+	}
+	if hasJSONField(fields, "flatFeeFen") {
+		update.FlatFeeFen = request.FlatFeeFen
+	}
+	if hasJSONField(fields, "freeShippingMinFen") {
+		update.FreeShippingMinFen = request.FreeShippingMinFen
+	}
+	if request.IsActive != nil {
+		update.IsActive = *request.IsActive
+	}
+	if message := validateShippingRule(update); message != "" {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", message)
+		return
+	}
+
+	row, err := h.ShippingStore.UpdateShippingRule(c.Request.Context(), update)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusNotFound, "not_found", "shipping rule not found")
+			return
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			h.writeError(c, http.StatusConflict, "shipping_rule_exists", "a shipping rule already covers this zone")
+			return
+		}
+		h.logError("update shipping rule failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to update shipping rule")
+		return
+	}
+	c.JSON(http.StatusOK, shippingRuleFromModel(row))
+}
+
+func (h *Handler) DeleteAdminShippingRulesRuleId(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionPricingManage); !ok {
+		return
+	}
+	ruleID, ok := h.parseShippingRuleID(c)
+	if !ok {
+		return
+	}
+	deleted, err := h.ShippingStore.DeleteShippingRule(c.Request.Context(), ruleID)
+	if err != nil {
+		h.logError("delete shipping rule failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to delete shipping rule")
+		return
+	}
+	if deleted == 0 {
+		h.writeError(c, http.StatusNotFound, "not_found", "shipping rule not found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// loadShippingRule fetches the shipping rule named in the path.
+func (h *Handler) loadShippingRule(c *gin.Context) (db.ShippingRule, bool) {
+	ruleID, ok := h.parseShippingRuleID(c)
+	if !ok {
+		return db.ShippingRule{}, false
+	}
+	row, err := h.ShippingStore.GetShippingRule(c.Request.Context(), ruleID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusNotFound, "not_found", "shipping rule not found")
+			return db.ShippingRule{}, false
+		}
+		h.logError("get shipping rule failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch shipping rule")
+		return db.ShippingRule{}, false
+	}
+	return row, true
+}
+
+func (h *Handler) parseShippingRuleID(c *gin.Context) (uuid.UUID, bool) {
+	if h.ShippingStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "shipping rules are not configured")
+		return uuid.Nil, false
+	}
+	ruleID, err := uuid.Parse(strings.TrimSpace(c.Param("ruleId")))
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid ruleId")
+		return uuid.Nil, false
+	}
+	return ruleID, true
+}
+
+// validateShippingRule mirrors the table constraints so callers get a
+// message instead of a constraint violation.
+func validateShippingRule(rule db.UpdateShippingRuleParams) string {
+	if rule.Name == "" {
+		return "name is required"
+	}
+	if len([]rune(rule.Name)) > maxShippingRuleNameLength {
+		return "name supports at most 100 characters"
+	}
+	if rule.City != nil && rule.Province == nil {
+		return "city requires province"
+	}
+	switch rule.ChargeType {
+	case shipping.ChargeWeight:
+		if rule.FirstWeightGrams == nil || *rule.FirstWeightGrams < 1 {
+			return "firstWeightGrams must be >= 1 for WEIGHT"
+		}
+		if rule.FirstFeeFen == nil || *rule.FirstFeeFen < 0 {
+			return "firstFeeFen must be >= 0 for WEIGHT"
+		}
+		if rule.AdditionalWeightGrams == nil || *rule.AdditionalWeightGrams < 1 {
+			return "additionalWeightGrams must be >= 1 for WEIGHT"
+		}
+		if rule.AdditionalFeeFen == nil || *rule.AdditionalFeeFen < 0 {
+			return "additionalFeeFen must be >= 0 for WEIGHT"
+		}
+		if rule.VolumetricDivisor != nil && *rule.VolumetricDivisor < 1 {
+			return "volumetricDivisor must be >= 1"
+		}
+		if rule.FlatFeeFen != nil {
+			return "flatFeeFen is only allowed for FLAT"
+		}
+	case shipping.ChargeFlat:
+		if rule.FlatFeeFen == nil || *rule.FlatFeeFen < 0 {
+			return "flatFeeFen must be >= 0 for FLAT"
+		}
+		if rule.FirstWeightGrams != nil || rule.FirstFeeFen != nil ||
+			rule.AdditionalWeightGrams != nil || rule.AdditionalFeeFen != nil ||
+			rule.VolumetricDivisor != nil {
+			return "weight settings are only allowed for WEIGHT"
+		}
+	default:
+		return "chargeType must be WEIGHT or FLAT"
+	}
+	if rule.FreeShippingMinFen != nil && *rule.FreeShippingMinFen < 0 {
+		return "freeShippingMinFen must be >= 0"
+	}
+	return ""
+}
+
+func shippingRuleFromModel(row db.ShippingRule) shippingRuleResponse {
+	return shippingRuleResponse{
+		ID:                    row.ID,
+		Name:                  row.Name,
+		Province:              row.Province,
+		City:                  row.City,
+		ChargeType:            row.ChargeType,
+		FirstWeightGrams:      row.FirstWeightGrams,
+		FirstFeeFen:           row.FirstFeeFen,
+		AdditionalWeightGrams: row.AdditionalWeightGrams,
+		AdditionalFeeFen:      row.AdditionalFeeFen,
+		VolumetricDivisor:     row.VolumetricDivisor,
+		FlatFeeFen:            row.FlatFeeFen,
+		FreeShippingMinFen:    row.FreeShippingMinFen,
+		IsActive:              row.IsActive,
+		CreatedBy:             row.CreatedBy,
+		CreatedAt:             row.CreatedAt.Time,
+		UpdatedAt:             row.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+)
+
+func TestShippingRulesChargeOrders(t *testing.T) {
+	pool := openHandlerTestPool(t)
+	resetCommerceTables(t, pool)
+
+	queries := db.New(pool)
+	skuA, _ := seedCatalog(t, queries)
+	ctx := context.Background()
+	if _, err := pool.Exec(ctx, `UPDATE catalog_skus SET weight_grams = 700 WHERE id = $1`, skuA.ID); err != nil {
+		t.Fatalf("set sku weight: %v", err)
+	}
+	customerID := uuid.New()
+	router := newServerIntegrationRouter(pool, queries, nil)
+	adminToken := makeAuthToken(t, uuid.New(), "ADMIN", nil)
+	customerToken := makeAuthToken(t, customerID, "CUSTOMER", nil)
+
+	doJSONRequest(t, router, http.MethodPost, "/admin/shipping-rules", customerToken,
+		`{"name":"Default","chargeType":"FLAT","flatFeeFen":800}`, http.StatusForbidden, nil)
+	doJSONRequest(t, router, http.MethodPost, "/admin/shipping-rules", adminToken,
+		`{"name":"Hangzhou","city":"杭州市","chargeType":"FLAT","flatFeeFen":0}`, http.StatusBadRequest, nil)
+	doJSONRequest(t, router, http.MethodPost, "/admin/shipping-rules", adminToken,
+		`{"name":"Default","chargeType":"FLAT","flatFeeFen":800,"firstWeightGrams":1000}`, http.StatusBadRequest, nil)
+	doJSONRequest(t, router, http.MethodPost, "/admin/shipping-rules", adminToken,
+		`{"name":"Zhejiang","province":"浙江省","chargeType":"WEIGHT","firstWeightGrams":1000,"firstFeeFen":1000}`, http.StatusBadRequest, nil)
+
+	var fallback shippingRuleResponse
+	doJSONRequest(t, router, http.MethodPost, "/admin/shipping-rules", adminToken,
+		`{"name":"Default","chargeType":"FLAT","flatFeeFen":800}`, http.StatusCreated, &fallback)
+	var zhejiang shippingRuleResponse
+	doJSONRequest(t, router, http.MethodPost, "/admin/shipping-rules", adminToken,
+		`{"name":"Zhejiang","province":" 浙江省 ","chargeType":"WEIGHT","firstWeightGrams":1000,"firstFeeFen":1000,"additionalWeightGrams":500,"additionalFeeFen":300,"freeShippingMinFen":50000}`, http.StatusCreated, &zhejiang)
+	if zhejiang.Province == nil || *zhejiang.Province != "浙江省" {
+		t.Fatalf("unexpected province %v", zhejiang.Province)
+	}
+	doJSONRequest(t, router, http.MethodPost, "/admin/shipping-rules", adminToken,
+		`{"name":"Zhejiang again","province":"浙江省","chargeType":"FLAT","flatFeeFen":0}`, http.StatusConflict, nil)
+
+	var rules shippingRuleListResponse
+	doJSONRequest(t, router, http.MethodGet, "/admin/shipping-rules", adminToken, "", http.StatusOK, &rules)
+	if rules.Total != 2 || len(rules.Items) != 2 || rules.Items[0].ID != fallback.ID {
+		t.Fatalf("unexpected shipping rules %+v", rules)
+	}
+
+	addCartItem := func() db.CartItem {
+		t.Helper()
+		cartItem, err := queries.UpsertCartItem(ctx, db.UpsertCartItemParams{OwnerUserID: customerID, SkuID: skuA.ID, Qty: 2})
+		if err != nil {
+			t.Fatalf("seed cart item: %v", err)
+		}
+		return cartItem
+	}
+	address := func(province string) string {
+		return fmt.Sprintf(`{"receiverName":"A","receiverPhone":"1","province":"%s","city":"杭州市","detail":"X"}`, province)
+	}
+
+	// Two 700 g pipes weigh 1400 g: the first kilogram and one started
+	// 500 g step.
+	cartItem := addCartItem()
+	var order oapi.Order
+	doJSONRequest(t, router, http.MethodPost, "/orders", customerToken,
+		fmt.Sprintf(`{"address":%s,"items":[{"cartItemId":"%s","skuId":"%s","qty":2}]}`, address("浙江省"), cartItem.ID, skuA.ID), http.StatusCreated, &order)
+	if order.ShippingFen == nil || *order.ShippingFen != 1300 || *order.TotalFen != 25300 {
+		t.Fatalf("unexpected order freight %v/%v", order.ShippingFen, order.TotalFen)
+	}
+	var fetched oapi.Order
+	doJSONRequest(t, router, http.MethodGet, "/orders/"+order.Id.String(), customerToken, "", http.StatusOK, &fetched)
+	if *fetched.ShippingFen != 1300 || *fetched.TotalFen != 25300 {
+		t.Fatalf("unexpected fetched order %+v", fetched)
+	}
+
+	preview := func(province string) oapi.CheckoutPreview {
+		t.Helper()
+		cartItem := addCartItem()
+		var preview oapi.CheckoutPreview
+		doJSONRequest(t, router, http.MethodPost, "/checkout/preview", customerToken,
+			fmt.Sprintf(`{"address":%s,"items":[{"cartItemId":"%s","skuId":"%s","qty":2}]}`, address(province), cartItem.ID, skuA.ID), http.StatusOK, &preview)
+		return preview
+	}
+
+	// Destinations outside Zhejiang fall back to the default rule.
+	if got := preview("广东省"); got.ShippingFen != 800 || got.TotalFen != 24800 {
+		t.Fatalf("unexpected default freight %d/%d", got.ShippingFen, got.TotalFen)
+	}
+
+	// Lowering the threshold below the order makes it ship free.
+	doJSONRequest(t, router, http.MethodPatch, "/admin/shipping-rules/"+zhejiang.ID.String(), adminToken, `{"freeShippingMinFen":20000}`, http.StatusOK, nil)
+	if got := preview("浙江省"); got.ShippingFen != 0 || got.TotalFen != 24000 {
+		t.Fatalf("unexpected free freight %d/%d", got.ShippingFen, got.TotalFen)
+	}
+
+	// Switching to a flat fee drops the weight settings.
+	var flat shippingRuleResponse
+	doJSONRequest(t, router, http.MethodPatch, "/admin/shipping-rules/"+zhejiang.ID.String(), adminToken,
+		`{"chargeType":"FLAT","flatFeeFen":500,"freeShippingMinFen":null}`, http.StatusOK, &flat)
+	if flat.FirstWeightGrams != nil || flat.FlatFeeFen == nil || *flat.FlatFeeFen != 500 || flat.FreeShippingMinFen != nil {
+		t.Fatalf("unexpected flat rule %+v", flat)
+	}
+	if got := preview("浙江省"); got.ShippingFen != 500 {
+		t.Fatalf("unexpected flat freight %d", got.ShippingFen)
+	}
+
+	// Without a default rule other destinations ship free.
+	doJSONRequest(t, router, http.MethodDelete, "/admin/shipping-rules/"+fallback.ID.String(), adminToken, "", http.StatusNoContent, nil)
+	doJSONRequest(t, router, http.MethodDelete, "/admin/shipping-rules/"+fallback.ID.String(), adminToken, "", http.StatusNotFound, nil)
+	if got := preview("广东省"); got.ShippingFen != 0 {
+		t.Fatalf("unexpected uncovered freight %d", got.ShippingFen)
+	}
+}
@@ -7,9 +7,15 @@ import (
 
 	"github.com/teamdsb/tmo/services/commerce/internal/db"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/pricing"
 )
 
 func (h *Handler) loadSkusWithTiers(ctx context.Context, skuIDs []uuid.UUID) (map[uuid.UUID]oapi.SKU, error) {
+	return h.loadSkusForBuyer(ctx, pricing.Buyer{}, skuIDs)
+}
+
+// loadSkusForBuyer maps SKUs with the tiers buyer pays.
+func (h *Handler) loadSkusForBuyer(ctx context.Context, buyer pricing.Buyer, skuIDs []uuid.UUID) (map[uuid.UUID]oapi.SKU, error) {
 	unique := uniqueUUIDs(skuIDs)
 	result := make(map[uuid.UUID]oapi.SKU, len(unique))
 	if len(unique) == 0 {
@@ -31,8 +37,18 @@ func (h *Handler) loadSkusWithTiers(ctx context.Context, skuIDs []uuid.UUID) (ma
 		tiersBySku[tier.SkuID] = append(tiersBySku[tier.SkuID], tier)
 	}
 
+	prices, err := h.resolvePrices(ctx, buyer, unique, tiersBySku)
+	if err != nil {
+		return nil, err
+	}
+
+	stock, err := h.loadSkuInventory(ctx, unique)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, sku := range skus {
-		mapped, err := skuFromModel(sku, tiersBySku[sku.ID])
+		mapped, err := pricedSkuFromModel(sku, prices[sku.ID], stockFor(stock, sku.ID))
 		if err != nil {
 			return nil, err
 		}
@@ -42,6 +58,37 @@ func (h *Handler) loadSkusWithTiers(ctx context.Context, skuIDs []uuid.UUID) (ma
 	return result, nil
 }
 
+func (h *Handler) loadSkuInventory(ctx context.Context, skuIDs []uuid.UUID) (map[uuid.UUID]db.SkuInventory, error) {
+	result := map[uuid.UUID]db.SkuInventory{}
+	if h.InventoryStore == nil || len(skuIDs) == 0 {
+		return result, nil
+	}
+	records, err := h.InventoryStore.ListSkuInventoryBySkuIDs(ctx, skuIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		result[record.SkuID] = record
+	}
+	return result, nil
+}
+
+func stockFor(stock map[uuid.UUID]db.SkuInventory, skuID uuid.UUID) *db.SkuInventory {
+	record, ok := stock[skuID]
+	if !ok {
+		return nil
+	}
+	return &record
+}
+
+func skuIDsOf(skus []db.CatalogSku) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(skus))
+	for _, sku := range skus {
+		ids = append(ids, sku.ID)
+	}
+	return ids
+}
+
 func uniqueUUIDs(values []uuid.UUID) []uuid.UUID {
 	seen := make(map[uuid.UUID]struct{}, len(values))
 	unique := make([]uuid.UUID, 0, len(values))
@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/statement"
+)
+
+type customerStatementRequest struct {
+	CustomerID string `json:"customerId"`
+	Period     string `json:"period"`
+}
+
+type customerStatementResponse struct {
+	ID                string     `json:"id"`
+	CustomerID        string     `json:"customerId"`
+	Period            string     `json:"period"`
+	PeriodStart       time.Time  `json:"periodStart"`
+	PeriodEnd         time.Time  `json:"periodEnd"`
+	Status            string     `json:"status"`
+	OpeningBalanceFen int64      `json:"openingBalanceFen"`
+	ChargesFen        int64      `json:"chargesFen"`
+	PaymentsFen       int64      `json:"paymentsFen"`
+	AdjustmentsFen    int64      `json:"adjustmentsFen"`
+	ClosingBalanceFen int64      `json:"closingBalanceFen"`
+	DownloadURL       *string    `json:"downloadUrl,omitempty"`
+	ErrorMessage      *string    `json:"errorMessage,omitempty"`
+	GeneratedAt       *time.Time `json:"generatedAt,omitempty"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	UpdatedAt         time.Time  `json:"updatedAt"`
+}
+
+type customerStatementListResponse struct {
+	Items    []customerStatementResponse `json:"items"`
+	Page     int                         `json:"page"`
+	PageSize int                         `json:"pageSize"`
+	Total    int64                       `json:"total"`
+}
+
+func (h *Handler) PostAdminStatements(c *gin.Context) {
+	claims, ok := h.requireAllScope(c, authz.PermissionStatementManage)
+	if !ok {
+		return
+	}
+	if h.StatementStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "statements are not configured")
+		return
+	}
+
+	var request customerStatementRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	customerID, err := uuid.Parse(strings.TrimSpace(request.CustomerID))
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid customerId")
+		return
+	}
+	period, err := statement.ParsePeriod(request.Period)
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	if period.Start.After(time.Now()) {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "period has not started yet")
+		return
+	}
+
+	row, err := h.StatementStore.EnqueueCustomerStatement(c.Request.Context(), db.EnqueueCustomerStatementParams{
+		CustomerID:  customerID,
+		Period:      period.Label,
+		PeriodStart: pgtype.Timestamptz{Time: period.Start, Valid: true},
+		PeriodEnd:   pgtype.Timestamptz{Time: period.End, Valid: true},
+		RequestedBy: claims.UserID,
+	})
+	if err != nil {
+		h.logError("enqueue customer statement failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to create statement")
+		return
+	}
+	c.JSON(http.StatusAccepted, adminStatementFromModel(row))
+}
+
+func (h *Handler) GetAdminStatements(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionStatementRead); !ok {
+		return
+	}
+	if h.StatementStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "statements are not configured")
+		return
+	}
+
+	customerID, ok := parseOptionalUUIDQuery(c.Query("customerId"))
+	if !ok {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid customerId")
+		return
+	}
+	var period *string
+	if raw := strings.TrimSpace(c.Query("period")); raw != "" {
+		parsed, err := statement.ParsePeriod(raw)
+		if err != nil {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", err.Error())
+			return
+		}
+		period = &parsed.Label
+	}
+	var status *string
+	if raw := strings.ToUpper(strings.TrimSpace(c.Query("status"))); raw != "" {
+		switch raw {
+		case statement.StatusPending, statement.StatusRunning, statement.StatusSucceeded, statement.StatusFailed:
+			status = &raw
+		default:
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "status must be one of PENDING, RUNNING, SUCCEEDED, FAILED")
+			return
+		}
+	}
+
+	h.listStatements(c, customerID, period, status, adminStatementFromModel)
+}
+
+func (h *Handler) GetAdminStatementsStatementId(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionStatementRead); !ok {
+		return
+	}
+	row, ok := h.loadStatement(c, uuid.Nil)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, adminStatementFromModel(row))
+}
+
+func (h *Handler) GetAdminStatementsStatementIdDownload(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionStatementRead); !ok {
+		return
+	}
+	row, ok := h.loadStatement(c, uuid.Nil)
+	if !ok {
+		return
+	}
+	h.serveStatementFile(c, row)
+}
+
+func (h *Handler) GetStatements(c *gin.Context) {
+	claims, _, ok := h.requirePermission(c, authz.PermissionStatementRead)
+	if !ok {
+		return
+	}
+	if h.StatementStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "statements are not configured")
+		return
+	}
+
+	succeeded := statement.StatusSucceeded
+	h.listStatements(c, pgtype.UUID{Bytes: claims.UserID, Valid: true}, nil, &succeeded, customerStatementFromModel)
+}
+
+func (h *Handler) GetStatementsStatementIdDownload(c *gin.Context) {
+	claims, _, ok := h.requirePermission(c, authz.PermissionStatementRead)
+	if !ok {
+		return
+	}
+	row, ok := h.loadStatement(c, claims.UserID)
+	if !ok {
+		return
+	}
+	h.serveStatementFile(c, row)
+}
+
+func (h *Handler) listStatements(c *gin.Context, customerID pgtype.UUID, period, status *string, mapper func(db.CustomerStatement) customerStatementResponse) {
+	page := parseAdminPositiveInt(c.Query("page"), 1)
+	pageSize := parseAdminPositiveInt(c.Query("pageSize"), 20)
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	offset := (page - 1) * pageSize
+
+	rows, err := h.StatementStore.ListCustomerStatements(c.Request.Context(), db.ListCustomerStatementsParams{
+		CustomerID: customerID,
+		Period:     period,
+		Status:     status,
+		Offset:     clampInt32(offset),
+		Limit:      clampInt32(pageSize),
+	})
+	if err != nil {
+		h.logError("list customer statements failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list statements")
+		return
+	}
+	total, err := h.StatementStore.CountCustomerStatements(c.Request.Context(), db.CountCustomerStatementsParams{
+		CustomerID: customerID,
+		Period:     period,
+		Status:     status,
+	})
+	if err != nil {
+		h.logError("count customer statements failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list statements")
+		return
+	}
+
+	items := make([]customerStatementResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, mapper(row))
+	}
+	c.JSON(http.StatusOK, customerStatementListResponse{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	})
+}
+
+// loadStatement fetches the statement named in the path. When customerID is
+// set, statements of other customers are reported as not found.
+func (h *Handler) loadStatement(c *gin.Context, customerID uuid.UUID) (db.CustomerStatement, bool) {
+	if h.StatementStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "statements are not configured")
+		return db.CustomerStatement{}, false
+	}
+	statementID, err := uuid.Parse(strings.TrimSpace(c.Param("statementId")))
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid statementId")
+		return db.CustomerStatement{}, false
+	}
+	row, err := h.StatementStore.GetCustomerStatement(c.Request.Context(), statementID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusNotFound, "not_found", "statement not found")
+			return db.CustomerStatement{}, false
+		}
+		h.logError("get customer statement failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch statement")
+		return db.CustomerStatement{}, false
+	}
+	if customerID != uuid.Nil && (row.CustomerID != customerID || row.Status != statement.StatusSucceeded) {
+		h.writeError(c, http.StatusNotFound, "not_found", "statement not found")
+		return db.CustomerStatement{}, false
+	}
+	return row, true
+}
+
+func (h *Handler) serveStatementFile(c *gin.Context, row db.CustomerStatement) {
+	if row.Status != statement.StatusSucceeded {
+		h.writeError(c, http.StatusConflict, "statement_not_ready", "statement has not been generated yet")
+		return
+	}
+	if !statement.FileExists(h.MediaLocalOutputDir, row.FilePath) {
+		h.writeError(c, http.StatusNotFound, "not_found", "statement file not found")
+		return
+	}
+	c.FileAttachment(statement.LocalPath(h.MediaLocalOutputDir, *row.FilePath), statement.FileName(row.Period))
+}
+
+func adminStatementFromModel(row db.CustomerStatement) customerStatementResponse {
+	response := statementFromModel(row)
+	response.ErrorMessage = row.ErrorMessage
+	if row.Status == statement.StatusSucceeded {
+		url := "/admin/statements/" + row.ID.String() + "/download"
+		response.DownloadURL = &url
+	}
+	return response
+}
+
+func customerStatementFromModel(row db.CustomerStatement) customerStatementResponse {
+	response := statementFromModel(row)
+	if row.Status == statement.StatusSucceeded {
+		url := "/statements/" + row.ID.String() + "/download"
+		response.DownloadURL = &url
+	}
+	return response
+}
+
+func statementFromModel(row db.CustomerStatement) customerStatementResponse {
+	return customerStatementResponse{
+		ID:                row.ID.String(),
+		CustomerID:        row.CustomerID.String(),
+		Period:            row.Period,
+		PeriodStart:       row.PeriodStart.Time,
+		PeriodEnd:         row.PeriodEnd.Time,
+		Status:            row.Status,
+		OpeningBalanceFen: row.OpeningBalanceFen,
+		ChargesFen:        row.ChargesFen,
+		PaymentsFen:       row.PaymentsFen,
+		AdjustmentsFen:    row.AdjustmentsFen,
+		ClosingBalanceFen: row.ClosingBalanceFen,
+		GeneratedAt:       timePtrFromPg(row.GeneratedAt),
+		CreatedAt:         row.CreatedAt.Time,
+		UpdatedAt:         row.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/teamdsb/tmo/packages/go-shared/httpx"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/middleware"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/statement"
+)
+
+type stubStatementStore struct {
+	statements map[uuid.UUID]db.CustomerStatement
+	enqueued   []db.EnqueueCustomerStatementParams
+	listed     []db.ListCustomerStatementsParams
+}
+
+func (s *stubStatementStore) EnqueueCustomerStatement(_ context.Context, arg db.EnqueueCustomerStatementParams) (db.CustomerStatement, error) {
+	s.enqueued = append(s.enqueued, arg)
+	return db.CustomerStatement{
+		ID: uuid.New(), CustomerID: arg.CustomerID, Period: arg.Period,
+		PeriodStart: arg.PeriodStart, PeriodEnd: arg.PeriodEnd, Status: statement.StatusPending, RequestedBy: arg.RequestedBy,
+	}, nil
+}
+
+func (s *stubStatementStore) GetCustomerStatement(_ context.Context, id uuid.UUID) (db.CustomerStatement, error) {
+	row, ok := s.statements[id]
+	if !ok {
+		return db.CustomerStatement{}, pgx.ErrNoRows
+	}
+	return row, nil
+}
+
+func (s *stubStatementStore) ListCustomerStatements(_ context.Context, arg db.ListCustomerStatementsParams) ([]db.CustomerStatement, error) {
+	s.listed = append(s.listed, arg)
+	items := make([]db.CustomerStatement, 0, len(s.statements))
+	for _, row := range s.statements {
+		items = append(items, row)
+	}
+	return items, nil
+}
+
+func (s *stubStatementStore) CountCustomerStatements(context.Context, db.CountCustomerStatementsParams) (int64, error) {
+	return int64(len(s.statements)), nil
+}
+
+func TestStatementEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mediaDir := t.TempDir()
+	customerID, otherCustomerID := uuid.New(), uuid.New()
+
+	ready := db.CustomerStatement{ID: uuid.New(), CustomerID: customerID, Period: "2026-08", Status: statement.StatusSucceeded, ClosingBalanceFen: 1200}
+	filePath := statement.FilePath(ready)
+	ready.FilePath = &filePath
+	localPath := statement.LocalPath(mediaDir, filePath)
+	if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+		t.Fatalf("create statement dir: %v", err)
+	}
+	if err := os.WriteFile(localPath, []byte("xlsx"), 0o600); err != nil {
+		t.Fatalf("write statement file: %v", err)
+	}
+	running := db.CustomerStatement{ID: uuid.New(), CustomerID: customerID, Period: "2026-09", Status: statement.StatusRunning}
+
+	store := &stubStatementStore{statements: map[uuid.UUID]db.CustomerStatement{ready.ID: ready, running.ID: running}}
+	handler := &Handler{
+		Auth:                middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer),
+		StatementStore:      store,
+		MediaLocalOutputDir: mediaDir,
+	}
+	router := httpx.NewRouter()
+	router.POST("/admin/statements", handler.PostAdminStatements)
+	router.GET("/admin/statements/:statementId/download", handler.GetAdminStatementsStatementIdDownload)
+	router.GET("/statements", handler.GetStatements)
+	router.GET("/statements/:statementId/download", handler.GetStatementsStatementIdDownload)
+
+	request := func(method, path, body string, userID uuid.UUID, role string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+makeAuthToken(t, userID, role, nil))
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	body := `{"customerId":"` + customerID.String() + `","period":"2026-08"}`
+	if recorder := request(http.MethodPost, "/admin/statements", body, uuid.New(), "SALES"); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected SALES to be rejected, got %d", recorder.Code)
+	}
+	if recorder := request(http.MethodPost, "/admin/statements", `{"customerId":"`+customerID.String()+`","period":"2026-8"}`, uuid.New(), "BOSS"); recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected malformed period 400, got %d", recorder.Code)
+	}
+	future := time.Now().AddDate(0, 2, 0).Format("2006-01")
+	if recorder := request(http.MethodPost, "/admin/statements", `{"customerId":"`+customerID.String()+`","period":"`+future+`"}`, uuid.New(), "BOSS"); recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected future period 400, got %d", recorder.Code)
+	}
+	recorder := request(http.MethodPost, "/admin/statements", body, uuid.New(), "BOSS")
+	if recorder.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if len(store.enqueued) != 1 || store.enqueued[0].Period != "2026-08" || store.enqueued[0].CustomerID != customerID {
+		t.Fatalf("unexpected enqueue %+v", store.enqueued)
+	}
+
+	recorder = request(http.MethodGet, "/statements", "", customerID, "CUSTOMER")
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	listed := store.listed[len(store.listed)-1]
+	if !listed.CustomerID.Valid || uuid.UUID(listed.CustomerID.Bytes) != customerID || listed.Status == nil || *listed.Status != statement.StatusSucceeded {
+		t.Fatalf("expected customer listing to be scoped to own succeeded statements, got %+v", listed)
+	}
+	var list customerStatementListResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	for _, item := range list.Items {
+		if item.ID == ready.ID.String() && (item.DownloadURL == nil || *item.DownloadURL != "/statements/"+ready.ID.String()+"/download") {
+			t.Fatalf("expected customer download URL, got %+v", item)
+		}
+	}
+
+	recorder = request(http.MethodGet, "/statements/"+ready.ID.String()+"/download", "", customerID, "CUSTOMER")
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "xlsx" {
+		t.Fatalf("expected statement file, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if disposition := recorder.Header().Get("Content-Disposition"); !strings.Contains(disposition, "statement-2026-08.xlsx") {
+		t.Fatalf("expected attachment name, got %q", disposition)
+	}
+	if recorder := request(http.MethodGet, "/statements/"+ready.ID.String()+"/download", "", otherCustomerID, "CUSTOMER"); recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected other customer 404, got %d", recorder.Code)
+	}
+	if recorder := request(http.MethodGet, "/statements/"+running.ID.String()+"/download", "", customerID, "CUSTOMER"); recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected unfinished statement to be hidden from customer, got %d", recorder.Code)
+	}
+	if recorder := request(http.MethodGet, "/admin/statements/"+running.ID.String()+"/download", "", uuid.New(), "MANAGER"); recorder.Code != http.StatusConflict {
+		t.Fatalf("expected unfinished statement 409 for admins, got %d", recorder.Code)
+	}
+	if recorder := request(http.MethodGet, "/admin/statements/"+ready.ID.String()+"/download", "", uuid.New(), "MANAGER"); recorder.Code != http.StatusOK {
+		t.Fatalf("expected admin download 200, got %d", recorder.Code)
+	}
+
+	if err := os.Remove(localPath); err != nil {
+		t.Fatalf("remove statement file: %v", err)
+	}
+	if recorder := request(http.MethodGet, "/admin/statements/"+ready.ID.String()+"/download", "", uuid.New(), "MANAGER"); recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected missing file 404, got %d", recorder.Code)
+	}
+}
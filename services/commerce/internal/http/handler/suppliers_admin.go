@@ -13,6 +13,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
 )
 
 type adminSupplierItem struct {
@@ -78,7 +80,7 @@ type patchAdminSupplierRequest struct {
 }
 
 func (h *Handler) GetAdminSuppliers(c *gin.Context) {
-	if _, ok := h.requireRole(c, "SALES", "CS", "MANAGER", "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionSupplierRead); !ok {
 		return
 	}
 	if h.DB == nil {
@@ -187,7 +189,7 @@ LIMIT ` + limitArg + ` OFFSET ` + offsetArg
 }
 
 func (h *Handler) GetAdminSuppliersSupplierId(c *gin.Context) {
-	if _, ok := h.requireRole(c, "SALES", "CS", "MANAGER", "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionSupplierRead); !ok {
 		return
 	}
 
@@ -211,7 +213,7 @@ func (h *Handler) GetAdminSuppliersSupplierId(c *gin.Context) {
 }
 
 func (h *Handler) PatchAdminSuppliersSupplierId(c *gin.Context) {
-	if _, ok := h.requireRole(c, "MANAGER", "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionSupplierManage); !ok {
 		return
 	}
 	if h.DB == nil {
@@ -420,7 +422,7 @@ RETURNING
 }
 
 func (h *Handler) GetAdminSuppliersSupplierIdContacts(c *gin.Context) {
-	if _, ok := h.requireRole(c, "SALES", "CS", "MANAGER", "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionSupplierRead); !ok {
 		return
 	}
 	if h.DB == nil {
@@ -477,7 +479,7 @@ ORDER BY is_primary DESC, updated_at DESC, id ASC
 }
 
 func (h *Handler) GetAdminSuppliersSupplierIdScorecards(c *gin.Context) {
-	if _, ok := h.requireRole(c, "SALES", "CS", "MANAGER", "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionSupplierRead); !ok {
 		return
 	}
 	if h.DB == nil {
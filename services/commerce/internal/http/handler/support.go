@@ -19,6 +19,7 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
 	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
 	"github.com/teamdsb/tmo/services/commerce/internal/db"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/middleware"
@@ -156,7 +157,7 @@ type transferSupportConversationRequest struct {
 }
 
 func (h *Handler) GetSupportConversationsCurrent(c *gin.Context) {
-	claims, ok := h.requireRole(c, "CUSTOMER")
+	claims, _, ok := h.requirePermission(c, authz.PermissionSupportCreate)
 	if !ok {
 		return
 	}
@@ -299,7 +300,7 @@ func (h *Handler) PostSupportConversationsConversationIdRead(c *gin.Context) {
 }
 
 func (h *Handler) GetAdminSupportConversations(c *gin.Context) {
-	claims, ok := h.requireRole(c, "CS", "MANAGER", "BOSS", "ADMIN")
+	claims, ok := h.requireAllScope(c, authz.PermissionSupportManage)
 	if !ok {
 		return
 	}
@@ -981,7 +982,7 @@ func (h *Handler) loadSupportConversationForCommonRoute(c *gin.Context) (middlew
 }
 
 func (h *Handler) loadSupportConversationForAdminRoute(c *gin.Context) (middleware.Claims, db.SupportConversation, bool) {
-	claims, ok := h.requireRole(c, "CS", "MANAGER", "BOSS", "ADMIN")
+	claims, ok := h.requireAllScope(c, authz.PermissionSupportManage)
 	if !ok {
 		return middleware.Claims{}, db.SupportConversation{}, false
 	}
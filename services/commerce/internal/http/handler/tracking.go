@@ -1,29 +1,31 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/oapi-codegen/runtime/types"
 
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
 	"github.com/teamdsb/tmo/services/commerce/internal/db"
-	"github.com/teamdsb/tmo/services/commerce/internal/excel"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/shipmentimport"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/tracking"
 )
 
 func (h *Handler) GetOrdersOrderIdTracking(c *gin.Context, orderId types.UUID) {
-	claims, ok := h.requireUser(c)
+	claims, scope, ok := h.requirePermission(c, authz.PermissionTrackingRead)
 	if !ok {
 		return
 	}
 
-	if strings.EqualFold(claims.Role, "CUSTOMER") {
+	if scope != authz.ScopeAll {
 		order, err := h.OrderStore.GetOrder(c.Request.Context(), uuid.UUID(orderId))
-		if err != nil || order.CustomerID != claims.UserID {
+		if err != nil || !scopeCovers(claims, scope, order.CustomerID, order.OwnerSalesUserID) {
 			h.writeError(c, http.StatusNotFound, "not_found", "order not found")
 			return
 		}
@@ -36,32 +38,17 @@ func (h *Handler) GetOrdersOrderIdTracking(c *gin.Context, orderId types.UUID) {
 		return
 	}
 
-	response := oapi.TrackingInfo{
-		OrderId: orderId,
-		Shipments: make([]struct {
-			Carrier   *string    `json:"carrier"`
-			ShippedAt *time.Time `json:"shippedAt"`
-			WaybillNo string     `json:"waybillNo"`
-		}, 0, len(shipments)),
-	}
-
-	for _, shipment := range shipments {
-		response.Shipments = append(response.Shipments, struct {
-			Carrier   *string    `json:"carrier"`
-			ShippedAt *time.Time `json:"shippedAt"`
-			WaybillNo string     `json:"waybillNo"`
-		}{
-			Carrier:   shipment.Carrier,
-			ShippedAt: timeFromTimestamptz(shipment.ShippedAt),
-			WaybillNo: shipment.WaybillNo,
-		})
+	response, err := h.trackingInfo(c.Request.Context(), uuid.UUID(orderId), shipments)
+	if err != nil {
+		h.logError("list shipment events failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch tracking")
+		return
 	}
-
 	c.JSON(http.StatusOK, response)
 }
 
 func (h *Handler) PostOrdersOrderIdTracking(c *gin.Context, orderId types.UUID) {
-	if _, ok := h.requireRole(c, "PROCUREMENT", "CS", "MANAGER", "BOSS", "ADMIN"); !ok {
+	if _, ok := h.requireAllScope(c, authz.PermissionShipmentManage); !ok {
 		return
 	}
 
@@ -96,36 +83,26 @@ func (h *Handler) PostOrdersOrderIdTracking(c *gin.Context, orderId types.UUID)
 		shipments = append(shipments, shipment)
 	}
 
-	response := oapi.TrackingInfo{
-		OrderId: orderId,
-		Shipments: make([]struct {
-			Carrier   *string    `json:"carrier"`
-			ShippedAt *time.Time `json:"shippedAt"`
-			WaybillNo string     `json:"waybillNo"`
-		}, 0, len(shipments)),
-	}
-
-	for _, shipment := range shipments {
-		response.Shipments = append(response.Shipments, struct {
-			Carrier   *string    `json:"carrier"`
-			ShippedAt *time.Time `json:"shippedAt"`
-			WaybillNo string     `json:"waybillNo"`
-		}{
-			Carrier:   shipment.Carrier,
-			ShippedAt: timeFromTimestamptz(shipment.ShippedAt),
-			WaybillNo: shipment.WaybillNo,
-		})
+	response, err := h.trackingInfo(c.Request.Context(), uuid.UUID(orderId), shipments)
+	if err != nil {
+		h.logError("list shipment events failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch tracking")
+		return
 	}
-
 	c.JSON(http.StatusOK, response)
 }
 
 func (h *Handler) PostShipmentsImportJobs(c *gin.Context) {
-	claims, ok := h.requireRole(c, "PROCUREMENT", "CS", "MANAGER", "BOSS", "ADMIN")
+	claims, ok := h.requireAllScope(c, authz.PermissionImportShipment)
 	if !ok {
 		return
 	}
 
+	if h.ShipmentImport == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "shipment import is not configured")
+		return
+	}
+
 	fileHeader, err := c.FormFile("excelFile")
 	if err != nil {
 		h.writeError(c, http.StatusBadRequest, "invalid_request", "missing file")
@@ -140,146 +117,88 @@ func (h *Handler) PostShipmentsImportJobs(c *gin.Context) {
 		_ = file.Close()
 	}()
 
-	rows, err := excel.ReadRows(file)
+	job, err := h.ShipmentImport.Enqueue(c.Request.Context(), shipmentimport.EnqueueInput{
+		CreatedByUserID: pgtype.UUID{Bytes: claims.UserID, Valid: true},
+		ExcelFile:       file,
+		ExcelFileName:   fileHeader.Filename,
+	})
 	if err != nil {
-		h.writeErrorWithDetails(c, http.StatusBadRequest, "invalid_request", "invalid excel file", map[string]interface{}{
-			"template": excel.ShipmentImportTemplate().Name,
-			"reason":   "invalid_excel",
-		})
-		return
-	}
-	if len(rows) <= 1 {
-		h.writeErrorWithDetails(c, http.StatusBadRequest, "invalid_request", "no data rows", map[string]interface{}{
-			"template": excel.ShipmentImportTemplate().Name,
-			"reason":   "no_data_rows",
-		})
-		return
-	}
-
-	spec := excel.ShipmentImportTemplate()
-	index := excel.HeaderIndexMap(rows[0])
-	missing, missingAny := excel.MissingRequiredHeaders(index, spec)
-	if len(missing) > 0 || len(missingAny) > 0 {
-		h.writeErrorWithDetails(c, http.StatusBadRequest, "invalid_request", "missing required headers", map[string]interface{}{
-			"template":            spec.Name,
-			"missingHeaders":      missing,
-			"missingHeaderGroups": missingAny,
-			"expectedHeaders":     excel.TemplateHeaders(spec),
-		})
+		h.logError("create shipment import job failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to create import job")
 		return
 	}
 
-	var processed int
-	rowErrors := make([]map[string]interface{}, 0)
-	for i := 1; i < len(rows); i++ {
-		row := rows[i]
-		rowNo := i + 1
-		orderIDRaw := excel.CellValue(row, index, "orderid")
-		waybillNo := excel.CellValue(row, index, "waybillno")
-		carrier := excel.CellValue(row, index, "carrier")
-		shippedAtRaw := excel.CellValue(row, index, "shippedat")
+	createdAt := job.CreatedAt.Time
+	c.JSON(http.StatusAccepted, oapi.ImportJob{
+		Id:             job.ID,
+		Type:           oapi.ImportJobType(job.Type),
+		Status:         oapi.JobStatus(job.Status),
+		Progress:       int(job.Progress),
+		ResultFileUrl:  job.ResultFileUrl,
+		ErrorReportUrl: job.ErrorReportUrl,
+		CreatedAt:      createdAt,
+	})
+}
 
-		if orderIDRaw == "" {
-			rowErrors = append(rowErrors, map[string]interface{}{
-				"rowNo":   rowNo,
-				"field":   "orderId",
-				"message": "orderId is required",
-			})
-			continue
+// trackingInfo maps shipments of an order together with their carrier trace.
+func (h *Handler) trackingInfo(ctx context.Context, orderID uuid.UUID, shipments []db.OrderTrackingShipment) (oapi.TrackingInfo, error) {
+	traces := make(map[uuid.UUID][]oapi.ShipmentTrackingEvent, len(shipments))
+	parcels := make(map[uuid.UUID][]oapi.ShipmentItem, len(shipments))
+	if len(shipments) > 0 {
+		items, err := h.TrackingStore.ListShipmentEventsByOrder(ctx, orderID)
+		if err != nil {
+			return oapi.TrackingInfo{}, err
 		}
-		if waybillNo == "" {
-			rowErrors = append(rowErrors, map[string]interface{}{
-				"rowNo":   rowNo,
-				"field":   "waybillNo",
-				"message": "waybillNo is required",
+		for _, item := range items {
+			traces[item.ShipmentID] = append(traces[item.ShipmentID], oapi.ShipmentTrackingEvent{
+				Description: item.Description,
+				Location:    item.Location,
+				OccurredAt:  item.OccurredAt.Time,
+				Status:      oapi.ShipmentTrackingStatus(item.Status),
 			})
-			continue
 		}
-		orderID, err := uuid.Parse(orderIDRaw)
+		lines, err := h.TrackingStore.ListShipmentLinesByOrder(ctx, orderID)
 		if err != nil {
-			rowErrors = append(rowErrors, map[string]interface{}{
-				"rowNo":   rowNo,
-				"field":   "orderId",
-				"message": "orderId must be a UUID",
+			return oapi.TrackingInfo{}, err
+		}
+		for _, line := range lines {
+			parcels[line.ShipmentID] = append(parcels[line.ShipmentID], oapi.ShipmentItem{
+				OrderItemId: line.OrderItemID,
+				Qty:         int(line.Qty),
+				SkuId:       line.SkuID,
+				SkuName:     line.SkuName,
 			})
-			continue
 		}
+	}
 
-		var shippedAt pgtype.Timestamptz
-		if shippedAtRaw != "" {
-			if parsed, ok := parseTime(shippedAtRaw); ok {
-				shippedAt = pgtype.Timestamptz{Time: parsed, Valid: true}
-			} else {
-				rowErrors = append(rowErrors, map[string]interface{}{
-					"rowNo":   rowNo,
-					"field":   "shippedAt",
-					"message": "shippedAt must be RFC3339 or YYYY-MM-DD",
-				})
-				continue
-			}
+	response := oapi.TrackingInfo{
+		OrderId:   orderID,
+		Shipments: make([]oapi.TrackingShipment, 0, len(shipments)),
+	}
+	for _, shipment := range shipments {
+		trace := traces[shipment.ID]
+		if trace == nil {
+			trace = []oapi.ShipmentTrackingEvent{}
 		}
-
-		var carrierPtr *string
-		if carrier != "" {
-			carrierPtr = &carrier
+		items := parcels[shipment.ID]
+		if items == nil {
+			items = []oapi.ShipmentItem{}
 		}
-
-		if _, err := h.TrackingStore.UpsertTrackingShipment(c.Request.Context(), db.UpsertTrackingShipmentParams{
-			OrderID:   orderID,
-			WaybillNo: waybillNo,
-			Carrier:   carrierPtr,
-			ShippedAt: shippedAt,
-		}); err != nil {
-			h.logError("upsert tracking shipment failed", err)
-			h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to import shipments")
-			return
+		status := shipment.TrackingStatus
+		if status == "" {
+			status = tracking.StatusPending
 		}
-		processed++
-	}
-
-	if processed == 0 {
-		h.writeErrorWithDetails(c, http.StatusBadRequest, "invalid_request", "no valid rows", map[string]interface{}{
-			"template":  spec.Name,
-			"reason":    "no_valid_rows",
-			"rowErrors": rowErrors,
+		response.Shipments = append(response.Shipments, oapi.TrackingShipment{
+			Carrier:     shipment.Carrier,
+			DeliveredAt: timeFromTimestamptz(shipment.DeliveredAt),
+			Events:      trace,
+			Id:          shipment.ID,
+			Items:       items,
+			LastEventAt: timeFromTimestamptz(shipment.LastEventAt),
+			ShippedAt:   timeFromTimestamptz(shipment.ShippedAt),
+			Status:      oapi.ShipmentTrackingStatus(status),
+			WaybillNo:   shipment.WaybillNo,
 		})
-		return
-	}
-
-	job, err := h.TrackingStore.CreateImportJob(c.Request.Context(), db.CreateImportJobParams{
-		Type:            string(oapi.ImportJobTypeSHIPMENTIMPORT),
-		Status:          string(oapi.SUCCEEDED),
-		Progress:        100,
-		ResultFileUrl:   nil,
-		ErrorReportUrl:  nil,
-		CreatedByUserID: pgtype.UUID{Bytes: claims.UserID, Valid: true},
-	})
-	if err != nil {
-		h.logError("create import job failed", err)
-		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to import shipments")
-		return
-	}
-
-	createdAt := job.CreatedAt.Time
-	response := oapi.ImportJob{
-		Id:        job.ID,
-		Type:      oapi.ImportJobType(job.Type),
-		Status:    oapi.JobStatus(job.Status),
-		Progress:  int(job.Progress),
-		CreatedAt: createdAt,
-	}
-
-	c.JSON(http.StatusAccepted, response)
-}
-
-func parseTime(value string) (time.Time, bool) {
-	parsed, err := time.Parse(time.RFC3339, value)
-	if err == nil {
-		return parsed, true
-	}
-	parsed, err = time.Parse("2006-01-02", value)
-	if err == nil {
-		return parsed, true
 	}
-	return time.Time{}, false
+	return response, nil
 }
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+
+	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/tracking"
+)
+
+const maxCarrierPushBytes = 1 << 20
+
+// PostTrackingCarriersCarrierPush receives trace pushes from a carrier. The
+// route is public; the carrier adapter is responsible for verifying the
+// callback. Shipments are matched by waybill number among those whose
+// carrier resolves to the same adapter.
+func (h *Handler) PostTrackingCarriersCarrierPush(c *gin.Context) {
+	adapter, ok := h.Carriers.Lookup(c.Param("carrier"))
+	if !ok {
+		h.writeError(c, http.StatusNotFound, "not_found", "carrier not supported")
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxCarrierPushBytes+1))
+	if err != nil || len(body) > maxCarrierPushBytes {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	updates, err := adapter.ParsePush(c.Request.Header, body)
+	if err != nil {
+		if errors.Is(err, tracking.ErrUnauthorizedPush) {
+			h.writeError(c, http.StatusUnauthorized, "unauthorized", err.Error())
+			return
+		}
+		h.writeError(c, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	if h.DB == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to record carrier push")
+		return
+	}
+
+	ctx := c.Request.Context()
+	var accepted int
+	for _, update := range updates {
+		shipments, err := h.TrackingStore.ListTrackingShipmentsByWaybill(ctx, update.WaybillNo)
+		if err != nil {
+			h.logError("list shipments by waybill failed", err)
+			h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to record carrier push")
+			return
+		}
+		for _, shipment := range shipments {
+			if shipment.Carrier == nil {
+				continue
+			}
+			if owner, ok := h.Carriers.Lookup(*shipment.Carrier); !ok || owner.Code() != adapter.Code() {
+				continue
+			}
+			err := shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+				_, err := tracking.Record(ctx, tx, h.Outbox, shipment.OrderID, shipment.ID, tracking.SourcePush, update.Events)
+				return err
+			})
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				h.logError("record carrier push failed", err)
+				h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to record carrier push")
+				return
+			}
+			accepted++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"accepted": accepted})
+}
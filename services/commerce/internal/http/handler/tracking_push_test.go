@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/teamdsb/tmo/packages/go-shared/httpx"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/tracking"
+)
+
+func TestCarrierPushRejectsUnknownCarrierAndUnverifiedCallbacks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	carriers := tracking.NewRegistry()
+	carriers.Register(&tracking.FixtureAdapter{Name: "SF", Token: "push-token"})
+	handler := &Handler{Carriers: carriers}
+	router := httpx.NewRouter()
+	router.POST("/tracking/carriers/:carrier/push", handler.PostTrackingCarriersCarrierPush)
+
+	cases := []struct {
+		name    string
+		carrier string
+		token   string
+		body    string
+		want    int
+	}{
+		{name: "unknown carrier", carrier: "YTO", token: "push-token", body: `{}`, want: http.StatusNotFound},
+		{name: "missing token", carrier: "SF", body: `{"waybillNo":"SF1","events":[]}`, want: http.StatusUnauthorized},
+		{name: "invalid body", carrier: "sf", token: "push-token", body: `{"events":[]}`, want: http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/tracking/carriers/"+tc.carrier+"/push", strings.NewReader(tc.body))
+		if tc.token != "" {
+			req.Header.Set(tracking.FixtureTokenHeader, tc.token)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Code != tc.want {
+			t.Errorf("%s: expected %d, got %d: %s", tc.name, tc.want, recorder.Code, recorder.Body.String())
+		}
+	}
+}
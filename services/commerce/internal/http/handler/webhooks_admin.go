@@ -0,0 +1,492 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/webhook"
+)
+
+type createWebhookSubscriptionRequest struct {
+	CustomerID  string   `json:"customerId"`
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"eventTypes"`
+	Description *string  `json:"description"`
+}
+
+type patchWebhookSubscriptionRequest struct {
+	URL         *string   `json:"url"`
+	EventTypes  *[]string `json:"eventTypes"`
+	Description *string   `json:"description"`
+	IsActive    *bool     `json:"isActive"`
+}
+
+type webhookSubscriptionResponse struct {
+	ID          string    `json:"id"`
+	CustomerID  string    `json:"customerId"`
+	URL         string    `json:"url"`
+	EventTypes  []string  `json:"eventTypes"`
+	Description *string   `json:"description,omitempty"`
+	IsActive    bool      `json:"isActive"`
+	SecretHint  string    `json:"secretHint"`
+	Secret      string    `json:"secret,omitempty"`
+	CreatedBy   string    `json:"createdBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type webhookSubscriptionListResponse struct {
+	Items    []webhookSubscriptionResponse `json:"items"`
+	Page     int                           `json:"page"`
+	PageSize int                           `json:"pageSize"`
+	Total    int64                         `json:"total"`
+}
+
+type webhookDeliveryResponse struct {
+	ID                 string     `json:"id"`
+	SubscriptionID     string     `json:"subscriptionId"`
+	EventID            string     `json:"eventId"`
+	EventType          string     `json:"eventType"`
+	Status             string     `json:"status"`
+	Attempts           int32      `json:"attempts"`
+	ReplayCount        int32      `json:"replayCount"`
+	NextAttemptAt      *time.Time `json:"nextAttemptAt,omitempty"`
+	LastResponseStatus *int32     `json:"lastResponseStatus,omitempty"`
+	LastError          *string    `json:"lastError,omitempty"`
+	DeliveredAt        *time.Time `json:"deliveredAt,omitempty"`
+	LastReplayedAt     *time.Time `json:"lastReplayedAt,omitempty"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	UpdatedAt          time.Time  `json:"updatedAt"`
+}
+
+type webhookDeliveryListResponse struct {
+	Items    []webhookDeliveryResponse `json:"items"`
+	Page     int                       `json:"page"`
+	PageSize int                       `json:"pageSize"`
+	Total    int64                     `json:"total"`
+}
+
+func (h *Handler) PostAdminWebhookSubscriptions(c *gin.Context) {
+	claims, ok := h.requireAllScope(c, authz.PermissionWebhookManage)
+	if !ok {
+		return
+	}
+	if h.WebhookStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "webhooks are not configured")
+		return
+	}
+
+	var request createWebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	customerID, err := uuid.Parse(strings.TrimSpace(request.CustomerID))
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid customerId")
+		return
+	}
+	url, err := webhook.NormalizeURL(request.URL)
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	eventTypes, err := webhook.NormalizeEventTypes(request.EventTypes)
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		h.logError("generate webhook secret failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to create webhook subscription")
+		return
+	}
+
+	row, err := h.WebhookStore.CreateWebhookSubscription(c.Request.Context(), db.CreateWebhookSubscriptionParams{
+		CustomerID:  customerID,
+		URL:         url,
+		Secret:      secret,
+		EventTypes:  eventTypes,
+		Description: normalizeWebhookDescription(request.Description),
+		CreatedBy:   claims.UserID,
+	})
+	if err != nil {
+		h.logError("create webhook subscription failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to create webhook subscription")
+		return
+	}
+	// The secret is only returned here and when it is rotated.
+	response := webhookSubscriptionFromModel(row)
+	response.Secret = row.Secret
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *Handler) GetAdminWebhookSubscriptions(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionWebhookManage); !ok {
+		return
+	}
+	if h.WebhookStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "webhooks are not configured")
+		return
+	}
+
+	customerID, ok := parseOptionalUUIDQuery(c.Query("customerId"))
+	if !ok {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid customerId")
+		return
+	}
+	page, pageSize := parseWebhookPage(c)
+
+	rows, err := h.WebhookStore.ListWebhookSubscriptions(c.Request.Context(), db.ListWebhookSubscriptionsParams{
+		CustomerID: customerID,
+		Limit:      clampInt32(pageSize),
+		Offset:     clampInt32((page - 1) * pageSize),
+	})
+	if err != nil {
+		h.logError("list webhook subscriptions failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list webhook subscriptions")
+		return
+	}
+	total, err := h.WebhookStore.CountWebhookSubscriptions(c.Request.Context(), customerID)
+	if err != nil {
+		h.logError("count webhook subscriptions failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list webhook subscriptions")
+		return
+	}
+
+	items := make([]webhookSubscriptionResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, webhookSubscriptionFromModel(row))
+	}
+	c.JSON(http.StatusOK, webhookSubscriptionListResponse{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	})
+}
+
+func (h *Handler) GetAdminWebhookSubscriptionsSubscriptionId(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionWebhookManage); !ok {
+		return
+	}
+	row, ok := h.loadWebhookSubscription(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, webhookSubscriptionFromModel(row))
+}
+
+func (h *Handler) PatchAdminWebhookSubscriptionsSubscriptionId(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionWebhookManage); !ok {
+		return
+	}
+	current, ok := h.loadWebhookSubscription(c)
+	if !ok {
+		return
+	}
+
+	var request patchWebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	update := db.UpdateWebhookSubscriptionParams{
+		ID:          current.ID,
+		URL:         current.URL,
+		EventTypes:  current.EventTypes,
+		Description: current.Description,
+		IsActive:    current.IsActive,
+	}
+	if request.URL != nil {
+		url, err := webhook.NormalizeURL(*request.URL)
+		if err != nil {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", err.Error())
+			return
+		}
+		update.URL = url
+	}
+	if request.EventTypes != nil {
+		eventTypes, err := webhook.NormalizeEventTypes(*request.EventTypes)
+		if err != nil {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", err.Error())
+			return
+		}
+		update.EventTypes = eventTypes
+	}
+	if request.Description != nil {
+		update.Description = normalizeWebhookDescription(request.Description)
+	}
+	if request.IsActive != nil {
+		update.IsActive = *request.IsActive
+	}
+
+	row, err := h.WebhookStore.UpdateWebhookSubscription(c.Request.Context(), update)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusNotFound, "not_found", "webhook subscription not found")
+			return
+		}
+		h.logError("update webhook subscription failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to update webhook subscription")
+		return
+	}
+	c.JSON(http.StatusOK, webhookSubscriptionFromModel(row))
+}
+
+func (h *Handler) DeleteAdminWebhookSubscriptionsSubscriptionId(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionWebhookManage); !ok {
+		return
+	}
+	subscriptionID, ok := h.parseWebhookPathID(c, "subscriptionId")
+	if !ok {
+		return
+	}
+	deleted, err := h.WebhookStore.DeleteWebhookSubscription(c.Request.Context(), subscriptionID)
+	if err != nil {
+		h.logError("delete webhook subscription failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to delete webhook subscription")
+		return
+	}
+	if deleted == 0 {
+		h.writeError(c, http.StatusNotFound, "not_found", "webhook subscription not found")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) PostAdminWebhookSubscriptionsSubscriptionIdRotateSecret(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionWebhookManage); !ok {
+		return
+	}
+	subscriptionID, ok := h.parseWebhookPathID(c, "subscriptionId")
+	if !ok {
+		return
+	}
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		h.logError("generate webhook secret failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to rotate webhook secret")
+		return
+	}
+	row, err := h.WebhookStore.RotateWebhookSubscriptionSecret(c.Request.Context(), db.RotateWebhookSubscriptionSecretParams{
+		ID:     subscriptionID,
+		Secret: secret,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusNotFound, "not_found", "webhook subscription not found")
+			return
+		}
+		h.logError("rotate webhook secret failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to rotate webhook secret")
+		return
+	}
+	response := webhookSubscriptionFromModel(row)
+	response.Secret = row.Secret
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *Handler) GetAdminWebhookDeliveries(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionWebhookManage); !ok {
+		return
+	}
+	if h.WebhookStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "webhooks are not configured")
+		return
+	}
+
+	subscriptionID, ok := parseOptionalUUIDQuery(c.Query("subscriptionId"))
+	if !ok {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid subscriptionId")
+		return
+	}
+	customerID, ok := parseOptionalUUIDQuery(c.Query("customerId"))
+	if !ok {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid customerId")
+		return
+	}
+	var status *string
+	if raw := strings.ToUpper(strings.TrimSpace(c.Query("status"))); raw != "" {
+		switch raw {
+		case webhook.DeliveryPending, webhook.DeliveryDelivered, webhook.DeliveryDead:
+			status = &raw
+		default:
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "status must be one of PENDING, DELIVERED, DEAD")
+			return
+		}
+	}
+	eventType := normalizeOptionalText(c.Query("eventType"))
+	page, pageSize := parseWebhookPage(c)
+
+	rows, err := h.WebhookStore.ListWebhookDeliveries(c.Request.Context(), db.ListWebhookDeliveriesParams{
+		SubscriptionID: subscriptionID,
+		CustomerID:     customerID,
+		Status:         status,
+		EventType:      eventType,
+		Limit:          clampInt32(pageSize),
+		Offset:         clampInt32((page - 1) * pageSize),
+	})
+	if err != nil {
+		h.logError("list webhook deliveries failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list webhook deliveries")
+		return
+	}
+	total, err := h.WebhookStore.CountWebhookDeliveries(c.Request.Context(), db.CountWebhookDeliveriesParams{
+		SubscriptionID: subscriptionID,
+		CustomerID:     customerID,
+		Status:         status,
+		EventType:      eventType,
+	})
+	if err != nil {
+		h.logError("count webhook deliveries failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list webhook deliveries")
+		return
+	}
+
+	items := make([]webhookDeliveryResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, webhookDeliveryFromModel(row))
+	}
+	c.JSON(http.StatusOK, webhookDeliveryListResponse{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	})
+}
+
+func (h *Handler) PostAdminWebhookDeliveriesDeliveryIdReplay(c *gin.Context) {
+	if _, ok := h.requireAllScope(c, authz.PermissionWebhookManage); !ok {
+		return
+	}
+	if h.Webhooks == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "webhooks are not configured")
+		return
+	}
+	deliveryID, err := uuid.Parse(strings.TrimSpace(c.Param("deliveryId")))
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid deliveryId")
+		return
+	}
+
+	row, err := h.Webhooks.Replay(c.Request.Context(), deliveryID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			h.writeError(c, http.StatusNotFound, "not_found", "webhook delivery not found")
+		case errors.Is(err, webhook.ErrSubscriptionInactive):
+			h.writeError(c, http.StatusConflict, "webhook_subscription_inactive", err.Error())
+		default:
+			h.logError("replay webhook delivery failed", err)
+			h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to replay webhook delivery")
+		}
+		return
+	}
+	// A replay that reached the endpoint but was rejected is still reported
+	// with 200; the delivery carries the response status and error.
+	c.JSON(http.StatusOK, webhookDeliveryFromModel(row))
+}
+
+// loadWebhookSubscription fetches the subscription named in the path.
+func (h *Handler) loadWebhookSubscription(c *gin.Context) (db.WebhookSubscription, bool) {
+	subscriptionID, ok := h.parseWebhookPathID(c, "subscriptionId")
+	if !ok {
+		return db.WebhookSubscription{}, false
+	}
+	row, err := h.WebhookStore.GetWebhookSubscription(c.Request.Context(), subscriptionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusNotFound, "not_found", "webhook subscription not found")
+			return db.WebhookSubscription{}, false
+		}
+		h.logError("get webhook subscription failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch webhook subscription")
+		return db.WebhookSubscription{}, false
+	}
+	return row, true
+}
+
+func (h *Handler) parseWebhookPathID(c *gin.Context, name string) (uuid.UUID, bool) {
+	if h.WebhookStore == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "webhooks are not configured")
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(strings.TrimSpace(c.Param(name)))
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid "+name)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func parseWebhookPage(c *gin.Context) (int, int) {
+	page := parseAdminPositiveInt(c.Query("page"), 1)
+	pageSize := parseAdminPositiveInt(c.Query("pageSize"), 20)
+	if pageSize > 100 {
+		pageSize = 100
+	}
+	return page, pageSize
+}
+
+func normalizeWebhookDescription(raw *string) *string {
+	if raw == nil {
+		return nil
+	}
+	return normalizeOptionalText(*raw)
+}
+
+func webhookSubscriptionFromModel(row db.WebhookSubscription) webhookSubscriptionResponse {
+	return webhookSubscriptionResponse{
+		ID:          row.ID.String(),
+		CustomerID:  row.CustomerID.String(),
+		URL:         row.URL,
+		EventTypes:  row.EventTypes,
+		Description: row.Description,
+		IsActive:    row.IsActive,
+		SecretHint:  webhookSecretHint(row.Secret),
+		CreatedBy:   row.CreatedBy.String(),
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}
+}
+
+// webhookSecretHint shows the last characters of a secret so admins can tell
+// which one a customer has configured.
+func webhookSecretHint(secret string) string {
+	if len(secret) <= 4 {
+		return "****"
+	}
+	return "****" + secret[len(secret)-4:]
+}
+
+func webhookDeliveryFromModel(row db.WebhookDelivery) webhookDeliveryResponse {
+	response := webhookDeliveryResponse{
+		ID:                 row.ID.String(),
+		SubscriptionID:     row.SubscriptionID.String(),
+		EventID:            row.EventID.String(),
+		EventType:          row.EventType,
+		Status:             row.Status,
+		Attempts:           row.Attempts,
+		ReplayCount:        row.ReplayCount,
+		LastResponseStatus: row.LastResponseStatus,
+		LastError:          row.LastError,
+		DeliveredAt:        timePtrFromPg(row.DeliveredAt),
+		LastReplayedAt:     timePtrFromPg(row.LastReplayedAt),
+		CreatedAt:          row.CreatedAt.Time,
+		UpdatedAt:          row.UpdatedAt.Time,
+	}
+	if row.Status == webhook.DeliveryPending {
+		response.NextAttemptAt = timePtrFromPg(row.NextAttemptAt)
+	}
+	return response
+}
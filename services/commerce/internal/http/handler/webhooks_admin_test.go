@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/packages/go-shared/httpx"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/middleware"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/webhook"
+)
+
+type stubWebhookStore struct {
+	subscriptions map[uuid.UUID]db.WebhookSubscription
+	deliveries    map[uuid.UUID]db.WebhookDelivery
+}
+
+func (s *stubWebhookStore) CreateWebhookSubscription(_ context.Context, arg db.CreateWebhookSubscriptionParams) (db.WebhookSubscription, error) {
+	row := db.WebhookSubscription{
+		ID: uuid.New(), CustomerID: arg.CustomerID, URL: arg.URL, Secret: arg.Secret,
+		EventTypes: arg.EventTypes, Description: arg.Description, IsActive: true, CreatedBy: arg.CreatedBy,
+	}
+	s.subscriptions[row.ID] = row
+	return row, nil
+}
+
+func (s *stubWebhookStore) GetWebhookSubscription(_ context.Context, id uuid.UUID) (db.WebhookSubscription, error) {
+	row, ok := s.subscriptions[id]
+	if !ok {
+		return db.WebhookSubscription{}, pgx.ErrNoRows
+	}
+	return row, nil
+}
+
+func (s *stubWebhookStore) ListWebhookSubscriptions(context.Context, db.ListWebhookSubscriptionsParams) ([]db.WebhookSubscription, error) {
+	items := make([]db.WebhookSubscription, 0, len(s.subscriptions))
+	for _, row := range s.subscriptions {
+		items = append(items, row)
+	}
+	return items, nil
+}
+
+func (s *stubWebhookStore) CountWebhookSubscriptions(context.Context, pgtype.UUID) (int64, error) {
+	return int64(len(s.subscriptions)), nil
+}
+
+func (s *stubWebhookStore) UpdateWebhookSubscription(_ context.Context, arg db.UpdateWebhookSubscriptionParams) (db.WebhookSubscription, error) {
+	row, ok := s.subscriptions[arg.ID]
+	if !ok {
+		return db.WebhookSubscription{}, pgx.ErrNoRows
+	}
+	row.URL, row.EventTypes, row.Description, row.IsActive = arg.URL, arg.EventTypes, arg.Description, arg.IsActive
+	s.subscriptions[arg.ID] = row
+	return row, nil
+}
+
+func (s *stubWebhookStore) RotateWebhookSubscriptionSecret(_ context.Context, arg db.RotateWebhookSubscriptionSecretParams) (db.WebhookSubscription, error) {
+	row, ok := s.subscriptions[arg.ID]
+	if !ok {
+		return db.WebhookSubscription{}, pgx.ErrNoRows
+	}
+	row.Secret = arg.Secret
+	s.subscriptions[arg.ID] = row
+	return row, nil
+}
+
+func (s *stubWebhookStore) DeleteWebhookSubscription(_ context.Context, id uuid.UUID) (int64, error) {
+	if _, ok := s.subscriptions[id]; !ok {
+		return 0, nil
+	}
+	delete(s.subscriptions, id)
+	return 1, nil
+}
+
+func (s *stubWebhookStore) GetWebhookDelivery(_ context.Context, id uuid.UUID) (db.WebhookDelivery, error) {
+	row, ok := s.deliveries[id]
+	if !ok {
+		return db.WebhookDelivery{}, pgx.ErrNoRows
+	}
+	return row, nil
+}
+
+func (s *stubWebhookStore) ListWebhookDeliveries(context.Context, db.ListWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	items := make([]db.WebhookDelivery, 0, len(s.deliveries))
+	for _, row := range s.deliveries {
+		items = append(items, row)
+	}
+	return items, nil
+}
+
+func (s *stubWebhookStore) CountWebhookDeliveries(context.Context, db.CountWebhookDeliveriesParams) (int64, error) {
+	return int64(len(s.deliveries)), nil
+}
+
+func (s *stubWebhookStore) ClaimDueWebhookDeliveries(context.Context, db.ClaimDueWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (s *stubWebhookStore) ReplayWebhookDelivery(_ context.Context, arg db.ReplayWebhookDeliveryParams) (db.WebhookDelivery, error) {
+	row := s.deliveries[arg.ID]
+	row.Status, row.Attempts = webhook.DeliveryPending, 1
+	row.ReplayCount++
+	s.deliveries[arg.ID] = row
+	return row, nil
+}
+
+func (s *stubWebhookStore) MarkWebhookDeliveryDelivered(_ context.Context, arg db.MarkWebhookDeliveryDeliveredParams) (db.WebhookDelivery, error) {
+	row := s.deliveries[arg.ID]
+	row.Status, row.LastResponseStatus, row.LastError = webhook.DeliveryDelivered, arg.LastResponseStatus, nil
+	s.deliveries[arg.ID] = row
+	return row, nil
+}
+
+func (s *stubWebhookStore) MarkWebhookDeliveryFailed(_ context.Context, arg db.MarkWebhookDeliveryFailedParams) (db.WebhookDelivery, error) {
+	row := s.deliveries[arg.ID]
+	row.Status, row.LastResponseStatus, row.LastError = arg.Status, arg.LastResponseStatus, arg.LastError
+	s.deliveries[arg.ID] = row
+	return row, nil
+}
+
+func TestWebhookAdminEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var received int
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		if r.Header.Get(webhook.HeaderSignature) == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer endpoint.Close()
+
+	store := &stubWebhookStore{subscriptions: map[uuid.UUID]db.WebhookSubscription{}, deliveries: map[uuid.UUID]db.WebhookDelivery{}}
+	handler := &Handler{
+		Auth:         middleware.NewAuthenticator(true, testJWTKeys, testJWTIssuer),
+		WebhookStore: store,
+		Webhooks:     &webhook.Dispatcher{Store: store, Client: endpoint.Client()},
+	}
+	router := httpx.NewRouter()
+	router.POST("/admin/webhooks/subscriptions", handler.PostAdminWebhookSubscriptions)
+	router.GET("/admin/webhooks/subscriptions", handler.GetAdminWebhookSubscriptions)
+	router.PATCH("/admin/webhooks/subscriptions/:subscriptionId", handler.PatchAdminWebhookSubscriptionsSubscriptionId)
+	router.DELETE("/admin/webhooks/subscriptions/:subscriptionId", handler.DeleteAdminWebhookSubscriptionsSubscriptionId)
+	router.POST("/admin/webhooks/subscriptions/:subscriptionId/rotate-secret", handler.PostAdminWebhookSubscriptionsSubscriptionIdRotateSecret)
+	router.POST("/admin/webhooks/deliveries/:deliveryId/replay", handler.PostAdminWebhookDeliveriesDeliveryIdReplay)
+
+	request := func(method, path, body, role string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+makeAuthToken(t, uuid.New(), role, nil))
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	customerID := uuid.New()
+	body := `{"customerId":"` + customerID.String() + `","url":"` + endpoint.URL + `","eventTypes":["order.status_changed","shipment.added"]}`
+	if recorder := request(http.MethodPost, "/admin/webhooks/subscriptions", body, "MANAGER"); recorder.Code != http.StatusForbidden {
+		t.Fatalf("expected MANAGER to be rejected, got %d", recorder.Code)
+	}
+	if recorder := request(http.MethodPost, "/admin/webhooks/subscriptions", `{"customerId":"`+customerID.String()+`","url":"ftp://erp","eventTypes":["order.created"]}`, "ADMIN"); recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected invalid url 400, got %d", recorder.Code)
+	}
+	if recorder := request(http.MethodPost, "/admin/webhooks/subscriptions", `{"customerId":"`+customerID.String()+`","url":"https://erp.example.com","eventTypes":["payment.succeeded"]}`, "ADMIN"); recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected unsupported event type 400, got %d", recorder.Code)
+	}
+	recorder := request(http.MethodPost, "/admin/webhooks/subscriptions", body, "ADMIN")
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var created webhookSubscriptionResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !strings.HasPrefix(created.Secret, "whsec_") || !strings.HasSuffix(created.Secret, strings.TrimPrefix(created.SecretHint, "****")) {
+		t.Fatalf("expected the new secret and its hint, got %+v", created)
+	}
+
+	recorder = request(http.MethodGet, "/admin/webhooks/subscriptions", "", "BOSS")
+	if recorder.Code != http.StatusOK || strings.Contains(recorder.Body.String(), created.Secret) {
+		t.Fatalf("expected listing without secrets, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = request(http.MethodPatch, "/admin/webhooks/subscriptions/"+created.ID, `{"isActive":false,"description":"  ERP  "}`, "ADMIN")
+	var patched webhookSubscriptionResponse
+	if recorder.Code != http.StatusOK || json.Unmarshal(recorder.Body.Bytes(), &patched) != nil || patched.IsActive || patched.Description == nil || *patched.Description != "ERP" || len(patched.EventTypes) != 2 {
+		t.Fatalf("unexpected patch response %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	subscriptionID := uuid.MustParse(created.ID)
+	event, _ := events.New("commerce", events.TypeShipmentAdded, "order-1", events.ShipmentAdded{OrderID: uuid.NewString()})
+	payload, _ := json.Marshal(event)
+	delivery := db.WebhookDelivery{ID: uuid.New(), SubscriptionID: subscriptionID, EventID: event.ID, EventType: event.Type, Payload: payload, Status: webhook.DeliveryDead, Attempts: 12}
+	store.deliveries[delivery.ID] = delivery
+	if recorder := request(http.MethodPost, "/admin/webhooks/deliveries/"+delivery.ID.String()+"/replay", "", "ADMIN"); recorder.Code != http.StatusConflict {
+		t.Fatalf("expected replay of an inactive subscription to conflict, got %d", recorder.Code)
+	}
+	request(http.MethodPatch, "/admin/webhooks/subscriptions/"+created.ID, `{"isActive":true}`, "ADMIN")
+	recorder = request(http.MethodPost, "/admin/webhooks/deliveries/"+delivery.ID.String()+"/replay", "", "ADMIN")
+	var replayed webhookDeliveryResponse
+	if recorder.Code != http.StatusOK || json.Unmarshal(recorder.Body.Bytes(), &replayed) != nil || replayed.Status != webhook.DeliveryDelivered || replayed.ReplayCount != 1 || received != 1 {
+		t.Fatalf("unexpected replay response %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if recorder := request(http.MethodPost, "/admin/webhooks/deliveries/"+uuid.NewString()+"/replay", "", "ADMIN"); recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected unknown delivery 404, got %d", recorder.Code)
+	}
+
+	recorder = request(http.MethodPost, "/admin/webhooks/subscriptions/"+created.ID+"/rotate-secret", "", "ADMIN")
+	var rotated webhookSubscriptionResponse
+	if recorder.Code != http.StatusOK || json.Unmarshal(recorder.Body.Bytes(), &rotated) != nil || rotated.Secret == "" || rotated.Secret == created.Secret {
+		t.Fatalf("unexpected rotate response %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if recorder := request(http.MethodDelete, "/admin/webhooks/subscriptions/"+created.ID, "", "ADMIN"); recorder.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", recorder.Code)
+	}
+	if recorder := request(http.MethodDelete, "/admin/webhooks/subscriptions/"+created.ID, "", "ADMIN"); recorder.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 after delete, got %d", recorder.Code)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/oapi-codegen/runtime/types"
 
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
 	"github.com/teamdsb/tmo/services/commerce/internal/db"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
 )
@@ -17,7 +18,7 @@ type wishlistResponse struct {
 }
 
 func (h *Handler) GetWishlist(c *gin.Context) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "ADMIN")
+	claims, _, ok := h.requirePermission(c, authz.PermissionWishlistManage)
 	if !ok {
 		return
 	}
@@ -34,7 +35,7 @@ func (h *Handler) GetWishlist(c *gin.Context) {
 		skuIDs = append(skuIDs, item.SkuID)
 	}
 
-	skuMap, err := h.loadSkusWithTiers(c.Request.Context(), skuIDs)
+	skuMap, err := h.loadSkusForBuyer(c.Request.Context(), h.displayBuyer(c.Request.Context(), claims), skuIDs)
 	if err != nil {
 		h.logError("load skus failed", err)
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch wishlist")
@@ -61,7 +62,7 @@ func (h *Handler) GetWishlist(c *gin.Context) {
 }
 
 func (h *Handler) PostWishlist(c *gin.Context) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "ADMIN")
+	claims, _, ok := h.requirePermission(c, authz.PermissionWishlistManage)
 	if !ok {
 		return
 	}
@@ -105,7 +106,7 @@ func (h *Handler) PostWishlist(c *gin.Context) {
 }
 
 func (h *Handler) DeleteWishlistSkuId(c *gin.Context, skuId types.UUID) {
-	claims, ok := h.requireRole(c, "CUSTOMER", "ADMIN")
+	claims, _, ok := h.requirePermission(c, authz.PermissionWishlistManage)
 	if !ok {
 		return
 	}
@@ -1,17 +1,25 @@
 package middleware
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+	"github.com/teamdsb/tmo/packages/go-shared/jwks"
+	"github.com/teamdsb/tmo/packages/go-shared/revocation"
 )
 
 func TestRequireUserDisabled(test *testing.T) {
-	authenticator := NewAuthenticator(false, "secret", "issuer")
+	authenticator := NewAuthenticator(false, nil, "issuer")
 	context, recorder := newTestContext()
 
 	claims, ok := authenticator.RequireUser(context)
@@ -27,7 +35,7 @@ func TestRequireUserDisabled(test *testing.T) {
 }
 
 func TestRequireUserMissingHeader(test *testing.T) {
-	authenticator := NewAuthenticator(true, "secret", "issuer")
+	authenticator := NewAuthenticator(true, testKeys, "issuer")
 	context, recorder := newTestContext()
 
 	_, ok := authenticator.RequireUser(context)
@@ -39,10 +47,40 @@ func TestRequireUserMissingHeader(test *testing.T) {
 	}
 }
 
+func TestOptionalUser(test *testing.T) {
+	authenticator := NewAuthenticator(true, testKeys, "issuer")
+
+	context, recorder := newTestContext()
+	_, authenticated, ok := authenticator.OptionalUser(context)
+	if !ok || authenticated {
+		test.Fatalf("expected anonymous caller to pass, got authenticated=%v ok=%v", authenticated, ok)
+	}
+	if recorder.Code != http.StatusOK {
+		test.Fatalf("expected status OK, got %d", recorder.Code)
+	}
+
+	userID := uuid.New()
+	context, _ = newTestContext()
+	context.Request.Header.Set("Authorization", "Bearer "+makeToken(test, "issuer", userID, "CUSTOMER"))
+	claims, authenticated, ok := authenticator.OptionalUser(context)
+	if !ok || !authenticated || claims.UserID != userID {
+		test.Fatalf("expected caller %s, got %+v authenticated=%v ok=%v", userID, claims, authenticated, ok)
+	}
+
+	context, recorder = newTestContext()
+	context.Request.Header.Set("Authorization", "Bearer invalid")
+	if _, _, ok := authenticator.OptionalUser(context); ok {
+		test.Fatal("expected invalid token to be rejected")
+	}
+	if recorder.Code != http.StatusUnauthorized {
+		test.Fatalf("expected status unauthorized, got %d", recorder.Code)
+	}
+}
+
 func TestRequireRoleMismatch(test *testing.T) {
-	authenticator := NewAuthenticator(true, "secret", "issuer")
+	authenticator := NewAuthenticator(true, testKeys, "issuer")
 	userID := uuid.New()
-	token := makeToken(test, "secret", "issuer", userID, "buyer")
+	token := makeToken(test, "issuer", userID, "buyer")
 
 	context, recorder := newTestContext()
 	context.Request.Header.Set("Authorization", "Bearer "+token)
@@ -57,9 +95,9 @@ func TestRequireRoleMismatch(test *testing.T) {
 }
 
 func TestRequireRoleMatch(test *testing.T) {
-	authenticator := NewAuthenticator(true, "secret", "issuer")
+	authenticator := NewAuthenticator(true, testKeys, "issuer")
 	userID := uuid.New()
-	token := makeToken(test, "secret", "issuer", userID, "buyer")
+	token := makeToken(test, "issuer", userID, "buyer")
 
 	context, recorder := newTestContext()
 	context.Request.Header.Set("Authorization", "Bearer "+token)
@@ -76,11 +114,61 @@ func TestRequireRoleMatch(test *testing.T) {
 	}
 }
 
+func TestRequirePermissionReturnsScope(test *testing.T) {
+	authenticator := NewAuthenticator(true, testKeys, "issuer")
+	userID := uuid.New()
+	token := makeToken(test, "issuer", userID, "SALES")
+
+	context, recorder := newTestContext()
+	context.Request.Header.Set("Authorization", "Bearer "+token)
+
+	claims, scope, ok := authenticator.RequirePermission(context, authz.PermissionOrderRead, "")
+	if !ok {
+		test.Fatal("expected permission check to succeed")
+	}
+	if claims.UserID != userID || scope != authz.ScopeOwned {
+		test.Fatalf("expected OWNED scope for %s, got %q for %s", userID, scope, claims.UserID)
+	}
+	if recorder.Code != http.StatusOK {
+		test.Fatalf("expected status OK, got %d", recorder.Code)
+	}
+}
+
+func TestRequirePermissionRejectsNarrowScope(test *testing.T) {
+	authenticator := NewAuthenticator(true, testKeys, "issuer")
+	token := makeToken(test, "issuer", uuid.New(), "SALES")
+
+	context, recorder := newTestContext()
+	context.Request.Header.Set("Authorization", "Bearer "+token)
+
+	if _, _, ok := authenticator.RequirePermission(context, authz.PermissionOrderRead, authz.ScopeAll); ok {
+		test.Fatal("expected OWNED scope to fail an ALL requirement")
+	}
+	if recorder.Code != http.StatusForbidden {
+		test.Fatalf("expected status forbidden, got %d", recorder.Code)
+	}
+}
+
+func TestRequirePermissionUsesAuthorizerMatrix(test *testing.T) {
+	authorizer := authz.NewAuthorizer(grantSource{{Role: "CS", Permission: authz.PermissionSupplierManage, Scope: authz.ScopeAll}})
+	if err := authorizer.Refresh(test.Context()); err != nil {
+		test.Fatalf("refresh: %v", err)
+	}
+	authenticator := NewAuthenticator(true, testKeys, "issuer").WithAuthorizer(authorizer)
+	token := makeToken(test, "issuer", uuid.New(), "CS")
+
+	context, _ := newTestContext()
+	context.Request.Header.Set("Authorization", "Bearer "+token)
+	if _, _, ok := authenticator.RequirePermission(context, authz.PermissionSupplierManage, authz.ScopeAll); !ok {
+		test.Fatal("expected refreshed grant to allow supplier:manage")
+	}
+}
+
 func TestRequireUserParsesOwnerSalesUserID(test *testing.T) {
-	authenticator := NewAuthenticator(true, "secret", "issuer")
+	authenticator := NewAuthenticator(true, testKeys, "issuer")
 	userID := uuid.New()
 	ownerSalesUserID := uuid.New()
-	token := makeTokenWithOwner(test, "secret", "issuer", userID, "buyer", ownerSalesUserID.String())
+	token := makeTokenWithOwner(test, "issuer", userID, "buyer", ownerSalesUserID.String())
 
 	context, recorder := newTestContext()
 	context.Request.Header.Set("Authorization", "Bearer "+token)
@@ -98,9 +186,9 @@ func TestRequireUserParsesOwnerSalesUserID(test *testing.T) {
 }
 
 func TestRequireUserInvalidOwnerSalesUserID(test *testing.T) {
-	authenticator := NewAuthenticator(true, "secret", "issuer")
+	authenticator := NewAuthenticator(true, testKeys, "issuer")
 	userID := uuid.New()
-	token := makeTokenWithOwner(test, "secret", "issuer", userID, "buyer", "not-a-uuid")
+	token := makeTokenWithOwner(test, "issuer", userID, "buyer", "not-a-uuid")
 
 	context, recorder := newTestContext()
 	context.Request.Header.Set("Authorization", "Bearer "+token)
@@ -115,9 +203,9 @@ func TestRequireUserInvalidOwnerSalesUserID(test *testing.T) {
 }
 
 func TestRequireUserParsesCustomerProfile(test *testing.T) {
-	authenticator := NewAuthenticator(true, "secret", "issuer")
+	authenticator := NewAuthenticator(true, testKeys, "issuer")
 	userID := uuid.New()
-	token := makeTokenWithProfile(test, "secret", "issuer", userID, "customer", "用户0003", "+15550000003")
+	token := makeTokenWithProfile(test, "issuer", userID, "customer", "用户0003", "+15550000003")
 
 	context, recorder := newTestContext()
 	context.Request.Header.Set("Authorization", "Bearer "+token)
@@ -137,6 +225,107 @@ func TestRequireUserParsesCustomerProfile(test *testing.T) {
 	}
 }
 
+func TestRequireUserRejectsRevokedTokenVersion(test *testing.T) {
+	userID := uuid.New()
+	revocations := revocation.NewRegistry(versionSource{{UserID: userID, TokenVersion: 2}})
+	if err := revocations.Refresh(test.Context()); err != nil {
+		test.Fatalf("refresh: %v", err)
+	}
+	authenticator := NewAuthenticator(true, testKeys, "issuer").WithRevocations(revocations)
+
+	for version, wantOK := range map[int]bool{1: false, 2: true} {
+		signed := signToken(test, testSigner, jwt.MapClaims{
+			"sub":  userID.String(),
+			"role": "CUSTOMER",
+			"iss":  "issuer",
+			"tv":   version,
+		})
+		context, recorder := newTestContext()
+		context.Request.Header.Set("Authorization", "Bearer "+signed)
+
+		_, ok := authenticator.RequireUser(context)
+		if ok != wantOK {
+			test.Fatalf("token version %d: expected ok=%v, got %v (status %d)", version, wantOK, ok, recorder.Code)
+		}
+		if !ok && recorder.Code != http.StatusUnauthorized {
+			test.Fatalf("expected status unauthorized, got %d", recorder.Code)
+		}
+	}
+}
+
+func TestRequireUserRejectsUntrustedSignatures(test *testing.T) {
+	authenticator := NewAuthenticator(true, testKeys, "issuer")
+	claims := jwt.MapClaims{"sub": uuid.NewString(), "role": "ADMIN", "iss": "issuer"}
+
+	_, otherSigner, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		test.Fatalf("generate key: %v", err)
+	}
+	hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	hmac.Header["kid"] = testKeyID
+	hmacSigned, err := hmac.SignedString([]byte(testKeyID))
+	if err != nil {
+		test.Fatalf("sign token: %v", err)
+	}
+
+	for name, token := range map[string]string{
+		"unknown key": signToken(test, otherSigner, claims),
+		"hmac":        hmacSigned,
+	} {
+		context, recorder := newTestContext()
+		context.Request.Header.Set("Authorization", "Bearer "+token)
+		if _, ok := authenticator.RequireUser(context); ok {
+			test.Fatalf("%s: expected authentication to fail", name)
+		}
+		if recorder.Code != http.StatusUnauthorized {
+			test.Fatalf("%s: expected status unauthorized, got %d", name, recorder.Code)
+		}
+	}
+}
+
+type grantSource []authz.Grant
+
+func (s grantSource) Grants(context.Context) ([]authz.Grant, error) {
+	return s, nil
+}
+
+type versionSource []revocation.Entry
+
+func (s versionSource) Changes(context.Context, time.Time) ([]revocation.Entry, time.Time, error) {
+	return s, time.Now(), nil
+}
+
+var testSigner, testKeyID, testKeys = newTestKeys()
+
+func newTestKeys() (ed25519.PrivateKey, string, *jwks.Cache) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	key, err := jwks.NewKey(public)
+	if err != nil {
+		panic(err)
+	}
+	return private, key.ID, jwks.NewStatic(key)
+}
+
+// signToken signs claims the way identity does: EdDSA with the key's
+// thumbprint as kid.
+func signToken(test *testing.T, signer ed25519.PrivateKey, claims jwt.MapClaims) string {
+	test.Helper()
+	key, err := jwks.NewKey(signer.Public())
+	if err != nil {
+		test.Fatalf("key: %v", err)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = key.ID
+	signed, err := token.SignedString(signer)
+	if err != nil {
+		test.Fatalf("sign token: %v", err)
+	}
+	return signed
+}
+
 func newTestContext() (*gin.Context, *httptest.ResponseRecorder) {
 	gin.SetMode(gin.TestMode)
 	recorder := httptest.NewRecorder()
@@ -145,47 +334,32 @@ func newTestContext() (*gin.Context, *httptest.ResponseRecorder) {
 	return context, recorder
 }
 
-func makeToken(test *testing.T, secret, issuer string, userID uuid.UUID, role string) string {
+func makeToken(test *testing.T, issuer string, userID uuid.UUID, role string) string {
 	test.Helper()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	return signToken(test, testSigner, jwt.MapClaims{
 		"sub":  userID.String(),
 		"role": role,
 		"iss":  issuer,
 	})
-	signed, err := token.SignedString([]byte(secret))
-	if err != nil {
-		test.Fatalf("sign token: %v", err)
-	}
-	return signed
 }
 
-func makeTokenWithOwner(test *testing.T, secret, issuer string, userID uuid.UUID, role string, ownerSalesUserID string) string {
+func makeTokenWithOwner(test *testing.T, issuer string, userID uuid.UUID, role string, ownerSalesUserID string) string {
 	test.Helper()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	return signToken(test, testSigner, jwt.MapClaims{
 		"sub":              userID.String(),
 		"role":             role,
 		"iss":              issuer,
 		"ownerSalesUserId": ownerSalesUserID,
 	})
-	signed, err := token.SignedString([]byte(secret))
-	if err != nil {
-		test.Fatalf("sign token: %v", err)
-	}
-	return signed
 }
 
-func makeTokenWithProfile(test *testing.T, secret, issuer string, userID uuid.UUID, role string, displayName string, phone string) string {
+func makeTokenWithProfile(test *testing.T, issuer string, userID uuid.UUID, role string, displayName string, phone string) string {
 	test.Helper()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+	return signToken(test, testSigner, jwt.MapClaims{
 		"sub":         userID.String(),
 		"role":        role,
 		"iss":         issuer,
 		"displayName": displayName,
 		"phone":       phone,
 	})
-	signed, err := token.SignedString([]byte(secret))
-	if err != nil {
-		test.Fatalf("sign token: %v", err)
-	}
-	return signed
 }
@@ -32,6 +32,20 @@ const (
 	NOTFOUND  CartImportPendingItemMatchType = "NOT_FOUND"
 )
 
+// Defines values for CheckoutWarningCode.
+const (
+	CARTITEMCHANGED     CheckoutWarningCode = "CART_ITEM_CHANGED"
+	COUPONINVALID       CheckoutWarningCode = "COUPON_INVALID"
+	COUPONUNAVAILABLE   CheckoutWarningCode = "COUPON_UNAVAILABLE"
+	CREDITLIMITEXCEEDED CheckoutWarningCode = "CREDIT_LIMIT_EXCEEDED"
+	INSUFFICIENTSTOCK   CheckoutWarningCode = "INSUFFICIENT_STOCK"
+	PRICECHANGED        CheckoutWarningCode = "PRICE_CHANGED"
+	PRICEUNAVAILABLE    CheckoutWarningCode = "PRICE_UNAVAILABLE"
+	SKUINACTIVE         CheckoutWarningCode = "SKU_INACTIVE"
+	SKUNOTFOUND         CheckoutWarningCode = "SKU_NOT_FOUND"
+	TIERBREAKPOINTNEAR  CheckoutWarningCode = "TIER_BREAKPOINT_NEAR"
+)
+
 // Defines values for DisplayCategoryIconKey.
 const (
 	Apps    DisplayCategoryIconKey = "apps"
@@ -66,25 +80,47 @@ const (
 	Staff    MessageSenderType = "staff"
 )
 
+// Defines values for OrderCancelReasonCode.
+const (
+	OrderCancelReasonCodeCHANGEDMIND    OrderCancelReasonCode = "CHANGED_MIND"
+	OrderCancelReasonCodeDUPLICATEORDER OrderCancelReasonCode = "DUPLICATE_ORDER"
+	OrderCancelReasonCodeOTHER          OrderCancelReasonCode = "OTHER"
+	OrderCancelReasonCodePRICECHANGED   OrderCancelReasonCode = "PRICE_CHANGED"
+	OrderCancelReasonCodeWRONGITEMS     OrderCancelReasonCode = "WRONG_ITEMS"
+)
+
+// Defines values for OrderCloseReasonCode.
+const (
+	OrderCloseReasonCodeCUSTOMERREQUEST OrderCloseReasonCode = "CUSTOMER_REQUEST"
+	OrderCloseReasonCodeDUPLICATEORDER  OrderCloseReasonCode = "DUPLICATE_ORDER"
+	OrderCloseReasonCodeOTHER           OrderCloseReasonCode = "OTHER"
+	OrderCloseReasonCodeOUTOFSTOCK      OrderCloseReasonCode = "OUT_OF_STOCK"
+	OrderCloseReasonCodePAYMENTTIMEOUT  OrderCloseReasonCode = "PAYMENT_TIMEOUT"
+	OrderCloseReasonCodeSUSPECTEDFRAUD  OrderCloseReasonCode = "SUSPECTED_FRAUD"
+)
+
 // Defines values for OrderPaymentStatus.
 const (
-	OrderPaymentStatusPAID       OrderPaymentStatus = "PAID"
-	OrderPaymentStatusPAYFAILED  OrderPaymentStatus = "PAY_FAILED"
-	OrderPaymentStatusPAYPENDING OrderPaymentStatus = "PAY_PENDING"
-	OrderPaymentStatusUNPAID     OrderPaymentStatus = "UNPAID"
+	OrderPaymentStatusPAID              OrderPaymentStatus = "PAID"
+	OrderPaymentStatusPARTIALLYREFUNDED OrderPaymentStatus = "PARTIALLY_REFUNDED"
+	OrderPaymentStatusPAYFAILED         OrderPaymentStatus = "PAY_FAILED"
+	OrderPaymentStatusPAYPENDING        OrderPaymentStatus = "PAY_PENDING"
+	OrderPaymentStatusREFUNDED          OrderPaymentStatus = "REFUNDED"
+	OrderPaymentStatusUNPAID            OrderPaymentStatus = "UNPAID"
 )
 
 // Defines values for OrderStatus.
 const (
-	OrderStatusCANCELLED  OrderStatus = "CANCELLED"
-	OrderStatusCLOSED     OrderStatus = "CLOSED"
-	OrderStatusCONFIRMED  OrderStatus = "CONFIRMED"
-	OrderStatusDELIVERED  OrderStatus = "DELIVERED"
-	OrderStatusPAID       OrderStatus = "PAID"
-	OrderStatusPAYFAILED  OrderStatus = "PAY_FAILED"
-	OrderStatusPAYPENDING OrderStatus = "PAY_PENDING"
-	OrderStatusSHIPPED    OrderStatus = "SHIPPED"
-	OrderStatusSUBMITTED  OrderStatus = "SUBMITTED"
+	OrderStatusCANCELLED        OrderStatus = "CANCELLED"
+	OrderStatusCLOSED           OrderStatus = "CLOSED"
+	OrderStatusCONFIRMED        OrderStatus = "CONFIRMED"
+	OrderStatusDELIVERED        OrderStatus = "DELIVERED"
+	OrderStatusPAID             OrderStatus = "PAID"
+	OrderStatusPARTIALLYSHIPPED OrderStatus = "PARTIALLY_SHIPPED"
+	OrderStatusPAYFAILED        OrderStatus = "PAY_FAILED"
+	OrderStatusPAYPENDING       OrderStatus = "PAY_PENDING"
+	OrderStatusSHIPPED          OrderStatus = "SHIPPED"
+	OrderStatusSUBMITTED        OrderStatus = "SUBMITTED"
 )
 
 // Defines values for PriceInquiryStatus.
@@ -109,6 +145,24 @@ const (
 	ProductStatusINACTIVE ProductStatus = "INACTIVE"
 )
 
+// Defines values for QuotationStatus.
+const (
+	QuotationStatusACCEPTED   QuotationStatus = "ACCEPTED"
+	QuotationStatusCONVERTED  QuotationStatus = "CONVERTED"
+	QuotationStatusEXPIRED    QuotationStatus = "EXPIRED"
+	QuotationStatusREJECTED   QuotationStatus = "REJECTED"
+	QuotationStatusSENT       QuotationStatus = "SENT"
+	QuotationStatusSUPERSEDED QuotationStatus = "SUPERSEDED"
+)
+
+// Defines values for ShipmentTrackingStatus.
+const (
+	ShipmentTrackingStatusDELIVERED ShipmentTrackingStatus = "DELIVERED"
+	ShipmentTrackingStatusEXCEPTION ShipmentTrackingStatus = "EXCEPTION"
+	ShipmentTrackingStatusINTRANSIT ShipmentTrackingStatus = "IN_TRANSIT"
+	ShipmentTrackingStatusPENDING   ShipmentTrackingStatus = "PENDING"
+)
+
 // Defines values for TicketStatus.
 const (
 	TicketStatusCLOSED     TicketStatus = "CLOSED"
@@ -169,6 +223,18 @@ type AfterSalesTicket struct {
 	UpdatedAt           *time.Time          `json:"updatedAt"`
 }
 
+// AppliedPriceList Customer price list that set the SKU's priceTiers; omitted at catalog prices
+type AppliedPriceList struct {
+	Id   openapi_types.UUID `json:"id"`
+	Name string             `json:"name"`
+}
+
+// CancelOrderRequest defines model for CancelOrderRequest.
+type CancelOrderRequest struct {
+	Note       *string               `json:"note,omitempty"`
+	ReasonCode OrderCancelReasonCode `json:"reasonCode"`
+}
+
 // Cart defines model for Cart.
 type Cart struct {
 	Items     []CartItem `json:"items"`
@@ -237,6 +303,66 @@ type CartItem struct {
 	Sku SKU                `json:"sku"`
 }
 
+// CatalogSearchCategoryFacet defines model for CatalogSearchCategoryFacet.
+type CatalogSearchCategoryFacet struct {
+	CategoryId openapi_types.UUID `json:"categoryId"`
+	Count      int                `json:"count"`
+	Name       string             `json:"name"`
+}
+
+// CatalogSearchFacetValue defines model for CatalogSearchFacetValue.
+type CatalogSearchFacetValue struct {
+	Count int    `json:"count"`
+	Value string `json:"value"`
+}
+
+// CatalogSearchFacets Match counts over all matching products, at most 20 values per facet
+type CatalogSearchFacets struct {
+	Categories       []CatalogSearchCategoryFacet `json:"categories"`
+	FilterDimensions []CatalogSearchFacetValue    `json:"filterDimensions"`
+	Tags             []CatalogSearchFacetValue    `json:"tags"`
+}
+
+// CatalogSearchHighlights HTML-escaped text with matches wrapped in <em> tags
+type CatalogSearchHighlights struct {
+	// Description Excerpt of the description around its first match; omitted when the description does not match
+	Description *string `json:"description,omitempty"`
+	Name        string  `json:"name"`
+}
+
+// CatalogSearchHit defines model for CatalogSearchHit.
+type CatalogSearchHit struct {
+	// Highlights HTML-escaped text with matches wrapped in <em> tags
+	Highlights CatalogSearchHighlights `json:"highlights"`
+
+	// MatchedSku The first active SKU whose name, code or spec contains the query
+	MatchedSku *CatalogSearchMatchedSku `json:"matchedSku,omitempty"`
+	Product    ProductSummary           `json:"product"`
+
+	// Score Relevance; higher is better and only comparable within one search
+	Score float64 `json:"score"`
+}
+
+// CatalogSearchMatchedSku The first active SKU whose name, code or spec contains the query
+type CatalogSearchMatchedSku struct {
+	// Highlight HTML-escaped SKU name with matches wrapped in <em> tags
+	Highlight string             `json:"highlight"`
+	Id        openapi_types.UUID `json:"id"`
+	Name      string             `json:"name"`
+	SkuCode   *string            `json:"skuCode,omitempty"`
+	Spec      *string            `json:"spec,omitempty"`
+}
+
+// CatalogSearchResult defines model for CatalogSearchResult.
+type CatalogSearchResult struct {
+	// Facets Match counts over all matching products, at most 20 values per facet
+	Facets   CatalogSearchFacets `json:"facets"`
+	Items    []CatalogSearchHit  `json:"items"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"pageSize"`
+	Total    int                 `json:"total"`
+}
+
 // Category defines model for Category.
 type Category struct {
 	Id       openapi_types.UUID  `json:"id"`
@@ -245,6 +371,77 @@ type Category struct {
 	Sort     int                 `json:"sort"`
 }
 
+// CheckoutPreview defines model for CheckoutPreview.
+type CheckoutPreview struct {
+	DiscountFen int64                 `json:"discountFen"`
+	Discounts   []OrderDiscount       `json:"discounts"`
+	Items       []CheckoutPreviewLine `json:"items"`
+
+	// Orderable False when POST /orders would reject these items or coupon; the blocking warnings say why.
+	Orderable bool `json:"orderable"`
+
+	// ShippingFen Freight to the address for the lines that can be ordered, in fen.
+	ShippingFen int64 `json:"shippingFen"`
+
+	// SubtotalFen Sum of the lines that can be ordered, in fen.
+	SubtotalFen int64             `json:"subtotalFen"`
+	TotalFen    int64             `json:"totalFen"`
+	Warnings    []CheckoutWarning `json:"warnings"`
+}
+
+// CheckoutPreviewLine defines model for CheckoutPreviewLine.
+type CheckoutPreviewLine struct {
+	CartItemId   openapi_types.UUID `json:"cartItemId"`
+	LineTotalFen *int64             `json:"lineTotalFen,omitempty"`
+	PriceTier    *PriceTier         `json:"priceTier,omitempty"`
+	Qty          int                `json:"qty"`
+	Sku          *SKU               `json:"sku,omitempty"`
+	SkuId        openapi_types.UUID `json:"skuId"`
+	UnitPriceFen *int64             `json:"unitPriceFen,omitempty"`
+}
+
+// CheckoutPreviewRequest defines model for CheckoutPreviewRequest.
+type CheckoutPreviewRequest struct {
+	Address    Address `json:"address"`
+	CouponCode *string `json:"couponCode,omitempty"`
+	Items      []struct {
+		CartItemId openapi_types.UUID `json:"cartItemId"`
+		Qty        int                `json:"qty"`
+		SkuId      openapi_types.UUID `json:"skuId"`
+
+		// UnitPriceFen Unit price the customer was shown. A different current price is reported as PRICE_CHANGED.
+		UnitPriceFen *int64 `json:"unitPriceFen,omitempty"`
+	} `json:"items"`
+}
+
+// CheckoutWarning SKU_NOT_FOUND, SKU_INACTIVE, PRICE_UNAVAILABLE, CART_ITEM_CHANGED, INSUFFICIENT_STOCK, COUPON_INVALID, COUPON_UNAVAILABLE and CREDIT_LIMIT_EXCEEDED block the order.
+// PRICE_CHANGED and TIER_BREAKPOINT_NEAR are advisory; TIER_BREAKPOINT_NEAR means ordering additionalQty more of the SKU, at most a fifth of nextTierMinQty, reaches a cheaper tier.
+// INSUFFICIENT_STOCK carries availableQty of the SKU; CREDIT_LIMIT_EXCEEDED carries creditLimitFen and outstandingFen of a customer on monthly terms.
+type CheckoutWarning struct {
+	AdditionalQty        *int                `json:"additionalQty,omitempty"`
+	AvailableQty         *int                `json:"availableQty,omitempty"`
+	Blocking             bool                `json:"blocking"`
+	CartItemId           *openapi_types.UUID `json:"cartItemId,omitempty"`
+	Code                 CheckoutWarningCode `json:"code"`
+	CreditLimitFen       *int64              `json:"creditLimitFen,omitempty"`
+	Message              string              `json:"message"`
+	NextTierMinQty       *int                `json:"nextTierMinQty,omitempty"`
+	NextTierUnitPriceFen *int64              `json:"nextTierUnitPriceFen,omitempty"`
+
+	// OutstandingFen Open receivables of the customer before this order.
+	OutstandingFen *int64              `json:"outstandingFen,omitempty"`
+	SkuId          *openapi_types.UUID `json:"skuId,omitempty"`
+}
+
+// CheckoutWarningCode defines model for CheckoutWarning.Code.
+type CheckoutWarningCode string
+
+// CloseOrderRequest defines model for CloseOrderRequest.
+type CloseOrderRequest struct {
+	Note       string               `json:"note"`
+	ReasonCode OrderCloseReasonCode `json:"reasonCode"`
+}
+
 // ConfirmCartImportRequest defines model for ConfirmCartImportRequest.
 type ConfirmCartImportRequest struct {
 	Selections []CartImportSelection `json:"selections"`
@@ -282,15 +479,21 @@ type CreateInquiryMessage struct {
 	Content string `json:"content"`
 }
 
-// CreateOrderRequest defines model for CreateOrderRequest.
+// CreateOrderRequest Orders either cart items at tier prices or an open quotation at its quoted prices; exactly one of items and quotationId is required.
 type CreateOrderRequest struct {
 	Address Address `json:"address"`
-	Items   []struct {
+
+	// CouponCode Coupon to redeem, compared case-insensitively. Only cart orders take coupons.
+	CouponCode *string `json:"couponCode,omitempty"`
+	Items      *[]struct {
 		CartItemId openapi_types.UUID `json:"cartItemId"`
 		Qty        int                `json:"qty"`
 		SkuId      openapi_types.UUID `json:"skuId"`
-	} `json:"items"`
-	Remark *string `json:"remark,omitempty"`
+	} `json:"items,omitempty"`
+
+	// QuotationId Converts a SENT or ACCEPTED quotation of the caller into this order. The quotation's lines and prices are used and it becomes CONVERTED.
+	QuotationId *openapi_types.UUID `json:"quotationId,omitempty"`
+	Remark      *string             `json:"remark,omitempty"`
 }
 
 // CreatePriceInquiry defines model for CreatePriceInquiry.
@@ -315,6 +518,17 @@ type CreateProductRequest struct {
 	Spec               *string   `json:"spec,omitempty"`
 }
 
+// CreateQuotationRequest defines model for CreateQuotationRequest.
+type CreateQuotationRequest struct {
+	Items []struct {
+		Qty          int                `json:"qty"`
+		SkuId        openapi_types.UUID `json:"skuId"`
+		UnitPriceFen int64              `json:"unitPriceFen"`
+	} `json:"items"`
+	Terms      *string            `json:"terms,omitempty"`
+	ValidUntil openapi_types.Date `json:"validUntil"`
+}
+
 // CreateSkuRequest defines model for CreateSkuRequest.
 type CreateSkuRequest struct {
 	Attributes *map[string]string `json:"attributes,omitempty"`
@@ -326,6 +540,12 @@ type CreateSkuRequest struct {
 	// Spec Primary spec label used for matching (e.g., size/grade); store here and avoid duplicating in attributes
 	Spec *string `json:"spec,omitempty"`
 	Unit *string `json:"unit,omitempty"`
+
+	// VolumeCm3 Shipping volume of one unit in cubic centimetres
+	VolumeCm3 *int `json:"volumeCm3,omitempty"`
+
+	// WeightGrams Shipping weight of one unit in grams
+	WeightGrams *int `json:"weightGrams,omitempty"`
 }
 
 // CreateTicketMessage defines model for CreateTicketMessage.
@@ -400,18 +620,36 @@ type MessageSenderType string
 
 // Order defines model for Order.
 type Order struct {
-	Address          *Address            `json:"address,omitempty"`
-	CreatedAt        time.Time           `json:"createdAt"`
-	Id               openapi_types.UUID  `json:"id"`
-	Items            []OrderItem         `json:"items"`
-	LatestPaymentId  *openapi_types.UUID `json:"latestPaymentId,omitempty"`
+	Address   *Address  `json:"address,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// DiscountFen Promotion discounts taken off subtotalFen, in fen.
+	DiscountFen *int64 `json:"discountFen,omitempty"`
+
+	// Discounts Discount lines of the order. Only returned when creating an order and by GET /orders/{orderId}.
+	Discounts       *[]OrderDiscount    `json:"discounts,omitempty"`
+	Id              openapi_types.UUID  `json:"id"`
+	Items           []OrderItem         `json:"items"`
+	LatestPaymentId *openapi_types.UUID `json:"latestPaymentId,omitempty"`
+
+	// OrderNo Sequential order number prefixed with the Asia/Shanghai creation date, for example TMO20261017-000123.
+	OrderNo          string              `json:"orderNo"`
 	OwnerSalesUserId *openapi_types.UUID `json:"ownerSalesUserId"`
 	PaidAt           *time.Time          `json:"paidAt"`
 	PaymentChannel   *string             `json:"paymentChannel"`
 	PaymentStatus    OrderPaymentStatus  `json:"paymentStatus"`
 	Remark           *string             `json:"remark,omitempty"`
-	Status           OrderStatus         `json:"status"`
-	UpdatedAt        *time.Time          `json:"updatedAt,omitempty"`
+
+	// ShippingFen Freight charged for the order, in fen.
+	ShippingFen *int64      `json:"shippingFen,omitempty"`
+	Status      OrderStatus `json:"status"`
+
+	// SubtotalFen Sum of the order lines in fen, before discounts.
+	SubtotalFen *int64 `json:"subtotalFen,omitempty"`
+
+	// TotalFen Amount the customer pays, subtotalFen less discountFen plus shippingFen, in fen.
+	TotalFen  *int64     `json:"totalFen,omitempty"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
 }
 
 // OrderAdminEvent defines model for OrderAdminEvent.
@@ -420,7 +658,7 @@ type OrderAdminEvent struct {
 	ActorUserId              openapi_types.UUID  `json:"actorUserId"`
 	CreatedAt                time.Time           `json:"createdAt"`
 	Id                       openapi_types.UUID  `json:"id"`
-	NewOwnerSalesUserId      openapi_types.UUID  `json:"newOwnerSalesUserId"`
+	NewOwnerSalesUserId      *openapi_types.UUID `json:"newOwnerSalesUserId"`
 	NewPaymentStatus         OrderPaymentStatus  `json:"newPaymentStatus"`
 	NewStatus                OrderStatus         `json:"newStatus"`
 	Note                     string              `json:"note"`
@@ -428,6 +666,7 @@ type OrderAdminEvent struct {
 	PreviousOwnerSalesUserId *openapi_types.UUID `json:"previousOwnerSalesUserId"`
 	PreviousPaymentStatus    OrderPaymentStatus  `json:"previousPaymentStatus"`
 	PreviousStatus           OrderStatus         `json:"previousStatus"`
+	ReasonCode               *string             `json:"reasonCode"`
 }
 
 // OrderAdminEventList defines model for OrderAdminEventList.
@@ -435,6 +674,20 @@ type OrderAdminEventList struct {
 	Items []OrderAdminEvent `json:"items"`
 }
 
+// OrderCancelReasonCode defines model for OrderCancelReasonCode.
+type OrderCancelReasonCode string
+
+// OrderCloseReasonCode defines model for OrderCloseReasonCode.
+type OrderCloseReasonCode string
+
+// OrderDiscount defines model for OrderDiscount.
+type OrderDiscount struct {
+	AmountFen   int64              `json:"amountFen"`
+	CouponCode  *string            `json:"couponCode,omitempty"`
+	Label       string             `json:"label"`
+	PromotionId openapi_types.UUID `json:"promotionId"`
+}
+
 // OrderItem defines model for OrderItem.
 type OrderItem struct {
 	Qty int `json:"qty"`
@@ -601,39 +854,140 @@ type ProductSummary struct {
 	Tags          *[]string          `json:"tags,omitempty"`
 }
 
+// Quotation defines model for Quotation.
+type Quotation struct {
+	CreatedAt       time.Time           `json:"createdAt"`
+	CreatedByUserId openapi_types.UUID  `json:"createdByUserId"`
+	CustomerId      openapi_types.UUID  `json:"customerId"`
+	DecidedAt       *time.Time          `json:"decidedAt,omitempty"`
+	DecisionNote    *string             `json:"decisionNote,omitempty"`
+	Id              openapi_types.UUID  `json:"id"`
+	InquiryId       openapi_types.UUID  `json:"inquiryId"`
+	Items           []QuotationItem     `json:"items"`
+	OrderId         *openapi_types.UUID `json:"orderId,omitempty"`
+
+	// Status EXPIRED is reported for SENT or ACCEPTED quotations past their validity date.
+	Status   QuotationStatus `json:"status"`
+	Terms    *string         `json:"terms,omitempty"`
+	TotalFen int64           `json:"totalFen"`
+
+	// ValidUntil Last day, in China Standard Time, the quotation can be accepted or converted.
+	ValidUntil openapi_types.Date `json:"validUntil"`
+	Version    int                `json:"version"`
+}
+
+// QuotationDecisionRequest defines model for QuotationDecisionRequest.
+type QuotationDecisionRequest struct {
+	Note *string `json:"note,omitempty"`
+}
+
+// QuotationItem defines model for QuotationItem.
+type QuotationItem struct {
+	AmountFen int64              `json:"amountFen"`
+	Qty       int                `json:"qty"`
+	SkuCode   *string            `json:"skuCode,omitempty"`
+	SkuId     openapi_types.UUID `json:"skuId"`
+	SkuName   string             `json:"skuName"`
+	Spec      *string            `json:"spec,omitempty"`
+
+	// UnitPriceFen Negotiated unit price, locked when the quotation is converted to an order.
+	UnitPriceFen int64 `json:"unitPriceFen"`
+}
+
+// QuotationList defines model for QuotationList.
+type QuotationList struct {
+	Items []Quotation `json:"items"`
+}
+
+// QuotationStatus EXPIRED is reported for SENT or ACCEPTED quotations past their validity date.
+type QuotationStatus string
+
 // SKU defines model for SKU.
 type SKU struct {
-	Attributes *map[string]string `json:"attributes,omitempty"`
-	Id         openapi_types.UUID `json:"id"`
-	IsActive   bool               `json:"isActive"`
-	Name       string             `json:"name"`
-	PriceTiers *[]PriceTier       `json:"priceTiers,omitempty"`
-	SkuCode    *string            `json:"skuCode,omitempty"`
+	AppliedPriceList *AppliedPriceList  `json:"appliedPriceList,omitempty"`
+	Attributes       *map[string]string `json:"attributes,omitempty"`
+
+	// AvailableQty On-hand minus reserved stock; omitted when the SKU is not stock-tracked
+	AvailableQty *int               `json:"availableQty,omitempty"`
+	Id           openapi_types.UUID `json:"id"`
+	IsActive     bool               `json:"isActive"`
+	Name         string             `json:"name"`
+
+	// PriceTiers Tiers the caller pays; a customer's price list replaces or discounts the catalog tiers
+	PriceTiers *[]PriceTier `json:"priceTiers,omitempty"`
+	SkuCode    *string      `json:"skuCode,omitempty"`
 
 	// Spec Canonical spec label for matching; do not duplicate in attributes
 	Spec  *string            `json:"spec,omitempty"`
 	SpuId openapi_types.UUID `json:"spuId"`
 	Unit  *string            `json:"unit,omitempty"`
+
+	// VolumeCm3 Shipping volume of one unit in cubic centimetres
+	VolumeCm3 *int `json:"volumeCm3,omitempty"`
+
+	// WeightGrams Shipping weight of one unit in grams
+	WeightGrams *int `json:"weightGrams,omitempty"`
 }
 
 // ShipOrderRequest defines model for ShipOrderRequest.
 type ShipOrderRequest struct {
-	Carrier   *string    `json:"carrier"`
-	ShippedAt *time.Time `json:"shippedAt"`
-	WaybillNo string     `json:"waybillNo"`
+	Carrier *string `json:"carrier"`
+
+	// Lines Quantities in this parcel; omit to ship everything not yet shipped
+	Lines     *[]ShipmentLineRequest `json:"lines,omitempty"`
+	ShippedAt *time.Time             `json:"shippedAt"`
+	WaybillNo string                 `json:"waybillNo"`
+}
+
+// ShipmentItem defines model for ShipmentItem.
+type ShipmentItem struct {
+	OrderItemId openapi_types.UUID `json:"orderItemId"`
+	Qty         int                `json:"qty"`
+	SkuId       openapi_types.UUID `json:"skuId"`
+	SkuName     string             `json:"skuName"`
+}
+
+// ShipmentLineRequest defines model for ShipmentLineRequest.
+type ShipmentLineRequest struct {
+	OrderItemId openapi_types.UUID `json:"orderItemId"`
+	Qty         int                `json:"qty"`
 }
 
+// ShipmentTrackingEvent defines model for ShipmentTrackingEvent.
+type ShipmentTrackingEvent struct {
+	Description string                 `json:"description"`
+	Location    *string                `json:"location"`
+	OccurredAt  time.Time              `json:"occurredAt"`
+	Status      ShipmentTrackingStatus `json:"status"`
+}
+
+// ShipmentTrackingStatus defines model for ShipmentTrackingStatus.
+type ShipmentTrackingStatus string
+
 // TicketStatus defines model for TicketStatus.
 type TicketStatus string
 
 // TrackingInfo defines model for TrackingInfo.
 type TrackingInfo struct {
 	OrderId   openapi_types.UUID `json:"orderId"`
-	Shipments []struct {
-		Carrier   *string    `json:"carrier"`
-		ShippedAt *time.Time `json:"shippedAt"`
-		WaybillNo string     `json:"waybillNo"`
-	} `json:"shipments"`
+	Shipments []TrackingShipment `json:"shipments"`
+}
+
+// TrackingShipment defines model for TrackingShipment.
+type TrackingShipment struct {
+	Carrier     *string    `json:"carrier"`
+	DeliveredAt *time.Time `json:"deliveredAt"`
+
+	// Events Carrier trace, newest first
+	Events []ShipmentTrackingEvent `json:"events"`
+	Id     openapi_types.UUID      `json:"id"`
+
+	// Items Order lines packed in this parcel; empty for waybills recorded without shipment lines
+	Items       []ShipmentItem         `json:"items"`
+	LastEventAt *time.Time             `json:"lastEventAt"`
+	ShippedAt   *time.Time             `json:"shippedAt"`
+	Status      ShipmentTrackingStatus `json:"status"`
+	WaybillNo   string                 `json:"waybillNo"`
 }
 
 // UpdateAfterSalesTicketRequest defines model for UpdateAfterSalesTicketRequest.
@@ -691,6 +1045,12 @@ type UpdateSkuRequest struct {
 	// Spec Primary spec label used for matching (e.g., size/grade); store here and avoid duplicating in attributes
 	Spec *string `json:"spec,omitempty"`
 	Unit *string `json:"unit,omitempty"`
+
+	// VolumeCm3 Shipping volume of one unit in cubic centimetres
+	VolumeCm3 *int `json:"volumeCm3,omitempty"`
+
+	// WeightGrams Shipping weight of one unit in grams
+	WeightGrams *int `json:"weightGrams,omitempty"`
 }
 
 // UpdateTrackingRequest defines model for UpdateTrackingRequest.
@@ -744,6 +1104,11 @@ type PostAdminCatalogProductsAssetsMultipartBody struct {
 	File openapi_types.File `json:"file"`
 }
 
+// PostAdminOrdersOrderIdCloseParams defines parameters for PostAdminOrdersOrderIdClose.
+type PostAdminOrdersOrderIdCloseParams struct {
+	IdempotencyKey string `json:"Idempotency-Key"`
+}
+
 // PatchAdminOrdersOrderIdFulfillmentParams defines parameters for PatchAdminOrdersOrderIdFulfillment.
 type PatchAdminOrdersOrderIdFulfillmentParams struct {
 	IdempotencyKey string `json:"Idempotency-Key"`
@@ -782,6 +1147,17 @@ type GetCatalogProductsParams struct {
 	PageSize   *int                `form:"pageSize,omitempty" json:"pageSize,omitempty"`
 }
 
+// GetCatalogSearchParams defines parameters for GetCatalogSearch.
+type GetCatalogSearchParams struct {
+	// Q Words, Chinese text, SKU codes or pinyin initials
+	Q               string              `form:"q" json:"q"`
+	CategoryId      *openapi_types.UUID `form:"categoryId,omitempty" json:"categoryId,omitempty"`
+	Tag             *string             `form:"tag,omitempty" json:"tag,omitempty"`
+	FilterDimension *string             `form:"filterDimension,omitempty" json:"filterDimension,omitempty"`
+	Page            *int                `form:"page,omitempty" json:"page,omitempty"`
+	PageSize        *int                `form:"pageSize,omitempty" json:"pageSize,omitempty"`
+}
+
 // GetInquiriesPriceParams defines parameters for GetInquiriesPrice.
 type GetInquiriesPriceParams struct {
 	Status   *GetInquiriesPriceParamsStatus `form:"status,omitempty" json:"status,omitempty"`
@@ -803,6 +1179,7 @@ type GetOrdersParams struct {
 	CustomerId       *openapi_types.UUID `form:"customerId,omitempty" json:"customerId,omitempty"`
 	OwnerSalesUserId *openapi_types.UUID `form:"ownerSalesUserId,omitempty" json:"ownerSalesUserId,omitempty"`
 	Status           *OrderStatus        `form:"status,omitempty" json:"status,omitempty"`
+	OrderNo          *string             `form:"orderNo,omitempty" json:"orderNo,omitempty"`
 	Page             *int                `form:"page,omitempty" json:"page,omitempty"`
 	PageSize         *int                `form:"pageSize,omitempty" json:"pageSize,omitempty"`
 }
@@ -812,6 +1189,11 @@ type PostOrdersParams struct {
 	IdempotencyKey *string `json:"Idempotency-Key,omitempty"`
 }
 
+// PostOrdersOrderIdCancelParams defines parameters for PostOrdersOrderIdCancel.
+type PostOrdersOrderIdCancelParams struct {
+	IdempotencyKey *string `json:"Idempotency-Key,omitempty"`
+}
+
 // GetProductRequestsParams defines parameters for GetProductRequests.
 type GetProductRequestsParams struct {
 	// Q Search by request ID, customer ID, name, specification, material, dimensions, color, quantity, or note
@@ -846,6 +1228,9 @@ type PatchAddressesAddressIdJSONRequestBody = UpdateUserAddressRequest
 // PostAdminCatalogProductsAssetsMultipartRequestBody defines body for PostAdminCatalogProductsAssets for multipart/form-data ContentType.
 type PostAdminCatalogProductsAssetsMultipartRequestBody PostAdminCatalogProductsAssetsMultipartBody
 
+// PostAdminOrdersOrderIdCloseJSONRequestBody defines body for PostAdminOrdersOrderIdClose for application/json ContentType.
+type PostAdminOrdersOrderIdCloseJSONRequestBody = CloseOrderRequest
+
 // PatchAdminOrdersOrderIdFulfillmentJSONRequestBody defines body for PatchAdminOrdersOrderIdFulfillment for application/json ContentType.
 type PatchAdminOrdersOrderIdFulfillmentJSONRequestBody = UpdateOrderFulfillmentRequest
 
@@ -891,6 +1276,9 @@ type PostCatalogProductsSpuIdSkusJSONRequestBody = CreateSkuRequest
 // PatchCatalogProductsSpuIdSkusSkuIdJSONRequestBody defines body for PatchCatalogProductsSpuIdSkusSkuId for application/json ContentType.
 type PatchCatalogProductsSpuIdSkusSkuIdJSONRequestBody = UpdateSkuRequest
 
+// PostCheckoutPreviewJSONRequestBody defines body for PostCheckoutPreview for application/json ContentType.
+type PostCheckoutPreviewJSONRequestBody = CheckoutPreviewRequest
+
 // PostInquiriesPriceJSONRequestBody defines body for PostInquiriesPrice for application/json ContentType.
 type PostInquiriesPriceJSONRequestBody = CreatePriceInquiry
 
@@ -900,9 +1288,15 @@ type PatchInquiriesPriceInquiryIdJSONRequestBody = UpdatePriceInquiryRequest
 // PostInquiriesPriceInquiryIdMessagesJSONRequestBody defines body for PostInquiriesPriceInquiryIdMessages for application/json ContentType.
 type PostInquiriesPriceInquiryIdMessagesJSONRequestBody = CreateInquiryMessage
 
+// PostInquiriesPriceInquiryIdQuotationsJSONRequestBody defines body for PostInquiriesPriceInquiryIdQuotations for application/json ContentType.
+type PostInquiriesPriceInquiryIdQuotationsJSONRequestBody = CreateQuotationRequest
+
 // PostOrdersJSONRequestBody defines body for PostOrders for application/json ContentType.
 type PostOrdersJSONRequestBody = CreateOrderRequest
 
+// PostOrdersOrderIdCancelJSONRequestBody defines body for PostOrdersOrderIdCancel for application/json ContentType.
+type PostOrdersOrderIdCancelJSONRequestBody = CancelOrderRequest
+
 // PostOrdersOrderIdTrackingJSONRequestBody defines body for PostOrdersOrderIdTracking for application/json ContentType.
 type PostOrdersOrderIdTrackingJSONRequestBody = UpdateTrackingRequest
 
@@ -912,6 +1306,12 @@ type PostProductRequestsJSONRequestBody = CreateProductRequest
 // PostProductRequestsAssetsMultipartRequestBody defines body for PostProductRequestsAssets for multipart/form-data ContentType.
 type PostProductRequestsAssetsMultipartRequestBody PostProductRequestsAssetsMultipartBody
 
+// PostQuotationsQuotationIdAcceptJSONRequestBody defines body for PostQuotationsQuotationIdAccept for application/json ContentType.
+type PostQuotationsQuotationIdAcceptJSONRequestBody = QuotationDecisionRequest
+
+// PostQuotationsQuotationIdRejectJSONRequestBody defines body for PostQuotationsQuotationIdReject for application/json ContentType.
+type PostQuotationsQuotationIdRejectJSONRequestBody = QuotationDecisionRequest
+
 // PostShipmentsImportJobsMultipartRequestBody defines body for PostShipmentsImportJobs for multipart/form-data ContentType.
 type PostShipmentsImportJobsMultipartRequestBody PostShipmentsImportJobsMultipartBody
 
@@ -935,6 +1335,9 @@ type ServerInterface interface {
 	// Upload catalog product image asset
 	// (POST /admin/catalog/products/assets)
 	PostAdminCatalogProductsAssets(c *gin.Context)
+	// Close an order that has not been paid or shipped
+	// (POST /admin/orders/{orderId}/close)
+	PostAdminOrdersOrderIdClose(c *gin.Context, orderId openapi_types.UUID, params PostAdminOrdersOrderIdCloseParams)
 	// Confirm delivery for a shipped order
 	// (POST /admin/orders/{orderId}/confirm-delivery)
 	PostAdminOrdersOrderIdConfirmDelivery(c *gin.Context, orderId openapi_types.UUID)
@@ -1025,6 +1428,12 @@ type ServerInterface interface {
 	// Update SKU for product
 	// (PATCH /catalog/products/{spuId}/skus/{skuId})
 	PatchCatalogProductsSpuIdSkusSkuId(c *gin.Context, spuId openapi_types.UUID, skuId openapi_types.UUID)
+	// Search active products by text, SKU code or pinyin initials
+	// (GET /catalog/search)
+	GetCatalogSearch(c *gin.Context, params GetCatalogSearchParams)
+	// Price selected cart items without ordering
+	// (POST /checkout/preview)
+	PostCheckoutPreview(c *gin.Context)
 	// List price inquiries
 	// (GET /inquiries/price)
 	GetInquiriesPrice(c *gin.Context, params GetInquiriesPriceParams)
@@ -1043,6 +1452,12 @@ type ServerInterface interface {
 	// Post a message in price inquiry
 	// (POST /inquiries/price/{inquiryId}/messages)
 	PostInquiriesPriceInquiryIdMessages(c *gin.Context, inquiryId openapi_types.UUID)
+	// List quotation versions of a price inquiry
+	// (GET /inquiries/price/{inquiryId}/quotations)
+	GetInquiriesPriceInquiryIdQuotations(c *gin.Context, inquiryId openapi_types.UUID)
+	// Send a quotation, or revise the current one
+	// (POST /inquiries/price/{inquiryId}/quotations)
+	PostInquiriesPriceInquiryIdQuotations(c *gin.Context, inquiryId openapi_types.UUID)
 	// List orders (scope by role)
 	// (GET /orders)
 	GetOrders(c *gin.Context, params GetOrdersParams)
@@ -1055,6 +1470,9 @@ type ServerInterface interface {
 	// Get my order detail
 	// (GET /orders/{orderId})
 	GetOrdersOrderId(c *gin.Context, orderId openapi_types.UUID)
+	// Cancel an unpaid order
+	// (POST /orders/{orderId}/cancel)
+	PostOrdersOrderIdCancel(c *gin.Context, orderId openapi_types.UUID, params PostOrdersOrderIdCancelParams)
 	// Confirm receipt for a shipped order
 	// (POST /orders/{orderId}/confirm-receipt)
 	PostOrdersOrderIdConfirmReceipt(c *gin.Context, orderId openapi_types.UUID)
@@ -1073,6 +1491,15 @@ type ServerInterface interface {
 	// Upload product request asset
 	// (POST /product-requests/assets)
 	PostProductRequestsAssets(c *gin.Context)
+	// Get a quotation
+	// (GET /quotations/{quotationId})
+	GetQuotationsQuotationId(c *gin.Context, quotationId openapi_types.UUID)
+	// Accept a quotation (customer)
+	// (POST /quotations/{quotationId}/accept)
+	PostQuotationsQuotationIdAccept(c *gin.Context, quotationId openapi_types.UUID)
+	// Reject a quotation (customer)
+	// (POST /quotations/{quotationId}/reject)
+	PostQuotationsQuotationIdReject(c *gin.Context, quotationId openapi_types.UUID)
 	// Upload Excel for bulk waybill import (procurement)
 	// (POST /shipments/import-jobs)
 	PostShipmentsImportJobs(c *gin.Context)
@@ -1193,6 +1620,59 @@ func (siw *ServerInterfaceWrapper) PostAdminCatalogProductsAssets(c *gin.Context
 	siw.Handler.PostAdminCatalogProductsAssets(c)
 }
 
+// PostAdminOrdersOrderIdClose operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminOrdersOrderIdClose(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "orderId" -------------
+	var orderId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "orderId", c.Param("orderId"), &orderId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter orderId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostAdminOrdersOrderIdCloseParams
+
+	headers := c.Request.Header
+
+	// ------------- Required header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandler(c, fmt.Errorf("Expected one value for Idempotency-Key, got %d", n), http.StatusBadRequest)
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter Idempotency-Key: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.IdempotencyKey = IdempotencyKey
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Header parameter Idempotency-Key is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostAdminOrdersOrderIdClose(c, orderId, params)
+}
+
 // PostAdminOrdersOrderIdConfirmDelivery operation middleware
 func (siw *ServerInterfaceWrapper) PostAdminOrdersOrderIdConfirmDelivery(c *gin.Context) {
 
@@ -1990,6 +2470,94 @@ func (siw *ServerInterfaceWrapper) PatchCatalogProductsSpuIdSkusSkuId(c *gin.Con
 	siw.Handler.PatchCatalogProductsSpuIdSkusSkuId(c, spuId, skuId)
 }
 
+// GetCatalogSearch operation middleware
+func (siw *ServerInterfaceWrapper) GetCatalogSearch(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCatalogSearchParams
+
+	// ------------- Required query parameter "q" -------------
+
+	if paramValue := c.Query("q"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument q is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "q", c.Request.URL.Query(), &params.Q)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter q: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "categoryId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "categoryId", c.Request.URL.Query(), &params.CategoryId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter categoryId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "tag" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag", c.Request.URL.Query(), &params.Tag)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter tag: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "filterDimension" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "filterDimension", c.Request.URL.Query(), &params.FilterDimension)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter filterDimension: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", c.Request.URL.Query(), &params.Page)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter page: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "pageSize" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "pageSize", c.Request.URL.Query(), &params.PageSize)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter pageSize: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetCatalogSearch(c, params)
+}
+
+// PostCheckoutPreview operation middleware
+func (siw *ServerInterfaceWrapper) PostCheckoutPreview(c *gin.Context) {
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostCheckoutPreview(c)
+}
+
 // GetInquiriesPrice operation middleware
 func (siw *ServerInterfaceWrapper) GetInquiriesPrice(c *gin.Context) {
 
@@ -2172,6 +2740,58 @@ func (siw *ServerInterfaceWrapper) PostInquiriesPriceInquiryIdMessages(c *gin.Co
 	siw.Handler.PostInquiriesPriceInquiryIdMessages(c, inquiryId)
 }
 
+// GetInquiriesPriceInquiryIdQuotations operation middleware
+func (siw *ServerInterfaceWrapper) GetInquiriesPriceInquiryIdQuotations(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "inquiryId" -------------
+	var inquiryId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "inquiryId", c.Param("inquiryId"), &inquiryId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter inquiryId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetInquiriesPriceInquiryIdQuotations(c, inquiryId)
+}
+
+// PostInquiriesPriceInquiryIdQuotations operation middleware
+func (siw *ServerInterfaceWrapper) PostInquiriesPriceInquiryIdQuotations(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "inquiryId" -------------
+	var inquiryId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "inquiryId", c.Param("inquiryId"), &inquiryId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter inquiryId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostInquiriesPriceInquiryIdQuotations(c, inquiryId)
+}
+
 // GetOrders operation middleware
 func (siw *ServerInterfaceWrapper) GetOrders(c *gin.Context) {
 
@@ -2206,6 +2826,14 @@ func (siw *ServerInterfaceWrapper) GetOrders(c *gin.Context) {
 		return
 	}
 
+	// ------------- Optional query parameter "orderNo" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "orderNo", c.Request.URL.Query(), &params.OrderNo)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter orderNo: %w", err), http.StatusBadRequest)
+		return
+	}
+
 	// ------------- Optional query parameter "page" -------------
 
 	err = runtime.BindQueryParameter("form", true, false, "page", c.Request.URL.Query(), &params.Page)
@@ -2314,6 +2942,56 @@ func (siw *ServerInterfaceWrapper) GetOrdersOrderId(c *gin.Context) {
 	siw.Handler.GetOrdersOrderId(c, orderId)
 }
 
+// PostOrdersOrderIdCancel operation middleware
+func (siw *ServerInterfaceWrapper) PostOrdersOrderIdCancel(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "orderId" -------------
+	var orderId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "orderId", c.Param("orderId"), &orderId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter orderId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostOrdersOrderIdCancelParams
+
+	headers := c.Request.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandler(c, fmt.Errorf("Expected one value for Idempotency-Key, got %d", n), http.StatusBadRequest)
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter Idempotency-Key: %w", err), http.StatusBadRequest)
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostOrdersOrderIdCancel(c, orderId, params)
+}
+
 // PostOrdersOrderIdConfirmReceipt operation middleware
 func (siw *ServerInterfaceWrapper) PostOrdersOrderIdConfirmReceipt(c *gin.Context) {
 
@@ -2482,6 +3160,84 @@ func (siw *ServerInterfaceWrapper) PostProductRequestsAssets(c *gin.Context) {
 	siw.Handler.PostProductRequestsAssets(c)
 }
 
+// GetQuotationsQuotationId operation middleware
+func (siw *ServerInterfaceWrapper) GetQuotationsQuotationId(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "quotationId" -------------
+	var quotationId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "quotationId", c.Param("quotationId"), &quotationId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter quotationId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetQuotationsQuotationId(c, quotationId)
+}
+
+// PostQuotationsQuotationIdAccept operation middleware
+func (siw *ServerInterfaceWrapper) PostQuotationsQuotationIdAccept(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "quotationId" -------------
+	var quotationId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "quotationId", c.Param("quotationId"), &quotationId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter quotationId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostQuotationsQuotationIdAccept(c, quotationId)
+}
+
+// PostQuotationsQuotationIdReject operation middleware
+func (siw *ServerInterfaceWrapper) PostQuotationsQuotationIdReject(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "quotationId" -------------
+	var quotationId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "quotationId", c.Param("quotationId"), &quotationId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter quotationId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostQuotationsQuotationIdReject(c, quotationId)
+}
+
 // PostShipmentsImportJobs operation middleware
 func (siw *ServerInterfaceWrapper) PostShipmentsImportJobs(c *gin.Context) {
 
@@ -2585,6 +3341,7 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 	router.DELETE(options.BaseURL+"/addresses/:addressId", wrapper.DeleteAddressesAddressId)
 	router.PATCH(options.BaseURL+"/addresses/:addressId", wrapper.PatchAddressesAddressId)
 	router.POST(options.BaseURL+"/admin/catalog/products/assets", wrapper.PostAdminCatalogProductsAssets)
+	router.POST(options.BaseURL+"/admin/orders/:orderId/close", wrapper.PostAdminOrdersOrderIdClose)
 	router.POST(options.BaseURL+"/admin/orders/:orderId/confirm-delivery", wrapper.PostAdminOrdersOrderIdConfirmDelivery)
 	router.GET(options.BaseURL+"/admin/orders/:orderId/events", wrapper.GetAdminOrdersOrderIdEvents)
 	router.PATCH(options.BaseURL+"/admin/orders/:orderId/fulfillment", wrapper.PatchAdminOrdersOrderIdFulfillment)
@@ -2615,22 +3372,30 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 	router.PATCH(options.BaseURL+"/catalog/products/:spuId", wrapper.PatchCatalogProductsSpuId)
 	router.POST(options.BaseURL+"/catalog/products/:spuId/skus", wrapper.PostCatalogProductsSpuIdSkus)
 	router.PATCH(options.BaseURL+"/catalog/products/:spuId/skus/:skuId", wrapper.PatchCatalogProductsSpuIdSkusSkuId)
+	router.GET(options.BaseURL+"/catalog/search", wrapper.GetCatalogSearch)
+	router.POST(options.BaseURL+"/checkout/preview", wrapper.PostCheckoutPreview)
 	router.GET(options.BaseURL+"/inquiries/price", wrapper.GetInquiriesPrice)
 	router.POST(options.BaseURL+"/inquiries/price", wrapper.PostInquiriesPrice)
 	router.GET(options.BaseURL+"/inquiries/price/:inquiryId", wrapper.GetInquiriesPriceInquiryId)
 	router.PATCH(options.BaseURL+"/inquiries/price/:inquiryId", wrapper.PatchInquiriesPriceInquiryId)
 	router.GET(options.BaseURL+"/inquiries/price/:inquiryId/messages", wrapper.GetInquiriesPriceInquiryIdMessages)
 	router.POST(options.BaseURL+"/inquiries/price/:inquiryId/messages", wrapper.PostInquiriesPriceInquiryIdMessages)
+	router.GET(options.BaseURL+"/inquiries/price/:inquiryId/quotations", wrapper.GetInquiriesPriceInquiryIdQuotations)
+	router.POST(options.BaseURL+"/inquiries/price/:inquiryId/quotations", wrapper.PostInquiriesPriceInquiryIdQuotations)
 	router.GET(options.BaseURL+"/orders", wrapper.GetOrders)
 	router.POST(options.BaseURL+"/orders", wrapper.PostOrders)
 	router.GET(options.BaseURL+"/orders/stats", wrapper.GetOrdersStats)
 	router.GET(options.BaseURL+"/orders/:orderId", wrapper.GetOrdersOrderId)
+	router.POST(options.BaseURL+"/orders/:orderId/cancel", wrapper.PostOrdersOrderIdCancel)
 	router.POST(options.BaseURL+"/orders/:orderId/confirm-receipt", wrapper.PostOrdersOrderIdConfirmReceipt)
 	router.GET(options.BaseURL+"/orders/:orderId/tracking", wrapper.GetOrdersOrderIdTracking)
 	router.POST(options.BaseURL+"/orders/:orderId/tracking", wrapper.PostOrdersOrderIdTracking)
 	router.GET(options.BaseURL+"/product-requests", wrapper.GetProductRequests)
 	router.POST(options.BaseURL+"/product-requests", wrapper.PostProductRequests)
 	router.POST(options.BaseURL+"/product-requests/assets", wrapper.PostProductRequestsAssets)
+	router.GET(options.BaseURL+"/quotations/:quotationId", wrapper.GetQuotationsQuotationId)
+	router.POST(options.BaseURL+"/quotations/:quotationId/accept", wrapper.PostQuotationsQuotationIdAccept)
+	router.POST(options.BaseURL+"/quotations/:quotationId/reject", wrapper.PostQuotationsQuotationIdReject)
 	router.POST(options.BaseURL+"/shipments/import-jobs", wrapper.PostShipmentsImportJobs)
 	router.GET(options.BaseURL+"/wishlist", wrapper.GetWishlist)
 	router.POST(options.BaseURL+"/wishlist", wrapper.PostWishlist)
@@ -116,6 +116,14 @@ func (server *stubServer) PatchCatalogProductsSpuIdSkusSkuId(context *gin.Contex
 	context.Status(http.StatusNotImplemented)
 }
 
+func (server *stubServer) GetCatalogSearch(context *gin.Context, params oapi.GetCatalogSearchParams) {
+	context.Status(http.StatusNotImplemented)
+}
+
+func (server *stubServer) PostCheckoutPreview(context *gin.Context) {
+	context.Status(http.StatusNotImplemented)
+}
+
 func (server *stubServer) GetOrders(context *gin.Context, params oapi.GetOrdersParams) {
 	context.Status(http.StatusNotImplemented)
 }
@@ -140,6 +148,10 @@ func (server *stubServer) PostAdminOrdersOrderIdShip(context *gin.Context, order
 	context.Status(http.StatusNotImplemented)
 }
 
+func (server *stubServer) PostAdminOrdersOrderIdClose(context *gin.Context, orderId openapi_types.UUID, params oapi.PostAdminOrdersOrderIdCloseParams) {
+	context.Status(http.StatusNotImplemented)
+}
+
 func (server *stubServer) PostAdminOrdersOrderIdConfirmDelivery(context *gin.Context, orderId openapi_types.UUID) {
 	context.Status(http.StatusNotImplemented)
 }
@@ -152,6 +164,10 @@ func (server *stubServer) GetOrdersOrderIdTracking(context *gin.Context, orderId
 	context.Status(http.StatusNotImplemented)
 }
 
+func (server *stubServer) PostOrdersOrderIdCancel(context *gin.Context, orderId openapi_types.UUID, params oapi.PostOrdersOrderIdCancelParams) {
+	context.Status(http.StatusNotImplemented)
+}
+
 func (server *stubServer) PostOrdersOrderIdConfirmReceipt(context *gin.Context, orderId openapi_types.UUID) {
 	context.Status(http.StatusNotImplemented)
 }
@@ -212,6 +228,26 @@ func (server *stubServer) PostInquiriesPriceInquiryIdMessages(context *gin.Conte
 	context.Status(http.StatusNotImplemented)
 }
 
+func (server *stubServer) GetInquiriesPriceInquiryIdQuotations(context *gin.Context, inquiryId openapi_types.UUID) {
+	context.Status(http.StatusNotImplemented)
+}
+
+func (server *stubServer) PostInquiriesPriceInquiryIdQuotations(context *gin.Context, inquiryId openapi_types.UUID) {
+	context.Status(http.StatusNotImplemented)
+}
+
+func (server *stubServer) GetQuotationsQuotationId(context *gin.Context, quotationId openapi_types.UUID) {
+	context.Status(http.StatusNotImplemented)
+}
+
+func (server *stubServer) PostQuotationsQuotationIdAccept(context *gin.Context, quotationId openapi_types.UUID) {
+	context.Status(http.StatusNotImplemented)
+}
+
+func (server *stubServer) PostQuotationsQuotationIdReject(context *gin.Context, quotationId openapi_types.UUID) {
+	context.Status(http.StatusNotImplemented)
+}
+
 func (server *stubServer) GetProductRequests(context *gin.Context, params oapi.GetProductRequestsParams) {
 	context.Status(http.StatusNotImplemented)
 }
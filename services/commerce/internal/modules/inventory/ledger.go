@@ -0,0 +1,274 @@
+package inventory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+const (
+	MovementAdjustment = "ADJUSTMENT"
+	MovementReserve    = "RESERVE"
+	MovementRelease    = "RELEASE"
+	MovementConsume    = "CONSUME"
+
+	ReservationActive   = "ACTIVE"
+	ReservationReleased = "RELEASED"
+	ReservationConsumed = "CONSUMED"
+)
+
+var (
+	ErrZeroAdjustment       = errors.New("adjustment delta must not be zero")
+	ErrInvalidReason        = errors.New("invalid adjustment reason")
+	ErrNegativeOnHand       = errors.New("adjustment would make on-hand stock negative")
+	ErrOnHandBelowReserved  = errors.New("adjustment would drop on-hand stock below reserved quantity")
+	ErrNegativeLowThreshold = errors.New("lowStockThreshold must be >= 0")
+)
+
+var adjustmentReasons = []string{"RESTOCK", "STOCKTAKE", "DAMAGE", "RETURN", "CORRECTION"}
+
+type InsufficientStockError struct {
+	SkuID     uuid.UUID
+	Requested int32
+	Available int32
+}
+
+func (e InsufficientStockError) Error() string {
+	return fmt.Sprintf("insufficient stock for sku %s: requested %d, available %d", e.SkuID, e.Requested, e.Available)
+}
+
+type Adjustment struct {
+	SkuID       uuid.UUID
+	Delta       int32
+	Reason      string
+	Note        *string
+	ActorUserID pgtype.UUID
+}
+
+func Available(record db.SkuInventory) int32 {
+	return record.OnHand - record.Reserved
+}
+
+func NormalizeAdjustmentReason(raw string) (string, bool) {
+	reason := strings.ToUpper(strings.TrimSpace(raw))
+	if !slices.Contains(adjustmentReasons, reason) {
+		return "", false
+	}
+	return reason, true
+}
+
+// Reserve holds stock for each tracked SKU in lines against orderID. SKUs
+// without an inventory record are untracked and are accepted as-is.
+func Reserve(ctx context.Context, store LedgerStore, orderID uuid.UUID, lines map[uuid.UUID]int32) error {
+	if len(lines) == 0 {
+		return nil
+	}
+	records, err := store.LockSkuInventoryBySkuIDs(ctx, sortedSkuIDs(lines))
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if requested := lines[record.SkuID]; requested > Available(record) {
+			return InsufficientStockError{SkuID: record.SkuID, Requested: requested, Available: max(Available(record), 0)}
+		}
+	}
+	for _, record := range records {
+		qty := lines[record.SkuID]
+		if qty <= 0 {
+			continue
+		}
+		updated, err := store.UpdateSkuInventoryLevels(ctx, db.UpdateSkuInventoryLevelsParams{
+			SkuID:    record.SkuID,
+			OnHand:   record.OnHand,
+			Reserved: record.Reserved + qty,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := store.CreateSkuInventoryReservation(ctx, db.CreateSkuInventoryReservationParams{
+			OrderID: orderID,
+			SkuID:   record.SkuID,
+			Qty:     qty,
+		}); err != nil {
+			return err
+		}
+		if _, err := store.CreateSkuInventoryMovement(ctx, db.CreateSkuInventoryMovementParams{
+			SkuID:         record.SkuID,
+			MovementType:  MovementReserve,
+			OnHandDelta:   0,
+			ReservedDelta: qty,
+			OnHandAfter:   updated.OnHand,
+			ReservedAfter: updated.Reserved,
+			OrderID:       pgtype.UUID{Bytes: orderID, Valid: true},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Release returns every active reservation of orderID to available stock.
+func Release(ctx context.Context, store LedgerStore, orderID uuid.UUID, actorUserID pgtype.UUID, reason string) error {
+	return settleReservations(ctx, store, orderID, actorUserID, reason, MovementRelease, ReservationReleased)
+}
+
+// Consume turns every active reservation of orderID into an on-hand deduction
+// once the goods leave the warehouse.
+func Consume(ctx context.Context, store LedgerStore, orderID uuid.UUID, actorUserID pgtype.UUID) error {
+	return settleReservations(ctx, store, orderID, actorUserID, "", MovementConsume, ReservationConsumed)
+}
+
+func settleReservations(ctx context.Context, store LedgerStore, orderID uuid.UUID, actorUserID pgtype.UUID, reason, movementType, status string) error {
+	reservations, err := store.ListActiveSkuInventoryReservationsByOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if len(reservations) == 0 {
+		return nil
+	}
+	qtyBySku := make(map[uuid.UUID]int32, len(reservations))
+	for _, reservation := range reservations {
+		qtyBySku[reservation.SkuID] += reservation.Qty
+	}
+	records, err := store.LockSkuInventoryBySkuIDs(ctx, sortedSkuIDs(qtyBySku))
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		qty := qtyBySku[record.SkuID]
+		onHandDelta := int32(0)
+		if movementType == MovementConsume {
+			onHandDelta = -qty
+		}
+		updated, err := store.UpdateSkuInventoryLevels(ctx, db.UpdateSkuInventoryLevelsParams{
+			SkuID:    record.SkuID,
+			OnHand:   record.OnHand + onHandDelta,
+			Reserved: record.Reserved - qty,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := store.CreateSkuInventoryMovement(ctx, db.CreateSkuInventoryMovementParams{
+			SkuID:         record.SkuID,
+			MovementType:  movementType,
+			OnHandDelta:   onHandDelta,
+			ReservedDelta: -qty,
+			OnHandAfter:   updated.OnHand,
+			ReservedAfter: updated.Reserved,
+			OrderID:       pgtype.UUID{Bytes: orderID, Valid: true},
+			ActorUserID:   actorUserID,
+			Reason:        optionalText(reason),
+		}); err != nil {
+			return err
+		}
+	}
+	for _, reservation := range reservations {
+		if err := store.UpdateSkuInventoryReservationStatus(ctx, db.UpdateSkuInventoryReservationStatusParams{
+			ID:     reservation.ID,
+			Status: status,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Adjust applies a manual on-hand correction. The first adjustment of a SKU
+// creates its inventory record and from then on the SKU is stock-tracked.
+func Adjust(ctx context.Context, store LedgerStore, adjustment Adjustment) (db.SkuInventory, db.SkuInventoryMovement, error) {
+	if adjustment.Delta == 0 {
+		return db.SkuInventory{}, db.SkuInventoryMovement{}, ErrZeroAdjustment
+	}
+	reason, ok := NormalizeAdjustmentReason(adjustment.Reason)
+	if !ok {
+		return db.SkuInventory{}, db.SkuInventoryMovement{}, ErrInvalidReason
+	}
+	record, err := lockOrCreate(ctx, store, adjustment.SkuID)
+	if err != nil {
+		return db.SkuInventory{}, db.SkuInventoryMovement{}, err
+	}
+	onHand := int64(record.OnHand) + int64(adjustment.Delta)
+	if onHand < 0 {
+		return db.SkuInventory{}, db.SkuInventoryMovement{}, ErrNegativeOnHand
+	}
+	if onHand < int64(record.Reserved) {
+		return db.SkuInventory{}, db.SkuInventoryMovement{}, ErrOnHandBelowReserved
+	}
+	updated, err := store.UpdateSkuInventoryLevels(ctx, db.UpdateSkuInventoryLevelsParams{
+		SkuID:    record.SkuID,
+		OnHand:   record.OnHand + adjustment.Delta,
+		Reserved: record.Reserved,
+	})
+	if err != nil {
+		return db.SkuInventory{}, db.SkuInventoryMovement{}, err
+	}
+	movement, err := store.CreateSkuInventoryMovement(ctx, db.CreateSkuInventoryMovementParams{
+		SkuID:         record.SkuID,
+		MovementType:  MovementAdjustment,
+		OnHandDelta:   adjustment.Delta,
+		ReservedDelta: 0,
+		OnHandAfter:   updated.OnHand,
+		ReservedAfter: updated.Reserved,
+		ActorUserID:   adjustment.ActorUserID,
+		Reason:        &reason,
+		Note:          adjustment.Note,
+	})
+	if err != nil {
+		return db.SkuInventory{}, db.SkuInventoryMovement{}, err
+	}
+	return updated, movement, nil
+}
+
+func SetLowStockThreshold(ctx context.Context, store LedgerStore, skuID uuid.UUID, threshold int32) (db.SkuInventory, error) {
+	if threshold < 0 {
+		return db.SkuInventory{}, ErrNegativeLowThreshold
+	}
+	if _, err := lockOrCreate(ctx, store, skuID); err != nil {
+		return db.SkuInventory{}, err
+	}
+	return store.UpdateSkuInventoryThreshold(ctx, db.UpdateSkuInventoryThresholdParams{
+		SkuID:             skuID,
+		LowStockThreshold: threshold,
+	})
+}
+
+func lockOrCreate(ctx context.Context, store LedgerStore, skuID uuid.UUID) (db.SkuInventory, error) {
+	if err := store.EnsureSkuInventory(ctx, skuID); err != nil {
+		return db.SkuInventory{}, err
+	}
+	records, err := store.LockSkuInventoryBySkuIDs(ctx, []uuid.UUID{skuID})
+	if err != nil {
+		return db.SkuInventory{}, err
+	}
+	if len(records) != 1 {
+		return db.SkuInventory{}, fmt.Errorf("inventory record for sku %s not found", skuID)
+	}
+	return records[0], nil
+}
+
+func sortedSkuIDs(lines map[uuid.UUID]int32) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(lines))
+	for id := range lines {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b uuid.UUID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	return ids
+}
+
+func optionalText(value string) *string {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
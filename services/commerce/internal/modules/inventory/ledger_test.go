@@ -0,0 +1,199 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+type fakeLedgerStore struct {
+	records      map[uuid.UUID]db.SkuInventory
+	reservations []db.SkuInventoryReservation
+	movements    []db.SkuInventoryMovement
+}
+
+func newFakeLedgerStore(records ...db.SkuInventory) *fakeLedgerStore {
+	store := &fakeLedgerStore{records: map[uuid.UUID]db.SkuInventory{}}
+	for _, record := range records {
+		store.records[record.SkuID] = record
+	}
+	return store
+}
+
+func (s *fakeLedgerStore) EnsureSkuInventory(_ context.Context, skuID uuid.UUID) error {
+	if _, ok := s.records[skuID]; !ok {
+		s.records[skuID] = db.SkuInventory{SkuID: skuID}
+	}
+	return nil
+}
+
+func (s *fakeLedgerStore) LockSkuInventoryBySkuIDs(_ context.Context, skuIDs []uuid.UUID) ([]db.SkuInventory, error) {
+	items := make([]db.SkuInventory, 0, len(skuIDs))
+	for _, id := range skuIDs {
+		if record, ok := s.records[id]; ok {
+			items = append(items, record)
+		}
+	}
+	return items, nil
+}
+
+func (s *fakeLedgerStore) UpdateSkuInventoryLevels(_ context.Context, arg db.UpdateSkuInventoryLevelsParams) (db.SkuInventory, error) {
+	record := s.records[arg.SkuID]
+	if arg.OnHand < 0 || arg.Reserved < 0 || arg.Reserved > arg.OnHand {
+		return db.SkuInventory{}, errors.New("check constraint violated")
+	}
+	record.OnHand = arg.OnHand
+	record.Reserved = arg.Reserved
+	s.records[arg.SkuID] = record
+	return record, nil
+}
+
+func (s *fakeLedgerStore) UpdateSkuInventoryThreshold(_ context.Context, arg db.UpdateSkuInventoryThresholdParams) (db.SkuInventory, error) {
+	record := s.records[arg.SkuID]
+	record.LowStockThreshold = arg.LowStockThreshold
+	s.records[arg.SkuID] = record
+	return record, nil
+}
+
+func (s *fakeLedgerStore) CreateSkuInventoryMovement(_ context.Context, arg db.CreateSkuInventoryMovementParams) (db.SkuInventoryMovement, error) {
+	movement := db.SkuInventoryMovement{
+		ID:            uuid.New(),
+		SkuID:         arg.SkuID,
+		MovementType:  arg.MovementType,
+		OnHandDelta:   arg.OnHandDelta,
+		ReservedDelta: arg.ReservedDelta,
+		OnHandAfter:   arg.OnHandAfter,
+		ReservedAfter: arg.ReservedAfter,
+		OrderID:       arg.OrderID,
+		ActorUserID:   arg.ActorUserID,
+		Reason:        arg.Reason,
+		Note:          arg.Note,
+	}
+	s.movements = append(s.movements, movement)
+	return movement, nil
+}
+
+func (s *fakeLedgerStore) CreateSkuInventoryReservation(_ context.Context, arg db.CreateSkuInventoryReservationParams) (db.SkuInventoryReservation, error) {
+	reservation := db.SkuInventoryReservation{ID: uuid.New(), OrderID: arg.OrderID, SkuID: arg.SkuID, Qty: arg.Qty, Status: ReservationActive}
+	s.reservations = append(s.reservations, reservation)
+	return reservation, nil
+}
+
+func (s *fakeLedgerStore) ListActiveSkuInventoryReservationsByOrder(_ context.Context, orderID uuid.UUID) ([]db.SkuInventoryReservation, error) {
+	items := make([]db.SkuInventoryReservation, 0)
+	for _, reservation := range s.reservations {
+		if reservation.OrderID == orderID && reservation.Status == ReservationActive {
+			items = append(items, reservation)
+		}
+	}
+	return items, nil
+}
+
+func (s *fakeLedgerStore) UpdateSkuInventoryReservationStatus(_ context.Context, arg db.UpdateSkuInventoryReservationStatusParams) error {
+	for i := range s.reservations {
+		if s.reservations[i].ID == arg.ID {
+			s.reservations[i].Status = arg.Status
+		}
+	}
+	return nil
+}
+
+func TestReserveRejectsInsufficientStock(t *testing.T) {
+	tracked := uuid.New()
+	store := newFakeLedgerStore(db.SkuInventory{SkuID: tracked, OnHand: 5, Reserved: 3})
+
+	err := Reserve(context.Background(), store, uuid.New(), map[uuid.UUID]int32{tracked: 3})
+	var stockErr InsufficientStockError
+	if !errors.As(err, &stockErr) {
+		t.Fatalf("expected insufficient stock error, got %v", err)
+	}
+	if stockErr.SkuID != tracked || stockErr.Requested != 3 || stockErr.Available != 2 {
+		t.Fatalf("unexpected error details: %#v", stockErr)
+	}
+	if len(store.movements) != 0 || store.records[tracked].Reserved != 3 {
+		t.Fatalf("expected no side effects, got movements=%d record=%#v", len(store.movements), store.records[tracked])
+	}
+}
+
+func TestReserveSkipsUntrackedSkus(t *testing.T) {
+	tracked, untracked := uuid.New(), uuid.New()
+	store := newFakeLedgerStore(db.SkuInventory{SkuID: tracked, OnHand: 10})
+	orderID := uuid.New()
+
+	if err := Reserve(context.Background(), store, orderID, map[uuid.UUID]int32{tracked: 4, untracked: 1000}); err != nil {
+		t.Fatalf("reserve: %v", err)
+	}
+	if got := store.records[tracked]; got.Reserved != 4 || got.OnHand != 10 {
+		t.Fatalf("unexpected tracked record: %#v", got)
+	}
+	if len(store.reservations) != 1 || store.reservations[0].SkuID != tracked {
+		t.Fatalf("expected a single reservation for the tracked sku, got %#v", store.reservations)
+	}
+	if len(store.movements) != 1 || store.movements[0].MovementType != MovementReserve || store.movements[0].ReservedAfter != 4 {
+		t.Fatalf("unexpected movements: %#v", store.movements)
+	}
+}
+
+func TestReleaseAndConsumeSettleReservations(t *testing.T) {
+	skuID := uuid.New()
+	actor := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+
+	released := newFakeLedgerStore(db.SkuInventory{SkuID: skuID, OnHand: 10})
+	orderID := uuid.New()
+	if err := Reserve(context.Background(), released, orderID, map[uuid.UUID]int32{skuID: 6}); err != nil {
+		t.Fatalf("reserve: %v", err)
+	}
+	if err := Release(context.Background(), released, orderID, actor, "CUSTOMER_CANCELLED"); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	if got := released.records[skuID]; got.OnHand != 10 || got.Reserved != 0 {
+		t.Fatalf("unexpected record after release: %#v", got)
+	}
+	if err := Release(context.Background(), released, orderID, actor, "CUSTOMER_CANCELLED"); err != nil {
+		t.Fatalf("second release: %v", err)
+	}
+	if len(released.movements) != 2 {
+		t.Fatalf("expected release to be idempotent, got %d movements", len(released.movements))
+	}
+
+	consumed := newFakeLedgerStore(db.SkuInventory{SkuID: skuID, OnHand: 10})
+	if err := Reserve(context.Background(), consumed, orderID, map[uuid.UUID]int32{skuID: 6}); err != nil {
+		t.Fatalf("reserve: %v", err)
+	}
+	if err := Consume(context.Background(), consumed, orderID, actor); err != nil {
+		t.Fatalf("consume: %v", err)
+	}
+	if got := consumed.records[skuID]; got.OnHand != 4 || got.Reserved != 0 {
+		t.Fatalf("unexpected record after consume: %#v", got)
+	}
+	last := consumed.movements[len(consumed.movements)-1]
+	if last.MovementType != MovementConsume || last.OnHandDelta != -6 || last.ReservedDelta != -6 {
+		t.Fatalf("unexpected consume movement: %#v", last)
+	}
+}
+
+func TestAdjustValidatesResultingLevels(t *testing.T) {
+	skuID := uuid.New()
+	store := newFakeLedgerStore(db.SkuInventory{SkuID: skuID, OnHand: 5, Reserved: 4})
+
+	if _, _, err := Adjust(context.Background(), store, Adjustment{SkuID: skuID, Delta: -2, Reason: "damage"}); !errors.Is(err, ErrOnHandBelowReserved) {
+		t.Fatalf("expected below reserved error, got %v", err)
+	}
+	if _, _, err := Adjust(context.Background(), store, Adjustment{SkuID: skuID, Delta: 1, Reason: "gift"}); !errors.Is(err, ErrInvalidReason) {
+		t.Fatalf("expected invalid reason error, got %v", err)
+	}
+
+	fresh := uuid.New()
+	record, movement, err := Adjust(context.Background(), store, Adjustment{SkuID: fresh, Delta: 12, Reason: "restock"})
+	if err != nil {
+		t.Fatalf("adjust: %v", err)
+	}
+	if record.OnHand != 12 || movement.Reason == nil || *movement.Reason != "RESTOCK" || movement.OnHandAfter != 12 {
+		t.Fatalf("unexpected adjustment result: %#v %#v", record, movement)
+	}
+}
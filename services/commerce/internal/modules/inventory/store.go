@@ -0,0 +1,27 @@
+package inventory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+type Store interface {
+	GetSkuInventory(ctx context.Context, skuID uuid.UUID) (db.SkuInventory, error)
+	ListSkuInventoryBySkuIDs(ctx context.Context, skuIds []uuid.UUID) ([]db.SkuInventory, error)
+	ListSkuInventoryMovements(ctx context.Context, arg db.ListSkuInventoryMovementsParams) ([]db.SkuInventoryMovement, error)
+	ListLowStockSkuInventory(ctx context.Context, arg db.ListLowStockSkuInventoryParams) ([]db.ListLowStockSkuInventoryRow, error)
+	CountLowStockSkuInventory(ctx context.Context) (int64, error)
+}
+
+type LedgerStore interface {
+	EnsureSkuInventory(ctx context.Context, skuID uuid.UUID) error
+	LockSkuInventoryBySkuIDs(ctx context.Context, skuIds []uuid.UUID) ([]db.SkuInventory, error)
+	UpdateSkuInventoryLevels(ctx context.Context, arg db.UpdateSkuInventoryLevelsParams) (db.SkuInventory, error)
+	UpdateSkuInventoryThreshold(ctx context.Context, arg db.UpdateSkuInventoryThresholdParams) (db.SkuInventory, error)
+	CreateSkuInventoryMovement(ctx context.Context, arg db.CreateSkuInventoryMovementParams) (db.SkuInventoryMovement, error)
+	CreateSkuInventoryReservation(ctx context.Context, arg db.CreateSkuInventoryReservationParams) (db.SkuInventoryReservation, error)
+	ListActiveSkuInventoryReservationsByOrder(ctx context.Context, orderID uuid.UUID) ([]db.SkuInventoryReservation, error)
+	UpdateSkuInventoryReservationStatus(ctx context.Context, arg db.UpdateSkuInventoryReservationStatusParams) error
+}
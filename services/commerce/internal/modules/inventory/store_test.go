@@ -0,0 +1,21 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+func TestQueriesImplementsStore(test *testing.T) {
+	var store Store = (*db.Queries)(nil)
+	if store == nil {
+		test.Fatal("expected store interface to be non-nil")
+	}
+}
+
+func TestQueriesImplementsLedgerStore(test *testing.T) {
+	var store LedgerStore = (*db.Queries)(nil)
+	if store == nil {
+		test.Fatal("expected ledger store interface to be non-nil")
+	}
+}
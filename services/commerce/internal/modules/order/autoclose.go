@@ -0,0 +1,140 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+const (
+	autoCloseAction         = "AUTO_CLOSE"
+	autoCloseReasonCode     = "PAYMENT_TIMEOUT"
+	autoCloseIdempotencyKey = "AUTO_CLOSE"
+	defaultAutoCloseBatch   = 100
+)
+
+// PaymentCloser closes the open payment sessions of an order in the payment
+// service.
+type PaymentCloser interface {
+	ClosePendingPayments(ctx context.Context, orderID uuid.UUID, reasonCode string) error
+}
+
+// AutoCloseWorker closes orders that stay unpaid for longer than After. Open
+// payment sessions are closed first so a late provider callback cannot mark a
+// closed order paid.
+type AutoCloseWorker struct {
+	DB            *pgxpool.Pool
+	Payments      PaymentCloser
+	Outbox        *events.Outbox
+	After         time.Duration
+	CheckInterval time.Duration
+	BatchSize     int
+	Logger        *slog.Logger
+}
+
+func (w *AutoCloseWorker) Start(ctx context.Context) {
+	if w.DB == nil {
+		return
+	}
+
+	interval := w.CheckInterval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	after := w.After
+	if after <= 0 {
+		after = 24 * time.Hour
+	}
+
+	go func() {
+		w.runOnce(ctx, after)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.runOnce(ctx, after)
+			}
+		}
+	}()
+}
+
+func (w *AutoCloseWorker) runOnce(ctx context.Context, after time.Duration) {
+	transition, err := Lookup(EventAutoClosed)
+	if err != nil {
+		w.logError("auto close failed", err)
+		return
+	}
+	limit := w.BatchSize
+	if limit <= 0 {
+		limit = defaultAutoCloseBatch
+	}
+	cutoff := time.Now().UTC().Add(-after)
+	orders, err := db.New(w.DB).ListUnpaidOrdersCreatedBefore(ctx, db.ListUnpaidOrdersCreatedBeforeParams{
+		Statuses:      transition.From,
+		CreatedBefore: pgtype.Timestamptz{Time: cutoff, Valid: true},
+		Limit:         int32(limit),
+	})
+	if err != nil {
+		w.logError("auto close failed", err)
+		return
+	}
+
+	closed := 0
+	for _, order := range orders {
+		if err := w.closeOrder(ctx, order, after); err != nil {
+			if w.Logger != nil {
+				w.Logger.Warn("auto close order failed", "orderId", order.ID, "error", err)
+			}
+			continue
+		}
+		closed++
+	}
+	if closed > 0 && w.Logger != nil {
+		w.Logger.Info("auto closed unpaid orders", "count", closed, "cutoff", cutoff.Format(time.RFC3339))
+	}
+}
+
+func (w *AutoCloseWorker) closeOrder(ctx context.Context, order db.Order, after time.Duration) error {
+	transition, err := Resolve(order.Status, order.PaymentStatus, EventAutoClosed)
+	if err != nil {
+		return err
+	}
+	if transition.HasEffect(EffectClosePayments) && w.Payments != nil && order.LatestPaymentID.Valid {
+		if err := w.Payments.ClosePendingPayments(ctx, order.ID, autoCloseReasonCode); err != nil {
+			return err
+		}
+	}
+	return shareddb.WithTx(ctx, w.DB, func(tx pgx.Tx) error {
+		_, err := Close(ctx, tx, CloseRequest{
+			OrderID:        order.ID,
+			Event:          EventAutoClosed,
+			ActorUserID:    SystemActorID,
+			IdempotencyKey: autoCloseIdempotencyKey,
+			Action:         autoCloseAction,
+			ReasonCode:     autoCloseReasonCode,
+			Note:           fmt.Sprintf("closed automatically after %s without payment", after),
+			Outbox:         w.Outbox,
+		})
+		return err
+	})
+}
+
+func (w *AutoCloseWorker) logError(message string, err error) {
+	if w.Logger != nil {
+		w.Logger.Error(message, "error", err)
+	}
+}
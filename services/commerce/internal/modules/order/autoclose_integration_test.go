@@ -0,0 +1,140 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+type recordingPaymentCloser struct {
+	orders []uuid.UUID
+}
+
+func (c *recordingPaymentCloser) ClosePendingPayments(_ context.Context, orderID uuid.UUID, _ string) error {
+	c.orders = append(c.orders, orderID)
+	return nil
+}
+
+func TestAutoCloseWorkerClosesStaleUnpaidOrders(t *testing.T) {
+	pool := openOrderTestPool(t)
+	resetOrderTables(t, pool)
+	queries := db.New(pool)
+	ctx := context.Background()
+
+	stale := createTestOrder(t, queries, "PAY_PENDING", "PAY_PENDING")
+	paymentID := uuid.New()
+	if _, err := queries.UpdateOrderPaymentSummary(ctx, db.UpdateOrderPaymentSummaryParams{
+		ID: stale.ID, Status: "PAY_PENDING", PaymentStatus: "PAY_PENDING", LatestPaymentID: pgtype.UUID{Bytes: paymentID, Valid: true},
+	}); err != nil {
+		t.Fatalf("set payment summary: %v", err)
+	}
+	fresh := createTestOrder(t, queries, "SUBMITTED", "UNPAID")
+	paid := createTestOrder(t, queries, "PAID", "PAID")
+	if _, err := pool.Exec(ctx, `UPDATE orders SET created_at = now() - interval '2 hours' WHERE id = ANY($1::uuid[])`, []uuid.UUID{stale.ID, paid.ID}); err != nil {
+		t.Fatalf("backdate orders: %v", err)
+	}
+
+	payments := &recordingPaymentCloser{}
+	worker := &AutoCloseWorker{DB: pool, Payments: payments}
+	worker.runOnce(ctx, time.Hour)
+
+	got, err := queries.GetOrder(ctx, stale.ID)
+	if err != nil {
+		t.Fatalf("get stale order: %v", err)
+	}
+	if got.Status != StatusClosed || got.PaymentStatus != PaymentStatusPayFailed {
+		t.Fatalf("expected stale order to be closed, got %s/%s", got.Status, got.PaymentStatus)
+	}
+	if len(payments.orders) != 1 || payments.orders[0] != stale.ID {
+		t.Fatalf("expected the payment session of the stale order to be closed, got %v", payments.orders)
+	}
+	events, err := queries.ListOrderAdminEvents(ctx, stale.ID)
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	if len(events) != 1 || events[0].ActorUserID != SystemActorID || events[0].ReasonCode == nil || *events[0].ReasonCode != "PAYMENT_TIMEOUT" {
+		t.Fatalf("unexpected auto close events: %#v", events)
+	}
+
+	for _, id := range []uuid.UUID{fresh.ID, paid.ID} {
+		untouched, err := queries.GetOrder(ctx, id)
+		if err != nil {
+			t.Fatalf("get order: %v", err)
+		}
+		if untouched.Status == StatusClosed {
+			t.Fatalf("expected order %s to stay open", id)
+		}
+	}
+
+	worker.runOnce(ctx, time.Hour)
+	if events, _ := queries.ListOrderAdminEvents(ctx, stale.ID); len(events) != 1 {
+		t.Fatalf("expected a single auto close event after a second run, got %d", len(events))
+	}
+}
+
+func createTestOrder(t *testing.T, queries *db.Queries, status, paymentStatus string) db.Order {
+	t.Helper()
+	address, _ := json.Marshal(map[string]string{"receiverName": "Test"})
+	order, err := queries.CreateOrder(context.Background(), db.CreateOrderParams{
+		Status:        status,
+		CustomerID:    uuid.New(),
+		Address:       address,
+		PaymentStatus: paymentStatus,
+	})
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	return order
+}
+
+func openOrderTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	dsn := os.Getenv("COMMERCE_DB_DSN")
+	if dsn == "" {
+		t.Skip("COMMERCE_DB_DSN is not set; skipping integration test")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("connect to database: %v", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		t.Fatalf("ping database: %v", err)
+	}
+
+	migrationsDir := filepath.Join("..", "..", "..", "migrations")
+	if err := db.ApplyMigrations(ctx, pool, migrationsDir); err != nil {
+		pool.Close()
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	t.Cleanup(func() {
+		pool.Close()
+	})
+	return pool
+}
+
+func resetOrderTables(t *testing.T, pool *pgxpool.Pool) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := pool.Exec(ctx, `TRUNCATE order_admin_events, order_items, orders RESTART IDENTITY CASCADE`); err != nil {
+		t.Fatalf("truncate tables: %v", err)
+	}
+}
@@ -5,8 +5,12 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
 
+	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	"github.com/teamdsb/tmo/packages/go-shared/events"
 	"github.com/teamdsb/tmo/services/commerce/internal/db"
 )
 
@@ -14,15 +18,20 @@ type AutoDeliveryStore interface {
 	AutoDeliverShippedOrders(ctx context.Context, arg db.AutoDeliverShippedOrdersParams) ([]db.Order, error)
 }
 
+// AutoDeliveryWorker marks orders delivered once they have been shipped for
+// longer than After. With DB set the update runs in a transaction that also
+// publishes order.status_changed through Outbox.
 type AutoDeliveryWorker struct {
 	Store         AutoDeliveryStore
+	DB            *pgxpool.Pool
+	Outbox        *events.Outbox
 	After         time.Duration
 	CheckInterval time.Duration
 	Logger        *slog.Logger
 }
 
 func (w *AutoDeliveryWorker) Start(ctx context.Context) {
-	if w.Store == nil {
+	if w.Store == nil && w.DB == nil {
 		return
 	}
 
@@ -52,15 +61,42 @@ func (w *AutoDeliveryWorker) Start(ctx context.Context) {
 }
 
 func (w *AutoDeliveryWorker) runOnce(ctx context.Context, after time.Duration) {
+	transition, err := Lookup(EventAutoDelivered)
+	if err != nil {
+		if w.Logger != nil {
+			w.Logger.Error("auto delivery failed", "error", err)
+		}
+		return
+	}
 	cutoff := time.Now().UTC().Add(-after)
-	orders, err := w.Store.AutoDeliverShippedOrders(ctx, db.AutoDeliverShippedOrdersParams{
-		Status:   "SHIPPED",
-		Status_2: "DELIVERED",
+	params := db.AutoDeliverShippedOrdersParams{
+		Status:   transition.From[0],
+		Status_2: transition.To,
 		ShippedAt: pgtype.Timestamptz{
 			Time:  cutoff,
 			Valid: true,
 		},
-	})
+	}
+	var orders []db.Order
+	if w.DB == nil {
+		orders, err = w.Store.AutoDeliverShippedOrders(ctx, params)
+	} else {
+		err = shareddb.WithTx(ctx, w.DB, func(tx pgx.Tx) error {
+			var err error
+			orders, err = db.New(tx).AutoDeliverShippedOrders(ctx, params)
+			if err != nil {
+				return err
+			}
+			for _, order := range orders {
+				before := order
+				before.Status = transition.From[0]
+				if err := PublishStatusChanged(ctx, w.Outbox, tx, before, order); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
 	if err != nil {
 		if w.Logger != nil {
 			w.Logger.Error("auto delivery failed", "error", err)
@@ -0,0 +1,126 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/inventory"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/receivable"
+)
+
+// CloseRequest describes a terminal transition of an unpaid order.
+type CloseRequest struct {
+	OrderID        uuid.UUID
+	Event          Event
+	ActorUserID    uuid.UUID
+	IdempotencyKey string
+	Action         string
+	ReasonCode     string
+	Note           string
+	// Outbox receives the order.closed event; nil publishes nothing.
+	Outbox *events.Outbox
+}
+
+// Close moves an unpaid order into the terminal status of req.Event, releases
+// its stock reservations and records an order event. Replaying the same
+// idempotency key returns the order unchanged.
+func Close(ctx context.Context, tx pgx.Tx, req CloseRequest) (db.Order, error) {
+	q := db.New(tx)
+	current, err := q.GetOrderForUpdate(ctx, req.OrderID)
+	if err != nil {
+		return db.Order{}, err
+	}
+	if _, err := q.GetOrderAdminEventByIdempotencyKey(ctx, db.GetOrderAdminEventByIdempotencyKeyParams{OrderID: current.ID, IdempotencyKey: req.IdempotencyKey}); err == nil {
+		return current, nil
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return db.Order{}, err
+	}
+	transition, err := Resolve(current.Status, current.PaymentStatus, req.Event)
+	if err != nil {
+		return db.Order{}, err
+	}
+	updated, err := q.UpdateOrderPaymentSummary(ctx, db.UpdateOrderPaymentSummaryParams{
+		ID:              current.ID,
+		Status:          transition.To,
+		PaymentStatus:   ClosedPaymentStatus(current.PaymentStatus),
+		LatestPaymentID: current.LatestPaymentID,
+		PaymentChannel:  current.PaymentChannel,
+		PaidAt:          current.PaidAt,
+	})
+	if err != nil {
+		return db.Order{}, err
+	}
+	if err := ApplyStockEffects(ctx, q, transition, current.ID, req.ActorUserID, req.ReasonCode); err != nil {
+		return db.Order{}, err
+	}
+	if err := ApplyReceivableEffects(ctx, q, transition, current.ID, req.ActorUserID); err != nil {
+		return db.Order{}, err
+	}
+	reasonCode := req.ReasonCode
+	if _, err := q.CreateOrderAdminEvent(ctx, db.CreateOrderAdminEventParams{
+		OrderID: current.ID, IdempotencyKey: req.IdempotencyKey, ActorUserID: req.ActorUserID, Action: req.Action, Note: req.Note,
+		PreviousStatus: current.Status, NewStatus: updated.Status, PreviousPaymentStatus: current.PaymentStatus, NewPaymentStatus: updated.PaymentStatus,
+		PreviousOwnerSalesUserID: current.OwnerSalesUserID, NewOwnerSalesUserID: current.OwnerSalesUserID, ReasonCode: &reasonCode,
+	}); err != nil {
+		return db.Order{}, err
+	}
+	if err := req.Outbox.Publish(ctx, tx, events.TypeOrderClosed, updated.ID.String(), events.OrderClosed{
+		OrderID:    updated.ID.String(),
+		Status:     updated.Status,
+		ReasonCode: req.ReasonCode,
+		ClosedAt:   updated.UpdatedAt.Time.UTC(),
+	}); err != nil {
+		return db.Order{}, err
+	}
+	return updated, nil
+}
+
+// ClosedPaymentStatus is the payment status an order keeps once it is closed;
+// a pending payment can no longer succeed.
+func ClosedPaymentStatus(current string) string {
+	if strings.EqualFold(current, PaymentStatusPayPending) {
+		return PaymentStatusPayFailed
+	}
+	return current
+}
+
+// ApplyStockEffects runs the inventory side effects declared by transition
+// inside the caller's transaction.
+func ApplyStockEffects(ctx context.Context, store inventory.LedgerStore, transition Transition, orderID, actorUserID uuid.UUID, reason string) error {
+	actor := pgtype.UUID{Bytes: actorUserID, Valid: actorUserID != uuid.Nil}
+	if transition.HasEffect(EffectReleaseStock) {
+		if err := inventory.Release(ctx, store, orderID, actor, reason); err != nil {
+			return err
+		}
+	}
+	if transition.HasEffect(EffectConsumeStock) {
+		if err := inventory.Consume(ctx, store, orderID, actor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyReceivableEffects settles or voids the receivable of a monthly-terms
+// order inside the caller's transaction. Orders without one are unaffected.
+func ApplyReceivableEffects(ctx context.Context, store receivable.LedgerStore, transition Transition, orderID, actorUserID uuid.UUID) error {
+	if transition.HasEffect(EffectSettleReceivable) {
+		actor := pgtype.UUID{Bytes: actorUserID, Valid: actorUserID != uuid.Nil}
+		if err := receivable.Settle(ctx, store, orderID, actor); err != nil {
+			return err
+		}
+	}
+	if transition.HasEffect(EffectVoidReceivable) {
+		if err := receivable.Void(ctx, store, orderID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
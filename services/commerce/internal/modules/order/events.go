@@ -0,0 +1,26 @@
+package order
+
+import (
+	"context"
+
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+// PublishStatusChanged publishes order.status_changed in tx when after differs
+// from before in status or payment status. Closing an order publishes
+// order.closed instead, see Close.
+func PublishStatusChanged(ctx context.Context, outbox *events.Outbox, tx events.DBTX, before, after db.Order) error {
+	if before.Status == after.Status && before.PaymentStatus == after.PaymentStatus {
+		return nil
+	}
+	return outbox.Publish(ctx, tx, events.TypeOrderStatusChanged, after.ID.String(), events.OrderStatusChanged{
+		OrderID:               after.ID.String(),
+		CustomerID:            after.CustomerID.String(),
+		Status:                after.Status,
+		PreviousStatus:        before.Status,
+		PaymentStatus:         after.PaymentStatus,
+		PreviousPaymentStatus: before.PaymentStatus,
+		ChangedAt:             after.UpdatedAt.Time.UTC(),
+	})
+}
@@ -0,0 +1,209 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+var (
+	ErrNotShippable        = errors.New("order must be confirmed and paid before shipping")
+	ErrUnknownOrderItem    = errors.New("order item does not belong to the order")
+	ErrInvalidShipmentQty  = errors.New("shipment qty must be positive")
+	ErrShipmentQtyExceeded = errors.New("shipped qty exceeds ordered qty")
+	ErrNothingToShip       = errors.New("every item of the order is already shipped")
+	ErrWaybillShipped      = errors.New("waybill already carries items of this order")
+)
+
+// ShipmentLine is the quantity of one order item packed in a parcel.
+type ShipmentLine struct {
+	OrderItemID uuid.UUID
+	Qty         int32
+}
+
+// ShipmentRequest describes one parcel leaving the warehouse.
+type ShipmentRequest struct {
+	OrderID     uuid.UUID
+	WaybillNo   string
+	Carrier     *string
+	ShippedAt   time.Time
+	ActorUserID uuid.UUID
+	// Lines lists what the parcel carries; empty ships every quantity not yet
+	// shipped.
+	Lines []ShipmentLine
+}
+
+// ShipmentResult is the outcome of Ship.
+type ShipmentResult struct {
+	Order    db.Order
+	Shipment db.OrderTrackingShipment
+	Lines    []db.OrderShipmentLine
+}
+
+// PlanShipment validates lines against the ordered quantities and what
+// earlier parcels already carried, keyed by order item. Repeated items are
+// merged. It returns the lines to record and whether the order is fully
+// shipped once they are.
+func PlanShipment(items []db.OrderItem, shipped map[uuid.UUID]int32, lines []ShipmentLine) ([]ShipmentLine, bool, error) {
+	remaining := make(map[uuid.UUID]int32, len(items))
+	for _, item := range items {
+		remaining[item.ID] = item.Qty - shipped[item.ID]
+	}
+
+	var planned []ShipmentLine
+	if len(lines) == 0 {
+		for _, item := range items {
+			if qty := remaining[item.ID]; qty > 0 {
+				planned = append(planned, ShipmentLine{OrderItemID: item.ID, Qty: qty})
+				remaining[item.ID] = 0
+			}
+		}
+		if len(planned) == 0 {
+			return nil, false, ErrNothingToShip
+		}
+		return planned, true, nil
+	}
+
+	index := make(map[uuid.UUID]int, len(lines))
+	for _, line := range lines {
+		left, ok := remaining[line.OrderItemID]
+		if !ok {
+			return nil, false, fmt.Errorf("%w: %s", ErrUnknownOrderItem, line.OrderItemID)
+		}
+		if line.Qty <= 0 {
+			return nil, false, ErrInvalidShipmentQty
+		}
+		if line.Qty > left {
+			return nil, false, fmt.Errorf("%w: order item %s has %d left to ship", ErrShipmentQtyExceeded, line.OrderItemID, max(left, 0))
+		}
+		remaining[line.OrderItemID] = left - line.Qty
+		if i, ok := index[line.OrderItemID]; ok {
+			planned[i].Qty += line.Qty
+			continue
+		}
+		index[line.OrderItemID] = len(planned)
+		planned = append(planned, line)
+	}
+
+	complete := true
+	for _, left := range remaining {
+		if left > 0 {
+			complete = false
+			break
+		}
+	}
+	return planned, complete, nil
+}
+
+// Ship records a parcel of a confirmed order with its shipment lines and
+// moves the order to PARTIALLY_SHIPPED, or to SHIPPED once every ordered
+// quantity has left. Stock reservations are consumed only with the last
+// parcel; until then they keep the unshipped quantities unavailable. It must
+// run inside tx and publishes shipment.added and order.status_changed.
+func Ship(ctx context.Context, tx pgx.Tx, outbox *events.Outbox, req ShipmentRequest) (ShipmentResult, error) {
+	q := db.New(tx)
+	current, err := q.GetOrderForUpdate(ctx, req.OrderID)
+	if err != nil {
+		return ShipmentResult{}, err
+	}
+	if _, err := Resolve(current.Status, current.PaymentStatus, EventShipped); err != nil {
+		return ShipmentResult{}, ErrNotShippable
+	}
+
+	items, err := q.ListOrderItems(ctx, current.ID)
+	if err != nil {
+		return ShipmentResult{}, err
+	}
+	recorded, err := q.ListShipmentLinesByOrder(ctx, current.ID)
+	if err != nil {
+		return ShipmentResult{}, err
+	}
+	shipped := make(map[uuid.UUID]int32, len(items))
+	for _, line := range recorded {
+		if line.WaybillNo == req.WaybillNo {
+			return ShipmentResult{}, ErrWaybillShipped
+		}
+		shipped[line.OrderItemID] += line.Qty
+	}
+	lines, complete, err := PlanShipment(items, shipped, req.Lines)
+	if err != nil {
+		return ShipmentResult{}, err
+	}
+	event := EventPartiallyShipped
+	if complete {
+		event = EventShipped
+	}
+	transition, err := Resolve(current.Status, current.PaymentStatus, event)
+	if err != nil {
+		return ShipmentResult{}, ErrNotShippable
+	}
+
+	shipment, err := q.UpsertTrackingShipment(ctx, db.UpsertTrackingShipmentParams{
+		OrderID:   current.ID,
+		WaybillNo: req.WaybillNo,
+		Carrier:   req.Carrier,
+		ShippedAt: pgtype.Timestamptz{Time: req.ShippedAt, Valid: true},
+	})
+	if err != nil {
+		return ShipmentResult{}, err
+	}
+	skuByItem := make(map[uuid.UUID]uuid.UUID, len(items))
+	for _, item := range items {
+		skuByItem[item.ID] = item.SkuID
+	}
+	result := ShipmentResult{Shipment: shipment, Lines: make([]db.OrderShipmentLine, 0, len(lines))}
+	payload := ShipmentAddedEvent(shipment)
+	for _, line := range lines {
+		created, err := q.CreateShipmentLine(ctx, db.CreateShipmentLineParams{
+			ShipmentID:  shipment.ID,
+			OrderItemID: line.OrderItemID,
+			Qty:         line.Qty,
+		})
+		if err != nil {
+			return ShipmentResult{}, err
+		}
+		result.Lines = append(result.Lines, created)
+		payload.Items = append(payload.Items, events.ShipmentItem{
+			OrderItemID: created.OrderItemID.String(),
+			SkuID:       skuByItem[created.OrderItemID].String(),
+			Qty:         created.Qty,
+		})
+	}
+	if err := outbox.Publish(ctx, tx, events.TypeShipmentAdded, current.ID.String(), payload); err != nil {
+		return ShipmentResult{}, err
+	}
+	if err := ApplyStockEffects(ctx, q, transition, current.ID, req.ActorUserID, ""); err != nil {
+		return ShipmentResult{}, err
+	}
+	result.Order, err = q.UpdateOrderStatus(ctx, db.UpdateOrderStatusParams{
+		ID:     current.ID,
+		Status: transition.To,
+	})
+	if err != nil {
+		return ShipmentResult{}, err
+	}
+	if err := PublishStatusChanged(ctx, outbox, tx, current, result.Order); err != nil {
+		return ShipmentResult{}, err
+	}
+	return result, nil
+}
+
+// ShipmentAddedEvent is the shipment.added payload of shipment without its
+// items.
+func ShipmentAddedEvent(shipment db.OrderTrackingShipment) events.ShipmentAdded {
+	return events.ShipmentAdded{
+		ShipmentID: shipment.ID.String(),
+		OrderID:    shipment.OrderID.String(),
+		WaybillNo:  shipment.WaybillNo,
+		Carrier:    shipment.Carrier,
+		ShippedAt:  shipment.ShippedAt.Time.UTC(),
+	}
+}
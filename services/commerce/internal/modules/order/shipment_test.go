@@ -0,0 +1,100 @@
+package order
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+func TestPlanShipment(t *testing.T) {
+	single := db.OrderItem{ID: uuid.New(), Qty: 1}
+	bulk := db.OrderItem{ID: uuid.New(), Qty: 3}
+	items := []db.OrderItem{single, bulk}
+
+	tests := []struct {
+		name         string
+		shipped      map[uuid.UUID]int32
+		lines        []ShipmentLine
+		wantLines    []ShipmentLine
+		wantComplete bool
+		wantErr      error
+	}{
+		{
+			name:         "no lines ships everything",
+			wantLines:    []ShipmentLine{{OrderItemID: single.ID, Qty: 1}, {OrderItemID: bulk.ID, Qty: 3}},
+			wantComplete: true,
+		},
+		{
+			name:         "no lines ships only what is left",
+			shipped:      map[uuid.UUID]int32{single.ID: 1, bulk.ID: 1},
+			wantLines:    []ShipmentLine{{OrderItemID: bulk.ID, Qty: 2}},
+			wantComplete: true,
+		},
+		{
+			name:      "partial parcel",
+			lines:     []ShipmentLine{{OrderItemID: bulk.ID, Qty: 2}},
+			wantLines: []ShipmentLine{{OrderItemID: bulk.ID, Qty: 2}},
+		},
+		{
+			name:         "repeated items are merged",
+			shipped:      map[uuid.UUID]int32{single.ID: 1},
+			lines:        []ShipmentLine{{OrderItemID: bulk.ID, Qty: 1}, {OrderItemID: bulk.ID, Qty: 2}},
+			wantLines:    []ShipmentLine{{OrderItemID: bulk.ID, Qty: 3}},
+			wantComplete: true,
+		},
+		{
+			name:    "more than ordered",
+			shipped: map[uuid.UUID]int32{bulk.ID: 2},
+			lines:   []ShipmentLine{{OrderItemID: bulk.ID, Qty: 2}},
+			wantErr: ErrShipmentQtyExceeded,
+		},
+		{
+			name:    "repeated items add up",
+			lines:   []ShipmentLine{{OrderItemID: single.ID, Qty: 1}, {OrderItemID: single.ID, Qty: 1}},
+			wantErr: ErrShipmentQtyExceeded,
+		},
+		{
+			name:    "foreign item",
+			lines:   []ShipmentLine{{OrderItemID: uuid.New(), Qty: 1}},
+			wantErr: ErrUnknownOrderItem,
+		},
+		{
+			name:    "zero qty",
+			lines:   []ShipmentLine{{OrderItemID: bulk.ID, Qty: 0}},
+			wantErr: ErrInvalidShipmentQty,
+		},
+		{
+			name:    "nothing left",
+			shipped: map[uuid.UUID]int32{single.ID: 1, bulk.ID: 3},
+			wantErr: ErrNothingToShip,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lines, complete, err := PlanShipment(items, test.shipped, test.lines)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("got %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("plan: %v", err)
+			}
+			if complete != test.wantComplete {
+				t.Fatalf("got complete %v, want %v", complete, test.wantComplete)
+			}
+			if len(lines) != len(test.wantLines) {
+				t.Fatalf("got lines %+v, want %+v", lines, test.wantLines)
+			}
+			for i := range lines {
+				if lines[i] != test.wantLines[i] {
+					t.Fatalf("got lines %+v, want %+v", lines, test.wantLines)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+)
+
+const (
+	StatusSubmitted        = "SUBMITTED"
+	StatusPayPending       = "PAY_PENDING"
+	StatusPaid             = "PAID"
+	StatusPayFailed        = "PAY_FAILED"
+	StatusConfirmed        = "CONFIRMED"
+	StatusPartiallyShipped = "PARTIALLY_SHIPPED"
+	StatusShipped          = "SHIPPED"
+	StatusDelivered        = "DELIVERED"
+	StatusCancelled        = "CANCELLED"
+	StatusClosed           = "CLOSED"
+
+	PaymentStatusUnpaid     = "UNPAID"
+	PaymentStatusPayPending = "PAY_PENDING"
+	PaymentStatusPaid       = "PAID"
+	PaymentStatusPayFailed  = "PAY_FAILED"
+
+	PaymentStatusPartiallyRefunded = "PARTIALLY_REFUNDED"
+	PaymentStatusRefunded          = "REFUNDED"
+
+	// RoleSystem triggers transitions that are not caused by a signed-in user,
+	// such as payment callbacks and background workers.
+	RoleSystem = "SYSTEM"
+)
+
+// SystemActorID is recorded as the actor of order events written by
+// background workers.
+var SystemActorID = uuid.Nil
+
+// Event names a single order transition trigger.
+type Event string
+
+const (
+	EventPaymentPending          Event = "PAYMENT_PENDING"
+	EventPaymentSucceeded        Event = "PAYMENT_SUCCEEDED"
+	EventPaymentFailed           Event = "PAYMENT_FAILED"
+	EventOfflinePaymentConfirmed Event = "OFFLINE_PAYMENT_CONFIRMED"
+	EventAssigned                Event = "ASSIGNED"
+	EventPartiallyShipped        Event = "PARTIALLY_SHIPPED"
+	EventShipped                 Event = "SHIPPED"
+	EventReceiptConfirmed        Event = "RECEIPT_CONFIRMED"
+	EventDeliveryConfirmed       Event = "DELIVERY_CONFIRMED"
+	EventAutoDelivered           Event = "AUTO_DELIVERED"
+	EventCarrierDelivered        Event = "CARRIER_DELIVERED"
+	EventCustomerCancelled       Event = "CUSTOMER_CANCELLED"
+	EventAdminClosed             Event = "ADMIN_CLOSED"
+	EventAutoClosed              Event = "AUTO_CLOSED"
+)
+
+// Effect is a side effect the caller must run together with a transition.
+type Effect string
+
+const (
+	EffectClosePayments Effect = "CLOSE_PAYMENTS"
+	EffectReleaseStock  Effect = "RELEASE_STOCK"
+	EffectConsumeStock  Effect = "CONSUME_STOCK"
+
+	EffectSettleReceivable Effect = "SETTLE_RECEIVABLE"
+	EffectVoidReceivable   Effect = "VOID_RECEIVABLE"
+)
+
+// PaymentGuard constrains the payment status an order must have for a
+// transition to apply.
+type PaymentGuard string
+
+const (
+	PaymentAny      PaymentGuard = "ANY"
+	PaymentRequired PaymentGuard = "PAID"
+	PaymentAbsent   PaymentGuard = "UNPAID"
+)
+
+var (
+	ErrUnknownEvent      = errors.New("unknown order event")
+	ErrInvalidTransition = errors.New("order cannot make this transition in its current state")
+	ErrPaymentRequired   = errors.New("order must be paid first")
+	ErrAlreadyPaid       = errors.New("order is already paid")
+)
+
+// Transition is one row of the order state machine. Roles lists who holds
+// Permission in the default RBAC matrix; handlers enforce Permission so the
+// matrix configured in identity decides.
+type Transition struct {
+	Event      Event
+	From       []string
+	To         string
+	Roles      []string
+	Permission string
+	Payment    PaymentGuard
+	Effects    []Effect
+}
+
+// HasEffect reports whether effect must run with the transition.
+func (t Transition) HasEffect(effect Effect) bool {
+	return slices.Contains(t.Effects, effect)
+}
+
+var (
+	customerRoles     = []string{"CUSTOMER"}
+	managerRoles      = []string{"BOSS", "MANAGER", "ADMIN"}
+	fulfillmentRoles  = []string{"CS", "MANAGER", "BOSS", "ADMIN"}
+	systemRoles       = []string{RoleSystem}
+	unpaidStatuses    = []string{StatusSubmitted, StatusPayPending, StatusPayFailed}
+	shippableStatuses = []string{StatusConfirmed, StatusPartiallyShipped}
+)
+
+var transitions = []Transition{
+	{Event: EventPaymentPending, From: unpaidStatuses, To: StatusPayPending, Roles: systemRoles, Payment: PaymentAbsent},
+	{Event: EventPaymentSucceeded, From: unpaidStatuses, To: StatusPaid, Roles: systemRoles, Payment: PaymentAbsent, Effects: []Effect{EffectSettleReceivable}},
+	{Event: EventPaymentFailed, From: unpaidStatuses, To: StatusPayFailed, Roles: systemRoles, Payment: PaymentAbsent},
+	{Event: EventOfflinePaymentConfirmed, From: unpaidStatuses, To: StatusConfirmed, Roles: managerRoles, Permission: authz.PermissionOrderManage, Payment: PaymentAbsent, Effects: []Effect{EffectSettleReceivable}},
+	{Event: EventAssigned, From: []string{StatusPaid, StatusConfirmed}, To: StatusConfirmed, Roles: managerRoles, Permission: authz.PermissionOrderManage, Payment: PaymentRequired},
+	{Event: EventPartiallyShipped, From: shippableStatuses, To: StatusPartiallyShipped, Roles: fulfillmentRoles, Permission: authz.PermissionShipmentManage, Payment: PaymentRequired},
+	{Event: EventShipped, From: shippableStatuses, To: StatusShipped, Roles: fulfillmentRoles, Permission: authz.PermissionShipmentManage, Payment: PaymentRequired, Effects: []Effect{EffectConsumeStock}},
+	{Event: EventReceiptConfirmed, From: []string{StatusShipped}, To: StatusDelivered, Roles: customerRoles, Permission: authz.PermissionOrderCreate, Payment: PaymentAny},
+	{Event: EventDeliveryConfirmed, From: []string{StatusShipped}, To: StatusDelivered, Roles: fulfillmentRoles, Permission: authz.PermissionShipmentManage, Payment: PaymentAny},
+	{Event: EventAutoDelivered, From: []string{StatusShipped}, To: StatusDelivered, Roles: systemRoles, Payment: PaymentAny},
+	{Event: EventCarrierDelivered, From: []string{StatusShipped}, To: StatusDelivered, Roles: systemRoles, Payment: PaymentAny},
+	{Event: EventCustomerCancelled, From: unpaidStatuses, To: StatusCancelled, Roles: customerRoles, Permission: authz.PermissionOrderCreate, Payment: PaymentAbsent, Effects: []Effect{EffectClosePayments, EffectReleaseStock, EffectVoidReceivable}},
+	{Event: EventAdminClosed, From: unpaidStatuses, To: StatusClosed, Roles: managerRoles, Permission: authz.PermissionOrderManage, Payment: PaymentAbsent, Effects: []Effect{EffectClosePayments, EffectReleaseStock, EffectVoidReceivable}},
+	{Event: EventAutoClosed, From: unpaidStatuses, To: StatusClosed, Roles: systemRoles, Payment: PaymentAbsent, Effects: []Effect{EffectClosePayments, EffectReleaseStock, EffectVoidReceivable}},
+}
+
+// States lists every order status in lifecycle order.
+func States() []string {
+	return []string{StatusSubmitted, StatusPayPending, StatusPaid, StatusPayFailed, StatusConfirmed, StatusPartiallyShipped, StatusShipped, StatusDelivered, StatusCancelled, StatusClosed}
+}
+
+// Transitions returns a copy of the transition table.
+func Transitions() []Transition {
+	items := make([]Transition, 0, len(transitions))
+	for _, transition := range transitions {
+		transition.From = slices.Clone(transition.From)
+		transition.Roles = slices.Clone(transition.Roles)
+		transition.Effects = slices.Clone(transition.Effects)
+		items = append(items, transition)
+	}
+	return items
+}
+
+// Lookup returns the transition declared for event.
+func Lookup(event Event) (Transition, error) {
+	for _, transition := range Transitions() {
+		if transition.Event == event {
+			return transition, nil
+		}
+	}
+	return Transition{}, fmt.Errorf("%w: %s", ErrUnknownEvent, event)
+}
+
+// Roles returns the roles allowed to trigger event.
+func Roles(event Event) []string {
+	transition, err := Lookup(event)
+	if err != nil {
+		return nil
+	}
+	return transition.Roles
+}
+
+// Permission returns the permission a caller needs to trigger event. System
+// events have none.
+func Permission(event Event) string {
+	transition, err := Lookup(event)
+	if err != nil {
+		return ""
+	}
+	return transition.Permission
+}
+
+// IsTerminal reports whether no transition leaves status.
+func IsTerminal(status string) bool {
+	status = strings.ToUpper(strings.TrimSpace(status))
+	for _, transition := range transitions {
+		if slices.Contains(transition.From, status) {
+			return false
+		}
+	}
+	return true
+}
+
+// Resolve checks that event may move an order with the given status and
+// payment status, and returns the matching transition. The payment guard is
+// checked first so callers can tell a paid order apart from a wrong state.
+func Resolve(status, paymentStatus string, event Event) (Transition, error) {
+	transition, err := Lookup(event)
+	if err != nil {
+		return Transition{}, err
+	}
+	paid := IsPaid(paymentStatus)
+	switch {
+	case transition.Payment == PaymentRequired && !paid:
+		return Transition{}, ErrPaymentRequired
+	case transition.Payment == PaymentAbsent && (paid || IsRefunded(paymentStatus)):
+		return Transition{}, ErrAlreadyPaid
+	}
+	if !slices.Contains(transition.From, strings.ToUpper(strings.TrimSpace(status))) {
+		return Transition{}, fmt.Errorf("%w: %s from %s", ErrInvalidTransition, event, status)
+	}
+	return transition, nil
+}
+
+// PaymentEvent maps a payment service status to the order event it triggers.
+func PaymentEvent(paymentStatus string) (Event, bool) {
+	switch strings.ToUpper(strings.TrimSpace(paymentStatus)) {
+	case "PAY_PENDING":
+		return EventPaymentPending, true
+	case "PAID":
+		return EventPaymentSucceeded, true
+	case "PAY_FAILED", "CANCELLED":
+		return EventPaymentFailed, true
+	default:
+		return "", false
+	}
+}
+
+// IsPaid reports whether an order with paymentStatus still holds money from
+// the customer. A partially refunded order keeps its paid guard.
+func IsPaid(paymentStatus string) bool {
+	switch strings.ToUpper(strings.TrimSpace(paymentStatus)) {
+	case PaymentStatusPaid, PaymentStatusPartiallyRefunded:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRefunded reports whether paymentStatus is one of the refund statuses
+// reported by the payment service. Refunds only change the payment status of
+// an order, never its order status.
+func IsRefunded(paymentStatus string) bool {
+	switch strings.ToUpper(strings.TrimSpace(paymentStatus)) {
+	case PaymentStatusPartiallyRefunded, PaymentStatusRefunded:
+		return true
+	default:
+		return false
+	}
+}
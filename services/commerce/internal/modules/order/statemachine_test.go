@@ -0,0 +1,130 @@
+package order
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+)
+
+func TestResolveTransitions(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        string
+		paymentStatus string
+		event         Event
+		wantTo        string
+		wantErr       error
+	}{
+		{name: "payment starts for a submitted order", status: StatusSubmitted, paymentStatus: PaymentStatusUnpaid, event: EventPaymentPending, wantTo: StatusPayPending},
+		{name: "failed payment can be retried", status: StatusPayFailed, paymentStatus: PaymentStatusPayFailed, event: EventPaymentSucceeded, wantTo: StatusPaid},
+		{name: "paid order ignores late failures", status: StatusPaid, paymentStatus: PaymentStatusPaid, event: EventPaymentFailed, wantErr: ErrAlreadyPaid},
+		{name: "cancelled order rejects payment", status: StatusCancelled, paymentStatus: PaymentStatusPayFailed, event: EventPaymentSucceeded, wantErr: ErrInvalidTransition},
+		{name: "paid order can be assigned", status: StatusPaid, paymentStatus: PaymentStatusPaid, event: EventAssigned, wantTo: StatusConfirmed},
+		{name: "unpaid order cannot be assigned", status: StatusSubmitted, paymentStatus: PaymentStatusUnpaid, event: EventAssigned, wantErr: ErrPaymentRequired},
+		{name: "shipping requires confirmation", status: StatusPaid, paymentStatus: PaymentStatusPaid, event: EventShipped, wantErr: ErrInvalidTransition},
+		{name: "first parcel partially ships a confirmed order", status: StatusConfirmed, paymentStatus: PaymentStatusPaid, event: EventPartiallyShipped, wantTo: StatusPartiallyShipped},
+		{name: "last parcel ships a partially shipped order", status: StatusPartiallyShipped, paymentStatus: PaymentStatusPaid, event: EventShipped, wantTo: StatusShipped},
+		{name: "partially shipped order awaits the rest before delivery", status: StatusPartiallyShipped, paymentStatus: PaymentStatusPaid, event: EventCarrierDelivered, wantErr: ErrInvalidTransition},
+		{name: "shipped order is delivered", status: "shipped", paymentStatus: PaymentStatusPaid, event: EventReceiptConfirmed, wantTo: StatusDelivered},
+		{name: "delivered order is final", status: StatusDelivered, paymentStatus: PaymentStatusPaid, event: EventDeliveryConfirmed, wantErr: ErrInvalidTransition},
+		{name: "paid order cannot be cancelled", status: StatusConfirmed, paymentStatus: PaymentStatusPaid, event: EventCustomerCancelled, wantErr: ErrAlreadyPaid},
+		{name: "partially refunded order can still ship", status: StatusConfirmed, paymentStatus: PaymentStatusPartiallyRefunded, event: EventShipped, wantTo: StatusShipped},
+		{name: "refunded order cannot ship", status: StatusConfirmed, paymentStatus: PaymentStatusRefunded, event: EventShipped, wantErr: ErrPaymentRequired},
+		{name: "refunded order ignores late payments", status: StatusPaid, paymentStatus: PaymentStatusRefunded, event: EventPaymentSucceeded, wantErr: ErrAlreadyPaid},
+		{name: "unknown event", status: StatusSubmitted, paymentStatus: PaymentStatusUnpaid, event: Event("REOPENED"), wantErr: ErrUnknownEvent},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Resolve(test.status, test.paymentStatus, test.event)
+			if test.wantErr != nil {
+				if !errors.Is(err, test.wantErr) {
+					t.Fatalf("got %v, want %v", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolve: %v", err)
+			}
+			if got.To != test.wantTo {
+				t.Fatalf("got target %s, want %s", got.To, test.wantTo)
+			}
+		})
+	}
+}
+
+func TestTransitionTableIsConsistent(t *testing.T) {
+	states := States()
+	seen := map[Event]bool{}
+	for _, transition := range Transitions() {
+		if seen[transition.Event] {
+			t.Fatalf("event %s is declared twice", transition.Event)
+		}
+		seen[transition.Event] = true
+		if len(transition.Roles) == 0 {
+			t.Fatalf("event %s has no roles", transition.Event)
+		}
+		for _, role := range transition.Roles {
+			if role == RoleSystem {
+				if transition.Permission != "" {
+					t.Fatalf("system event %s must not require a permission", transition.Event)
+				}
+				continue
+			}
+			if _, ok := authz.NewAuthorizer(nil).Allows(role, transition.Permission, ""); !ok {
+				t.Fatalf("event %s lists role %s without %q in the default matrix", transition.Event, role, transition.Permission)
+			}
+		}
+		if !slices.Contains(states, transition.To) {
+			t.Fatalf("event %s targets unknown status %s", transition.Event, transition.To)
+		}
+		for _, from := range transition.From {
+			if !slices.Contains(states, from) {
+				t.Fatalf("event %s starts from unknown status %s", transition.Event, from)
+			}
+		}
+	}
+	for _, status := range []string{StatusDelivered, StatusCancelled, StatusClosed} {
+		if !IsTerminal(status) {
+			t.Fatalf("expected %s to be terminal", status)
+		}
+	}
+	if IsTerminal(StatusShipped) {
+		t.Fatal("expected SHIPPED to have outgoing transitions")
+	}
+	cancel, _ := Lookup(EventCustomerCancelled)
+	if !cancel.HasEffect(EffectReleaseStock) || !cancel.HasEffect(EffectClosePayments) {
+		t.Fatalf("expected cancel to release stock and close payments, got %v", cancel.Effects)
+	}
+	for _, event := range []Event{EventCustomerCancelled, EventAdminClosed, EventAutoClosed} {
+		if transition, _ := Lookup(event); !transition.HasEffect(EffectVoidReceivable) {
+			t.Fatalf("expected %s to void the receivable, got %v", event, transition.Effects)
+		}
+	}
+	for _, event := range []Event{EventOfflinePaymentConfirmed, EventPaymentSucceeded} {
+		if transition, _ := Lookup(event); !transition.HasEffect(EffectSettleReceivable) {
+			t.Fatalf("expected %s to settle the receivable, got %v", event, transition.Effects)
+		}
+	}
+}
+
+func TestTransitionsReturnsCopy(t *testing.T) {
+	items := Transitions()
+	items[0].From[0] = "MUTATED"
+	if Transitions()[0].From[0] == "MUTATED" {
+		t.Fatal("expected Transitions to return an independent copy")
+	}
+}
+
+func TestClosedPaymentStatus(t *testing.T) {
+	if got := ClosedPaymentStatus(PaymentStatusPayPending); got != PaymentStatusPayFailed {
+		t.Fatalf("expected pending payment to become PAY_FAILED, got %s", got)
+	}
+	if got := ClosedPaymentStatus(PaymentStatusUnpaid); got != PaymentStatusUnpaid {
+		t.Fatalf("expected unpaid order to stay UNPAID, got %s", got)
+	}
+	if _, err := Resolve(StatusPaid, PaymentStatusPaid, EventAutoClosed); !errors.Is(err, ErrAlreadyPaid) {
+		t.Fatalf("expected paid order to be excluded from auto close, got %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+const (
+	// KindFixedTiers replaces the catalog tiers of a SKU with the rule's own.
+	KindFixedTiers = "FIXED_TIERS"
+	// KindPercentOff takes a percentage off every catalog tier of the SKUs
+	// of a SKU or category rule.
+	KindPercentOff = "PERCENT_OFF"
+
+	MatchedByCustomer = "CUSTOMER"
+	MatchedByTag      = "TAG"
+
+	// MaxDiscountBps is a discount of 100% in basis points.
+	MaxDiscountBps = 10000
+)
+
+// Buyer is whose price lists apply. The zero Buyer pays catalog prices.
+type Buyer struct {
+	CustomerID uuid.UUID
+	TagIDs     []uuid.UUID
+}
+
+// Price is what a buyer pays for one SKU.
+type Price struct {
+	// Tiers are the tiers the buyer pays. They are the catalog tiers unless
+	// a price list rule applies.
+	Tiers     []db.CatalogPriceTier
+	BaseTiers []db.CatalogPriceTier
+	// Rule is the rule that set Tiers, nil when catalog prices apply.
+	Rule *db.ListApplicablePriceListRulesRow
+	// Candidates are all the rules that matched the SKU, best first. Rule is
+	// the first of them able to price the SKU: a percentage off needs
+	// catalog tiers to discount.
+	Candidates []db.ListApplicablePriceListRulesRow
+}
+
+// Resolve prices skuIDs for buyer at now; base holds the catalog tiers by
+// SKU. Every SKU gets a Price, at catalog prices when no rule applies.
+func Resolve(ctx context.Context, store Store, buyer Buyer, skuIDs []uuid.UUID, base map[uuid.UUID][]db.CatalogPriceTier, now time.Time) (map[uuid.UUID]Price, error) {
+	prices := make(map[uuid.UUID]Price, len(skuIDs))
+	for _, skuID := range skuIDs {
+		prices[skuID] = Price{Tiers: base[skuID], BaseTiers: base[skuID]}
+	}
+	if buyer.CustomerID == uuid.Nil || len(skuIDs) == 0 {
+		return prices, nil
+	}
+
+	tagIDs := buyer.TagIDs
+	if tagIDs == nil {
+		tagIDs = []uuid.UUID{}
+	}
+	rows, err := store.ListApplicablePriceListRules(ctx, db.ListApplicablePriceListRulesParams{
+		SkuIds:         skuIDs,
+		Now:            pgtype.Timestamptz{Time: now, Valid: true},
+		CustomerID:     buyer.CustomerID,
+		CustomerTagIds: tagIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list applicable price list rules: %w", err)
+	}
+	if len(rows) == 0 {
+		return prices, nil
+	}
+
+	var fixedRuleIDs []uuid.UUID
+	for _, row := range rows {
+		if row.Kind == KindFixedTiers {
+			fixedRuleIDs = append(fixedRuleIDs, row.RuleID)
+		}
+	}
+	fixedTiers := map[uuid.UUID][]db.PriceListRuleTier{}
+	if len(fixedRuleIDs) > 0 {
+		tiers, err := store.ListPriceListRuleTiersByRules(ctx, fixedRuleIDs)
+		if err != nil {
+			return nil, fmt.Errorf("list price list rule tiers: %w", err)
+		}
+		for _, tier := range tiers {
+			fixedTiers[tier.RuleID] = append(fixedTiers[tier.RuleID], tier)
+		}
+	}
+
+	for skuID, candidates := range Rank(rows) {
+		price, ok := prices[skuID]
+		if !ok {
+			continue
+		}
+		price.Candidates = candidates
+		for i := range candidates {
+			if tiers, ok := Apply(candidates[i], price.BaseTiers, fixedTiers[candidates[i].RuleID]); ok {
+				rule := candidates[i]
+				price.Tiers = tiers
+				price.Rule = &rule
+				break
+			}
+		}
+		prices[skuID] = price
+	}
+	return prices, nil
+}
+
+// Rank groups matched rules by the SKU they price and orders each group
+// best first: lists assigned to the customer directly beat lists reaching
+// them through a tag, then higher priority wins, then SKU rules beat
+// category rules and a nearer category beats its ancestors, then the newer
+// list wins. A rule matched more than once is kept at its best position.
+func Rank(rows []db.ListApplicablePriceListRulesRow) map[uuid.UUID][]db.ListApplicablePriceListRulesRow {
+	sorted := make([]db.ListApplicablePriceListRulesRow, len(rows))
+	copy(sorted, rows)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return better(sorted[i], sorted[j])
+	})
+
+	type ruleKey struct{ skuID, ruleID uuid.UUID }
+	seen := make(map[ruleKey]struct{}, len(sorted))
+	ranked := map[uuid.UUID][]db.ListApplicablePriceListRulesRow{}
+	for _, row := range sorted {
+		key := ruleKey{skuID: row.TargetSkuID, ruleID: row.RuleID}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		ranked[row.TargetSkuID] = append(ranked[row.TargetSkuID], row)
+	}
+	return ranked
+}
+
+func better(a, b db.ListApplicablePriceListRulesRow) bool {
+	if aCustomer, bCustomer := MatchedBy(a) == MatchedByCustomer, MatchedBy(b) == MatchedByCustomer; aCustomer != bCustomer {
+		return aCustomer
+	}
+	if a.Priority != b.Priority {
+		return a.Priority > b.Priority
+	}
+	if a.CategoryDepth != b.CategoryDepth {
+		return a.CategoryDepth < b.CategoryDepth
+	}
+	if !a.PriceListCreatedAt.Time.Equal(b.PriceListCreatedAt.Time) {
+		return a.PriceListCreatedAt.Time.After(b.PriceListCreatedAt.Time)
+	}
+	return a.RuleID.String() < b.RuleID.String()
+}
+
+// MatchedBy reports whether a rule reached the buyer through a customer
+// assignment or one of their tags.
+func MatchedBy(rule db.ListApplicablePriceListRulesRow) string {
+	if rule.MatchedCustomerTagID.Valid {
+		return MatchedByTag
+	}
+	return MatchedByCustomer
+}
+
+// Apply computes the tiers a rule sets for a SKU with the catalog tiers
+// base. It reports false when the rule cannot price the SKU.
+func Apply(rule db.ListApplicablePriceListRulesRow, base []db.CatalogPriceTier, fixed []db.PriceListRuleTier) ([]db.CatalogPriceTier, bool) {
+	switch rule.Kind {
+	case KindFixedTiers:
+		if len(fixed) == 0 {
+			return nil, false
+		}
+		tiers := make([]db.CatalogPriceTier, 0, len(fixed))
+		for _, tier := range fixed {
+			tiers = append(tiers, db.CatalogPriceTier{
+				ID:           tier.ID,
+				SkuID:        rule.TargetSkuID,
+				MinQty:       tier.MinQty,
+				MaxQty:       tier.MaxQty,
+				UnitPriceFen: tier.UnitPriceFen,
+			})
+		}
+		return tiers, true
+	case KindPercentOff:
+		if len(base) == 0 || rule.DiscountBps == nil {
+			return nil, false
+		}
+		tiers := make([]db.CatalogPriceTier, 0, len(base))
+		for _, tier := range base {
+			tier.UnitPriceFen = Discount(tier.UnitPriceFen, *rule.DiscountBps)
+			tiers = append(tiers, tier)
+		}
+		return tiers, true
+	}
+	return nil, false
+}
+
+// Discount takes bps basis points off a price in fen, rounding half up.
+func Discount(priceFen int64, bps int32) int64 {
+	if bps <= 0 {
+		return priceFen
+	}
+	if bps >= MaxDiscountBps {
+		return 0
+	}
+	return (priceFen*int64(MaxDiscountBps-bps) + MaxDiscountBps/2) / MaxDiscountBps
+}
+
+// Tier is a quantity tier of a fixed-tier rule.
+type Tier struct {
+	MinQty       int32
+	MaxQty       *int32
+	UnitPriceFen int64
+}
+
+// ValidateTiers checks the tiers of a fixed-tier rule: at least one, each
+// with a positive minimum, a maximum not below it and a price of at least
+// zero, and no two covering the same quantity. The tiers are sorted by
+// minimum quantity.
+func ValidateTiers(tiers []Tier) error {
+	if len(tiers) == 0 {
+		return errors.New("tiers are required for FIXED_TIERS")
+	}
+	sort.SliceStable(tiers, func(i, j int) bool { return tiers[i].MinQty < tiers[j].MinQty })
+	for i, tier := range tiers {
+		if tier.MinQty < 1 {
+			return errors.New("tier minQty must be >= 1")
+		}
+		if tier.MaxQty != nil && *tier.MaxQty < tier.MinQty {
+			return errors.New("tier maxQty must be >= minQty")
+		}
+		if tier.UnitPriceFen < 0 {
+			return errors.New("tier unitPriceFen must be >= 0")
+		}
+		if i > 0 {
+			previous := tiers[i-1]
+			if previous.MaxQty == nil || *previous.MaxQty >= tier.MinQty {
+				return errors.New("tiers must not overlap")
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package pricing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+type stubStore struct {
+	Store
+	rows  []db.ListApplicablePriceListRulesRow
+	tiers []db.PriceListRuleTier
+	arg   db.ListApplicablePriceListRulesParams
+}
+
+func (s *stubStore) ListApplicablePriceListRules(_ context.Context, arg db.ListApplicablePriceListRulesParams) ([]db.ListApplicablePriceListRulesRow, error) {
+	s.arg = arg
+	return s.rows, nil
+}
+
+func (s *stubStore) ListPriceListRuleTiersByRules(context.Context, []uuid.UUID) ([]db.PriceListRuleTier, error) {
+	return s.tiers, nil
+}
+
+func int32Ptr(value int32) *int32 {
+	return &value
+}
+
+func TestDiscountRoundsHalfUp(t *testing.T) {
+	cases := []struct {
+		price int64
+		bps   int32
+		want  int64
+	}{
+		{12000, 1000, 10800},
+		{999, 500, 949},   // 949.05
+		{1001, 5000, 501}, // 500.5
+		{12000, 0, 12000},
+		{12000, MaxDiscountBps, 0},
+	}
+	for _, tc := range cases {
+		if got := Discount(tc.price, tc.bps); got != tc.want {
+			t.Fatalf("Discount(%d, %d) = %d, want %d", tc.price, tc.bps, got, tc.want)
+		}
+	}
+}
+
+func TestRankOrdersCandidates(t *testing.T) {
+	skuID := uuid.New()
+	older := pgtype.Timestamptz{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	newer := pgtype.Timestamptz{Time: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	tag := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+
+	tagHighPriority := db.ListApplicablePriceListRulesRow{TargetSkuID: skuID, RuleID: uuid.New(), Priority: 100, MatchedCustomerTagID: tag, PriceListCreatedAt: newer}
+	customerCategory := db.ListApplicablePriceListRulesRow{TargetSkuID: skuID, RuleID: uuid.New(), Priority: 1, CategoryDepth: 1, PriceListCreatedAt: newer}
+	customerSku := db.ListApplicablePriceListRulesRow{TargetSkuID: skuID, RuleID: uuid.New(), Priority: 1, PriceListCreatedAt: older}
+	customerSkuNewer := db.ListApplicablePriceListRulesRow{TargetSkuID: skuID, RuleID: uuid.New(), Priority: 1, PriceListCreatedAt: newer}
+	customerHigh := db.ListApplicablePriceListRulesRow{TargetSkuID: skuID, RuleID: uuid.New(), Priority: 5, CategoryDepth: 2, PriceListCreatedAt: older}
+	// The same rule reached through a tag as well as directly.
+	customerHighViaTag := customerHigh
+	customerHighViaTag.MatchedCustomerTagID = tag
+
+	ranked := Rank([]db.ListApplicablePriceListRulesRow{
+		tagHighPriority, customerHighViaTag, customerCategory, customerSku, customerSkuNewer, customerHigh,
+	})[skuID]
+	want := []uuid.UUID{customerHigh.RuleID, customerSkuNewer.RuleID, customerSku.RuleID, customerCategory.RuleID, tagHighPriority.RuleID}
+	if len(ranked) != len(want) {
+		t.Fatalf("expected %d candidates, got %d", len(want), len(ranked))
+	}
+	for i, ruleID := range want {
+		if ranked[i].RuleID != ruleID {
+			t.Fatalf("candidate %d: expected rule %s, got %s", i, ruleID, ranked[i].RuleID)
+		}
+	}
+	if MatchedBy(ranked[0]) != MatchedByCustomer {
+		t.Fatalf("expected duplicate rule to keep its customer match, got %s", MatchedBy(ranked[0]))
+	}
+}
+
+func TestResolveAppliesBestRuleAbleToPrice(t *testing.T) {
+	pricedSku := uuid.New()
+	unpricedSku := uuid.New()
+	plainSku := uuid.New()
+	customerID := uuid.New()
+	fixedRule := uuid.New()
+
+	store := &stubStore{
+		rows: []db.ListApplicablePriceListRulesRow{
+			{TargetSkuID: pricedSku, RuleID: uuid.New(), Kind: KindPercentOff, DiscountBps: int32Ptr(1000), Priority: 10},
+			{TargetSkuID: pricedSku, RuleID: fixedRule, Kind: KindFixedTiers, Priority: 1},
+			// A percentage off cannot price a SKU without catalog tiers.
+			{TargetSkuID: unpricedSku, RuleID: uuid.New(), Kind: KindPercentOff, DiscountBps: int32Ptr(1000)},
+		},
+		tiers: []db.PriceListRuleTier{{ID: uuid.New(), RuleID: fixedRule, MinQty: 1, UnitPriceFen: 9000}},
+	}
+	base := map[uuid.UUID][]db.CatalogPriceTier{
+		pricedSku: {{SkuID: pricedSku, MinQty: 1, UnitPriceFen: 12000}},
+		plainSku:  {{SkuID: plainSku, MinQty: 1, UnitPriceFen: 5000}},
+	}
+
+	prices, err := Resolve(context.Background(), store, Buyer{CustomerID: customerID}, []uuid.UUID{pricedSku, unpricedSku, plainSku}, base, time.Now())
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if store.arg.CustomerID != customerID || store.arg.CustomerTagIds == nil {
+		t.Fatalf("unexpected query arguments %#v", store.arg)
+	}
+
+	priced := prices[pricedSku]
+	if priced.Rule == nil || priced.Rule.Kind != KindPercentOff || priced.Tiers[0].UnitPriceFen != 10800 {
+		t.Fatalf("expected 10%% off to apply, got %#v", priced)
+	}
+	if len(priced.Candidates) != 2 || priced.BaseTiers[0].UnitPriceFen != 12000 {
+		t.Fatalf("expected both candidates and catalog tiers kept, got %#v", priced)
+	}
+	if unpriced := prices[unpricedSku]; unpriced.Rule != nil || len(unpriced.Tiers) != 0 || len(unpriced.Candidates) != 1 {
+		t.Fatalf("expected unpriced sku to stay without tiers, got %#v", unpriced)
+	}
+	if plain := prices[plainSku]; plain.Rule != nil || plain.Tiers[0].UnitPriceFen != 5000 {
+		t.Fatalf("expected catalog price for sku without rules, got %#v", plain)
+	}
+}
+
+func TestResolveSkipsAnonymousBuyer(t *testing.T) {
+	skuID := uuid.New()
+	store := &stubStore{rows: []db.ListApplicablePriceListRulesRow{{TargetSkuID: skuID, RuleID: uuid.New(), Kind: KindPercentOff, DiscountBps: int32Ptr(5000)}}}
+	base := map[uuid.UUID][]db.CatalogPriceTier{skuID: {{SkuID: skuID, MinQty: 1, UnitPriceFen: 100}}}
+
+	prices, err := Resolve(context.Background(), store, Buyer{}, []uuid.UUID{skuID}, base, time.Now())
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if prices[skuID].Rule != nil || prices[skuID].Tiers[0].UnitPriceFen != 100 {
+		t.Fatalf("expected catalog price for anonymous buyer, got %#v", prices[skuID])
+	}
+}
+
+func TestValidateTiers(t *testing.T) {
+	valid := []Tier{{MinQty: 10, UnitPriceFen: 800}, {MinQty: 1, MaxQty: int32Ptr(9), UnitPriceFen: 1000}}
+	if err := ValidateTiers(valid); err != nil {
+		t.Fatalf("expected valid tiers, got %v", err)
+	}
+	if valid[0].MinQty != 1 {
+		t.Fatalf("expected tiers sorted by minQty, got %#v", valid)
+	}
+
+	invalid := [][]Tier{
+		nil,
+		{{MinQty: 0, UnitPriceFen: 100}},
+		{{MinQty: 5, MaxQty: int32Ptr(4), UnitPriceFen: 100}},
+		{{MinQty: 1, UnitPriceFen: -1}},
+		{{MinQty: 1, MaxQty: int32Ptr(10), UnitPriceFen: 100}, {MinQty: 10, UnitPriceFen: 90}},
+		{{MinQty: 1, UnitPriceFen: 100}, {MinQty: 10, UnitPriceFen: 90}},
+	}
+	for i, tiers := range invalid {
+		if err := ValidateTiers(tiers); err == nil {
+			t.Fatalf("case %d: expected invalid tiers", i)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package pricing
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+type Store interface {
+	CreatePriceList(ctx context.Context, arg db.CreatePriceListParams) (db.PriceList, error)
+	GetPriceList(ctx context.Context, id uuid.UUID) (db.PriceList, error)
+	ListPriceLists(ctx context.Context, arg db.ListPriceListsParams) ([]db.PriceList, error)
+	CountPriceLists(ctx context.Context) (int64, error)
+	UpdatePriceList(ctx context.Context, arg db.UpdatePriceListParams) (db.PriceList, error)
+	ListPriceListAssignments(ctx context.Context, priceListID uuid.UUID) ([]db.PriceListAssignment, error)
+	ListPriceListRules(ctx context.Context, priceListID uuid.UUID) ([]db.PriceListRule, error)
+	DeletePriceListRule(ctx context.Context, arg db.DeletePriceListRuleParams) (int64, error)
+	ListPriceListRuleTiersByRules(ctx context.Context, ruleIds []uuid.UUID) ([]db.PriceListRuleTier, error)
+	ListApplicablePriceListRules(ctx context.Context, arg db.ListApplicablePriceListRulesParams) ([]db.ListApplicablePriceListRulesRow, error)
+}
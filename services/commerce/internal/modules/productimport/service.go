@@ -26,6 +26,7 @@ import (
 	"github.com/teamdsb/tmo/services/commerce/internal/db"
 	"github.com/teamdsb/tmo/services/commerce/internal/excel"
 	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/search"
 )
 
 const (
@@ -499,13 +500,15 @@ func (s *Service) processGroup(ctx context.Context, group *groupExecution, resol
 		existing, hasExisting := existingSkus[state.Parsed.SkuCode]
 		if hasExisting {
 			sku, err = queries.UpdateSku(ctx, db.UpdateSkuParams{
-				ID:         existing.ID,
-				SkuCode:    skuCode,
-				Name:       state.Parsed.SkuName,
-				Spec:       state.Parsed.Spec,
-				Attributes: attributesJSON,
-				Unit:       state.Parsed.Unit,
-				IsActive:   state.Parsed.IsActive,
+				ID:          existing.ID,
+				SkuCode:     skuCode,
+				Name:        state.Parsed.SkuName,
+				Spec:        state.Parsed.Spec,
+				Attributes:  attributesJSON,
+				Unit:        state.Parsed.Unit,
+				IsActive:    state.Parsed.IsActive,
+				WeightGrams: existing.WeightGrams,
+				VolumeCm3:   existing.VolumeCm3,
 			})
 		} else {
 			sku, err = queries.CreateSku(ctx, db.CreateSkuParams{
@@ -555,6 +558,10 @@ func (s *Service) processGroup(ctx context.Context, group *groupExecution, resol
 		state.Error = ""
 	}
 
+	if err := search.IndexProduct(ctx, queries, product.ID); err != nil {
+		return s.markGroupFailed(ctx, group.Rows, fmt.Sprintf("index product for search: %v", err))
+	}
+
 	if err := tx.Commit(ctx); err != nil {
 		return s.markGroupFailed(ctx, group.Rows, fmt.Sprintf("commit tx: %v", err))
 	}
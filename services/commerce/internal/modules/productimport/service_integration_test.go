@@ -520,6 +520,7 @@ func resetProductImportTables(t *testing.T, pool *pgxpool.Pool) {
 	_, err := pool.Exec(ctx, `
 TRUNCATE product_import_rows,
 product_import_jobs,
+shipment_events,
 order_tracking_shipments,
 import_jobs,
 product_requests,
@@ -344,7 +344,11 @@ func resetProductRequestExportTables(t *testing.T, pool *pgxpool.Pool) {
 	defer cancel()
 
 	_, err := pool.Exec(ctx, `
-TRUNCATE order_tracking_shipments,
+TRUNCATE shipment_import_rows,
+shipment_import_jobs,
+shipment_events,
+order_shipment_lines,
+order_tracking_shipments,
 import_jobs,
 product_requests,
 order_items,
@@ -0,0 +1,248 @@
+package promotion
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+const (
+	// KindCoupon promotions apply to orders that enter one of their coupon
+	// codes.
+	KindCoupon = "COUPON"
+	// KindAutomatic promotions apply to every order that qualifies.
+	KindAutomatic = "AUTOMATIC"
+
+	// DiscountFixed takes DiscountValue fen off the eligible lines.
+	DiscountFixed = "FIXED"
+	// DiscountPercent takes DiscountValue basis points off the eligible
+	// lines, up to MaxDiscountFen.
+	DiscountPercent = "PERCENT"
+
+	// MaxPercentBps is a discount of 100% in basis points.
+	MaxPercentBps = 10000
+
+	// MaxCodePrefixLength bounds the prefix of issued coupon codes.
+	MaxCodePrefixLength = 12
+	codeRandomLength    = 8
+	// codeAlphabet leaves out characters that are easily confused when
+	// codes are typed in: 0/O and 1/I.
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+var (
+	ErrCouponNotFound       = errors.New("coupon not found")
+	ErrInactive             = errors.New("promotion is not active")
+	ErrNotStarted           = errors.New("promotion has not started")
+	ErrEnded                = errors.New("promotion has ended")
+	ErrMinSpendNotMet       = errors.New("order does not meet the minimum spend of the coupon")
+	ErrCouponRedeemed       = errors.New("coupon has been fully redeemed")
+	ErrCustomerLimitReached = errors.New("promotion limit reached for this customer")
+)
+
+// Line is an order line promotions can discount.
+type Line struct {
+	SkuID uuid.UUID
+	// CategoryIDs are the category of the SKU's product and its ancestors.
+	CategoryIDs []uuid.UUID
+	AmountFen   int64
+}
+
+// Discount is a promotion applied to an order. Coupon is set for coupon
+// promotions.
+type Discount struct {
+	Promotion   db.Promotion
+	Coupon      *db.Coupon
+	EligibleFen int64
+	AmountFen   int64
+}
+
+// Available reports why a promotion cannot be used at now, or nil.
+func Available(promotion db.Promotion, now time.Time) error {
+	switch {
+	case !promotion.IsActive:
+		return ErrInactive
+	case promotion.StartsAt.Valid && now.Before(promotion.StartsAt.Time):
+		return ErrNotStarted
+	case promotion.EndsAt.Valid && !now.Before(promotion.EndsAt.Time):
+		return ErrEnded
+	}
+	return nil
+}
+
+// EligibleFen sums the lines a promotion covers: every line, or only those
+// in its category or a subcategory of it.
+func EligibleFen(promotion db.Promotion, lines []Line) int64 {
+	var total int64
+	for _, line := range lines {
+		if promotion.CategoryID.Valid && !slices.Contains(line.CategoryIDs, uuid.UUID(promotion.CategoryID.Bytes)) {
+			continue
+		}
+		total += line.AmountFen
+	}
+	return total
+}
+
+// Evaluate computes what a promotion takes off lines. It reports false when
+// no line is covered or the covered lines do not reach the minimum spend.
+func Evaluate(promotion db.Promotion, lines []Line) (Discount, bool) {
+	eligible := EligibleFen(promotion, lines)
+	if eligible <= 0 || eligible < promotion.MinSpendFen {
+		return Discount{}, false
+	}
+
+	var amount int64
+	switch promotion.DiscountType {
+	case DiscountFixed:
+		amount = min(promotion.DiscountValue, eligible)
+	case DiscountPercent:
+		amount = (eligible*promotion.DiscountValue + MaxPercentBps/2) / MaxPercentBps
+		if promotion.MaxDiscountFen != nil {
+			amount = min(amount, *promotion.MaxDiscountFen)
+		}
+		amount = min(amount, eligible)
+	}
+	if amount <= 0 {
+		return Discount{}, false
+	}
+	return Discount{Promotion: promotion, EligibleFen: eligible, AmountFen: amount}, true
+}
+
+// Combine picks the discounts an order gets from the coupon the customer
+// entered, if any, and the automatic promotions it qualifies for.
+// Stackable promotions add up; one that does not stack applies alone.
+//
+// An entered coupon always applies, joined by the stackable automatic
+// promotions when it stacks itself. Without a coupon the order gets the
+// larger of all stackable automatic promotions together and the best one
+// that does not stack. The coupon comes first, then larger discounts, and
+// together they never exceed subtotalFen.
+func Combine(coupon *Discount, automatic []Discount, subtotalFen int64) []Discount {
+	var (
+		stackable     []Discount
+		stackableFen  int64
+		bestExclusive *Discount
+	)
+	for i := range automatic {
+		discount := automatic[i]
+		if discount.Promotion.Stackable {
+			stackable = append(stackable, discount)
+			stackableFen += discount.AmountFen
+			continue
+		}
+		if bestExclusive == nil || discount.AmountFen > bestExclusive.AmountFen {
+			bestExclusive = &automatic[i]
+		}
+	}
+	sort.SliceStable(stackable, func(i, j int) bool {
+		return stackable[i].AmountFen > stackable[j].AmountFen
+	})
+
+	var selected []Discount
+	switch {
+	case coupon != nil && !coupon.Promotion.Stackable:
+		selected = []Discount{*coupon}
+	case coupon != nil:
+		selected = append([]Discount{*coupon}, stackable...)
+	case bestExclusive != nil && bestExclusive.AmountFen > stackableFen:
+		selected = []Discount{*bestExclusive}
+	default:
+		selected = stackable
+	}
+
+	applied := make([]Discount, 0, len(selected))
+	remaining := subtotalFen
+	for _, discount := range selected {
+		discount.AmountFen = min(discount.AmountFen, remaining)
+		if discount.AmountFen <= 0 {
+			continue
+		}
+		remaining -= discount.AmountFen
+		applied = append(applied, discount)
+	}
+	return applied
+}
+
+// TotalFen sums discounts.
+func TotalFen(discounts []Discount) int64 {
+	var total int64
+	for _, discount := range discounts {
+		total += discount.AmountFen
+	}
+	return total
+}
+
+// CheckLimits reports whether customerID can still redeem a discount:
+// ErrCouponRedeemed once its coupon reached its redemptions and
+// ErrCustomerLimitReached once the customer used the promotion as often as
+// it allows. Orders that were cancelled or closed do not count.
+func CheckLimits(ctx context.Context, counter Counter, discount Discount, customerID uuid.UUID) error {
+	if discount.Coupon != nil {
+		redeemed, err := counter.CountCouponRedemptions(ctx, pgtype.UUID{Bytes: discount.Coupon.ID, Valid: true})
+		if err != nil {
+			return fmt.Errorf("count coupon redemptions: %w", err)
+		}
+		if redeemed >= int64(discount.Coupon.MaxRedemptions) {
+			return ErrCouponRedeemed
+		}
+	}
+	if limit := discount.Promotion.PerCustomerLimit; limit != nil {
+		used, err := counter.CountCustomerPromotionRedemptions(ctx, db.CountCustomerPromotionRedemptionsParams{
+			PromotionID: discount.Promotion.ID,
+			CustomerID:  customerID,
+		})
+		if err != nil {
+			return fmt.Errorf("count customer promotion redemptions: %w", err)
+		}
+		if used >= int64(*limit) {
+			return ErrCustomerLimitReached
+		}
+	}
+	return nil
+}
+
+// NormalizeCode is how coupon codes are compared: trimmed and upper case.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ValidCodePrefix reports whether prefix, normalized, can start issued
+// coupon codes: up to MaxCodePrefixLength letters, digits or dashes.
+func ValidCodePrefix(prefix string) bool {
+	if len(prefix) > MaxCodePrefixLength {
+		return false
+	}
+	for _, r := range prefix {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// GenerateCode returns prefix followed by random characters that are hard
+// to mistype.
+func GenerateCode(prefix string) (string, error) {
+	var builder strings.Builder
+	builder.WriteString(prefix)
+	max := big.NewInt(int64(len(codeAlphabet)))
+	for i := 0; i < codeRandomLength; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("generate coupon code: %w", err)
+		}
+		builder.WriteByte(codeAlphabet[n.Int64()])
+	}
+	return builder.String(), nil
+}
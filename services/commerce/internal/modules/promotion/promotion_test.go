@@ -0,0 +1,180 @@
+package promotion
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+type stubCounter struct {
+	coupon   int64
+	customer int64
+}
+
+func (s stubCounter) CountCouponRedemptions(context.Context, pgtype.UUID) (int64, error) {
+	return s.coupon, nil
+}
+
+func (s stubCounter) CountCustomerPromotionRedemptions(context.Context, db.CountCustomerPromotionRedemptionsParams) (int64, error) {
+	return s.customer, nil
+}
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
+
+func int32Ptr(value int32) *int32 {
+	return &value
+}
+
+func discount(amount int64, stackable bool) Discount {
+	return Discount{Promotion: db.Promotion{ID: uuid.New(), Stackable: stackable}, AmountFen: amount}
+}
+
+func TestEvaluate(t *testing.T) {
+	metals := uuid.New()
+	lines := []Line{
+		{SkuID: uuid.New(), CategoryIDs: []uuid.UUID{metals, uuid.New()}, AmountFen: 24000},
+		{SkuID: uuid.New(), CategoryIDs: []uuid.UUID{uuid.New()}, AmountFen: 6000},
+	}
+	inMetals := pgtype.UUID{Bytes: metals, Valid: true}
+
+	cases := []struct {
+		name      string
+		promotion db.Promotion
+		want      int64
+		ok        bool
+	}{
+		{"fixed", db.Promotion{DiscountType: DiscountFixed, DiscountValue: 5000}, 5000, true},
+		{"fixed capped at eligible", db.Promotion{DiscountType: DiscountFixed, DiscountValue: 50000, CategoryID: inMetals}, 24000, true},
+		{"percent rounds half up", db.Promotion{DiscountType: DiscountPercent, DiscountValue: 333}, 999, true},
+		{"percent capped", db.Promotion{DiscountType: DiscountPercent, DiscountValue: 5000, MaxDiscountFen: int64Ptr(2000)}, 2000, true},
+		{"category scope", db.Promotion{DiscountType: DiscountPercent, DiscountValue: 1000, CategoryID: inMetals}, 2400, true},
+		{"min spend met", db.Promotion{DiscountType: DiscountFixed, DiscountValue: 100, MinSpendFen: 30000}, 100, true},
+		{"min spend not met", db.Promotion{DiscountType: DiscountFixed, DiscountValue: 100, MinSpendFen: 30001}, 0, false},
+		{"min spend counts eligible lines", db.Promotion{DiscountType: DiscountFixed, DiscountValue: 100, MinSpendFen: 25000, CategoryID: inMetals}, 0, false},
+		{"no eligible lines", db.Promotion{DiscountType: DiscountFixed, DiscountValue: 100, CategoryID: pgtype.UUID{Bytes: uuid.New(), Valid: true}}, 0, false},
+	}
+	for _, tc := range cases {
+		got, ok := Evaluate(tc.promotion, lines)
+		if ok != tc.ok || got.AmountFen != tc.want {
+			t.Fatalf("%s: Evaluate = %d, %v, want %d, %v", tc.name, got.AmountFen, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestAvailable(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	at := func(value time.Time) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: value, Valid: true}
+	}
+
+	cases := []struct {
+		promotion db.Promotion
+		want      error
+	}{
+		{db.Promotion{IsActive: true}, nil},
+		{db.Promotion{IsActive: false}, ErrInactive},
+		{db.Promotion{IsActive: true, StartsAt: at(now.Add(time.Hour))}, ErrNotStarted},
+		{db.Promotion{IsActive: true, EndsAt: at(now)}, ErrEnded},
+		{db.Promotion{IsActive: true, StartsAt: at(now), EndsAt: at(now.Add(time.Hour))}, nil},
+	}
+	for i, tc := range cases {
+		if got := Available(tc.promotion, now); !errors.Is(got, tc.want) {
+			t.Fatalf("case %d: Available = %v, want %v", i, got, tc.want)
+		}
+	}
+}
+
+func TestCombineWithoutCoupon(t *testing.T) {
+	small := discount(1000, true)
+	medium := discount(1500, true)
+	exclusive := discount(2000, false)
+
+	got := Combine(nil, []Discount{small, exclusive, medium}, 100000)
+	if len(got) != 2 || got[0].Promotion.ID != medium.Promotion.ID || got[1].Promotion.ID != small.Promotion.ID {
+		t.Fatalf("expected stackable promotions largest first, got %+v", got)
+	}
+
+	bigger := discount(3000, false)
+	got = Combine(nil, []Discount{small, exclusive, bigger, medium}, 100000)
+	if len(got) != 1 || got[0].Promotion.ID != bigger.Promotion.ID {
+		t.Fatalf("expected the best exclusive promotion alone, got %+v", got)
+	}
+
+	tie := discount(2500, false)
+	got = Combine(nil, []Discount{small, medium, tie}, 100000)
+	if len(got) != 2 {
+		t.Fatalf("expected stackable promotions to win a tie, got %+v", got)
+	}
+}
+
+func TestCombineWithCoupon(t *testing.T) {
+	automatic := []Discount{discount(1000, true), discount(9000, false)}
+
+	coupon := discount(500, false)
+	got := Combine(&coupon, automatic, 100000)
+	if len(got) != 1 || got[0].Promotion.ID != coupon.Promotion.ID {
+		t.Fatalf("expected exclusive coupon alone, got %+v", got)
+	}
+
+	coupon = discount(500, true)
+	got = Combine(&coupon, automatic, 100000)
+	if len(got) != 2 || got[0].Promotion.ID != coupon.Promotion.ID || got[1].AmountFen != 1000 {
+		t.Fatalf("expected coupon then stackable promotions, got %+v", got)
+	}
+}
+
+func TestCombineCapsAtSubtotal(t *testing.T) {
+	coupon := discount(800, true)
+	got := Combine(&coupon, []Discount{discount(500, true), discount(300, true)}, 1000)
+	if len(got) != 2 || got[0].AmountFen != 800 || got[1].AmountFen != 200 || TotalFen(got) != 1000 {
+		t.Fatalf("expected discounts capped at the subtotal, got %+v", got)
+	}
+}
+
+func TestCheckLimits(t *testing.T) {
+	ctx := context.Background()
+	customerID := uuid.New()
+	withCoupon := discount(100, false)
+	withCoupon.Coupon = &db.Coupon{ID: uuid.New(), MaxRedemptions: 2}
+	withCoupon.Promotion.PerCustomerLimit = int32Ptr(1)
+
+	if err := CheckLimits(ctx, stubCounter{coupon: 1}, withCoupon, customerID); err != nil {
+		t.Fatalf("expected coupon to be available, got %v", err)
+	}
+	if err := CheckLimits(ctx, stubCounter{coupon: 2}, withCoupon, customerID); !errors.Is(err, ErrCouponRedeemed) {
+		t.Fatalf("expected ErrCouponRedeemed, got %v", err)
+	}
+	if err := CheckLimits(ctx, stubCounter{customer: 1}, withCoupon, customerID); !errors.Is(err, ErrCustomerLimitReached) {
+		t.Fatalf("expected ErrCustomerLimitReached, got %v", err)
+	}
+	if err := CheckLimits(ctx, stubCounter{coupon: 99, customer: 99}, discount(100, true), customerID); err != nil {
+		t.Fatalf("expected unlimited promotion to be available, got %v", err)
+	}
+}
+
+func TestGenerateCode(t *testing.T) {
+	code, err := GenerateCode("VIP-")
+	if err != nil {
+		t.Fatalf("generate code: %v", err)
+	}
+	if !strings.HasPrefix(code, "VIP-") || len(code) != len("VIP-")+codeRandomLength {
+		t.Fatalf("unexpected code %q", code)
+	}
+	for _, r := range code[len("VIP-"):] {
+		if !strings.ContainsRune(codeAlphabet, r) {
+			t.Fatalf("unexpected character %q in %q", r, code)
+		}
+	}
+	if !ValidCodePrefix(NormalizeCode(" vip- ")) || ValidCodePrefix("VIP_") || ValidCodePrefix(strings.Repeat("A", MaxCodePrefixLength+1)) {
+		t.Fatal("unexpected prefix validation")
+	}
+}
@@ -0,0 +1,32 @@
+package promotion
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+type Store interface {
+	Counter
+	CreatePromotion(ctx context.Context, arg db.CreatePromotionParams) (db.Promotion, error)
+	GetPromotion(ctx context.Context, id uuid.UUID) (db.Promotion, error)
+	ListPromotions(ctx context.Context, arg db.ListPromotionsParams) ([]db.Promotion, error)
+	CountPromotions(ctx context.Context, kind *string) (int64, error)
+	UpdatePromotion(ctx context.Context, arg db.UpdatePromotionParams) (db.Promotion, error)
+	ListActiveAutomaticPromotions(ctx context.Context, now pgtype.Timestamptz) ([]db.Promotion, error)
+	GetCouponByCode(ctx context.Context, code string) (db.Coupon, error)
+	ListCoupons(ctx context.Context, arg db.ListCouponsParams) ([]db.ListCouponsRow, error)
+	CountCoupons(ctx context.Context, promotionID uuid.UUID) (int64, error)
+	ListOrderDiscounts(ctx context.Context, orderID uuid.UUID) ([]db.OrderDiscount, error)
+	ListSkuCategoryAncestors(ctx context.Context, skuIds []uuid.UUID) ([]db.ListSkuCategoryAncestorsRow, error)
+}
+
+// Counter counts redemptions; *db.Queries bound to the order transaction
+// satisfies it.
+type Counter interface {
+	CountCouponRedemptions(ctx context.Context, couponID pgtype.UUID) (int64, error)
+	CountCustomerPromotionRedemptions(ctx context.Context, arg db.CountCustomerPromotionRedemptionsParams) (int64, error)
+}
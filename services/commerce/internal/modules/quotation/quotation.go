@@ -0,0 +1,60 @@
+package quotation
+
+import (
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+const (
+	StatusSent       = "SENT"
+	StatusAccepted   = "ACCEPTED"
+	StatusRejected   = "REJECTED"
+	StatusSuperseded = "SUPERSEDED"
+	StatusConverted  = "CONVERTED"
+	// StatusExpired is reported for open quotations past their validity
+	// date. It is never stored, so extending a quotation means revising it.
+	StatusExpired = "EXPIRED"
+)
+
+// OpenStatuses are the stored statuses a quotation can still be accepted,
+// rejected, converted or superseded from.
+var OpenStatuses = []string{StatusSent, StatusAccepted}
+
+// validityLocation is where a validity date ends; quotations are valid
+// through the whole of their last day in China Standard Time.
+var validityLocation = time.FixedZone("CST", 8*60*60)
+
+// Expired reports whether a quotation valid through validUntil has expired
+// at now.
+func Expired(validUntil pgtype.Date, now time.Time) bool {
+	if !validUntil.Valid {
+		return false
+	}
+	day := validUntil.Time
+	end := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, validityLocation).AddDate(0, 0, 1)
+	return !now.Before(end)
+}
+
+// IsOpen reports whether the quotation can still be acted on at now.
+func IsOpen(quotation db.Quotation, now time.Time) bool {
+	return slices.Contains(OpenStatuses, quotation.Status) && !Expired(quotation.ValidUntil, now)
+}
+
+// EffectiveStatus is the status shown to users: open quotations past their
+// validity date read as EXPIRED.
+func EffectiveStatus(quotation db.Quotation, now time.Time) string {
+	if slices.Contains(OpenStatuses, quotation.Status) && Expired(quotation.ValidUntil, now) {
+		return StatusExpired
+	}
+	return quotation.Status
+}
+
+// Today is the current date in the zone validity dates are given in.
+func Today(now time.Time) time.Time {
+	local := now.In(validityLocation)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,51 @@
+package quotation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+func TestExpiredAtEndOfValidityDayInChinaTime(t *testing.T) {
+	validUntil := pgtype.Date{Time: time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC), Valid: true}
+	lastMoment := time.Date(2026, 10, 31, 15, 59, 59, 0, time.UTC)
+	if Expired(validUntil, lastMoment) {
+		t.Fatal("expected quotation to be valid until midnight China time")
+	}
+	if !Expired(validUntil, lastMoment.Add(time.Second)) {
+		t.Fatal("expected quotation to expire at midnight China time")
+	}
+}
+
+func TestEffectiveStatus(t *testing.T) {
+	now := time.Date(2026, 10, 17, 4, 0, 0, 0, time.UTC)
+	past := pgtype.Date{Time: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), Valid: true}
+	future := pgtype.Date{Time: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	cases := []struct {
+		status     string
+		validUntil pgtype.Date
+		want       string
+		open       bool
+	}{
+		{StatusSent, future, StatusSent, true},
+		{StatusAccepted, past, StatusExpired, false},
+		{StatusConverted, past, StatusConverted, false},
+		{StatusRejected, future, StatusRejected, false},
+	}
+	for _, tc := range cases {
+		quotation := db.Quotation{Status: tc.status, ValidUntil: tc.validUntil}
+		if got := EffectiveStatus(quotation, now); got != tc.want {
+			t.Errorf("EffectiveStatus(%s) = %s, want %s", tc.status, got, tc.want)
+		}
+		if got := IsOpen(quotation, now); got != tc.open {
+			t.Errorf("IsOpen(%s) = %v, want %v", tc.status, got, tc.open)
+		}
+	}
+	if got := Today(time.Date(2026, 10, 16, 17, 0, 0, 0, time.UTC)); !got.Equal(time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Today = %v", got)
+	}
+}
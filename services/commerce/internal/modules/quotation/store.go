@@ -0,0 +1,16 @@
+package quotation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+type Store interface {
+	GetQuotation(ctx context.Context, id uuid.UUID) (db.Quotation, error)
+	ListQuotationsByInquiry(ctx context.Context, inquiryID uuid.UUID) ([]db.Quotation, error)
+	ListQuotationItems(ctx context.Context, quotationID uuid.UUID) ([]db.QuotationItem, error)
+	ListQuotationItemsByQuotations(ctx context.Context, quotationIds []uuid.UUID) ([]db.QuotationItem, error)
+	DecideQuotation(ctx context.Context, arg db.DecideQuotationParams) (db.Quotation, error)
+}
@@ -0,0 +1,92 @@
+package receivable
+
+import (
+	"bytes"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+const (
+	GroupByCustomer = "customer"
+	GroupBySales    = "sales"
+)
+
+// Buckets splits outstanding amounts by days since the receivable was posted.
+type Buckets struct {
+	Days0To30  int64
+	Days31To60 int64
+	Days61To90 int64
+	Over90     int64
+}
+
+// AgingGroup sums the open receivables of one customer or one owning sales
+// rep. GroupID is the zero UUID for receivables without an owning sales rep.
+type AgingGroup struct {
+	GroupID    uuid.UUID
+	OpenCount  int
+	TotalFen   int64
+	OverdueFen int64
+	Buckets    Buckets
+}
+
+// Age groups open receivables by customer or owning sales rep as of asOf.
+// Groups are ordered by outstanding amount, largest first.
+func Age(items []db.Receivable, asOf time.Time, groupBy string) []AgingGroup {
+	index := map[uuid.UUID]int{}
+	groups := make([]AgingGroup, 0)
+	for _, item := range items {
+		if item.Status != StatusOpen {
+			continue
+		}
+		key := item.CustomerID
+		if groupBy == GroupBySales {
+			key = uuid.Nil
+			if item.OwnerSalesUserID.Valid {
+				key = item.OwnerSalesUserID.Bytes
+			}
+		}
+		position, ok := index[key]
+		if !ok {
+			position = len(groups)
+			index[key] = position
+			groups = append(groups, AgingGroup{GroupID: key})
+		}
+		group := &groups[position]
+		group.OpenCount++
+		group.TotalFen += item.AmountFen
+		if item.DueAt.Valid && asOf.After(item.DueAt.Time) {
+			group.OverdueFen += item.AmountFen
+		}
+		switch days := ageDays(item.PostedAt.Time, asOf); {
+		case days <= 30:
+			group.Buckets.Days0To30 += item.AmountFen
+		case days <= 60:
+			group.Buckets.Days31To60 += item.AmountFen
+		case days <= 90:
+			group.Buckets.Days61To90 += item.AmountFen
+		default:
+			group.Buckets.Over90 += item.AmountFen
+		}
+	}
+	slices.SortFunc(groups, func(a, b AgingGroup) int {
+		if a.TotalFen != b.TotalFen {
+			if a.TotalFen > b.TotalFen {
+				return -1
+			}
+			return 1
+		}
+		return bytes.Compare(a.GroupID[:], b.GroupID[:])
+	})
+	return groups
+}
+
+func ageDays(postedAt, asOf time.Time) int {
+	if !asOf.After(postedAt) {
+		return 0
+	}
+	return int(asOf.Sub(postedAt) / (24 * time.Hour))
+}
@@ -0,0 +1,104 @@
+package receivable
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+const (
+	StatusOpen    = "OPEN"
+	StatusSettled = "SETTLED"
+	StatusVoid    = "VOID"
+)
+
+// CreditLimitExceededError reports an order that would push a customer's open
+// receivables over the credit limit.
+type CreditLimitExceededError struct {
+	CustomerID     uuid.UUID
+	LimitFen       int64
+	OutstandingFen int64
+	RequestedFen   int64
+}
+
+func (e CreditLimitExceededError) Error() string {
+	return fmt.Sprintf("credit limit exceeded for customer %s: limit %d, outstanding %d, requested %d", e.CustomerID, e.LimitFen, e.OutstandingFen, e.RequestedFen)
+}
+
+// Posting describes the receivable of one order placed on monthly terms.
+// A nil CreditLimitFen means the customer has no limit.
+type Posting struct {
+	OrderID          uuid.UUID
+	CustomerID       uuid.UUID
+	OwnerSalesUserID pgtype.UUID
+	AmountFen        int64
+	TermDays         int32
+	CreditLimitFen   *int64
+	PostedAt         time.Time
+}
+
+// Post records the receivable of an order. Postings of the same customer are
+// serialised with a transaction-scoped lock so two concurrent orders cannot
+// both slip under the credit limit; Post must run inside a transaction.
+func Post(ctx context.Context, store LedgerStore, posting Posting) (db.Receivable, error) {
+	if err := store.LockCustomerReceivables(ctx, posting.CustomerID); err != nil {
+		return db.Receivable{}, err
+	}
+	if err := CheckCredit(ctx, store, posting.CustomerID, posting.CreditLimitFen, posting.AmountFen); err != nil {
+		return db.Receivable{}, err
+	}
+	postedAt := posting.PostedAt.UTC()
+	termDays := max(posting.TermDays, 0)
+	return store.CreateReceivable(ctx, db.CreateReceivableParams{
+		OrderID:          posting.OrderID,
+		CustomerID:       posting.CustomerID,
+		OwnerSalesUserID: posting.OwnerSalesUserID,
+		AmountFen:        posting.AmountFen,
+		TermDays:         termDays,
+		PostedAt:         pgtype.Timestamptz{Time: postedAt, Valid: true},
+		DueAt:            pgtype.Timestamptz{Time: postedAt.AddDate(0, 0, int(termDays)), Valid: true},
+	})
+}
+
+// CheckCredit returns a CreditLimitExceededError when amountFen on top of
+// the customer's open receivables would pass creditLimitFen. It takes no
+// lock, so on its own it only predicts what Post will decide.
+func CheckCredit(ctx context.Context, store CreditStore, customerID uuid.UUID, creditLimitFen *int64, amountFen int64) error {
+	if creditLimitFen == nil {
+		return nil
+	}
+	outstanding, err := store.SumOpenReceivablesByCustomer(ctx, customerID)
+	if err != nil {
+		return err
+	}
+	if outstanding+amountFen > *creditLimitFen {
+		return CreditLimitExceededError{
+			CustomerID:     customerID,
+			LimitFen:       *creditLimitFen,
+			OutstandingFen: outstanding,
+			RequestedFen:   amountFen,
+		}
+	}
+	return nil
+}
+
+// Settle marks the open receivable of orderID as paid. Orders without a
+// receivable are left alone.
+func Settle(ctx context.Context, store LedgerStore, orderID uuid.UUID, actorUserID pgtype.UUID) error {
+	_, err := store.SettleReceivableByOrder(ctx, db.SettleReceivableByOrderParams{
+		OrderID:   orderID,
+		SettledBy: actorUserID,
+	})
+	return err
+}
+
+// Void drops the open receivable of an order that will never be fulfilled.
+func Void(ctx context.Context, store LedgerStore, orderID uuid.UUID) error {
+	_, err := store.VoidReceivableByOrder(ctx, orderID)
+	return err
+}
@@ -0,0 +1,200 @@
+package receivable
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+type fakeLedgerStore struct {
+	locked []uuid.UUID
+	items  []db.Receivable
+}
+
+func (s *fakeLedgerStore) LockCustomerReceivables(_ context.Context, customerID uuid.UUID) error {
+	s.locked = append(s.locked, customerID)
+	return nil
+}
+
+func (s *fakeLedgerStore) SumOpenReceivablesByCustomer(_ context.Context, customerID uuid.UUID) (int64, error) {
+	var total int64
+	for _, item := range s.items {
+		if item.CustomerID == customerID && item.Status == StatusOpen {
+			total += item.AmountFen
+		}
+	}
+	return total, nil
+}
+
+func (s *fakeLedgerStore) CreateReceivable(_ context.Context, arg db.CreateReceivableParams) (db.Receivable, error) {
+	item := db.Receivable{
+		ID:               uuid.New(),
+		OrderID:          arg.OrderID,
+		CustomerID:       arg.CustomerID,
+		OwnerSalesUserID: arg.OwnerSalesUserID,
+		AmountFen:        arg.AmountFen,
+		Status:           StatusOpen,
+		TermDays:         arg.TermDays,
+		PostedAt:         arg.PostedAt,
+		DueAt:            arg.DueAt,
+	}
+	s.items = append(s.items, item)
+	return item, nil
+}
+
+func (s *fakeLedgerStore) SettleReceivableByOrder(_ context.Context, arg db.SettleReceivableByOrderParams) (int64, error) {
+	return s.setStatus(arg.OrderID, StatusSettled), nil
+}
+
+func (s *fakeLedgerStore) VoidReceivableByOrder(_ context.Context, orderID uuid.UUID) (int64, error) {
+	return s.setStatus(orderID, StatusVoid), nil
+}
+
+func (s *fakeLedgerStore) setStatus(orderID uuid.UUID, status string) int64 {
+	var affected int64
+	for i := range s.items {
+		if s.items[i].OrderID == orderID && s.items[i].Status == StatusOpen {
+			s.items[i].Status = status
+			affected++
+		}
+	}
+	return affected
+}
+
+func TestPostEnforcesCreditLimit(t *testing.T) {
+	store := &fakeLedgerStore{}
+	customerID := uuid.New()
+	limit := int64(10000)
+	postedAt := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	first, err := Post(context.Background(), store, Posting{OrderID: uuid.New(), CustomerID: customerID, AmountFen: 6000, TermDays: 30, CreditLimitFen: &limit, PostedAt: postedAt})
+	if err != nil {
+		t.Fatalf("post first: %v", err)
+	}
+	if want := postedAt.AddDate(0, 0, 30); !first.DueAt.Time.Equal(want) {
+		t.Fatalf("expected due at %s, got %s", want, first.DueAt.Time)
+	}
+
+	_, err = Post(context.Background(), store, Posting{OrderID: uuid.New(), CustomerID: customerID, AmountFen: 4001, TermDays: 30, CreditLimitFen: &limit, PostedAt: postedAt})
+	var limitErr CreditLimitExceededError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("expected credit limit error, got %v", err)
+	}
+	if limitErr.OutstandingFen != 6000 || limitErr.RequestedFen != 4001 || limitErr.LimitFen != limit {
+		t.Fatalf("unexpected credit limit error %+v", limitErr)
+	}
+
+	if _, err := Post(context.Background(), store, Posting{OrderID: uuid.New(), CustomerID: customerID, AmountFen: 4000, TermDays: 30, CreditLimitFen: &limit, PostedAt: postedAt}); err != nil {
+		t.Fatalf("post up to the limit: %v", err)
+	}
+	if len(store.locked) != 3 {
+		t.Fatalf("expected every posting to lock the customer, got %d locks", len(store.locked))
+	}
+
+	if err := Settle(context.Background(), store, first.OrderID, pgtype.UUID{}); err != nil {
+		t.Fatalf("settle: %v", err)
+	}
+	if _, err := Post(context.Background(), store, Posting{OrderID: uuid.New(), CustomerID: customerID, AmountFen: 6000, TermDays: 30, CreditLimitFen: &limit, PostedAt: postedAt}); err != nil {
+		t.Fatalf("expected settled receivable to free credit, got %v", err)
+	}
+}
+
+func TestPostWithoutLimit(t *testing.T) {
+	store := &fakeLedgerStore{}
+	if _, err := Post(context.Background(), store, Posting{OrderID: uuid.New(), CustomerID: uuid.New(), AmountFen: 1 << 40, TermDays: 60, PostedAt: time.Now()}); err != nil {
+		t.Fatalf("expected unlimited posting, got %v", err)
+	}
+}
+
+func TestCheckCreditDoesNotLock(t *testing.T) {
+	customerID := uuid.New()
+	store := &fakeLedgerStore{items: []db.Receivable{{CustomerID: customerID, AmountFen: 6000, Status: StatusOpen}}}
+	limit := int64(10000)
+
+	var limitErr CreditLimitExceededError
+	if err := CheckCredit(context.Background(), store, customerID, &limit, 4001); !errors.As(err, &limitErr) || limitErr.OutstandingFen != 6000 {
+		t.Fatalf("expected credit limit error, got %v", err)
+	}
+	if err := CheckCredit(context.Background(), store, customerID, &limit, 4000); err != nil {
+		t.Fatalf("expected amount up to the limit to pass, got %v", err)
+	}
+	if err := CheckCredit(context.Background(), store, customerID, nil, 1<<40); err != nil {
+		t.Fatalf("expected no limit to pass, got %v", err)
+	}
+	if len(store.locked) != 0 {
+		t.Fatalf("expected no lock, got %v", store.locked)
+	}
+}
+
+func TestVoidOnlyTouchesOpenReceivables(t *testing.T) {
+	store := &fakeLedgerStore{}
+	orderID := uuid.New()
+	if _, err := Post(context.Background(), store, Posting{OrderID: orderID, CustomerID: uuid.New(), AmountFen: 100, PostedAt: time.Now()}); err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if err := Settle(context.Background(), store, orderID, pgtype.UUID{}); err != nil {
+		t.Fatalf("settle: %v", err)
+	}
+	if err := Void(context.Background(), store, orderID); err != nil {
+		t.Fatalf("void: %v", err)
+	}
+	if store.items[0].Status != StatusSettled {
+		t.Fatalf("expected settled receivable to stay settled, got %s", store.items[0].Status)
+	}
+}
+
+func TestAgeBucketsByCustomerAndSales(t *testing.T) {
+	asOf := time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)
+	customerA, customerB := uuid.New(), uuid.New()
+	sales := uuid.New()
+	open := func(customerID uuid.UUID, owner pgtype.UUID, amount int64, ageDays, termDays int) db.Receivable {
+		postedAt := asOf.AddDate(0, 0, -ageDays)
+		return db.Receivable{
+			CustomerID:       customerID,
+			OwnerSalesUserID: owner,
+			AmountFen:        amount,
+			Status:           StatusOpen,
+			PostedAt:         pgtype.Timestamptz{Time: postedAt, Valid: true},
+			DueAt:            pgtype.Timestamptz{Time: postedAt.AddDate(0, 0, termDays), Valid: true},
+		}
+	}
+	owned := pgtype.UUID{Bytes: sales, Valid: true}
+	items := []db.Receivable{
+		open(customerA, owned, 100, 0, 30),
+		open(customerA, owned, 200, 30, 30),
+		open(customerA, owned, 300, 31, 30),
+		open(customerB, pgtype.UUID{}, 400, 75, 60),
+		open(customerB, owned, 500, 91, 30),
+		{CustomerID: customerB, AmountFen: 9999, Status: StatusSettled},
+	}
+
+	byCustomer := Age(items, asOf, GroupByCustomer)
+	if len(byCustomer) != 2 {
+		t.Fatalf("expected 2 customer groups, got %d", len(byCustomer))
+	}
+	b := byCustomer[0]
+	if b.GroupID != customerB || b.TotalFen != 900 || b.OpenCount != 2 {
+		t.Fatalf("unexpected first group %+v", b)
+	}
+	if b.Buckets != (Buckets{Days61To90: 400, Over90: 500}) || b.OverdueFen != 900 {
+		t.Fatalf("unexpected customer B aging %+v", b)
+	}
+	a := byCustomer[1]
+	if a.Buckets != (Buckets{Days0To30: 300, Days31To60: 300}) || a.OverdueFen != 300 {
+		t.Fatalf("unexpected customer A aging %+v", a)
+	}
+
+	bySales := Age(items, asOf, GroupBySales)
+	if len(bySales) != 2 || bySales[0].GroupID != sales || bySales[0].TotalFen != 1100 {
+		t.Fatalf("unexpected sales groups %+v", bySales)
+	}
+	if bySales[1].GroupID != uuid.Nil || bySales[1].TotalFen != 400 {
+		t.Fatalf("expected unassigned group, got %+v", bySales[1])
+	}
+}
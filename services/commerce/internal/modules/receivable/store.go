@@ -0,0 +1,27 @@
+package receivable
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+type Store interface {
+	ListOpenReceivables(ctx context.Context) ([]db.Receivable, error)
+	ListReceivables(ctx context.Context, arg db.ListReceivablesParams) ([]db.Receivable, error)
+	CountReceivables(ctx context.Context, arg db.CountReceivablesParams) (int64, error)
+	SumOpenReceivablesByCustomer(ctx context.Context, customerID uuid.UUID) (int64, error)
+}
+
+type CreditStore interface {
+	SumOpenReceivablesByCustomer(ctx context.Context, customerID uuid.UUID) (int64, error)
+}
+
+type LedgerStore interface {
+	LockCustomerReceivables(ctx context.Context, customerID uuid.UUID) error
+	SumOpenReceivablesByCustomer(ctx context.Context, customerID uuid.UUID) (int64, error)
+	CreateReceivable(ctx context.Context, arg db.CreateReceivableParams) (db.Receivable, error)
+	SettleReceivableByOrder(ctx context.Context, arg db.SettleReceivableByOrderParams) (int64, error)
+	VoidReceivableByOrder(ctx context.Context, orderID uuid.UUID) (int64, error)
+}
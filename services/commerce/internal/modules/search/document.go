@@ -0,0 +1,115 @@
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+// Document is the search index entry of one product.
+type Document struct {
+	// SearchText is the lowercase text that substring and fuzzy matching
+	// run against.
+	SearchText string
+	// PinyinInitials holds the initials of the product and SKU names, one
+	// word per name, so "bxgl" and "bxglsm8" both find "不锈钢螺栓 M8".
+	PinyinInitials string
+	// Vector is the tsvector in its input syntax. The product name is
+	// weighted A; SKU names, SKU codes and tags B; everything else C.
+	Vector string
+}
+
+// BuildDocument indexes a product together with its active SKUs.
+func BuildDocument(product db.CatalogProduct, skus []db.CatalogSku) Document {
+	var (
+		skuTexts    []string
+		detailTexts []string
+		initials    = []string{compactInitials(product.Name)}
+	)
+	if product.Description != nil {
+		detailTexts = append(detailTexts, *product.Description)
+	}
+	for _, sku := range skus {
+		if !sku.IsActive {
+			continue
+		}
+		skuTexts = append(skuTexts, sku.Name)
+		if sku.SkuCode != nil {
+			skuTexts = append(skuTexts, *sku.SkuCode)
+		}
+		if sku.Spec != nil {
+			detailTexts = append(detailTexts, *sku.Spec)
+		}
+		detailTexts = append(detailTexts, attributeValues(sku.Attributes)...)
+		initials = append(initials, compactInitials(sku.Name))
+	}
+	skuTexts = append(skuTexts, product.Tags...)
+	detailTexts = append(detailTexts, product.FilterDimensions...)
+
+	all := append([]string{product.Name}, skuTexts...)
+	all = append(all, detailTexts...)
+	return Document{
+		SearchText:     strings.ToLower(joinNonEmpty(all)),
+		PinyinInitials: joinNonEmpty(dedupe(initials)),
+		Vector: tsvectorLiteral(
+			weightedText{weight: 'A', texts: []string{product.Name}},
+			weightedText{weight: 'B', texts: skuTexts},
+			weightedText{weight: 'C', texts: detailTexts},
+		),
+	}
+}
+
+// attributeValues returns the scalar values of a SKU attribute object in key
+// order; attribute names such as "material" are not worth matching.
+func attributeValues(raw json.RawMessage) []string {
+	var attributes map[string]interface{}
+	if len(raw) == 0 || json.Unmarshal(raw, &attributes) != nil {
+		return nil
+	}
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		switch value := attributes[key].(type) {
+		case string:
+			values = append(values, value)
+		case float64, bool:
+			values = append(values, fmt.Sprint(value))
+		}
+	}
+	return values
+}
+
+func compactInitials(name string) string {
+	return strings.ReplaceAll(Initials(name), " ", "")
+}
+
+func joinNonEmpty(values []string) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func dedupe(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		result = append(result, value)
+	}
+	return result
+}
@@ -0,0 +1,119 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+const (
+	defaultReindexInterval = 10 * time.Minute
+	defaultReindexBatch    = 200
+)
+
+// IndexProduct rebuilds the search document of a product from its current
+// row and SKUs.
+func IndexProduct(ctx context.Context, store IndexStore, productID uuid.UUID) error {
+	product, err := store.GetProduct(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("get product: %w", err)
+	}
+	skus, err := store.ListSkusByProduct(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("list skus: %w", err)
+	}
+	document := BuildDocument(product, skus)
+	if err := store.UpsertCatalogSearchDocument(ctx, db.UpsertCatalogSearchDocumentParams{
+		ProductID:      productID,
+		SearchText:     document.SearchText,
+		PinyinInitials: document.PinyinInitials,
+		Document:       document.Vector,
+	}); err != nil {
+		return fmt.Errorf("upsert search document: %w", err)
+	}
+	return nil
+}
+
+// Indexer indexes products that have no search document yet or whose
+// product or SKUs changed after it was built. Writes through the API index
+// right away; the indexer covers existing catalogs, bulk SQL changes and
+// writes whose indexing failed.
+type Indexer struct {
+	DB       *pgxpool.Pool
+	Interval time.Duration
+	Logger   *slog.Logger
+}
+
+func (i *Indexer) Start(ctx context.Context) {
+	if i == nil || i.DB == nil {
+		return
+	}
+	interval := i.Interval
+	if interval <= 0 {
+		interval = defaultReindexInterval
+	}
+
+	go func() {
+		i.runLogged(ctx)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				i.runLogged(ctx)
+			}
+		}
+	}()
+}
+
+func (i *Indexer) runLogged(ctx context.Context) {
+	if _, err := i.RunOnce(ctx); err != nil && i.Logger != nil && ctx.Err() == nil {
+		i.Logger.Warn("catalog search reindex failed", "error", err)
+	}
+}
+
+// RunOnce indexes stale products in batches until none are left and returns
+// how many were indexed. A product that fails to index is logged and
+// retried on the next run.
+func (i *Indexer) RunOnce(ctx context.Context) (int, error) {
+	queries := db.New(i.DB)
+	attempted := make(map[uuid.UUID]struct{})
+	indexed := 0
+	for {
+		productIDs, err := queries.ListProductsNeedingSearchIndex(ctx, defaultReindexBatch)
+		if err != nil {
+			return indexed, fmt.Errorf("list stale products: %w", err)
+		}
+		progressed := false
+		for _, productID := range productIDs {
+			// Failed products, and products stamped in the future, stay
+			// stale; trying each once per run keeps the loop finite.
+			if _, ok := attempted[productID]; ok {
+				continue
+			}
+			attempted[productID] = struct{}{}
+			progressed = true
+			if err := IndexProduct(ctx, queries, productID); err != nil {
+				if ctx.Err() != nil {
+					return indexed, ctx.Err()
+				}
+				if i.Logger != nil {
+					i.Logger.Warn("index product for search failed", "product_id", productID, "error", err)
+				}
+				continue
+			}
+			indexed++
+		}
+		if !progressed || len(productIDs) < defaultReindexBatch {
+			return indexed, nil
+		}
+	}
+}
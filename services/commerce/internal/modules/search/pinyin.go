@@ -0,0 +1,56 @@
+package search
+
+import (
+	_ "embed"
+	"strings"
+	"unicode"
+)
+
+const (
+	cjkFirst = 0x4E00
+	cjkLast  = 0x9FFF
+)
+
+// pinyinInitials holds the initial letter of the pinyin of every character
+// of the CJK Unified Ideographs block, one byte per code point from U+4E00,
+// wrapped at 128 characters per line; '.' marks characters without a
+// reading. It was derived from the Unicode pinyin collation order, so
+// polyphonic characters use their most common reading.
+//
+//go:embed pinyin_initials.txt
+var pinyinInitials string
+
+var initialsTable = strings.ReplaceAll(pinyinInitials, "\n", "")
+
+// Initials returns the pinyin initials of text, the way buyers abbreviate a
+// product name: "不锈钢螺栓 M8" becomes "bxgls m8". Runs of letters and
+// digits are kept as lowercase words, and anything else separates words.
+func Initials(text string) string {
+	var builder strings.Builder
+	pendingSpace := false
+	for _, r := range text {
+		var next rune
+		switch {
+		case r >= cjkFirst && r <= cjkLast:
+			if letter := initialsTable[r-cjkFirst]; letter != '.' {
+				next = rune(letter)
+			}
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			next = unicode.ToLower(r)
+		}
+		if next == 0 {
+			pendingSpace = builder.Len() > 0
+			continue
+		}
+		if pendingSpace {
+			builder.WriteByte(' ')
+			pendingSpace = false
+		}
+		builder.WriteRune(next)
+	}
+	return builder.String()
+}
+
+func isHan(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
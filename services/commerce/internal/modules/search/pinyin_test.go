@@ -0,0 +1,18 @@
+package search
+
+import "testing"
+
+func TestInitials(t *testing.T) {
+	cases := map[string]string{
+		"不锈钢螺栓 M8":  "bxgls m8",
+		"六角螺母（304）": "ljlm 304",
+		"PVC 管件":    "pvc gj",
+		"":          "",
+		"  ——  ":    "",
+	}
+	for input, want := range cases {
+		if got := Initials(input); got != want {
+			t.Errorf("Initials(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
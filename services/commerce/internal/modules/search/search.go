@@ -0,0 +1,224 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+const (
+	// FacetCategory, FacetTag and FacetFilterDimension name the facets
+	// ListCatalogSearchFacets returns.
+	FacetCategory        = "category"
+	FacetTag             = "tag"
+	FacetFilterDimension = "filter_dimension"
+
+	maxFacetValues = 20
+	// minFuzzyLength keeps trigram matching away from queries too short to
+	// have meaningful trigrams.
+	minFuzzyLength = 3
+)
+
+// Query is a catalog search. Text is required; the filters narrow the
+// matches and the facets alike.
+type Query struct {
+	Text            string
+	CategoryID      *uuid.UUID
+	Tag             *string
+	FilterDimension *string
+	Limit           int32
+	Offset          int32
+}
+
+type SkuMatch struct {
+	ID      uuid.UUID
+	Name    string
+	SkuCode *string
+	Spec    *string
+}
+
+type Hit struct {
+	Product    db.CatalogProduct
+	Score      float64
+	MatchedSku *SkuMatch
+}
+
+type FacetValue struct {
+	Value string
+	Label string
+	Count int64
+}
+
+type Result struct {
+	Hits             []Hit
+	Total            int64
+	Categories       []FacetValue
+	Tags             []FacetValue
+	FilterDimensions []FacetValue
+}
+
+// matchArgs are the arguments shared by the search queries.
+type matchArgs struct {
+	query    *string
+	term     string
+	initials *string
+	fuzzy    *string
+}
+
+// buildMatchArgs prepares the ways a query can match: full-text terms, a
+// substring of the indexed text, pinyin initials and trigram similarity.
+// Initials only apply to queries made of latin letters and digits, and
+// fuzzy matching only to queries without Chinese.
+func buildMatchArgs(text string) matchArgs {
+	text = strings.ToLower(strings.TrimSpace(text))
+	args := matchArgs{term: EscapeLike(text)}
+	if query := tsqueryLiteral(text); query != "" {
+		args.query = &query
+	}
+
+	compact := strings.ReplaceAll(text, " ", "")
+	hasLetter, latinOnly, hasHan := false, compact != "", false
+	for _, r := range compact {
+		switch {
+		case r < unicode.MaxASCII && unicode.IsLetter(r):
+			hasLetter = true
+		case r < unicode.MaxASCII && unicode.IsDigit(r):
+		default:
+			latinOnly = false
+			if isHan(r) {
+				hasHan = true
+			}
+		}
+	}
+	if latinOnly && hasLetter && len(compact) >= 2 {
+		args.initials = &compact
+	}
+	if !hasHan && utf8.RuneCountInString(text) >= minFuzzyLength {
+		args.fuzzy = &text
+	}
+	return args
+}
+
+// EscapeLike escapes the LIKE wildcards in value so it matches literally
+// with ESCAPE '\'.
+func EscapeLike(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "%", `\%`)
+	return strings.ReplaceAll(value, "_", `\_`)
+}
+
+// Search returns one page of active products matching the query, best
+// matches first, together with the total and the facets of all matches.
+func Search(ctx context.Context, store Store, query Query) (Result, error) {
+	args := buildMatchArgs(query.Text)
+	categoryID := pgtype.UUID{}
+	if query.CategoryID != nil {
+		categoryID = pgtype.UUID{Bytes: *query.CategoryID, Valid: true}
+	}
+
+	rows, err := store.SearchCatalog(ctx, db.SearchCatalogParams{
+		Query:           args.query,
+		Term:            args.term,
+		Initials:        args.initials,
+		Fuzzy:           args.fuzzy,
+		CategoryID:      categoryID,
+		Tag:             query.Tag,
+		FilterDimension: query.FilterDimension,
+		Limit:           query.Limit,
+		Offset:          query.Offset,
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("search catalog: %w", err)
+	}
+	total, err := store.CountCatalogSearch(ctx, db.CountCatalogSearchParams{
+		Query:           args.query,
+		Term:            args.term,
+		Initials:        args.initials,
+		Fuzzy:           args.fuzzy,
+		CategoryID:      categoryID,
+		Tag:             query.Tag,
+		FilterDimension: query.FilterDimension,
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("count catalog search: %w", err)
+	}
+	facets, err := store.ListCatalogSearchFacets(ctx, db.ListCatalogSearchFacetsParams{
+		Query:           args.query,
+		Term:            args.term,
+		Initials:        args.initials,
+		Fuzzy:           args.fuzzy,
+		CategoryID:      categoryID,
+		Tag:             query.Tag,
+		FilterDimension: query.FilterDimension,
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("list catalog search facets: %w", err)
+	}
+
+	result := Result{
+		Hits:             make([]Hit, 0, len(rows)),
+		Total:            total,
+		Categories:       []FacetValue{},
+		Tags:             []FacetValue{},
+		FilterDimensions: []FacetValue{},
+	}
+	for _, row := range rows {
+		result.Hits = append(result.Hits, hitFromRow(row))
+	}
+	for _, facet := range facets {
+		value := FacetValue{Value: facet.Value, Label: facet.Label, Count: facet.Count}
+		switch facet.Facet {
+		case FacetCategory:
+			result.Categories = appendFacet(result.Categories, value)
+		case FacetTag:
+			result.Tags = appendFacet(result.Tags, value)
+		case FacetFilterDimension:
+			result.FilterDimensions = appendFacet(result.FilterDimensions, value)
+		}
+	}
+	return result, nil
+}
+
+func hitFromRow(row db.SearchCatalogRow) Hit {
+	hit := Hit{
+		Product: db.CatalogProduct{
+			ID:               row.ID,
+			Name:             row.Name,
+			Description:      row.Description,
+			CategoryID:       row.CategoryID,
+			CoverImageUrl:    row.CoverImageUrl,
+			Images:           row.Images,
+			Tags:             row.Tags,
+			FilterDimensions: row.FilterDimensions,
+			CreatedAt:        row.CreatedAt,
+			UpdatedAt:        row.UpdatedAt,
+			Status:           row.Status,
+		},
+		Score: row.Score,
+	}
+	if row.MatchedSkuID.Valid && row.MatchedSkuName != nil {
+		hit.MatchedSku = &SkuMatch{
+			ID:      uuid.UUID(row.MatchedSkuID.Bytes),
+			Name:    *row.MatchedSkuName,
+			SkuCode: row.MatchedSkuCode,
+			Spec:    row.MatchedSkuSpec,
+		}
+	}
+	return hit
+}
+
+// appendFacet keeps the most frequent values; the query returns each facet
+// ordered by count.
+func appendFacet(values []FacetValue, value FacetValue) []FacetValue {
+	if len(values) >= maxFacetValues {
+		return values
+	}
+	return append(values, value)
+}
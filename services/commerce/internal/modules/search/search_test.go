@@ -0,0 +1,51 @@
+package search
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+func TestBuildMatchArgs(t *testing.T) {
+	latin := buildMatchArgs("  BXGL ")
+	if latin.term != "bxgl" || latin.initials == nil || *latin.initials != "bxgl" || latin.fuzzy == nil {
+		t.Fatalf("unexpected args for latin query: %+v", latin)
+	}
+	chinese := buildMatchArgs("不锈钢")
+	if chinese.initials != nil || chinese.fuzzy != nil || chinese.query == nil {
+		t.Fatalf("expected only full-text and substring matching for Chinese, got %+v", chinese)
+	}
+	if digits := buildMatchArgs("304"); digits.initials != nil {
+		t.Fatalf("expected no initials match for digits, got %q", *digits.initials)
+	}
+	if escaped := buildMatchArgs("50%_off"); escaped.term != `50\%\_off` {
+		t.Fatalf("expected LIKE wildcards to be escaped, got %q", escaped.term)
+	}
+}
+
+func TestBuildDocumentIndexesActiveSkus(t *testing.T) {
+	description := "耐腐蚀"
+	code := "BOLT-M8"
+	product := db.CatalogProduct{
+		Name:             "不锈钢螺栓",
+		Description:      &description,
+		Tags:             []string{"热销"},
+		FilterDimensions: []string{"M8"},
+	}
+	skus := []db.CatalogSku{
+		{Name: "螺栓 M8", SkuCode: &code, Attributes: json.RawMessage(`{"material":"304","length":20}`), IsActive: true},
+		{Name: "停产款", Attributes: json.RawMessage(`{}`), IsActive: false},
+	}
+
+	document := BuildDocument(product, skus)
+	if want := "不锈钢螺栓 螺栓 m8 bolt-m8 热销 耐腐蚀 20 304 m8"; document.SearchText != want {
+		t.Fatalf("SearchText = %q, want %q", document.SearchText, want)
+	}
+	if want := "bxgls lsm8"; document.PinyinInitials != want {
+		t.Fatalf("PinyinInitials = %q, want %q", document.PinyinInitials, want)
+	}
+	if document.Vector == "" {
+		t.Fatal("expected a tsvector")
+	}
+}
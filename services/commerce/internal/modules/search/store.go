@@ -0,0 +1,22 @@
+package search
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+// IndexStore is what keeping a product's search document current needs.
+type IndexStore interface {
+	GetProduct(ctx context.Context, id uuid.UUID) (db.CatalogProduct, error)
+	ListSkusByProduct(ctx context.Context, productID uuid.UUID) ([]db.CatalogSku, error)
+	UpsertCatalogSearchDocument(ctx context.Context, arg db.UpsertCatalogSearchDocumentParams) error
+}
+
+type Store interface {
+	IndexStore
+	SearchCatalog(ctx context.Context, arg db.SearchCatalogParams) ([]db.SearchCatalogRow, error)
+	CountCatalogSearch(ctx context.Context, arg db.CountCatalogSearchParams) (int64, error)
+	ListCatalogSearchFacets(ctx context.Context, arg db.ListCatalogSearchFacetsParams) ([]db.ListCatalogSearchFacetsRow, error)
+}
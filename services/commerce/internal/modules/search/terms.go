@@ -0,0 +1,238 @@
+package search
+
+import (
+	"html"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// maxPosition is the largest lexeme position a tsvector stores.
+const maxPosition = 16383
+
+// segment is a run of text that is either Chinese or letters and digits.
+type segment struct {
+	text string
+	han  bool
+}
+
+func segments(text string) []segment {
+	var (
+		result  []segment
+		current []rune
+		han     bool
+	)
+	flush := func() {
+		if len(current) > 0 {
+			result = append(result, segment{text: string(current), han: han})
+			current = current[:0]
+		}
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case isHan(r):
+			if !han {
+				flush()
+			}
+			han = true
+			current = append(current, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if han {
+				flush()
+			}
+			han = false
+			current = append(current, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return result
+}
+
+// documentTerms splits text into the lexemes stored in the index. Chinese is
+// written without spaces, so every character and every pair of adjacent
+// characters is a lexeme of its own.
+func documentTerms(text string) []string {
+	var terms []string
+	for _, seg := range segments(text) {
+		if !seg.han {
+			terms = append(terms, seg.text)
+			continue
+		}
+		runes := []rune(seg.text)
+		for i := range runes {
+			terms = append(terms, string(runes[i]))
+			if i+1 < len(runes) {
+				terms = append(terms, string(runes[i:i+2]))
+			}
+		}
+	}
+	return terms
+}
+
+// queryTerms splits a search query into the terms that must all match. A
+// Chinese run matches through its adjacent pairs, which keeps "不锈钢" from
+// matching a product that only mentions "钢".
+func queryTerms(text string) []segment {
+	var terms []segment
+	for _, seg := range segments(text) {
+		if !seg.han {
+			terms = append(terms, seg)
+			continue
+		}
+		runes := []rune(seg.text)
+		if len(runes) == 1 {
+			terms = append(terms, seg)
+			continue
+		}
+		for i := 0; i+1 < len(runes); i++ {
+			terms = append(terms, segment{text: string(runes[i : i+2]), han: true})
+		}
+	}
+	return terms
+}
+
+// weightedText is text indexed with one of the tsvector weights A to D.
+type weightedText struct {
+	weight byte
+	texts  []string
+}
+
+// tsvectorLiteral builds the tsvector of the given texts in its input
+// syntax. Building it here rather than with to_tsvector keeps Chinese
+// tokenization independent of the database locale.
+func tsvectorLiteral(groups ...weightedText) string {
+	var builder strings.Builder
+	position := 0
+	for _, group := range groups {
+		for _, text := range group.texts {
+			for _, term := range documentTerms(text) {
+				if position < maxPosition {
+					position++
+				}
+				if builder.Len() > 0 {
+					builder.WriteByte(' ')
+				}
+				builder.WriteString(quoteLexeme(term))
+				builder.WriteByte(':')
+				builder.WriteString(strconv.Itoa(position))
+				builder.WriteByte(group.weight)
+			}
+		}
+	}
+	return builder.String()
+}
+
+// tsqueryLiteral ANDs the terms of a query; words match as prefixes so
+// results show up while the buyer is still typing. It returns "" when the
+// query has no terms.
+func tsqueryLiteral(text string) string {
+	terms := queryTerms(text)
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		part := quoteLexeme(term.text)
+		if !term.han {
+			part += ":*"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " & ")
+}
+
+func quoteLexeme(term string) string {
+	term = strings.ReplaceAll(term, `\`, `\\`)
+	return "'" + strings.ReplaceAll(term, "'", "''") + "'"
+}
+
+// Highlight HTML-escapes text and wraps the parts that match the query terms
+// in <em> tags.
+func Highlight(text, query string) string {
+	runes := []rune(text)
+	return renderMarked(runes, markMatches(runes, query))
+}
+
+// Snippet highlights the part of text around its first match, at most width
+// characters long, with an ellipsis where text was cut. It reports false
+// when nothing in text matches.
+func Snippet(text, query string, width int) (string, bool) {
+	runes := []rune(text)
+	marked := markMatches(runes, query)
+	first := -1
+	for i, isMarked := range marked {
+		if isMarked {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return "", false
+	}
+	if len(runes) <= width {
+		return renderMarked(runes, marked), true
+	}
+
+	start := first - width/4
+	if start < 0 {
+		start = 0
+	}
+	end := start + width
+	if end > len(runes) {
+		end = len(runes)
+		start = end - width
+	}
+	snippet := renderMarked(runes[start:end], marked[start:end])
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(runes) {
+		snippet += "…"
+	}
+	return snippet, true
+}
+
+func markMatches(runes []rune, query string) []bool {
+	lower := []rune(strings.ToLower(string(runes)))
+	if len(lower) != len(runes) {
+		lower = runes
+	}
+	marked := make([]bool, len(runes))
+	for _, term := range queryTerms(query) {
+		pattern := []rune(term.text)
+		for start := 0; start+len(pattern) <= len(lower); start++ {
+			if matchesAt(lower, pattern, start) {
+				for i := start; i < start+len(pattern); i++ {
+					marked[i] = true
+				}
+			}
+		}
+	}
+	return marked
+}
+
+func renderMarked(runes []rune, marked []bool) string {
+	var builder strings.Builder
+	for i := 0; i < len(runes); {
+		end := i
+		for end < len(runes) && marked[end] == marked[i] {
+			end++
+		}
+		chunk := html.EscapeString(string(runes[i:end]))
+		if marked[i] {
+			builder.WriteString("<em>" + chunk + "</em>")
+		} else {
+			builder.WriteString(chunk)
+		}
+		i = end
+	}
+	return builder.String()
+}
+
+func matchesAt(text, pattern []rune, start int) bool {
+	for i, r := range pattern {
+		if text[start+i] != r {
+			return false
+		}
+	}
+	return true
+}
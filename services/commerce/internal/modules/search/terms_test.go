@@ -0,0 +1,61 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDocumentTermsIndexesChineseCharactersAndPairs(t *testing.T) {
+	got := documentTerms("不锈钢 M8-20")
+	want := []string{"不", "不锈", "锈", "锈钢", "钢", "m8", "20"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("documentTerms = %v, want %v", got, want)
+	}
+}
+
+func TestTSQueryLiteralMatchesChinesePairsAndWordPrefixes(t *testing.T) {
+	if got, want := tsqueryLiteral("不锈钢 M8"), "'不锈' & '锈钢' & 'm8':*"; got != want {
+		t.Fatalf("tsqueryLiteral = %q, want %q", got, want)
+	}
+	if got, want := tsqueryLiteral("钢"), "'钢'"; got != want {
+		t.Fatalf("tsqueryLiteral = %q, want %q", got, want)
+	}
+	if got := tsqueryLiteral(" - "); got != "" {
+		t.Fatalf("expected no query for punctuation, got %q", got)
+	}
+}
+
+func TestTSVectorLiteralWeightsGroups(t *testing.T) {
+	got := tsvectorLiteral(
+		weightedText{weight: 'A', texts: []string{"螺栓"}},
+		weightedText{weight: 'C', texts: []string{"it's"}},
+	)
+	want := "'螺':1A '螺栓':2A '栓':3A 'it':4C 's':5C"
+	if got != want {
+		t.Fatalf("tsvectorLiteral = %q, want %q", got, want)
+	}
+	if got := quoteLexeme("o'k"); got != "'o''k'" {
+		t.Fatalf("quoteLexeme = %q", got)
+	}
+}
+
+func TestHighlightWrapsMatchesAndEscapesHTML(t *testing.T) {
+	got := Highlight("304不锈钢螺栓 <M8>", "不锈钢 m8")
+	want := "304<em>不锈钢</em>螺栓 &lt;<em>M8</em>&gt;"
+	if got != want {
+		t.Fatalf("Highlight = %q, want %q", got, want)
+	}
+	if got := Highlight("Steel Pipe", "brass"); got != "Steel Pipe" {
+		t.Fatalf("expected text without matches unchanged, got %q", got)
+	}
+}
+
+func TestSnippetCutsAroundFirstMatch(t *testing.T) {
+	got, ok := Snippet("适用于户外管道的连接，采用不锈钢材质，耐腐蚀", "不锈钢", 10)
+	if !ok || got != "…采用<em>不锈钢</em>材质，耐腐…" {
+		t.Fatalf("Snippet = %q, %v", got, ok)
+	}
+	if _, ok := Snippet("碳钢", "不锈钢", 10); ok {
+		t.Fatal("expected no snippet without a match")
+	}
+}
@@ -0,0 +1,156 @@
+package shipmentimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/excel"
+	"github.com/teamdsb/tmo/services/commerce/internal/modules/tracking"
+)
+
+type parsedRow struct {
+	RowNumber   int
+	OrderRef    string
+	WaybillNo   string
+	Carrier     string
+	ShippedAt   *time.Time
+	OrderItemID uuid.UUID
+	Qty         int32
+	// HasLine marks rows that ship a quantity of one order item; rows without
+	// one only record the waybill.
+	HasLine   bool
+	RawValues map[string]string
+}
+
+type parsedRowState struct {
+	Row   parsedRow
+	Error string
+}
+
+func parseWorkbookRows(rows [][]string) ([]parsedRowState, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("empty worksheet")
+	}
+
+	spec := excel.ShipmentImportTemplate()
+	headerIndex := excel.HeaderIndexMap(rows[0])
+	missing, missingAny := excel.MissingRequiredHeaders(headerIndex, spec)
+	if len(missing) > 0 || len(missingAny) > 0 {
+		return nil, fmt.Errorf("missing required headers: %s", strings.Join(missing, ", "))
+	}
+
+	results := make([]parsedRowState, 0, len(rows)-1)
+	for rowIndex, row := range rows[1:] {
+		if isBlankRow(row) {
+			continue
+		}
+		state := parsedRowState{
+			Row: parsedRow{
+				RowNumber: rowIndex + 2,
+				RawValues: buildRawValues(row, headerIndex, spec),
+			},
+		}
+		state.Error = parseRow(&state.Row, row, headerIndex)
+		results = append(results, state)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no data rows")
+	}
+	return results, nil
+}
+
+func parseRow(parsed *parsedRow, row []string, headerIndex map[string]int) string {
+	parsed.OrderRef = excel.CellValue(row, headerIndex, "orderid")
+	parsed.WaybillNo = excel.CellValue(row, headerIndex, "waybillno")
+	if parsed.OrderRef == "" {
+		return "orderId is required"
+	}
+	if parsed.WaybillNo == "" {
+		return "waybillNo is required"
+	}
+
+	parsed.Carrier = excel.CellValue(row, headerIndex, "carrier")
+	if known, ok := tracking.KnownCarrier(parsed.Carrier); ok {
+		parsed.Carrier = known.Name
+	}
+
+	if raw := excel.CellValue(row, headerIndex, "shippedat"); raw != "" {
+		shippedAt, ok := parseShippedAt(raw)
+		if !ok {
+			return "shippedAt must be RFC3339 or YYYY-MM-DD"
+		}
+		parsed.ShippedAt = &shippedAt
+	}
+
+	orderItemRaw := excel.CellValue(row, headerIndex, "orderitemid")
+	qtyRaw := excel.CellValue(row, headerIndex, "qty")
+	if orderItemRaw == "" && qtyRaw == "" {
+		return ""
+	}
+	orderItemID, err := uuid.Parse(orderItemRaw)
+	if err != nil {
+		return "orderItemId must be a UUID"
+	}
+	qty, err := strconv.ParseInt(qtyRaw, 10, 32)
+	if err != nil || qty <= 0 {
+		return "qty must be a positive integer"
+	}
+	parsed.OrderItemID = orderItemID
+	parsed.Qty = int32(qty)
+	parsed.HasLine = true
+	return ""
+}
+
+func parseShippedAt(raw string) (time.Time, bool) {
+	if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+		return parsed.UTC(), true
+	}
+	if parsed, err := time.Parse("2006-01-02", raw); err == nil {
+		return parsed, true
+	}
+	return time.Time{}, false
+}
+
+func buildRawValues(row []string, headerIndex map[string]int, spec excel.TemplateSpec) map[string]string {
+	values := make(map[string]string, len(spec.Columns))
+	for _, column := range spec.Columns {
+		values[column.Key] = excel.CellValue(row, headerIndex, column.Key)
+	}
+	return values
+}
+
+func isBlankRow(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func marshalPayload(row parsedRow) json.RawMessage {
+	payload := map[string]interface{}{
+		"rowNumber": row.RowNumber,
+		"orderRef":  row.OrderRef,
+		"waybillNo": row.WaybillNo,
+		"carrier":   row.Carrier,
+		"rawValues": row.RawValues,
+	}
+	if row.ShippedAt != nil {
+		payload["shippedAt"] = row.ShippedAt.Format(time.RFC3339)
+	}
+	if row.HasLine {
+		payload["orderItemId"] = row.OrderItemID.String()
+		payload["qty"] = row.Qty
+	}
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return json.RawMessage(`{}`)
+	}
+	return encoded
+}
@@ -0,0 +1,103 @@
+package shipmentimport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseWorkbookRowsValidatesRows(t *testing.T) {
+	orderID := uuid.New().String()
+	orderItemID := uuid.New().String()
+	rows := [][]string{
+		{"Order ID", "Waybill No", "Carrier", "Shipped At", "Order Item ID", "Qty"},
+		{orderID, "SF100", "顺丰", "2026-09-01", orderItemID, "2"},
+		{orderID, "SF101", "本地配送"},
+		{},
+		{"", "SF102"},
+		{orderID, "SF103", "", "yesterday"},
+		{orderID, "SF104", "", "", "item-1", "1"},
+		{orderID, "SF105", "", "", orderItemID, "0"},
+	}
+
+	parsed, err := parseWorkbookRows(rows)
+	if err != nil {
+		t.Fatalf("parseWorkbookRows returned error: %v", err)
+	}
+	if len(parsed) != 6 {
+		t.Fatalf("expected blank rows to be skipped, got %d rows", len(parsed))
+	}
+
+	first := parsed[0]
+	if first.Error != "" {
+		t.Fatalf("expected first row to be valid, got %q", first.Error)
+	}
+	if first.Row.RowNumber != 2 || first.Row.Carrier != "顺丰速运" || !first.Row.HasLine || first.Row.Qty != 2 {
+		t.Fatalf("unexpected first row %+v", first.Row)
+	}
+	if first.Row.ShippedAt == nil || !first.Row.ShippedAt.Equal(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected shippedAt %v", first.Row.ShippedAt)
+	}
+	if parsed[1].Error != "" || parsed[1].Row.HasLine || parsed[1].Row.Carrier != "本地配送" {
+		t.Fatalf("expected waybill-only row to keep its carrier, got %+v (%q)", parsed[1].Row, parsed[1].Error)
+	}
+
+	wantErrors := map[int]string{
+		5: "orderId is required",
+		6: "shippedAt must be RFC3339 or YYYY-MM-DD",
+		7: "orderItemId must be a UUID",
+		8: "qty must be a positive integer",
+	}
+	for _, state := range parsed[2:] {
+		if want := wantErrors[state.Row.RowNumber]; state.Error != want {
+			t.Errorf("row %d: expected error %q, got %q", state.Row.RowNumber, want, state.Error)
+		}
+	}
+}
+
+func TestParseWorkbookRowsRejectsMissingHeaders(t *testing.T) {
+	if _, err := parseWorkbookRows([][]string{{"Order ID", "Carrier"}, {uuid.New().String(), "SF"}}); err == nil {
+		t.Fatal("expected missing waybill header to fail the sheet")
+	}
+	if _, err := parseWorkbookRows([][]string{{"Order ID", "Waybill No"}}); err == nil {
+		t.Fatal("expected a sheet without data rows to fail")
+	}
+}
+
+func TestBuildWorkGroupsParcelsAndSkipsSucceededRows(t *testing.T) {
+	orderID := uuid.New()
+	itemA := uuid.New()
+	itemB := uuid.New()
+	line := func(waybill string, item uuid.UUID, qty int32, status string) *rowExecutionState {
+		return &rowExecutionState{
+			Parsed:         parsedRow{WaybillNo: waybill, OrderItemID: item, Qty: qty, HasLine: true},
+			PersistedState: status,
+			OrderID:        orderID,
+		}
+	}
+	states := []*rowExecutionState{
+		line("SF1", itemA, 1, rowStatusPending),
+		{Parsed: parsedRow{WaybillNo: "SF9"}, PersistedState: rowStatusPending, OrderID: orderID},
+		line("SF1", itemB, 2, rowStatusPending),
+		line("SF2", itemA, 1, rowStatusSucceeded),
+		line("SF3", itemA, 1, rowStatusSucceeded),
+		line("SF3", itemB, 1, rowStatusFailed),
+		{Parsed: parsedRow{WaybillNo: "SF4", HasLine: true}, Error: "qty must be a positive integer"},
+	}
+
+	actor := uuid.New()
+	waybills, parcels := buildWork(states, actor)
+	if len(waybills) != 1 || waybills[0].Parsed.WaybillNo != "SF9" {
+		t.Fatalf("unexpected waybill-only rows %+v", waybills)
+	}
+	if len(parcels) != 2 {
+		t.Fatalf("expected SF1 and SF3 to be shipped, got %d parcels", len(parcels))
+	}
+	if parcels[0].Request.WaybillNo != "SF1" || len(parcels[0].Request.Lines) != 2 || parcels[0].Request.ActorUserID != actor {
+		t.Fatalf("unexpected first parcel %+v", parcels[0].Request)
+	}
+	if parcels[1].Request.WaybillNo != "SF3" || len(parcels[1].Rows) != 2 {
+		t.Fatalf("expected a partly failed parcel to be retried whole, got %+v", parcels[1].Request)
+	}
+}
@@ -0,0 +1,755 @@
+package shipmentimport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/xuri/excelize/v2"
+
+	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/excel"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+	ordermodule "github.com/teamdsb/tmo/services/commerce/internal/modules/order"
+)
+
+const (
+	rowStatusPending   = "PENDING"
+	rowStatusSucceeded = "SUCCEEDED"
+	rowStatusFailed    = "FAILED"
+
+	errorReportFileName = "errors.xlsx"
+)
+
+var errOrderNotFound = errors.New("order not found")
+
+type EnqueueInput struct {
+	CreatedByUserID pgtype.UUID
+	ExcelFile       io.Reader
+	ExcelFileName   string
+}
+
+// Service runs shipment import jobs. Rows are stored with their outcome so a
+// job that is picked up again after a restart skips the rows it already
+// applied.
+type Service struct {
+	DB                  *pgxpool.Pool
+	Outbox              *events.Outbox
+	MediaLocalOutputDir string
+	MediaPublicBaseURL  string
+	Logger              *slog.Logger
+}
+
+type rowExecutionState struct {
+	Parsed         parsedRow
+	Record         db.ShipmentImportRow
+	Error          string
+	PersistedState string
+	OrderID        uuid.UUID
+}
+
+// parcel collects the rows that share an order and a waybill into one
+// shipment.
+type parcel struct {
+	Rows    []*rowExecutionState
+	Request ordermodule.ShipmentRequest
+}
+
+type importSummary struct {
+	JobID       string    `json:"jobId"`
+	Status      string    `json:"status"`
+	TotalRows   int       `json:"totalRows"`
+	SuccessRows int       `json:"successRows"`
+	FailedRows  int       `json:"failedRows"`
+	ProcessedAt time.Time `json:"processedAt"`
+}
+
+func NewService(pool *pgxpool.Pool, outbox *events.Outbox, mediaLocalOutputDir, mediaPublicBaseURL string, logger *slog.Logger) *Service {
+	return &Service{
+		DB:                  pool,
+		Outbox:              outbox,
+		MediaLocalOutputDir: mediaLocalOutputDir,
+		MediaPublicBaseURL:  mediaPublicBaseURL,
+		Logger:              logger,
+	}
+}
+
+func (s *Service) Enqueue(ctx context.Context, input EnqueueInput) (db.ImportJob, error) {
+	if s == nil || s.DB == nil {
+		return db.ImportJob{}, errors.New("shipment import service is not configured")
+	}
+	if input.ExcelFile == nil {
+		return db.ImportJob{}, errors.New("excel file is required")
+	}
+
+	tx, err := s.DB.Begin(ctx)
+	if err != nil {
+		return db.ImportJob{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	queries := db.New(tx)
+	job, err := queries.CreateImportJob(ctx, db.CreateImportJobParams{
+		Type:            string(oapi.ImportJobTypeSHIPMENTIMPORT),
+		Status:          string(oapi.PENDING),
+		Progress:        0,
+		ResultFileUrl:   nil,
+		ErrorReportUrl:  nil,
+		CreatedByUserID: input.CreatedByUserID,
+	})
+	if err != nil {
+		return db.ImportJob{}, fmt.Errorf("create import job: %w", err)
+	}
+
+	jobRoot := s.jobRootDir(job.ID)
+	inputDir := filepath.Join(jobRoot, "input")
+	if err := os.MkdirAll(inputDir, 0o755); err != nil {
+		return db.ImportJob{}, fmt.Errorf("create job input dir: %w", err)
+	}
+
+	cleanupDir := true
+	defer func() {
+		if cleanupDir {
+			_ = os.RemoveAll(jobRoot)
+		}
+	}()
+
+	excelFileName := sanitizeFileName(input.ExcelFileName, "shipment-import.xlsx")
+	excelPath := filepath.Join(inputDir, excelFileName)
+	if err := copyReaderToFile(excelPath, input.ExcelFile); err != nil {
+		return db.ImportJob{}, fmt.Errorf("save excel file: %w", err)
+	}
+
+	if _, err := queries.CreateShipmentImportJob(ctx, db.CreateShipmentImportJobParams{
+		JobID:         job.ID,
+		ExcelFilePath: excelPath,
+		ExcelFileName: excelFileName,
+	}); err != nil {
+		return db.ImportJob{}, fmt.Errorf("create shipment import job: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return db.ImportJob{}, fmt.Errorf("commit tx: %w", err)
+	}
+	cleanupDir = false
+	return job, nil
+}
+
+func (s *Service) ResetStaleRunning(ctx context.Context) error {
+	if s == nil || s.DB == nil {
+		return nil
+	}
+	_, err := db.New(s.DB).ResetRunningShipmentImportJobs(ctx)
+	return err
+}
+
+func (s *Service) RunNext(ctx context.Context) (bool, error) {
+	if s == nil || s.DB == nil {
+		return false, nil
+	}
+
+	job, err := db.New(s.DB).ClaimNextPendingShipmentImportJob(ctx)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("claim pending shipment import job: %w", err)
+	}
+
+	if err := s.processJob(ctx, job); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+func (s *Service) processJob(ctx context.Context, job db.ClaimNextPendingShipmentImportJobRow) error {
+	rows, err := s.readWorkbook(job.ExcelFilePath)
+	if err != nil {
+		return s.failJob(ctx, job.JobID, fmt.Sprintf("failed to read excel: %v", err))
+	}
+
+	parsedRows, err := parseWorkbookRows(rows)
+	if err != nil {
+		return s.failJob(ctx, job.JobID, err.Error())
+	}
+
+	states, err := s.persistParsedRows(ctx, job.JobID, parsedRows)
+	if err != nil {
+		return s.failJob(ctx, job.JobID, fmt.Sprintf("failed to persist parsed rows: %v", err))
+	}
+	if _, err := db.New(s.DB).UpdateShipmentImportJobCounts(ctx, db.UpdateShipmentImportJobCountsParams{
+		JobID:       job.JobID,
+		TotalRows:   int32(len(states)),
+		SuccessRows: 0,
+		FailedRows:  0,
+	}); err != nil {
+		s.logError("update shipment import total rows failed", err)
+	}
+
+	if err := s.resolveOrders(ctx, states); err != nil {
+		return s.failJob(ctx, job.JobID, fmt.Sprintf("failed to resolve orders: %v", err))
+	}
+	if err := s.flushFailedRows(ctx, states); err != nil {
+		return s.failJob(ctx, job.JobID, fmt.Sprintf("failed to persist validation errors: %v", err))
+	}
+
+	waybills, parcels := buildWork(states, uuid.UUID(job.CreatedByUserID.Bytes))
+	total := len(waybills) + len(parcels)
+	done := 0
+	reportProgress := func() {
+		done++
+		progress := 10 + int32((done*80)/max(1, total))
+		if _, err := db.New(s.DB).UpdateImportJobStatus(ctx, db.UpdateImportJobStatusParams{
+			ID:       job.JobID,
+			Status:   string(oapi.RUNNING),
+			Progress: progress,
+		}); err != nil {
+			s.logError("update shipment import progress failed", err)
+		}
+	}
+	for _, state := range waybills {
+		if err := s.recordWaybill(ctx, state); err != nil {
+			s.logError("import shipment waybill failed", err)
+		}
+		reportProgress()
+	}
+	for _, item := range parcels {
+		if err := s.shipParcel(ctx, item); err != nil {
+			s.logError("import shipment parcel failed", err)
+		}
+		reportProgress()
+	}
+
+	totalRows, successRows, failedRows := summarizeStates(states)
+	if _, err := db.New(s.DB).UpdateShipmentImportJobCounts(ctx, db.UpdateShipmentImportJobCountsParams{
+		JobID:       job.JobID,
+		TotalRows:   int32(totalRows),
+		SuccessRows: int32(successRows),
+		FailedRows:  int32(failedRows),
+	}); err != nil {
+		s.logError("update shipment import counts failed", err)
+	}
+
+	resultURL, err := s.writeSummary(job.JobID, importSummary{
+		JobID:       job.JobID.String(),
+		Status:      string(oapi.SUCCEEDED),
+		TotalRows:   totalRows,
+		SuccessRows: successRows,
+		FailedRows:  failedRows,
+		ProcessedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		s.logError("write shipment import summary failed", err)
+	}
+
+	var errorReportURL *string
+	if failedRows > 0 {
+		reportURL, reportErr := s.writeErrorReport(job.JobID, states)
+		if reportErr != nil {
+			s.logError("write shipment import error report failed", reportErr)
+		} else {
+			errorReportURL = reportURL
+		}
+	}
+
+	_, finalizeErr := db.New(s.DB).FinalizeImportJob(ctx, db.FinalizeImportJobParams{
+		ID:             job.JobID,
+		Status:         string(oapi.SUCCEEDED),
+		Progress:       100,
+		ResultFileUrl:  resultURL,
+		ErrorReportUrl: errorReportURL,
+	})
+	return finalizeErr
+}
+
+// persistParsedRows stores every parsed row. A row that already exists from
+// an earlier run of the job keeps its stored outcome.
+func (s *Service) persistParsedRows(ctx context.Context, jobID uuid.UUID, parsedRows []parsedRowState) ([]*rowExecutionState, error) {
+	queries := db.New(s.DB)
+	states := make([]*rowExecutionState, 0, len(parsedRows))
+	for _, item := range parsedRows {
+		rowStatus := rowStatusPending
+		var errMessage *string
+		if item.Error != "" {
+			rowStatus = rowStatusFailed
+			errValue := item.Error
+			errMessage = &errValue
+		}
+		record, err := queries.CreateShipmentImportRow(ctx, db.CreateShipmentImportRowParams{
+			JobID:        jobID,
+			LineNo:       int32(item.Row.RowNumber),
+			OrderRef:     normalizeNullableString(item.Row.OrderRef),
+			WaybillNo:    normalizeNullableString(item.Row.WaybillNo),
+			RowData:      marshalPayload(item.Row),
+			Status:       rowStatus,
+			ErrorMessage: errMessage,
+		})
+		if err != nil {
+			return nil, err
+		}
+		state := &rowExecutionState{
+			Parsed:         item.Row,
+			Record:         record,
+			Error:          item.Error,
+			PersistedState: record.Status,
+		}
+		if record.Status == rowStatusSucceeded {
+			state.Error = ""
+		}
+		if record.OrderID.Valid {
+			state.OrderID = record.OrderID.Bytes
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// resolveOrders looks up the order each valid row refers to.
+func (s *Service) resolveOrders(ctx context.Context, states []*rowExecutionState) error {
+	queries := db.New(s.DB)
+	resolved := map[string]uuid.UUID{}
+	for _, state := range states {
+		if state.Error != "" || state.OrderID != uuid.Nil {
+			continue
+		}
+		ref := state.Parsed.OrderRef
+		if orderID, ok := resolved[ref]; ok {
+			state.OrderID = orderID
+			continue
+		}
+		orderID, err := resolveOrderRef(ctx, queries, ref)
+		if err != nil {
+			if errors.Is(err, errOrderNotFound) {
+				state.Error = fmt.Sprintf("order %q not found", ref)
+				continue
+			}
+			return err
+		}
+		resolved[ref] = orderID
+		state.OrderID = orderID
+	}
+	return nil
+}
+
+// resolveOrderRef finds the order an import row names by its id or by its
+// order number.
+func resolveOrderRef(ctx context.Context, queries *db.Queries, ref string) (uuid.UUID, error) {
+	var (
+		order db.Order
+		err   error
+	)
+	if orderID, parseErr := uuid.Parse(ref); parseErr == nil {
+		order, err = queries.GetOrder(ctx, orderID)
+	} else {
+		order, err = queries.GetOrderByOrderNo(ctx, strings.ToUpper(ref))
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, errOrderNotFound
+		}
+		return uuid.Nil, err
+	}
+	return order.ID, nil
+}
+
+// buildWork splits the valid rows into waybill-only rows and parcels, keeping
+// the order of the sheet. A parcel is retried as a whole unless every one of
+// its rows already succeeded.
+func buildWork(states []*rowExecutionState, actorUserID uuid.UUID) ([]*rowExecutionState, []*parcel) {
+	waybills := make([]*rowExecutionState, 0)
+	parcels := make([]*parcel, 0)
+	index := map[string]*parcel{}
+	for _, state := range states {
+		if state.Error != "" {
+			continue
+		}
+		if !state.Parsed.HasLine {
+			if state.PersistedState != rowStatusSucceeded {
+				waybills = append(waybills, state)
+			}
+			continue
+		}
+		key := state.OrderID.String() + "/" + state.Parsed.WaybillNo
+		item, ok := index[key]
+		if !ok {
+			item = &parcel{Request: ordermodule.ShipmentRequest{
+				OrderID:     state.OrderID,
+				WaybillNo:   state.Parsed.WaybillNo,
+				Carrier:     normalizeNullableString(state.Parsed.Carrier),
+				ShippedAt:   time.Now().UTC(),
+				ActorUserID: actorUserID,
+			}}
+			if state.Parsed.ShippedAt != nil {
+				item.Request.ShippedAt = *state.Parsed.ShippedAt
+			}
+			index[key] = item
+			parcels = append(parcels, item)
+		}
+		item.Rows = append(item.Rows, state)
+		item.Request.Lines = append(item.Request.Lines, ordermodule.ShipmentLine{
+			OrderItemID: state.Parsed.OrderItemID,
+			Qty:         state.Parsed.Qty,
+		})
+	}
+
+	pending := parcels[:0]
+	for _, item := range parcels {
+		for _, state := range item.Rows {
+			if state.PersistedState != rowStatusSucceeded {
+				pending = append(pending, item)
+				break
+			}
+		}
+	}
+	return waybills, pending
+}
+
+func (s *Service) recordWaybill(ctx context.Context, state *rowExecutionState) error {
+	var shippedAt pgtype.Timestamptz
+	if state.Parsed.ShippedAt != nil {
+		shippedAt = pgtype.Timestamptz{Time: *state.Parsed.ShippedAt, Valid: true}
+	}
+	shipment, err := db.New(s.DB).UpsertTrackingShipment(ctx, db.UpsertTrackingShipmentParams{
+		OrderID:   state.OrderID,
+		WaybillNo: state.Parsed.WaybillNo,
+		Carrier:   normalizeNullableString(state.Parsed.Carrier),
+		ShippedAt: shippedAt,
+	})
+	if err != nil {
+		return s.markRowsFailed(ctx, []*rowExecutionState{state}, fmt.Sprintf("record waybill: %v", err))
+	}
+	return s.markRowsSucceeded(ctx, []*rowExecutionState{state}, shipment.ID)
+}
+
+func (s *Service) shipParcel(ctx context.Context, item *parcel) error {
+	var shipment db.OrderTrackingShipment
+	err := shareddb.WithTx(ctx, s.DB, func(tx pgx.Tx) error {
+		result, err := ordermodule.Ship(ctx, tx, s.Outbox, item.Request)
+		shipment = result.Shipment
+		return err
+	})
+	if errors.Is(err, ordermodule.ErrWaybillShipped) {
+		// The parcel was shipped by an earlier run or an earlier upload of
+		// the same sheet; that counts as done when it carries the same lines.
+		existing, same, checkErr := s.shippedAsRequested(ctx, item.Request)
+		if checkErr != nil {
+			return s.markRowsFailed(ctx, item.Rows, fmt.Sprintf("check shipped waybill: %v", checkErr))
+		}
+		if same {
+			return s.markRowsSucceeded(ctx, item.Rows, existing)
+		}
+	}
+	if err != nil {
+		message, ok := rowErrorMessage(err)
+		if !ok {
+			message = fmt.Sprintf("ship parcel: %v", err)
+		}
+		return s.markRowsFailed(ctx, item.Rows, message)
+	}
+	return s.markRowsSucceeded(ctx, item.Rows, shipment.ID)
+}
+
+// shippedAsRequested reports whether the waybill of request already carries
+// exactly the requested quantities.
+func (s *Service) shippedAsRequested(ctx context.Context, request ordermodule.ShipmentRequest) (uuid.UUID, bool, error) {
+	recorded, err := db.New(s.DB).ListShipmentLinesByOrder(ctx, request.OrderID)
+	if err != nil {
+		return uuid.Nil, false, err
+	}
+	want := map[uuid.UUID]int32{}
+	for _, line := range request.Lines {
+		want[line.OrderItemID] += line.Qty
+	}
+	var shipmentID uuid.UUID
+	got := map[uuid.UUID]int32{}
+	for _, line := range recorded {
+		if line.WaybillNo != request.WaybillNo {
+			continue
+		}
+		shipmentID = line.ShipmentID
+		got[line.OrderItemID] += line.Qty
+	}
+	if len(got) != len(want) {
+		return uuid.Nil, false, nil
+	}
+	for orderItemID, qty := range want {
+		if got[orderItemID] != qty {
+			return uuid.Nil, false, nil
+		}
+	}
+	return shipmentID, true, nil
+}
+
+// rowErrorMessage reports the row error for a parcel that cannot ship.
+func rowErrorMessage(err error) (string, bool) {
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return "order not found", true
+	case errors.Is(err, ordermodule.ErrNotShippable),
+		errors.Is(err, ordermodule.ErrUnknownOrderItem),
+		errors.Is(err, ordermodule.ErrInvalidShipmentQty),
+		errors.Is(err, ordermodule.ErrShipmentQtyExceeded),
+		errors.Is(err, ordermodule.ErrNothingToShip),
+		errors.Is(err, ordermodule.ErrWaybillShipped):
+		return err.Error(), true
+	default:
+		return "", false
+	}
+}
+
+func (s *Service) flushFailedRows(ctx context.Context, states []*rowExecutionState) error {
+	for _, state := range states {
+		if state.Error == "" {
+			continue
+		}
+		if state.PersistedState == rowStatusFailed && state.Record.ErrorMessage != nil && *state.Record.ErrorMessage == state.Error {
+			continue
+		}
+		if err := s.updateRow(ctx, state, rowStatusFailed, &state.Error, uuid.Nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) markRowsFailed(ctx context.Context, rows []*rowExecutionState, message string) error {
+	for _, state := range rows {
+		state.Error = message
+		if err := s.updateRow(ctx, state, rowStatusFailed, &message, uuid.Nil); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("%s", message)
+}
+
+func (s *Service) markRowsSucceeded(ctx context.Context, rows []*rowExecutionState, shipmentID uuid.UUID) error {
+	for _, state := range rows {
+		state.Error = ""
+		if err := s.updateRow(ctx, state, rowStatusSucceeded, nil, shipmentID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) updateRow(ctx context.Context, state *rowExecutionState, status string, message *string, shipmentID uuid.UUID) error {
+	params := db.UpdateShipmentImportRowResultParams{
+		ID:           state.Record.ID,
+		Status:       status,
+		ErrorMessage: message,
+	}
+	if state.OrderID != uuid.Nil {
+		params.OrderID = pgtype.UUID{Bytes: state.OrderID, Valid: true}
+	}
+	if shipmentID != uuid.Nil {
+		params.ShipmentID = pgtype.UUID{Bytes: shipmentID, Valid: true}
+	}
+	record, err := db.New(s.DB).UpdateShipmentImportRowResult(ctx, params)
+	if err != nil {
+		return err
+	}
+	state.Record = record
+	state.PersistedState = status
+	return nil
+}
+
+func (s *Service) failJob(ctx context.Context, jobID uuid.UUID, reason string) error {
+	reportURL, err := s.writeFatalErrorReport(jobID, reason)
+	if err != nil {
+		s.logError("write fatal shipment import error report failed", err)
+	}
+	_, finalizeErr := db.New(s.DB).FinalizeImportJob(ctx, db.FinalizeImportJobParams{
+		ID:             jobID,
+		Status:         string(oapi.FAILED),
+		Progress:       100,
+		ResultFileUrl:  nil,
+		ErrorReportUrl: reportURL,
+	})
+	if finalizeErr != nil {
+		return finalizeErr
+	}
+	return nil
+}
+
+func (s *Service) readWorkbook(excelPath string) ([][]string, error) {
+	file, err := os.Open(excelPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return excel.ReadRows(file)
+}
+
+func summarizeStates(states []*rowExecutionState) (int, int, int) {
+	totalRows := len(states)
+	successRows := 0
+	failedRows := 0
+	for _, state := range states {
+		if state.PersistedState == rowStatusSucceeded {
+			successRows++
+			continue
+		}
+		failedRows++
+	}
+	return totalRows, successRows, failedRows
+}
+
+func (s *Service) writeSummary(jobID uuid.UUID, summary importSummary) (*string, error) {
+	relativePath := filepath.ToSlash(filepath.Join("import-jobs", jobID.String(), "reports", "summary.json"))
+	localPath := filepath.Join(s.MediaLocalOutputDir, filepath.FromSlash(relativePath))
+	if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+		return nil, err
+	}
+	encoded, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(localPath, encoded, 0o644); err != nil {
+		return nil, err
+	}
+	urlValue := s.publicURL(relativePath)
+	return &urlValue, nil
+}
+
+// writeErrorReport writes the failed rows as they were uploaded, followed by
+// the reason, so staff can fix them in place and upload the sheet again.
+func (s *Service) writeErrorReport(jobID uuid.UUID, states []*rowExecutionState) (*string, error) {
+	spec := excel.ShipmentImportTemplate()
+	file := excelize.NewFile()
+	defer func() {
+		_ = file.Close()
+	}()
+	sheet := file.GetSheetName(0)
+	if err := file.SetSheetName(sheet, spec.SheetName); err != nil {
+		return nil, err
+	}
+	sheet = spec.SheetName
+
+	headers := append([]string{"Row"}, excel.TemplateHeaders(spec)...)
+	headers = append(headers, "Error")
+	if err := setRowValues(file, sheet, 1, headers); err != nil {
+		return nil, err
+	}
+	rowIndex := 2
+	for _, state := range states {
+		if state.PersistedState != rowStatusFailed {
+			continue
+		}
+		values := make([]string, 0, len(headers))
+		if state.Parsed.RowNumber > 0 {
+			values = append(values, strconv.Itoa(state.Parsed.RowNumber))
+		} else {
+			values = append(values, "")
+		}
+		for _, column := range spec.Columns {
+			values = append(values, state.Parsed.RawValues[column.Key])
+		}
+		values = append(values, state.Error)
+		if err := setRowValues(file, sheet, rowIndex, values); err != nil {
+			return nil, err
+		}
+		rowIndex++
+	}
+
+	relativePath := filepath.ToSlash(filepath.Join("import-jobs", jobID.String(), "reports", errorReportFileName))
+	localPath := filepath.Join(s.MediaLocalOutputDir, filepath.FromSlash(relativePath))
+	if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+		return nil, err
+	}
+	if err := file.SaveAs(localPath); err != nil {
+		return nil, err
+	}
+	urlValue := s.publicURL(relativePath)
+	return &urlValue, nil
+}
+
+func (s *Service) writeFatalErrorReport(jobID uuid.UUID, reason string) (*string, error) {
+	state := &rowExecutionState{
+		Parsed:         parsedRow{RowNumber: 0},
+		Error:          reason,
+		PersistedState: rowStatusFailed,
+	}
+	return s.writeErrorReport(jobID, []*rowExecutionState{state})
+}
+
+func setRowValues(file *excelize.File, sheet string, row int, values []string) error {
+	for columnIndex, value := range values {
+		cell, err := excelize.CoordinatesToCellName(columnIndex+1, row)
+		if err != nil {
+			return err
+		}
+		if err := file.SetCellValue(sheet, cell, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) publicURL(relativePath string) string {
+	return strings.TrimRight(s.MediaPublicBaseURL, "/") + "/" + strings.TrimLeft(filepath.ToSlash(relativePath), "/")
+}
+
+func (s *Service) jobRootDir(jobID uuid.UUID) string {
+	return filepath.Join(s.MediaLocalOutputDir, "import-jobs", jobID.String())
+}
+
+func (s *Service) logError(message string, err error) {
+	if s == nil || s.Logger == nil {
+		return
+	}
+	s.Logger.Error(message, "error", err)
+}
+
+func normalizeNullableString(value string) *string {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+func copyReaderToFile(path string, reader io.Reader) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	if _, err := io.Copy(file, reader); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func sanitizeFileName(raw, fallback string) string {
+	value := strings.TrimSpace(filepath.Base(raw))
+	if value == "" || value == "." || value == string(filepath.Separator) {
+		return fallback
+	}
+	value = strings.ReplaceAll(value, "..", "")
+	value = strings.ReplaceAll(value, "/", "_")
+	value = strings.ReplaceAll(value, "\\", "_")
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,328 @@
+package shipmentimport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/excel"
+	"github.com/teamdsb/tmo/services/commerce/internal/http/oapi"
+)
+
+const testMediaBaseURL = "http://localhost:8080/assets/media"
+
+func TestServiceRunNextShipsParcelsAndReportsFailedRows(t *testing.T) {
+	pool := openShipmentImportTestPool(t)
+	resetShipmentImportTables(t, pool)
+	queries := db.New(pool)
+	ctx := context.Background()
+
+	order, item := seedConfirmedOrder(t, queries, 3)
+	workbook := buildShipmentWorkbook(t, [][]string{
+		{order.ID.String(), "SF-IMPORT-1", "顺丰", "2026-09-01", item.ID.String(), "2"},
+		{strings.ToLower(order.OrderNo), "SF-IMPORT-2", "", "", item.ID.String(), "5"},
+		{uuid.NewString(), "SF-IMPORT-3"},
+		{order.ID.String(), ""},
+	})
+
+	mediaDir := t.TempDir()
+	service := NewService(pool, nil, mediaDir, testMediaBaseURL, nil)
+	job := enqueueAndRun(t, service, workbook)
+
+	importJob, err := queries.GetImportJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("get import job: %v", err)
+	}
+	if importJob.Status != string(oapi.SUCCEEDED) || importJob.Progress != 100 {
+		t.Fatalf("expected finished job, got %s at %d", importJob.Status, importJob.Progress)
+	}
+	if importJob.ErrorReportUrl == nil {
+		t.Fatal("expected an error report for the failed rows")
+	}
+	shipmentJob, err := queries.GetShipmentImportJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("get shipment import job: %v", err)
+	}
+	if shipmentJob.TotalRows != 4 || shipmentJob.SuccessRows != 1 || shipmentJob.FailedRows != 3 {
+		t.Fatalf("unexpected row counts: %+v", shipmentJob)
+	}
+
+	stored, err := queries.GetOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("get order: %v", err)
+	}
+	if stored.Status != string(oapi.OrderStatusPARTIALLYSHIPPED) {
+		t.Fatalf("expected PARTIALLY_SHIPPED, got %s", stored.Status)
+	}
+	lines, err := queries.ListShipmentLinesByOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("list shipment lines: %v", err)
+	}
+	if len(lines) != 1 || lines[0].WaybillNo != "SF-IMPORT-1" || lines[0].Qty != 2 {
+		t.Fatalf("unexpected shipment lines %+v", lines)
+	}
+	shipments, err := queries.ListTrackingShipments(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("list shipments: %v", err)
+	}
+	if len(shipments) != 1 || shipments[0].Carrier == nil || *shipments[0].Carrier != "顺丰速运" {
+		t.Fatalf("expected the carrier alias to be stored by its name, got %+v", shipments)
+	}
+
+	report, err := excelize.OpenFile(filepath.Join(mediaDir, "import-jobs", job.ID.String(), "reports", errorReportFileName))
+	if err != nil {
+		t.Fatalf("open error report: %v", err)
+	}
+	defer func() {
+		_ = report.Close()
+	}()
+	reportRows, err := report.GetRows(report.GetSheetName(0))
+	if err != nil {
+		t.Fatalf("read error report: %v", err)
+	}
+	if len(reportRows) != 4 {
+		t.Fatalf("expected a header and three failed rows, got %v", reportRows)
+	}
+	for _, row := range reportRows[1:] {
+		if row[0] == "" || row[len(row)-1] == "" {
+			t.Fatalf("expected failed rows to carry their row number and reason, got %v", row)
+		}
+	}
+}
+
+func TestServiceRunNextSkipsParcelsShippedByEarlierUpload(t *testing.T) {
+	pool := openShipmentImportTestPool(t)
+	resetShipmentImportTables(t, pool)
+	queries := db.New(pool)
+	ctx := context.Background()
+
+	order, item := seedConfirmedOrder(t, queries, 2)
+	workbook := buildShipmentWorkbook(t, [][]string{
+		{order.OrderNo, "YT-IMPORT-1", "圆通", "", item.ID.String(), "2"},
+	})
+	service := NewService(pool, nil, t.TempDir(), testMediaBaseURL, nil)
+	enqueueAndRun(t, service, workbook)
+	again := enqueueAndRun(t, service, workbook)
+
+	shipmentJob, err := queries.GetShipmentImportJob(ctx, again.ID)
+	if err != nil {
+		t.Fatalf("get shipment import job: %v", err)
+	}
+	if shipmentJob.SuccessRows != 1 || shipmentJob.FailedRows != 0 {
+		t.Fatalf("expected the repeated upload to succeed, got %+v", shipmentJob)
+	}
+	stored, err := queries.GetOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("get order: %v", err)
+	}
+	if stored.Status != string(oapi.OrderStatusSHIPPED) {
+		t.Fatalf("expected SHIPPED, got %s", stored.Status)
+	}
+	lines, err := queries.ListShipmentLinesByOrder(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("list shipment lines: %v", err)
+	}
+	if len(lines) != 1 {
+		t.Fatalf("expected the parcel to be recorded once, got %+v", lines)
+	}
+}
+
+func TestServiceRunNextMarksSheetWithoutRequiredHeadersAsFailed(t *testing.T) {
+	pool := openShipmentImportTestPool(t)
+	resetShipmentImportTables(t, pool)
+	queries := db.New(pool)
+
+	file := excelize.NewFile()
+	if err := file.SetSheetRow(file.GetSheetName(0), "A1", &[]string{"Order ID", "Carrier"}); err != nil {
+		t.Fatalf("write header: %v", err)
+	}
+	var buffer bytes.Buffer
+	if err := file.Write(&buffer); err != nil {
+		t.Fatalf("write workbook: %v", err)
+	}
+
+	service := NewService(pool, nil, t.TempDir(), testMediaBaseURL, nil)
+	job := enqueueAndRun(t, service, buffer.Bytes())
+	importJob, err := queries.GetImportJob(context.Background(), job.ID)
+	if err != nil {
+		t.Fatalf("get import job: %v", err)
+	}
+	if importJob.Status != string(oapi.FAILED) || importJob.ErrorReportUrl == nil {
+		t.Fatalf("expected a failed job with an error report, got %+v", importJob)
+	}
+}
+
+func enqueueAndRun(t *testing.T, service *Service, workbook []byte) db.ImportJob {
+	t.Helper()
+
+	ctx := context.Background()
+	job, err := service.Enqueue(ctx, EnqueueInput{
+		CreatedByUserID: pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		ExcelFile:       bytes.NewReader(workbook),
+		ExcelFileName:   "shipments.xlsx",
+	})
+	if err != nil {
+		t.Fatalf("enqueue shipment import: %v", err)
+	}
+	processed, err := service.RunNext(ctx)
+	if err != nil {
+		t.Fatalf("run next job: %v", err)
+	}
+	if !processed {
+		t.Fatal("expected a pending shipment import job to be processed")
+	}
+	return job
+}
+
+func seedConfirmedOrder(t *testing.T, queries *db.Queries, qty int32) (db.Order, db.OrderItem) {
+	t.Helper()
+
+	ctx := context.Background()
+	category, err := queries.CreateCategory(ctx, db.CreateCategoryParams{Name: "Pipes", Sort: 1})
+	if err != nil {
+		t.Fatalf("create category: %v", err)
+	}
+	product, err := queries.CreateProduct(ctx, db.CreateProductParams{
+		Name:             "Steel Pipe",
+		CategoryID:       category.ID,
+		Images:           []string{},
+		Tags:             []string{},
+		FilterDimensions: []string{},
+		Status:           "ACTIVE",
+	})
+	if err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	sku, err := queries.CreateSku(ctx, db.CreateSkuParams{
+		ProductID:  product.ID,
+		Name:       "Steel Pipe 1m",
+		Attributes: json.RawMessage(`{}`),
+		IsActive:   true,
+	})
+	if err != nil {
+		t.Fatalf("create sku: %v", err)
+	}
+	address, _ := json.Marshal(oapi.Address{ReceiverName: "A", ReceiverPhone: "1", Detail: "X"})
+	order, err := queries.CreateOrder(ctx, db.CreateOrderParams{
+		Status:        string(oapi.OrderStatusSUBMITTED),
+		CustomerID:    uuid.New(),
+		Address:       address,
+		PaymentStatus: string(oapi.OrderPaymentStatusUNPAID),
+	})
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	item, err := queries.CreateOrderItem(ctx, db.CreateOrderItemParams{
+		OrderID:      order.ID,
+		SkuID:        sku.ID,
+		Qty:          qty,
+		UnitPriceFen: 12000,
+	})
+	if err != nil {
+		t.Fatalf("create order item: %v", err)
+	}
+	channel := "OFFLINE"
+	order, err = queries.UpdateOrderPaymentSummary(ctx, db.UpdateOrderPaymentSummaryParams{
+		ID:             order.ID,
+		Status:         string(oapi.OrderStatusCONFIRMED),
+		PaymentStatus:  string(oapi.OrderPaymentStatusPAID),
+		PaymentChannel: &channel,
+		PaidAt:         pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("prepare confirmed order: %v", err)
+	}
+	return order, item
+}
+
+func openShipmentImportTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	dsn := os.Getenv("COMMERCE_DB_DSN")
+	if dsn == "" {
+		t.Skip("COMMERCE_DB_DSN is not set; skipping integration test")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("connect to database: %v", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		t.Fatalf("ping database: %v", err)
+	}
+
+	migrationsDir := filepath.Join("..", "..", "..", "migrations")
+	if err := db.ApplyMigrations(ctx, pool, migrationsDir); err != nil {
+		pool.Close()
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	t.Cleanup(func() {
+		pool.Close()
+	})
+	return pool
+}
+
+func resetShipmentImportTables(t *testing.T, pool *pgxpool.Pool) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := pool.Exec(ctx, `
+TRUNCATE shipment_import_rows,
+shipment_import_jobs,
+order_shipment_lines,
+shipment_events,
+order_tracking_shipments,
+import_jobs,
+order_items,
+orders,
+catalog_skus,
+catalog_products,
+catalog_categories
+RESTART IDENTITY CASCADE`)
+	if err != nil {
+		t.Fatalf("truncate tables: %v", err)
+	}
+}
+
+func buildShipmentWorkbook(t *testing.T, dataRows [][]string) []byte {
+	t.Helper()
+
+	file := excelize.NewFile()
+	sheet := file.GetSheetName(0)
+	allRows := append([][]string{excel.TemplateHeaders(excel.ShipmentImportTemplate())}, dataRows...)
+	for rowIndex, row := range allRows {
+		cell := "A" + strconv.Itoa(rowIndex+1)
+		values := make([]interface{}, len(row))
+		for index, value := range row {
+			values[index] = value
+		}
+		if err := file.SetSheetRow(sheet, cell, &values); err != nil {
+			t.Fatalf("set row: %v", err)
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := file.Write(&buffer); err != nil {
+		t.Fatalf("write workbook: %v", err)
+	}
+	return buffer.Bytes()
+}
@@ -0,0 +1,104 @@
+package shipping
+
+import (
+	"strings"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+const (
+	// ChargeWeight rules charge FirstFeeFen for the first FirstWeightGrams
+	// and AdditionalFeeFen for every started AdditionalWeightGrams beyond.
+	ChargeWeight = "WEIGHT"
+	// ChargeFlat rules charge FlatFeeFen per order.
+	ChargeFlat = "FLAT"
+)
+
+// Item is an order line as freight sees it. SKUs without a weight or volume
+// count as zero.
+type Item struct {
+	WeightGrams *int32
+	VolumeCm3   *int32
+	Qty         int32
+}
+
+// Match picks the rule for a destination: a rule for the city beats one for
+// the whole province, which beats the default rule without a province.
+// Province and city compare exactly after trimming spaces.
+func Match(rules []db.ShippingRule, province, city string) (db.ShippingRule, bool) {
+	province = strings.TrimSpace(province)
+	city = strings.TrimSpace(city)
+
+	best, bestRank := db.ShippingRule{}, 0
+	for _, rule := range rules {
+		rank := 0
+		switch {
+		case rule.Province == nil:
+			rank = 1
+		case *rule.Province != province:
+			continue
+		case rule.City == nil:
+			rank = 2
+		case *rule.City == city:
+			rank = 3
+		default:
+			continue
+		}
+		if rank > bestRank {
+			best, bestRank = rule, rank
+		}
+	}
+	return best, bestRank > 0
+}
+
+// BillableGrams is the weight a rule charges for: the total weight of the
+// items, or their volumetric weight when the rule sets a divisor (cm³ per
+// kg) and that comes out heavier.
+func BillableGrams(rule db.ShippingRule, items []Item) int64 {
+	var weightGrams, volumeCm3 int64
+	for _, item := range items {
+		if item.WeightGrams != nil {
+			weightGrams += int64(*item.WeightGrams) * int64(item.Qty)
+		}
+		if item.VolumeCm3 != nil {
+			volumeCm3 += int64(*item.VolumeCm3) * int64(item.Qty)
+		}
+	}
+	if rule.VolumetricDivisor != nil && *rule.VolumetricDivisor > 0 {
+		divisor := int64(*rule.VolumetricDivisor)
+		weightGrams = max(weightGrams, ceilDiv(volumeCm3*1000, divisor))
+	}
+	return weightGrams
+}
+
+// Fee works out the freight of items under rule. goodsFen is what the
+// customer pays for the goods after discounts; at or above the rule's free
+// shipping threshold the freight is waived.
+func Fee(rule db.ShippingRule, items []Item, goodsFen int64) int64 {
+	if rule.FreeShippingMinFen != nil && goodsFen >= *rule.FreeShippingMinFen {
+		return 0
+	}
+	if rule.ChargeType == ChargeFlat {
+		return deref(rule.FlatFeeFen)
+	}
+
+	feeFen := deref(rule.FirstFeeFen)
+	firstGrams := int64(deref(rule.FirstWeightGrams))
+	stepGrams := int64(deref(rule.AdditionalWeightGrams))
+	billable := BillableGrams(rule, items)
+	if billable > firstGrams && stepGrams > 0 {
+		feeFen += ceilDiv(billable-firstGrams, stepGrams) * deref(rule.AdditionalFeeFen)
+	}
+	return feeFen
+}
+
+func ceilDiv(value, divisor int64) int64 {
+	return (value + divisor - 1) / divisor
+}
+
+func deref[T int32 | int64](value *T) T {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
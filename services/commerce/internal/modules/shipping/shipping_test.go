@@ -0,0 +1,110 @@
+package shipping
+
+import (
+	"testing"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func int32Ptr(value int32) *int32 {
+	return &value
+}
+
+func int64Ptr(value int64) *int64 {
+	return &value
+}
+
+func TestMatch(t *testing.T) {
+	rules := []db.ShippingRule{
+		{Name: "default"},
+		{Name: "guangdong", Province: stringPtr("广东省")},
+		{Name: "shenzhen", Province: stringPtr("广东省"), City: stringPtr("深圳市")},
+		{Name: "hangzhou", Province: stringPtr("浙江省"), City: stringPtr("杭州市")},
+	}
+
+	cases := []struct {
+		province string
+		city     string
+		want     string
+	}{
+		{"广东省", "深圳市", "shenzhen"},
+		{" 广东省 ", "广州市", "guangdong"},
+		{"广东省", "", "guangdong"},
+		{"浙江省", "宁波市", "default"},
+		{"", "", "default"},
+	}
+	for _, tc := range cases {
+		got, ok := Match(rules, tc.province, tc.city)
+		if !ok || got.Name != tc.want {
+			t.Fatalf("Match(%q, %q) = %q, %v, want %q", tc.province, tc.city, got.Name, ok, tc.want)
+		}
+	}
+	if _, ok := Match(rules[1:], "浙江省", "宁波市"); ok {
+		t.Fatal("expected no rule without a default")
+	}
+}
+
+func TestFeeByWeight(t *testing.T) {
+	rule := db.ShippingRule{
+		ChargeType:            ChargeWeight,
+		FirstWeightGrams:      int32Ptr(1000),
+		FirstFeeFen:           int64Ptr(1200),
+		AdditionalWeightGrams: int32Ptr(500),
+		AdditionalFeeFen:      int64Ptr(300),
+		FreeShippingMinFen:    int64Ptr(100000),
+	}
+	items := []Item{
+		{WeightGrams: int32Ptr(600), Qty: 3},
+		{Qty: 5},
+	}
+
+	if got := BillableGrams(rule, items); got != 1800 {
+		t.Fatalf("expected 1800 billable grams, got %d", got)
+	}
+	// 800 grams over the first kilogram start two 500 gram steps.
+	if got := Fee(rule, items, 99999); got != 1800 {
+		t.Fatalf("expected fee 1800, got %d", got)
+	}
+	if got := Fee(rule, []Item{{WeightGrams: int32Ptr(200), Qty: 1}}, 0); got != 1200 {
+		t.Fatalf("expected the first weight fee, got %d", got)
+	}
+	if got := Fee(rule, items, 100000); got != 0 {
+		t.Fatalf("expected free shipping at the threshold, got %d", got)
+	}
+}
+
+func TestFeeByVolumetricWeight(t *testing.T) {
+	rule := db.ShippingRule{
+		ChargeType:            ChargeWeight,
+		FirstWeightGrams:      int32Ptr(1000),
+		FirstFeeFen:           int64Ptr(1000),
+		AdditionalWeightGrams: int32Ptr(1000),
+		AdditionalFeeFen:      int64Ptr(500),
+	}
+	items := []Item{{WeightGrams: int32Ptr(500), VolumeCm3: int32Ptr(12000), Qty: 1}}
+
+	if got := Fee(rule, items, 0); got != 1000 {
+		t.Fatalf("expected volume to be ignored without a divisor, got %d", got)
+	}
+	rule.VolumetricDivisor = int32Ptr(6000)
+	if got := BillableGrams(rule, items); got != 2000 {
+		t.Fatalf("expected 2000 volumetric grams, got %d", got)
+	}
+	if got := Fee(rule, items, 0); got != 1500 {
+		t.Fatalf("expected fee 1500, got %d", got)
+	}
+}
+
+func TestFeeFlat(t *testing.T) {
+	rule := db.ShippingRule{ChargeType: ChargeFlat, FlatFeeFen: int64Ptr(800), FreeShippingMinFen: int64Ptr(5000)}
+	if got := Fee(rule, []Item{{WeightGrams: int32Ptr(99000), Qty: 10}}, 4999); got != 800 {
+		t.Fatalf("expected flat fee 800, got %d", got)
+	}
+	if got := Fee(rule, nil, 5000); got != 0 {
+		t.Fatalf("expected free shipping at the threshold, got %d", got)
+	}
+}
@@ -0,0 +1,19 @@
+package shipping
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+type Store interface {
+	CreateShippingRule(ctx context.Context, arg db.CreateShippingRuleParams) (db.ShippingRule, error)
+	GetShippingRule(ctx context.Context, id uuid.UUID) (db.ShippingRule, error)
+	ListShippingRules(ctx context.Context, arg db.ListShippingRulesParams) ([]db.ShippingRule, error)
+	CountShippingRules(ctx context.Context) (int64, error)
+	UpdateShippingRule(ctx context.Context, arg db.UpdateShippingRuleParams) (db.ShippingRule, error)
+	DeleteShippingRule(ctx context.Context, id uuid.UUID) (int64, error)
+	ListActiveShippingRules(ctx context.Context, province string) ([]db.ShippingRule, error)
+}
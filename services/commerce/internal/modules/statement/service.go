@@ -0,0 +1,190 @@
+package statement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+const statementDir = "statements"
+
+type Service struct {
+	Store               JobStore
+	MediaLocalOutputDir string
+	// DB and Outbox, when both are set, complete statements in a transaction
+	// that publishes statement.generated.
+	DB     *pgxpool.Pool
+	Outbox *events.Outbox
+}
+
+func NewService(store JobStore, mediaLocalOutputDir string) *Service {
+	return &Service{
+		Store:               store,
+		MediaLocalOutputDir: mediaLocalOutputDir,
+	}
+}
+
+func (s *Service) ResetStaleRunning(ctx context.Context) error {
+	if s == nil || s.Store == nil {
+		return nil
+	}
+	_, err := s.Store.ResetRunningCustomerStatements(ctx)
+	return err
+}
+
+// RunNext builds the oldest pending statement. A statement that fails is
+// marked FAILED with the reason and the error is returned for logging.
+func (s *Service) RunNext(ctx context.Context) (bool, error) {
+	if s == nil || s.Store == nil {
+		return false, nil
+	}
+
+	statement, err := s.Store.ClaimNextPendingCustomerStatement(ctx)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("claim pending customer statement: %w", err)
+	}
+
+	if err := s.generate(ctx, statement); err != nil {
+		message := err.Error()
+		if _, failErr := s.Store.FailCustomerStatement(ctx, db.FailCustomerStatementParams{
+			ID:           statement.ID,
+			ErrorMessage: &message,
+		}); failErr != nil && !errors.Is(failErr, pgx.ErrNoRows) {
+			return true, fmt.Errorf("mark customer statement %s failed: %w", statement.ID, failErr)
+		}
+		return true, fmt.Errorf("generate customer statement %s: %w", statement.ID, err)
+	}
+	return true, nil
+}
+
+func (s *Service) generate(ctx context.Context, statement db.CustomerStatement) error {
+	if strings.TrimSpace(s.MediaLocalOutputDir) == "" {
+		return errors.New("media output is not configured")
+	}
+
+	period := Period{Label: statement.Period, Start: statement.PeriodStart.Time, End: statement.PeriodEnd.Time}
+	opening, err := s.Store.SumStatementOpeningBalance(ctx, db.SumStatementOpeningBalanceParams{
+		CustomerID:  statement.CustomerID,
+		PeriodStart: statement.PeriodStart,
+	})
+	if err != nil {
+		return fmt.Errorf("sum opening balance: %w", err)
+	}
+	receivables, err := s.Store.ListStatementReceivables(ctx, db.ListStatementReceivablesParams{
+		CustomerID:  statement.CustomerID,
+		PeriodStart: statement.PeriodStart,
+		PeriodEnd:   statement.PeriodEnd,
+	})
+	if err != nil {
+		return fmt.Errorf("list receivables: %w", err)
+	}
+	orders, err := s.Store.ListStatementOrders(ctx, db.ListStatementOrdersParams{
+		CustomerID:  statement.CustomerID,
+		PeriodStart: statement.PeriodStart,
+		PeriodEnd:   statement.PeriodEnd,
+	})
+	if err != nil {
+		return fmt.Errorf("list orders: %w", err)
+	}
+	items, err := s.Store.ListStatementOrderItems(ctx, db.ListStatementOrderItemsParams{
+		CustomerID:  statement.CustomerID,
+		PeriodStart: statement.PeriodStart,
+		PeriodEnd:   statement.PeriodEnd,
+	})
+	if err != nil {
+		return fmt.Errorf("list order items: %w", err)
+	}
+
+	summary := Summarize(opening, period, receivables)
+	relativePath := FilePath(statement)
+	if err := writeWorkbook(LocalPath(s.MediaLocalOutputDir, relativePath), workbookData{
+		Statement:   statement,
+		Period:      period,
+		Summary:     summary,
+		Orders:      orders,
+		Items:       items,
+		Receivables: receivables,
+	}); err != nil {
+		return fmt.Errorf("write statement workbook: %w", err)
+	}
+
+	err = s.complete(ctx, db.CompleteCustomerStatementParams{
+		ID:                statement.ID,
+		OpeningBalanceFen: summary.OpeningBalanceFen,
+		ChargesFen:        summary.ChargesFen,
+		PaymentsFen:       summary.PaymentsFen,
+		AdjustmentsFen:    summary.AdjustmentsFen,
+		ClosingBalanceFen: summary.ClosingBalanceFen,
+		FilePath:          &relativePath,
+	})
+	// No rows means the statement was requested again while it was running;
+	// it is back to PENDING and will be rebuilt.
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("complete customer statement: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) complete(ctx context.Context, arg db.CompleteCustomerStatementParams) error {
+	if s.DB == nil || s.Outbox == nil {
+		_, err := s.Store.CompleteCustomerStatement(ctx, arg)
+		return err
+	}
+	return shareddb.WithTx(ctx, s.DB, func(tx pgx.Tx) error {
+		row, err := db.New(tx).CompleteCustomerStatement(ctx, arg)
+		if err != nil {
+			return err
+		}
+		return s.Outbox.Publish(ctx, tx, events.TypeStatementGenerated, row.ID.String(), events.StatementGenerated{
+			StatementID:       row.ID.String(),
+			CustomerID:        row.CustomerID.String(),
+			Period:            row.Period,
+			OpeningBalanceFen: row.OpeningBalanceFen,
+			ChargesFen:        row.ChargesFen,
+			PaymentsFen:       row.PaymentsFen,
+			AdjustmentsFen:    row.AdjustmentsFen,
+			ClosingBalanceFen: row.ClosingBalanceFen,
+			GeneratedAt:       row.GeneratedAt.Time.UTC(),
+		})
+	})
+}
+
+// FilePath is the statement workbook path relative to the media output dir.
+func FilePath(statement db.CustomerStatement) string {
+	return path.Join(statementDir, statement.CustomerID.String(), statement.ID.String(), FileName(statement.Period))
+}
+
+// FileName is the download name of a statement workbook.
+func FileName(period string) string {
+	return "statement-" + period + ".xlsx"
+}
+
+// LocalPath resolves a stored statement path under the media output dir.
+func LocalPath(mediaLocalOutputDir, relativePath string) string {
+	cleaned := path.Clean("/" + relativePath)
+	return filepath.Join(mediaLocalOutputDir, filepath.FromSlash(strings.TrimPrefix(cleaned, "/")))
+}
+
+// FileExists reports whether the workbook of a finished statement is still on
+// disk.
+func FileExists(mediaLocalOutputDir string, filePath *string) bool {
+	if filePath == nil || strings.TrimSpace(*filePath) == "" {
+		return false
+	}
+	info, err := os.Stat(LocalPath(mediaLocalOutputDir, *filePath))
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,189 @@
+package statement
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/excel"
+)
+
+type fakeJobStore struct {
+	pending     []db.CustomerStatement
+	opening     int64
+	receivables []db.ListStatementReceivablesRow
+	orders      []db.Order
+	items       []db.ListStatementOrderItemsRow
+	completed   []db.CompleteCustomerStatementParams
+	failed      []db.FailCustomerStatementParams
+}
+
+func (f *fakeJobStore) ClaimNextPendingCustomerStatement(context.Context) (db.CustomerStatement, error) {
+	if len(f.pending) == 0 {
+		return db.CustomerStatement{}, pgx.ErrNoRows
+	}
+	next := f.pending[0]
+	f.pending = f.pending[1:]
+	next.Status = StatusRunning
+	return next, nil
+}
+
+func (f *fakeJobStore) ResetRunningCustomerStatements(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeJobStore) CompleteCustomerStatement(_ context.Context, arg db.CompleteCustomerStatementParams) (db.CustomerStatement, error) {
+	f.completed = append(f.completed, arg)
+	return db.CustomerStatement{ID: arg.ID, Status: StatusSucceeded}, nil
+}
+
+func (f *fakeJobStore) FailCustomerStatement(_ context.Context, arg db.FailCustomerStatementParams) (db.CustomerStatement, error) {
+	f.failed = append(f.failed, arg)
+	return db.CustomerStatement{ID: arg.ID, Status: StatusFailed}, nil
+}
+
+func (f *fakeJobStore) SumStatementOpeningBalance(context.Context, db.SumStatementOpeningBalanceParams) (int64, error) {
+	return f.opening, nil
+}
+
+func (f *fakeJobStore) ListStatementReceivables(context.Context, db.ListStatementReceivablesParams) ([]db.ListStatementReceivablesRow, error) {
+	return f.receivables, nil
+}
+
+func (f *fakeJobStore) ListStatementOrders(context.Context, db.ListStatementOrdersParams) ([]db.Order, error) {
+	return f.orders, nil
+}
+
+func (f *fakeJobStore) ListStatementOrderItems(context.Context, db.ListStatementOrderItemsParams) ([]db.ListStatementOrderItemsRow, error) {
+	return f.items, nil
+}
+
+func pendingStatement(t *testing.T, label string) db.CustomerStatement {
+	t.Helper()
+	period, err := ParsePeriod(label)
+	if err != nil {
+		t.Fatalf("parse period: %v", err)
+	}
+	return db.CustomerStatement{
+		ID:          uuid.New(),
+		CustomerID:  uuid.New(),
+		Period:      period.Label,
+		PeriodStart: pgtype.Timestamptz{Time: period.Start, Valid: true},
+		PeriodEnd:   pgtype.Timestamptz{Time: period.End, Valid: true},
+		Status:      StatusPending,
+	}
+}
+
+func TestRunNextWritesStatementWorkbook(t *testing.T) {
+	mediaDir := t.TempDir()
+	statement := pendingStatement(t, "2026-09")
+	orderID := uuid.New()
+	paymentID := uuid.New()
+	channel := "OFFLINE"
+	postedAt := pgtype.Timestamptz{Time: statement.PeriodStart.Time.AddDate(0, 0, 2), Valid: true}
+	settledAt := pgtype.Timestamptz{Time: statement.PeriodStart.Time.AddDate(0, 0, 9), Valid: true}
+	store := &fakeJobStore{
+		pending: []db.CustomerStatement{statement},
+		opening: 10000,
+		receivables: []db.ListStatementReceivablesRow{{
+			OrderID: orderID, CustomerID: statement.CustomerID, AmountFen: 2500, Status: "SETTLED",
+			PostedAt: postedAt, SettledAt: settledAt, PaymentChannel: &channel,
+			LatestPaymentID: pgtype.UUID{Bytes: paymentID, Valid: true},
+		}},
+		orders: []db.Order{{
+			ID: orderID, CustomerID: statement.CustomerID, Status: "DELIVERED", PaymentStatus: "PAID",
+			PaymentChannel: &channel, CreatedAt: postedAt, OrderNo: "TMO20260915-000001",
+		}},
+		items: []db.ListStatementOrderItemsRow{
+			{OrderID: orderID, ProductName: "Bolt", SkuName: "M8", Qty: 10, UnitPriceFen: 150},
+			{OrderID: orderID, ProductName: "Nut", SkuName: "M8", Qty: 20, UnitPriceFen: 50},
+		},
+	}
+	service := NewService(store, mediaDir)
+
+	processed, err := service.RunNext(context.Background())
+	if err != nil || !processed {
+		t.Fatalf("expected statement to be processed, got processed=%v err=%v", processed, err)
+	}
+	if len(store.completed) != 1 {
+		t.Fatalf("expected statement to be completed, got %+v", store.completed)
+	}
+	completed := store.completed[0]
+	if completed.ChargesFen != 2500 || completed.PaymentsFen != 2500 || completed.ClosingBalanceFen != 10000 {
+		t.Fatalf("unexpected totals: %+v", completed)
+	}
+	if completed.FilePath == nil || *completed.FilePath != FilePath(statement) {
+		t.Fatalf("expected file path %q, got %v", FilePath(statement), completed.FilePath)
+	}
+	if !FileExists(mediaDir, completed.FilePath) {
+		t.Fatalf("expected workbook to exist")
+	}
+
+	file, err := excelize.OpenFile(LocalPath(mediaDir, *completed.FilePath))
+	if err != nil {
+		t.Fatalf("open workbook: %v", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	closing, err := file.GetCellValue(summarySheet, "B9")
+	if err != nil || closing != "100.00" {
+		t.Fatalf("expected closing balance 100.00, got %q (%v)", closing, err)
+	}
+	orderRows, err := file.GetRows(excel.StatementOrdersTemplate().SheetName)
+	if err != nil || len(orderRows) != 2 || orderRows[1][1] != "TMO20260915-000001" || orderRows[1][6] != "25.00" {
+		t.Fatalf("unexpected order rows %v (%v)", orderRows, err)
+	}
+	itemRows, err := file.GetRows(excel.StatementItemsTemplate().SheetName)
+	if err != nil || len(itemRows) != 3 {
+		t.Fatalf("expected two item rows, got %v (%v)", itemRows, err)
+	}
+	paymentRows, err := file.GetRows(excel.StatementPaymentsTemplate().SheetName)
+	if err != nil || len(paymentRows) != 2 || paymentRows[1][3] != paymentID.String() {
+		t.Fatalf("unexpected payment rows %v (%v)", paymentRows, err)
+	}
+	adjustmentRows, err := file.GetRows(excel.StatementAdjustmentsTemplate().SheetName)
+	if err != nil || len(adjustmentRows) != 1 {
+		t.Fatalf("expected header-only adjustments, got %v (%v)", adjustmentRows, err)
+	}
+
+	if _, err := os.Stat(LocalPath(mediaDir, *completed.FilePath) + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("expected temp file to be renamed, got %v", err)
+	}
+
+	processed, err = service.RunNext(context.Background())
+	if err != nil || processed {
+		t.Fatalf("expected empty queue, got processed=%v err=%v", processed, err)
+	}
+}
+
+func TestRunNextMarksStatementFailed(t *testing.T) {
+	store := &fakeJobStore{pending: []db.CustomerStatement{pendingStatement(t, "2026-09")}}
+	service := NewService(store, "")
+
+	processed, err := service.RunNext(context.Background())
+	if !processed || err == nil {
+		t.Fatalf("expected failure to be reported, got processed=%v err=%v", processed, err)
+	}
+	if len(store.failed) != 1 || store.failed[0].ErrorMessage == nil || !strings.Contains(*store.failed[0].ErrorMessage, "media output") {
+		t.Fatalf("expected statement to be marked failed, got %+v", store.failed)
+	}
+	if len(store.completed) != 0 {
+		t.Fatalf("expected no completion, got %+v", store.completed)
+	}
+}
+
+func TestLocalPathStaysUnderMediaDir(t *testing.T) {
+	got := LocalPath("/srv/media", "../../etc/passwd")
+	if got != "/srv/media/etc/passwd" {
+		t.Fatalf("expected path to stay under media dir, got %q", got)
+	}
+}
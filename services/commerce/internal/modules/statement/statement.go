@@ -0,0 +1,77 @@
+package statement
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+const (
+	StatusPending   = "PENDING"
+	StatusRunning   = "RUNNING"
+	StatusSucceeded = "SUCCEEDED"
+	StatusFailed    = "FAILED"
+)
+
+const periodLayout = "2006-01"
+
+var ErrInvalidPeriod = errors.New("period must be formatted as YYYY-MM")
+
+// Billing periods are calendar months in China Standard Time.
+var periodLocation = time.FixedZone("CST", 8*60*60)
+
+// Period is one billing month, covering [Start, End).
+type Period struct {
+	Label string
+	Start time.Time
+	End   time.Time
+}
+
+// ParsePeriod parses a billing period written as YYYY-MM.
+func ParsePeriod(raw string) (Period, error) {
+	month, err := time.ParseInLocation(periodLayout, strings.TrimSpace(raw), periodLocation)
+	if err != nil {
+		return Period{}, ErrInvalidPeriod
+	}
+	return Period{
+		Label: month.Format(periodLayout),
+		Start: month,
+		End:   month.AddDate(0, 1, 0),
+	}, nil
+}
+
+// Summary is the balance movement of one customer over a billing period.
+// Adjustments are signed; voided receivables reduce the balance.
+type Summary struct {
+	OpeningBalanceFen int64
+	ChargesFen        int64
+	PaymentsFen       int64
+	AdjustmentsFen    int64
+	ClosingBalanceFen int64
+}
+
+// Summarize rolls the opening balance forward with the receivables that were
+// posted, settled or voided within the period. A receivable can contribute to
+// several lines, e.g. an order posted and settled in the same month.
+func Summarize(openingBalanceFen int64, period Period, receivables []db.ListStatementReceivablesRow) Summary {
+	summary := Summary{OpeningBalanceFen: openingBalanceFen}
+	for _, row := range receivables {
+		if within(period, row.PostedAt.Time, row.PostedAt.Valid) {
+			summary.ChargesFen += row.AmountFen
+		}
+		if within(period, row.SettledAt.Time, row.SettledAt.Valid) {
+			summary.PaymentsFen += row.AmountFen
+		}
+		if within(period, row.VoidedAt.Time, row.VoidedAt.Valid) {
+			summary.AdjustmentsFen -= row.AmountFen
+		}
+	}
+	summary.ClosingBalanceFen = summary.OpeningBalanceFen + summary.ChargesFen - summary.PaymentsFen + summary.AdjustmentsFen
+	return summary
+}
+
+func within(period Period, value time.Time, valid bool) bool {
+	return valid && !value.Before(period.Start) && value.Before(period.End)
+}
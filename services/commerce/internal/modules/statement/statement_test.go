@@ -0,0 +1,67 @@
+package statement
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+func TestParsePeriodUsesChinaStandardTimeMonths(t *testing.T) {
+	period, err := ParsePeriod(" 2026-12 ")
+	if err != nil {
+		t.Fatalf("parse period: %v", err)
+	}
+	if period.Label != "2026-12" {
+		t.Fatalf("expected label 2026-12, got %q", period.Label)
+	}
+	if want := time.Date(2026, 11, 30, 16, 0, 0, 0, time.UTC); !period.Start.Equal(want) {
+		t.Fatalf("expected start %s, got %s", want, period.Start.UTC())
+	}
+	if want := time.Date(2026, 12, 31, 16, 0, 0, 0, time.UTC); !period.End.Equal(want) {
+		t.Fatalf("expected end %s, got %s", want, period.End.UTC())
+	}
+
+	for _, raw := range []string{"", "2026-13", "2026/09", "2026-9", "2026-09-01"} {
+		if _, err := ParsePeriod(raw); !errors.Is(err, ErrInvalidPeriod) {
+			t.Fatalf("expected %q to be rejected, got %v", raw, err)
+		}
+	}
+}
+
+func TestSummarizeRollsBalanceForward(t *testing.T) {
+	period, err := ParsePeriod("2026-09")
+	if err != nil {
+		t.Fatalf("parse period: %v", err)
+	}
+	inPeriod := func(day int) pgtype.Timestamptz {
+		return pgtype.Timestamptz{Time: period.Start.AddDate(0, 0, day), Valid: true}
+	}
+	before := pgtype.Timestamptz{Time: period.Start.Add(-time.Hour), Valid: true}
+	after := pgtype.Timestamptz{Time: period.End, Valid: true}
+
+	summary := Summarize(5000, period, []db.ListStatementReceivablesRow{
+		// Carried over from last month and paid this month.
+		{AmountFen: 3000, PostedAt: before, SettledAt: inPeriod(3)},
+		// Posted this month and still open.
+		{AmountFen: 1200, PostedAt: inPeriod(1)},
+		// Posted and voided this month.
+		{AmountFen: 800, PostedAt: inPeriod(2), VoidedAt: inPeriod(4)},
+		// Posted this month, settled next month.
+		{AmountFen: 400, PostedAt: inPeriod(5), SettledAt: after},
+	})
+
+	want := Summary{
+		OpeningBalanceFen: 5000,
+		ChargesFen:        2400,
+		PaymentsFen:       3000,
+		AdjustmentsFen:    -800,
+		ClosingBalanceFen: 3600,
+	}
+	if summary != want {
+		t.Fatalf("expected %+v, got %+v", want, summary)
+	}
+}
@@ -0,0 +1,28 @@
+package statement
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+type Store interface {
+	EnqueueCustomerStatement(ctx context.Context, arg db.EnqueueCustomerStatementParams) (db.CustomerStatement, error)
+	GetCustomerStatement(ctx context.Context, id uuid.UUID) (db.CustomerStatement, error)
+	ListCustomerStatements(ctx context.Context, arg db.ListCustomerStatementsParams) ([]db.CustomerStatement, error)
+	CountCustomerStatements(ctx context.Context, arg db.CountCustomerStatementsParams) (int64, error)
+}
+
+// JobStore is what the statement worker needs to claim and build statements.
+type JobStore interface {
+	ClaimNextPendingCustomerStatement(ctx context.Context) (db.CustomerStatement, error)
+	ResetRunningCustomerStatements(ctx context.Context) (int64, error)
+	CompleteCustomerStatement(ctx context.Context, arg db.CompleteCustomerStatementParams) (db.CustomerStatement, error)
+	FailCustomerStatement(ctx context.Context, arg db.FailCustomerStatementParams) (db.CustomerStatement, error)
+	SumStatementOpeningBalance(ctx context.Context, arg db.SumStatementOpeningBalanceParams) (int64, error)
+	ListStatementReceivables(ctx context.Context, arg db.ListStatementReceivablesParams) ([]db.ListStatementReceivablesRow, error)
+	ListStatementOrders(ctx context.Context, arg db.ListStatementOrdersParams) ([]db.Order, error)
+	ListStatementOrderItems(ctx context.Context, arg db.ListStatementOrderItemsParams) ([]db.ListStatementOrderItemsRow, error)
+}
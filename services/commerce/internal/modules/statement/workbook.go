@@ -0,0 +1,224 @@
+package statement
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	"github.com/teamdsb/tmo/services/commerce/internal/excel"
+)
+
+const (
+	summarySheet    = "对账单"
+	adjustmentVoid  = "订单关闭冲销"
+	amountNumFormat = "#,##0.00"
+)
+
+type workbookData struct {
+	Statement   db.CustomerStatement
+	Period      Period
+	Summary     Summary
+	Orders      []db.Order
+	Items       []db.ListStatementOrderItemsRow
+	Receivables []db.ListStatementReceivablesRow
+}
+
+// writeWorkbook saves the statement next to its final path first and renames
+// it into place, so a download never sees a half-written file.
+func writeWorkbook(localPath string, data workbookData) error {
+	file := excelize.NewFile()
+	defer func() {
+		_ = file.Close()
+	}()
+
+	amountFormat := amountNumFormat
+	amountStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: &amountFormat})
+	if err != nil {
+		return err
+	}
+
+	if err := file.SetSheetName(file.GetSheetName(0), summarySheet); err != nil {
+		return err
+	}
+	if err := writeSummarySheet(file, amountStyle, data); err != nil {
+		return err
+	}
+
+	orderTotals := make(map[uuid.UUID]int64, len(data.Orders))
+	itemRows := make([][]any, 0, len(data.Items))
+	for _, item := range data.Items {
+		amount := item.UnitPriceFen * int64(item.Qty)
+		orderTotals[item.OrderID] += amount
+		itemRows = append(itemRows, []any{
+			item.OrderID.String(),
+			optional(item.SkuCode),
+			item.ProductName,
+			item.SkuName,
+			optional(item.Spec),
+			optional(item.Unit),
+			item.Qty,
+			yuan(item.UnitPriceFen),
+			yuan(amount),
+		})
+	}
+
+	orderRows := make([][]any, 0, len(data.Orders))
+	for _, order := range data.Orders {
+		orderRows = append(orderRows, []any{
+			order.ID.String(),
+			order.OrderNo,
+			timestamptz(order.CreatedAt),
+			order.Status,
+			order.PaymentStatus,
+			optional(order.PaymentChannel),
+			yuan(orderTotals[order.ID]),
+			optional(order.Remark),
+		})
+	}
+
+	paymentRows := make([][]any, 0)
+	adjustmentRows := make([][]any, 0)
+	for _, row := range data.Receivables {
+		if within(data.Period, row.SettledAt.Time, row.SettledAt.Valid) {
+			paymentRows = append(paymentRows, []any{
+				row.OrderID.String(),
+				timestamptz(row.SettledAt),
+				optional(row.PaymentChannel),
+				uuidString(row.LatestPaymentID),
+				yuan(row.AmountFen),
+			})
+		}
+		if within(data.Period, row.VoidedAt.Time, row.VoidedAt.Valid) {
+			adjustmentRows = append(adjustmentRows, []any{
+				row.OrderID.String(),
+				timestamptz(row.VoidedAt),
+				adjustmentVoid,
+				yuan(-row.AmountFen),
+			})
+		}
+	}
+
+	sheets := []struct {
+		spec         excel.TemplateSpec
+		rows         [][]any
+		amountColumn []int
+	}{
+		{spec: excel.StatementOrdersTemplate(), rows: orderRows, amountColumn: []int{7}},
+		{spec: excel.StatementItemsTemplate(), rows: itemRows, amountColumn: []int{8, 9}},
+		{spec: excel.StatementPaymentsTemplate(), rows: paymentRows, amountColumn: []int{5}},
+		{spec: excel.StatementAdjustmentsTemplate(), rows: adjustmentRows, amountColumn: []int{4}},
+	}
+	for _, sheet := range sheets {
+		if err := writeTableSheet(file, amountStyle, sheet.spec, sheet.rows, sheet.amountColumn); err != nil {
+			return err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+		return err
+	}
+	tempPath := localPath + ".tmp"
+	output, err := os.Create(tempPath)
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteTo(output); err != nil {
+		_ = output.Close()
+		_ = os.Remove(tempPath)
+		return err
+	}
+	if err := output.Close(); err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+	return os.Rename(tempPath, localPath)
+}
+
+func writeSummarySheet(file *excelize.File, amountStyle int, data workbookData) error {
+	rows := [][]any{
+		{"客户ID", data.Statement.CustomerID.String()},
+		{"账期", data.Period.Label},
+		{"账期开始", data.Period.Start.In(periodLocation).Format("2006-01-02")},
+		{"账期结束", data.Period.End.AddDate(0, 0, -1).In(periodLocation).Format("2006-01-02")},
+		{"期初余额(元)", yuan(data.Summary.OpeningBalanceFen)},
+		{"本期应收(元)", yuan(data.Summary.ChargesFen)},
+		{"本期收款(元)", yuan(data.Summary.PaymentsFen)},
+		{"本期调整(元)", yuan(data.Summary.AdjustmentsFen)},
+		{"期末余额(元)", yuan(data.Summary.ClosingBalanceFen)},
+	}
+	for index, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, index+1)
+		if err != nil {
+			return err
+		}
+		if err := file.SetSheetRow(summarySheet, cell, &row); err != nil {
+			return err
+		}
+	}
+	return file.SetCellStyle(summarySheet, "B5", "B9", amountStyle)
+}
+
+func writeTableSheet(file *excelize.File, amountStyle int, spec excel.TemplateSpec, rows [][]any, amountColumns []int) error {
+	if _, err := file.NewSheet(spec.SheetName); err != nil {
+		return err
+	}
+	headers := excel.TemplateHeaders(spec)
+	if err := file.SetSheetRow(spec.SheetName, "A1", &headers); err != nil {
+		return err
+	}
+	for index, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, index+2)
+		if err != nil {
+			return err
+		}
+		if err := file.SetSheetRow(spec.SheetName, cell, &row); err != nil {
+			return err
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	for _, column := range amountColumns {
+		top, err := excelize.CoordinatesToCellName(column, 2)
+		if err != nil {
+			return err
+		}
+		bottom, err := excelize.CoordinatesToCellName(column, len(rows)+1)
+		if err != nil {
+			return err
+		}
+		if err := file.SetCellStyle(spec.SheetName, top, bottom, amountStyle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func timestamptz(value pgtype.Timestamptz) string {
+	if !value.Valid {
+		return ""
+	}
+	return value.Time.In(periodLocation).Format("2006-01-02 15:04:05")
+}
+
+func yuan(fen int64) float64 {
+	return float64(fen) / 100
+}
+
+func optional(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func uuidString(value pgtype.UUID) string {
+	if !value.Valid {
+		return ""
+	}
+	return uuid.UUID(value.Bytes).String()
+}
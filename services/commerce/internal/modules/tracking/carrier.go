@@ -0,0 +1,221 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	StatusPending   = "PENDING"
+	StatusInTransit = "IN_TRANSIT"
+	StatusDelivered = "DELIVERED"
+	StatusException = "EXCEPTION"
+
+	SourcePoll = "POLL"
+	SourcePush = "PUSH"
+)
+
+var (
+	// ErrWaybillNotFound is returned by QueryTrace when the carrier does not
+	// know the waybill yet, which is common right after shipping.
+	ErrWaybillNotFound = errors.New("waybill not found at carrier")
+	// ErrUnauthorizedPush is returned by ParsePush when the callback cannot be
+	// verified as coming from the carrier.
+	ErrUnauthorizedPush = errors.New("carrier push could not be verified")
+	ErrInvalidPush      = errors.New("invalid carrier push")
+	ErrInvalidEvent     = errors.New("invalid trace event")
+)
+
+// TraceEvent is one step of a parcel's journey as reported by the carrier.
+type TraceEvent struct {
+	Status      string
+	Description string
+	Location    string
+	OccurredAt  time.Time
+}
+
+// PushUpdate is the trace a carrier pushed for one waybill.
+type PushUpdate struct {
+	WaybillNo string
+	Events    []TraceEvent
+}
+
+// CarrierAdapter talks to one carrier's tracking API. Adapters translate the
+// carrier's own status codes into the Status* constants.
+type CarrierAdapter interface {
+	// Code identifies the carrier, e.g. "SF".
+	Code() string
+	// QueryTrace returns every event the carrier knows for waybillNo.
+	QueryTrace(ctx context.Context, waybillNo string) ([]TraceEvent, error)
+	// ParsePush verifies and decodes a push callback sent by the carrier.
+	ParsePush(header http.Header, body []byte) ([]PushUpdate, error)
+}
+
+// Registry resolves the free-text carrier stored on a shipment to an adapter.
+type Registry struct {
+	mu       sync.RWMutex
+	adapters map[string]CarrierAdapter
+	fallback CarrierAdapter
+}
+
+func NewRegistry() *Registry {
+	return &Registry{adapters: make(map[string]CarrierAdapter)}
+}
+
+// Register makes adapter available under its code and the given aliases,
+// such as the Chinese carrier name staff type when shipping.
+func (r *Registry) Register(adapter CarrierAdapter, aliases ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.adapters[normalizeCarrier(adapter.Code())] = adapter
+	for _, alias := range aliases {
+		if key := normalizeCarrier(alias); key != "" {
+			r.adapters[key] = adapter
+		}
+	}
+}
+
+// SetFallback serves every carrier without a registered adapter, which lets
+// local environments track all shipments through a FixtureAdapter.
+func (r *Registry) SetFallback(adapter CarrierAdapter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = adapter
+}
+
+// Configured reports whether any carrier can be tracked.
+func (r *Registry) Configured() bool {
+	if r == nil {
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.adapters) > 0 || r.fallback != nil
+}
+
+// Lookup returns the adapter for carrier.
+func (r *Registry) Lookup(carrier string) (CarrierAdapter, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if adapter, ok := r.adapters[normalizeCarrier(carrier)]; ok {
+		return adapter, true
+	}
+	if known, ok := KnownCarrier(carrier); ok {
+		if adapter, ok := r.adapters[normalizeCarrier(known.Code)]; ok {
+			return adapter, true
+		}
+	}
+	if r.fallback != nil && strings.TrimSpace(carrier) != "" {
+		return r.fallback, true
+	}
+	return nil, false
+}
+
+// Carrier is a courier the business ships with. Staff name carriers in many
+// ways, so Aliases lists the spellings that resolve to it.
+type Carrier struct {
+	Code    string
+	Name    string
+	Aliases []string
+}
+
+var knownCarriers = []Carrier{
+	{Code: "SF", Name: "顺丰速运", Aliases: []string{"顺丰", "顺丰快递", "SF Express"}},
+	{Code: "ZTO", Name: "中通快递", Aliases: []string{"中通", "ZTO Express"}},
+	{Code: "YTO", Name: "圆通速递", Aliases: []string{"圆通", "圆通快递", "YTO Express"}},
+	{Code: "STO", Name: "申通快递", Aliases: []string{"申通", "STO Express"}},
+	{Code: "YUNDA", Name: "韵达快递", Aliases: []string{"韵达", "韵达速递", "Yunda Express"}},
+	{Code: "JD", Name: "京东物流", Aliases: []string{"京东", "京东快递", "JD Logistics"}},
+	{Code: "EMS", Name: "中国邮政", Aliases: []string{"邮政", "邮政EMS", "China Post"}},
+	{Code: "DBL", Name: "德邦快递", Aliases: []string{"德邦", "德邦物流", "Deppon"}},
+	{Code: "JTSD", Name: "极兔速递", Aliases: []string{"极兔", "J&T", "J&T Express"}},
+}
+
+// KnownCarrier resolves a carrier code, name or alias, ignoring case and
+// surrounding spaces.
+func KnownCarrier(name string) (Carrier, bool) {
+	key := normalizeCarrier(name)
+	if key == "" {
+		return Carrier{}, false
+	}
+	for _, carrier := range knownCarriers {
+		if key == normalizeCarrier(carrier.Code) || key == normalizeCarrier(carrier.Name) {
+			return carrier, true
+		}
+		for _, alias := range carrier.Aliases {
+			if key == normalizeCarrier(alias) {
+				return carrier, true
+			}
+		}
+	}
+	return Carrier{}, false
+}
+
+func normalizeCarrier(carrier string) string {
+	return strings.ToUpper(strings.TrimSpace(carrier))
+}
+
+// NormalizeStatus maps a trace status to one of the Status* constants.
+func NormalizeStatus(status string) (string, bool) {
+	status = strings.ToUpper(strings.TrimSpace(status))
+	switch status {
+	case StatusPending, StatusInTransit, StatusDelivered, StatusException:
+		return status, true
+	default:
+		return "", false
+	}
+}
+
+// NormalizeEvent validates an event reported by an adapter.
+func NormalizeEvent(event TraceEvent) (TraceEvent, error) {
+	status, ok := NormalizeStatus(event.Status)
+	if !ok {
+		return TraceEvent{}, fmt.Errorf("%w: unknown status %q", ErrInvalidEvent, event.Status)
+	}
+	if event.OccurredAt.IsZero() {
+		return TraceEvent{}, fmt.Errorf("%w: occurredAt is required", ErrInvalidEvent)
+	}
+	event.Status = status
+	event.Description = strings.TrimSpace(event.Description)
+	if event.Description == "" {
+		event.Description = status
+	}
+	event.Location = strings.TrimSpace(event.Location)
+	event.OccurredAt = event.OccurredAt.UTC()
+	return event, nil
+}
+
+// Summary is the tracking state derived from a trace.
+type Summary struct {
+	Status      string
+	LastEventAt time.Time
+	DeliveredAt time.Time
+}
+
+// Summarize derives the tracking state from trace. A delivered parcel stays
+// delivered even if the carrier reports later events, such as a signature
+// scan; otherwise the newest event decides.
+func Summarize(trace []TraceEvent) Summary {
+	summary := Summary{Status: StatusPending}
+	for _, event := range trace {
+		if event.OccurredAt.After(summary.LastEventAt) || summary.LastEventAt.IsZero() {
+			summary.LastEventAt = event.OccurredAt
+			if summary.DeliveredAt.IsZero() {
+				summary.Status = event.Status
+			}
+		}
+		if event.Status == StatusDelivered && (summary.DeliveredAt.IsZero() || event.OccurredAt.Before(summary.DeliveredAt)) {
+			summary.DeliveredAt = event.OccurredAt
+			summary.Status = StatusDelivered
+		}
+	}
+	return summary
+}
@@ -0,0 +1,124 @@
+package tracking
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+func TestRegistryLookup(t *testing.T) {
+	registry := NewRegistry()
+	if registry.Configured() {
+		t.Fatal("expected an empty registry to be unconfigured")
+	}
+	sf := &FixtureAdapter{Name: "SF"}
+	registry.Register(sf, "顺丰", " sf express ")
+	for _, carrier := range []string{"sf", " SF ", "顺丰", "SF EXPRESS"} {
+		if adapter, ok := registry.Lookup(carrier); !ok || adapter != sf {
+			t.Errorf("Lookup(%q) = %v, %v", carrier, adapter, ok)
+		}
+	}
+	if adapter, ok := registry.Lookup("顺丰速运"); !ok || adapter != sf {
+		t.Errorf("expected known carrier name to resolve through its code, got %v, %v", adapter, ok)
+	}
+	if _, ok := registry.Lookup("YTO"); ok {
+		t.Fatal("expected unknown carrier to be unresolved")
+	}
+
+	fallback := &FixtureAdapter{}
+	registry.SetFallback(fallback)
+	if adapter, ok := registry.Lookup("YTO"); !ok || adapter != fallback {
+		t.Fatalf("expected fallback for unknown carrier, got %v", adapter)
+	}
+	if _, ok := registry.Lookup("  "); ok {
+		t.Fatal("expected blank carrier to stay unresolved")
+	}
+	var missing *Registry
+	if _, ok := missing.Lookup("SF"); ok || missing.Configured() {
+		t.Fatal("expected nil registry to resolve nothing")
+	}
+}
+
+func TestKnownCarrier(t *testing.T) {
+	for _, name := range []string{"SF", " sf ", "顺丰", "顺丰速运", "sf express"} {
+		carrier, ok := KnownCarrier(name)
+		if !ok || carrier.Code != "SF" || carrier.Name != "顺丰速运" {
+			t.Errorf("KnownCarrier(%q) = %+v, %v", name, carrier, ok)
+		}
+	}
+	if carrier, ok := KnownCarrier("j&t"); !ok || carrier.Code != "JTSD" {
+		t.Errorf("expected j&t to resolve to JTSD, got %+v, %v", carrier, ok)
+	}
+	for _, name := range []string{"", "  ", "本地配送"} {
+		if _, ok := KnownCarrier(name); ok {
+			t.Errorf("KnownCarrier(%q) resolved an unknown carrier", name)
+		}
+	}
+}
+
+func TestNormalizeEvent(t *testing.T) {
+	at := time.Date(2026, 9, 1, 9, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	event, err := NormalizeEvent(TraceEvent{Status: " delivered ", OccurredAt: at, Location: " 上海 "})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if event.Status != StatusDelivered || event.Description != StatusDelivered || event.Location != "上海" || event.OccurredAt.Location() != time.UTC {
+		t.Fatalf("unexpected event %+v", event)
+	}
+	for _, invalid := range []TraceEvent{{Status: "LOST", OccurredAt: at}, {Status: StatusInTransit}} {
+		if _, err := NormalizeEvent(invalid); !errors.Is(err, ErrInvalidEvent) {
+			t.Errorf("NormalizeEvent(%+v) = %v, want ErrInvalidEvent", invalid, err)
+		}
+	}
+}
+
+func TestSummarizeKeepsDeliveredStatus(t *testing.T) {
+	base := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	summary := Summarize([]TraceEvent{
+		{Status: StatusException, OccurredAt: base.Add(3 * time.Hour)},
+		{Status: StatusInTransit, OccurredAt: base},
+		{Status: StatusDelivered, OccurredAt: base.Add(2 * time.Hour)},
+	})
+	if summary.Status != StatusDelivered || !summary.DeliveredAt.Equal(base.Add(2*time.Hour)) || !summary.LastEventAt.Equal(base.Add(3*time.Hour)) {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+
+	summary = Summarize([]TraceEvent{
+		{Status: StatusException, OccurredAt: base.Add(time.Hour)},
+		{Status: StatusInTransit, OccurredAt: base},
+	})
+	if summary.Status != StatusException || !summary.DeliveredAt.IsZero() {
+		t.Fatalf("expected newest event to decide, got %+v", summary)
+	}
+	if summary := Summarize(nil); summary.Status != StatusPending || !summary.LastEventAt.IsZero() {
+		t.Fatalf("unexpected empty summary %+v", summary)
+	}
+}
+
+func TestNextStatusIgnoresOlderEvents(t *testing.T) {
+	base := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	shipment := db.OrderTrackingShipment{
+		ID:             uuid.New(),
+		TrackingStatus: StatusException,
+		LastEventAt:    pgtype.Timestamptz{Time: base.Add(time.Hour), Valid: true},
+	}
+	if _, changed := nextStatus(shipment, Summarize([]TraceEvent{{Status: StatusInTransit, OccurredAt: base}})); changed {
+		t.Fatal("expected an older push not to change the shipment")
+	}
+	params, changed := nextStatus(shipment, Summarize([]TraceEvent{{Status: StatusDelivered, OccurredAt: base.Add(2 * time.Hour)}}))
+	if !changed || params.TrackingStatus != StatusDelivered || !params.DeliveredAt.Time.Equal(base.Add(2*time.Hour)) {
+		t.Fatalf("expected delivery to be recorded, got %+v", params)
+	}
+
+	shipment.TrackingStatus = StatusDelivered
+	shipment.DeliveredAt = params.DeliveredAt
+	params, changed = nextStatus(shipment, Summarize([]TraceEvent{{Status: StatusException, OccurredAt: base.Add(5 * time.Hour)}}))
+	if !changed || params.TrackingStatus != StatusDelivered || !params.LastEventAt.Time.Equal(base.Add(5*time.Hour)) {
+		t.Fatalf("expected delivered status to stick, got %+v", params)
+	}
+}
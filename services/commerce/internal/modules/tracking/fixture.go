@@ -0,0 +1,121 @@
+package tracking
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FixtureTokenHeader carries FixtureAdapter.Token on push callbacks.
+const FixtureTokenHeader = "X-Carrier-Token"
+
+// FixtureAdapter is a fake carrier that serves traces from JSON files named
+// <waybillNo>.json in Dir. It stands in for real carrier APIs in tests and
+// local development; see testdata/ for the file format.
+type FixtureAdapter struct {
+	Name string
+	Dir  string
+	// Token must be sent in FixtureTokenHeader on push callbacks. Without a
+	// Token every push is refused.
+	Token string
+}
+
+type fixtureEvent struct {
+	Status      string    `json:"status"`
+	Description string    `json:"description"`
+	Location    string    `json:"location"`
+	OccurredAt  time.Time `json:"occurredAt"`
+}
+
+type fixtureTrace struct {
+	WaybillNo string         `json:"waybillNo"`
+	Events    []fixtureEvent `json:"events"`
+}
+
+func (a *FixtureAdapter) Code() string {
+	if a.Name == "" {
+		return "FIXTURE"
+	}
+	return a.Name
+}
+
+func (a *FixtureAdapter) QueryTrace(ctx context.Context, waybillNo string) ([]TraceEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(waybillNo)
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, ErrWaybillNotFound
+	}
+	raw, err := os.ReadFile(filepath.Join(a.Dir, name+".json"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrWaybillNotFound
+		}
+		return nil, err
+	}
+	var trace fixtureTrace
+	if err := json.Unmarshal(raw, &trace); err != nil {
+		return nil, fmt.Errorf("decode fixture %s: %w", name, err)
+	}
+	return convertFixtureEvents(trace.Events)
+}
+
+// ParsePush accepts a single trace or an array of traces in the fixture file
+// format, each with its waybillNo.
+func (a *FixtureAdapter) ParsePush(header http.Header, body []byte) ([]PushUpdate, error) {
+	if a.Token == "" || subtle.ConstantTimeCompare([]byte(header.Get(FixtureTokenHeader)), []byte(a.Token)) != 1 {
+		return nil, ErrUnauthorizedPush
+	}
+	var traces []fixtureTrace
+	trimmed := strings.TrimSpace(string(body))
+	if strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal(body, &traces); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPush, err)
+		}
+	} else {
+		var trace fixtureTrace
+		if err := json.Unmarshal(body, &trace); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPush, err)
+		}
+		traces = append(traces, trace)
+	}
+
+	updates := make([]PushUpdate, 0, len(traces))
+	for _, trace := range traces {
+		waybillNo := strings.TrimSpace(trace.WaybillNo)
+		if waybillNo == "" {
+			return nil, fmt.Errorf("%w: waybillNo is required", ErrInvalidPush)
+		}
+		events, err := convertFixtureEvents(trace.Events)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPush, err)
+		}
+		updates = append(updates, PushUpdate{WaybillNo: waybillNo, Events: events})
+	}
+	return updates, nil
+}
+
+func convertFixtureEvents(items []fixtureEvent) ([]TraceEvent, error) {
+	events := make([]TraceEvent, 0, len(items))
+	for _, item := range items {
+		event, err := NormalizeEvent(TraceEvent{
+			Status:      item.Status,
+			Description: item.Description,
+			Location:    item.Location,
+			OccurredAt:  item.OccurredAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,57 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestFixtureAdapterQueryTrace(t *testing.T) {
+	adapter := &FixtureAdapter{Name: "SF", Dir: "testdata"}
+	trace, err := adapter.QueryTrace(context.Background(), "SF1000000001")
+	if err != nil {
+		t.Fatalf("query trace: %v", err)
+	}
+	if len(trace) != 3 || trace[2].Status != StatusDelivered || trace[2].Location != "上海" {
+		t.Fatalf("unexpected trace %+v", trace)
+	}
+	if summary := Summarize(trace); summary.Status != StatusDelivered {
+		t.Fatalf("expected delivered summary, got %+v", summary)
+	}
+	for _, waybillNo := range []string{"SF404", "../testdata/SF1000000001", "", ".hidden"} {
+		if _, err := adapter.QueryTrace(context.Background(), waybillNo); !errors.Is(err, ErrWaybillNotFound) {
+			t.Errorf("QueryTrace(%q) = %v, want ErrWaybillNotFound", waybillNo, err)
+		}
+	}
+}
+
+func TestFixtureAdapterParsePush(t *testing.T) {
+	adapter := &FixtureAdapter{Token: "push-token"}
+	body := []byte(`[{"waybillNo":"SF1","events":[{"status":"in_transit","occurredAt":"2026-09-01T09:00:00+08:00"}]},{"waybillNo":"SF2","events":[]}]`)
+	header := http.Header{}
+	if _, err := adapter.ParsePush(header, body); !errors.Is(err, ErrUnauthorizedPush) {
+		t.Fatalf("expected ErrUnauthorizedPush, got %v", err)
+	}
+	header.Set(FixtureTokenHeader, "push-token")
+	if _, err := (&FixtureAdapter{}).ParsePush(header, body); !errors.Is(err, ErrUnauthorizedPush) {
+		t.Fatalf("expected an adapter without token to refuse pushes, got %v", err)
+	}
+	updates, err := adapter.ParsePush(header, body)
+	if err != nil {
+		t.Fatalf("parse push: %v", err)
+	}
+	if len(updates) != 2 || updates[0].WaybillNo != "SF1" || updates[0].Events[0].Status != StatusInTransit || len(updates[1].Events) != 0 {
+		t.Fatalf("unexpected updates %+v", updates)
+	}
+
+	single, err := adapter.ParsePush(header, []byte(`{"waybillNo":"SF3","events":[{"status":"DELIVERED","occurredAt":"2026-09-02T09:00:00Z"}]}`))
+	if err != nil || len(single) != 1 || single[0].WaybillNo != "SF3" {
+		t.Fatalf("unexpected single push %+v (%v)", single, err)
+	}
+	for _, invalid := range []string{`{`, `{"events":[]}`, `{"waybillNo":"SF4","events":[{"status":"LOST","occurredAt":"2026-09-02T09:00:00Z"}]}`} {
+		if _, err := adapter.ParsePush(header, []byte(invalid)); !errors.Is(err, ErrInvalidPush) {
+			t.Errorf("ParsePush(%s) = %v, want ErrInvalidPush", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	ordermodule "github.com/teamdsb/tmo/services/commerce/internal/modules/order"
+)
+
+const (
+	defaultPollInterval = 5 * time.Minute
+	defaultRefreshAfter = 30 * time.Minute
+	defaultPollBatch    = 50
+)
+
+// Poller refreshes the carrier trace of shipments whose order is still
+// PARTIALLY_SHIPPED or SHIPPED and whose parcel has not been delivered. Each shipment is polled at
+// most once per RefreshAfter; claiming marks it polled, so several replicas
+// never query the same waybill concurrently.
+type Poller struct {
+	DB           *pgxpool.Pool
+	Outbox       *events.Outbox
+	Carriers     *Registry
+	Interval     time.Duration
+	RefreshAfter time.Duration
+	Logger       *slog.Logger
+}
+
+func (p *Poller) Start(ctx context.Context) {
+	if p == nil || p.DB == nil || !p.Carriers.Configured() {
+		return
+	}
+	interval := p.Interval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := p.RunOnce(ctx); err != nil && p.Logger != nil && ctx.Err() == nil {
+					p.Logger.Warn("shipment tracking poll failed", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// RunOnce polls one batch of due shipments and returns how many orders were
+// moved to DELIVERED. A failing carrier only skips its own shipments.
+func (p *Poller) RunOnce(ctx context.Context) (int, error) {
+	refreshAfter := p.RefreshAfter
+	if refreshAfter <= 0 {
+		refreshAfter = defaultRefreshAfter
+	}
+	shipments, err := db.New(p.DB).ClaimTrackingShipmentsForPoll(ctx, db.ClaimTrackingShipmentsForPollParams{
+		OrderStatuses: []string{ordermodule.StatusPartiallyShipped, ordermodule.StatusShipped},
+		PolledBefore:  pgtype.Timestamptz{Time: time.Now().UTC().Add(-refreshAfter), Valid: true},
+		BatchSize:     defaultPollBatch,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("claim shipments: %w", err)
+	}
+
+	var delivered int
+	for _, shipment := range shipments {
+		if shipment.Carrier == nil {
+			continue
+		}
+		adapter, ok := p.Carriers.Lookup(*shipment.Carrier)
+		if !ok {
+			continue
+		}
+		trace, err := adapter.QueryTrace(ctx, shipment.WaybillNo)
+		if err != nil {
+			if !errors.Is(err, ErrWaybillNotFound) && p.Logger != nil {
+				p.Logger.Warn("query carrier trace failed", "carrier", adapter.Code(), "waybillNo", shipment.WaybillNo, "error", err)
+			}
+			continue
+		}
+		var result Result
+		err = shareddb.WithTx(ctx, p.DB, func(tx pgx.Tx) error {
+			var err error
+			result, err = Record(ctx, tx, p.Outbox, shipment.OrderID, shipment.ID, SourcePoll, trace)
+			return err
+		})
+		if err != nil {
+			return delivered, fmt.Errorf("record trace of %s: %w", shipment.WaybillNo, err)
+		}
+		if result.Order != nil {
+			delivered++
+		}
+	}
+	if delivered > 0 && p.Logger != nil {
+		p.Logger.Info("delivered orders from carrier trace", "count", delivered)
+	}
+	return delivered, nil
+}
@@ -0,0 +1,151 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+	ordermodule "github.com/teamdsb/tmo/services/commerce/internal/modules/order"
+)
+
+// Result reports what Record changed.
+type Result struct {
+	Shipment  db.OrderTrackingShipment
+	NewEvents int
+	// Order is set when the order was moved to DELIVERED.
+	Order *db.Order
+}
+
+// Record stores trace events of a shipment and updates its tracking status.
+// Once every parcel of a shipped order is delivered, the order moves to
+// DELIVERED and order.status_changed is published, so delivery no longer
+// waits for the auto-delivery timer. It must run inside tx; the order row is
+// locked before the shipment, matching the ship handler.
+func Record(ctx context.Context, tx pgx.Tx, outbox *events.Outbox, orderID, shipmentID uuid.UUID, source string, trace []TraceEvent) (Result, error) {
+	q := db.New(tx)
+	order, err := q.GetOrderForUpdate(ctx, orderID)
+	if err != nil {
+		return Result{}, err
+	}
+	shipment, err := q.GetTrackingShipmentForUpdate(ctx, shipmentID)
+	if err != nil {
+		return Result{}, err
+	}
+	if shipment.OrderID != order.ID {
+		return Result{}, pgx.ErrNoRows
+	}
+
+	result := Result{Shipment: shipment}
+	for _, event := range trace {
+		var location *string
+		if event.Location != "" {
+			location = &event.Location
+		}
+		inserted, err := q.CreateShipmentEvent(ctx, db.CreateShipmentEventParams{
+			ShipmentID:  shipment.ID,
+			Status:      event.Status,
+			Description: event.Description,
+			Location:    location,
+			OccurredAt:  pgtype.Timestamptz{Time: event.OccurredAt, Valid: true},
+			Source:      source,
+		})
+		if err != nil {
+			return Result{}, err
+		}
+		result.NewEvents += int(inserted)
+	}
+	if result.NewEvents == 0 {
+		return result, nil
+	}
+
+	params, changed := nextStatus(shipment, Summarize(trace))
+	if !changed {
+		return result, nil
+	}
+	result.Shipment, err = q.UpdateTrackingShipmentStatus(ctx, params)
+	if err != nil {
+		return Result{}, err
+	}
+	if shipment.TrackingStatus == StatusDelivered || result.Shipment.TrackingStatus != StatusDelivered {
+		return result, nil
+	}
+
+	delivered, err := deliverOrder(ctx, q, tx, outbox, order)
+	if err != nil {
+		return Result{}, err
+	}
+	result.Order = delivered
+	return result, nil
+}
+
+// nextStatus merges a trace summary into the stored shipment state. Pushes
+// may carry only the newest events, so an older summary never overrides a
+// newer stored status.
+func nextStatus(shipment db.OrderTrackingShipment, summary Summary) (db.UpdateTrackingShipmentStatusParams, bool) {
+	params := db.UpdateTrackingShipmentStatusParams{
+		TrackingStatus: shipment.TrackingStatus,
+		LastEventAt:    shipment.LastEventAt,
+		DeliveredAt:    shipment.DeliveredAt,
+		ID:             shipment.ID,
+	}
+	if summary.LastEventAt.IsZero() {
+		return params, false
+	}
+	newer := !shipment.LastEventAt.Valid || !summary.LastEventAt.Before(shipment.LastEventAt.Time)
+	if newer {
+		params.LastEventAt = pgtype.Timestamptz{Time: summary.LastEventAt, Valid: true}
+		if shipment.TrackingStatus != StatusDelivered {
+			params.TrackingStatus = summary.Status
+		}
+	}
+	if !summary.DeliveredAt.IsZero() && shipment.TrackingStatus != StatusDelivered {
+		params.TrackingStatus = StatusDelivered
+		params.DeliveredAt = pgtype.Timestamptz{Time: summary.DeliveredAt, Valid: true}
+	}
+	changed := params.TrackingStatus != shipment.TrackingStatus ||
+		!sameTime(params.LastEventAt, shipment.LastEventAt) ||
+		!sameTime(params.DeliveredAt, shipment.DeliveredAt)
+	return params, changed
+}
+
+func sameTime(a, b pgtype.Timestamptz) bool {
+	return a.Valid == b.Valid && a.Time.Equal(b.Time)
+}
+
+// deliverOrder moves a shipped order to DELIVERED when all of its parcels
+// are delivered. Orders that already left SHIPPED, for example through the
+// customer's receipt confirmation, are left alone.
+func deliverOrder(ctx context.Context, q *db.Queries, tx pgx.Tx, outbox *events.Outbox, order db.Order) (*db.Order, error) {
+	transition, err := ordermodule.Resolve(order.Status, order.PaymentStatus, ordermodule.EventCarrierDelivered)
+	if err != nil {
+		if errors.Is(err, ordermodule.ErrInvalidTransition) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	shipments, err := q.ListTrackingShipments(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, shipment := range shipments {
+		if shipment.TrackingStatus != StatusDelivered {
+			return nil, nil
+		}
+	}
+	updated, err := q.UpdateOrderStatus(ctx, db.UpdateOrderStatusParams{
+		ID:     order.ID,
+		Status: transition.To,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ordermodule.PublishStatusChanged(ctx, outbox, tx, order, updated); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
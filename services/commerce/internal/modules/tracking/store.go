@@ -10,6 +10,8 @@ import (
 type Store interface {
 	UpsertTrackingShipment(ctx context.Context, arg db.UpsertTrackingShipmentParams) (db.OrderTrackingShipment, error)
 	ListTrackingShipments(ctx context.Context, orderID uuid.UUID) ([]db.OrderTrackingShipment, error)
-	CreateImportJob(ctx context.Context, arg db.CreateImportJobParams) (db.ImportJob, error)
+	ListTrackingShipmentsByWaybill(ctx context.Context, waybillNo string) ([]db.OrderTrackingShipment, error)
+	ListShipmentEventsByOrder(ctx context.Context, orderID uuid.UUID) ([]db.ShipmentEvent, error)
+	ListShipmentLinesByOrder(ctx context.Context, orderID uuid.UUID) ([]db.ListShipmentLinesByOrderRow, error)
 	GetImportJob(ctx context.Context, id uuid.UUID) (db.ImportJob, error)
 }
@@ -0,0 +1,260 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+const (
+	// DefaultMaxAttempts is how often a delivery is tried before it is marked
+	// dead; with the backoff below that spans about fourteen hours.
+	DefaultMaxAttempts = 12
+
+	defaultPollInterval = 5 * time.Second
+	defaultBatchSize    = 20
+	retryBaseDelay      = 30 * time.Second
+	retryMaxDelay       = 6 * time.Hour
+	// requestTimeout times defaultBatchSize stays below deliveryLease, so a
+	// claimed batch is finished before another dispatcher may claim it again.
+	requestTimeout     = 10 * time.Second
+	deliveryLease      = 5 * time.Minute
+	maxLastErrorLength = 1000
+	maxResponseExcerpt = 512
+)
+
+// ErrSubscriptionInactive is returned when replaying a delivery of a disabled
+// subscription.
+var ErrSubscriptionInactive = errors.New("webhook subscription is inactive")
+
+// Dispatcher sends queued deliveries to customer endpoints. Each request is
+// signed with the subscription secret; failures are retried with exponential
+// backoff until MaxAttempts, after which the delivery is marked DEAD and can
+// only be replayed by an admin. Deliveries of inactive subscriptions wait
+// until the subscription is enabled again.
+type Dispatcher struct {
+	Store        DispatchStore
+	Client       *http.Client
+	MaxAttempts  int
+	PollInterval time.Duration
+	Logger       *slog.Logger
+}
+
+// Start sends due deliveries every PollInterval until ctx is cancelled.
+func (d *Dispatcher) Start(ctx context.Context) {
+	if d == nil || d.Store == nil {
+		return
+	}
+	interval := d.PollInterval
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				d.tick(ctx)
+			}
+		}
+	}()
+}
+
+func (d *Dispatcher) tick(ctx context.Context) {
+	for {
+		count, err := d.RunOnce(ctx)
+		if err != nil {
+			if d.Logger != nil && ctx.Err() == nil {
+				d.Logger.Warn("webhook dispatch failed", "error", err)
+			}
+			return
+		}
+		// A full batch means more deliveries may be due right away.
+		if count < defaultBatchSize {
+			return
+		}
+	}
+}
+
+// RunOnce sends the deliveries that are due and returns how many it
+// attempted. Deliveries are leased while they are sent, so several replicas
+// can dispatch concurrently.
+func (d *Dispatcher) RunOnce(ctx context.Context) (int, error) {
+	if d == nil || d.Store == nil {
+		return 0, nil
+	}
+	due, err := d.Store.ClaimDueWebhookDeliveries(ctx, db.ClaimDueWebhookDeliveriesParams{
+		LeaseSeconds: deliveryLease.Seconds(),
+		BatchSize:    defaultBatchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+	subscriptions := make(map[uuid.UUID]db.WebhookSubscription)
+	for _, delivery := range due {
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = d.Store.GetWebhookSubscription(ctx, delivery.SubscriptionID)
+			if err != nil {
+				return len(due), fmt.Errorf("get webhook subscription: %w", err)
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+		if _, err := d.attempt(ctx, subscription, delivery); err != nil {
+			return len(due), err
+		}
+	}
+	return len(due), nil
+}
+
+// Replay sends a delivery again right away, whatever its status, and returns
+// it with the outcome recorded. A replay starts a fresh retry budget, so a
+// failed replay is retried automatically like a new delivery.
+func (d *Dispatcher) Replay(ctx context.Context, id uuid.UUID) (db.WebhookDelivery, error) {
+	if d == nil || d.Store == nil {
+		return db.WebhookDelivery{}, errors.New("webhook dispatcher is not configured")
+	}
+	current, err := d.Store.GetWebhookDelivery(ctx, id)
+	if err != nil {
+		return db.WebhookDelivery{}, err
+	}
+	subscription, err := d.Store.GetWebhookSubscription(ctx, current.SubscriptionID)
+	if err != nil {
+		return db.WebhookDelivery{}, err
+	}
+	if !subscription.IsActive {
+		return db.WebhookDelivery{}, ErrSubscriptionInactive
+	}
+	delivery, err := d.Store.ReplayWebhookDelivery(ctx, db.ReplayWebhookDeliveryParams{
+		LeaseSeconds: deliveryLease.Seconds(),
+		ID:           id,
+	})
+	if err != nil {
+		return db.WebhookDelivery{}, err
+	}
+	recorded, err := d.attempt(ctx, subscription, delivery)
+	if err == nil && recorded.ID == uuid.Nil {
+		// The subscription was deleted while the request was in flight.
+		return db.WebhookDelivery{}, pgx.ErrNoRows
+	}
+	return recorded, err
+}
+
+// attempt sends a claimed delivery and records the outcome.
+func (d *Dispatcher) attempt(ctx context.Context, subscription db.WebhookSubscription, delivery db.WebhookDelivery) (db.WebhookDelivery, error) {
+	statusCode, sendErr := d.send(ctx, subscription, delivery)
+	var responseStatus *int32
+	if statusCode > 0 {
+		code := int32(statusCode)
+		responseStatus = &code
+	}
+	if sendErr == nil {
+		recorded, err := d.Store.MarkWebhookDeliveryDelivered(ctx, db.MarkWebhookDeliveryDeliveredParams{
+			ID:                 delivery.ID,
+			LastResponseStatus: responseStatus,
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return db.WebhookDelivery{}, fmt.Errorf("record webhook delivery: %w", err)
+		}
+		return recorded, nil
+	}
+
+	status := DeliveryPending
+	if int(delivery.Attempts) >= d.maxAttempts() {
+		status = DeliveryDead
+	}
+	message := truncate(sendErr.Error(), maxLastErrorLength)
+	recorded, err := d.Store.MarkWebhookDeliveryFailed(ctx, db.MarkWebhookDeliveryFailedParams{
+		Status:             status,
+		LastResponseStatus: responseStatus,
+		LastError:          &message,
+		RetrySeconds:       retryDelay(int(delivery.Attempts)).Seconds(),
+		ID:                 delivery.ID,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return db.WebhookDelivery{}, fmt.Errorf("record webhook delivery: %w", err)
+	}
+	return recorded, nil
+}
+
+// send posts the stored event and returns the response status, or zero when
+// no response was received.
+func (d *Dispatcher) send(ctx context.Context, subscription db.WebhookSubscription, delivery db.WebhookDelivery) (int, error) {
+	requestCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+	req, err := http.NewRequestWithContext(requestCtx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "tmo-webhooks/1")
+	req.Header.Set(HeaderDeliveryID, delivery.ID.String())
+	req.Header.Set(HeaderEventID, delivery.EventID.String())
+	req.Header.Set(HeaderEventType, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, body))
+
+	resp, err := d.client().Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseExcerpt))
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint returned %d: %s", resp.StatusCode, bytes.TrimSpace(excerpt))
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) client() *http.Client {
+	if d.Client != nil {
+		return d.Client
+	}
+	return http.DefaultClient
+}
+
+func (d *Dispatcher) maxAttempts() int {
+	if d.MaxAttempts > 0 {
+		return d.MaxAttempts
+	}
+	return DefaultMaxAttempts
+}
+
+// retryDelay doubles the wait after every failed attempt, capped at six
+// hours.
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
+
+func truncate(message string, limit int) string {
+	if len(message) > limit {
+		return message[:limit]
+	}
+	return message
+}
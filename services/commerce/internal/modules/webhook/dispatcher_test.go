@@ -0,0 +1,279 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+type fakeStore struct {
+	orders        map[uuid.UUID]db.Order
+	subscriptions map[uuid.UUID]db.WebhookSubscription
+	deliveries    map[uuid.UUID]db.WebhookDelivery
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		orders:        map[uuid.UUID]db.Order{},
+		subscriptions: map[uuid.UUID]db.WebhookSubscription{},
+		deliveries:    map[uuid.UUID]db.WebhookDelivery{},
+	}
+}
+
+func (f *fakeStore) GetOrder(_ context.Context, id uuid.UUID) (db.Order, error) {
+	order, ok := f.orders[id]
+	if !ok {
+		return db.Order{}, pgx.ErrNoRows
+	}
+	return order, nil
+}
+
+func (f *fakeStore) ListActiveWebhookSubscriptionsByCustomer(_ context.Context, customerID uuid.UUID) ([]db.WebhookSubscription, error) {
+	var items []db.WebhookSubscription
+	for _, subscription := range f.subscriptions {
+		if subscription.CustomerID == customerID && subscription.IsActive {
+			items = append(items, subscription)
+		}
+	}
+	return items, nil
+}
+
+func (f *fakeStore) CreateWebhookDelivery(_ context.Context, arg db.CreateWebhookDeliveryParams) (int64, error) {
+	for _, delivery := range f.deliveries {
+		if delivery.SubscriptionID == arg.SubscriptionID && delivery.EventID == arg.EventID {
+			return 0, nil
+		}
+	}
+	id := uuid.New()
+	f.deliveries[id] = db.WebhookDelivery{
+		ID:             id,
+		SubscriptionID: arg.SubscriptionID,
+		EventID:        arg.EventID,
+		EventType:      arg.EventType,
+		Payload:        arg.Payload,
+		Status:         DeliveryPending,
+	}
+	return 1, nil
+}
+
+func (f *fakeStore) GetWebhookSubscription(_ context.Context, id uuid.UUID) (db.WebhookSubscription, error) {
+	subscription, ok := f.subscriptions[id]
+	if !ok {
+		return db.WebhookSubscription{}, pgx.ErrNoRows
+	}
+	return subscription, nil
+}
+
+func (f *fakeStore) GetWebhookDelivery(_ context.Context, id uuid.UUID) (db.WebhookDelivery, error) {
+	delivery, ok := f.deliveries[id]
+	if !ok {
+		return db.WebhookDelivery{}, pgx.ErrNoRows
+	}
+	return delivery, nil
+}
+
+// ClaimDueWebhookDeliveries treats every pending delivery of an active
+// subscription as due.
+func (f *fakeStore) ClaimDueWebhookDeliveries(_ context.Context, arg db.ClaimDueWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	var due []db.WebhookDelivery
+	for id, delivery := range f.deliveries {
+		if delivery.Status != DeliveryPending || !f.subscriptions[delivery.SubscriptionID].IsActive || len(due) >= int(arg.BatchSize) {
+			continue
+		}
+		delivery.Attempts++
+		f.deliveries[id] = delivery
+		due = append(due, delivery)
+	}
+	return due, nil
+}
+
+func (f *fakeStore) ReplayWebhookDelivery(_ context.Context, arg db.ReplayWebhookDeliveryParams) (db.WebhookDelivery, error) {
+	delivery, ok := f.deliveries[arg.ID]
+	if !ok {
+		return db.WebhookDelivery{}, pgx.ErrNoRows
+	}
+	delivery.Status = DeliveryPending
+	delivery.Attempts = 1
+	delivery.ReplayCount++
+	f.deliveries[arg.ID] = delivery
+	return delivery, nil
+}
+
+func (f *fakeStore) MarkWebhookDeliveryDelivered(_ context.Context, arg db.MarkWebhookDeliveryDeliveredParams) (db.WebhookDelivery, error) {
+	delivery := f.deliveries[arg.ID]
+	delivery.Status = DeliveryDelivered
+	delivery.LastResponseStatus = arg.LastResponseStatus
+	delivery.LastError = nil
+	f.deliveries[arg.ID] = delivery
+	return delivery, nil
+}
+
+func (f *fakeStore) MarkWebhookDeliveryFailed(_ context.Context, arg db.MarkWebhookDeliveryFailedParams) (db.WebhookDelivery, error) {
+	delivery := f.deliveries[arg.ID]
+	delivery.Status = arg.Status
+	delivery.LastResponseStatus = arg.LastResponseStatus
+	delivery.LastError = arg.LastError
+	f.deliveries[arg.ID] = delivery
+	return delivery, nil
+}
+
+func (f *fakeStore) addSubscription(customerID uuid.UUID, url string, active bool, types ...string) db.WebhookSubscription {
+	subscription := db.WebhookSubscription{
+		ID:         uuid.New(),
+		CustomerID: customerID,
+		URL:        url,
+		Secret:     "whsec_test",
+		EventTypes: types,
+		IsActive:   active,
+	}
+	f.subscriptions[subscription.ID] = subscription
+	return subscription
+}
+
+func (f *fakeStore) only(t *testing.T) db.WebhookDelivery {
+	t.Helper()
+	if len(f.deliveries) != 1 {
+		t.Fatalf("expected one delivery, got %d", len(f.deliveries))
+	}
+	for _, delivery := range f.deliveries {
+		return delivery
+	}
+	return db.WebhookDelivery{}
+}
+
+func TestFanoutQueuesMatchingSubscriptionsOfTheCustomer(t *testing.T) {
+	store := newFakeStore()
+	customerID := uuid.New()
+	orderID := uuid.New()
+	store.orders[orderID] = db.Order{ID: orderID, CustomerID: customerID}
+	shipments := store.addSubscription(customerID, "https://erp.example.com/hooks", true, events.TypeShipmentAdded)
+	store.addSubscription(customerID, "https://erp.example.com/orders", true, events.TypeOrderCreated)
+	store.addSubscription(customerID, "https://erp.example.com/old", false, events.TypeShipmentAdded)
+	store.addSubscription(uuid.New(), "https://other.example.com/hooks", true, events.TypeShipmentAdded)
+
+	event, err := events.New("commerce", events.TypeShipmentAdded, orderID.String(), events.ShipmentAdded{ShipmentID: uuid.NewString(), OrderID: orderID.String()})
+	if err != nil {
+		t.Fatalf("new event: %v", err)
+	}
+	subscriber := NewSubscriber(store)
+	if !subscriber.Accepts(events.TypeShipmentAdded) || subscriber.Accepts(events.TypeTicketUpdated) {
+		t.Fatal("unexpected subscriber type filter")
+	}
+	for i := 0; i < 2; i++ {
+		if err := subscriber.Deliver(context.Background(), event); err != nil {
+			t.Fatalf("fan out: %v", err)
+		}
+	}
+	delivery := store.only(t)
+	if delivery.SubscriptionID != shipments.ID || delivery.EventID != event.ID {
+		t.Fatalf("unexpected delivery %+v", delivery)
+	}
+	var envelope events.Event
+	if err := json.Unmarshal(delivery.Payload, &envelope); err != nil || envelope.ID != event.ID || envelope.Type != events.TypeShipmentAdded {
+		t.Fatalf("expected the event envelope as payload, got %s (%v)", delivery.Payload, err)
+	}
+
+	unknown, _ := events.New("commerce", events.TypeShipmentAdded, "x", events.ShipmentAdded{OrderID: uuid.NewString()})
+	if err := Fanout(context.Background(), store, unknown); err != nil || len(store.deliveries) != 1 {
+		t.Fatalf("expected events of unknown orders to be dropped, got %v", err)
+	}
+}
+
+func TestFanoutUsesCustomerFromPayload(t *testing.T) {
+	store := newFakeStore()
+	customerID := uuid.New()
+	store.addSubscription(customerID, "https://erp.example.com/hooks", true, events.TypeStatementGenerated)
+	event, _ := events.New("commerce", events.TypeStatementGenerated, "s-1", events.StatementGenerated{StatementID: "s-1", CustomerID: customerID.String()})
+	if err := Fanout(context.Background(), store, event); err != nil {
+		t.Fatalf("fan out: %v", err)
+	}
+	store.only(t)
+}
+
+func TestDispatcherSignsAndRetries(t *testing.T) {
+	status := http.StatusInternalServerError
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		body, _ := io.ReadAll(r.Body)
+		timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if err != nil || r.Header.Get(HeaderSignature) != Sign("whsec_test", timestamp, body) {
+			t.Errorf("unexpected signature headers %v", r.Header)
+		}
+		if r.Header.Get(HeaderEventType) != events.TypeOrderCreated || r.Header.Get(HeaderDeliveryID) == "" {
+			t.Errorf("unexpected event headers %v", r.Header)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("maintenance"))
+	}))
+	defer server.Close()
+
+	store := newFakeStore()
+	customerID := uuid.New()
+	store.addSubscription(customerID, server.URL, true, events.TypeOrderCreated)
+	event, _ := events.New("commerce", events.TypeOrderCreated, "o-1", events.OrderCreated{OrderID: uuid.NewString(), CustomerID: customerID.String()})
+	if err := Fanout(context.Background(), store, event); err != nil {
+		t.Fatalf("fan out: %v", err)
+	}
+
+	dispatcher := &Dispatcher{Store: store, Client: server.Client(), MaxAttempts: 2}
+	if count, err := dispatcher.RunOnce(context.Background()); err != nil || count != 1 {
+		t.Fatalf("run once: %d %v", count, err)
+	}
+	delivery := store.only(t)
+	if delivery.Status != DeliveryPending || delivery.LastResponseStatus == nil || *delivery.LastResponseStatus != http.StatusInternalServerError || delivery.LastError == nil {
+		t.Fatalf("expected a pending retry with the response recorded, got %+v", delivery)
+	}
+	if _, err := dispatcher.RunOnce(context.Background()); err != nil {
+		t.Fatalf("run once: %v", err)
+	}
+	if delivery = store.only(t); delivery.Status != DeliveryDead {
+		t.Fatalf("expected the delivery to be dead after max attempts, got %s", delivery.Status)
+	}
+	if count, _ := dispatcher.RunOnce(context.Background()); count != 0 {
+		t.Fatalf("expected dead deliveries not to be retried, got %d", count)
+	}
+
+	status = http.StatusOK
+	replayed, err := dispatcher.Replay(context.Background(), delivery.ID)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if replayed.Status != DeliveryDelivered || replayed.ReplayCount != 1 || replayed.LastError != nil {
+		t.Fatalf("unexpected replayed delivery %+v", replayed)
+	}
+	if requests != 3 {
+		t.Fatalf("expected three requests, got %d", requests)
+	}
+}
+
+func TestReplayRejectsInactiveSubscription(t *testing.T) {
+	store := newFakeStore()
+	customerID := uuid.New()
+	subscription := store.addSubscription(customerID, "https://erp.example.com/hooks", true, events.TypeOrderCreated)
+	event, _ := events.New("commerce", events.TypeOrderCreated, "o-1", events.OrderCreated{CustomerID: customerID.String()})
+	if err := Fanout(context.Background(), store, event); err != nil {
+		t.Fatalf("fan out: %v", err)
+	}
+	subscription.IsActive = false
+	store.subscriptions[subscription.ID] = subscription
+
+	dispatcher := &Dispatcher{Store: store}
+	if _, err := dispatcher.Replay(context.Background(), store.only(t).ID); !errors.Is(err, ErrSubscriptionInactive) {
+		t.Fatalf("expected ErrSubscriptionInactive, got %v", err)
+	}
+	if _, err := dispatcher.Replay(context.Background(), uuid.New()); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected ErrNoRows for an unknown delivery, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+// SubscriberName is the outbox subscriber that queues customer deliveries.
+const SubscriberName = "customer-webhooks"
+
+// NewSubscriber returns the outbox subscriber that turns commerce events into
+// one pending delivery per matching subscription of the customer the event
+// belongs to. Queueing is idempotent, so a redelivered event adds nothing.
+func NewSubscriber(store FanoutStore) *events.LocalSubscriber {
+	return events.NewLocalSubscriber(SubscriberName, EventTypes, func(ctx context.Context, event events.Event) error {
+		return Fanout(ctx, store, event)
+	})
+}
+
+// eventOwner holds the payload fields that identify whose event it is.
+type eventOwner struct {
+	CustomerID string `json:"customerId"`
+	OrderID    string `json:"orderId"`
+}
+
+// Fanout queues event for the active subscriptions of its customer that
+// listen to its type. Events without a known customer are dropped.
+func Fanout(ctx context.Context, store FanoutStore, event events.Event) error {
+	customerID, ok, err := resolveCustomer(ctx, store, event)
+	if err != nil || !ok {
+		return err
+	}
+	subscriptions, err := store.ListActiveWebhookSubscriptionsByCustomer(ctx, customerID)
+	if err != nil {
+		return fmt.Errorf("list webhook subscriptions: %w", err)
+	}
+	var body json.RawMessage
+	for _, subscription := range subscriptions {
+		if !events.Matches(subscription.EventTypes, event.Type) {
+			continue
+		}
+		if body == nil {
+			if body, err = json.Marshal(event); err != nil {
+				return fmt.Errorf("encode webhook event: %w", err)
+			}
+		}
+		if _, err := store.CreateWebhookDelivery(ctx, db.CreateWebhookDeliveryParams{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        body,
+		}); err != nil {
+			return fmt.Errorf("queue webhook delivery: %w", err)
+		}
+	}
+	return nil
+}
+
+func resolveCustomer(ctx context.Context, store FanoutStore, event events.Event) (uuid.UUID, bool, error) {
+	var owner eventOwner
+	if err := event.Decode(&owner); err != nil {
+		return uuid.Nil, false, nil
+	}
+	if customerID, err := uuid.Parse(strings.TrimSpace(owner.CustomerID)); err == nil {
+		return customerID, true, nil
+	}
+	orderID, err := uuid.Parse(strings.TrimSpace(owner.OrderID))
+	if err != nil {
+		return uuid.Nil, false, nil
+	}
+	order, err := store.GetOrder(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, false, nil
+		}
+		return uuid.Nil, false, fmt.Errorf("get order: %w", err)
+	}
+	return order.CustomerID, true, nil
+}
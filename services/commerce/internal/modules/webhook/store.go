@@ -0,0 +1,40 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/commerce/internal/db"
+)
+
+type Store interface {
+	CreateWebhookSubscription(ctx context.Context, arg db.CreateWebhookSubscriptionParams) (db.WebhookSubscription, error)
+	GetWebhookSubscription(ctx context.Context, id uuid.UUID) (db.WebhookSubscription, error)
+	ListWebhookSubscriptions(ctx context.Context, arg db.ListWebhookSubscriptionsParams) ([]db.WebhookSubscription, error)
+	CountWebhookSubscriptions(ctx context.Context, customerID pgtype.UUID) (int64, error)
+	UpdateWebhookSubscription(ctx context.Context, arg db.UpdateWebhookSubscriptionParams) (db.WebhookSubscription, error)
+	RotateWebhookSubscriptionSecret(ctx context.Context, arg db.RotateWebhookSubscriptionSecretParams) (db.WebhookSubscription, error)
+	DeleteWebhookSubscription(ctx context.Context, id uuid.UUID) (int64, error)
+	GetWebhookDelivery(ctx context.Context, id uuid.UUID) (db.WebhookDelivery, error)
+	ListWebhookDeliveries(ctx context.Context, arg db.ListWebhookDeliveriesParams) ([]db.WebhookDelivery, error)
+	CountWebhookDeliveries(ctx context.Context, arg db.CountWebhookDeliveriesParams) (int64, error)
+}
+
+// FanoutStore is what the outbox subscriber needs to queue deliveries.
+type FanoutStore interface {
+	GetOrder(ctx context.Context, id uuid.UUID) (db.Order, error)
+	ListActiveWebhookSubscriptionsByCustomer(ctx context.Context, customerID uuid.UUID) ([]db.WebhookSubscription, error)
+	CreateWebhookDelivery(ctx context.Context, arg db.CreateWebhookDeliveryParams) (int64, error)
+}
+
+// DispatchStore is what the dispatcher needs to send and record deliveries.
+type DispatchStore interface {
+	GetWebhookSubscription(ctx context.Context, id uuid.UUID) (db.WebhookSubscription, error)
+	GetWebhookDelivery(ctx context.Context, id uuid.UUID) (db.WebhookDelivery, error)
+	ClaimDueWebhookDeliveries(ctx context.Context, arg db.ClaimDueWebhookDeliveriesParams) ([]db.WebhookDelivery, error)
+	ReplayWebhookDelivery(ctx context.Context, arg db.ReplayWebhookDeliveryParams) (db.WebhookDelivery, error)
+	MarkWebhookDeliveryDelivered(ctx context.Context, arg db.MarkWebhookDeliveryDeliveredParams) (db.WebhookDelivery, error)
+	MarkWebhookDeliveryFailed(ctx context.Context, arg db.MarkWebhookDeliveryFailedParams) (db.WebhookDelivery, error)
+}
@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+)
+
+// Statuses stored in webhook_deliveries.
+const (
+	DeliveryPending   = "PENDING"
+	DeliveryDelivered = "DELIVERED"
+	DeliveryDead      = "DEAD"
+)
+
+// Headers sent with every delivery. The signature covers the timestamp and
+// the raw body, see Sign.
+const (
+	HeaderDeliveryID = "X-Webhook-Delivery-Id"
+	HeaderEventID    = "X-Webhook-Event-Id"
+	HeaderEventType  = "X-Webhook-Event-Type"
+	HeaderTimestamp  = "X-Webhook-Timestamp"
+	HeaderSignature  = "X-Webhook-Signature"
+)
+
+const (
+	secretPrefix    = "whsec_"
+	signatureScheme = "v1="
+	maxURLLength    = 2048
+)
+
+var (
+	ErrInvalidURL        = errors.New("url must be an absolute http or https url")
+	ErrInvalidEventTypes = errors.New("eventTypes must list supported event types")
+)
+
+// EventTypes are the events customers can subscribe to.
+var EventTypes = []string{
+	events.TypeOrderCreated,
+	events.TypeOrderStatusChanged,
+	events.TypeOrderClosed,
+	events.TypeShipmentAdded,
+	events.TypeStatementGenerated,
+}
+
+// NormalizeURL trims raw and checks that it is an absolute http(s) URL.
+func NormalizeURL(raw string) (string, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" || len(trimmed) > maxURLLength {
+		return "", ErrInvalidURL
+	}
+	parsed, err := url.Parse(trimmed)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return "", ErrInvalidURL
+	}
+	return trimmed, nil
+}
+
+// NormalizeEventTypes trims and deduplicates raw. Every entry must be one of
+// EventTypes.
+func NormalizeEventTypes(raw []string) ([]string, error) {
+	normalized := make([]string, 0, len(raw))
+	seen := make(map[string]struct{}, len(raw))
+	for _, value := range raw {
+		value = strings.TrimSpace(value)
+		if !supported(value) {
+			return nil, ErrInvalidEventTypes
+		}
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		normalized = append(normalized, value)
+	}
+	if len(normalized) == 0 {
+		return nil, ErrInvalidEventTypes
+	}
+	return normalized, nil
+}
+
+func supported(eventType string) bool {
+	for _, candidate := range EventTypes {
+		if candidate == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSecret returns a random signing secret.
+func NewSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return secretPrefix + hex.EncodeToString(buf), nil
+}
+
+// Sign returns the signature header value for body sent at timestamp (Unix
+// seconds): "v1=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>"
+// keyed with secret. Receivers recompute it and should reject timestamps
+// that are more than a few minutes old.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signatureScheme + hex.EncodeToString(mac.Sum(nil))
+}
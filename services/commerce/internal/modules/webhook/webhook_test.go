@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"1"}`)
+	signature := Sign("secret", 1700000000, body)
+	// printf '1700000000.{"id":"1"}' | openssl dgst -sha256 -hmac secret
+	want := "v1=086f6aff7bd084c98679825129c5a64dbad88c760016d6d2c0fb123f27951d54"
+	if signature != want {
+		t.Fatalf("Sign = %q, want %q", signature, want)
+	}
+	if Sign("other", 1700000000, body) == signature || Sign("secret", 1700000001, body) == signature {
+		t.Fatal("expected secret and timestamp to change the signature")
+	}
+}
+
+func TestNormalizeURL(t *testing.T) {
+	if got, err := NormalizeURL("  https://erp.example.com/hooks  "); err != nil || got != "https://erp.example.com/hooks" {
+		t.Fatalf("unexpected result %q %v", got, err)
+	}
+	for _, raw := range []string{"", "erp.example.com/hooks", "ftp://erp.example.com", "https:///path"} {
+		if _, err := NormalizeURL(raw); !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("NormalizeURL(%q) = %v, want ErrInvalidURL", raw, err)
+		}
+	}
+}
+
+func TestNormalizeEventTypes(t *testing.T) {
+	got, err := NormalizeEventTypes([]string{" order.created ", events.TypeShipmentAdded, events.TypeOrderCreated})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if len(got) != 2 || got[0] != events.TypeOrderCreated || got[1] != events.TypeShipmentAdded {
+		t.Fatalf("unexpected event types %v", got)
+	}
+	for _, raw := range [][]string{nil, {" "}, {events.TypePaymentSucceeded}, {"*"}} {
+		if _, err := NormalizeEventTypes(raw); !errors.Is(err, ErrInvalidEventTypes) {
+			t.Errorf("NormalizeEventTypes(%v) = %v, want ErrInvalidEventTypes", raw, err)
+		}
+	}
+}
+
+func TestRetryDelayBacksOff(t *testing.T) {
+	if got := retryDelay(1); got != retryBaseDelay {
+		t.Fatalf("first retry after %s, want %s", got, retryBaseDelay)
+	}
+	if got := retryDelay(4); got != 8*retryBaseDelay {
+		t.Fatalf("fourth retry after %s, want %s", got, 8*retryBaseDelay)
+	}
+	if got := retryDelay(DefaultMaxAttempts * 10); got != retryMaxDelay {
+		t.Fatalf("expected delay to be capped at %s, got %s", retryMaxDelay, got)
+	}
+}
@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS sku_inventory (
+    sku_id uuid PRIMARY KEY REFERENCES catalog_skus(id) ON DELETE CASCADE,
+    on_hand integer NOT NULL DEFAULT 0,
+    reserved integer NOT NULL DEFAULT 0,
+    low_stock_threshold integer NOT NULL DEFAULT 0,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT sku_inventory_on_hand_non_negative CHECK (on_hand >= 0),
+    CONSTRAINT sku_inventory_reserved_non_negative CHECK (reserved >= 0),
+    CONSTRAINT sku_inventory_reserved_within_on_hand CHECK (reserved <= on_hand),
+    CONSTRAINT sku_inventory_low_stock_threshold_non_negative CHECK (low_stock_threshold >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS sku_inventory_reservations (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id uuid NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    sku_id uuid NOT NULL REFERENCES catalog_skus(id) ON DELETE CASCADE,
+    qty integer NOT NULL,
+    status text NOT NULL DEFAULT 'ACTIVE',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT sku_inventory_reservations_qty_positive CHECK (qty > 0),
+    CONSTRAINT sku_inventory_reservations_status_valid CHECK (status IN ('ACTIVE', 'RELEASED', 'CONSUMED')),
+    CONSTRAINT sku_inventory_reservations_order_sku_unique UNIQUE (order_id, sku_id)
+);
+
+CREATE INDEX IF NOT EXISTS sku_inventory_reservations_sku_status_idx
+    ON sku_inventory_reservations(sku_id, status);
+
+CREATE TABLE IF NOT EXISTS sku_inventory_movements (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    sku_id uuid NOT NULL REFERENCES catalog_skus(id) ON DELETE CASCADE,
+    movement_type text NOT NULL,
+    on_hand_delta integer NOT NULL,
+    reserved_delta integer NOT NULL,
+    on_hand_after integer NOT NULL,
+    reserved_after integer NOT NULL,
+    order_id uuid REFERENCES orders(id) ON DELETE SET NULL,
+    actor_user_id uuid,
+    reason text,
+    note text,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT sku_inventory_movements_type_valid CHECK (movement_type IN ('ADJUSTMENT', 'RESERVE', 'RELEASE', 'CONSUME'))
+);
+
+CREATE INDEX IF NOT EXISTS sku_inventory_movements_sku_created_idx
+    ON sku_inventory_movements(sku_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS sku_inventory_movements_order_idx
+    ON sku_inventory_movements(order_id)
+    WHERE order_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS sku_inventory_movements;
+DROP TABLE IF EXISTS sku_inventory_reservations;
+DROP TABLE IF EXISTS sku_inventory;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE order_admin_events
+    ALTER COLUMN new_owner_sales_user_id DROP NOT NULL,
+    ADD COLUMN IF NOT EXISTS reason_code text;
+
+CREATE INDEX IF NOT EXISTS order_admin_events_reason_code_idx
+    ON order_admin_events(reason_code)
+    WHERE reason_code IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS order_admin_events_reason_code_idx;
+DELETE FROM order_admin_events WHERE new_owner_sales_user_id IS NULL;
+ALTER TABLE order_admin_events
+    DROP COLUMN IF EXISTS reason_code,
+    ALTER COLUMN new_owner_sales_user_id SET NOT NULL;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX IF NOT EXISTS orders_unpaid_created_idx
+    ON orders(created_at)
+    WHERE payment_status <> 'PAID';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS orders_unpaid_created_idx;
+-- +goose StatementEnd
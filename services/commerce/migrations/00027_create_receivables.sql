@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS receivables (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id uuid NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    customer_id uuid NOT NULL,
+    owner_sales_user_id uuid,
+    amount_fen bigint NOT NULL,
+    status text NOT NULL DEFAULT 'OPEN',
+    term_days integer NOT NULL,
+    posted_at timestamptz NOT NULL DEFAULT now(),
+    due_at timestamptz NOT NULL,
+    settled_at timestamptz,
+    settled_by uuid,
+    voided_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT receivables_order_unique UNIQUE (order_id),
+    CONSTRAINT receivables_amount_non_negative CHECK (amount_fen >= 0),
+    CONSTRAINT receivables_term_days_non_negative CHECK (term_days >= 0),
+    CONSTRAINT receivables_status_valid CHECK (status IN ('OPEN', 'SETTLED', 'VOID'))
+);
+
+CREATE INDEX IF NOT EXISTS receivables_customer_open_idx
+    ON receivables(customer_id)
+    WHERE status = 'OPEN';
+CREATE INDEX IF NOT EXISTS receivables_open_posted_idx
+    ON receivables(posted_at)
+    WHERE status = 'OPEN';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS receivables;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS customer_statements (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    customer_id uuid NOT NULL,
+    period text NOT NULL,
+    period_start timestamptz NOT NULL,
+    period_end timestamptz NOT NULL,
+    status text NOT NULL DEFAULT 'PENDING',
+    opening_balance_fen bigint NOT NULL DEFAULT 0,
+    charges_fen bigint NOT NULL DEFAULT 0,
+    payments_fen bigint NOT NULL DEFAULT 0,
+    adjustments_fen bigint NOT NULL DEFAULT 0,
+    closing_balance_fen bigint NOT NULL DEFAULT 0,
+    file_path text,
+    error_message text,
+    requested_by uuid NOT NULL,
+    generated_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT customer_statements_customer_period_unique UNIQUE (customer_id, period),
+    CONSTRAINT customer_statements_period_range CHECK (period_end > period_start),
+    CONSTRAINT customer_statements_status_valid CHECK (status IN ('PENDING', 'RUNNING', 'SUCCEEDED', 'FAILED'))
+);
+
+CREATE INDEX IF NOT EXISTS customer_statements_pending_idx
+    ON customer_statements(created_at)
+    WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS receivables_customer_posted_idx
+    ON receivables(customer_id, posted_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS receivables_customer_posted_idx;
+DROP TABLE IF EXISTS customer_statements;
+-- +goose StatementEnd
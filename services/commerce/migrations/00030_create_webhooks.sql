@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    customer_id uuid NOT NULL,
+    url text NOT NULL,
+    secret text NOT NULL,
+    event_types text[] NOT NULL,
+    description text,
+    is_active boolean NOT NULL DEFAULT true,
+    created_by uuid NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT webhook_subscriptions_event_types_not_empty CHECK (cardinality(event_types) > 0)
+);
+
+CREATE INDEX IF NOT EXISTS webhook_subscriptions_customer_id_idx
+    ON webhook_subscriptions(customer_id)
+    WHERE is_active;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id uuid NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id uuid NOT NULL,
+    event_type text NOT NULL,
+    payload jsonb NOT NULL,
+    status text NOT NULL DEFAULT 'PENDING',
+    attempts integer NOT NULL DEFAULT 0,
+    replay_count integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz NOT NULL DEFAULT now(),
+    last_response_status integer,
+    last_error text,
+    delivered_at timestamptz,
+    last_replayed_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT webhook_deliveries_event_unique UNIQUE (subscription_id, event_id),
+    CONSTRAINT webhook_deliveries_status_valid CHECK (status IN ('PENDING', 'DELIVERED', 'DEAD'))
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_next_attempt_at_idx
+    ON webhook_deliveries(next_attempt_at)
+    WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_subscription_id_created_at_idx
+    ON webhook_deliveries(subscription_id, created_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE order_tracking_shipments
+    ADD COLUMN IF NOT EXISTS tracking_status text NOT NULL DEFAULT 'PENDING',
+    ADD COLUMN IF NOT EXISTS last_event_at timestamptz,
+    ADD COLUMN IF NOT EXISTS delivered_at timestamptz,
+    ADD COLUMN IF NOT EXISTS last_polled_at timestamptz;
+
+ALTER TABLE order_tracking_shipments
+    ADD CONSTRAINT order_tracking_shipments_tracking_status_check
+    CHECK (tracking_status IN ('PENDING', 'IN_TRANSIT', 'DELIVERED', 'EXCEPTION'));
+
+CREATE INDEX IF NOT EXISTS order_tracking_shipments_waybill_idx
+    ON order_tracking_shipments(waybill_no);
+
+CREATE INDEX IF NOT EXISTS order_tracking_shipments_poll_idx
+    ON order_tracking_shipments(last_polled_at NULLS FIRST)
+    WHERE tracking_status <> 'DELIVERED';
+
+CREATE TABLE IF NOT EXISTS shipment_events (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    shipment_id uuid NOT NULL REFERENCES order_tracking_shipments(id) ON DELETE CASCADE,
+    status text NOT NULL,
+    description text NOT NULL,
+    location text,
+    occurred_at timestamptz NOT NULL,
+    source text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT shipment_events_status_check CHECK (status IN ('PENDING', 'IN_TRANSIT', 'DELIVERED', 'EXCEPTION')),
+    CONSTRAINT shipment_events_source_check CHECK (source IN ('POLL', 'PUSH')),
+    UNIQUE (shipment_id, occurred_at, status, description)
+);
+
+CREATE INDEX IF NOT EXISTS shipment_events_shipment_idx
+    ON shipment_events(shipment_id, occurred_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shipment_events;
+DROP INDEX IF EXISTS order_tracking_shipments_poll_idx;
+DROP INDEX IF EXISTS order_tracking_shipments_waybill_idx;
+ALTER TABLE order_tracking_shipments
+    DROP CONSTRAINT IF EXISTS order_tracking_shipments_tracking_status_check,
+    DROP COLUMN IF EXISTS last_polled_at,
+    DROP COLUMN IF EXISTS delivered_at,
+    DROP COLUMN IF EXISTS last_event_at,
+    DROP COLUMN IF EXISTS tracking_status;
+-- +goose StatementEnd
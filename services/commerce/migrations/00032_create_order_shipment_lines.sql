@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS order_shipment_lines (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    shipment_id uuid NOT NULL REFERENCES order_tracking_shipments(id) ON DELETE CASCADE,
+    order_item_id uuid NOT NULL REFERENCES order_items(id) ON DELETE CASCADE,
+    qty integer NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT order_shipment_lines_qty_check CHECK (qty > 0),
+    UNIQUE (shipment_id, order_item_id)
+);
+
+CREATE INDEX IF NOT EXISTS order_shipment_lines_order_item_idx
+    ON order_shipment_lines(order_item_id);
+
+-- Before shipment lines existed the first waybill of a shipped order covered
+-- every item; record that so shipped quantities add up for old orders.
+INSERT INTO order_shipment_lines (shipment_id, order_item_id, qty)
+SELECT s.id, oi.id, oi.qty
+FROM orders o
+JOIN LATERAL (
+    SELECT t.id
+    FROM order_tracking_shipments t
+    WHERE t.order_id = o.id
+    ORDER BY t.created_at ASC, t.id ASC
+    LIMIT 1
+) s ON true
+JOIN order_items oi ON oi.order_id = o.id
+WHERE o.status IN ('SHIPPED', 'DELIVERED')
+  AND oi.qty > 0
+ON CONFLICT (shipment_id, order_item_id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS order_shipment_lines;
+-- +goose StatementEnd
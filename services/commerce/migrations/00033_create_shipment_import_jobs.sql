@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS shipment_import_jobs (
+    job_id uuid PRIMARY KEY REFERENCES import_jobs(id) ON DELETE CASCADE,
+    excel_file_path text NOT NULL,
+    excel_file_name text NOT NULL,
+    total_rows integer NOT NULL DEFAULT 0,
+    success_rows integer NOT NULL DEFAULT 0,
+    failed_rows integer NOT NULL DEFAULT 0,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS shipment_import_rows (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    job_id uuid NOT NULL REFERENCES shipment_import_jobs(job_id) ON DELETE CASCADE,
+    line_no integer NOT NULL,
+    order_ref text,
+    waybill_no text,
+    row_data jsonb NOT NULL DEFAULT '{}'::jsonb,
+    status text NOT NULL,
+    error_message text,
+    order_id uuid,
+    shipment_id uuid,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (job_id, line_no)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shipment_import_rows_job_status
+    ON shipment_import_rows (job_id, status, line_no);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS shipment_import_rows;
+DROP TABLE IF EXISTS shipment_import_jobs;
+-- +goose StatementEnd
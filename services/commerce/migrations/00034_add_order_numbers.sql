@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+-- order_number_sequences hands out the daily counter of order numbers. The
+-- counter row is locked by the order transaction that increments it, so
+-- concurrent orders get distinct consecutive numbers and a rolled back order
+-- gives its number back.
+CREATE TABLE IF NOT EXISTS order_number_sequences (
+    day date PRIMARY KEY,
+    last_value bigint NOT NULL
+);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS order_no text;
+
+WITH numbered AS (
+    SELECT
+        id,
+        (created_at AT TIME ZONE 'Asia/Shanghai')::date AS day,
+        row_number() OVER (
+            PARTITION BY (created_at AT TIME ZONE 'Asia/Shanghai')::date
+            ORDER BY created_at, id
+        ) AS seq
+    FROM orders
+    WHERE order_no IS NULL
+)
+UPDATE orders o
+SET order_no = 'TMO' || to_char(numbered.day, 'YYYYMMDD') || '-' || lpad(numbered.seq::text, 6, '0')
+FROM numbered
+WHERE o.id = numbered.id;
+
+INSERT INTO order_number_sequences (day, last_value)
+SELECT (created_at AT TIME ZONE 'Asia/Shanghai')::date, count(*)
+FROM orders
+GROUP BY 1
+ON CONFLICT (day) DO NOTHING;
+
+ALTER TABLE orders ALTER COLUMN order_no SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS orders_order_no_idx ON orders(order_no);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS orders_order_no_idx;
+ALTER TABLE orders DROP COLUMN IF EXISTS order_no;
+DROP TABLE IF EXISTS order_number_sequences;
+-- +goose StatementEnd
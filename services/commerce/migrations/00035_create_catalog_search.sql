@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE IF NOT EXISTS catalog_search_documents (
+    product_id uuid PRIMARY KEY REFERENCES catalog_products(id) ON DELETE CASCADE,
+    search_text text NOT NULL,
+    pinyin_initials text NOT NULL,
+    document tsvector NOT NULL,
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_search_documents_document
+    ON catalog_search_documents USING gin (document);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_search_documents_search_text_trgm
+    ON catalog_search_documents USING gin (search_text gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_catalog_search_documents_pinyin_initials_trgm
+    ON catalog_search_documents USING gin (pinyin_initials gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS catalog_search_documents;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS quotations (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    inquiry_id uuid NOT NULL REFERENCES price_inquiries(id) ON DELETE CASCADE,
+    version integer NOT NULL,
+    customer_id uuid NOT NULL,
+    owner_sales_user_id uuid,
+    created_by_user_id uuid NOT NULL,
+    status text NOT NULL,
+    valid_until date NOT NULL,
+    terms text,
+    decision_note text,
+    decided_at timestamptz,
+    order_id uuid REFERENCES orders(id) ON DELETE SET NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    UNIQUE (inquiry_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS quotations_customer_idx ON quotations(customer_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS quotation_items (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    quotation_id uuid NOT NULL REFERENCES quotations(id) ON DELETE CASCADE,
+    sku_id uuid NOT NULL REFERENCES catalog_skus(id),
+    qty integer NOT NULL CHECK (qty > 0),
+    unit_price_fen bigint NOT NULL CHECK (unit_price_fen >= 0),
+    line_no integer NOT NULL,
+    UNIQUE (quotation_id, sku_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS quotation_items;
+DROP TABLE IF EXISTS quotations;
+-- +goose StatementEnd
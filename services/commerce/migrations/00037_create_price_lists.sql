@@ -0,0 +1,82 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS price_lists (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name text NOT NULL,
+    description text,
+    priority integer NOT NULL DEFAULT 0,
+    is_active boolean NOT NULL DEFAULT true,
+    valid_from timestamptz,
+    valid_until timestamptz,
+    created_by uuid NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT price_lists_validity_window CHECK (valid_from IS NULL OR valid_until IS NULL OR valid_until > valid_from)
+);
+
+CREATE TABLE IF NOT EXISTS price_list_assignments (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    price_list_id uuid NOT NULL REFERENCES price_lists(id) ON DELETE CASCADE,
+    customer_id uuid,
+    customer_tag_id uuid,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT price_list_assignments_one_target CHECK ((customer_id IS NULL) <> (customer_tag_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS price_list_assignments_customer_uidx
+    ON price_list_assignments(price_list_id, customer_id)
+    WHERE customer_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS price_list_assignments_tag_uidx
+    ON price_list_assignments(price_list_id, customer_tag_id)
+    WHERE customer_tag_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS price_list_assignments_customer_idx
+    ON price_list_assignments(customer_id)
+    WHERE customer_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS price_list_assignments_tag_idx
+    ON price_list_assignments(customer_tag_id)
+    WHERE customer_tag_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS price_list_rules (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    price_list_id uuid NOT NULL REFERENCES price_lists(id) ON DELETE CASCADE,
+    sku_id uuid REFERENCES catalog_skus(id) ON DELETE CASCADE,
+    category_id uuid REFERENCES catalog_categories(id) ON DELETE CASCADE,
+    kind text NOT NULL,
+    discount_bps integer,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT price_list_rules_one_target CHECK ((sku_id IS NULL) <> (category_id IS NULL)),
+    CONSTRAINT price_list_rules_kind CHECK (
+        (kind = 'FIXED_TIERS' AND sku_id IS NOT NULL AND discount_bps IS NULL)
+        OR (kind = 'PERCENT_OFF' AND discount_bps BETWEEN 1 AND 10000)
+    )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS price_list_rules_sku_uidx
+    ON price_list_rules(price_list_id, sku_id)
+    WHERE sku_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS price_list_rules_category_uidx
+    ON price_list_rules(price_list_id, category_id)
+    WHERE category_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS price_list_rule_tiers (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    rule_id uuid NOT NULL REFERENCES price_list_rules(id) ON DELETE CASCADE,
+    min_qty integer NOT NULL CHECK (min_qty > 0),
+    max_qty integer CHECK (max_qty IS NULL OR max_qty >= min_qty),
+    unit_price_fen bigint NOT NULL CHECK (unit_price_fen >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS price_list_rule_tiers_rule_idx ON price_list_rule_tiers(rule_id, min_qty);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS price_list_rule_tiers;
+DROP TABLE IF EXISTS price_list_rules;
+DROP TABLE IF EXISTS price_list_assignments;
+DROP TABLE IF EXISTS price_lists;
+-- +goose StatementEnd
@@ -0,0 +1,70 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS promotions (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name text NOT NULL,
+    description text,
+    kind text NOT NULL,
+    discount_type text NOT NULL,
+    discount_value bigint NOT NULL,
+    max_discount_fen bigint,
+    min_spend_fen bigint NOT NULL DEFAULT 0,
+    category_id uuid REFERENCES catalog_categories(id) ON DELETE RESTRICT,
+    stackable boolean NOT NULL DEFAULT false,
+    per_customer_limit integer,
+    is_active boolean NOT NULL DEFAULT true,
+    starts_at timestamptz,
+    ends_at timestamptz,
+    created_by uuid NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT promotions_kind CHECK (kind IN ('COUPON', 'AUTOMATIC')),
+    CONSTRAINT promotions_discount CHECK (
+        (discount_type = 'FIXED' AND discount_value > 0 AND max_discount_fen IS NULL)
+        OR (discount_type = 'PERCENT' AND discount_value BETWEEN 1 AND 10000 AND (max_discount_fen IS NULL OR max_discount_fen > 0))
+    ),
+    CONSTRAINT promotions_min_spend CHECK (min_spend_fen >= 0),
+    CONSTRAINT promotions_per_customer_limit CHECK (per_customer_limit IS NULL OR per_customer_limit > 0),
+    CONSTRAINT promotions_validity_window CHECK (starts_at IS NULL OR ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS promotions_kind_idx ON promotions(kind, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS coupons (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    promotion_id uuid NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+    code text NOT NULL,
+    max_redemptions integer NOT NULL DEFAULT 1 CHECK (max_redemptions > 0),
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS coupons_code_uidx ON coupons(upper(code));
+CREATE INDEX IF NOT EXISTS coupons_promotion_idx ON coupons(promotion_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS order_discounts (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id uuid NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    promotion_id uuid NOT NULL REFERENCES promotions(id) ON DELETE RESTRICT,
+    coupon_id uuid REFERENCES coupons(id) ON DELETE RESTRICT,
+    coupon_code text,
+    label text NOT NULL,
+    amount_fen bigint NOT NULL CHECK (amount_fen > 0),
+    position integer NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS order_discounts_order_idx ON order_discounts(order_id, position);
+CREATE INDEX IF NOT EXISTS order_discounts_promotion_idx ON order_discounts(promotion_id);
+CREATE INDEX IF NOT EXISTS order_discounts_coupon_idx ON order_discounts(coupon_id) WHERE coupon_id IS NOT NULL;
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS discount_fen bigint NOT NULL DEFAULT 0 CHECK (discount_fen >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN IF EXISTS discount_fen;
+DROP TABLE IF EXISTS order_discounts;
+DROP TABLE IF EXISTS coupons;
+DROP TABLE IF EXISTS promotions;
+-- +goose StatementEnd
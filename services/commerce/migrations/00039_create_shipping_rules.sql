@@ -0,0 +1,53 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE catalog_skus
+    ADD COLUMN IF NOT EXISTS weight_grams integer CHECK (weight_grams IS NULL OR weight_grams >= 0),
+    ADD COLUMN IF NOT EXISTS volume_cm3 integer CHECK (volume_cm3 IS NULL OR volume_cm3 >= 0);
+
+CREATE TABLE IF NOT EXISTS shipping_rules (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name text NOT NULL,
+    province text,
+    city text,
+    charge_type text NOT NULL,
+    first_weight_grams integer,
+    first_fee_fen bigint,
+    additional_weight_grams integer,
+    additional_fee_fen bigint,
+    volumetric_divisor integer,
+    flat_fee_fen bigint,
+    free_shipping_min_fen bigint,
+    is_active boolean NOT NULL DEFAULT true,
+    created_by uuid NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT shipping_rules_zone CHECK (city IS NULL OR province IS NOT NULL),
+    CONSTRAINT shipping_rules_charge CHECK (
+        (charge_type = 'WEIGHT'
+            AND first_weight_grams > 0 AND first_fee_fen >= 0
+            AND additional_weight_grams > 0 AND additional_fee_fen >= 0
+            AND (volumetric_divisor IS NULL OR volumetric_divisor > 0)
+            AND flat_fee_fen IS NULL)
+        OR (charge_type = 'FLAT'
+            AND flat_fee_fen >= 0
+            AND first_weight_grams IS NULL AND first_fee_fen IS NULL
+            AND additional_weight_grams IS NULL AND additional_fee_fen IS NULL
+            AND volumetric_divisor IS NULL)
+    ),
+    CONSTRAINT shipping_rules_free_shipping CHECK (free_shipping_min_fen IS NULL OR free_shipping_min_fen >= 0)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS shipping_rules_zone_uidx ON shipping_rules(COALESCE(province, ''), COALESCE(city, ''));
+
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS shipping_fen bigint NOT NULL DEFAULT 0 CHECK (shipping_fen >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN IF EXISTS shipping_fen;
+DROP TABLE IF EXISTS shipping_rules;
+ALTER TABLE catalog_skus
+    DROP COLUMN IF EXISTS volume_cm3,
+    DROP COLUMN IF EXISTS weight_grams;
+-- +goose StatementEnd
@@ -0,0 +1,129 @@
+-- name: UpsertCatalogSearchDocument :exec
+INSERT INTO catalog_search_documents (
+    product_id,
+    search_text,
+    pinyin_initials,
+    document,
+    updated_at
+) VALUES (
+    sqlc.arg('product_id'),
+    sqlc.arg('search_text'),
+    sqlc.arg('pinyin_initials'),
+    sqlc.arg('document')::text::tsvector,
+    now()
+)
+ON CONFLICT (product_id) DO UPDATE
+SET search_text = EXCLUDED.search_text,
+    pinyin_initials = EXCLUDED.pinyin_initials,
+    document = EXCLUDED.document,
+    updated_at = now();
+
+-- name: ListProductsNeedingSearchIndex :many
+SELECT p.id
+FROM catalog_products p
+LEFT JOIN catalog_search_documents d ON d.product_id = p.id
+WHERE d.product_id IS NULL
+   OR d.updated_at < p.updated_at
+   OR EXISTS (
+       SELECT 1
+       FROM catalog_skus s
+       WHERE s.product_id = p.id
+         AND s.updated_at > d.updated_at
+   )
+ORDER BY p.updated_at, p.id
+LIMIT sqlc.arg('batch_size');
+
+-- name: SearchCatalog :many
+SELECT p.id, p.name, p.description, p.category_id, p.cover_image_url, p.images, p.tags, p.filter_dimensions, p.created_at, p.updated_at, p.status,
+    matched_sku.id AS matched_sku_id,
+    matched_sku.name AS matched_sku_name,
+    matched_sku.sku_code AS matched_sku_code,
+    matched_sku.spec AS matched_sku_spec,
+    (
+        COALESCE(ts_rank_cd(d.document, sqlc.narg('query')::text::tsquery), 0)
+        + CASE
+            WHEN lower(p.name) LIKE sqlc.arg('term')::text || '%' THEN 1.0
+            WHEN lower(p.name) LIKE '%' || sqlc.arg('term')::text || '%' THEN 0.5
+            ELSE 0
+        END
+        + CASE
+            WHEN sqlc.narg('initials')::text IS NULL THEN 0
+            WHEN d.pinyin_initials LIKE sqlc.narg('initials')::text || '%' THEN 0.8
+            WHEN d.pinyin_initials LIKE '%' || sqlc.narg('initials')::text || '%' THEN 0.4
+            ELSE 0
+        END
+        + CASE WHEN matched_sku.id IS NULL THEN 0 ELSE 0.3 END
+        + COALESCE(word_similarity(sqlc.narg('fuzzy')::text, d.search_text), 0) * 0.2
+    )::float8 AS score
+FROM catalog_products p
+JOIN catalog_search_documents d ON d.product_id = p.id
+LEFT JOIN LATERAL (
+    SELECT s.id, s.name, s.sku_code, s.spec
+    FROM catalog_skus s
+    WHERE s.product_id = p.id
+      AND s.is_active
+      AND (
+          lower(s.name) LIKE '%' || sqlc.arg('term')::text || '%'
+          OR lower(COALESCE(s.sku_code, '')) LIKE '%' || sqlc.arg('term')::text || '%'
+          OR lower(COALESCE(s.spec, '')) LIKE '%' || sqlc.arg('term')::text || '%'
+      )
+    ORDER BY s.created_at, s.id
+    LIMIT 1
+) matched_sku ON true
+WHERE p.status = 'ACTIVE'
+  AND (
+      d.document @@ sqlc.narg('query')::text::tsquery
+      OR d.search_text LIKE '%' || sqlc.arg('term')::text || '%'
+      OR d.pinyin_initials LIKE '%' || sqlc.narg('initials')::text || '%'
+      OR sqlc.narg('fuzzy')::text <% d.search_text
+  )
+  AND (sqlc.narg('category_id')::uuid IS NULL OR p.category_id = sqlc.narg('category_id'))
+  AND (sqlc.narg('tag')::text IS NULL OR sqlc.narg('tag') = ANY(p.tags))
+  AND (sqlc.narg('filter_dimension')::text IS NULL OR sqlc.narg('filter_dimension') = ANY(p.filter_dimensions))
+ORDER BY score DESC, p.created_at DESC, p.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountCatalogSearch :one
+SELECT count(*)
+FROM catalog_products p
+JOIN catalog_search_documents d ON d.product_id = p.id
+WHERE p.status = 'ACTIVE'
+  AND (
+      d.document @@ sqlc.narg('query')::text::tsquery
+      OR d.search_text LIKE '%' || sqlc.arg('term')::text || '%'
+      OR d.pinyin_initials LIKE '%' || sqlc.narg('initials')::text || '%'
+      OR sqlc.narg('fuzzy')::text <% d.search_text
+  )
+  AND (sqlc.narg('category_id')::uuid IS NULL OR p.category_id = sqlc.narg('category_id'))
+  AND (sqlc.narg('tag')::text IS NULL OR sqlc.narg('tag') = ANY(p.tags))
+  AND (sqlc.narg('filter_dimension')::text IS NULL OR sqlc.narg('filter_dimension') = ANY(p.filter_dimensions));
+
+-- name: ListCatalogSearchFacets :many
+WITH matched AS (
+    SELECT p.category_id, p.tags, p.filter_dimensions
+    FROM catalog_products p
+    JOIN catalog_search_documents d ON d.product_id = p.id
+    WHERE p.status = 'ACTIVE'
+      AND (
+          d.document @@ sqlc.narg('query')::text::tsquery
+          OR d.search_text LIKE '%' || sqlc.arg('term')::text || '%'
+          OR d.pinyin_initials LIKE '%' || sqlc.narg('initials')::text || '%'
+          OR sqlc.narg('fuzzy')::text <% d.search_text
+      )
+      AND (sqlc.narg('category_id')::uuid IS NULL OR p.category_id = sqlc.narg('category_id'))
+      AND (sqlc.narg('tag')::text IS NULL OR sqlc.narg('tag') = ANY(p.tags))
+      AND (sqlc.narg('filter_dimension')::text IS NULL OR sqlc.narg('filter_dimension') = ANY(p.filter_dimensions))
+)
+SELECT 'category'::text AS facet, c.id::text AS value, c.name AS label, count(*) AS count
+FROM matched m
+JOIN catalog_categories c ON c.id = m.category_id
+GROUP BY c.id, c.name
+UNION ALL
+SELECT 'tag'::text AS facet, tag AS value, tag AS label, count(*) AS count
+FROM matched m, unnest(m.tags) AS tag
+GROUP BY tag
+UNION ALL
+SELECT 'filter_dimension'::text AS facet, dimension AS value, dimension AS label, count(*) AS count
+FROM matched m, unnest(m.filter_dimensions) AS dimension
+GROUP BY dimension
+ORDER BY facet, count DESC, value;
@@ -6,7 +6,9 @@ INSERT INTO catalog_skus (
     spec,
     attributes,
     unit,
-    is_active
+    is_active,
+    weight_grams,
+    volume_cm3
 ) VALUES (
     $1,
     $2,
@@ -14,9 +16,11 @@ INSERT INTO catalog_skus (
     $4,
     $5,
     $6,
-    $7
+    $7,
+    $8,
+    $9
 )
-RETURNING id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at;
+RETURNING id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at, weight_grams, volume_cm3;
 
 -- name: UpdateSku :one
 UPDATE catalog_skus
@@ -26,33 +30,35 @@ SET sku_code = $2,
     attributes = $5,
     unit = $6,
     is_active = $7,
+    weight_grams = $8,
+    volume_cm3 = $9,
     updated_at = now()
 WHERE id = $1
-RETURNING id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at;
+RETURNING id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at, weight_grams, volume_cm3;
 
 -- name: ListSkusByProduct :many
-SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at
+SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at, weight_grams, volume_cm3
 FROM catalog_skus
 WHERE product_id = $1
 ORDER BY created_at ASC;
 
 -- name: ListSkusByIDs :many
-SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at
+SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at, weight_grams, volume_cm3
 FROM catalog_skus
 WHERE id = ANY($1::uuid[]);
 
 -- name: ListSkusBySkuCode :many
-SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at
+SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at, weight_grams, volume_cm3
 FROM catalog_skus
 WHERE sku_code = $1;
 
 -- name: ListSkusByName :many
-SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at
+SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at, weight_grams, volume_cm3
 FROM catalog_skus
 WHERE name = $1;
 
 -- name: ListSkusByNameAndSpec :many
-SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at
+SELECT id, product_id, sku_code, name, spec, attributes, unit, is_active, created_at, updated_at, weight_grams, volume_cm3
 FROM catalog_skus
 WHERE name = sqlc.arg(name)
   AND spec = sqlc.arg(spec);
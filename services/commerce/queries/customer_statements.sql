@@ -0,0 +1,134 @@
+-- name: EnqueueCustomerStatement :one
+INSERT INTO customer_statements (
+    customer_id,
+    period,
+    period_start,
+    period_end,
+    requested_by
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+ON CONFLICT (customer_id, period) DO UPDATE
+SET status = 'PENDING',
+    error_message = NULL,
+    requested_by = EXCLUDED.requested_by,
+    updated_at = now()
+RETURNING *;
+
+-- name: ClaimNextPendingCustomerStatement :one
+UPDATE customer_statements
+SET status = 'RUNNING',
+    updated_at = now()
+WHERE id = (
+    SELECT cs.id
+    FROM customer_statements cs
+    WHERE cs.status = 'PENDING'
+    ORDER BY cs.created_at ASC
+    FOR UPDATE SKIP LOCKED
+    LIMIT 1
+)
+RETURNING *;
+
+-- name: ResetRunningCustomerStatements :execrows
+UPDATE customer_statements
+SET status = 'PENDING',
+    updated_at = now()
+WHERE status = 'RUNNING';
+
+-- name: CompleteCustomerStatement :one
+UPDATE customer_statements
+SET status = 'SUCCEEDED',
+    opening_balance_fen = $2,
+    charges_fen = $3,
+    payments_fen = $4,
+    adjustments_fen = $5,
+    closing_balance_fen = $6,
+    file_path = $7,
+    error_message = NULL,
+    generated_at = now(),
+    updated_at = now()
+WHERE id = $1
+  AND status = 'RUNNING'
+RETURNING *;
+
+-- name: FailCustomerStatement :one
+UPDATE customer_statements
+SET status = 'FAILED',
+    error_message = $2,
+    updated_at = now()
+WHERE id = $1
+  AND status = 'RUNNING'
+RETURNING *;
+
+-- name: GetCustomerStatement :one
+SELECT *
+FROM customer_statements
+WHERE id = $1;
+
+-- name: ListCustomerStatements :many
+SELECT *
+FROM customer_statements
+WHERE (sqlc.narg('customer_id')::uuid IS NULL OR customer_id = sqlc.narg('customer_id')::uuid)
+  AND (sqlc.narg('period')::text IS NULL OR period = sqlc.narg('period')::text)
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status')::text)
+ORDER BY period DESC, created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountCustomerStatements :one
+SELECT count(*)
+FROM customer_statements
+WHERE (sqlc.narg('customer_id')::uuid IS NULL OR customer_id = sqlc.narg('customer_id')::uuid)
+  AND (sqlc.narg('period')::text IS NULL OR period = sqlc.narg('period')::text)
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status')::text);
+
+-- name: SumStatementOpeningBalance :one
+SELECT COALESCE(sum(amount_fen), 0)::bigint
+FROM receivables
+WHERE customer_id = sqlc.arg('customer_id')
+  AND posted_at < sqlc.arg('period_start')
+  AND (settled_at IS NULL OR settled_at >= sqlc.arg('period_start'))
+  AND (voided_at IS NULL OR voided_at >= sqlc.arg('period_start'));
+
+-- name: ListStatementReceivables :many
+SELECT r.id, r.order_id, r.customer_id, r.owner_sales_user_id, r.amount_fen, r.status, r.term_days, r.posted_at, r.due_at, r.settled_at, r.settled_by, r.voided_at, r.created_at, r.updated_at, o.payment_channel, o.latest_payment_id
+FROM receivables r
+JOIN orders o ON o.id = r.order_id
+WHERE r.customer_id = sqlc.arg('customer_id')
+  AND (
+    (r.posted_at >= sqlc.arg('period_start') AND r.posted_at < sqlc.arg('period_end'))
+    OR (r.settled_at >= sqlc.arg('period_start') AND r.settled_at < sqlc.arg('period_end'))
+    OR (r.voided_at >= sqlc.arg('period_start') AND r.voided_at < sqlc.arg('period_end'))
+  )
+ORDER BY r.posted_at ASC, r.id ASC;
+
+-- name: ListStatementOrders :many
+SELECT *
+FROM orders
+WHERE customer_id = sqlc.arg('customer_id')
+  AND created_at >= sqlc.arg('period_start')
+  AND created_at < sqlc.arg('period_end')
+ORDER BY created_at ASC, id ASC;
+
+-- name: ListStatementOrderItems :many
+SELECT
+    oi.order_id,
+    oi.sku_id,
+    s.sku_code,
+    p.name AS product_name,
+    s.name AS sku_name,
+    s.spec,
+    s.unit,
+    oi.qty,
+    oi.unit_price_fen
+FROM order_items oi
+JOIN orders o ON o.id = oi.order_id
+JOIN catalog_skus s ON s.id = oi.sku_id
+JOIN catalog_products p ON p.id = s.product_id
+WHERE o.customer_id = sqlc.arg('customer_id')
+  AND o.created_at >= sqlc.arg('period_start')
+  AND o.created_at < sqlc.arg('period_end')
+ORDER BY o.created_at ASC, o.id ASC, oi.created_at ASC, oi.id ASC;
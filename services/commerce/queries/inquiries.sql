@@ -81,3 +81,9 @@ LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 SELECT count(*)
 FROM inquiry_messages
 WHERE inquiry_id = $1;
+
+-- name: GetPriceInquiryForUpdate :one
+SELECT id, created_by_user_id, owner_sales_user_id, assigned_sales_user_id, sku_id, order_id, message, status, response_note, created_at, updated_at
+FROM price_inquiries
+WHERE id = $1
+FOR UPDATE;
@@ -0,0 +1,117 @@
+-- name: GetSkuInventory :one
+SELECT sku_id, on_hand, reserved, low_stock_threshold, created_at, updated_at
+FROM sku_inventory
+WHERE sku_id = $1;
+
+-- name: ListSkuInventoryBySkuIDs :many
+SELECT sku_id, on_hand, reserved, low_stock_threshold, created_at, updated_at
+FROM sku_inventory
+WHERE sku_id = ANY(sqlc.arg('sku_ids')::uuid[]);
+
+-- name: LockSkuInventoryBySkuIDs :many
+SELECT sku_id, on_hand, reserved, low_stock_threshold, created_at, updated_at
+FROM sku_inventory
+WHERE sku_id = ANY(sqlc.arg('sku_ids')::uuid[])
+ORDER BY sku_id
+FOR UPDATE;
+
+-- name: EnsureSkuInventory :exec
+INSERT INTO sku_inventory (sku_id)
+VALUES ($1)
+ON CONFLICT (sku_id) DO NOTHING;
+
+-- name: UpdateSkuInventoryLevels :one
+UPDATE sku_inventory
+SET on_hand = $2,
+    reserved = $3,
+    updated_at = now()
+WHERE sku_id = $1
+RETURNING sku_id, on_hand, reserved, low_stock_threshold, created_at, updated_at;
+
+-- name: UpdateSkuInventoryThreshold :one
+UPDATE sku_inventory
+SET low_stock_threshold = $2,
+    updated_at = now()
+WHERE sku_id = $1
+RETURNING sku_id, on_hand, reserved, low_stock_threshold, created_at, updated_at;
+
+-- name: ListLowStockSkuInventory :many
+SELECT i.sku_id,
+       i.on_hand,
+       i.reserved,
+       i.low_stock_threshold,
+       i.updated_at,
+       s.product_id,
+       s.sku_code,
+       s.name,
+       s.spec,
+       s.is_active
+FROM sku_inventory i
+JOIN catalog_skus s ON s.id = i.sku_id
+WHERE i.on_hand - i.reserved <= i.low_stock_threshold
+ORDER BY i.on_hand - i.reserved ASC, s.name ASC, i.sku_id ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountLowStockSkuInventory :one
+SELECT count(*)
+FROM sku_inventory
+WHERE on_hand - reserved <= low_stock_threshold;
+
+-- name: CreateSkuInventoryMovement :one
+INSERT INTO sku_inventory_movements (
+    sku_id,
+    movement_type,
+    on_hand_delta,
+    reserved_delta,
+    on_hand_after,
+    reserved_after,
+    order_id,
+    actor_user_id,
+    reason,
+    note
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10
+)
+RETURNING id, sku_id, movement_type, on_hand_delta, reserved_delta, on_hand_after, reserved_after, order_id, actor_user_id, reason, note, created_at;
+
+-- name: ListSkuInventoryMovements :many
+SELECT id, sku_id, movement_type, on_hand_delta, reserved_delta, on_hand_after, reserved_after, order_id, actor_user_id, reason, note, created_at
+FROM sku_inventory_movements
+WHERE sku_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2;
+
+-- name: CreateSkuInventoryReservation :one
+INSERT INTO sku_inventory_reservations (
+    order_id,
+    sku_id,
+    qty
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+RETURNING id, order_id, sku_id, qty, status, created_at, updated_at;
+
+-- name: ListActiveSkuInventoryReservationsByOrder :many
+SELECT id, order_id, sku_id, qty, status, created_at, updated_at
+FROM sku_inventory_reservations
+WHERE order_id = $1
+  AND status = 'ACTIVE'
+ORDER BY sku_id
+FOR UPDATE;
+
+-- name: UpdateSkuInventoryReservationStatus :exec
+UPDATE sku_inventory_reservations
+SET status = $2,
+    updated_at = now()
+WHERE id = $1;
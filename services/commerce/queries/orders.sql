@@ -1,4 +1,11 @@
 -- name: CreateOrder :one
+WITH next_order_no AS (
+    INSERT INTO order_number_sequences (day, last_value)
+    VALUES ((now() AT TIME ZONE 'Asia/Shanghai')::date, 1)
+    ON CONFLICT (day) DO UPDATE
+    SET last_value = order_number_sequences.last_value + 1
+    RETURNING 'TMO' || to_char(day, 'YYYYMMDD') || '-' || lpad(last_value::text, 6, '0') AS order_no
+)
 INSERT INTO orders (
     status,
     customer_id,
@@ -6,7 +13,10 @@ INSERT INTO orders (
     address,
     remark,
     idempotency_key,
-    payment_status
+    payment_status,
+    discount_fen,
+    shipping_fen,
+    order_no
 ) VALUES (
     $1,
     $2,
@@ -14,7 +24,10 @@ INSERT INTO orders (
     $4,
     $5,
     $6,
-    $7
+    $7,
+    $8,
+    $9,
+    (SELECT order_no FROM next_order_no)
 )
 RETURNING *;
 
@@ -40,6 +53,7 @@ FROM orders
 WHERE (sqlc.narg('customer_id')::uuid IS NULL OR customer_id = sqlc.narg('customer_id'))
   AND (sqlc.narg('owner_sales_user_id')::uuid IS NULL OR owner_sales_user_id = sqlc.narg('owner_sales_user_id'))
   AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('order_no')::text IS NULL OR order_no ILIKE '%' || sqlc.narg('order_no') || '%' ESCAPE '\')
 ORDER BY created_at DESC
 LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
@@ -48,7 +62,8 @@ SELECT count(*)
 FROM orders
 WHERE (sqlc.narg('customer_id')::uuid IS NULL OR customer_id = sqlc.narg('customer_id'))
   AND (sqlc.narg('owner_sales_user_id')::uuid IS NULL OR owner_sales_user_id = sqlc.narg('owner_sales_user_id'))
-  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'));
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+  AND (sqlc.narg('order_no')::text IS NULL OR order_no ILIKE '%' || sqlc.narg('order_no') || '%' ESCAPE '\');
 
 -- name: ListOrderStatusStats :many
 SELECT status, count(*)::bigint AS order_count
@@ -63,6 +78,11 @@ SELECT *
 FROM orders
 WHERE id = $1;
 
+-- name: GetOrderByOrderNo :one
+SELECT *
+FROM orders
+WHERE order_no = $1;
+
 -- name: GetOrderForUpdate :one
 SELECT *
 FROM orders
@@ -123,12 +143,27 @@ WHERE o.status = $1
   )
 RETURNING o.*;
 
+-- name: ListUnpaidOrdersCreatedBefore :many
+SELECT *
+FROM orders
+WHERE status = ANY(sqlc.arg('statuses')::text[])
+  AND payment_status <> 'PAID'
+  AND created_at <= sqlc.arg('created_before')
+  AND NOT EXISTS (
+      SELECT 1
+      FROM receivables r
+      WHERE r.order_id = orders.id
+        AND r.status = 'OPEN'
+  )
+ORDER BY created_at ASC
+LIMIT sqlc.arg('limit');
+
 -- name: CreateOrderAdminEvent :one
 INSERT INTO order_admin_events (
     order_id, idempotency_key, actor_user_id, action, note,
     previous_status, new_status, previous_payment_status, new_payment_status,
-    previous_owner_sales_user_id, new_owner_sales_user_id
-) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+    previous_owner_sales_user_id, new_owner_sales_user_id, reason_code
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
 RETURNING *;
 
 -- name: GetOrderAdminEventByIdempotencyKey :one
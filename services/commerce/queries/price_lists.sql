@@ -0,0 +1,158 @@
+-- name: CreatePriceList :one
+INSERT INTO price_lists (
+    name,
+    description,
+    priority,
+    is_active,
+    valid_from,
+    valid_until,
+    created_by
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING *;
+
+-- name: GetPriceList :one
+SELECT *
+FROM price_lists
+WHERE id = $1;
+
+-- name: ListPriceLists :many
+SELECT *
+FROM price_lists
+ORDER BY priority DESC, created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountPriceLists :one
+SELECT count(*)
+FROM price_lists;
+
+-- name: UpdatePriceList :one
+UPDATE price_lists
+SET name = $2,
+    description = $3,
+    priority = $4,
+    is_active = $5,
+    valid_from = $6,
+    valid_until = $7,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: CreatePriceListAssignment :one
+INSERT INTO price_list_assignments (
+    price_list_id,
+    customer_id,
+    customer_tag_id
+) VALUES (
+    sqlc.arg('price_list_id'),
+    sqlc.narg('customer_id'),
+    sqlc.narg('customer_tag_id')
+)
+RETURNING *;
+
+-- name: DeletePriceListAssignments :exec
+DELETE FROM price_list_assignments
+WHERE price_list_id = $1;
+
+-- name: ListPriceListAssignments :many
+SELECT *
+FROM price_list_assignments
+WHERE price_list_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: CreatePriceListRule :one
+INSERT INTO price_list_rules (
+    price_list_id,
+    sku_id,
+    category_id,
+    kind,
+    discount_bps
+) VALUES (
+    sqlc.arg('price_list_id'),
+    sqlc.narg('sku_id'),
+    sqlc.narg('category_id'),
+    sqlc.arg('kind'),
+    sqlc.narg('discount_bps')
+)
+RETURNING *;
+
+-- name: DeletePriceListRule :execrows
+DELETE FROM price_list_rules
+WHERE id = $1 AND price_list_id = $2;
+
+-- name: ListPriceListRules :many
+SELECT *
+FROM price_list_rules
+WHERE price_list_id = $1
+ORDER BY created_at ASC, id ASC;
+
+-- name: CreatePriceListRuleTier :one
+INSERT INTO price_list_rule_tiers (
+    rule_id,
+    min_qty,
+    max_qty,
+    unit_price_fen
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+RETURNING *;
+
+-- name: ListPriceListRuleTiersByRules :many
+SELECT *
+FROM price_list_rule_tiers
+WHERE rule_id = ANY(sqlc.arg('rule_ids')::uuid[])
+ORDER BY rule_id, min_qty ASC;
+
+-- name: ListApplicablePriceListRules :many
+WITH RECURSIVE sku_categories AS (
+    SELECT s.id AS sku_id, p.category_id, 1 AS depth
+    FROM catalog_skus s
+    JOIN catalog_products p ON p.id = s.product_id
+    WHERE s.id = ANY(sqlc.arg('sku_ids')::uuid[])
+    UNION ALL
+    SELECT sc.sku_id, c.parent_id, sc.depth + 1
+    FROM sku_categories sc
+    JOIN catalog_categories c ON c.id = sc.category_id
+    WHERE c.parent_id IS NOT NULL AND sc.depth < 16
+),
+targets AS (
+    SELECT r.id AS rule_id, r.sku_id AS target_sku_id, 0 AS category_depth
+    FROM price_list_rules r
+    WHERE r.sku_id = ANY(sqlc.arg('sku_ids')::uuid[])
+    UNION ALL
+    SELECT r.id, sc.sku_id, sc.depth
+    FROM price_list_rules r
+    JOIN sku_categories sc ON sc.category_id = r.category_id
+)
+SELECT
+    t.target_sku_id::uuid AS target_sku_id,
+    t.category_depth::integer AS category_depth,
+    r.id AS rule_id,
+    r.kind,
+    r.discount_bps,
+    r.sku_id,
+    r.category_id,
+    l.id AS price_list_id,
+    l.name AS price_list_name,
+    l.priority,
+    l.created_at AS price_list_created_at,
+    a.customer_tag_id AS matched_customer_tag_id
+FROM targets t
+JOIN price_list_rules r ON r.id = t.rule_id
+JOIN price_lists l ON l.id = r.price_list_id
+JOIN price_list_assignments a ON a.price_list_id = l.id
+WHERE l.is_active
+  AND (l.valid_from IS NULL OR l.valid_from <= sqlc.arg('now')::timestamptz)
+  AND (l.valid_until IS NULL OR l.valid_until > sqlc.arg('now')::timestamptz)
+  AND (a.customer_id = sqlc.arg('customer_id')::uuid OR a.customer_tag_id = ANY(sqlc.arg('customer_tag_ids')::uuid[]))
+ORDER BY t.target_sku_id, r.id;
@@ -0,0 +1,183 @@
+-- name: CreatePromotion :one
+INSERT INTO promotions (
+    name,
+    description,
+    kind,
+    discount_type,
+    discount_value,
+    max_discount_fen,
+    min_spend_fen,
+    category_id,
+    stackable,
+    per_customer_limit,
+    is_active,
+    starts_at,
+    ends_at,
+    created_by
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13,
+    $14
+)
+RETURNING *;
+
+-- name: GetPromotion :one
+SELECT *
+FROM promotions
+WHERE id = $1;
+
+-- name: LockPromotion :one
+SELECT *
+FROM promotions
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListPromotions :many
+SELECT *
+FROM promotions
+WHERE sqlc.narg('kind')::text IS NULL OR kind = sqlc.narg('kind')::text
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountPromotions :one
+SELECT count(*)
+FROM promotions
+WHERE sqlc.narg('kind')::text IS NULL OR kind = sqlc.narg('kind')::text;
+
+-- name: UpdatePromotion :one
+UPDATE promotions
+SET name = $2,
+    description = $3,
+    stackable = $4,
+    per_customer_limit = $5,
+    is_active = $6,
+    starts_at = $7,
+    ends_at = $8,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListActiveAutomaticPromotions :many
+SELECT *
+FROM promotions
+WHERE kind = 'AUTOMATIC'
+  AND is_active
+  AND (starts_at IS NULL OR starts_at <= sqlc.arg('now')::timestamptz)
+  AND (ends_at IS NULL OR ends_at > sqlc.arg('now')::timestamptz)
+ORDER BY created_at ASC, id ASC;
+
+-- name: CreateCoupon :one
+INSERT INTO coupons (
+    promotion_id,
+    code,
+    max_redemptions
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+ON CONFLICT DO NOTHING
+RETURNING *;
+
+-- name: GetCouponByCode :one
+SELECT *
+FROM coupons
+WHERE upper(code) = upper(sqlc.arg('code')::text);
+
+-- name: LockCoupon :one
+SELECT *
+FROM coupons
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListCoupons :many
+SELECT
+    c.id,
+    c.promotion_id,
+    c.code,
+    c.max_redemptions,
+    c.created_at,
+    (
+        SELECT count(*)
+        FROM order_discounts d
+        JOIN orders o ON o.id = d.order_id
+        WHERE d.coupon_id = c.id
+          AND o.status NOT IN ('CANCELLED', 'CLOSED')
+    )::bigint AS redeemed_count
+FROM coupons c
+WHERE c.promotion_id = sqlc.arg('promotion_id')
+ORDER BY c.created_at DESC, c.code ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountCoupons :one
+SELECT count(*)
+FROM coupons
+WHERE promotion_id = $1;
+
+-- name: CountCouponRedemptions :one
+SELECT count(*)
+FROM order_discounts d
+JOIN orders o ON o.id = d.order_id
+WHERE d.coupon_id = $1
+  AND o.status NOT IN ('CANCELLED', 'CLOSED');
+
+-- name: CountCustomerPromotionRedemptions :one
+SELECT count(*)
+FROM order_discounts d
+JOIN orders o ON o.id = d.order_id
+WHERE d.promotion_id = sqlc.arg('promotion_id')
+  AND o.customer_id = sqlc.arg('customer_id')
+  AND o.status NOT IN ('CANCELLED', 'CLOSED');
+
+-- name: CreateOrderDiscount :one
+INSERT INTO order_discounts (
+    order_id,
+    promotion_id,
+    coupon_id,
+    coupon_code,
+    label,
+    amount_fen,
+    position
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING *;
+
+-- name: ListOrderDiscounts :many
+SELECT *
+FROM order_discounts
+WHERE order_id = $1
+ORDER BY position ASC;
+
+-- name: ListSkuCategoryAncestors :many
+WITH RECURSIVE sku_categories AS (
+    SELECT s.id AS sku_id, p.category_id, 1 AS depth
+    FROM catalog_skus s
+    JOIN catalog_products p ON p.id = s.product_id
+    WHERE s.id = ANY(sqlc.arg('sku_ids')::uuid[])
+    UNION ALL
+    SELECT sc.sku_id, c.parent_id, sc.depth + 1
+    FROM sku_categories sc
+    JOIN catalog_categories c ON c.id = sc.category_id
+    WHERE c.parent_id IS NOT NULL AND sc.depth < 16
+)
+SELECT sku_id::uuid AS sku_id, category_id::uuid AS category_id
+FROM sku_categories
+ORDER BY sku_id, depth;
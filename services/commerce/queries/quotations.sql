@@ -0,0 +1,99 @@
+-- name: CreateQuotation :one
+INSERT INTO quotations (
+    inquiry_id,
+    version,
+    customer_id,
+    owner_sales_user_id,
+    created_by_user_id,
+    status,
+    valid_until,
+    terms
+) VALUES (
+    sqlc.arg('inquiry_id'),
+    (SELECT COALESCE(max(version), 0) + 1 FROM quotations WHERE inquiry_id = sqlc.arg('inquiry_id')),
+    sqlc.arg('customer_id'),
+    sqlc.arg('owner_sales_user_id'),
+    sqlc.arg('created_by_user_id'),
+    sqlc.arg('status'),
+    sqlc.arg('valid_until'),
+    sqlc.arg('terms')
+)
+RETURNING id, inquiry_id, version, customer_id, owner_sales_user_id, created_by_user_id, status, valid_until, terms, decision_note, decided_at, order_id, created_at, updated_at;
+
+-- name: CreateQuotationItem :one
+INSERT INTO quotation_items (
+    quotation_id,
+    sku_id,
+    qty,
+    unit_price_fen,
+    line_no
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+RETURNING id, quotation_id, sku_id, qty, unit_price_fen, line_no;
+
+-- name: GetQuotation :one
+SELECT id, inquiry_id, version, customer_id, owner_sales_user_id, created_by_user_id, status, valid_until, terms, decision_note, decided_at, order_id, created_at, updated_at
+FROM quotations
+WHERE id = $1;
+
+-- name: GetQuotationForUpdate :one
+SELECT id, inquiry_id, version, customer_id, owner_sales_user_id, created_by_user_id, status, valid_until, terms, decision_note, decided_at, order_id, created_at, updated_at
+FROM quotations
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListQuotationsByInquiry :many
+SELECT id, inquiry_id, version, customer_id, owner_sales_user_id, created_by_user_id, status, valid_until, terms, decision_note, decided_at, order_id, created_at, updated_at
+FROM quotations
+WHERE inquiry_id = $1
+ORDER BY version DESC;
+
+-- name: ListQuotationItems :many
+SELECT id, quotation_id, sku_id, qty, unit_price_fen, line_no
+FROM quotation_items
+WHERE quotation_id = $1
+ORDER BY line_no;
+
+-- name: ListQuotationItemsByQuotations :many
+SELECT id, quotation_id, sku_id, qty, unit_price_fen, line_no
+FROM quotation_items
+WHERE quotation_id = ANY(sqlc.arg('quotation_ids')::uuid[])
+ORDER BY quotation_id, line_no;
+
+-- name: CountQuotationsByInquiryAndStatus :one
+SELECT count(*)
+FROM quotations
+WHERE inquiry_id = $1
+  AND status = $2;
+
+-- name: SupersedeQuotations :execrows
+UPDATE quotations
+SET status = sqlc.arg('status'),
+    updated_at = now()
+WHERE inquiry_id = sqlc.arg('inquiry_id')
+  AND status = ANY(sqlc.arg('from_statuses')::text[]);
+
+-- name: DecideQuotation :one
+UPDATE quotations
+SET status = sqlc.arg('status'),
+    decision_note = sqlc.narg('decision_note'),
+    decided_at = now(),
+    updated_at = now()
+WHERE id = sqlc.arg('id')
+  AND status = ANY(sqlc.arg('from_statuses')::text[])
+RETURNING id, inquiry_id, version, customer_id, owner_sales_user_id, created_by_user_id, status, valid_until, terms, decision_note, decided_at, order_id, created_at, updated_at;
+
+-- name: ConvertQuotation :one
+UPDATE quotations
+SET status = sqlc.arg('status'),
+    order_id = sqlc.arg('order_id'),
+    decided_at = COALESCE(decided_at, now()),
+    updated_at = now()
+WHERE id = sqlc.arg('id')
+  AND status = ANY(sqlc.arg('from_statuses')::text[])
+RETURNING id, inquiry_id, version, customer_id, owner_sales_user_id, created_by_user_id, status, valid_until, terms, decision_note, decided_at, order_id, created_at, updated_at;
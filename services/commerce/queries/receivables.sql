@@ -0,0 +1,67 @@
+-- name: LockCustomerReceivables :exec
+SELECT pg_advisory_xact_lock(hashtextextended('receivables:' || sqlc.arg('customer_id')::uuid::text, 0));
+
+-- name: SumOpenReceivablesByCustomer :one
+SELECT COALESCE(sum(amount_fen), 0)::bigint
+FROM receivables
+WHERE customer_id = $1
+  AND status = 'OPEN';
+
+-- name: CreateReceivable :one
+INSERT INTO receivables (
+    order_id,
+    customer_id,
+    owner_sales_user_id,
+    amount_fen,
+    term_days,
+    posted_at,
+    due_at
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING *;
+
+-- name: SettleReceivableByOrder :execrows
+UPDATE receivables
+SET status = 'SETTLED',
+    settled_at = now(),
+    settled_by = $2,
+    updated_at = now()
+WHERE order_id = $1
+  AND status = 'OPEN';
+
+-- name: VoidReceivableByOrder :execrows
+UPDATE receivables
+SET status = 'VOID',
+    voided_at = now(),
+    updated_at = now()
+WHERE order_id = $1
+  AND status = 'OPEN';
+
+-- name: ListOpenReceivables :many
+SELECT *
+FROM receivables
+WHERE status = 'OPEN'
+ORDER BY posted_at ASC, id ASC;
+
+-- name: ListReceivables :many
+SELECT *
+FROM receivables
+WHERE (sqlc.narg('customer_id')::uuid IS NULL OR customer_id = sqlc.narg('customer_id')::uuid)
+  AND (sqlc.narg('owner_sales_user_id')::uuid IS NULL OR owner_sales_user_id = sqlc.narg('owner_sales_user_id')::uuid)
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status')::text)
+ORDER BY posted_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountReceivables :one
+SELECT count(*)
+FROM receivables
+WHERE (sqlc.narg('customer_id')::uuid IS NULL OR customer_id = sqlc.narg('customer_id')::uuid)
+  AND (sqlc.narg('owner_sales_user_id')::uuid IS NULL OR owner_sales_user_id = sqlc.narg('owner_sales_user_id')::uuid)
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status')::text);
@@ -0,0 +1,110 @@
+-- name: CreateShipmentImportJob :one
+INSERT INTO shipment_import_jobs (
+    job_id,
+    excel_file_path,
+    excel_file_name
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+RETURNING job_id, excel_file_path, excel_file_name, total_rows, success_rows, failed_rows, created_at, updated_at;
+
+-- name: ClaimNextPendingShipmentImportJob :one
+WITH picked AS (
+    SELECT sj.job_id
+    FROM shipment_import_jobs sj
+    JOIN import_jobs ij ON ij.id = sj.job_id
+    WHERE ij.type = 'SHIPMENT_IMPORT'
+      AND ij.status = 'PENDING'
+    ORDER BY ij.created_at ASC
+    FOR UPDATE SKIP LOCKED
+    LIMIT 1
+), updated AS (
+    UPDATE import_jobs ij
+    SET status = 'RUNNING',
+        progress = 1,
+        updated_at = now()
+    FROM picked
+    WHERE ij.id = picked.job_id
+    RETURNING ij.id, ij.type, ij.status, ij.progress, ij.result_file_url, ij.error_report_url, ij.created_by_user_id, ij.created_at, ij.updated_at
+)
+SELECT
+    updated.id,
+    updated.type,
+    updated.status,
+    updated.progress,
+    updated.result_file_url,
+    updated.error_report_url,
+    updated.created_by_user_id,
+    updated.created_at,
+    updated.updated_at,
+    sj.job_id,
+    sj.excel_file_path,
+    sj.excel_file_name,
+    sj.total_rows,
+    sj.success_rows,
+    sj.failed_rows,
+    sj.created_at AS shipment_import_created_at,
+    sj.updated_at AS shipment_import_updated_at
+FROM updated
+JOIN shipment_import_jobs sj ON sj.job_id = updated.id;
+
+-- name: ResetRunningShipmentImportJobs :execrows
+UPDATE import_jobs
+SET status = 'PENDING',
+    progress = 0,
+    updated_at = now()
+WHERE type = 'SHIPMENT_IMPORT'
+  AND status = 'RUNNING';
+
+-- name: UpdateShipmentImportJobCounts :one
+UPDATE shipment_import_jobs
+SET total_rows = $2,
+    success_rows = $3,
+    failed_rows = $4,
+    updated_at = now()
+WHERE job_id = $1
+RETURNING job_id, excel_file_path, excel_file_name, total_rows, success_rows, failed_rows, created_at, updated_at;
+
+-- name: GetShipmentImportJob :one
+SELECT job_id, excel_file_path, excel_file_name, total_rows, success_rows, failed_rows, created_at, updated_at
+FROM shipment_import_jobs
+WHERE job_id = $1;
+
+-- name: CreateShipmentImportRow :one
+INSERT INTO shipment_import_rows (
+    job_id,
+    line_no,
+    order_ref,
+    waybill_no,
+    row_data,
+    status,
+    error_message
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+ON CONFLICT (job_id, line_no) DO UPDATE SET updated_at = now()
+RETURNING id, job_id, line_no, order_ref, waybill_no, row_data, status, error_message, order_id, shipment_id, created_at, updated_at;
+
+-- name: UpdateShipmentImportRowResult :one
+UPDATE shipment_import_rows
+SET status = $2,
+    error_message = $3,
+    order_id = $4,
+    shipment_id = $5,
+    updated_at = now()
+WHERE id = $1
+RETURNING id, job_id, line_no, order_ref, waybill_no, row_data, status, error_message, order_id, shipment_id, created_at, updated_at;
+
+-- name: ListShipmentImportRowsByJob :many
+SELECT id, job_id, line_no, order_ref, waybill_no, row_data, status, error_message, order_id, shipment_id, created_at, updated_at
+FROM shipment_import_rows
+WHERE job_id = $1
+ORDER BY line_no ASC;
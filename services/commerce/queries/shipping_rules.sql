@@ -0,0 +1,75 @@
+-- name: CreateShippingRule :one
+INSERT INTO shipping_rules (
+    name,
+    province,
+    city,
+    charge_type,
+    first_weight_grams,
+    first_fee_fen,
+    additional_weight_grams,
+    additional_fee_fen,
+    volumetric_divisor,
+    flat_fee_fen,
+    free_shipping_min_fen,
+    is_active,
+    created_by
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13
+)
+RETURNING *;
+
+-- name: GetShippingRule :one
+SELECT *
+FROM shipping_rules
+WHERE id = $1;
+
+-- name: ListShippingRules :many
+SELECT *
+FROM shipping_rules
+ORDER BY province NULLS FIRST, city NULLS FIRST, created_at ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountShippingRules :one
+SELECT count(*)
+FROM shipping_rules;
+
+-- name: ListActiveShippingRules :many
+SELECT *
+FROM shipping_rules
+WHERE is_active
+  AND (province IS NULL OR province = sqlc.arg(province)::text)
+ORDER BY created_at ASC;
+
+-- name: UpdateShippingRule :one
+UPDATE shipping_rules
+SET name = $2,
+    province = $3,
+    city = $4,
+    charge_type = $5,
+    first_weight_grams = $6,
+    first_fee_fen = $7,
+    additional_weight_grams = $8,
+    additional_fee_fen = $9,
+    volumetric_divisor = $10,
+    flat_fee_fen = $11,
+    free_shipping_min_fen = $12,
+    is_active = $13,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteShippingRule :execrows
+DELETE FROM shipping_rules
+WHERE id = $1;
@@ -14,10 +14,94 @@ ON CONFLICT (order_id, waybill_no)
 DO UPDATE SET carrier = EXCLUDED.carrier,
               shipped_at = EXCLUDED.shipped_at,
               updated_at = now()
-RETURNING id, order_id, waybill_no, carrier, shipped_at, created_at, updated_at;
+RETURNING id, order_id, waybill_no, carrier, shipped_at, created_at, updated_at, tracking_status, last_event_at, delivered_at, last_polled_at;
 
 -- name: ListTrackingShipments :many
-SELECT id, order_id, waybill_no, carrier, shipped_at, created_at, updated_at
+SELECT id, order_id, waybill_no, carrier, shipped_at, created_at, updated_at, tracking_status, last_event_at, delivered_at, last_polled_at
 FROM order_tracking_shipments
 WHERE order_id = $1
 ORDER BY created_at ASC;
+
+-- name: GetTrackingShipmentForUpdate :one
+SELECT id, order_id, waybill_no, carrier, shipped_at, created_at, updated_at, tracking_status, last_event_at, delivered_at, last_polled_at
+FROM order_tracking_shipments
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListTrackingShipmentsByWaybill :many
+SELECT id, order_id, waybill_no, carrier, shipped_at, created_at, updated_at, tracking_status, last_event_at, delivered_at, last_polled_at
+FROM order_tracking_shipments
+WHERE waybill_no = $1
+ORDER BY created_at ASC;
+
+-- name: ClaimTrackingShipmentsForPoll :many
+UPDATE order_tracking_shipments s
+SET last_polled_at = now()
+WHERE s.id IN (
+    SELECT t.id
+    FROM order_tracking_shipments t
+    JOIN orders o ON o.id = t.order_id
+    WHERE o.status = ANY(sqlc.arg('order_statuses')::text[])
+      AND t.carrier IS NOT NULL
+      AND t.tracking_status <> 'DELIVERED'
+      AND (t.last_polled_at IS NULL OR t.last_polled_at <= sqlc.arg('polled_before'))
+    ORDER BY t.last_polled_at ASC NULLS FIRST
+    LIMIT sqlc.arg('batch_size')
+    FOR UPDATE OF t SKIP LOCKED
+)
+RETURNING s.id, s.order_id, s.waybill_no, s.carrier, s.shipped_at, s.created_at, s.updated_at, s.tracking_status, s.last_event_at, s.delivered_at, s.last_polled_at;
+
+-- name: UpdateTrackingShipmentStatus :one
+UPDATE order_tracking_shipments
+SET tracking_status = sqlc.arg('tracking_status'),
+    last_event_at = sqlc.narg('last_event_at'),
+    delivered_at = sqlc.narg('delivered_at'),
+    updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING id, order_id, waybill_no, carrier, shipped_at, created_at, updated_at, tracking_status, last_event_at, delivered_at, last_polled_at;
+
+-- name: CreateShipmentEvent :execrows
+INSERT INTO shipment_events (
+    shipment_id,
+    status,
+    description,
+    location,
+    occurred_at,
+    source
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+)
+ON CONFLICT (shipment_id, occurred_at, status, description) DO NOTHING;
+
+-- name: ListShipmentEventsByOrder :many
+SELECT e.id, e.shipment_id, e.status, e.description, e.location, e.occurred_at, e.source, e.created_at
+FROM shipment_events e
+JOIN order_tracking_shipments s ON s.id = e.shipment_id
+WHERE s.order_id = $1
+ORDER BY e.occurred_at DESC, e.created_at DESC;
+
+-- name: CreateShipmentLine :one
+INSERT INTO order_shipment_lines (
+    shipment_id,
+    order_item_id,
+    qty
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+RETURNING id, shipment_id, order_item_id, qty, created_at;
+
+-- name: ListShipmentLinesByOrder :many
+SELECT l.id, l.shipment_id, l.order_item_id, l.qty, l.created_at, s.waybill_no, oi.sku_id, cs.name AS sku_name
+FROM order_shipment_lines l
+JOIN order_tracking_shipments s ON s.id = l.shipment_id
+JOIN order_items oi ON oi.id = l.order_item_id
+JOIN catalog_skus cs ON cs.id = oi.sku_id
+WHERE s.order_id = $1
+ORDER BY s.created_at ASC, l.created_at ASC, l.id ASC;
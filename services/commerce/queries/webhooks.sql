@@ -0,0 +1,150 @@
+-- name: CreateWebhookSubscription :one
+INSERT INTO webhook_subscriptions (
+    customer_id,
+    url,
+    secret,
+    event_types,
+    description,
+    created_by
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+)
+RETURNING *;
+
+-- name: GetWebhookSubscription :one
+SELECT *
+FROM webhook_subscriptions
+WHERE id = $1;
+
+-- name: ListWebhookSubscriptions :many
+SELECT *
+FROM webhook_subscriptions
+WHERE (sqlc.narg('customer_id')::uuid IS NULL OR customer_id = sqlc.narg('customer_id')::uuid)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountWebhookSubscriptions :one
+SELECT count(*)
+FROM webhook_subscriptions
+WHERE (sqlc.narg('customer_id')::uuid IS NULL OR customer_id = sqlc.narg('customer_id')::uuid);
+
+-- name: ListActiveWebhookSubscriptionsByCustomer :many
+SELECT *
+FROM webhook_subscriptions
+WHERE customer_id = $1
+  AND is_active
+ORDER BY created_at ASC, id ASC;
+
+-- name: UpdateWebhookSubscription :one
+UPDATE webhook_subscriptions
+SET url = $2,
+    event_types = $3,
+    description = $4,
+    is_active = $5,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: RotateWebhookSubscriptionSecret :one
+UPDATE webhook_subscriptions
+SET secret = $2,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteWebhookSubscription :execrows
+DELETE FROM webhook_subscriptions
+WHERE id = $1;
+
+-- name: CreateWebhookDelivery :execrows
+INSERT INTO webhook_deliveries (
+    subscription_id,
+    event_id,
+    event_type,
+    payload
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+ON CONFLICT (subscription_id, event_id) DO NOTHING;
+
+-- name: ClaimDueWebhookDeliveries :many
+UPDATE webhook_deliveries
+SET attempts = attempts + 1,
+    next_attempt_at = now() + make_interval(secs => sqlc.arg('lease_seconds')::float8),
+    updated_at = now()
+WHERE id IN (
+    SELECT d.id
+    FROM webhook_deliveries d
+    JOIN webhook_subscriptions s ON s.id = d.subscription_id
+    WHERE d.status = 'PENDING'
+      AND d.next_attempt_at <= now()
+      AND s.is_active
+    ORDER BY d.next_attempt_at
+    LIMIT sqlc.arg('batch_size')
+    FOR UPDATE OF d SKIP LOCKED
+)
+RETURNING *;
+
+-- name: ReplayWebhookDelivery :one
+UPDATE webhook_deliveries
+SET status = 'PENDING',
+    attempts = 1,
+    replay_count = replay_count + 1,
+    last_replayed_at = now(),
+    next_attempt_at = now() + make_interval(secs => sqlc.arg('lease_seconds')::float8),
+    updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: MarkWebhookDeliveryDelivered :one
+UPDATE webhook_deliveries
+SET status = 'DELIVERED',
+    last_response_status = $2,
+    last_error = NULL,
+    delivered_at = now(),
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: MarkWebhookDeliveryFailed :one
+UPDATE webhook_deliveries
+SET status = sqlc.arg('status'),
+    last_response_status = sqlc.narg('last_response_status'),
+    last_error = sqlc.arg('last_error'),
+    next_attempt_at = now() + make_interval(secs => sqlc.arg('retry_seconds')::float8),
+    updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: GetWebhookDelivery :one
+SELECT *
+FROM webhook_deliveries
+WHERE id = $1;
+
+-- name: ListWebhookDeliveries :many
+SELECT d.*
+FROM webhook_deliveries d
+JOIN webhook_subscriptions s ON s.id = d.subscription_id
+WHERE (sqlc.narg('subscription_id')::uuid IS NULL OR d.subscription_id = sqlc.narg('subscription_id')::uuid)
+  AND (sqlc.narg('customer_id')::uuid IS NULL OR s.customer_id = sqlc.narg('customer_id')::uuid)
+  AND (sqlc.narg('status')::text IS NULL OR d.status = sqlc.narg('status')::text)
+  AND (sqlc.narg('event_type')::text IS NULL OR d.event_type = sqlc.narg('event_type')::text)
+ORDER BY d.created_at DESC, d.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountWebhookDeliveries :one
+SELECT count(*)
+FROM webhook_deliveries d
+JOIN webhook_subscriptions s ON s.id = d.subscription_id
+WHERE (sqlc.narg('subscription_id')::uuid IS NULL OR d.subscription_id = sqlc.narg('subscription_id')::uuid)
+  AND (sqlc.narg('customer_id')::uuid IS NULL OR s.customer_id = sqlc.narg('customer_id')::uuid)
+  AND (sqlc.narg('status')::text IS NULL OR d.status = sqlc.narg('status')::text)
+  AND (sqlc.narg('event_type')::text IS NULL OR d.event_type = sqlc.narg('event_type')::text);
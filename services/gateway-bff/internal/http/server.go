@@ -56,6 +56,7 @@ func NewRouter(handlers ProxyHandlers, logger *slog.Logger, readyCheck func(cont
 		router.GET("/catalog/products/:spuId", handlers.Commerce)
 	}
 
+	router.GET("/.well-known/jwks.json", handlers.Identity)
 	router.Any("/auth", handlers.Identity)
 	router.Any("/auth/*path", handlers.Identity)
 	router.Any("/me", handlers.Identity)
@@ -72,6 +73,7 @@ func NewRouter(handlers ProxyHandlers, logger *slog.Logger, readyCheck func(cont
 	router.Any("/admin/config/feature-flags", handlers.Identity)
 	router.Any("/admin/sales-users", handlers.Identity)
 	router.Any("/admin/users", handlers.Identity)
+	router.Any("/admin/users/*path", handlers.Identity)
 	router.Any("/admin/customers", handlers.Identity)
 	router.Any("/admin/customers/*path", handlers.Identity)
 	router.Any("/admin/customer-tags", handlers.Identity)
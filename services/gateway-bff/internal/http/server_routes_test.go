@@ -65,6 +65,30 @@ func TestAdminUsersRouteStillForwardsToIdentity(t *testing.T) {
 	}
 }
 
+func TestJWKSRouteForwardsToIdentity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := NewRouter(ProxyHandlers{
+		Identity:     markerHandler("identity"),
+		Commerce:     markerHandler("commerce"),
+		Payment:      markerHandler("payment"),
+		AI:           markerHandler("ai"),
+		Bootstrap:    markerHandler("bootstrap"),
+		AdminSummary: markerHandler("summary"),
+		Image:        markerHandler("image"),
+	}, nil, func(context.Context) error {
+		return nil
+	}, 0)
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	if got := recorder.Header().Get("X-Upstream"); got != "identity" {
+		t.Fatalf("expected identity upstream, got %s", got)
+	}
+}
+
 func markerHandler(name string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("X-Upstream", name)
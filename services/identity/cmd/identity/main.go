@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -70,7 +71,10 @@ func run(ctx context.Context, cfg config.Config, logger *slog.Logger) error {
 	}
 	defer pool.Close()
 
-	authManager := auth.NewTokenManager(cfg.JWTSecret, cfg.JWTIssuer, cfg.AccessTokenTTL)
+	authManager, err := newTokenManager(cfg, logger)
+	if err != nil {
+		return fmt.Errorf("jwt signing key setup failed: %w", err)
+	}
 	store := db.New(pool)
 	platformResolver := platform.NewMiniLoginResolver(platform.Config{
 		Mode:                        platform.LoginMode(cfg.LoginMode),
@@ -137,3 +141,41 @@ func run(ctx context.Context, cfg config.Config, logger *slog.Logger) error {
 
 	return nil
 }
+
+// newTokenManager loads the signing key and any retired verification keys
+// from PEM files. Without a configured key an ephemeral one is generated,
+// which is fine for local development but logs everyone out on restart.
+func newTokenManager(cfg config.Config, logger *slog.Logger) (*auth.TokenManager, error) {
+	var signer crypto.Signer
+	if cfg.JWTPrivateKeyFile == "" {
+		logger.Warn("IDENTITY_JWT_PRIVATE_KEY_FILE is not set; signing tokens with an ephemeral key")
+		generated, err := auth.GenerateSigningKey()
+		if err != nil {
+			return nil, err
+		}
+		signer = generated
+	} else {
+		data, err := os.ReadFile(cfg.JWTPrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		signer, err = auth.ParsePrivateKey(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var retired []crypto.PublicKey
+	if cfg.JWTRetiredKeysFile != "" {
+		data, err := os.ReadFile(cfg.JWTRetiredKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		retired, err = auth.ParsePublicKeys(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return auth.NewTokenManager(signer, retired, cfg.JWTIssuer, cfg.AccessTokenTTL)
+}
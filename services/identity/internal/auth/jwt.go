@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"crypto"
 	"errors"
 	"fmt"
 	"strings"
@@ -8,6 +9,8 @@ import (
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/packages/go-shared/jwks"
 )
 
 var (
@@ -24,33 +27,98 @@ type Claims struct {
 	OwnerSalesUserID *uuid.UUID
 	DisplayName      *string
 	Phone            *string
+	SessionID        *uuid.UUID
+	TokenVersion     int
 	ExpiresAt        time.Time
 }
 
+// Session ties an access token to the login that minted it. TokenVersion is
+// the user's token version at issue time; bumping the stored version revokes
+// every access token carrying an older one.
+type Session struct {
+	ID           uuid.UUID
+	TokenVersion int
+}
+
+// TokenManager signs access tokens with a single private key and verifies
+// them against that key plus any retired keys, picked by the kid header.
+// Only the public halves are published, so services that verify tokens
+// cannot mint them.
 type TokenManager struct {
-	secret []byte
-	issuer string
-	ttl    time.Duration
+	signer  crypto.Signer
+	signing jwks.Key
+	method  jwt.SigningMethod
+	keys    map[string]jwks.Key
+	keySet  jwks.Set
+	issuer  string
+	ttl     time.Duration
 }
 
-func NewTokenManager(secret, issuer string, ttl time.Duration) *TokenManager {
-	return &TokenManager{
-		secret: []byte(secret),
-		issuer: issuer,
-		ttl:    ttl,
+// NewTokenManager signs with signer (Ed25519 or RSA) and keeps accepting
+// tokens signed by the retired public keys so a key rotation does not log
+// everyone out.
+func NewTokenManager(signer crypto.Signer, retired []crypto.PublicKey, issuer string, ttl time.Duration) (*TokenManager, error) {
+	if signer == nil {
+		return nil, fmt.Errorf("%w: signing key is required", ErrInvalidKey)
+	}
+	signing, err := jwks.NewKey(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	method := jwt.GetSigningMethod(signing.Algorithm)
+	if method == nil {
+		return nil, fmt.Errorf("%w: unsupported algorithm %s", ErrInvalidKey, signing.Algorithm)
 	}
+
+	published := []jwks.Key{signing}
+	keys := map[string]jwks.Key{signing.ID: signing}
+	for _, public := range retired {
+		key, err := jwks.NewKey(public)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := keys[key.ID]; exists {
+			continue
+		}
+		keys[key.ID] = key
+		published = append(published, key)
+	}
+	keySet, err := jwks.NewSet(published...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenManager{
+		signer:  signer,
+		signing: signing,
+		method:  method,
+		keys:    keys,
+		keySet:  keySet,
+		issuer:  issuer,
+		ttl:     ttl,
+	}, nil
 }
 
-func (m *TokenManager) Issue(userID uuid.UUID, role string, roles []string, userType string, ownerSalesUserID *uuid.UUID, displayName *string, phone *string) (string, time.Time, error) {
+// KeySet returns the public keys served at /.well-known/jwks.json, the
+// current signing key first.
+func (m *TokenManager) KeySet() jwks.Set {
+	return m.keySet
+}
+
+func (m *TokenManager) Issue(userID uuid.UUID, role string, roles []string, userType string, ownerSalesUserID *uuid.UUID, displayName *string, phone *string, session Session) (string, time.Time, error) {
 	now := time.Now()
 	expiresAt := now.Add(m.ttl)
 
 	claims := jwt.MapClaims{
 		"sub":  userID.String(),
 		"role": role,
+		"tv":   session.TokenVersion,
 		"exp":  expiresAt.Unix(),
 		"iat":  now.Unix(),
 	}
+	if session.ID != uuid.Nil {
+		claims["sid"] = session.ID.String()
+	}
 	if m.issuer != "" {
 		claims["iss"] = m.issuer
 	}
@@ -70,8 +138,9 @@ func (m *TokenManager) Issue(userID uuid.UUID, role string, roles []string, user
 		claims["phone"] = strings.TrimSpace(*phone)
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signed, err := token.SignedString(m.secret)
+	token := jwt.NewWithClaims(m.method, claims)
+	token.Header["kid"] = m.signing.ID
+	signed, err := token.SignedString(m.signer)
 	if err != nil {
 		return "", time.Time{}, fmt.Errorf("sign token: %w", err)
 	}
@@ -92,11 +161,13 @@ func (m *TokenManager) ParseAuthorization(raw string) (Claims, error) {
 
 func (m *TokenManager) Parse(raw string) (Claims, error) {
 	token, err := jwt.Parse(raw, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := m.keys[kid]
+		if !ok || token.Method.Alg() != key.Algorithm {
 			return nil, ErrInvalidToken
 		}
-		return m.secret, nil
-	})
+		return key.Public, nil
+	}, jwt.WithValidMethods(jwks.Algorithms))
 	if err != nil || !token.Valid {
 		return Claims{}, ErrInvalidToken
 	}
@@ -167,6 +238,17 @@ func (m *TokenManager) Parse(raw string) (Claims, error) {
 		}
 	}
 
+	if sidRaw, ok := mapClaims["sid"].(string); ok && sidRaw != "" {
+		sessionID, err := uuid.Parse(sidRaw)
+		if err != nil {
+			return Claims{}, ErrInvalidToken
+		}
+		claims.SessionID = &sessionID
+	}
+	if tokenVersion, ok := mapClaims["tv"].(float64); ok {
+		claims.TokenVersion = int(tokenVersion)
+	}
+
 	if expRaw, ok := mapClaims["exp"].(float64); ok {
 		claims.ExpiresAt = time.Unix(int64(expRaw), 0)
 	}
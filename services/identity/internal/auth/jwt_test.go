@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func newTestTokenManager(t *testing.T, retired []crypto.PublicKey) *TokenManager {
+	t.Helper()
+	signer, err := GenerateSigningKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	manager, err := NewTokenManager(signer, retired, "tmo-identity", time.Hour)
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	return manager
+}
+
+func TestIssueSignsWithKeyID(t *testing.T) {
+	manager := newTestTokenManager(t, nil)
+	token, _, err := manager.Issue(uuid.New(), "ADMIN", nil, "", nil, nil, nil, Session{})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		t.Fatalf("parse unverified: %v", err)
+	}
+	set := manager.KeySet()
+	if len(set.Keys) != 1 {
+		t.Fatalf("expected one published key, got %d", len(set.Keys))
+	}
+	if parsed.Header["alg"] != "EdDSA" || parsed.Header["kid"] != set.Keys[0].Kid {
+		t.Fatalf("unexpected header %v", parsed.Header)
+	}
+}
+
+func TestParseAcceptsRetiredKeys(t *testing.T) {
+	oldSigner, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa: %v", err)
+	}
+	oldManager, err := NewTokenManager(oldSigner, nil, "tmo-identity", time.Hour)
+	if err != nil {
+		t.Fatalf("old manager: %v", err)
+	}
+	userID := uuid.New()
+	oldToken, _, err := oldManager.Issue(userID, "ADMIN", nil, "", nil, nil, nil, Session{})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+
+	rotated := newTestTokenManager(t, []crypto.PublicKey{oldSigner.Public()})
+	claims, err := rotated.Parse(oldToken)
+	if err != nil {
+		t.Fatalf("expected retired key to verify: %v", err)
+	}
+	if claims.UserID != userID {
+		t.Fatalf("unexpected subject %s", claims.UserID)
+	}
+	if len(rotated.KeySet().Keys) != 2 {
+		t.Fatalf("expected signing and retired key to be published")
+	}
+
+	unrelated := newTestTokenManager(t, nil)
+	if _, err := unrelated.Parse(oldToken); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected unknown key to be rejected, got %v", err)
+	}
+}
+
+func TestParseRejectsHMACTokens(t *testing.T) {
+	manager := newTestTokenManager(t, nil)
+	kid := manager.KeySet().Keys[0].Kid
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": uuid.NewString(),
+		"iss": "tmo-identity",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = kid
+	signed, err := token.SignedString([]byte(kid))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if _, err := manager.Parse(signed); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected HMAC token to be rejected, got %v", err)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("marshal private: %v", err)
+	}
+	signer, err := ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}))
+	if err != nil {
+		t.Fatalf("parse private: %v", err)
+	}
+	if !public.Equal(signer.Public()) {
+		t.Fatal("parsed private key does not match")
+	}
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa: %v", err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatalf("marshal public: %v", err)
+	}
+	bundle := append(
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)})...,
+	)
+	keys, err := ParsePublicKeys(bundle)
+	if err != nil {
+		t.Fatalf("parse public: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("expected 2 keys, got %d", len(keys))
+	}
+
+	if _, err := ParsePrivateKey([]byte("not pem")); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected ErrInvalidKey, got %v", err)
+	}
+}
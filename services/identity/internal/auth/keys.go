@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidKey is returned for PEM data that holds no usable key.
+var ErrInvalidKey = errors.New("invalid key")
+
+// ParsePrivateKey parses a PEM encoded Ed25519 or RSA private key in PKCS#8
+// form, or an RSA key in PKCS#1 form.
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM block found", ErrInvalidKey)
+	}
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+		}
+		switch key := parsed.(type) {
+		case ed25519.PrivateKey:
+			return key, nil
+		case *rsa.PrivateKey:
+			return key, nil
+		default:
+			return nil, fmt.Errorf("%w: unsupported key type %T", ErrInvalidKey, parsed)
+		}
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected PEM block %q", ErrInvalidKey, block.Type)
+	}
+}
+
+// ParsePublicKeys parses every PEM encoded Ed25519 or RSA public key in
+// data. It is used for retired signing keys whose tokens must keep verifying
+// until they expire.
+func ParsePublicKeys(data []byte) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			break
+		}
+		data = rest
+		switch block.Type {
+		case "PUBLIC KEY":
+			parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+			}
+			switch parsed.(type) {
+			case ed25519.PublicKey, *rsa.PublicKey:
+				keys = append(keys, parsed)
+			default:
+				return nil, fmt.Errorf("%w: unsupported key type %T", ErrInvalidKey, parsed)
+			}
+		case "RSA PUBLIC KEY":
+			parsed, err := x509.ParsePKCS1PublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+			}
+			keys = append(keys, parsed)
+		default:
+			return nil, fmt.Errorf("%w: unexpected PEM block %q", ErrInvalidKey, block.Type)
+		}
+	}
+	return keys, nil
+}
+
+// GenerateSigningKey returns a fresh Ed25519 key. Identity falls back to one
+// when no key is configured; tokens it signs stop verifying on restart.
+func GenerateSigningKey() (crypto.Signer, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return private, nil
+}
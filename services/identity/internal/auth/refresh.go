@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+const refreshSecretBytes = 32
+
+// NewRefreshToken mints an opaque refresh token for sessionID and returns it
+// together with the hash to store. The session id prefix lets a presented
+// token be matched to its session even after it has been rotated away, which
+// is how reuse is detected.
+func NewRefreshToken(sessionID uuid.UUID) (string, string, error) {
+	secret := make([]byte, refreshSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", fmt.Errorf("generate refresh token: %w", err)
+	}
+	token := sessionID.String() + "." + base64.RawURLEncoding.EncodeToString(secret)
+	return token, HashRefreshToken(token), nil
+}
+
+// ParseRefreshToken returns the session a refresh token belongs to and the
+// hash to compare against the stored one.
+func ParseRefreshToken(raw string) (uuid.UUID, string, error) {
+	raw = strings.TrimSpace(raw)
+	sessionPart, secretPart, ok := strings.Cut(raw, ".")
+	if !ok || secretPart == "" {
+		return uuid.Nil, "", ErrInvalidRefreshToken
+	}
+	sessionID, err := uuid.Parse(sessionPart)
+	if err != nil {
+		return uuid.Nil, "", ErrInvalidRefreshToken
+	}
+	secret, err := base64.RawURLEncoding.DecodeString(secretPart)
+	if err != nil || len(secret) != refreshSecretBytes {
+		return uuid.Nil, "", ErrInvalidRefreshToken
+	}
+	return sessionID, HashRefreshToken(raw), nil
+}
+
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
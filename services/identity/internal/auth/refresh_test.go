@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestRefreshTokenRoundTrip(t *testing.T) {
+	sessionID := uuid.New()
+	token, hash, err := NewRefreshToken(sessionID)
+	if err != nil {
+		t.Fatalf("new refresh token: %v", err)
+	}
+	if strings.Contains(hash, token) || len(hash) != 64 {
+		t.Fatalf("unexpected hash %q", hash)
+	}
+
+	gotID, gotHash, err := ParseRefreshToken(token)
+	if err != nil {
+		t.Fatalf("parse refresh token: %v", err)
+	}
+	if gotID != sessionID || gotHash != hash {
+		t.Fatalf("unexpected parse result %s %s", gotID, gotHash)
+	}
+
+	_, other, err := NewRefreshToken(sessionID)
+	if err != nil {
+		t.Fatalf("new refresh token: %v", err)
+	}
+	if other == hash {
+		t.Fatal("expected rotated tokens to differ")
+	}
+}
+
+func TestParseRefreshTokenRejectsMalformed(t *testing.T) {
+	for _, raw := range []string{"", "abc", uuid.NewString() + ".", "nope.AAAA", uuid.NewString() + ".c2hvcnQ"} {
+		if _, _, err := ParseRefreshToken(raw); err != ErrInvalidRefreshToken {
+			t.Fatalf("expected %q to be rejected, got %v", raw, err)
+		}
+	}
+}
+
+func TestIssueCarriesSession(t *testing.T) {
+	manager := newTestTokenManager(t, nil)
+	sessionID := uuid.New()
+	token, _, err := manager.Issue(uuid.New(), "CUSTOMER", nil, "customer", nil, nil, nil, Session{ID: sessionID, TokenVersion: 3})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	claims, err := manager.Parse(token)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if claims.SessionID == nil || *claims.SessionID != sessionID || claims.TokenVersion != 3 {
+		t.Fatalf("unexpected session claims %+v", claims)
+	}
+}
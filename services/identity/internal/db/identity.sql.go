@@ -67,7 +67,7 @@ const bindOwnerSalesUser = `-- name: BindOwnerSalesUser :one
 UPDATE users
 SET owner_sales_user_id = $2, updated_at = now()
 WHERE id = $1 AND owner_sales_user_id IS NULL
-RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url
+RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url, credit_limit_fen, token_version, token_version_changed_at
 `
 
 type BindOwnerSalesUserParams struct {
@@ -94,6 +94,9 @@ func (q *Queries) BindOwnerSalesUser(ctx context.Context, arg BindOwnerSalesUser
 		&i.PaymentTermDays,
 		&i.PaymentTermCustomLabel,
 		&i.AvatarUrl,
+		&i.CreditLimitFen,
+		&i.TokenVersion,
+		&i.TokenVersionChangedAt,
 	)
 	return i, err
 }
@@ -104,7 +107,7 @@ SET phone = $2,
     updated_at = now()
 WHERE id = $1
   AND (phone IS NULL OR phone = $2)
-RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url
+RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url, credit_limit_fen, token_version, token_version_changed_at
 `
 
 type BindUserPhoneParams struct {
@@ -131,6 +134,9 @@ func (q *Queries) BindUserPhone(ctx context.Context, arg BindUserPhoneParams) (U
 		&i.PaymentTermDays,
 		&i.PaymentTermCustomLabel,
 		&i.AvatarUrl,
+		&i.CreditLimitFen,
+		&i.TokenVersion,
+		&i.TokenVersionChangedAt,
 	)
 	return i, err
 }
@@ -487,7 +493,7 @@ func (q *Queries) CreateStaffBindingToken(ctx context.Context, arg CreateStaffBi
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (id, display_name, phone, user_type, owner_sales_user_id)
 VALUES ($1, $2, $3, $4, $5)
-RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url
+RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url, credit_limit_fen, token_version, token_version_changed_at
 `
 
 type CreateUserParams struct {
@@ -523,6 +529,9 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.PaymentTermDays,
 		&i.PaymentTermCustomLabel,
 		&i.AvatarUrl,
+		&i.CreditLimitFen,
+		&i.TokenVersion,
+		&i.TokenVersionChangedAt,
 	)
 	return i, err
 }
@@ -588,7 +597,7 @@ SET user_type = 'customer',
     disabled_reason = NULL,
     updated_at = now()
 WHERE id = $1 AND user_type = 'staff'
-RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url
+RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url, credit_limit_fen, token_version, token_version_changed_at
 `
 
 func (q *Queries) DemoteStaffToCustomer(ctx context.Context, id uuid.UUID) (User, error) {
@@ -610,12 +619,15 @@ func (q *Queries) DemoteStaffToCustomer(ctx context.Context, id uuid.UUID) (User
 		&i.PaymentTermDays,
 		&i.PaymentTermCustomLabel,
 		&i.AvatarUrl,
+		&i.CreditLimitFen,
+		&i.TokenVersion,
+		&i.TokenVersionChangedAt,
 	)
 	return i, err
 }
 
 const getActiveSalesUserByID = `-- name: GetActiveSalesUserByID :one
-SELECT DISTINCT u.id, u.display_name, u.user_type, u.owner_sales_user_id, u.created_at, u.updated_at, u.status, u.disabled_at, u.disabled_reason, u.phone, u.payment_term_remark, u.payment_term_type, u.payment_term_days, u.payment_term_custom_label, u.avatar_url
+SELECT DISTINCT u.id, u.display_name, u.user_type, u.owner_sales_user_id, u.created_at, u.updated_at, u.status, u.disabled_at, u.disabled_reason, u.phone, u.payment_term_remark, u.payment_term_type, u.payment_term_days, u.payment_term_custom_label, u.avatar_url, u.credit_limit_fen, u.token_version, u.token_version_changed_at
 FROM users u
 JOIN user_roles ur ON ur.user_id = u.id
 WHERE u.id = $1
@@ -644,12 +656,15 @@ func (q *Queries) GetActiveSalesUserByID(ctx context.Context, id uuid.UUID) (Use
 		&i.PaymentTermDays,
 		&i.PaymentTermCustomLabel,
 		&i.AvatarUrl,
+		&i.CreditLimitFen,
+		&i.TokenVersion,
+		&i.TokenVersionChangedAt,
 	)
 	return i, err
 }
 
 const getCustomerByID = `-- name: GetCustomerByID :one
-SELECT id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url FROM users
+SELECT id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url, credit_limit_fen, token_version, token_version_changed_at FROM users
 WHERE id = $1 AND user_type = 'customer'
 `
 
@@ -672,12 +687,15 @@ func (q *Queries) GetCustomerByID(ctx context.Context, id uuid.UUID) (User, erro
 		&i.PaymentTermDays,
 		&i.PaymentTermCustomLabel,
 		&i.AvatarUrl,
+		&i.CreditLimitFen,
+		&i.TokenVersion,
+		&i.TokenVersionChangedAt,
 	)
 	return i, err
 }
 
 const getCustomerFinanceProfile = `-- name: GetCustomerFinanceProfile :one
-SELECT id, payment_term_type, payment_term_days, payment_term_custom_label, payment_term_remark, updated_at
+SELECT id, payment_term_type, payment_term_days, payment_term_custom_label, payment_term_remark, credit_limit_fen, updated_at
 FROM users
 WHERE id = $1 AND user_type = 'customer'
 `
@@ -688,6 +706,7 @@ type GetCustomerFinanceProfileRow struct {
 	PaymentTermDays        *int32             `db:"payment_term_days" json:"payment_term_days"`
 	PaymentTermCustomLabel *string            `db:"payment_term_custom_label" json:"payment_term_custom_label"`
 	PaymentTermRemark      *string            `db:"payment_term_remark" json:"payment_term_remark"`
+	CreditLimitFen         *int64             `db:"credit_limit_fen" json:"credit_limit_fen"`
 	UpdatedAt              pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
 }
 
@@ -700,6 +719,7 @@ func (q *Queries) GetCustomerFinanceProfile(ctx context.Context, id uuid.UUID) (
 		&i.PaymentTermDays,
 		&i.PaymentTermCustomLabel,
 		&i.PaymentTermRemark,
+		&i.CreditLimitFen,
 		&i.UpdatedAt,
 	)
 	return i, err
@@ -841,7 +861,7 @@ func (q *Queries) GetStaffBindingToken(ctx context.Context, token string) (Staff
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url FROM users WHERE id = $1
+SELECT id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url, credit_limit_fen, token_version, token_version_changed_at FROM users WHERE id = $1
 `
 
 func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
@@ -863,12 +883,15 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.PaymentTermDays,
 		&i.PaymentTermCustomLabel,
 		&i.AvatarUrl,
+		&i.CreditLimitFen,
+		&i.TokenVersion,
+		&i.TokenVersionChangedAt,
 	)
 	return i, err
 }
 
 const getUserByIdentity = `-- name: GetUserByIdentity :one
-SELECT u.id, u.display_name, u.user_type, u.owner_sales_user_id, u.created_at, u.updated_at, u.status, u.disabled_at, u.disabled_reason, u.phone, u.payment_term_remark, u.payment_term_type, u.payment_term_days, u.payment_term_custom_label, u.avatar_url FROM users u
+SELECT u.id, u.display_name, u.user_type, u.owner_sales_user_id, u.created_at, u.updated_at, u.status, u.disabled_at, u.disabled_reason, u.phone, u.payment_term_remark, u.payment_term_type, u.payment_term_days, u.payment_term_custom_label, u.avatar_url, u.credit_limit_fen, u.token_version, u.token_version_changed_at FROM users u
 JOIN user_identities i ON i.user_id = u.id
 WHERE i.provider = $1 AND i.provider_user_id = $2
 LIMIT 1
@@ -898,12 +921,15 @@ func (q *Queries) GetUserByIdentity(ctx context.Context, arg GetUserByIdentityPa
 		&i.PaymentTermDays,
 		&i.PaymentTermCustomLabel,
 		&i.AvatarUrl,
+		&i.CreditLimitFen,
+		&i.TokenVersion,
+		&i.TokenVersionChangedAt,
 	)
 	return i, err
 }
 
 const getUserByPhone = `-- name: GetUserByPhone :one
-SELECT id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url FROM users
+SELECT id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url, credit_limit_fen, token_version, token_version_changed_at FROM users
 WHERE phone = $1
 LIMIT 1
 `
@@ -927,6 +953,9 @@ func (q *Queries) GetUserByPhone(ctx context.Context, phone *string) (User, erro
 		&i.PaymentTermDays,
 		&i.PaymentTermCustomLabel,
 		&i.AvatarUrl,
+		&i.CreditLimitFen,
+		&i.TokenVersion,
+		&i.TokenVersionChangedAt,
 	)
 	return i, err
 }
@@ -974,7 +1003,7 @@ func (q *Queries) GetUserPasswordByUsername(ctx context.Context, username string
 }
 
 const listActiveSalesUsers = `-- name: ListActiveSalesUsers :many
-SELECT DISTINCT u.id, u.display_name, u.user_type, u.owner_sales_user_id, u.created_at, u.updated_at, u.status, u.disabled_at, u.disabled_reason, u.phone, u.payment_term_remark, u.payment_term_type, u.payment_term_days, u.payment_term_custom_label, u.avatar_url
+SELECT DISTINCT u.id, u.display_name, u.user_type, u.owner_sales_user_id, u.created_at, u.updated_at, u.status, u.disabled_at, u.disabled_reason, u.phone, u.payment_term_remark, u.payment_term_type, u.payment_term_days, u.payment_term_custom_label, u.avatar_url, u.credit_limit_fen, u.token_version, u.token_version_changed_at
 FROM users u
 JOIN user_roles ur ON ur.user_id = u.id
 WHERE u.user_type = 'staff'
@@ -1020,6 +1049,9 @@ func (q *Queries) ListActiveSalesUsers(ctx context.Context, arg ListActiveSalesU
 			&i.PaymentTermDays,
 			&i.PaymentTermCustomLabel,
 			&i.AvatarUrl,
+			&i.CreditLimitFen,
+			&i.TokenVersion,
+			&i.TokenVersionChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1032,7 +1064,7 @@ func (q *Queries) ListActiveSalesUsers(ctx context.Context, arg ListActiveSalesU
 }
 
 const listAdminCustomers = `-- name: ListAdminCustomers :many
-SELECT u.id, u.display_name, u.user_type, u.owner_sales_user_id, u.created_at, u.updated_at, u.status, u.disabled_at, u.disabled_reason, u.phone, u.payment_term_remark, u.payment_term_type, u.payment_term_days, u.payment_term_custom_label, u.avatar_url
+SELECT u.id, u.display_name, u.user_type, u.owner_sales_user_id, u.created_at, u.updated_at, u.status, u.disabled_at, u.disabled_reason, u.phone, u.payment_term_remark, u.payment_term_type, u.payment_term_days, u.payment_term_custom_label, u.avatar_url, u.credit_limit_fen, u.token_version, u.token_version_changed_at
 FROM users u
 JOIN user_roles ur ON ur.user_id = u.id
 WHERE ur.role = 'CUSTOMER'
@@ -1097,6 +1129,9 @@ func (q *Queries) ListAdminCustomers(ctx context.Context, arg ListAdminCustomers
 			&i.PaymentTermDays,
 			&i.PaymentTermCustomLabel,
 			&i.AvatarUrl,
+			&i.CreditLimitFen,
+			&i.TokenVersion,
+			&i.TokenVersionChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1109,7 +1144,7 @@ func (q *Queries) ListAdminCustomers(ctx context.Context, arg ListAdminCustomers
 }
 
 const listAdminUsers = `-- name: ListAdminUsers :many
-SELECT DISTINCT u.id, u.display_name, u.user_type, u.owner_sales_user_id, u.created_at, u.updated_at, u.status, u.disabled_at, u.disabled_reason, u.phone, u.payment_term_remark, u.payment_term_type, u.payment_term_days, u.payment_term_custom_label, u.avatar_url
+SELECT DISTINCT u.id, u.display_name, u.user_type, u.owner_sales_user_id, u.created_at, u.updated_at, u.status, u.disabled_at, u.disabled_reason, u.phone, u.payment_term_remark, u.payment_term_type, u.payment_term_days, u.payment_term_custom_label, u.avatar_url, u.credit_limit_fen, u.token_version, u.token_version_changed_at
 FROM users u
 JOIN user_roles ur ON ur.user_id = u.id
 WHERE u.user_type IN ('admin', 'staff')
@@ -1164,6 +1199,9 @@ func (q *Queries) ListAdminUsers(ctx context.Context, arg ListAdminUsersParams)
 			&i.PaymentTermDays,
 			&i.PaymentTermCustomLabel,
 			&i.AvatarUrl,
+			&i.CreditLimitFen,
+			&i.TokenVersion,
+			&i.TokenVersionChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1175,6 +1213,36 @@ func (q *Queries) ListAdminUsers(ctx context.Context, arg ListAdminUsersParams)
 	return items, nil
 }
 
+const listAllRolePermissions = `-- name: ListAllRolePermissions :many
+SELECT role_code, permission_code, scope FROM role_permissions ORDER BY role_code, permission_code
+`
+
+type ListAllRolePermissionsRow struct {
+	RoleCode       string `db:"role_code" json:"role_code"`
+	PermissionCode string `db:"permission_code" json:"permission_code"`
+	Scope          string `db:"scope" json:"scope"`
+}
+
+func (q *Queries) ListAllRolePermissions(ctx context.Context) ([]ListAllRolePermissionsRow, error) {
+	rows, err := q.db.Query(ctx, listAllRolePermissions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAllRolePermissionsRow
+	for rows.Next() {
+		var i ListAllRolePermissionsRow
+		if err := rows.Scan(&i.RoleCode, &i.PermissionCode, &i.Scope); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAuditLogs = `-- name: ListAuditLogs :many
 SELECT id, actor_user_id, action, target_type, target_id, metadata, request_id, ip, user_agent, created_at FROM audit_logs
 WHERE ($1::uuid IS NULL OR actor_user_id = $1)
@@ -1349,7 +1417,7 @@ func (q *Queries) ListCustomerTagsByIDs(ctx context.Context, tagIds []uuid.UUID)
 }
 
 const listCustomers = `-- name: ListCustomers :many
-SELECT id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url FROM users
+SELECT id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url, credit_limit_fen, token_version, token_version_changed_at FROM users
 WHERE user_type = 'customer'
   AND (
     $1::text IS NULL
@@ -1401,6 +1469,9 @@ func (q *Queries) ListCustomers(ctx context.Context, arg ListCustomersParams) ([
 			&i.PaymentTermDays,
 			&i.PaymentTermCustomLabel,
 			&i.AvatarUrl,
+			&i.CreditLimitFen,
+			&i.TokenVersion,
+			&i.TokenVersionChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1542,7 +1613,7 @@ func (q *Queries) ListRoles(ctx context.Context) ([]Role, error) {
 }
 
 const listStaffUsers = `-- name: ListStaffUsers :many
-SELECT id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url FROM users
+SELECT id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url, credit_limit_fen, token_version, token_version_changed_at FROM users
 WHERE user_type = 'staff'
   AND (
     $1::text IS NULL
@@ -1591,6 +1662,9 @@ func (q *Queries) ListStaffUsers(ctx context.Context, arg ListStaffUsersParams)
 			&i.PaymentTermDays,
 			&i.PaymentTermCustomLabel,
 			&i.AvatarUrl,
+			&i.CreditLimitFen,
+			&i.TokenVersion,
+			&i.TokenVersionChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1627,7 +1701,7 @@ func (q *Queries) ListUserRoles(ctx context.Context, userID uuid.UUID) ([]string
 }
 
 const listUsersByIDs = `-- name: ListUsersByIDs :many
-SELECT id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url
+SELECT id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url, credit_limit_fen, token_version, token_version_changed_at
 FROM users
 WHERE id = ANY($1::uuid[])
 `
@@ -1657,6 +1731,9 @@ func (q *Queries) ListUsersByIDs(ctx context.Context, ids []uuid.UUID) ([]User,
 			&i.PaymentTermDays,
 			&i.PaymentTermCustomLabel,
 			&i.AvatarUrl,
+			&i.CreditLimitFen,
+			&i.TokenVersion,
+			&i.TokenVersionChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1688,7 +1765,7 @@ SET user_type = 'staff',
     disabled_reason = NULL,
     updated_at = now()
 WHERE id = $1 AND user_type = 'customer'
-RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url
+RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url, credit_limit_fen, token_version, token_version_changed_at
 `
 
 func (q *Queries) PromoteCustomerToStaff(ctx context.Context, id uuid.UUID) (User, error) {
@@ -1710,6 +1787,9 @@ func (q *Queries) PromoteCustomerToStaff(ctx context.Context, id uuid.UUID) (Use
 		&i.PaymentTermDays,
 		&i.PaymentTermCustomLabel,
 		&i.AvatarUrl,
+		&i.CreditLimitFen,
+		&i.TokenVersion,
+		&i.TokenVersionChangedAt,
 	)
 	return i, err
 }
@@ -1735,7 +1815,7 @@ UPDATE users
 SET owner_sales_user_id = $2,
     updated_at = now()
 WHERE id = $1 AND user_type = 'customer'
-RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url
+RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url, credit_limit_fen, token_version, token_version_changed_at
 `
 
 type TransferCustomerOwnershipParams struct {
@@ -1762,6 +1842,9 @@ func (q *Queries) TransferCustomerOwnership(ctx context.Context, arg TransferCus
 		&i.PaymentTermDays,
 		&i.PaymentTermCustomLabel,
 		&i.AvatarUrl,
+		&i.CreditLimitFen,
+		&i.TokenVersion,
+		&i.TokenVersionChangedAt,
 	)
 	return i, err
 }
@@ -1772,7 +1855,7 @@ SET owner_sales_user_id = $1::uuid,
     updated_at = now()
 WHERE user_type = 'customer'
   AND id = ANY($2::uuid[])
-RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url
+RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url, credit_limit_fen, token_version, token_version_changed_at
 `
 
 type TransferCustomersOwnershipParams struct {
@@ -1805,6 +1888,9 @@ func (q *Queries) TransferCustomersOwnership(ctx context.Context, arg TransferCu
 			&i.PaymentTermDays,
 			&i.PaymentTermCustomLabel,
 			&i.AvatarUrl,
+			&i.CreditLimitFen,
+			&i.TokenVersion,
+			&i.TokenVersionChangedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1822,9 +1908,10 @@ SET payment_term_type = $2,
     payment_term_days = $3,
     payment_term_custom_label = $4,
     payment_term_remark = $5,
+    credit_limit_fen = $6,
     updated_at = now()
 WHERE id = $1 AND user_type = 'customer'
-RETURNING id, payment_term_type, payment_term_days, payment_term_custom_label, payment_term_remark, updated_at
+RETURNING id, payment_term_type, payment_term_days, payment_term_custom_label, payment_term_remark, credit_limit_fen, updated_at
 `
 
 type UpdateCustomerFinanceProfileParams struct {
@@ -1833,6 +1920,7 @@ type UpdateCustomerFinanceProfileParams struct {
 	PaymentTermDays        *int32    `db:"payment_term_days" json:"payment_term_days"`
 	PaymentTermCustomLabel *string   `db:"payment_term_custom_label" json:"payment_term_custom_label"`
 	PaymentTermRemark      *string   `db:"payment_term_remark" json:"payment_term_remark"`
+	CreditLimitFen         *int64    `db:"credit_limit_fen" json:"credit_limit_fen"`
 }
 
 type UpdateCustomerFinanceProfileRow struct {
@@ -1841,6 +1929,7 @@ type UpdateCustomerFinanceProfileRow struct {
 	PaymentTermDays        *int32             `db:"payment_term_days" json:"payment_term_days"`
 	PaymentTermCustomLabel *string            `db:"payment_term_custom_label" json:"payment_term_custom_label"`
 	PaymentTermRemark      *string            `db:"payment_term_remark" json:"payment_term_remark"`
+	CreditLimitFen         *int64             `db:"credit_limit_fen" json:"credit_limit_fen"`
 	UpdatedAt              pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
 }
 
@@ -1851,6 +1940,7 @@ func (q *Queries) UpdateCustomerFinanceProfile(ctx context.Context, arg UpdateCu
 		arg.PaymentTermDays,
 		arg.PaymentTermCustomLabel,
 		arg.PaymentTermRemark,
+		arg.CreditLimitFen,
 	)
 	var i UpdateCustomerFinanceProfileRow
 	err := row.Scan(
@@ -1859,6 +1949,7 @@ func (q *Queries) UpdateCustomerFinanceProfile(ctx context.Context, arg UpdateCu
 		&i.PaymentTermDays,
 		&i.PaymentTermCustomLabel,
 		&i.PaymentTermRemark,
+		&i.CreditLimitFen,
 		&i.UpdatedAt,
 	)
 	return i, err
@@ -1957,7 +2048,7 @@ UPDATE users
 SET display_name = COALESCE($2, display_name),
     updated_at = now()
 WHERE id = $1
-RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url
+RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url, credit_limit_fen, token_version, token_version_changed_at
 `
 
 type UpdateUserProfileParams struct {
@@ -1984,6 +2075,9 @@ func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfilePa
 		&i.PaymentTermDays,
 		&i.PaymentTermCustomLabel,
 		&i.AvatarUrl,
+		&i.CreditLimitFen,
+		&i.TokenVersion,
+		&i.TokenVersionChangedAt,
 	)
 	return i, err
 }
@@ -1995,7 +2089,7 @@ SET status = $2,
     disabled_reason = $4,
     updated_at = now()
 WHERE id = $1
-RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url
+RETURNING id, display_name, user_type, owner_sales_user_id, created_at, updated_at, status, disabled_at, disabled_reason, phone, payment_term_remark, payment_term_type, payment_term_days, payment_term_custom_label, avatar_url, credit_limit_fen, token_version, token_version_changed_at
 `
 
 type UpdateUserStatusParams struct {
@@ -2029,6 +2123,9 @@ func (q *Queries) UpdateUserStatus(ctx context.Context, arg UpdateUserStatusPara
 		&i.PaymentTermDays,
 		&i.PaymentTermCustomLabel,
 		&i.AvatarUrl,
+		&i.CreditLimitFen,
+		&i.TokenVersion,
+		&i.TokenVersionChangedAt,
 	)
 	return i, err
 }
@@ -112,6 +112,9 @@ type User struct {
 	PaymentTermDays        *int32             `db:"payment_term_days" json:"payment_term_days"`
 	PaymentTermCustomLabel *string            `db:"payment_term_custom_label" json:"payment_term_custom_label"`
 	AvatarUrl              *string            `db:"avatar_url" json:"avatar_url"`
+	CreditLimitFen         *int64             `db:"credit_limit_fen" json:"credit_limit_fen"`
+	TokenVersion           int32              `db:"token_version" json:"token_version"`
+	TokenVersionChangedAt  pgtype.Timestamptz `db:"token_version_changed_at" json:"token_version_changed_at"`
 }
 
 type UserIdentity struct {
@@ -137,3 +140,20 @@ type UserRole struct {
 	Role      string             `db:"role" json:"role"`
 	CreatedAt pgtype.Timestamptz `db:"created_at" json:"created_at"`
 }
+
+type UserSession struct {
+	ID               uuid.UUID          `db:"id" json:"id"`
+	UserID           uuid.UUID          `db:"user_id" json:"user_id"`
+	Role             string             `db:"role" json:"role"`
+	RefreshTokenHash string             `db:"refresh_token_hash" json:"refresh_token_hash"`
+	Platform         *string            `db:"platform" json:"platform"`
+	DeviceID         *string            `db:"device_id" json:"device_id"`
+	DeviceName       *string            `db:"device_name" json:"device_name"`
+	UserAgent        *string            `db:"user_agent" json:"user_agent"`
+	IpAddress        *string            `db:"ip_address" json:"ip_address"`
+	CreatedAt        pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	LastUsedAt       pgtype.Timestamptz `db:"last_used_at" json:"last_used_at"`
+	ExpiresAt        pgtype.Timestamptz `db:"expires_at" json:"expires_at"`
+	RevokedAt        pgtype.Timestamptz `db:"revoked_at" json:"revoked_at"`
+	RevokedReason    *string            `db:"revoked_reason" json:"revoked_reason"`
+}
@@ -90,12 +90,14 @@ type customerFinanceProfileResponse struct {
 	CustomerID        openapi_types.UUID `json:"customerId"`
 	PaymentTerm       *paymentTermConfig `json:"paymentTerm"`
 	PaymentTermRemark *string            `json:"paymentTermRemark"`
+	CreditLimitFen    *int64             `json:"creditLimitFen"`
 	UpdatedAt         string             `json:"updatedAt"`
 }
 
 type customerFinanceProfilePatch struct {
 	PaymentTerm       json.RawMessage `json:"paymentTerm,omitempty"`
 	PaymentTermRemark string          `json:"paymentTermRemark"`
+	CreditLimitFen    json.RawMessage `json:"creditLimitFen,omitempty"`
 }
 
 type salesUserSummary struct {
@@ -499,6 +501,11 @@ func (h *Handler) PatchAdminUsersUserId(c *gin.Context, userId openapi_types.UUI
 			}); err != nil {
 				return err
 			}
+			if status == "disabled" {
+				if err := revokeAllSessionsTx(c.Request.Context(), q, user.ID, sessionRevokedDisabled); err != nil {
+					return err
+				}
+			}
 		}
 		return nil
 	})
@@ -1314,6 +1321,7 @@ func (h *Handler) GetAdminCustomersCustomerIdFinanceProfile(c *gin.Context) {
 		CustomerID:        openapi_types.UUID(profile.ID),
 		PaymentTerm:       buildPaymentTermConfig(profile.PaymentTermType, profile.PaymentTermDays, profile.PaymentTermCustomLabel),
 		PaymentTermRemark: profile.PaymentTermRemark,
+		CreditLimitFen:    profile.CreditLimitFen,
 		UpdatedAt:         profile.UpdatedAt.Time.Format(time.RFC3339),
 	})
 }
@@ -1361,6 +1369,21 @@ func (h *Handler) PatchAdminCustomersCustomerIdFinanceProfile(c *gin.Context) {
 	nextPaymentTermType := currentProfile.PaymentTermType
 	nextPaymentTermDays := currentProfile.PaymentTermDays
 	nextCustomTermLabel := currentProfile.PaymentTermCustomLabel
+	nextCreditLimitFen := currentProfile.CreditLimitFen
+
+	if request.CreditLimitFen != nil {
+		trimmedCreditLimitRaw := bytes.TrimSpace(request.CreditLimitFen)
+		if bytes.Equal(trimmedCreditLimitRaw, []byte("null")) {
+			nextCreditLimitFen = nil
+		} else {
+			var creditLimitFen int64
+			if err := json.Unmarshal(trimmedCreditLimitRaw, &creditLimitFen); err != nil || creditLimitFen < 0 {
+				h.writeError(c, http.StatusBadRequest, "invalid_request", "creditLimitFen must be null or a non-negative integer")
+				return
+			}
+			nextCreditLimitFen = &creditLimitFen
+		}
+	}
 
 	if request.PaymentTerm != nil {
 		trimmedPaymentTermRaw := bytes.TrimSpace(request.PaymentTerm)
@@ -1441,6 +1464,7 @@ func (h *Handler) PatchAdminCustomersCustomerIdFinanceProfile(c *gin.Context) {
 		PaymentTermDays:        nextPaymentTermDays,
 		PaymentTermCustomLabel: nextCustomTermLabel,
 		PaymentTermRemark:      remark,
+		CreditLimitFen:         nextCreditLimitFen,
 	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -1458,12 +1482,14 @@ func (h *Handler) PatchAdminCustomersCustomerIdFinanceProfile(c *gin.Context) {
 		"paymentTermDays":   profile.PaymentTermDays,
 		"customTermLabel":   truncateRemarkForAudit(pointerStringValue(profile.PaymentTermCustomLabel)),
 		"paymentTermRemark": truncateRemarkForAudit(trimmedRemark),
+		"creditLimitFen":    profile.CreditLimitFen,
 	})
 
 	c.JSON(http.StatusOK, customerFinanceProfileResponse{
 		CustomerID:        openapi_types.UUID(profile.ID),
 		PaymentTerm:       buildPaymentTermConfig(profile.PaymentTermType, profile.PaymentTermDays, profile.PaymentTermCustomLabel),
 		PaymentTermRemark: profile.PaymentTermRemark,
+		CreditLimitFen:    profile.CreditLimitFen,
 		UpdatedAt:         profile.UpdatedAt.Time.Format(time.RFC3339),
 	})
 }
@@ -16,6 +16,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	"github.com/teamdsb/tmo/services/identity/internal/auth"
 	"github.com/teamdsb/tmo/services/identity/internal/db"
 	"github.com/teamdsb/tmo/services/identity/internal/http/oapi"
 	"github.com/teamdsb/tmo/services/identity/internal/platform"
@@ -160,18 +161,12 @@ func (h *Handler) PostAuthMiniLogin(c *gin.Context) {
 		ownerSalesUserID = &owner
 	}
 
-	token, expiresAt, err := h.Auth.Issue(user.ID, selectedRole, roles, string(userType), ownerSalesUserID, user.DisplayName, user.Phone)
+	response, err := h.startSession(c, user, selectedRole, roles, userType, ownerSalesUserID, platformName)
 	if err != nil {
-		h.logError("issue token failed", err)
+		h.logError("start session failed", err)
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "login failed")
 		return
 	}
-
-	response := oapi.AuthResponse{
-		AccessToken: token,
-		ExpiresIn:   expiresInSeconds(expiresAt),
-		User:        userFromModel(user, roles, selectedRole, userType),
-	}
 	c.JSON(http.StatusOK, response)
 }
 
@@ -596,18 +591,12 @@ func (h *Handler) PostAuthPasswordLogin(c *gin.Context) {
 		h.writeError(c, http.StatusUnauthorized, "unauthorized", "invalid credentials")
 		return
 	}
-	token, expiresAt, err := h.Auth.Issue(user.ID, selectedRole, roles, string(userType), nil, user.DisplayName, user.Phone)
+	response, err := h.startSession(c, user, selectedRole, roles, userType, nil, "web")
 	if err != nil {
-		h.logError("issue token failed", err)
+		h.logError("start session failed", err)
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "login failed")
 		return
 	}
-
-	response := oapi.AuthResponse{
-		AccessToken: token,
-		ExpiresIn:   expiresInSeconds(expiresAt),
-		User:        userFromModel(user, roles, selectedRole, userType),
-	}
 	c.JSON(http.StatusOK, response)
 }
 
@@ -680,7 +669,23 @@ func (h *Handler) switchRole(c *gin.Context) {
 		ownerSalesUserID = &owner
 	}
 
-	token, expiresAt, err := h.Auth.Issue(user.ID, targetRole, roles, string(userType), ownerSalesUserID, user.DisplayName, user.Phone)
+	// The session keeps its refresh token; later refreshes mint tokens for
+	// the new role.
+	session := auth.Session{TokenVersion: int(user.TokenVersion)}
+	if claims.SessionID != nil {
+		session.ID = *claims.SessionID
+		if err := h.Store.UpdateUserSessionRole(c.Request.Context(), db.UpdateUserSessionRoleParams{
+			ID:     session.ID,
+			UserID: user.ID,
+			Role:   targetRole,
+		}); err != nil {
+			h.logError("update session role failed", err)
+			h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to switch role")
+			return
+		}
+	}
+
+	token, expiresAt, err := h.Auth.Issue(user.ID, targetRole, roles, string(userType), ownerSalesUserID, user.DisplayName, user.Phone, session)
 	if err != nil {
 		h.logError("issue token failed", err)
 		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to switch role")
@@ -15,11 +15,13 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/teamdsb/tmo/packages/go-shared/jwks"
 	"github.com/teamdsb/tmo/services/identity/internal/auth"
 	"github.com/teamdsb/tmo/services/identity/internal/config"
 	"github.com/teamdsb/tmo/services/identity/internal/db"
@@ -36,8 +38,24 @@ const (
 	csPassword    = "cs123"
 	salesUsername = "sales"
 	salesPassword = "sales123"
+
+	testInternalToken = "test-internal-token"
 )
 
+var testTokenManager = newTestTokenManager()
+
+func newTestTokenManager() *auth.TokenManager {
+	signer, err := auth.GenerateSigningKey()
+	if err != nil {
+		panic(err)
+	}
+	manager, err := auth.NewTokenManager(signer, nil, "test-issuer", 2*time.Hour)
+	if err != nil {
+		panic(err)
+	}
+	return manager
+}
+
 var (
 	adminID = uuid.MustParse("aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa")
 	salesID = uuid.MustParse("bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb")
@@ -103,8 +121,7 @@ func TestMiniLoginTokenIncludesCustomerProfileClaims(t *testing.T) {
 		t.Fatalf("decode auth response: %v", err)
 	}
 
-	manager := auth.NewTokenManager("test-secret", "test-issuer", 24*time.Hour)
-	claims, err := manager.Parse(authResponse.AccessToken)
+	claims, err := testTokenManager.Parse(authResponse.AccessToken)
 	if err != nil {
 		t.Fatalf("parse token: %v", err)
 	}
@@ -923,6 +940,7 @@ func TestAdminCustomerFinanceProfile(t *testing.T) {
 	type financeProfileResponse struct {
 		CustomerID        uuid.UUID `json:"customerId"`
 		PaymentTermRemark *string   `json:"paymentTermRemark"`
+		CreditLimitFen    *int64    `json:"creditLimitFen"`
 		UpdatedAt         string    `json:"updatedAt"`
 	}
 
@@ -1045,6 +1063,308 @@ func TestAdminCustomerFinanceProfile(t *testing.T) {
 	if tooLongResp.Code != http.StatusBadRequest {
 		t.Fatalf("expected too long remark 400, got %d: %s", tooLongResp.Code, tooLongResp.Body.String())
 	}
+
+	limitResp := doJSON(t, router, http.MethodPatch, path, map[string]interface{}{
+		"paymentTerm":    map[string]interface{}{"type": "MONTHLY", "monthlySettlementDays": 30},
+		"creditLimitFen": 500000,
+	}, adminAuth.AccessToken)
+	if limitResp.Code != http.StatusOK {
+		t.Fatalf("expected credit limit patch 200, got %d: %s", limitResp.Code, limitResp.Body.String())
+	}
+	if err := json.NewDecoder(limitResp.Body).Decode(&profile); err != nil {
+		t.Fatalf("decode credit limit profile: %v", err)
+	}
+	if profile.CreditLimitFen == nil || *profile.CreditLimitFen != 500000 {
+		t.Fatalf("expected credit limit 500000, got %#v", profile.CreditLimitFen)
+	}
+
+	keepResp := doJSON(t, router, http.MethodPatch, path, map[string]interface{}{
+		"paymentTermRemark": "月结",
+	}, adminAuth.AccessToken)
+	if keepResp.Code != http.StatusOK {
+		t.Fatalf("expected remark patch 200, got %d: %s", keepResp.Code, keepResp.Body.String())
+	}
+	profile.CreditLimitFen = nil
+	if err := json.NewDecoder(keepResp.Body).Decode(&profile); err != nil {
+		t.Fatalf("decode kept profile: %v", err)
+	}
+	if profile.CreditLimitFen == nil || *profile.CreditLimitFen != 500000 {
+		t.Fatalf("expected omitted creditLimitFen to keep 500000, got %#v", profile.CreditLimitFen)
+	}
+
+	negativeResp := doJSON(t, router, http.MethodPatch, path, map[string]interface{}{
+		"creditLimitFen": -1,
+	}, adminAuth.AccessToken)
+	if negativeResp.Code != http.StatusBadRequest {
+		t.Fatalf("expected negative credit limit 400, got %d: %s", negativeResp.Code, negativeResp.Body.String())
+	}
+
+	internalPath := "/internal/customers/" + customerID.String() + "/finance-profile"
+	unauthorizedInternal := doJSON(t, router, http.MethodGet, internalPath, nil, "")
+	if unauthorizedInternal.Code != http.StatusUnauthorized {
+		t.Fatalf("expected internal profile without token 401, got %d: %s", unauthorizedInternal.Code, unauthorizedInternal.Body.String())
+	}
+	internalReq := httptest.NewRequest(http.MethodGet, internalPath, nil)
+	internalReq.Header.Set("X-Internal-Token", testInternalToken)
+	internalResp := httptest.NewRecorder()
+	router.ServeHTTP(internalResp, internalReq)
+	if internalResp.Code != http.StatusOK {
+		t.Fatalf("expected internal profile 200, got %d: %s", internalResp.Code, internalResp.Body.String())
+	}
+	profile.CreditLimitFen = nil
+	if err := json.NewDecoder(internalResp.Body).Decode(&profile); err != nil {
+		t.Fatalf("decode internal profile: %v", err)
+	}
+	if profile.CreditLimitFen == nil || *profile.CreditLimitFen != 500000 {
+		t.Fatalf("expected internal credit limit 500000, got %#v", profile.CreditLimitFen)
+	}
+
+	unlimitedResp := doJSON(t, router, http.MethodPatch, path, map[string]interface{}{
+		"creditLimitFen": nil,
+	}, adminAuth.AccessToken)
+	if unlimitedResp.Code != http.StatusOK {
+		t.Fatalf("expected clear credit limit 200, got %d: %s", unlimitedResp.Code, unlimitedResp.Body.String())
+	}
+	if err := json.NewDecoder(unlimitedResp.Body).Decode(&profile); err != nil {
+		t.Fatalf("decode unlimited profile: %v", err)
+	}
+	if profile.CreditLimitFen != nil {
+		t.Fatalf("expected cleared credit limit, got %d", *profile.CreditLimitFen)
+	}
+}
+
+func TestInternalRbacGrants(t *testing.T) {
+	router, _ := setupTestRouter(t)
+
+	unauthorized := doJSON(t, router, http.MethodGet, "/internal/rbac/grants", nil, "")
+	if unauthorized.Code != http.StatusUnauthorized {
+		t.Fatalf("expected grants without token 401, got %d: %s", unauthorized.Code, unauthorized.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/internal/rbac/grants", nil)
+	req.Header.Set("X-Internal-Token", testInternalToken)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected grants 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var payload struct {
+		Items []struct {
+			Role       string `json:"role"`
+			Permission string `json:"permission"`
+			Scope      string `json:"scope"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode grants: %v", err)
+	}
+	found := false
+	for _, item := range payload.Items {
+		if item.Role == "SALES" && item.Permission == "order:read" {
+			found = item.Scope == "OWNED"
+		}
+	}
+	if !found {
+		t.Fatalf("expected SALES order:read OWNED in %+v", payload.Items)
+	}
+}
+
+func TestJWKSVerifiesIssuedTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	router := httpserver.NewRouter(&handler.Handler{Auth: testTokenManager, Logger: logger}, logger, func(context.Context) error { return nil })
+
+	resp := doJSON(t, router, http.MethodGet, "/.well-known/jwks.json", nil, "")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var set jwks.Set
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		t.Fatalf("decode jwks: %v", err)
+	}
+	keys := jwks.NewStatic(set.Decode()...)
+
+	userID := uuid.New()
+	signed, _, err := testTokenManager.Issue(userID, "ADMIN", nil, "", nil, nil, nil, auth.Session{})
+	if err != nil {
+		t.Fatalf("issue: %v", err)
+	}
+	token, err := jwt.Parse(signed, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := keys.Key(context.Background(), kid)
+		if err != nil {
+			return nil, err
+		}
+		return key.Public, nil
+	}, jwt.WithValidMethods(jwks.Algorithms))
+	if err != nil || !token.Valid {
+		t.Fatalf("expected published key to verify the token: %v", err)
+	}
+	subject, _ := token.Claims.GetSubject()
+	if subject != userID.String() {
+		t.Fatalf("unexpected subject %q", subject)
+	}
+}
+
+func TestRefreshTokenRotatesAndDetectsReuse(t *testing.T) {
+	router, pool := setupTestRouter(t)
+	ctx := context.Background()
+
+	if err := resetIdentityTables(ctx, pool); err != nil {
+		t.Fatalf("reset tables: %v", err)
+	}
+	if err := seedAdmin(ctx, pool); err != nil {
+		t.Fatalf("seed admin: %v", err)
+	}
+
+	login := doJSON(t, router, http.MethodPost, "/auth/password/login", map[string]interface{}{
+		"username": adminUsername,
+		"password": adminPassword,
+	}, "")
+	if login.Code != http.StatusOK {
+		t.Fatalf("expected login 200, got %d: %s", login.Code, login.Body.String())
+	}
+	var first oapi.AuthResponse
+	if err := json.NewDecoder(login.Body).Decode(&first); err != nil {
+		t.Fatalf("decode login: %v", err)
+	}
+	if first.RefreshToken == nil || *first.RefreshToken == "" {
+		t.Fatal("expected login to return a refresh token")
+	}
+
+	refreshed := doJSON(t, router, http.MethodPost, "/auth/refresh", map[string]interface{}{
+		"refreshToken": *first.RefreshToken,
+	}, "")
+	if refreshed.Code != http.StatusOK {
+		t.Fatalf("expected refresh 200, got %d: %s", refreshed.Code, refreshed.Body.String())
+	}
+	var second oapi.AuthResponse
+	if err := json.NewDecoder(refreshed.Body).Decode(&second); err != nil {
+		t.Fatalf("decode refresh: %v", err)
+	}
+	if second.RefreshToken == nil || *second.RefreshToken == *first.RefreshToken {
+		t.Fatal("expected refresh to rotate the refresh token")
+	}
+	if second.User.CurrentRole != "ADMIN" {
+		t.Fatalf("expected refreshed role ADMIN, got %q", second.User.CurrentRole)
+	}
+
+	reused := doJSON(t, router, http.MethodPost, "/auth/refresh", map[string]interface{}{
+		"refreshToken": *first.RefreshToken,
+	}, "")
+	if reused.Code != http.StatusUnauthorized {
+		t.Fatalf("expected reused refresh 401, got %d: %s", reused.Code, reused.Body.String())
+	}
+	afterReuse := doJSON(t, router, http.MethodPost, "/auth/refresh", map[string]interface{}{
+		"refreshToken": *second.RefreshToken,
+	}, "")
+	if afterReuse.Code != http.StatusUnauthorized {
+		t.Fatalf("expected session revoked after reuse, got %d: %s", afterReuse.Code, afterReuse.Body.String())
+	}
+}
+
+func TestLogoutAllAndAdminSessionRevocation(t *testing.T) {
+	router, pool := setupTestRouter(t)
+	ctx := context.Background()
+
+	if err := resetIdentityTables(ctx, pool); err != nil {
+		t.Fatalf("reset tables: %v", err)
+	}
+	if err := seedAdmin(ctx, pool); err != nil {
+		t.Fatalf("seed admin: %v", err)
+	}
+	adminLogin := doJSON(t, router, http.MethodPost, "/auth/password/login", map[string]interface{}{
+		"username": adminUsername,
+		"password": adminPassword,
+	}, "")
+	if adminLogin.Code != http.StatusOK {
+		t.Fatalf("expected admin login 200, got %d: %s", adminLogin.Code, adminLogin.Body.String())
+	}
+	var adminAuth oapi.AuthResponse
+	if err := json.NewDecoder(adminLogin.Body).Decode(&adminAuth); err != nil {
+		t.Fatalf("decode admin auth: %v", err)
+	}
+
+	customerLogin := doJSON(t, router, http.MethodPost, "/auth/mini/login", map[string]interface{}{
+		"platform": "weapp",
+		"code":     "mock_session_customer",
+	}, "")
+	if customerLogin.Code != http.StatusOK {
+		t.Fatalf("expected customer login 200, got %d: %s", customerLogin.Code, customerLogin.Body.String())
+	}
+	var customerAuth oapi.AuthResponse
+	if err := json.NewDecoder(customerLogin.Body).Decode(&customerAuth); err != nil {
+		t.Fatalf("decode customer auth: %v", err)
+	}
+	customerID := uuid.UUID(customerAuth.User.Id)
+
+	list := doJSON(t, router, http.MethodGet, "/admin/users/"+customerID.String()+"/sessions", nil, adminAuth.AccessToken)
+	if list.Code != http.StatusOK {
+		t.Fatalf("expected sessions 200, got %d: %s", list.Code, list.Body.String())
+	}
+	var sessions struct {
+		Items []struct {
+			ID       string  `json:"id"`
+			Platform *string `json:"platform"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(list.Body).Decode(&sessions); err != nil {
+		t.Fatalf("decode sessions: %v", err)
+	}
+	if len(sessions.Items) != 1 || sessions.Items[0].Platform == nil || *sessions.Items[0].Platform != "weapp" {
+		t.Fatalf("unexpected sessions %+v", sessions.Items)
+	}
+
+	forbidden := doJSON(t, router, http.MethodGet, "/admin/users/"+customerID.String()+"/sessions", nil, customerAuth.AccessToken)
+	if forbidden.Code != http.StatusForbidden {
+		t.Fatalf("expected customer sessions list 403, got %d: %s", forbidden.Code, forbidden.Body.String())
+	}
+
+	killAll := doJSON(t, router, http.MethodDelete, "/admin/users/"+customerID.String()+"/sessions", nil, adminAuth.AccessToken)
+	if killAll.Code != http.StatusNoContent {
+		t.Fatalf("expected revoke all 204, got %d: %s", killAll.Code, killAll.Body.String())
+	}
+	me := doJSON(t, router, http.MethodGet, "/me", nil, customerAuth.AccessToken)
+	if me.Code != http.StatusUnauthorized {
+		t.Fatalf("expected revoked access token 401, got %d: %s", me.Code, me.Body.String())
+	}
+	refresh := doJSON(t, router, http.MethodPost, "/auth/refresh", map[string]interface{}{
+		"refreshToken": *customerAuth.RefreshToken,
+	}, "")
+	if refresh.Code != http.StatusUnauthorized {
+		t.Fatalf("expected revoked refresh 401, got %d: %s", refresh.Code, refresh.Body.String())
+	}
+
+	versions := httptest.NewRequest(http.MethodGet, "/internal/token-versions", nil)
+	versions.Header.Set("X-Internal-Token", testInternalToken)
+	versionsResp := httptest.NewRecorder()
+	router.ServeHTTP(versionsResp, versions)
+	if versionsResp.Code != http.StatusOK {
+		t.Fatalf("expected token versions 200, got %d: %s", versionsResp.Code, versionsResp.Body.String())
+	}
+	var versionPayload struct {
+		Items []struct {
+			UserID       uuid.UUID `json:"userId"`
+			TokenVersion int       `json:"tokenVersion"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(versionsResp.Body).Decode(&versionPayload); err != nil {
+		t.Fatalf("decode token versions: %v", err)
+	}
+	if len(versionPayload.Items) != 1 || versionPayload.Items[0].UserID != customerID || versionPayload.Items[0].TokenVersion != 1 {
+		t.Fatalf("unexpected token versions %+v", versionPayload.Items)
+	}
+
+	logout := doJSON(t, router, http.MethodPost, "/auth/logout", map[string]interface{}{
+		"allSessions": true,
+	}, adminAuth.AccessToken)
+	if logout.Code != http.StatusNoContent {
+		t.Fatalf("expected logout 204, got %d: %s", logout.Code, logout.Body.String())
+	}
+	adminMe := doJSON(t, router, http.MethodGet, "/me", nil, adminAuth.AccessToken)
+	if adminMe.Code != http.StatusUnauthorized {
+		t.Fatalf("expected logged out access token 401, got %d: %s", adminMe.Code, adminMe.Body.String())
+	}
 }
 
 func TestAdminCustomerTransferAndBatch(t *testing.T) {
@@ -1618,6 +1938,10 @@ func TestAdminCustomerTagsAndBatchUpdate(t *testing.T) {
 		t.Fatalf("expected 2 tagged customers, got total=%d items=%d", customerList.Total, len(customerList.Items))
 	}
 
+	if tagIDs := internalCustomerTagIDs(t, router, customerA); len(tagIDs) != 1 || tagIDs[0] != createdTag.ID {
+		t.Fatalf("expected internal tags [%s], got %v", createdTag.ID, tagIDs)
+	}
+
 	removeTag := doJSON(t, router, http.MethodPost, "/admin/customers/tags:batch-update", map[string]interface{}{
 		"customerIds":  []string{customerB.String()},
 		"removeTagIds": []string{createdTag.ID},
@@ -1633,6 +1957,10 @@ func TestAdminCustomerTagsAndBatchUpdate(t *testing.T) {
 		t.Fatalf("expected disable tag 200, got %d: %s", disableTag.Code, disableTag.Body.String())
 	}
 
+	if tagIDs := internalCustomerTagIDs(t, router, customerA); len(tagIDs) != 0 {
+		t.Fatalf("expected inactive tag hidden from internal tags, got %v", tagIDs)
+	}
+
 	addInactive := doJSON(t, router, http.MethodPost, "/admin/customers/tags:batch-update", map[string]interface{}{
 		"customerIds": []string{customerB.String()},
 		"addTagIds":   []string{createdTag.ID},
@@ -1642,6 +1970,24 @@ func TestAdminCustomerTagsAndBatchUpdate(t *testing.T) {
 	}
 }
 
+func internalCustomerTagIDs(t *testing.T, router *gin.Engine, customerID uuid.UUID) []string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/internal/customers/"+customerID.String()+"/tags", nil)
+	req.Header.Set("X-Internal-Token", testInternalToken)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected internal customer tags 200, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var body struct {
+		TagIDs []string `json:"tagIds"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode internal customer tags: %v", err)
+	}
+	return body.TagIDs
+}
+
 func containsRole(roles []string, role string) bool {
 	for _, candidate := range roles {
 		if strings.EqualFold(candidate, role) {
@@ -1704,10 +2050,12 @@ func setupTestRouterWithPlatformConfig(t *testing.T, resolverConfig platform.Con
 			WeappAppSecret:             resolverConfig.WeappAppSecret,
 			EnablePhoneProofSimulation: resolverConfig.EnablePhoneProofSimulation,
 			EnableDebugRoleSwitch:      true,
+			InternalToken:              testInternalToken,
+			RefreshTokenTTL:            24 * time.Hour,
 		},
 		DB:       pool,
 		Logger:   logger,
-		Auth:     auth.NewTokenManager("test-secret", "test-issuer", 2*time.Hour),
+		Auth:     testTokenManager,
 		Store:    store,
 		Platform: platform.NewMiniLoginResolver(resolverConfig),
 	}
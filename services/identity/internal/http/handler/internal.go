@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+)
+
+// GetInternalCustomersCustomerIdFinanceProfile exposes a customer's payment
+// term and credit limit to other services (commerce checks them when placing
+// orders). It is guarded by the shared internal token instead of a user JWT.
+func (h *Handler) GetInternalCustomersCustomerIdFinanceProfile(c *gin.Context) {
+	if !h.authorizeInternal(c) {
+		h.writeError(c, http.StatusUnauthorized, "unauthorized", "invalid internal token")
+		return
+	}
+
+	customerID, err := uuid.Parse(strings.TrimSpace(c.Param("customerId")))
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid customer id")
+		return
+	}
+
+	profile, err := h.Store.GetCustomerFinanceProfile(c.Request.Context(), customerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusNotFound, "not_found", "customer not found")
+			return
+		}
+		h.logError("get customer finance profile failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to fetch customer finance profile")
+		return
+	}
+
+	c.JSON(http.StatusOK, customerFinanceProfileResponse{
+		CustomerID:        openapi_types.UUID(profile.ID),
+		PaymentTerm:       buildPaymentTermConfig(profile.PaymentTermType, profile.PaymentTermDays, profile.PaymentTermCustomLabel),
+		PaymentTermRemark: profile.PaymentTermRemark,
+		CreditLimitFen:    profile.CreditLimitFen,
+		UpdatedAt:         profile.UpdatedAt.Time.Format(time.RFC3339),
+	})
+}
+
+// GetInternalCustomersCustomerIdTags lists the active tags of a customer so
+// commerce can apply the price lists assigned to them.
+func (h *Handler) GetInternalCustomersCustomerIdTags(c *gin.Context) {
+	if !h.authorizeInternal(c) {
+		h.writeError(c, http.StatusUnauthorized, "unauthorized", "invalid internal token")
+		return
+	}
+
+	customerID, err := uuid.Parse(strings.TrimSpace(c.Param("customerId")))
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid customer id")
+		return
+	}
+
+	rows, err := h.Store.ListCustomerTagsByCustomerIDs(c.Request.Context(), []uuid.UUID{customerID})
+	if err != nil {
+		h.logError("list customer tags failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list customer tags")
+		return
+	}
+	tagIDs := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		if row.TagActive {
+			tagIDs = append(tagIDs, row.TagID)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"customerId": customerID, "tagIds": tagIDs})
+}
+
+type internalRoleGrant struct {
+	Role       string `json:"role"`
+	Permission string `json:"permission"`
+	Scope      string `json:"scope"`
+}
+
+// GetInternalRbacGrants returns the whole role-permission matrix. Commerce and
+// payment cache it to enforce the permissions configured in the admin UI.
+func (h *Handler) GetInternalRbacGrants(c *gin.Context) {
+	if !h.authorizeInternal(c) {
+		h.writeError(c, http.StatusUnauthorized, "unauthorized", "invalid internal token")
+		return
+	}
+
+	rows, err := h.Store.ListAllRolePermissions(c.Request.Context())
+	if err != nil {
+		h.logError("list role permissions failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list role permissions")
+		return
+	}
+	items := make([]internalRoleGrant, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, internalRoleGrant{Role: row.RoleCode, Permission: row.PermissionCode, Scope: row.Scope})
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+func (h *Handler) authorizeInternal(c *gin.Context) bool {
+	expected := strings.TrimSpace(h.Config.InternalToken)
+	if expected == "" {
+		return false
+	}
+	provided := strings.TrimSpace(c.GetHeader("X-Internal-Token"))
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(provided)) == 1
+}
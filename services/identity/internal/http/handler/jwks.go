@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetWellKnownJwksJson publishes the public keys access tokens are signed with.
+// Services verifying tokens cache the set and refetch it when they meet an
+// unknown kid, so a short max-age is enough.
+func (h *Handler) GetWellKnownJwksJson(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.Auth.KeySet())
+}
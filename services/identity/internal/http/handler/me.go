@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 	"time"
@@ -136,5 +137,21 @@ func (h *Handler) requireClaims(c *gin.Context) (auth.Claims, bool) {
 		h.writeError(c, http.StatusUnauthorized, "unauthorized", "invalid token")
 		return auth.Claims{}, false
 	}
+	if h.Store != nil {
+		state, err := h.Store.GetUserTokenState(c.Request.Context(), claims.UserID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				h.writeError(c, http.StatusUnauthorized, "unauthorized", "invalid token")
+				return auth.Claims{}, false
+			}
+			h.logError("load token state failed", err)
+			h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to authenticate")
+			return auth.Claims{}, false
+		}
+		if state.Status == "disabled" || claims.TokenVersion < int(state.TokenVersion) {
+			h.writeError(c, http.StatusUnauthorized, "unauthorized", "token revoked")
+			return auth.Claims{}, false
+		}
+	}
 	return claims, true
 }
@@ -0,0 +1,446 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	"github.com/teamdsb/tmo/services/identity/internal/auth"
+	"github.com/teamdsb/tmo/services/identity/internal/db"
+	"github.com/teamdsb/tmo/services/identity/internal/http/oapi"
+)
+
+const (
+	sessionRevokedLogout        = "logout"
+	sessionRevokedLogoutAll     = "logout_all"
+	sessionRevokedReused        = "refresh_token_reused"
+	sessionRevokedDisabled      = "account_disabled"
+	sessionRevokedRoleRemoved   = "role_removed"
+	sessionRevokedByAdmin       = "revoked_by_admin"
+	sessionRevokedAllByAdmin    = "all_revoked_by_admin"
+	sessionManagePermissionCode = "session:manage"
+)
+
+type refreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+type logoutRequest struct {
+	RefreshToken *string `json:"refreshToken"`
+	AllSessions  bool    `json:"allSessions"`
+}
+
+type userSessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	Role       string    `json:"role"`
+	Platform   *string   `json:"platform"`
+	DeviceID   *string   `json:"deviceId"`
+	DeviceName *string   `json:"deviceName"`
+	UserAgent  *string   `json:"userAgent"`
+	IPAddress  *string   `json:"ipAddress"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+type internalTokenVersion struct {
+	UserID       uuid.UUID `json:"userId"`
+	TokenVersion int32     `json:"tokenVersion"`
+}
+
+// startSession records a new login session for user and returns the access
+// and refresh tokens for it. Device details come from the X-Device-Id and
+// X-Device-Name headers.
+func (h *Handler) startSession(c *gin.Context, user db.User, role string, roles []string, userType oapi.UserUserType, ownerSalesUserID *uuid.UUID, platformName string) (oapi.AuthResponse, error) {
+	sessionID := uuid.New()
+	refreshToken, refreshHash, err := auth.NewRefreshToken(sessionID)
+	if err != nil {
+		return oapi.AuthResponse{}, err
+	}
+	refreshExpiresAt := time.Now().Add(h.Config.RefreshTokenTTL)
+	if _, err := h.Store.CreateUserSession(c.Request.Context(), db.CreateUserSessionParams{
+		ID:               sessionID,
+		UserID:           user.ID,
+		Role:             role,
+		RefreshTokenHash: refreshHash,
+		Platform:         optionalString(platformName),
+		DeviceID:         optionalString(c.GetHeader("X-Device-Id")),
+		DeviceName:       optionalString(c.GetHeader("X-Device-Name")),
+		UserAgent:        optionalString(c.Request.UserAgent()),
+		IpAddress:        optionalString(c.ClientIP()),
+		ExpiresAt:        pgtype.Timestamptz{Time: refreshExpiresAt, Valid: true},
+	}); err != nil {
+		return oapi.AuthResponse{}, err
+	}
+
+	token, expiresAt, err := h.Auth.Issue(user.ID, role, roles, string(userType), ownerSalesUserID, user.DisplayName, user.Phone, auth.Session{
+		ID:           sessionID,
+		TokenVersion: int(user.TokenVersion),
+	})
+	if err != nil {
+		return oapi.AuthResponse{}, err
+	}
+	refreshExpiresIn := expiresInSeconds(refreshExpiresAt)
+	return oapi.AuthResponse{
+		AccessToken:      token,
+		ExpiresIn:        expiresInSeconds(expiresAt),
+		RefreshToken:     &refreshToken,
+		RefreshExpiresIn: &refreshExpiresIn,
+		User:             userFromModel(user, roles, role, userType),
+	}, nil
+}
+
+// PostAuthRefresh rotates a refresh token. A token that no longer matches its
+// session has already been used, so the session is revoked: either the
+// client or someone who copied the token is replaying it.
+func (h *Handler) PostAuthRefresh(c *gin.Context) {
+	if h.Store == nil || h.Auth == nil {
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "service not ready")
+		return
+	}
+
+	var request refreshTokenRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+		return
+	}
+	sessionID, presentedHash, err := auth.ParseRefreshToken(request.RefreshToken)
+	if err != nil {
+		h.writeError(c, http.StatusUnauthorized, "unauthorized", "invalid refresh token")
+		return
+	}
+
+	ctx := c.Request.Context()
+	var (
+		session       db.UserSession
+		user          db.User
+		roles         []string
+		refreshToken  string
+		revokedReason string
+	)
+	err = shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		q := h.Store.WithTx(tx)
+		current, err := q.GetUserSessionForUpdate(ctx, sessionID)
+		if err != nil {
+			return err
+		}
+		if current.RevokedAt.Valid || !current.ExpiresAt.Time.After(time.Now()) {
+			return pgx.ErrNoRows
+		}
+		revoke := func(reason string) error {
+			revokedReason = reason
+			_, err := q.RevokeUserSession(ctx, db.RevokeUserSessionParams{
+				ID:            current.ID,
+				UserID:        current.UserID,
+				RevokedReason: &reason,
+			})
+			return err
+		}
+		if subtle.ConstantTimeCompare([]byte(current.RefreshTokenHash), []byte(presentedHash)) != 1 {
+			return revoke(sessionRevokedReused)
+		}
+
+		user, err = q.GetUserByID(ctx, current.UserID)
+		if err != nil {
+			return err
+		}
+		if user.Status == "disabled" {
+			return revoke(sessionRevokedDisabled)
+		}
+		roles, err = q.ListUserRoles(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		roles = normalizeRoles(roles)
+		if !containsRole(roles, current.Role) {
+			return revoke(sessionRevokedRoleRemoved)
+		}
+
+		var nextHash string
+		refreshToken, nextHash, err = auth.NewRefreshToken(current.ID)
+		if err != nil {
+			return err
+		}
+		session, err = q.RotateUserSession(ctx, db.RotateUserSessionParams{
+			ID:               current.ID,
+			RefreshTokenHash: nextHash,
+			ExpiresAt:        pgtype.Timestamptz{Time: time.Now().Add(h.Config.RefreshTokenTTL), Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusUnauthorized, "unauthorized", "invalid refresh token")
+			return
+		}
+		h.logError("refresh session failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to refresh session")
+		return
+	}
+	switch revokedReason {
+	case "":
+	case sessionRevokedReused:
+		h.recordAudit(c, nil, "session.refresh_reused", "session", &sessionID, nil)
+		h.writeError(c, http.StatusUnauthorized, "unauthorized", "refresh token reused")
+		return
+	case sessionRevokedDisabled:
+		h.writeError(c, http.StatusForbidden, "forbidden", "account disabled")
+		return
+	default:
+		h.writeError(c, http.StatusUnauthorized, "unauthorized", "invalid refresh token")
+		return
+	}
+
+	userType, ok := userTypeFromRole(session.Role)
+	if !ok {
+		h.writeError(c, http.StatusUnauthorized, "unauthorized", "invalid refresh token")
+		return
+	}
+	var ownerSalesUserID *uuid.UUID
+	if session.Role == "CUSTOMER" && user.OwnerSalesUserID.Valid {
+		owner := uuid.UUID(user.OwnerSalesUserID.Bytes)
+		ownerSalesUserID = &owner
+	}
+	token, expiresAt, err := h.Auth.Issue(user.ID, session.Role, roles, string(userType), ownerSalesUserID, user.DisplayName, user.Phone, auth.Session{
+		ID:           session.ID,
+		TokenVersion: int(user.TokenVersion),
+	})
+	if err != nil {
+		h.logError("issue token failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to refresh session")
+		return
+	}
+	refreshExpiresIn := expiresInSeconds(session.ExpiresAt.Time)
+	c.JSON(http.StatusOK, oapi.AuthResponse{
+		AccessToken:      token,
+		ExpiresIn:        expiresInSeconds(expiresAt),
+		RefreshToken:     &refreshToken,
+		RefreshExpiresIn: &refreshExpiresIn,
+		User:             userFromModel(user, roles, session.Role, userType),
+	})
+}
+
+// PostAuthLogout revokes the caller's session. Access tokens already issued
+// for it stay valid until they expire unless allSessions is set, which also
+// bumps the token version so every outstanding access token is rejected.
+func (h *Handler) PostAuthLogout(c *gin.Context) {
+	claims, ok := h.requireClaims(c)
+	if !ok {
+		return
+	}
+
+	var request logoutRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid request body")
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	if request.AllSessions {
+		if err := h.revokeAllSessions(c, claims.UserID, sessionRevokedLogoutAll); err != nil {
+			h.logError("logout all sessions failed", err)
+			h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to logout")
+			return
+		}
+		h.recordAudit(c, &claims.UserID, "session.logout_all", "user", &claims.UserID, nil)
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	sessionID := uuid.Nil
+	if claims.SessionID != nil {
+		sessionID = *claims.SessionID
+	} else if request.RefreshToken != nil {
+		parsed, _, err := auth.ParseRefreshToken(*request.RefreshToken)
+		if err != nil {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid refresh token")
+			return
+		}
+		sessionID = parsed
+	}
+	if sessionID == uuid.Nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	reason := sessionRevokedLogout
+	if _, err := h.Store.RevokeUserSession(ctx, db.RevokeUserSessionParams{
+		ID:            sessionID,
+		UserID:        claims.UserID,
+		RevokedReason: &reason,
+	}); err != nil {
+		h.logError("logout session failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to logout")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) GetAdminUsersUserIdSessions(c *gin.Context) {
+	if _, _, ok := h.requirePermission(c, sessionManagePermissionCode, "ALL"); !ok {
+		return
+	}
+	userID, err := uuid.Parse(strings.TrimSpace(c.Param("userId")))
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid user id")
+		return
+	}
+
+	sessions, err := h.Store.ListActiveUserSessions(c.Request.Context(), userID)
+	if err != nil {
+		h.logError("list user sessions failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list sessions")
+		return
+	}
+	items := make([]userSessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		items = append(items, userSessionFromModel(session))
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items})
+}
+
+func (h *Handler) DeleteAdminUsersUserIdSessions(c *gin.Context) {
+	claims, _, ok := h.requirePermission(c, sessionManagePermissionCode, "ALL")
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(strings.TrimSpace(c.Param("userId")))
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid user id")
+		return
+	}
+
+	if err := h.revokeAllSessions(c, userID, sessionRevokedAllByAdmin); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writeError(c, http.StatusNotFound, "not_found", "user not found")
+			return
+		}
+		h.logError("revoke user sessions failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to revoke sessions")
+		return
+	}
+	h.recordAudit(c, &claims.UserID, "session.revoke_all", "user", &userID, nil)
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) DeleteAdminUsersUserIdSessionsSessionId(c *gin.Context) {
+	claims, _, ok := h.requirePermission(c, sessionManagePermissionCode, "ALL")
+	if !ok {
+		return
+	}
+	userID, err := uuid.Parse(strings.TrimSpace(c.Param("userId")))
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid user id")
+		return
+	}
+	sessionID, err := uuid.Parse(strings.TrimSpace(c.Param("sessionId")))
+	if err != nil {
+		h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid session id")
+		return
+	}
+
+	reason := sessionRevokedByAdmin
+	revoked, err := h.Store.RevokeUserSession(c.Request.Context(), db.RevokeUserSessionParams{
+		ID:            sessionID,
+		UserID:        userID,
+		RevokedReason: &reason,
+	})
+	if err != nil {
+		h.logError("revoke user session failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to revoke session")
+		return
+	}
+	if revoked == 0 {
+		h.writeError(c, http.StatusNotFound, "not_found", "session not found")
+		return
+	}
+	h.recordAudit(c, &claims.UserID, "session.revoke", "session", &sessionID, map[string]interface{}{
+		"userId": userID.String(),
+	})
+	c.Status(http.StatusNoContent)
+}
+
+// GetInternalTokenVersions lists users whose token version changed after
+// since. Commerce and payment poll it to reject revoked access tokens.
+func (h *Handler) GetInternalTokenVersions(c *gin.Context) {
+	if !h.authorizeInternal(c) {
+		h.writeError(c, http.StatusUnauthorized, "unauthorized", "invalid internal token")
+		return
+	}
+
+	since := time.Time{}
+	if raw := strings.TrimSpace(c.Query("since")); raw != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			h.writeError(c, http.StatusBadRequest, "invalid_request", "invalid since")
+			return
+		}
+		since = parsed
+	}
+
+	asOf := time.Now().UTC()
+	rows, err := h.Store.ListTokenVersionsChangedSince(c.Request.Context(), pgtype.Timestamptz{Time: since, Valid: true})
+	if err != nil {
+		h.logError("list token versions failed", err)
+		h.writeError(c, http.StatusInternalServerError, "internal_error", "failed to list token versions")
+		return
+	}
+	items := make([]internalTokenVersion, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, internalTokenVersion{UserID: row.ID, TokenVersion: row.TokenVersion})
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "asOf": asOf})
+}
+
+// revokeAllSessions revokes every session of userID and bumps the user's
+// token version so access tokens already handed out stop working too.
+func (h *Handler) revokeAllSessions(c *gin.Context, userID uuid.UUID, reason string) error {
+	ctx := c.Request.Context()
+	return shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		return revokeAllSessionsTx(ctx, h.Store.WithTx(tx), userID, reason)
+	})
+}
+
+func revokeAllSessionsTx(ctx context.Context, q *db.Queries, userID uuid.UUID, reason string) error {
+	if _, err := q.RevokeUserSessions(ctx, db.RevokeUserSessionsParams{
+		UserID:        userID,
+		RevokedReason: &reason,
+	}); err != nil {
+		return err
+	}
+	_, err := q.BumpUserTokenVersion(ctx, userID)
+	return err
+}
+
+func userSessionFromModel(session db.UserSession) userSessionResponse {
+	return userSessionResponse{
+		ID:         session.ID,
+		Role:       session.Role,
+		Platform:   session.Platform,
+		DeviceID:   session.DeviceID,
+		DeviceName: session.DeviceName,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IpAddress,
+		CreatedAt:  session.CreatedAt.Time,
+		LastUsedAt: session.LastUsedAt.Time,
+		ExpiresAt:  session.ExpiresAt.Time,
+	}
+}
+
+func optionalString(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -266,6 +266,11 @@ func (h *Handler) PatchStaffStaffId(c *gin.Context, staffId types.UUID) {
 			}); err != nil {
 				return err
 			}
+			if status == "disabled" {
+				if err := revokeAllSessionsTx(c.Request.Context(), q, user.ID, sessionRevokedDisabled); err != nil {
+					return err
+				}
+			}
 		}
 		if request.Roles != nil {
 			if err := q.DeleteUserRoles(c.Request.Context(), user.ID); err != nil {
@@ -22,6 +22,12 @@ func NewRouter(handler *handler.Handler, logger *slog.Logger, readyCheck func(co
 	router.GET("/ready", httpx.Ready(readyCheck))
 
 	oapi.RegisterHandlers(router, handler)
+	router.GET("/.well-known/jwks.json", handler.GetWellKnownJwksJson)
+	router.POST("/auth/refresh", handler.PostAuthRefresh)
+	router.POST("/auth/logout", handler.PostAuthLogout)
+	router.GET("/admin/users/:userId/sessions", handler.GetAdminUsersUserIdSessions)
+	router.DELETE("/admin/users/:userId/sessions", handler.DeleteAdminUsersUserIdSessions)
+	router.DELETE("/admin/users/:userId/sessions/:sessionId", handler.DeleteAdminUsersUserIdSessionsSessionId)
 	router.GET("/admin/config/feature-flags", handler.GetAdminConfigFeatureFlags)
 	router.PATCH("/admin/config/feature-flags", handler.PatchAdminConfigFeatureFlags)
 	router.GET("/admin/sales-users", handler.GetAdminSalesUsers)
@@ -32,6 +38,10 @@ func NewRouter(handler *handler.Handler, logger *slog.Logger, readyCheck func(co
 	router.POST("/admin/customers/:customerId/transfer", handler.PostAdminCustomersCustomerIdTransfer)
 	router.GET("/admin/customers/:customerId/finance-profile", handler.GetAdminCustomersCustomerIdFinanceProfile)
 	router.PATCH("/admin/customers/:customerId/finance-profile", handler.PatchAdminCustomersCustomerIdFinanceProfile)
+	router.GET("/internal/customers/:customerId/finance-profile", handler.GetInternalCustomersCustomerIdFinanceProfile)
+	router.GET("/internal/customers/:customerId/tags", handler.GetInternalCustomersCustomerIdTags)
+	router.GET("/internal/rbac/grants", handler.GetInternalRbacGrants)
+	router.GET("/internal/token-versions", handler.GetInternalTokenVersions)
 	router.GET("/admin/customer-tags", handler.GetAdminCustomerTags)
 	router.POST("/admin/customer-tags", handler.PostAdminCustomerTags)
 	router.PATCH("/admin/customer-tags/:tagId", handler.PatchAdminCustomerTagsTagId)
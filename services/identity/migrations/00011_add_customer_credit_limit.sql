@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- credit_limit_fen caps the open receivables commerce lets a MONTHLY customer
+-- carry; NULL means no limit.
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS credit_limit_fen bigint;
+
+ALTER TABLE users
+  DROP CONSTRAINT IF EXISTS users_credit_limit_fen_check,
+  ADD CONSTRAINT users_credit_limit_fen_check CHECK (credit_limit_fen IS NULL OR credit_limit_fen >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+  DROP CONSTRAINT IF EXISTS users_credit_limit_fen_check;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS credit_limit_fen;
+-- +goose StatementEnd
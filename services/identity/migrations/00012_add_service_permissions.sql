@@ -0,0 +1,81 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO permissions (code, description) VALUES
+  ('payment:read', 'Read payments'),
+  ('supplier:read', 'Read suppliers'),
+  ('supplier:manage', 'Manage suppliers'),
+  ('inventory:read', 'Read inventory'),
+  ('inventory:manage', 'Adjust inventory'),
+  ('receivable:read', 'Read receivables'),
+  ('statement:read', 'Read customer statements'),
+  ('statement:manage', 'Generate customer statements'),
+  ('support:create', 'Start support conversations'),
+  ('support:manage', 'Handle support conversations')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_code, permission_code, scope)
+SELECT role_code, permissions.code, 'ALL'
+FROM (VALUES ('ADMIN'), ('BOSS')) AS roles(role_code)
+CROSS JOIN permissions
+WHERE permissions.code IN (
+  'payment:read', 'supplier:read', 'supplier:manage', 'inventory:read', 'inventory:manage',
+  'receivable:read', 'statement:read', 'statement:manage', 'support:create', 'support:manage'
+)
+ON CONFLICT (role_code, permission_code) DO NOTHING;
+
+-- Grants below keep what each role could already do in commerce and payment
+-- before the services checked permissions instead of role lists.
+INSERT INTO role_permissions (role_code, permission_code, scope) VALUES
+  ('CUSTOMER', 'payment:read', 'SELF'),
+  ('CUSTOMER', 'statement:read', 'SELF'),
+  ('CUSTOMER', 'support:create', 'SELF'),
+  ('SALES', 'inquiry:read', 'OWNED'),
+  ('SALES', 'after_sales:read', 'OWNED'),
+  ('SALES', 'supplier:read', 'ALL'),
+  ('CS', 'inquiry:read', 'ALL'),
+  ('CS', 'after_sales:read', 'ALL'),
+  ('CS', 'supplier:read', 'ALL'),
+  ('CS', 'inventory:read', 'ALL'),
+  ('CS', 'payment:read', 'ALL'),
+  ('CS', 'support:manage', 'ALL'),
+  ('MANAGER', 'inquiry:create', 'ALL'),
+  ('MANAGER', 'inquiry:read', 'ALL'),
+  ('MANAGER', 'after_sales:create', 'ALL'),
+  ('MANAGER', 'after_sales:read', 'ALL'),
+  ('MANAGER', 'import:shipment', 'ALL'),
+  ('MANAGER', 'shipment:manage', 'ALL'),
+  ('MANAGER', 'supplier:read', 'ALL'),
+  ('MANAGER', 'supplier:manage', 'ALL'),
+  ('MANAGER', 'inventory:read', 'ALL'),
+  ('MANAGER', 'inventory:manage', 'ALL'),
+  ('MANAGER', 'receivable:read', 'ALL'),
+  ('MANAGER', 'statement:read', 'ALL'),
+  ('MANAGER', 'statement:manage', 'ALL'),
+  ('MANAGER', 'support:manage', 'ALL'),
+  ('MANAGER', 'payment:read', 'ALL'),
+  ('MANAGER', 'payment:manage', 'ALL')
+ON CONFLICT (role_code, permission_code) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM role_permissions
+WHERE (role_code, permission_code) IN (
+  ('SALES', 'inquiry:read'),
+  ('SALES', 'after_sales:read'),
+  ('CS', 'inquiry:read'),
+  ('CS', 'after_sales:read'),
+  ('MANAGER', 'inquiry:create'),
+  ('MANAGER', 'inquiry:read'),
+  ('MANAGER', 'after_sales:create'),
+  ('MANAGER', 'after_sales:read'),
+  ('MANAGER', 'import:shipment'),
+  ('MANAGER', 'shipment:manage'),
+  ('MANAGER', 'payment:manage')
+);
+
+DELETE FROM permissions WHERE code IN (
+  'payment:read', 'supplier:read', 'supplier:manage', 'inventory:read', 'inventory:manage',
+  'receivable:read', 'statement:read', 'statement:manage', 'support:create', 'support:manage'
+);
+-- +goose StatementEnd
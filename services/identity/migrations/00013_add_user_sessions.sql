@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+-- token_version is stamped into every access token. Bumping it revokes all
+-- access tokens issued before; token_version_changed_at lets commerce and
+-- payment poll only the users whose version moved.
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS token_version integer NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS token_version_changed_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_users_token_version_changed_at
+  ON users (token_version_changed_at)
+  WHERE token_version_changed_at IS NOT NULL;
+
+-- One row per login. Only the SHA-256 of the current refresh token is kept;
+-- it is replaced on every refresh.
+CREATE TABLE IF NOT EXISTS user_sessions (
+  id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id uuid NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  role text NOT NULL,
+  refresh_token_hash text NOT NULL UNIQUE,
+  platform text,
+  device_id text,
+  device_name text,
+  user_agent text,
+  ip_address text,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  last_used_at timestamptz NOT NULL DEFAULT now(),
+  expires_at timestamptz NOT NULL,
+  revoked_at timestamptz,
+  revoked_reason text
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user_id ON user_sessions (user_id, created_at DESC);
+
+INSERT INTO permissions (code, description) VALUES
+  ('session:manage', 'List and revoke user sessions')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_code, permission_code, scope) VALUES
+  ('ADMIN', 'session:manage', 'ALL'),
+  ('BOSS', 'session:manage', 'ALL')
+ON CONFLICT (role_code, permission_code) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM role_permissions WHERE permission_code = 'session:manage';
+DELETE FROM permissions WHERE code = 'session:manage';
+
+DROP TABLE IF EXISTS user_sessions;
+
+DROP INDEX IF EXISTS idx_users_token_version_changed_at;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS token_version_changed_at,
+  DROP COLUMN IF EXISTS token_version;
+-- +goose StatementEnd
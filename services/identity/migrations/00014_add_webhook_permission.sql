@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO permissions (code, description) VALUES
+  ('webhook:manage', 'Manage customer webhook subscriptions')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_code, permission_code, scope) VALUES
+  ('ADMIN', 'webhook:manage', 'ALL'),
+  ('BOSS', 'webhook:manage', 'ALL')
+ON CONFLICT (role_code, permission_code) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM role_permissions WHERE permission_code = 'webhook:manage';
+DELETE FROM permissions WHERE code = 'webhook:manage';
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO permissions (code, description) VALUES
+  ('pricing:manage', 'Manage customer price lists')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_code, permission_code, scope) VALUES
+  ('ADMIN', 'pricing:manage', 'ALL'),
+  ('BOSS', 'pricing:manage', 'ALL'),
+  ('MANAGER', 'pricing:manage', 'ALL')
+ON CONFLICT (role_code, permission_code) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM role_permissions WHERE permission_code = 'pricing:manage';
+DELETE FROM permissions WHERE code = 'pricing:manage';
+-- +goose StatementEnd
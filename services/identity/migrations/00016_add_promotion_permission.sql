@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO permissions (code, description) VALUES
+  ('promotion:manage', 'Manage promotions and coupons')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO role_permissions (role_code, permission_code, scope) VALUES
+  ('ADMIN', 'promotion:manage', 'ALL'),
+  ('BOSS', 'promotion:manage', 'ALL'),
+  ('MANAGER', 'promotion:manage', 'ALL')
+ON CONFLICT (role_code, permission_code) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM role_permissions WHERE permission_code = 'promotion:manage';
+DELETE FROM permissions WHERE code = 'promotion:manage';
+-- +goose StatementEnd
@@ -117,7 +117,7 @@ SELECT * FROM users
 WHERE id = $1 AND user_type = 'customer';
 
 -- name: GetCustomerFinanceProfile :one
-SELECT id, payment_term_type, payment_term_days, payment_term_custom_label, payment_term_remark, updated_at
+SELECT id, payment_term_type, payment_term_days, payment_term_custom_label, payment_term_remark, credit_limit_fen, updated_at
 FROM users
 WHERE id = $1 AND user_type = 'customer';
 
@@ -127,9 +127,10 @@ SET payment_term_type = $2,
     payment_term_days = $3,
     payment_term_custom_label = $4,
     payment_term_remark = $5,
+    credit_limit_fen = $6,
     updated_at = now()
 WHERE id = $1 AND user_type = 'customer'
-RETURNING id, payment_term_type, payment_term_days, payment_term_custom_label, payment_term_remark, updated_at;
+RETURNING id, payment_term_type, payment_term_days, payment_term_custom_label, payment_term_remark, credit_limit_fen, updated_at;
 
 -- name: ListUserRoles :many
 SELECT role FROM user_roles WHERE user_id = $1 ORDER BY role;
@@ -224,6 +225,9 @@ SET scope = EXCLUDED.scope;
 -- name: ListRolePermissions :many
 SELECT permission_code, scope FROM role_permissions WHERE role_code = $1 ORDER BY permission_code;
 
+-- name: ListAllRolePermissions :many
+SELECT role_code, permission_code, scope FROM role_permissions ORDER BY role_code, permission_code;
+
 -- name: ListEffectivePermissions :many
 SELECT permission_code,
   CASE MAX(
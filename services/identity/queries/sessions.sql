@@ -0,0 +1,52 @@
+-- name: CreateUserSession :one
+INSERT INTO user_sessions (id, user_id, role, refresh_token_hash, platform, device_id, device_name, user_agent, ip_address, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetUserSessionForUpdate :one
+SELECT * FROM user_sessions WHERE id = $1 FOR UPDATE;
+
+-- name: RotateUserSession :one
+UPDATE user_sessions
+SET refresh_token_hash = $2,
+    expires_at = $3,
+    last_used_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateUserSessionRole :exec
+UPDATE user_sessions
+SET role = $3
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: RevokeUserSession :execrows
+UPDATE user_sessions
+SET revoked_at = now(),
+    revoked_reason = $3
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: RevokeUserSessions :execrows
+UPDATE user_sessions
+SET revoked_at = now(),
+    revoked_reason = $2
+WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: ListActiveUserSessions :many
+SELECT * FROM user_sessions
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > now()
+ORDER BY last_used_at DESC;
+
+-- name: BumpUserTokenVersion :one
+UPDATE users
+SET token_version = token_version + 1,
+    token_version_changed_at = now()
+WHERE id = $1
+RETURNING token_version;
+
+-- name: GetUserTokenState :one
+SELECT status, token_version FROM users WHERE id = $1;
+
+-- name: ListTokenVersionsChangedSince :many
+SELECT id, token_version FROM users
+WHERE token_version_changed_at > $1
+ORDER BY token_version_changed_at;
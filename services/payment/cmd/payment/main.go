@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -13,12 +14,19 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/packages/go-shared/jwks"
 	"github.com/teamdsb/tmo/packages/go-shared/observability"
+	"github.com/teamdsb/tmo/packages/go-shared/revocation"
 	"github.com/teamdsb/tmo/services/payment/internal/config"
 	"github.com/teamdsb/tmo/services/payment/internal/db"
 	httpserver "github.com/teamdsb/tmo/services/payment/internal/http"
 	"github.com/teamdsb/tmo/services/payment/internal/http/handler"
 	"github.com/teamdsb/tmo/services/payment/internal/http/middleware"
+	"github.com/teamdsb/tmo/services/payment/internal/modules/reconcile"
 )
 
 func main() {
@@ -78,26 +86,52 @@ func run(ctx context.Context, cfg config.Config, logger *slog.Logger) error {
 		return fmt.Errorf("apply migrations failed: %w", err)
 	}
 
-	auth := middleware.NewAuthenticator(cfg.AuthEnabled, cfg.JWTSecret, cfg.JWTIssuer)
+	authorizer := authz.NewAuthorizer(authz.NewClient(cfg.IdentityBaseURL, cfg.IdentityToken, nil))
+	authorizer.Start(ctx, cfg.RBACRefreshEvery, logger)
+	revocations := revocation.NewRegistry(revocation.NewClient(cfg.IdentityBaseURL, cfg.IdentityToken, nil))
+	revocations.Start(ctx, cfg.RevocationRefreshEvery, logger)
+	keys := jwks.NewCache(cfg.JWKSURL, nil)
+	if cfg.AuthEnabled {
+		keys.Start(ctx, cfg.JWKSRefreshEvery, logger)
+	}
+	auth := middleware.NewAuthenticator(cfg.AuthEnabled, keys, cfg.JWTIssuer).
+		WithAuthorizer(authorizer).
+		WithRevocations(revocations)
 	flagsProvider := handler.NewIdentityFlagsProvider(cfg.IdentityBaseURL, cfg.FeatureFlagsTimeout, handler.FeatureFlags{
 		PaymentEnabled:   cfg.PaymentEnabled,
 		WechatPayEnabled: cfg.WechatPayEnabled,
 		AlipayPayEnabled: cfg.AlipayPayEnabled,
 	}, logger)
 
+	// Payment status changes reach commerce through the outbox, so a paid
+	// order is marked paid even if commerce was down when the payment settled.
+	outbox := events.NewOutbox(pool, "payment",
+		events.NewHTTPSubscriber("commerce", strings.TrimRight(cfg.CommerceBaseURL, "/")+"/internal/events", cfg.CommerceSyncToken, []string{"payment.*"}, nil),
+	).WithMaxAttempts(cfg.OutboxMaxAttempts)
+	outbox.Start(ctx, cfg.OutboxPollEvery, logger)
+
 	apiHandler := &handler.Handler{
-		Logger:       logger,
-		Auth:         auth,
-		Flags:        flagsProvider,
-		Store:        db.New(pool),
-		Commerce:     handler.NewCommerceClient(cfg.CommerceBaseURL, cfg.CommerceSyncToken),
-		ProviderMode: cfg.ProviderMode,
-	}
-	if provider, providerErr := handler.NewWechatB2BDirectProvider(handler.WechatB2BConfig{AppID: cfg.WechatB2BAppID, AppSecret: cfg.WechatB2BAppSecret, MchID: cfg.WechatB2BMchID, AppKey: cfg.WechatB2BAppKey, Environment: cfg.WechatB2BEnvironment, SessionURL: cfg.WechatSessionURL}); providerErr == nil {
-		apiHandler.WechatB2BProvider = provider
-	} else {
-		logger.Warn("wechat b2b provider disabled", "reason", providerErr)
+		Logger:        logger,
+		Auth:          auth,
+		Flags:         flagsProvider,
+		Store:         db.New(pool),
+		DB:            pool,
+		Outbox:        outbox,
+		Commerce:      handler.NewCommerceClient(cfg.CommerceBaseURL, cfg.CommerceSyncToken),
+		InternalToken: cfg.CommerceSyncToken,
+		ProviderMode:  cfg.ProviderMode,
+	}
+	providers, err := configureProviders(cfg, logger)
+	if err != nil {
+		return err
 	}
+	apiHandler.Providers = providers
+	(&reconcile.PendingWorker{
+		Settler:       apiHandler,
+		After:         cfg.PendingSettleAfter,
+		CheckInterval: cfg.PendingSettleEvery,
+		Logger:        logger,
+	}).Start(ctx)
 
 	router := httpserver.NewRouter(apiHandler, logger, func(checkCtx context.Context) error {
 		return db.Ready(checkCtx, pool)
@@ -134,6 +168,106 @@ func run(ctx context.Context, cfg config.Config, logger *slog.Logger) error {
 	return nil
 }
 
+// configureProviders registers one provider per channel for the configured
+// provider mode. mock answers locally and lets clients report results, sandbox
+// simulates a channel end to end including async notifies, and any other mode
+// wires the channels whose merchant credentials are configured.
+func configureProviders(cfg config.Config, logger *slog.Logger) (handler.Providers, error) {
+	providers := handler.Providers{}
+	switch strings.ToLower(strings.TrimSpace(cfg.ProviderMode)) {
+	case "mock":
+		providers["WECHAT"] = handler.MockProvider{}
+		providers["ALIPAY"] = handler.MockProvider{}
+		configureWechatB2BProvider(providers, cfg, logger)
+		return providers, nil
+	case "sandbox":
+		secret := cfg.SandboxSecret
+		if strings.TrimSpace(secret) == "" {
+			secret = uuid.NewString()
+		}
+		sandbox, err := handler.NewSandboxProvider(handler.SandboxConfig{
+			NotifyBaseURL: sandboxNotifyBaseURL(cfg),
+			NotifyDelay:   cfg.SandboxNotifyDelay,
+			Result:        cfg.SandboxResult,
+			Secret:        secret,
+			Logger:        logger,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("sandbox provider: %w", err)
+		}
+		for _, channel := range []string{"WECHAT", "WECHAT_B2B", "ALIPAY"} {
+			providers[channel] = sandbox
+		}
+		return providers, nil
+	}
+
+	configureWechatB2BProvider(providers, cfg, logger)
+	wechatKey, err := readSecretFile(cfg.WechatPrivateKeyPath)
+	if err != nil {
+		logger.Warn("wechat pay provider disabled", "reason", err)
+	} else if platformKey, keyErr := readSecretFile(cfg.WechatPlatformCertPath); keyErr != nil {
+		logger.Warn("wechat pay provider disabled", "reason", keyErr)
+	} else if provider, providerErr := handler.NewWechatPayProvider(handler.WechatPayConfig{MchID: cfg.WechatMchID, SerialNo: cfg.WechatSerialNumber, PrivateKeyPEM: wechatKey, BaseURL: cfg.WechatPayBaseURL, RefundNotifyURL: cfg.WechatRefundNotify, APIv3Key: cfg.WechatAPIv3Key, PlatformKeyPEM: platformKey, PlatformSerial: cfg.WechatPlatformSerial}); providerErr == nil {
+		if platformKey == "" || cfg.WechatAPIv3Key == "" {
+			logger.Warn("wechat pay notifies will be rejected until the platform certificate and apiv3 key are configured")
+		}
+		providers["WECHAT"] = provider
+	} else {
+		logger.Warn("wechat pay provider disabled", "reason", providerErr)
+	}
+
+	alipayKey, err := readSecretFile(cfg.AlipayPrivateKeyPath)
+	if err != nil {
+		logger.Warn("alipay provider disabled", "reason", err)
+	} else if publicKey, keyErr := readSecretFile(cfg.AlipayPublicKeyPath); keyErr != nil {
+		logger.Warn("alipay provider disabled", "reason", keyErr)
+	} else if provider, providerErr := handler.NewAlipayProvider(handler.AlipayConfig{AppID: cfg.AlipayAppID, PrivateKeyPEM: alipayKey, GatewayURL: cfg.AlipayGatewayURL, AlipayPublicKeyPEM: publicKey}); providerErr == nil {
+		if publicKey == "" {
+			logger.Warn("alipay notifies will be rejected until the alipay public key is configured")
+		}
+		providers["ALIPAY"] = provider
+	} else {
+		logger.Warn("alipay provider disabled", "reason", providerErr)
+	}
+	return providers, nil
+}
+
+func configureWechatB2BProvider(providers handler.Providers, cfg config.Config, logger *slog.Logger) {
+	provider, err := handler.NewWechatB2BDirectProvider(handler.WechatB2BConfig{AppID: cfg.WechatB2BAppID, AppSecret: cfg.WechatB2BAppSecret, MchID: cfg.WechatB2BMchID, AppKey: cfg.WechatB2BAppKey, Environment: cfg.WechatB2BEnvironment, SessionURL: cfg.WechatSessionURL, NotifyToken: cfg.WechatB2BNotifyToken, NotifyAESKey: cfg.WechatB2BNotifyAESKey})
+	if err != nil {
+		logger.Warn("wechat b2b provider disabled", "reason", err)
+		return
+	}
+	providers["WECHAT_B2B"] = provider
+}
+
+// sandboxNotifyBaseURL defaults to this service's own listen address so the
+// sandbox notifies loop back without any extra configuration.
+func sandboxNotifyBaseURL(cfg config.Config) string {
+	if strings.TrimSpace(cfg.SandboxNotifyBaseURL) != "" {
+		return cfg.SandboxNotifyBaseURL
+	}
+	host, port, err := net.SplitHostPort(cfg.HTTPAddr)
+	if err != nil {
+		return ""
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, port)
+}
+
+func readSecretFile(path string) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return "", nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", path, err)
+	}
+	return string(content), nil
+}
+
 func resolveMigrationsDir(configured string) (string, error) {
 	candidates := []string{}
 	if strings.TrimSpace(configured) != "" {
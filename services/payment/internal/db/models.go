@@ -29,6 +29,8 @@ type Payment struct {
 	ClosedAt         pgtype.Timestamptz `db:"closed_at" json:"closed_at"`
 	CreatedAt        pgtype.Timestamptz `db:"created_at" json:"created_at"`
 	UpdatedAt        pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	RefundedFen      int64              `db:"refunded_fen" json:"refunded_fen"`
+	OutTradeNo       *string            `db:"out_trade_no" json:"out_trade_no"`
 }
 
 type PaymentAuditLog struct {
@@ -41,14 +43,58 @@ type PaymentAuditLog struct {
 }
 
 type PaymentWebhook struct {
-	ID             uuid.UUID          `db:"id" json:"id"`
-	PaymentID      pgtype.UUID        `db:"payment_id" json:"payment_id"`
-	Provider       string             `db:"provider" json:"provider"`
-	EventType      string             `db:"event_type" json:"event_type"`
-	DeliveryStatus string             `db:"delivery_status" json:"delivery_status"`
-	RawBody        json.RawMessage    `db:"raw_body" json:"raw_body"`
-	ReplayCount    int32              `db:"replay_count" json:"replay_count"`
-	ProcessedAt    pgtype.Timestamptz `db:"processed_at" json:"processed_at"`
-	CreatedAt      pgtype.Timestamptz `db:"created_at" json:"created_at"`
-	UpdatedAt      pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	ID              uuid.UUID          `db:"id" json:"id"`
+	PaymentID       pgtype.UUID        `db:"payment_id" json:"payment_id"`
+	Provider        string             `db:"provider" json:"provider"`
+	EventType       string             `db:"event_type" json:"event_type"`
+	DeliveryStatus  string             `db:"delivery_status" json:"delivery_status"`
+	RawBody         json.RawMessage    `db:"raw_body" json:"raw_body"`
+	ReplayCount     int32              `db:"replay_count" json:"replay_count"`
+	ProcessedAt     pgtype.Timestamptz `db:"processed_at" json:"processed_at"`
+	CreatedAt       pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
+	RejectionReason *string            `db:"rejection_reason" json:"rejection_reason"`
+}
+
+type ReconciliationDiscrepancy struct {
+	ID                 uuid.UUID          `db:"id" json:"id"`
+	RunID              uuid.UUID          `db:"run_id" json:"run_id"`
+	Kind               string             `db:"kind" json:"kind"`
+	PaymentID          pgtype.UUID        `db:"payment_id" json:"payment_id"`
+	OutTradeNo         *string            `db:"out_trade_no" json:"out_trade_no"`
+	ProviderTradeNo    *string            `db:"provider_trade_no" json:"provider_trade_no"`
+	LedgerAmountFen    *int64             `db:"ledger_amount_fen" json:"ledger_amount_fen"`
+	StatementAmountFen *int64             `db:"statement_amount_fen" json:"statement_amount_fen"`
+	LedgerStatus       *string            `db:"ledger_status" json:"ledger_status"`
+	StatementStatus    *string            `db:"statement_status" json:"statement_status"`
+	CreatedAt          pgtype.Timestamptz `db:"created_at" json:"created_at"`
+}
+
+type ReconciliationRun struct {
+	ID               uuid.UUID          `db:"id" json:"id"`
+	Channel          string             `db:"channel" json:"channel"`
+	StatementDate    pgtype.Date        `db:"statement_date" json:"statement_date"`
+	FileName         string             `db:"file_name" json:"file_name"`
+	StatementLines   int32              `db:"statement_lines" json:"statement_lines"`
+	MatchedCount     int32              `db:"matched_count" json:"matched_count"`
+	DiscrepancyCount int32              `db:"discrepancy_count" json:"discrepancy_count"`
+	CreatedBy        string             `db:"created_by" json:"created_by"`
+	CreatedAt        pgtype.Timestamptz `db:"created_at" json:"created_at"`
+}
+
+type Refund struct {
+	ID               uuid.UUID          `db:"id" json:"id"`
+	PaymentID        uuid.UUID          `db:"payment_id" json:"payment_id"`
+	OrderID          uuid.UUID          `db:"order_id" json:"order_id"`
+	Channel          string             `db:"channel" json:"channel"`
+	Status           string             `db:"status" json:"status"`
+	AmountFen        int64              `db:"amount_fen" json:"amount_fen"`
+	Reason           *string            `db:"reason" json:"reason"`
+	IdempotencyKey   string             `db:"idempotency_key" json:"idempotency_key"`
+	ProviderRefundNo *string            `db:"provider_refund_no" json:"provider_refund_no"`
+	FailureMessage   *string            `db:"failure_message" json:"failure_message"`
+	RequestedBy      string             `db:"requested_by" json:"requested_by"`
+	RefundedAt       pgtype.Timestamptz `db:"refunded_at" json:"refunded_at"`
+	CreatedAt        pgtype.Timestamptz `db:"created_at" json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `db:"updated_at" json:"updated_at"`
 }
@@ -89,6 +89,7 @@ func (q *Queries) CountPayments(ctx context.Context, arg CountPaymentsParams) (i
 
 const createPayment = `-- name: CreatePayment :one
 INSERT INTO payments (
+    id,
     order_id,
     payer_user_id,
     channel,
@@ -102,7 +103,8 @@ INSERT INTO payments (
     failure_code,
     failure_message,
     paid_at,
-    closed_at
+    closed_at,
+    out_trade_no
 ) VALUES (
     $1,
     $2,
@@ -117,12 +119,15 @@ INSERT INTO payments (
     $11,
     $12,
     $13,
-    $14
+    $14,
+    $15,
+    $16
 )
-RETURNING id, order_id, payer_user_id, channel, status, amount_fen, currency, idempotency_key, provider_trade_no, provider_prepay_id, provider_payload, failure_code, failure_message, paid_at, closed_at, created_at, updated_at
+RETURNING id, order_id, payer_user_id, channel, status, amount_fen, currency, idempotency_key, provider_trade_no, provider_prepay_id, provider_payload, failure_code, failure_message, paid_at, closed_at, created_at, updated_at, refunded_fen, out_trade_no
 `
 
 type CreatePaymentParams struct {
+	ID               uuid.UUID          `db:"id" json:"id"`
 	OrderID          uuid.UUID          `db:"order_id" json:"order_id"`
 	PayerUserID      pgtype.UUID        `db:"payer_user_id" json:"payer_user_id"`
 	Channel          string             `db:"channel" json:"channel"`
@@ -137,10 +142,12 @@ type CreatePaymentParams struct {
 	FailureMessage   *string            `db:"failure_message" json:"failure_message"`
 	PaidAt           pgtype.Timestamptz `db:"paid_at" json:"paid_at"`
 	ClosedAt         pgtype.Timestamptz `db:"closed_at" json:"closed_at"`
+	OutTradeNo       *string            `db:"out_trade_no" json:"out_trade_no"`
 }
 
 func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error) {
 	row := q.db.QueryRow(ctx, createPayment,
+		arg.ID,
 		arg.OrderID,
 		arg.PayerUserID,
 		arg.Channel,
@@ -155,6 +162,7 @@ func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (P
 		arg.FailureMessage,
 		arg.PaidAt,
 		arg.ClosedAt,
+		arg.OutTradeNo,
 	)
 	var i Payment
 	err := row.Scan(
@@ -175,6 +183,8 @@ func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (P
 		&i.ClosedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RefundedFen,
+		&i.OutTradeNo,
 	)
 	return i, err
 }
@@ -227,25 +237,28 @@ INSERT INTO payment_webhooks (
     event_type,
     delivery_status,
     raw_body,
-    processed_at
+    processed_at,
+    rejection_reason
 ) VALUES (
     $1,
     $2,
     $3,
     $4,
     $5,
-    $6
+    $6,
+    $7
 )
-RETURNING id, payment_id, provider, event_type, delivery_status, raw_body, replay_count, processed_at, created_at, updated_at
+RETURNING id, payment_id, provider, event_type, delivery_status, raw_body, replay_count, processed_at, created_at, updated_at, rejection_reason
 `
 
 type CreatePaymentWebhookParams struct {
-	PaymentID      pgtype.UUID        `db:"payment_id" json:"payment_id"`
-	Provider       string             `db:"provider" json:"provider"`
-	EventType      string             `db:"event_type" json:"event_type"`
-	DeliveryStatus string             `db:"delivery_status" json:"delivery_status"`
-	RawBody        json.RawMessage    `db:"raw_body" json:"raw_body"`
-	ProcessedAt    pgtype.Timestamptz `db:"processed_at" json:"processed_at"`
+	PaymentID       pgtype.UUID        `db:"payment_id" json:"payment_id"`
+	Provider        string             `db:"provider" json:"provider"`
+	EventType       string             `db:"event_type" json:"event_type"`
+	DeliveryStatus  string             `db:"delivery_status" json:"delivery_status"`
+	RawBody         json.RawMessage    `db:"raw_body" json:"raw_body"`
+	ProcessedAt     pgtype.Timestamptz `db:"processed_at" json:"processed_at"`
+	RejectionReason *string            `db:"rejection_reason" json:"rejection_reason"`
 }
 
 func (q *Queries) CreatePaymentWebhook(ctx context.Context, arg CreatePaymentWebhookParams) (PaymentWebhook, error) {
@@ -256,6 +269,7 @@ func (q *Queries) CreatePaymentWebhook(ctx context.Context, arg CreatePaymentWeb
 		arg.DeliveryStatus,
 		arg.RawBody,
 		arg.ProcessedAt,
+		arg.RejectionReason,
 	)
 	var i PaymentWebhook
 	err := row.Scan(
@@ -269,12 +283,13 @@ func (q *Queries) CreatePaymentWebhook(ctx context.Context, arg CreatePaymentWeb
 		&i.ProcessedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RejectionReason,
 	)
 	return i, err
 }
 
 const getPayment = `-- name: GetPayment :one
-SELECT id, order_id, payer_user_id, channel, status, amount_fen, currency, idempotency_key, provider_trade_no, provider_prepay_id, provider_payload, failure_code, failure_message, paid_at, closed_at, created_at, updated_at
+SELECT id, order_id, payer_user_id, channel, status, amount_fen, currency, idempotency_key, provider_trade_no, provider_prepay_id, provider_payload, failure_code, failure_message, paid_at, closed_at, created_at, updated_at, refunded_fen, out_trade_no
 FROM payments
 WHERE id = $1
 `
@@ -300,12 +315,14 @@ func (q *Queries) GetPayment(ctx context.Context, id uuid.UUID) (Payment, error)
 		&i.ClosedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RefundedFen,
+		&i.OutTradeNo,
 	)
 	return i, err
 }
 
 const getPaymentByIdempotencyKey = `-- name: GetPaymentByIdempotencyKey :one
-SELECT id, order_id, payer_user_id, channel, status, amount_fen, currency, idempotency_key, provider_trade_no, provider_prepay_id, provider_payload, failure_code, failure_message, paid_at, closed_at, created_at, updated_at
+SELECT id, order_id, payer_user_id, channel, status, amount_fen, currency, idempotency_key, provider_trade_no, provider_prepay_id, provider_payload, failure_code, failure_message, paid_at, closed_at, created_at, updated_at, refunded_fen, out_trade_no
 FROM payments
 WHERE order_id = $1
   AND channel = $2
@@ -339,12 +356,14 @@ func (q *Queries) GetPaymentByIdempotencyKey(ctx context.Context, arg GetPayment
 		&i.ClosedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RefundedFen,
+		&i.OutTradeNo,
 	)
 	return i, err
 }
 
 const getPaymentWebhook = `-- name: GetPaymentWebhook :one
-SELECT id, payment_id, provider, event_type, delivery_status, raw_body, replay_count, processed_at, created_at, updated_at
+SELECT id, payment_id, provider, event_type, delivery_status, raw_body, replay_count, processed_at, created_at, updated_at, rejection_reason
 FROM payment_webhooks
 WHERE id = $1
 `
@@ -363,10 +382,66 @@ func (q *Queries) GetPaymentWebhook(ctx context.Context, id uuid.UUID) (PaymentW
 		&i.ProcessedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RejectionReason,
 	)
 	return i, err
 }
 
+const listPaidPaymentsByChannel = `-- name: ListPaidPaymentsByChannel :many
+SELECT id, order_id, payer_user_id, channel, status, amount_fen, currency, idempotency_key, provider_trade_no, provider_prepay_id, provider_payload, failure_code, failure_message, paid_at, closed_at, created_at, updated_at, refunded_fen, out_trade_no
+FROM payments
+WHERE channel = $1
+  AND paid_at >= $2
+  AND paid_at < $3
+ORDER BY paid_at
+`
+
+type ListPaidPaymentsByChannelParams struct {
+	Channel  string             `db:"channel" json:"channel"`
+	PaidFrom pgtype.Timestamptz `db:"paid_from" json:"paid_from"`
+	PaidTo   pgtype.Timestamptz `db:"paid_to" json:"paid_to"`
+}
+
+func (q *Queries) ListPaidPaymentsByChannel(ctx context.Context, arg ListPaidPaymentsByChannelParams) ([]Payment, error) {
+	rows, err := q.db.Query(ctx, listPaidPaymentsByChannel, arg.Channel, arg.PaidFrom, arg.PaidTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Payment
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.PayerUserID,
+			&i.Channel,
+			&i.Status,
+			&i.AmountFen,
+			&i.Currency,
+			&i.IdempotencyKey,
+			&i.ProviderTradeNo,
+			&i.ProviderPrepayID,
+			&i.ProviderPayload,
+			&i.FailureCode,
+			&i.FailureMessage,
+			&i.PaidAt,
+			&i.ClosedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RefundedFen,
+			&i.OutTradeNo,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listPaymentAuditLogs = `-- name: ListPaymentAuditLogs :many
 SELECT id, payment_id, action, actor, detail, created_at
 FROM payment_audit_logs
@@ -420,7 +495,7 @@ func (q *Queries) ListPaymentAuditLogs(ctx context.Context, arg ListPaymentAudit
 }
 
 const listPaymentWebhooks = `-- name: ListPaymentWebhooks :many
-SELECT id, payment_id, provider, event_type, delivery_status, raw_body, replay_count, processed_at, created_at, updated_at
+SELECT id, payment_id, provider, event_type, delivery_status, raw_body, replay_count, processed_at, created_at, updated_at, rejection_reason
 FROM payment_webhooks
 WHERE (
     $1::text IS NULL
@@ -465,6 +540,7 @@ func (q *Queries) ListPaymentWebhooks(ctx context.Context, arg ListPaymentWebhoo
 			&i.ProcessedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.RejectionReason,
 		); err != nil {
 			return nil, err
 		}
@@ -477,7 +553,7 @@ func (q *Queries) ListPaymentWebhooks(ctx context.Context, arg ListPaymentWebhoo
 }
 
 const listPayments = `-- name: ListPayments :many
-SELECT id, order_id, payer_user_id, channel, status, amount_fen, currency, idempotency_key, provider_trade_no, provider_prepay_id, provider_payload, failure_code, failure_message, paid_at, closed_at, created_at, updated_at
+SELECT id, order_id, payer_user_id, channel, status, amount_fen, currency, idempotency_key, provider_trade_no, provider_prepay_id, provider_payload, failure_code, failure_message, paid_at, closed_at, created_at, updated_at, refunded_fen, out_trade_no
 FROM payments
 WHERE (
     $1::text IS NULL
@@ -533,6 +609,169 @@ func (q *Queries) ListPayments(ctx context.Context, arg ListPaymentsParams) ([]P
 			&i.ClosedAt,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.RefundedFen,
+			&i.OutTradeNo,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPaymentsByOrder = `-- name: ListPaymentsByOrder :many
+SELECT id, order_id, payer_user_id, channel, status, amount_fen, currency, idempotency_key, provider_trade_no, provider_prepay_id, provider_payload, failure_code, failure_message, paid_at, closed_at, created_at, updated_at, refunded_fen, out_trade_no
+FROM payments
+WHERE order_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListPaymentsByOrder(ctx context.Context, orderID uuid.UUID) ([]Payment, error) {
+	rows, err := q.db.Query(ctx, listPaymentsByOrder, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Payment{}
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.PayerUserID,
+			&i.Channel,
+			&i.Status,
+			&i.AmountFen,
+			&i.Currency,
+			&i.IdempotencyKey,
+			&i.ProviderTradeNo,
+			&i.ProviderPrepayID,
+			&i.ProviderPayload,
+			&i.FailureCode,
+			&i.FailureMessage,
+			&i.PaidAt,
+			&i.ClosedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RefundedFen,
+			&i.OutTradeNo,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPaymentsByOutTradeNo = `-- name: ListPaymentsByOutTradeNo :many
+SELECT id, order_id, payer_user_id, channel, status, amount_fen, currency, idempotency_key, provider_trade_no, provider_prepay_id, provider_payload, failure_code, failure_message, paid_at, closed_at, created_at, updated_at, refunded_fen, out_trade_no
+FROM payments
+WHERE out_trade_no = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListPaymentsByOutTradeNo(ctx context.Context, outTradeNo *string) ([]Payment, error) {
+	rows, err := q.db.Query(ctx, listPaymentsByOutTradeNo, outTradeNo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Payment{}
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.PayerUserID,
+			&i.Channel,
+			&i.Status,
+			&i.AmountFen,
+			&i.Currency,
+			&i.IdempotencyKey,
+			&i.ProviderTradeNo,
+			&i.ProviderPrepayID,
+			&i.ProviderPayload,
+			&i.FailureCode,
+			&i.FailureMessage,
+			&i.PaidAt,
+			&i.ClosedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RefundedFen,
+			&i.OutTradeNo,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingPaymentsCreatedBefore = `-- name: ListPendingPaymentsCreatedBefore :many
+SELECT id, order_id, payer_user_id, channel, status, amount_fen, currency, idempotency_key, provider_trade_no, provider_prepay_id, provider_payload, failure_code, failure_message, paid_at, closed_at, created_at, updated_at, refunded_fen, out_trade_no
+FROM payments
+WHERE status = $1
+  AND created_at < $2
+  AND (
+    $3::timestamptz IS NULL
+    OR (created_at, id) > ($3, $4::uuid)
+  )
+ORDER BY created_at, id
+LIMIT $5
+`
+
+type ListPendingPaymentsCreatedBeforeParams struct {
+	Status        string             `db:"status" json:"status"`
+	CreatedBefore pgtype.Timestamptz `db:"created_before" json:"created_before"`
+	CreatedAfter  pgtype.Timestamptz `db:"created_after" json:"created_after"`
+	AfterID       uuid.UUID          `db:"after_id" json:"after_id"`
+	Limit         int32              `db:"limit" json:"limit"`
+}
+
+func (q *Queries) ListPendingPaymentsCreatedBefore(ctx context.Context, arg ListPendingPaymentsCreatedBeforeParams) ([]Payment, error) {
+	rows, err := q.db.Query(ctx, listPendingPaymentsCreatedBefore,
+		arg.Status,
+		arg.CreatedBefore,
+		arg.CreatedAfter,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Payment
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrderID,
+			&i.PayerUserID,
+			&i.Channel,
+			&i.Status,
+			&i.AmountFen,
+			&i.Currency,
+			&i.IdempotencyKey,
+			&i.ProviderTradeNo,
+			&i.ProviderPrepayID,
+			&i.ProviderPayload,
+			&i.FailureCode,
+			&i.FailureMessage,
+			&i.PaidAt,
+			&i.ClosedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RefundedFen,
+			&i.OutTradeNo,
 		); err != nil {
 			return nil, err
 		}
@@ -551,7 +790,7 @@ SET replay_count = replay_count + 1,
     processed_at = $3,
     updated_at = now()
 WHERE id = $1
-RETURNING id, payment_id, provider, event_type, delivery_status, raw_body, replay_count, processed_at, created_at, updated_at
+RETURNING id, payment_id, provider, event_type, delivery_status, raw_body, replay_count, processed_at, created_at, updated_at, rejection_reason
 `
 
 type ReplayPaymentWebhookParams struct {
@@ -574,6 +813,7 @@ func (q *Queries) ReplayPaymentWebhook(ctx context.Context, arg ReplayPaymentWeb
 		&i.ProcessedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RejectionReason,
 	)
 	return i, err
 }
@@ -590,7 +830,7 @@ SET status = $2,
     closed_at = $9,
     updated_at = now()
 WHERE id = $1
-RETURNING id, order_id, payer_user_id, channel, status, amount_fen, currency, idempotency_key, provider_trade_no, provider_prepay_id, provider_payload, failure_code, failure_message, paid_at, closed_at, created_at, updated_at
+RETURNING id, order_id, payer_user_id, channel, status, amount_fen, currency, idempotency_key, provider_trade_no, provider_prepay_id, provider_payload, failure_code, failure_message, paid_at, closed_at, created_at, updated_at, refunded_fen, out_trade_no
 `
 
 type UpdatePaymentStateParams struct {
@@ -636,6 +876,8 @@ func (q *Queries) UpdatePaymentState(ctx context.Context, arg UpdatePaymentState
 		&i.ClosedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.RefundedFen,
+		&i.OutTradeNo,
 	)
 	return i, err
 }
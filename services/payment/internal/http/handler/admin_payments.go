@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
 	apierrors "github.com/teamdsb/tmo/packages/go-shared/errors"
 	"github.com/teamdsb/tmo/services/payment/internal/db"
 )
@@ -21,6 +22,7 @@ type adminPaymentTransaction struct {
 	Channel       string `json:"channel"`
 	Status        string `json:"status"`
 	AmountFen     int64  `json:"amountFen"`
+	RefundedFen   int64  `json:"refundedFen"`
 	Currency      string `json:"currency"`
 	CreatedAt     string `json:"createdAt"`
 	UpdatedAt     string `json:"updatedAt"`
@@ -45,6 +47,7 @@ type adminPaymentWebhook struct {
 	ReplayCount int    `json:"replayCount"`
 	ReceivedAt  string `json:"receivedAt"`
 	LastReplay  string `json:"lastReplayAt,omitempty"`
+	Rejection   string `json:"rejectionReason,omitempty"`
 }
 
 type pagedAdminPaymentTransactions struct {
@@ -76,7 +79,7 @@ type replayWebhookResponse struct {
 }
 
 func (h *Handler) GetAdminPaymentsTransactions(c *gin.Context) {
-	if _, ok := h.requireUser(c); !ok {
+	if _, _, ok := h.requirePermission(c, authz.PermissionPaymentRead, authz.ScopeAll); !ok {
 		return
 	}
 	if h.Store == nil {
@@ -137,7 +140,7 @@ func (h *Handler) GetAdminPaymentsTransactions(c *gin.Context) {
 }
 
 func (h *Handler) GetAdminPaymentsTransactionsId(c *gin.Context) {
-	if _, ok := h.requireUser(c); !ok {
+	if _, _, ok := h.requirePermission(c, authz.PermissionPaymentRead, authz.ScopeAll); !ok {
 		return
 	}
 	if h.Store == nil {
@@ -170,7 +173,7 @@ func (h *Handler) GetAdminPaymentsTransactionsId(c *gin.Context) {
 }
 
 func (h *Handler) GetAdminPaymentsAuditLogs(c *gin.Context) {
-	if _, ok := h.requireUser(c); !ok {
+	if _, _, ok := h.requirePermission(c, authz.PermissionPaymentRead, authz.ScopeAll); !ok {
 		return
 	}
 	if h.Store == nil {
@@ -228,7 +231,7 @@ func (h *Handler) GetAdminPaymentsAuditLogs(c *gin.Context) {
 }
 
 func (h *Handler) GetAdminPaymentsWebhooks(c *gin.Context) {
-	if _, ok := h.requireUser(c); !ok {
+	if _, _, ok := h.requirePermission(c, authz.PermissionPaymentRead, authz.ScopeAll); !ok {
 		return
 	}
 	if h.Store == nil {
@@ -286,7 +289,7 @@ func (h *Handler) GetAdminPaymentsWebhooks(c *gin.Context) {
 }
 
 func (h *Handler) PostAdminPaymentsWebhooksIdReplay(c *gin.Context) {
-	if _, ok := h.requireUser(c); !ok {
+	if _, _, ok := h.requirePermission(c, authz.PermissionPaymentManage, authz.ScopeAll); !ok {
 		return
 	}
 	if h.Store == nil {
@@ -315,6 +318,14 @@ func (h *Handler) PostAdminPaymentsWebhooksIdReplay(c *gin.Context) {
 		return
 	}
 
+	if webhook.DeliveryStatus == webhookStatusRejected {
+		apierrors.Write(c, http.StatusConflict, apierrors.APIError{
+			Code:    "conflict",
+			Message: "rejected webhooks cannot be replayed",
+		})
+		return
+	}
+
 	now := time.Now().UTC()
 	webhook, err = h.Store.ReplayPaymentWebhook(c.Request.Context(), db.ReplayPaymentWebhookParams{
 		ID:             webhookID,
@@ -332,7 +343,11 @@ func (h *Handler) PostAdminPaymentsWebhooksIdReplay(c *gin.Context) {
 
 	if webhook.PaymentID.Valid {
 		payment, err := h.Store.GetPayment(c.Request.Context(), webhook.PaymentID.Bytes)
-		if err == nil && h.Commerce != nil {
+		if err == nil && h.Outbox != nil && h.DB != nil {
+			if err := h.republishPaymentStatus(c.Request.Context(), payment); err != nil {
+				h.logError("replay webhook publish payment status failed", err)
+			}
+		} else if err == nil && h.Commerce != nil {
 			var paidAt *time.Time
 			if payment.PaidAt.Valid {
 				value := payment.PaidAt.Time
@@ -370,6 +385,7 @@ func adminTransactionFromModel(item db.Payment) adminPaymentTransaction {
 		Channel:       strings.ToLower(item.Channel),
 		Status:        strings.ToLower(item.Status),
 		AmountFen:     item.AmountFen,
+		RefundedFen:   item.RefundedFen,
 		Currency:      item.Currency,
 		CreatedAt:     item.CreatedAt.Time.Format(time.RFC3339),
 		UpdatedAt:     item.UpdatedAt.Time.Format(time.RFC3339),
@@ -398,6 +414,7 @@ func adminWebhookFromModel(item db.PaymentWebhook) adminPaymentWebhook {
 		ReplayCount: int(item.ReplayCount),
 		ReceivedAt:  item.CreatedAt.Time.Format(time.RFC3339),
 		LastReplay:  timestampString(item.ProcessedAt),
+		Rejection:   nullableString(item.RejectionReason),
 	}
 }
 
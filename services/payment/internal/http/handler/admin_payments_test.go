@@ -2,6 +2,8 @@ package handler
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -10,10 +12,13 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 
+	"github.com/teamdsb/tmo/packages/go-shared/jwks"
 	"github.com/teamdsb/tmo/services/payment/internal/db"
+	"github.com/teamdsb/tmo/services/payment/internal/http/middleware"
 )
 
 func TestAdminPaymentsTransactionsList(t *testing.T) {
@@ -70,6 +75,58 @@ func TestAdminPaymentsWebhookReplay(t *testing.T) {
 	}
 }
 
+func TestAdminPaymentsRequirePermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	key, err := jwks.NewKey(public)
+	if err != nil {
+		t.Fatalf("key: %v", err)
+	}
+
+	store := newAdminPaymentStoreStub()
+	h := &Handler{Store: store, Auth: middleware.NewAuthenticator(true, jwks.NewStatic(key), "")}
+	router.GET("/admin/payments/transactions", h.GetAdminPaymentsTransactions)
+	router.POST("/admin/payments/webhooks/:id/replay", h.PostAdminPaymentsWebhooksIdReplay)
+
+	request := func(method, path, role string) int {
+		token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.MapClaims{"sub": uuid.NewString(), "role": role})
+		token.Header["kid"] = key.ID
+		signed, err := token.SignedString(private)
+		if err != nil {
+			t.Fatalf("sign token: %v", err)
+		}
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+signed)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	replayPath := "/admin/payments/webhooks/" + store.webhook.ID.String() + "/replay"
+	cases := []struct {
+		method string
+		path   string
+		role   string
+		want   int
+	}{
+		{http.MethodGet, "/admin/payments/transactions", "CUSTOMER", http.StatusForbidden},
+		{http.MethodGet, "/admin/payments/transactions", "SALES", http.StatusForbidden},
+		{http.MethodGet, "/admin/payments/transactions", "CS", http.StatusOK},
+		{http.MethodPost, replayPath, "CS", http.StatusForbidden},
+		{http.MethodPost, replayPath, "MANAGER", http.StatusOK},
+	}
+	for _, tc := range cases {
+		if got := request(tc.method, tc.path, tc.role); got != tc.want {
+			t.Fatalf("%s %s as %s: expected %d, got %d", tc.method, tc.path, tc.role, tc.want, got)
+		}
+	}
+}
+
 type adminPaymentStoreStub struct {
 	payment db.Payment
 	webhook db.PaymentWebhook
@@ -130,6 +187,14 @@ func (s *adminPaymentStoreStub) ListPayments(context.Context, db.ListPaymentsPar
 	return []db.Payment{s.payment}, nil
 }
 
+func (s *adminPaymentStoreStub) ListPaymentsByOrder(context.Context, uuid.UUID) ([]db.Payment, error) {
+	return []db.Payment{s.payment}, nil
+}
+
+func (s *adminPaymentStoreStub) ListPaymentsByOutTradeNo(context.Context, *string) ([]db.Payment, error) {
+	return []db.Payment{s.payment}, nil
+}
+
 func (s *adminPaymentStoreStub) CountPayments(context.Context, db.CountPaymentsParams) (int64, error) {
 	return 1, nil
 }
@@ -174,3 +239,63 @@ func (s *adminPaymentStoreStub) ListPaymentAuditLogs(context.Context, db.ListPay
 func (s *adminPaymentStoreStub) CountPaymentAuditLogs(context.Context, db.CountPaymentAuditLogsParams) (int64, error) {
 	return 0, nil
 }
+
+func (s *adminPaymentStoreStub) CreateRefund(context.Context, db.CreateRefundParams) (db.Refund, error) {
+	return db.Refund{}, errors.New("not implemented")
+}
+
+func (s *adminPaymentStoreStub) GetRefund(context.Context, uuid.UUID) (db.Refund, error) {
+	return db.Refund{}, errors.New("not found")
+}
+
+func (s *adminPaymentStoreStub) GetRefundByIdempotencyKey(context.Context, db.GetRefundByIdempotencyKeyParams) (db.Refund, error) {
+	return db.Refund{}, errors.New("not found")
+}
+
+func (s *adminPaymentStoreStub) ListRefundsByPayment(context.Context, uuid.UUID) ([]db.Refund, error) {
+	return nil, nil
+}
+
+func (s *adminPaymentStoreStub) UpdateRefundState(context.Context, db.UpdateRefundStateParams) (db.Refund, error) {
+	return db.Refund{}, errors.New("not implemented")
+}
+
+func (s *adminPaymentStoreStub) ReleaseRefundReservation(context.Context, db.ReleaseRefundReservationParams) (db.Payment, error) {
+	return db.Payment{}, errors.New("not implemented")
+}
+
+func (s *adminPaymentStoreStub) SumSucceededRefunds(context.Context, uuid.UUID) (int64, error) {
+	return 0, nil
+}
+
+func (s *adminPaymentStoreStub) ListPendingPaymentsCreatedBefore(context.Context, db.ListPendingPaymentsCreatedBeforeParams) ([]db.Payment, error) {
+	return nil, nil
+}
+
+func (s *adminPaymentStoreStub) ListPaidPaymentsByChannel(context.Context, db.ListPaidPaymentsByChannelParams) ([]db.Payment, error) {
+	return nil, nil
+}
+
+func (s *adminPaymentStoreStub) CreateReconciliationRun(context.Context, db.CreateReconciliationRunParams) (db.ReconciliationRun, error) {
+	return db.ReconciliationRun{}, errors.New("not implemented")
+}
+
+func (s *adminPaymentStoreStub) CreateReconciliationDiscrepancy(context.Context, db.CreateReconciliationDiscrepancyParams) (db.ReconciliationDiscrepancy, error) {
+	return db.ReconciliationDiscrepancy{}, errors.New("not implemented")
+}
+
+func (s *adminPaymentStoreStub) GetReconciliationRun(context.Context, uuid.UUID) (db.ReconciliationRun, error) {
+	return db.ReconciliationRun{}, errors.New("not found")
+}
+
+func (s *adminPaymentStoreStub) ListReconciliationRuns(context.Context, db.ListReconciliationRunsParams) ([]db.ReconciliationRun, error) {
+	return nil, nil
+}
+
+func (s *adminPaymentStoreStub) CountReconciliationRuns(context.Context, *string) (int64, error) {
+	return 0, nil
+}
+
+func (s *adminPaymentStoreStub) ListReconciliationDiscrepancies(context.Context, uuid.UUID) ([]db.ReconciliationDiscrepancy, error) {
+	return nil, nil
+}
@@ -0,0 +1,281 @@
+package handler
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultAlipayGatewayURL = "https://openapi.alipay.com/gateway.do"
+
+// AlipayConfig contains the application credentials used to sign Alipay
+// OpenAPI requests. Keep the private key in deployment secrets. The Alipay
+// public key verifies notifies; without it every notify is rejected.
+type AlipayConfig struct {
+	AppID, PrivateKeyPEM, GatewayURL string
+	AlipayPublicKeyPEM               string
+}
+
+// AlipayProvider talks to the Alipay OpenAPI gateway. Miniapp trades need the
+// buyer id, which the payment service does not hold, so this provider covers
+// query, close and refund.
+type AlipayProvider struct {
+	config     AlipayConfig
+	privateKey *rsa.PrivateKey
+	publicKey  *rsa.PublicKey
+	client     *http.Client
+}
+
+type alipayResponse struct {
+	Code        string `json:"code"`
+	Msg         string `json:"msg"`
+	SubCode     string `json:"sub_code"`
+	SubMsg      string `json:"sub_msg"`
+	TradeNo     string `json:"trade_no"`
+	TradeStatus string `json:"trade_status"`
+}
+
+func NewAlipayProvider(config AlipayConfig) (*AlipayProvider, error) {
+	if strings.TrimSpace(config.AppID) == "" || strings.TrimSpace(config.PrivateKeyPEM) == "" {
+		return nil, fmt.Errorf("alipay credentials are incomplete")
+	}
+	privateKey, err := parseRSAPrivateKey(config.PrivateKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parse alipay private key: %w", err)
+	}
+	if strings.TrimSpace(config.GatewayURL) == "" {
+		config.GatewayURL = defaultAlipayGatewayURL
+	}
+	provider := &AlipayProvider{config: config, privateKey: privateKey, client: &http.Client{Timeout: 10 * time.Second}}
+	if strings.TrimSpace(config.AlipayPublicKeyPEM) != "" {
+		publicKey, err := parseRSAPublicKey(config.AlipayPublicKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("parse alipay public key: %w", err)
+		}
+		provider.publicKey = publicKey
+	}
+	return provider, nil
+}
+
+func (p *AlipayProvider) CreateSession(context.Context, SessionRequest) (Session, error) {
+	return Session{}, fmt.Errorf("%w: alipay miniapp trades", ErrProviderUnsupported)
+}
+
+func (p *AlipayProvider) Query(ctx context.Context, payment ProviderPayment) (QueryResult, error) {
+	response, err := p.call(ctx, "alipay.trade.query", alipayTradeReference(payment.OrderID.String(), payment.ProviderTradeNo))
+	if err != nil {
+		return QueryResult{}, err
+	}
+	switch {
+	case response.Code == "10000":
+	case response.SubCode == "ACQ.TRADE_NOT_EXIST":
+		// The buyer has not opened the cashier yet.
+		return QueryResult{Status: paymentStatusPending}, nil
+	default:
+		return QueryResult{}, fmt.Errorf("alipay query failed: %s: %s", response.SubCode, response.SubMsg)
+	}
+	result := QueryResult{Status: normalizeAlipayTradeStatus(response.TradeStatus)}
+	if response.TradeNo != "" {
+		result.ProviderTradeNo = &response.TradeNo
+	}
+	return result, nil
+}
+
+func (p *AlipayProvider) Close(ctx context.Context, payment ProviderPayment) error {
+	response, err := p.call(ctx, "alipay.trade.close", alipayTradeReference(payment.OrderID.String(), payment.ProviderTradeNo))
+	if err != nil {
+		return err
+	}
+	if response.Code != "10000" && response.SubCode != "ACQ.TRADE_NOT_EXIST" {
+		return fmt.Errorf("alipay close failed: %s: %s", response.SubCode, response.SubMsg)
+	}
+	return nil
+}
+
+// Refund calls alipay.trade.refund. Alipay settles refunds synchronously, so
+// the result is final unless the request itself fails.
+func (p *AlipayProvider) Refund(ctx context.Context, request RefundRequest) (RefundResult, error) {
+	bizContent := alipayTradeReference(request.OrderID.String(), request.ProviderTradeNo)
+	bizContent["refund_amount"] = formatFenAsYuan(request.AmountFen)
+	bizContent["out_request_no"] = request.RefundID.String()
+	if strings.TrimSpace(request.Reason) != "" {
+		bizContent["refund_reason"] = request.Reason
+	}
+	response, err := p.call(ctx, "alipay.trade.refund", bizContent)
+	if err != nil {
+		return RefundResult{}, err
+	}
+	if response.Code != "10000" {
+		message := fmt.Sprintf("%s: %s", response.SubCode, response.SubMsg)
+		return RefundResult{Status: refundStatusFailed, FailureMessage: &message}, nil
+	}
+	result := RefundResult{Status: refundStatusSucceeded}
+	if response.TradeNo != "" {
+		result.ProviderRefundNo = &response.TradeNo
+	}
+	return result, nil
+}
+
+// VerifyNotify checks the RSA2 signature of a form-encoded async notify over
+// every parameter except sign and sign_type, sorted by name. The returned
+// payload holds the notify parameters plus the order, status, trade number
+// and amount under the keys handleNotify reads.
+func (p *AlipayProvider) VerifyNotify(_ context.Context, request NotifyRequest) (map[string]interface{}, error) {
+	if p.publicKey == nil {
+		return nil, errors.New("alipay notify verification is not configured")
+	}
+	params, err := url.ParseQuery(string(request.Body))
+	if err != nil {
+		return nil, errors.New("invalid alipay notify body")
+	}
+	if signType := params.Get("sign_type"); signType != "" && !strings.EqualFold(signType, "RSA2") {
+		return nil, fmt.Errorf("unsupported alipay sign type: %s", signType)
+	}
+	sign := params.Get("sign")
+	if sign == "" {
+		return nil, errors.New("alipay notify signature is required")
+	}
+	unsigned := url.Values{}
+	for key, values := range params {
+		if key != "sign_type" {
+			unsigned[key] = values
+		}
+	}
+	if err := verifySHA256WithRSA(buildAlipaySignContent(unsigned), sign, p.publicKey); err != nil {
+		return nil, fmt.Errorf("alipay notify: %w", err)
+	}
+	if appID := params.Get("app_id"); appID != p.config.AppID {
+		return nil, fmt.Errorf("alipay notify is for app %s", appID)
+	}
+
+	payload := make(map[string]interface{}, len(params)+5)
+	for key := range params {
+		payload[key] = params.Get(key)
+	}
+	payload["orderId"] = params.Get("out_trade_no")
+	payload["status"] = normalizeAlipayTradeStatus(params.Get("trade_status"))
+	payload["providerTradeNo"] = params.Get("trade_no")
+	payload["eventType"] = params.Get("notify_type")
+	if amountFen, err := parseYuanAsFen(params.Get("total_amount")); err == nil {
+		payload["amountFen"] = amountFen
+	}
+	return payload, nil
+}
+
+// call signs and sends one OpenAPI request and returns its response node.
+func (p *AlipayProvider) call(ctx context.Context, method string, bizContent map[string]interface{}) (alipayResponse, error) {
+	rawBizContent, err := json.Marshal(bizContent)
+	if err != nil {
+		return alipayResponse{}, err
+	}
+	params := url.Values{}
+	params.Set("app_id", p.config.AppID)
+	params.Set("method", method)
+	params.Set("format", "JSON")
+	params.Set("charset", "utf-8")
+	params.Set("sign_type", "RSA2")
+	params.Set("timestamp", time.Now().Format("2006-01-02 15:04:05"))
+	params.Set("version", "1.0")
+	params.Set("biz_content", string(rawBizContent))
+	signature, err := signSHA256WithRSA(buildAlipaySignContent(params), p.privateKey)
+	if err != nil {
+		return alipayResponse{}, fmt.Errorf("sign alipay request: %w", err)
+	}
+	params.Set("sign", signature)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.GatewayURL, strings.NewReader(params.Encode()))
+	if err != nil {
+		return alipayResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return alipayResponse{}, fmt.Errorf("call %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return alipayResponse{}, fmt.Errorf("%s failed with status %d", method, resp.StatusCode)
+	}
+	var payload map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return alipayResponse{}, fmt.Errorf("decode %s response: %w", method, err)
+	}
+	var response alipayResponse
+	if err := json.Unmarshal(payload[strings.ReplaceAll(method, ".", "_")+"_response"], &response); err != nil {
+		return alipayResponse{}, fmt.Errorf("decode %s response: %w", method, err)
+	}
+	return response, nil
+}
+
+// alipayTradeReference identifies a trade by the Alipay trade number when it
+// is known and by the merchant order number otherwise.
+func alipayTradeReference(outTradeNo string, tradeNo *string) map[string]interface{} {
+	if tradeNo != nil && strings.TrimSpace(*tradeNo) != "" {
+		return map[string]interface{}{"trade_no": strings.TrimSpace(*tradeNo)}
+	}
+	return map[string]interface{}{"out_trade_no": outTradeNo}
+}
+
+// normalizeAlipayTradeStatus maps Alipay trade statuses onto payment statuses.
+func normalizeAlipayTradeStatus(status string) string {
+	switch strings.ToUpper(strings.TrimSpace(status)) {
+	case "TRADE_SUCCESS", "TRADE_FINISHED":
+		return paymentStatusPaid
+	case "TRADE_CLOSED":
+		return paymentStatusCancelled
+	default:
+		return paymentStatusPending
+	}
+}
+
+func buildAlipaySignContent(params url.Values) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		if key == "sign" || params.Get(key) == "" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+params.Get(key))
+	}
+	return strings.Join(pairs, "&")
+}
+
+func formatFenAsYuan(amountFen int64) string {
+	return fmt.Sprintf("%d.%02d", amountFen/100, amountFen%100)
+}
+
+// parseYuanAsFen parses a yuan amount with at most two decimals without going
+// through floating point.
+func parseYuanAsFen(value string) (int64, error) {
+	yuan, cents, found := strings.Cut(strings.TrimSpace(value), ".")
+	if !found {
+		cents = "00"
+	}
+	if yuan == "" || len(cents) == 0 || len(cents) > 2 {
+		return 0, fmt.Errorf("invalid yuan amount %q", value)
+	}
+	if len(cents) == 1 {
+		cents += "0"
+	}
+	whole, err := strconv.ParseInt(yuan, 10, 64)
+	if err != nil || whole < 0 {
+		return 0, fmt.Errorf("invalid yuan amount %q", value)
+	}
+	fraction, err := strconv.ParseInt(cents, 10, 64)
+	if err != nil || fraction < 0 {
+		return 0, fmt.Errorf("invalid yuan amount %q", value)
+	}
+	return whole*100 + fraction, nil
+}
@@ -18,9 +18,14 @@ type CommerceClient struct {
 
 type CommerceOrder struct {
 	ID            string              `json:"id"`
+	OrderNo       string              `json:"orderNo"`
 	Status        string              `json:"status"`
 	PaymentStatus string              `json:"paymentStatus"`
 	Items         []CommerceOrderItem `json:"items"`
+	// TotalFen is what the customer pays after discounts and including
+	// shipping. Orders from commerce versions that predate it only carry
+	// Items.
+	TotalFen *int64 `json:"totalFen,omitempty"`
 }
 
 type CommerceOrderItem struct {
@@ -3,37 +3,28 @@ package handler
 import (
 	"context"
 	"log/slog"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+	"github.com/teamdsb/tmo/packages/go-shared/events"
 	"github.com/teamdsb/tmo/services/payment/internal/db"
 	"github.com/teamdsb/tmo/services/payment/internal/http/middleware"
 )
 
 type Handler struct {
-	Logger            *slog.Logger
-	Auth              *middleware.Authenticator
-	Flags             FeatureFlagsProvider
-	Store             PaymentStore
-	Commerce          *CommerceClient
-	ProviderMode      string
-	WechatB2BProvider WechatB2BProvider
-}
-
-// WechatB2BProvider creates the signed payload required by the WeChat B2B
-// Store Assistant API. Its implementation belongs to the merchant-specific
-// integration because the protocol credentials must never reach a miniapp.
-type WechatB2BProvider interface {
-	CreateCommonPayParams(ctx context.Context, request WechatB2BPaymentRequest) (map[string]interface{}, error)
-}
-
-type WechatB2BPaymentRequest struct {
-	OrderID   uuid.UUID
-	AmountFen int64
-	ExpiresAt time.Time
-	LoginCode string
+	Logger        *slog.Logger
+	Auth          *middleware.Authenticator
+	Flags         FeatureFlagsProvider
+	Store         PaymentStore
+	DB            *pgxpool.Pool
+	Outbox        *events.Outbox
+	Commerce      *CommerceClient
+	InternalToken string
+	ProviderMode  string
+	Providers     Providers
 }
 
 type PaymentStore interface {
@@ -42,6 +33,8 @@ type PaymentStore interface {
 	GetPaymentByIdempotencyKey(ctx context.Context, arg db.GetPaymentByIdempotencyKeyParams) (db.Payment, error)
 	UpdatePaymentState(ctx context.Context, arg db.UpdatePaymentStateParams) (db.Payment, error)
 	ListPayments(ctx context.Context, arg db.ListPaymentsParams) ([]db.Payment, error)
+	ListPaymentsByOrder(ctx context.Context, orderID uuid.UUID) ([]db.Payment, error)
+	ListPaymentsByOutTradeNo(ctx context.Context, outTradeNo *string) ([]db.Payment, error)
 	CountPayments(ctx context.Context, arg db.CountPaymentsParams) (int64, error)
 	CreatePaymentWebhook(ctx context.Context, arg db.CreatePaymentWebhookParams) (db.PaymentWebhook, error)
 	GetPaymentWebhook(ctx context.Context, id uuid.UUID) (db.PaymentWebhook, error)
@@ -51,6 +44,21 @@ type PaymentStore interface {
 	CreatePaymentAuditLog(ctx context.Context, arg db.CreatePaymentAuditLogParams) (db.PaymentAuditLog, error)
 	ListPaymentAuditLogs(ctx context.Context, arg db.ListPaymentAuditLogsParams) ([]db.PaymentAuditLog, error)
 	CountPaymentAuditLogs(ctx context.Context, arg db.CountPaymentAuditLogsParams) (int64, error)
+	CreateRefund(ctx context.Context, arg db.CreateRefundParams) (db.Refund, error)
+	GetRefund(ctx context.Context, id uuid.UUID) (db.Refund, error)
+	GetRefundByIdempotencyKey(ctx context.Context, arg db.GetRefundByIdempotencyKeyParams) (db.Refund, error)
+	ListRefundsByPayment(ctx context.Context, paymentID uuid.UUID) ([]db.Refund, error)
+	UpdateRefundState(ctx context.Context, arg db.UpdateRefundStateParams) (db.Refund, error)
+	ReleaseRefundReservation(ctx context.Context, arg db.ReleaseRefundReservationParams) (db.Payment, error)
+	SumSucceededRefunds(ctx context.Context, paymentID uuid.UUID) (int64, error)
+	ListPendingPaymentsCreatedBefore(ctx context.Context, arg db.ListPendingPaymentsCreatedBeforeParams) ([]db.Payment, error)
+	ListPaidPaymentsByChannel(ctx context.Context, arg db.ListPaidPaymentsByChannelParams) ([]db.Payment, error)
+	CreateReconciliationRun(ctx context.Context, arg db.CreateReconciliationRunParams) (db.ReconciliationRun, error)
+	CreateReconciliationDiscrepancy(ctx context.Context, arg db.CreateReconciliationDiscrepancyParams) (db.ReconciliationDiscrepancy, error)
+	GetReconciliationRun(ctx context.Context, id uuid.UUID) (db.ReconciliationRun, error)
+	ListReconciliationRuns(ctx context.Context, arg db.ListReconciliationRunsParams) ([]db.ReconciliationRun, error)
+	CountReconciliationRuns(ctx context.Context, channel *string) (int64, error)
+	ListReconciliationDiscrepancies(ctx context.Context, runID uuid.UUID) ([]db.ReconciliationDiscrepancy, error)
 }
 
 func (h *Handler) requireUser(c *gin.Context) (middleware.Claims, bool) {
@@ -60,6 +68,15 @@ func (h *Handler) requireUser(c *gin.Context) (middleware.Claims, bool) {
 	return h.Auth.RequireUser(c)
 }
 
+// requirePermission checks that the caller's role holds permission with at
+// least the required scope and returns the scope it holds.
+func (h *Handler) requirePermission(c *gin.Context, permission string, required authz.Scope) (middleware.Claims, authz.Scope, bool) {
+	if h.Auth == nil {
+		return middleware.Claims{Role: "ADMIN"}, authz.ScopeAll, true
+	}
+	return h.Auth.RequirePermission(c, permission, required)
+}
+
 func (h *Handler) logError(message string, err error) {
 	if h.Logger == nil || err == nil {
 		return
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	apierrors "github.com/teamdsb/tmo/packages/go-shared/errors"
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/services/payment/internal/db"
+)
+
+// orderEventsConsumer names payment's handling of order events in
+// processed_events.
+const orderEventsConsumer = "payment.order-close"
+
+// PostInternalEvents receives events pushed by the outbox relay of another
+// service. A 2xx response acknowledges the event; anything else makes the
+// relay retry it later.
+func (h *Handler) PostInternalEvents(c *gin.Context) {
+	if !h.authorizeInternal(c) {
+		apierrors.Write(c, http.StatusUnauthorized, apierrors.APIError{Code: "unauthorized", Message: "invalid internal token"})
+		return
+	}
+	var event events.Event
+	if err := c.ShouldBindJSON(&event); err != nil {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: "invalid request body"})
+		return
+	}
+	if err := event.Validate(); err != nil {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: err.Error()})
+		return
+	}
+
+	var err error
+	switch event.Type {
+	case events.TypeOrderClosed:
+		err = h.consumeOrderClosed(c.Request.Context(), event)
+	default:
+		// Unknown types are acknowledged so that a producer can add events
+		// before every subscriber handles them.
+		if h.Logger != nil {
+			h.Logger.Debug("ignoring event", "eventId", event.ID, "type", event.Type)
+		}
+	}
+	if err != nil {
+		h.logError("consume event failed", err)
+		apierrors.Write(c, http.StatusInternalServerError, apierrors.APIError{Code: "internal_error", Message: "failed to process event"})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// consumeOrderClosed closes the pending payments of a closed order. Commerce
+// already closes them before it closes the order; the event catches sessions
+// created in between.
+func (h *Handler) consumeOrderClosed(ctx context.Context, event events.Event) error {
+	if h.DB == nil {
+		return errors.New("db pool is nil")
+	}
+	var payload events.OrderClosed
+	if err := event.Decode(&payload); err != nil {
+		h.logEventSkipped(event, err.Error())
+		return nil
+	}
+	orderID, err := uuid.Parse(strings.TrimSpace(payload.OrderID))
+	if err != nil {
+		h.logEventSkipped(event, "invalid orderId")
+		return nil
+	}
+	reasonCode := strings.ToUpper(strings.TrimSpace(payload.ReasonCode))
+	if reasonCode == "" {
+		reasonCode = "ORDER_CLOSED"
+	}
+
+	err = shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		fresh, err := events.MarkProcessed(ctx, tx, orderEventsConsumer, event)
+		if err != nil || !fresh {
+			return err
+		}
+		_, err = h.closePendingPayments(ctx, db.New(tx), orderID, reasonCode)
+		return err
+	})
+	var httpErr paymentHTTPError
+	if errors.As(err, &httpErr) && httpErr.status == http.StatusConflict {
+		// The order was paid before it closed; that needs a refund, not a retry.
+		h.logEventSkipped(event, httpErr.message)
+		return nil
+	}
+	return err
+}
+
+func (h *Handler) logEventSkipped(event events.Event, reason string) {
+	if h.Logger != nil {
+		h.Logger.Warn("skipping event", "eventId", event.ID, "type", event.Type, "reason", reason)
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/services/payment/internal/db"
+)
+
+func TestPostInternalEventsAuthorizesAndAcknowledgesUnknownTypes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := newTestRouter(&Handler{Store: newPaymentStoreStub(), InternalToken: "sync-token"})
+
+	post := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/internal/events", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Internal-Token", token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	event, err := events.New("commerce", events.TypeShipmentAdded, "order-1", events.ShipmentAdded{OrderID: "order-1"})
+	if err != nil {
+		t.Fatalf("new event: %v", err)
+	}
+	body, _ := json.Marshal(event)
+
+	if rec := post("wrong", string(body)); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for invalid token, got %d", rec.Code)
+	}
+	if rec := post("sync-token", `{"type":"order.closed"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an event without id, got %d", rec.Code)
+	}
+	if rec := post("sync-token", string(body)); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected unknown event type to be acknowledged, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestPaymentStatusEvents(t *testing.T) {
+	cases := map[string]string{
+		paymentStatusPaid:              events.TypePaymentSucceeded,
+		paymentStatusFailed:            events.TypePaymentFailed,
+		paymentStatusCancelled:         events.TypePaymentCancelled,
+		paymentStatusPartiallyRefunded: events.TypePaymentRefunded,
+		paymentStatusRefunded:          events.TypePaymentRefunded,
+	}
+	for status, want := range cases {
+		if got, ok := paymentEventType(status); !ok || got != want {
+			t.Errorf("paymentEventType(%q) = %q, %v; want %q", status, got, ok, want)
+		}
+	}
+	if _, ok := paymentEventType(paymentStatusPending); ok {
+		t.Error("expected pending payments to publish no event")
+	}
+
+	paidAt := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	tradeNo := "T-1"
+	payment := db.Payment{ID: uuid.New(), OrderID: uuid.New(), Channel: paymentChannelWechat, Status: paymentStatusPaid, AmountFen: 1200, ProviderTradeNo: &tradeNo, PaidAt: pgtype.Timestamptz{Time: paidAt, Valid: true}}
+	payload := paymentStatusPayload(payment)
+	if payload.OrderID != payment.OrderID.String() || payload.AmountFen != 1200 || payload.PaidAt == nil || !payload.PaidAt.Equal(paidAt) || payload.ProviderTradeNo != &tradeNo {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+}
+
+func TestSavePaymentStateWithoutOutboxSyncsDirectly(t *testing.T) {
+	store := newPaymentStoreStub()
+	payment, _ := store.CreatePayment(t.Context(), db.CreatePaymentParams{OrderID: uuid.New(), Channel: paymentChannelWechat, Status: paymentStatusPending, AmountFen: 100, Currency: "CNY"})
+	handler := &Handler{Store: store}
+	updated, published, err := handler.savePaymentState(t.Context(), db.UpdatePaymentStateParams{ID: payment.ID, Status: paymentStatusPaid})
+	if err != nil {
+		t.Fatalf("save payment state: %v", err)
+	}
+	if published || updated.Status != paymentStatusPaid {
+		t.Fatalf("expected an unpublished update to PAID, got %v %s", published, updated.Status)
+	}
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	apierrors "github.com/teamdsb/tmo/packages/go-shared/errors"
+	"github.com/teamdsb/tmo/services/payment/internal/db"
+)
+
+const failureCodeOrderClosed = "ORDER_CLOSED"
+
+type internalClosePaymentsRequest struct {
+	ReasonCode string `json:"reasonCode"`
+}
+
+type internalClosePaymentsResponse struct {
+	OrderID          string   `json:"orderId"`
+	ClosedPaymentIDs []string `json:"closedPaymentIds"`
+}
+
+// PostInternalOrdersOrderIdPaymentsClose is called by commerce when an order is
+// cancelled or closed. Every pending payment session of the order is closed so
+// that a late provider callback can no longer mark it paid.
+func (h *Handler) PostInternalOrdersOrderIdPaymentsClose(c *gin.Context) {
+	if !h.authorizeInternal(c) {
+		apierrors.Write(c, http.StatusUnauthorized, apierrors.APIError{Code: "unauthorized", Message: "invalid internal token"})
+		return
+	}
+	orderID, err := uuid.Parse(strings.TrimSpace(c.Param("orderId")))
+	if err != nil {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: "invalid orderId"})
+		return
+	}
+	var request internalClosePaymentsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: "invalid request body"})
+		return
+	}
+	reasonCode := strings.ToUpper(strings.TrimSpace(request.ReasonCode))
+	if reasonCode == "" {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: "reasonCode is required"})
+		return
+	}
+
+	if h.Store == nil {
+		h.writePaymentError(c, errInternal("payment store is not configured"))
+		return
+	}
+	closed, err := h.closePendingPayments(c.Request.Context(), h.Store, orderID, reasonCode)
+	if err != nil {
+		h.writePaymentError(c, err)
+		return
+	}
+	ids := make([]string, 0, len(closed))
+	for _, payment := range closed {
+		ids = append(ids, payment.ID.String())
+	}
+	c.JSON(http.StatusOK, internalClosePaymentsResponse{OrderID: orderID.String(), ClosedPaymentIDs: ids})
+}
+
+// closePendingPayments cancels the order's pending payments through store, so
+// the event consumer can run it inside its transaction.
+func (h *Handler) closePendingPayments(ctx context.Context, store PaymentStore, orderID uuid.UUID, reasonCode string) ([]db.Payment, error) {
+	payments, err := store.ListPaymentsByOrder(ctx, orderID)
+	if err != nil {
+		return nil, errInternal("list order payments failed")
+	}
+	for _, payment := range payments {
+		if payment.Status == paymentStatusPaid || payment.Status == paymentStatusPartiallyRefunded {
+			return nil, errConflict("order has a paid payment")
+		}
+	}
+
+	closed := make([]db.Payment, 0)
+	for _, payment := range payments {
+		if payment.Status != paymentStatusPending {
+			continue
+		}
+		// The order is closed either way; a channel that cannot close the
+		// session lets it expire on its side.
+		if provider, ok := h.Providers.Lookup(payment.Channel); ok {
+			if err := provider.Close(ctx, providerPaymentFromModel(payment)); err != nil && !errors.Is(err, ErrProviderUnsupported) {
+				h.logError("close provider payment failed", err)
+			}
+		}
+		failureCode := failureCodeOrderClosed
+		failureMessage := reasonCode
+		updated, err := store.UpdatePaymentState(ctx, db.UpdatePaymentStateParams{
+			ID:               payment.ID,
+			Status:           paymentStatusCancelled,
+			ProviderTradeNo:  payment.ProviderTradeNo,
+			ProviderPrepayID: payment.ProviderPrepayID,
+			ProviderPayload:  payment.ProviderPayload,
+			FailureCode:      &failureCode,
+			FailureMessage:   &failureMessage,
+			PaidAt:           pgtype.Timestamptz{},
+			ClosedAt:         pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+		})
+		if err != nil {
+			return nil, errInternal("close payment failed")
+		}
+		if err := h.recordAudit(ctx, updated.ID, "closed", "commerce", fmt.Sprintf("order closed: %s", reasonCode)); err != nil {
+			h.logError("create payment audit log failed", err)
+		}
+		closed = append(closed, updated)
+	}
+	return closed, nil
+}
+
+func (h *Handler) authorizeInternal(c *gin.Context) bool {
+	expected := strings.TrimSpace(h.InternalToken)
+	if expected == "" {
+		return false
+	}
+	provided := strings.TrimSpace(c.GetHeader("X-Internal-Token"))
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(provided)) == 1
+}
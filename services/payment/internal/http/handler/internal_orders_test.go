@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/payment/internal/db"
+)
+
+func TestPostInternalOrdersPaymentsCloseClosesPendingPayments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	orderID := uuid.New()
+	store := newPaymentStoreStub()
+	pending, _ := store.CreatePayment(context.Background(), db.CreatePaymentParams{OrderID: orderID, Channel: paymentChannelWechat, Status: paymentStatusPending, AmountFen: 100, Currency: "CNY"})
+	failed, _ := store.CreatePayment(context.Background(), db.CreatePaymentParams{OrderID: orderID, Channel: paymentChannelAlipay, Status: paymentStatusFailed, AmountFen: 100, Currency: "CNY"})
+	router := newTestRouter(&Handler{Store: store, InternalToken: "sync-token"})
+
+	request := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/internal/orders/"+orderID.String()+"/payments/close", strings.NewReader(`{"reasonCode":"changed_mind"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Internal-Token", token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := request("wrong"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for invalid token, got %d", rec.Code)
+	}
+	rec := request("sync-token")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if !strings.Contains(rec.Body.String(), pending.ID.String()) || strings.Contains(rec.Body.String(), failed.ID.String()) {
+		t.Fatalf("expected only the pending payment to be closed: %s", rec.Body.String())
+	}
+	closed := store.payments[pending.ID]
+	if closed.Status != paymentStatusCancelled || !closed.ClosedAt.Valid || closed.FailureCode == nil || *closed.FailureCode != failureCodeOrderClosed || *closed.FailureMessage != "CHANGED_MIND" {
+		t.Fatalf("unexpected closed payment: %#v", closed)
+	}
+	if store.payments[failed.ID].Status != paymentStatusFailed {
+		t.Fatalf("expected failed payment to stay untouched")
+	}
+}
+
+func TestPostInternalOrdersPaymentsCloseRejectsPaidOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	orderID := uuid.New()
+	store := newPaymentStoreStub()
+	pending, _ := store.CreatePayment(context.Background(), db.CreatePaymentParams{OrderID: orderID, Channel: paymentChannelWechat, Status: paymentStatusPending, AmountFen: 100, Currency: "CNY"})
+	_, _ = store.CreatePayment(context.Background(), db.CreatePaymentParams{OrderID: orderID, Channel: paymentChannelAlipay, Status: paymentStatusPaid, AmountFen: 100, Currency: "CNY"})
+	router := newTestRouter(&Handler{Store: store, InternalToken: "sync-token"})
+
+	req := httptest.NewRequest(http.MethodPost, "/internal/orders/"+orderID.String()+"/payments/close", strings.NewReader(`{"reasonCode":"OTHER"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Internal-Token", "sync-token")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if store.payments[pending.ID].Status != paymentStatusPending {
+		t.Fatalf("expected pending payment to stay open when the order is already paid")
+	}
+}
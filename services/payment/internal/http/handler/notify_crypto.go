@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// verifySHA256WithRSA checks a base64 SHA256withRSA signature, the scheme used
+// by both WeChat Pay v3 and Alipay RSA2 notifies.
+func verifySHA256WithRSA(content, signature string, key *rsa.PublicKey) error {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return fmt.Errorf("decode signature: %w", err)
+	}
+	hash := sha256.Sum256([]byte(content))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, hash[:], decoded); err != nil {
+		return fmt.Errorf("verify signature: %w", err)
+	}
+	return nil
+}
+
+// parseRSAPublicKey accepts a PEM public key or a PEM certificate, which is
+// how WeChat Pay distributes its platform keys.
+func parseRSAPublicKey(pemData string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(pemData))
+	if block == nil {
+		return nil, errors.New("invalid public key")
+	}
+	if block.Type == "CERTIFICATE" {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		rsaKey, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, errors.New("certificate does not hold an rsa key")
+		}
+		return rsaKey, nil
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err == nil {
+		if rsaKey, ok := pub.(*rsa.PublicKey); ok {
+			return rsaKey, nil
+		}
+	}
+	return x509.ParsePKCS1PublicKey(block.Bytes)
+}
+
+// decryptAESGCM opens an AEAD_AES_256_GCM resource as delivered by WeChat Pay
+// v3 notifies.
+func decryptAESGCM(key, nonce, associatedData, ciphertext string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("decode ciphertext: %w", err)
+	}
+	block, err := aes.NewCipher([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+	plaintext, err := gcm.Open(nil, []byte(nonce), raw, []byte(associatedData))
+	if err != nil {
+		return nil, fmt.Errorf("decrypt resource: %w", err)
+	}
+	return plaintext, nil
+}
+
+// decryptWechatMessage opens a WeChat message push in safe mode: AES-256-CBC
+// with the EncodingAESKey, PKCS#7 padded to 32 bytes, holding 16 random bytes,
+// the big-endian message length, the message and the app id.
+func decryptWechatMessage(encodingAESKey, appID, encrypted string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encodingAESKey + "=")
+	if err != nil || len(key) != 32 {
+		return nil, errors.New("invalid encoding aes key")
+	}
+	raw, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("decode message: %w", err)
+	}
+	if len(raw) == 0 || len(raw)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid message length")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(raw))
+	cipher.NewCBCDecrypter(block, key[:aes.BlockSize]).CryptBlocks(plaintext, raw)
+	padding := int(plaintext[len(plaintext)-1])
+	if padding < 1 || padding > 32 || padding > len(plaintext) {
+		return nil, errors.New("invalid message padding")
+	}
+	plaintext = plaintext[:len(plaintext)-padding]
+	if len(plaintext) < 20 {
+		return nil, errors.New("invalid message length")
+	}
+	length := int(binary.BigEndian.Uint32(plaintext[16:20]))
+	if length > len(plaintext)-20 {
+		return nil, errors.New("invalid message length")
+	}
+	message, receivedAppID := plaintext[20:20+length], plaintext[20+length:]
+	if !bytes.Equal(receivedAppID, []byte(appID)) {
+		return nil, errors.New("message was encrypted for another app")
+	}
+	return message, nil
+}
@@ -0,0 +1,369 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/payment/internal/db"
+)
+
+const testAPIv3Key = "0123456789abcdef0123456789abcdef"
+
+func TestWechatNotifyVerifiesSignatureAndAmount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	platformKey := newTestRSAKey(t)
+	provider, err := NewWechatPayProvider(WechatPayConfig{
+		MchID:          "1900000001",
+		SerialNo:       "merchant-serial",
+		PrivateKeyPEM:  encodeTestPrivateKey(newTestRSAKey(t)),
+		APIv3Key:       testAPIv3Key,
+		PlatformKeyPEM: encodeTestPublicKey(t, &platformKey.PublicKey),
+		PlatformSerial: "platform-serial",
+	})
+	if err != nil {
+		t.Fatalf("new wechat pay provider: %v", err)
+	}
+
+	orderID := uuid.New()
+	store := newPaymentStoreStub()
+	payment, _ := store.CreatePayment(context.Background(), db.CreatePaymentParams{OrderID: orderID, Channel: paymentChannelWechat, Status: paymentStatusPending, AmountFen: 2500, Currency: "CNY"})
+	commerce := newCommerceServerStub(CommerceOrder{ID: orderID.String(), Status: "PAY_PENDING", PaymentStatus: "PAY_PENDING"})
+	defer commerce.Close()
+	router := newTestRouter(&Handler{
+		Flags:        StaticFlagsProvider{Flags: FeatureFlags{PaymentEnabled: true, WechatPayEnabled: true}},
+		Store:        store,
+		Commerce:     NewCommerceClient(commerce.URL(), "sync-token"),
+		ProviderMode: "live",
+		Providers:    Providers{paymentChannelWechat: provider},
+	})
+
+	notify := func(body []byte, signWith *rsa.PrivateKey) *httptest.ResponseRecorder {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		nonce := "notify-nonce"
+		signature, err := signSHA256WithRSA(timestamp+"\n"+nonce+"\n"+string(body)+"\n", signWith)
+		if err != nil {
+			t.Fatalf("sign notify: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/payments/wechat/notify", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Wechatpay-Timestamp", timestamp)
+		req.Header.Set("Wechatpay-Nonce", nonce)
+		req.Header.Set("Wechatpay-Signature", signature)
+		req.Header.Set("Wechatpay-Serial", "platform-serial")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+	transaction := func(total int64) []byte {
+		return wechatNotifyBody(t, map[string]interface{}{
+			"mchid":          "1900000001",
+			"out_trade_no":   orderID.String(),
+			"transaction_id": "4200000001",
+			"trade_state":    "SUCCESS",
+			"amount":         map[string]interface{}{"total": total, "currency": "CNY"},
+		})
+	}
+
+	if rec := notify(transaction(2500), newTestRSAKey(t)); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a forged signature, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := notify(transaction(1), platformKey); rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for an amount mismatch, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if store.payments[payment.ID].Status != paymentStatusPending {
+		t.Fatalf("expected rejected notifies to leave the payment pending, got %s", store.payments[payment.ID].Status)
+	}
+	if len(store.webhooks) != 2 || store.webhooks[0].DeliveryStatus != webhookStatusRejected || store.webhooks[0].PaymentID.Valid {
+		t.Fatalf("expected the forged notify to be recorded without a payment, got %#v", store.webhooks)
+	}
+	if reason := store.webhooks[1].RejectionReason; reason == nil || !strings.Contains(*reason, "amount mismatch") || !store.webhooks[1].PaymentID.Valid {
+		t.Fatalf("expected the amount mismatch to be recorded against the payment, got %#v", store.webhooks[1])
+	}
+
+	if rec := notify(transaction(2500), platformKey); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	got := store.payments[payment.ID]
+	if got.Status != paymentStatusPaid || got.ProviderTradeNo == nil || *got.ProviderTradeNo != "4200000001" {
+		t.Fatalf("expected the verified notify to mark the payment paid, got %s/%v", got.Status, got.ProviderTradeNo)
+	}
+	if statuses := commerce.syncStatuses(); len(statuses) != 1 || statuses[0] != paymentStatusPaid {
+		t.Fatalf("unexpected sync statuses: %v", statuses)
+	}
+}
+
+func TestAlipayNotifyVerifiesRSA2Signature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	alipayKey := newTestRSAKey(t)
+	provider, err := NewAlipayProvider(AlipayConfig{
+		AppID:              "2021000000000001",
+		PrivateKeyPEM:      encodeTestPrivateKey(newTestRSAKey(t)),
+		AlipayPublicKeyPEM: encodeTestPublicKey(t, &alipayKey.PublicKey),
+	})
+	if err != nil {
+		t.Fatalf("new alipay provider: %v", err)
+	}
+
+	orderID := uuid.New()
+	store := newPaymentStoreStub()
+	payment, _ := store.CreatePayment(context.Background(), db.CreatePaymentParams{OrderID: orderID, Channel: paymentChannelAlipay, Status: paymentStatusPending, AmountFen: 12030, Currency: "CNY"})
+	router := newTestRouter(&Handler{
+		Flags:        StaticFlagsProvider{Flags: FeatureFlags{PaymentEnabled: true, AlipayPayEnabled: true}},
+		Store:        store,
+		ProviderMode: "live",
+		Providers:    Providers{paymentChannelAlipay: provider},
+	})
+
+	params := url.Values{}
+	params.Set("app_id", "2021000000000001")
+	params.Set("notify_type", "trade_status_sync")
+	params.Set("out_trade_no", orderID.String())
+	params.Set("trade_no", "2024000000001")
+	params.Set("trade_status", "TRADE_SUCCESS")
+	params.Set("total_amount", "120.30")
+	params.Set("sign_type", "RSA2")
+	unsigned := url.Values{}
+	for key, values := range params {
+		if key != "sign_type" {
+			unsigned[key] = values
+		}
+	}
+	signature, err := signSHA256WithRSA(buildAlipaySignContent(unsigned), alipayKey)
+	if err != nil {
+		t.Fatalf("sign notify: %v", err)
+	}
+	notify := func(params url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/payments/alipay/notify", strings.NewReader(params.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tampered := url.Values{}
+	for key, values := range params {
+		tampered[key] = values
+	}
+	tampered.Set("total_amount", "0.01")
+	tampered.Set("sign", signature)
+	if rec := notify(tampered); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for a tampered notify, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if store.webhooks[0].DeliveryStatus != webhookStatusRejected || !json.Valid(store.webhooks[0].RawBody) {
+		t.Fatalf("expected the form body to be kept as a rejected webhook, got %#v", store.webhooks[0])
+	}
+
+	params.Set("sign", signature)
+	if rec := notify(params); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if store.payments[payment.ID].Status != paymentStatusPaid {
+		t.Fatalf("expected payment to be paid, got %s", store.payments[payment.ID].Status)
+	}
+}
+
+func TestWechatB2BNotifyRequiresSafeMode(t *testing.T) {
+	const (
+		appID  = "wx0000000000000001"
+		token  = "push-token"
+		aesKey = "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"
+	)
+	provider, err := NewWechatB2BDirectProvider(WechatB2BConfig{AppID: appID, AppSecret: "secret", MchID: "b2b-mch", AppKey: "app-key", NotifyToken: token, NotifyAESKey: aesKey})
+	if err != nil {
+		t.Fatalf("new wechat b2b provider: %v", err)
+	}
+	orderID := uuid.New()
+	message, _ := json.Marshal(map[string]interface{}{
+		"Event":        "retail_pay_notify",
+		"mchid":        "b2b-mch",
+		"out_trade_no": "TMO20261017-000123",
+		"attach":       orderID.String(),
+		"order_id":     "b2b-order-1",
+		"pay_status":   "ORDER_PAY_SUCC",
+		"amount":       map[string]interface{}{"order_amount": 800},
+	})
+	encrypted := encryptTestWechatMessage(t, aesKey, appID, message)
+	body, _ := json.Marshal(map[string]string{"ToUserName": appID, "Encrypt": encrypted})
+	parts := []string{token, "1700000000", "nonce", encrypted}
+	sort.Strings(parts)
+	digest := sha1.Sum([]byte(strings.Join(parts, "")))
+	query := url.Values{"timestamp": {"1700000000"}, "nonce": {"nonce"}, "msg_signature": {hex.EncodeToString(digest[:])}}
+
+	payload, err := provider.VerifyNotify(context.Background(), NotifyRequest{Query: query, Body: body})
+	if err != nil {
+		t.Fatalf("verify notify: %v", err)
+	}
+	normalized, err := normalizeNotifyPayload(payload)
+	if err != nil {
+		t.Fatalf("normalize notify: %v", err)
+	}
+	if normalized.OrderID != orderID.String() || normalized.Status != paymentStatusPaid || normalized.AmountFen == nil || *normalized.AmountFen != 800 {
+		t.Fatalf("unexpected notify: %#v", normalized)
+	}
+
+	query.Set("msg_signature", strings.Repeat("0", 40))
+	if _, err := provider.VerifyNotify(context.Background(), NotifyRequest{Query: query, Body: body}); err == nil {
+		t.Fatal("expected a bad msg_signature to be rejected")
+	}
+	if _, err := provider.VerifyNotify(context.Background(), NotifyRequest{Query: query, Body: message}); err == nil {
+		t.Fatal("expected a plaintext push to be rejected")
+	}
+}
+
+func TestNotifyWithoutAmountIsRejectedOutsideMockMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newPaymentStoreStub()
+	payment, _ := store.CreatePayment(context.Background(), db.CreatePaymentParams{OrderID: uuid.New(), Channel: paymentChannelWechat, Status: paymentStatusPending, AmountFen: 500, Currency: "CNY"})
+	sandbox, err := NewSandboxProvider(SandboxConfig{NotifyBaseURL: "http://127.0.0.1:1", Secret: "sandbox-secret"})
+	if err != nil {
+		t.Fatalf("new sandbox provider: %v", err)
+	}
+	router := newTestRouter(&Handler{
+		Flags:        StaticFlagsProvider{Flags: FeatureFlags{PaymentEnabled: true, WechatPayEnabled: true}},
+		Store:        store,
+		ProviderMode: "sandbox",
+		Providers:    Providers{paymentChannelWechat: sandbox},
+	})
+
+	body := `{"paymentId":"` + payment.ID.String() + `","status":"SUCCESS"}`
+	req := httptest.NewRequest(http.MethodPost, "/payments/wechat/notify", strings.NewReader(body))
+	req.Header.Set(sandboxSignatureHeader, hmacSHA256Hex("sandbox-secret", body))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict || store.payments[payment.ID].Status != paymentStatusPending {
+		t.Fatalf("expected the notify to be rejected, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(store.webhooks) != 1 || store.webhooks[0].DeliveryStatus != webhookStatusRejected {
+		t.Fatalf("expected one rejected webhook, got %#v", store.webhooks)
+	}
+}
+
+func TestMockNotifyCannotSettleLiveChannelPayment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newPaymentStoreStub()
+	payment, _ := store.CreatePayment(context.Background(), db.CreatePaymentParams{OrderID: uuid.New(), Channel: paymentChannelWechatB2B, Status: paymentStatusPending, AmountFen: 800, Currency: "CNY"})
+	b2b, err := NewWechatB2BDirectProvider(WechatB2BConfig{AppID: "wx0000000000000001", AppSecret: "secret", MchID: "b2b-mch", AppKey: "app-key", NotifyToken: "push-token", NotifyAESKey: "abcdefghijklmnopqrstuvwxyz0123456789ABCDEFG"})
+	if err != nil {
+		t.Fatalf("new wechat b2b provider: %v", err)
+	}
+	router := newTestRouter(&Handler{
+		Flags:        StaticFlagsProvider{Flags: FeatureFlags{PaymentEnabled: true, WechatPayEnabled: true}},
+		Store:        store,
+		ProviderMode: "mock",
+		Providers:    Providers{paymentChannelWechat: MockProvider{}, paymentChannelWechatB2B: b2b},
+	})
+
+	body := `{"paymentId":"` + payment.ID.String() + `","status":"SUCCESS","amountFen":800}`
+	req := httptest.NewRequest(http.MethodPost, "/payments/wechat/notify", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusConflict || store.payments[payment.ID].Status != paymentStatusPending {
+		t.Fatalf("expected the forged notify to be rejected, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(store.webhooks) != 1 || store.webhooks[0].DeliveryStatus != webhookStatusRejected {
+		t.Fatalf("expected one rejected webhook, got %#v", store.webhooks)
+	}
+}
+
+func TestParseYuanAsFen(t *testing.T) {
+	cases := map[string]int64{"120.30": 12030, "0.01": 1, "8": 800, "3.5": 350}
+	for input, want := range cases {
+		if got, err := parseYuanAsFen(input); err != nil || got != want {
+			t.Fatalf("parseYuanAsFen(%q) = %d, %v; want %d", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "1.234", "-1.00", "abc"} {
+		if _, err := parseYuanAsFen(input); err == nil {
+			t.Fatalf("expected parseYuanAsFen(%q) to fail", input)
+		}
+	}
+}
+
+func newTestRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	return key
+}
+
+func encodeTestPrivateKey(key *rsa.PrivateKey) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+}
+
+func encodeTestPublicKey(t *testing.T, key *rsa.PublicKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func wechatNotifyBody(t *testing.T, resource map[string]interface{}) []byte {
+	t.Helper()
+	plaintext, _ := json.Marshal(resource)
+	block, _ := aes.NewCipher([]byte(testAPIv3Key))
+	gcm, _ := cipher.NewGCM(block)
+	nonce := "resourcenonc"
+	ciphertext := gcm.Seal(nil, []byte(nonce), plaintext, []byte("transaction"))
+	body, _ := json.Marshal(map[string]interface{}{
+		"id":            uuid.NewString(),
+		"event_type":    "TRANSACTION.SUCCESS",
+		"resource_type": "encrypt-resource",
+		"resource": map[string]string{
+			"algorithm":       "AEAD_AES_256_GCM",
+			"ciphertext":      base64.StdEncoding.EncodeToString(ciphertext),
+			"associated_data": "transaction",
+			"nonce":           nonce,
+		},
+	})
+	return body
+}
+
+func encryptTestWechatMessage(t *testing.T, encodingAESKey, appID string, message []byte) string {
+	t.Helper()
+	key, err := base64.StdEncoding.DecodeString(encodingAESKey + "=")
+	if err != nil {
+		t.Fatalf("decode aes key: %v", err)
+	}
+	plaintext := make([]byte, 20, 20+len(message)+len(appID)+32)
+	binary.BigEndian.PutUint32(plaintext[16:20], uint32(len(message)))
+	plaintext = append(append(plaintext, message...), appID...)
+	padding := 32 - len(plaintext)%32
+	plaintext = append(plaintext, bytes.Repeat([]byte{byte(padding)}, padding)...)
+	block, _ := aes.NewCipher(key)
+	ciphertext := make([]byte, len(plaintext))
+	cipher.NewCBCEncrypter(block, key[:aes.BlockSize]).CryptBlocks(ciphertext, plaintext)
+	return base64.StdEncoding.EncodeToString(ciphertext)
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	"github.com/teamdsb/tmo/packages/go-shared/events"
+	"github.com/teamdsb/tmo/services/payment/internal/db"
+)
+
+// paymentEventType returns the event announcing a payment that reached
+// status. Pending payments are synced to commerce when they are created.
+func paymentEventType(status string) (string, bool) {
+	switch status {
+	case paymentStatusPaid:
+		return events.TypePaymentSucceeded, true
+	case paymentStatusFailed:
+		return events.TypePaymentFailed, true
+	case paymentStatusCancelled:
+		return events.TypePaymentCancelled, true
+	case paymentStatusPartiallyRefunded, paymentStatusRefunded:
+		return events.TypePaymentRefunded, true
+	default:
+		return "", false
+	}
+}
+
+func paymentStatusPayload(payment db.Payment) events.PaymentStatus {
+	payload := events.PaymentStatus{
+		PaymentID:       payment.ID.String(),
+		OrderID:         payment.OrderID.String(),
+		Channel:         payment.Channel,
+		Status:          payment.Status,
+		AmountFen:       payment.AmountFen,
+		ProviderTradeNo: payment.ProviderTradeNo,
+	}
+	if payment.PaidAt.Valid {
+		paidAt := payment.PaidAt.Time.UTC()
+		payload.PaidAt = &paidAt
+	}
+	return payload
+}
+
+// savePaymentState updates the payment and records its status event in the
+// same transaction, so commerce learns about the change even when it cannot
+// be reached right now. It reports false when no outbox is configured; the
+// caller then syncs commerce directly.
+func (h *Handler) savePaymentState(ctx context.Context, update db.UpdatePaymentStateParams) (db.Payment, bool, error) {
+	if h.DB == nil || h.Outbox == nil {
+		updated, err := h.Store.UpdatePaymentState(ctx, update)
+		return updated, false, err
+	}
+	var updated db.Payment
+	err := shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		var err error
+		updated, err = db.New(tx).UpdatePaymentState(ctx, update)
+		if err != nil {
+			return err
+		}
+		return h.publishPaymentStatus(ctx, tx, updated)
+	})
+	if err != nil {
+		return db.Payment{}, false, err
+	}
+	return updated, true, nil
+}
+
+// republishPaymentStatus publishes the payment's current status again, for
+// example when an admin replays a webhook.
+func (h *Handler) republishPaymentStatus(ctx context.Context, payment db.Payment) error {
+	return shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		return h.publishPaymentStatus(ctx, tx, payment)
+	})
+}
+
+func (h *Handler) publishPaymentStatus(ctx context.Context, tx pgx.Tx, payment db.Payment) error {
+	eventType, ok := paymentEventType(payment.Status)
+	if !ok {
+		return nil
+	}
+	return h.Outbox.Publish(ctx, tx, eventType, payment.ID.String(), paymentStatusPayload(payment))
+}
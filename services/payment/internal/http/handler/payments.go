@@ -5,8 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
-	"strconv"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,6 +17,7 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/oapi-codegen/runtime/types"
 
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
 	apierrors "github.com/teamdsb/tmo/packages/go-shared/errors"
 	"github.com/teamdsb/tmo/services/payment/internal/db"
 	"github.com/teamdsb/tmo/services/payment/internal/http/middleware"
@@ -28,20 +30,27 @@ const (
 	paymentStatusFailed    = "PAY_FAILED"
 	paymentStatusCancelled = "CANCELLED"
 
+	paymentStatusPartiallyRefunded = "PARTIALLY_REFUNDED"
+	paymentStatusRefunded          = "REFUNDED"
+
 	paymentChannelWechat    = "WECHAT"
 	paymentChannelWechatB2B = "WECHAT_B2B"
 	paymentChannelAlipay    = "ALIPAY"
+
+	// webhookStatusRejected marks notifies that failed verification or
+	// cross-checks; they never change a payment.
+	webhookStatusRejected = "REJECTED"
 )
 
 type normalizedNotifyPayload struct {
 	PaymentID       string
+	OrderID         string
 	Status          string
 	ProviderTradeNo *string
+	AmountFen       *int64
 	EventType       string
 }
 
-type wechatB2BLoginCodeContextKey struct{}
-
 func (h *Handler) PostPaymentsWechatCreate(c *gin.Context, params oapi.PostPaymentsWechatCreateParams) {
 	claims, ok := h.requireUser(c)
 	if !ok {
@@ -112,7 +121,7 @@ func (h *Handler) PostPaymentsAlipayCreate(c *gin.Context, params oapi.PostPayme
 }
 
 func (h *Handler) GetPaymentsPaymentId(c *gin.Context, paymentId types.UUID) {
-	claims, ok := h.requireUser(c)
+	claims, scope, ok := h.requirePermission(c, authz.PermissionPaymentRead, "")
 	if !ok {
 		return
 	}
@@ -122,7 +131,7 @@ func (h *Handler) GetPaymentsPaymentId(c *gin.Context, paymentId types.UUID) {
 		h.writePaymentError(c, err)
 		return
 	}
-	if !canAccessPayment(claims, payment) {
+	if !canAccessPayment(claims, scope, payment) {
 		apierrors.Write(c, http.StatusNotFound, apierrors.APIError{
 			Code:    "not_found",
 			Message: "payment not found",
@@ -134,7 +143,7 @@ func (h *Handler) GetPaymentsPaymentId(c *gin.Context, paymentId types.UUID) {
 }
 
 func (h *Handler) PostPaymentsPaymentIdRecheck(c *gin.Context, paymentId types.UUID) {
-	claims, ok := h.requireUser(c)
+	claims, scope, ok := h.requirePermission(c, authz.PermissionPaymentRead, "")
 	if !ok {
 		return
 	}
@@ -155,7 +164,7 @@ func (h *Handler) PostPaymentsPaymentIdRecheck(c *gin.Context, paymentId types.U
 		h.writePaymentError(c, err)
 		return
 	}
-	if !canAccessPayment(claims, payment) {
+	if !canAccessPayment(claims, scope, payment) {
 		apierrors.Write(c, http.StatusNotFound, apierrors.APIError{
 			Code:    "not_found",
 			Message: "payment not found",
@@ -181,14 +190,11 @@ func (h *Handler) PostPaymentsAlipayNotify(c *gin.Context) {
 }
 
 func (h *Handler) handleNotify(c *gin.Context, channel string) {
-	var payload map[string]interface{}
-	if err := c.ShouldBindJSON(&payload); err != nil {
-		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{
-			Code:    "invalid_request",
-			Message: "invalid request body",
-		})
+	verified, ok := h.verifyNotify(c, channel)
+	if !ok {
 		return
 	}
+	payload := verified.payload
 
 	flags := h.getFeatureFlags(c)
 	if !flags.PaymentEnabled {
@@ -205,22 +211,22 @@ func (h *Handler) handleNotify(c *gin.Context, channel string) {
 		return
 	}
 
-	paymentID, err := uuid.Parse(normalized.PaymentID)
+	payment, err := h.findNotifiedPayment(c, channel, normalized)
 	if err != nil {
-		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{
-			Code:    "invalid_request",
-			Message: "invalid paymentId",
-		})
+		h.writePaymentError(c, err)
 		return
 	}
 
-	payment, err := h.loadPayment(c, paymentID)
-	if err != nil {
-		h.writePaymentError(c, err)
+	rawBody, _ := json.Marshal(payload)
+	if reason := h.notifyRejection(channel, payment, verified, normalized); reason != "" {
+		h.recordRejectedNotify(c.Request.Context(), channel, toNullableUUID(payment.ID), rawBody, reason)
+		apierrors.Write(c, http.StatusConflict, apierrors.APIError{
+			Code:    "notify_rejected",
+			Message: reason,
+		})
 		return
 	}
 
-	rawBody, _ := json.Marshal(payload)
 	if _, err := h.Store.CreatePaymentWebhook(c.Request.Context(), db.CreatePaymentWebhookParams{
 		PaymentID:      toNullableUUID(payment.ID),
 		Provider:       strings.ToLower(channel),
@@ -232,6 +238,13 @@ func (h *Handler) handleNotify(c *gin.Context, channel string) {
 		h.logError("create payment webhook failed", err)
 	}
 
+	// Channels also notify intermediate states; only final results move the
+	// payment.
+	if normalized.Status == paymentStatusPending {
+		c.JSON(http.StatusOK, paymentDetailFromModel(payment))
+		return
+	}
+
 	updated, err := h.applyPaymentResolution(c, payment, normalized.Status, normalized.ProviderTradeNo, nil)
 	if err != nil {
 		h.writePaymentError(c, err)
@@ -245,6 +258,145 @@ func (h *Handler) handleNotify(c *gin.Context, channel string) {
 	c.JSON(http.StatusOK, paymentDetailFromModel(updated))
 }
 
+// findNotifiedPayment resolves the payment a notify refers to. Sandbox and
+// mock notifies name the payment; live channels only echo the merchant order
+// number, which resolves to the latest payment of the order on this channel.
+func (h *Handler) findNotifiedPayment(c *gin.Context, channel string, normalized normalizedNotifyPayload) (db.Payment, error) {
+	if normalized.PaymentID != "" {
+		paymentID, err := uuid.Parse(normalized.PaymentID)
+		if err != nil {
+			return db.Payment{}, errBadRequest("invalid paymentId")
+		}
+		return h.loadPayment(c, paymentID)
+	}
+	orderID, err := uuid.Parse(normalized.OrderID)
+	if err != nil {
+		return db.Payment{}, errBadRequest("invalid orderId")
+	}
+	if h.Store == nil {
+		return db.Payment{}, errInternal("payment store is not configured")
+	}
+	payments, err := h.Store.ListPaymentsByOrder(c.Request.Context(), orderID)
+	if err != nil {
+		return db.Payment{}, errInternal("load payment failed")
+	}
+	for _, payment := range payments {
+		if notifyChannelAccepts(channel, payment.Channel) {
+			return payment, nil
+		}
+	}
+	return db.Payment{}, errNotFound("payment not found")
+}
+
+// notifyRejection returns why an authenticated notify must still not be
+// applied, or an empty string when it may be. Only the provider registered
+// for the payment's own channel can vouch for it, so an unsigned mock notify
+// never settles a payment of a live channel.
+func (h *Handler) notifyRejection(channel string, payment db.Payment, verified verifiedNotify, normalized normalizedNotifyPayload) string {
+	if !notifyChannelAccepts(channel, payment.Channel) {
+		return fmt.Sprintf("payment belongs to channel %s", payment.Channel)
+	}
+	if !verified.by(payment.Channel) {
+		return fmt.Sprintf("notify was not verified by the %s provider", strings.ToLower(payment.Channel))
+	}
+	if normalized.AmountFen == nil {
+		if normalized.Status == paymentStatusPaid && !h.isMockChannel(payment.Channel) {
+			return "notify does not carry the paid amount"
+		}
+		return ""
+	}
+	if *normalized.AmountFen != payment.AmountFen {
+		return fmt.Sprintf("amount mismatch: notified %d fen, expected %d fen", *normalized.AmountFen, payment.AmountFen)
+	}
+	return ""
+}
+
+// verifiedNotify is a notify body together with the channels whose providers
+// accepted it.
+type verifiedNotify struct {
+	payload  map[string]interface{}
+	channels []string
+}
+
+func (v verifiedNotify) by(channel string) bool {
+	return slices.Contains(v.channels, channel)
+}
+
+// verifyNotify reads the raw notify body and lets the channel providers
+// authenticate it before anything is trusted. Every channel served by the
+// endpoint gets a chance, since WeChat delivers JSAPI and B2B notifies to the
+// same URL; the payload is taken from the first provider that accepts it.
+// Deliveries no provider accepts are recorded as rejected.
+func (h *Handler) verifyNotify(c *gin.Context, channel string) (verifiedNotify, bool) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+	if err != nil {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{
+			Code:    "invalid_request",
+			Message: "invalid request body",
+		})
+		return verifiedNotify{}, false
+	}
+	channels := h.notifyProviderChannels(channel)
+	if len(channels) == 0 {
+		h.writePaymentError(c, errNotFound(fmt.Sprintf("%s notifies are not configured", strings.ToLower(channel))))
+		return verifiedNotify{}, false
+	}
+	request := NotifyRequest{Header: c.Request.Header, Query: c.Request.URL.Query(), Body: body}
+	var verified verifiedNotify
+	var verifyErrors []error
+	for _, candidate := range channels {
+		provider, _ := h.Providers.Lookup(candidate)
+		payload, err := provider.VerifyNotify(c.Request.Context(), request)
+		if err != nil {
+			verifyErrors = append(verifyErrors, err)
+			continue
+		}
+		if verified.payload == nil {
+			verified.payload = payload
+		}
+		verified.channels = append(verified.channels, candidate)
+	}
+	if len(verified.channels) > 0 {
+		return verified, true
+	}
+
+	reason := errors.Join(verifyErrors...).Error()
+	if h.Logger != nil {
+		h.Logger.Warn("payment notify rejected", "channel", channel, "error", reason)
+	}
+	rawBody := body
+	if !json.Valid(rawBody) {
+		rawBody, _ = json.Marshal(map[string]string{"body": string(body)})
+	}
+	h.recordRejectedNotify(c.Request.Context(), channel, pgtype.UUID{}, rawBody, reason)
+	apierrors.Write(c, http.StatusUnauthorized, apierrors.APIError{
+		Code:    "invalid_signature",
+		Message: "notify could not be verified",
+	})
+	return verifiedNotify{}, false
+}
+
+func (h *Handler) recordRejectedNotify(ctx context.Context, channel string, paymentID pgtype.UUID, rawBody []byte, reason string) {
+	if h.Store == nil {
+		return
+	}
+	if _, err := h.Store.CreatePaymentWebhook(ctx, db.CreatePaymentWebhookParams{
+		PaymentID:       paymentID,
+		Provider:        strings.ToLower(channel),
+		EventType:       "notify.rejected",
+		DeliveryStatus:  webhookStatusRejected,
+		RawBody:         rawBody,
+		RejectionReason: &reason,
+	}); err != nil {
+		h.logError("create payment webhook failed", err)
+	}
+}
+
+func (h *Handler) isMockProviderMode() bool {
+	mode := strings.ToUpper(strings.TrimSpace(h.ProviderMode))
+	return mode == "" || mode == "MOCK"
+}
+
 func (h *Handler) createPaymentSession(c *gin.Context, claims middleware.Claims, orderID uuid.UUID, channel string, idempotencyKey *string) (interface{}, error) {
 	if h.Store == nil {
 		return nil, errInternal("payment store is not configured")
@@ -291,22 +443,33 @@ func (h *Handler) createPaymentSession(c *gin.Context, claims middleware.Claims,
 		}
 	}
 
+	provider, err := h.provider(channel)
+	if err != nil {
+		return nil, err
+	}
 	now := time.Now().UTC()
-	expiresAt := now.Add(15 * time.Minute)
+	paymentID := uuid.New()
 	amount := calculateOrderAmount(order)
-	providerContext := c.Request.Context()
-	if channel == paymentChannelWechatB2B {
-		providerContext = context.WithValue(providerContext, wechatB2BLoginCodeContextKey{}, c.GetString("wechatB2BLoginCode"))
+	session, err := provider.CreateSession(c.Request.Context(), SessionRequest{
+		PaymentID: paymentID,
+		OrderID:   orderID,
+		OrderNo:   order.OrderNo,
+		Channel:   channel,
+		AmountFen: amount,
+		CreatedAt: now,
+		ExpiresAt: now.Add(15 * time.Minute),
+		LoginCode: c.GetString("wechatB2BLoginCode"),
+	})
+	if err != nil {
+		return nil, errConflict(err.Error())
 	}
-	responsePayload, providerTradeNo, providerPrepayID, rawPayload, err := h.buildProviderPayload(providerContext, channel, orderID, amount, now, expiresAt)
+	rawPayload, err := json.Marshal(session.Response)
 	if err != nil {
-		if channel == paymentChannelWechatB2B {
-			return nil, errConflict(err.Error())
-		}
 		return nil, errInternal("build payment payload failed")
 	}
 
 	params := db.CreatePaymentParams{
+		ID:               paymentID,
 		OrderID:          orderID,
 		PayerUserID:      toNullableUUID(claims.UserID),
 		Channel:          channel,
@@ -314,13 +477,14 @@ func (h *Handler) createPaymentSession(c *gin.Context, claims middleware.Claims,
 		AmountFen:        amount,
 		Currency:         "CNY",
 		IdempotencyKey:   normalizeOptionalString(idempotencyKey),
-		ProviderTradeNo:  providerTradeNo,
-		ProviderPrepayID: providerPrepayID,
+		ProviderTradeNo:  session.ProviderTradeNo,
+		ProviderPrepayID: session.ProviderPrepayID,
 		ProviderPayload:  rawPayload,
 		FailureCode:      nil,
 		FailureMessage:   nil,
 		PaidAt:           pgtype.Timestamptz{},
 		ClosedAt:         pgtype.Timestamptz{},
+		OutTradeNo:       session.OutTradeNo,
 	}
 
 	payment, err := h.Store.CreatePayment(c.Request.Context(), params)
@@ -342,7 +506,7 @@ func (h *Handler) createPaymentSession(c *gin.Context, claims middleware.Claims,
 		}
 	}
 
-	return hydrateCreateResponseIDs(payment.ID, responsePayload), nil
+	return hydrateCreateResponseIDs(payment.ID, session.Response), nil
 }
 
 func (h *Handler) resolvePaymentFromClientResult(c *gin.Context, payment db.Payment, request oapi.PaymentRecheckRequest) (db.Payment, error) {
@@ -352,8 +516,7 @@ func (h *Handler) resolvePaymentFromClientResult(c *gin.Context, payment db.Paym
 	}
 	reason := normalizeOptionalString(request.Reason)
 
-	switch strings.ToUpper(strings.TrimSpace(h.ProviderMode)) {
-	case "", "MOCK":
+	if h.isMockProviderMode() {
 		switch strings.ToUpper(strings.TrimSpace(clientResult)) {
 		case "SUCCESS":
 			return h.applyPaymentResolution(c, payment, paymentStatusPaid, payment.ProviderTradeNo, nil)
@@ -364,16 +527,66 @@ func (h *Handler) resolvePaymentFromClientResult(c *gin.Context, payment db.Paym
 		default:
 			return payment, nil
 		}
+	}
+	return h.resolvePaymentFromProvider(c.Request.Context(), payment, h.requestActor(c))
+}
+
+// resolvePaymentFromProvider asks the channel for the state of a pending
+// payment and applies a final result. Recheck is best effort, so channel
+// errors leave the payment as it is.
+func (h *Handler) resolvePaymentFromProvider(ctx context.Context, payment db.Payment, actor string) (db.Payment, error) {
+	if payment.Status != paymentStatusPending {
+		return payment, nil
+	}
+	provider, ok := h.Providers.Lookup(payment.Channel)
+	if !ok {
+		return payment, nil
+	}
+	result, err := provider.Query(ctx, providerPaymentFromModel(payment))
+	if err != nil {
+		if !errors.Is(err, ErrProviderUnsupported) {
+			h.logError("query payment provider failed", err)
+		}
+		return payment, nil
+	}
+	switch result.Status {
+	case paymentStatusPaid, paymentStatusFailed, paymentStatusCancelled:
+		providerTradeNo := payment.ProviderTradeNo
+		if result.ProviderTradeNo != nil {
+			providerTradeNo = result.ProviderTradeNo
+		}
+		return h.settlePayment(ctx, payment, result.Status, providerTradeNo, nil, actor)
 	default:
 		return payment, nil
 	}
 }
 
 func (h *Handler) applyPaymentResolution(c *gin.Context, payment db.Payment, status string, providerTradeNo *string, reason *string) (db.Payment, error) {
+	return h.settlePayment(c.Request.Context(), payment, status, providerTradeNo, reason, h.requestActor(c))
+}
+
+// requestActor names the caller for audit logs; unauthenticated requests such
+// as channel notifies are recorded as the system.
+func (h *Handler) requestActor(c *gin.Context) string {
+	if c != nil {
+		if claims, ok := h.requireUser(c); ok && claims.UserID != uuid.Nil {
+			return claims.UserID.String()
+		}
+	}
+	return "system"
+}
+
+// settlePayment moves a payment to a final status, tells commerce about it and
+// writes the audit log.
+func (h *Handler) settlePayment(ctx context.Context, payment db.Payment, status string, providerTradeNo *string, reason *string, actor string) (db.Payment, error) {
 	normalizedStatus := strings.ToUpper(strings.TrimSpace(status))
 	if payment.Status == normalizedStatus {
 		return payment, nil
 	}
+	// A refunded payment was paid once; late pay callbacks must not move it.
+	if isRefundedPaymentStatus(payment.Status) {
+		return payment, nil
+	}
 
 	paidAt := pgtype.Timestamptz{}
 	closedAt := pgtype.Timestamptz{}
@@ -396,7 +609,7 @@ func (h *Handler) applyPaymentResolution(c *gin.Context, payment db.Payment, sta
 		return payment, errBadRequest("invalid payment status")
 	}
 
-	updated, err := h.Store.UpdatePaymentState(c.Request.Context(), db.UpdatePaymentStateParams{
+	updated, published, err := h.savePaymentState(ctx, db.UpdatePaymentStateParams{
 		ID:               payment.ID,
 		Status:           normalizedStatus,
 		ProviderTradeNo:  providerTradeNo,
@@ -411,13 +624,13 @@ func (h *Handler) applyPaymentResolution(c *gin.Context, payment db.Payment, sta
 		return db.Payment{}, errInternal("update payment failed")
 	}
 
-	if h.Commerce != nil {
+	if !published && h.Commerce != nil {
 		var paidAtTime *time.Time
 		if updated.PaidAt.Valid {
 			value := updated.PaidAt.Time
 			paidAtTime = &value
 		}
-		if err := h.Commerce.SyncOrderPayment(c.Request.Context(), updated.OrderID.String(), CommercePaymentSyncRequest{
+		if err := h.Commerce.SyncOrderPayment(ctx, updated.OrderID.String(), CommercePaymentSyncRequest{
 			PaymentID:       updated.ID.String(),
 			Channel:         updated.Channel,
 			Status:          updated.Status,
@@ -428,13 +641,7 @@ func (h *Handler) applyPaymentResolution(c *gin.Context, payment db.Payment, sta
 		}
 	}
 
-	actor := "system"
-	if c != nil {
-		if claims, ok := h.requireUser(c); ok && claims.UserID != uuid.Nil {
-			actor = claims.UserID.String()
-		}
-	}
-	if err := h.recordAudit(c.Request.Context(), updated.ID, "status_updated", actor, "payment status -> "+updated.Status); err != nil {
+	if err := h.recordAudit(ctx, updated.ID, "status_updated", actor, "payment status -> "+updated.Status); err != nil {
 		h.logError("create payment audit log failed", err)
 	}
 
@@ -455,6 +662,16 @@ func (h *Handler) loadPayment(c *gin.Context, paymentID uuid.UUID) (db.Payment,
 	return payment, nil
 }
 
+func providerPaymentFromModel(payment db.Payment) ProviderPayment {
+	return ProviderPayment{
+		PaymentID:       payment.ID,
+		OrderID:         payment.OrderID,
+		Channel:         payment.Channel,
+		AmountFen:       payment.AmountFen,
+		ProviderTradeNo: payment.ProviderTradeNo,
+	}
+}
+
 func paymentDetailFromModel(payment db.Payment) oapi.PaymentDetail {
 	response := oapi.PaymentDetail{
 		Id:        payment.ID,
@@ -530,62 +747,10 @@ func hydrateCreateResponseIDs(paymentID uuid.UUID, payload interface{}) interfac
 	}
 }
 
-func (h *Handler) buildProviderPayload(ctx context.Context, channel string, orderID uuid.UUID, amountFen int64, now time.Time, expiresAt time.Time) (interface{}, *string, *string, json.RawMessage, error) {
-	switch channel {
-	case paymentChannelWechat:
-		prepayID := "prepay_" + uuid.NewString()
-		pkg := "prepay_id=" + prepayID
-		response := oapi.WechatPayCreateResponse{
-			OrderId:   orderID,
-			Channel:   oapi.PaymentChannel(paymentChannelWechat),
-			Status:    oapi.PaymentStatus(paymentStatusPending),
-			ExpiresAt: expiresAt,
-			PrepayId:  prepayID,
-			Package:   pkg,
-			NonceStr:  uuid.NewString(),
-			TimeStamp: strconv.FormatInt(now.Unix(), 10),
-			SignType:  "RSA",
-			PaySign:   uuid.NewString(),
-		}
-		raw, err := json.Marshal(response)
-		prepayValue := prepayID
-		return response, nil, &prepayValue, raw, err
-	case paymentChannelWechatB2B:
-		if h.WechatB2BProvider == nil {
-			return nil, nil, nil, nil, fmt.Errorf("wechat b2b provider is not configured")
-		}
-		loginCode, _ := ctx.Value(wechatB2BLoginCodeContextKey{}).(string)
-		params, err := h.WechatB2BProvider.CreateCommonPayParams(ctx, WechatB2BPaymentRequest{OrderID: orderID, AmountFen: amountFen, ExpiresAt: expiresAt, LoginCode: loginCode})
-		if err != nil {
-			return nil, nil, nil, nil, fmt.Errorf("create wechat b2b parameters: %w", err)
-		}
-		if len(params) == 0 {
-			return nil, nil, nil, nil, fmt.Errorf("wechat b2b provider returned empty payment parameters")
-		}
-		response := oapi.WechatB2BPayCreateResponse{OrderId: orderID, Channel: oapi.WECHATB2B, Status: oapi.PaymentStatus(paymentStatusPending), ExpiresAt: expiresAt, CommonPayParams: params}
-		raw, err := json.Marshal(response)
-		return response, nil, nil, raw, err
-	case paymentChannelAlipay:
-		tradeNo := "trade_" + uuid.NewString()
-		response := oapi.AlipayPayCreateResponse{
-			OrderId:   orderID,
-			Channel:   oapi.PaymentChannel(paymentChannelAlipay),
-			Status:    oapi.PaymentStatus(paymentStatusPending),
-			ExpiresAt: expiresAt,
-			TradeNo:   tradeNo,
-			PayParams: map[string]interface{}{
-				"tradeNO": tradeNo,
-			},
-		}
-		raw, err := json.Marshal(response)
-		tradeValue := tradeNo
-		return response, &tradeValue, nil, raw, err
-	default:
-		return nil, nil, nil, nil, fmt.Errorf("unsupported channel")
-	}
-}
-
 func calculateOrderAmount(order CommerceOrder) int64 {
+	if order.TotalFen != nil {
+		return *order.TotalFen
+	}
 	var total int64
 	for _, item := range order.Items {
 		total += int64(item.Qty) * item.UnitPriceFen
@@ -605,10 +770,9 @@ func isOrderPayable(orderStatus, paymentStatus string) bool {
 	}
 }
 
-func canAccessPayment(claims middleware.Claims, payment db.Payment) bool {
-	role := strings.ToUpper(strings.TrimSpace(claims.Role))
-	switch role {
-	case "ADMIN", "MANAGER", "BOSS", "CS", "PROCUREMENT":
+// canAccessPayment limits callers without ALL scope to payments they made.
+func canAccessPayment(claims middleware.Claims, scope authz.Scope, payment db.Payment) bool {
+	if scope == authz.ScopeAll {
 		return true
 	}
 	if !payment.PayerUserID.Valid {
@@ -650,10 +814,20 @@ func normalizeNotifyPayload(payload map[string]interface{}) (normalizedNotifyPay
 	}
 
 	paymentID := strings.TrimSpace(readString(payload, "paymentId", "payment_id"))
-	if paymentID == "" {
+	orderID := strings.TrimSpace(readString(payload, "orderId"))
+	if paymentID == "" && orderID == "" {
 		return normalizedNotifyPayload{}, fmt.Errorf("paymentId is required")
 	}
 
+	var amountFen *int64
+	if value, ok := payload["amountFen"]; ok {
+		parsed, ok := readFen(value)
+		if !ok {
+			return normalizedNotifyPayload{}, fmt.Errorf("invalid amountFen")
+		}
+		amountFen = &parsed
+	}
+
 	var providerTradeNo *string
 	if value := readString(payload, "providerTradeNo", "provider_trade_no", "tradeNo", "trade_no", "out_trade_no"); strings.TrimSpace(value) != "" {
 		trimmed := strings.TrimSpace(value)
@@ -662,8 +836,10 @@ func normalizeNotifyPayload(payload map[string]interface{}) (normalizedNotifyPay
 
 	return normalizedNotifyPayload{
 		PaymentID:       paymentID,
+		OrderID:         orderID,
 		Status:          status,
 		ProviderTradeNo: providerTradeNo,
+		AmountFen:       amountFen,
 		EventType:       readString(payload, "eventType", "event_type"),
 	}, nil
 }
@@ -688,6 +864,25 @@ func readString(payload map[string]interface{}, keys ...string) string {
 	return ""
 }
 
+// readFen reads an amount in fen from a decoded JSON number or from an
+// integer placed into the payload by a provider.
+func readFen(value interface{}) (int64, bool) {
+	switch typed := value.(type) {
+	case int64:
+		return typed, true
+	case float64:
+		if typed != float64(int64(typed)) {
+			return 0, false
+		}
+		return int64(typed), true
+	case json.Number:
+		parsed, err := typed.Int64()
+		return parsed, err == nil
+	default:
+		return 0, false
+	}
+}
+
 func (h *Handler) recordAudit(ctx context.Context, paymentID uuid.UUID, action, actor, detail string) error {
 	if h.Store == nil {
 		return nil
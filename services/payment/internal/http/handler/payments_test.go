@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -41,6 +43,7 @@ func TestPostPaymentsWechatCreateCreatesPaymentAndSyncsOrder(t *testing.T) {
 		Store:        store,
 		Commerce:     NewCommerceClient(commerce.URL(), "sync-token"),
 		ProviderMode: "mock",
+		Providers:    mockProviders(),
 	})
 
 	req := httptest.NewRequest(http.MethodPost, "/payments/wechat/create", strings.NewReader(`{"orderId":"`+orderID.String()+`"}`))
@@ -96,13 +99,13 @@ func TestPostPaymentsWechatB2BCreateUsesProviderParameters(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	orderID := uuid.MustParse("abababab-abab-abab-abab-abababababab")
 	store := newPaymentStoreStub()
-	commerce := newCommerceServerStub(CommerceOrder{ID: orderID.String(), Status: "SUBMITTED", PaymentStatus: "UNPAID", Items: []CommerceOrderItem{{Qty: 2, UnitPriceFen: 1200}}})
+	commerce := newCommerceServerStub(CommerceOrder{ID: orderID.String(), OrderNo: "TMO20261017-000123", Status: "SUBMITTED", PaymentStatus: "UNPAID", Items: []CommerceOrderItem{{Qty: 2, UnitPriceFen: 1200}}})
 	defer commerce.Close()
 
 	router := newTestRouter(&Handler{
 		Flags: StaticFlagsProvider{Flags: FeatureFlags{PaymentEnabled: true, WechatPayEnabled: true}},
 		Store: store, Commerce: NewCommerceClient(commerce.URL(), "sync-token"), ProviderMode: "live",
-		WechatB2BProvider: wechatB2BProviderStub{params: map[string]interface{}{"signData": "opaque", "mode": "retail_pay_goods", "paySig": "opaque", "signature": "opaque"}},
+		Providers: Providers{paymentChannelWechatB2B: wechatB2BProviderStub{params: map[string]interface{}{"signData": "opaque", "mode": "retail_pay_goods", "paySig": "opaque", "signature": "opaque"}}},
 	})
 	req := httptest.NewRequest(http.MethodPost, "/payments/wechat/b2b/create", strings.NewReader(`{"orderId":"`+orderID.String()+`"}`))
 	req.Header.Set("Content-Type", "application/json")
@@ -122,6 +125,41 @@ func TestPostPaymentsWechatB2BCreateUsesProviderParameters(t *testing.T) {
 	if response.Channel != paymentChannelWechatB2B || response.CommonPayParams["mode"] != "retail_pay_goods" || response.PrepayID != nil {
 		t.Fatalf("unexpected B2B response: %#v", response)
 	}
+	for _, payment := range store.payments {
+		if payment.OutTradeNo == nil || *payment.OutTradeNo != "TMO20261017-000123" {
+			t.Fatalf("expected the order number to be stored as out_trade_no, got %v", payment.OutTradeNo)
+		}
+	}
+}
+
+func TestWechatB2BCommonPayParamsUseOrderNoAsOutTradeNo(t *testing.T) {
+	session := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"session_key":"session-key"}`))
+	}))
+	defer session.Close()
+	provider, err := NewWechatB2BDirectProvider(WechatB2BConfig{AppID: "wx-app", AppSecret: "secret", MchID: "b2b-mch", AppKey: "app-key", SessionURL: session.URL})
+	if err != nil {
+		t.Fatalf("new wechat b2b provider: %v", err)
+	}
+	orderID := uuid.New()
+
+	signData := func(orderNo string) map[string]interface{} {
+		params, err := provider.CreateCommonPayParams(context.Background(), WechatB2BPaymentRequest{OrderID: orderID, OrderNo: orderNo, AmountFen: 800, LoginCode: "code"})
+		if err != nil {
+			t.Fatalf("create common pay params: %v", err)
+		}
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(params["signData"].(string)), &data); err != nil {
+			t.Fatalf("decode signData: %v", err)
+		}
+		return data
+	}
+	if data := signData("TMO20261017-000123"); data["out_trade_no"] != "TMO20261017-000123" || data["attach"] != orderID.String() {
+		t.Fatalf("expected the order number as out_trade_no and the order id as attach, got %v", data)
+	}
+	if data := signData(""); data["out_trade_no"] != orderID.String() {
+		t.Fatalf("expected orders without a number to fall back to the order id, got %v", data)
+	}
 }
 
 func TestPostPaymentsWechatB2BCreateRejectsMissingProvider(t *testing.T) {
@@ -140,12 +178,20 @@ func TestPostPaymentsWechatB2BCreateRejectsMissingProvider(t *testing.T) {
 }
 
 type wechatB2BProviderStub struct {
+	MockProvider
 	params map[string]interface{}
 	err    error
 }
 
-func (s wechatB2BProviderStub) CreateCommonPayParams(_ context.Context, _ WechatB2BPaymentRequest) (map[string]interface{}, error) {
-	return s.params, s.err
+func (s wechatB2BProviderStub) CreateSession(_ context.Context, request SessionRequest) (Session, error) {
+	if s.err != nil {
+		return Session{}, s.err
+	}
+	return Session{Response: oapi.WechatB2BPayCreateResponse{OrderId: request.OrderID, Channel: oapi.WECHATB2B, Status: oapi.PaymentStatus(paymentStatusPending), ExpiresAt: request.ExpiresAt, CommonPayParams: s.params}, OutTradeNo: &request.OrderNo}, nil
+}
+
+func mockProviders() Providers {
+	return Providers{paymentChannelWechat: MockProvider{}, paymentChannelAlipay: MockProvider{}}
 }
 
 func TestPostPaymentsWechatCreateReturnsExistingPaymentForIdempotencyKey(t *testing.T) {
@@ -197,6 +243,7 @@ func TestPostPaymentsWechatCreateReturnsExistingPaymentForIdempotencyKey(t *test
 		Store:        store,
 		Commerce:     NewCommerceClient(commerce.URL(), "sync-token"),
 		ProviderMode: "mock",
+		Providers:    mockProviders(),
 	})
 
 	req := httptest.NewRequest(http.MethodPost, "/payments/wechat/create", strings.NewReader(`{"orderId":"`+orderID.String()+`"}`))
@@ -245,6 +292,7 @@ func TestPostPaymentsAlipayCreateReturnsTradeNo(t *testing.T) {
 		Store:        store,
 		Commerce:     NewCommerceClient(commerce.URL(), "sync-token"),
 		ProviderMode: "mock",
+		Providers:    mockProviders(),
 	})
 
 	req := httptest.NewRequest(http.MethodPost, "/payments/alipay/create", strings.NewReader(`{"orderId":"`+orderID.String()+`"}`))
@@ -290,6 +338,7 @@ func TestPostPaymentsWechatCreateRejectsWhenFeatureFlagDisabled(t *testing.T) {
 		Store:        store,
 		Commerce:     NewCommerceClient(commerce.URL(), "sync-token"),
 		ProviderMode: "mock",
+		Providers:    mockProviders(),
 	})
 
 	req := httptest.NewRequest(http.MethodPost, "/payments/wechat/create", strings.NewReader(`{"orderId":"`+orderID.String()+`"}`))
@@ -333,6 +382,7 @@ func TestPostPaymentsPaymentIdRecheckUpdatesStatusAndSyncsOrder(t *testing.T) {
 		Store:        store,
 		Commerce:     NewCommerceClient(commerce.URL(), "sync-token"),
 		ProviderMode: "mock",
+		Providers:    mockProviders(),
 	})
 
 	req := httptest.NewRequest(http.MethodPost, "/payments/"+paymentID.String()+"/recheck", strings.NewReader(`{"clientResult":"SUCCESS"}`))
@@ -382,6 +432,7 @@ func TestPostPaymentsWechatNotifyStoresWebhookAndMarksPaid(t *testing.T) {
 		Store:        store,
 		Commerce:     NewCommerceClient(commerce.URL(), "sync-token"),
 		ProviderMode: "mock",
+		Providers:    mockProviders(),
 	})
 
 	body := `{"paymentId":"` + paymentID.String() + `","status":"SUCCESS","providerTradeNo":"wx-trade-123","eventType":"payment.succeeded"}`
@@ -414,6 +465,20 @@ type paymentStoreStub struct {
 	idempotency map[string]uuid.UUID
 	webhooks    []db.PaymentWebhook
 	audits      []db.PaymentAuditLog
+	refunds     map[uuid.UUID]db.Refund
+	runs        []db.ReconciliationRun
+	mismatches  []db.ReconciliationDiscrepancy
+}
+
+func TestCalculateOrderAmountPrefersOrderTotal(t *testing.T) {
+	items := []CommerceOrderItem{{Qty: 2, UnitPriceFen: 1500}, {Qty: 1, UnitPriceFen: 3000}}
+	if got := calculateOrderAmount(CommerceOrder{Items: items}); got != 6000 {
+		t.Fatalf("expected line total 6000, got %d", got)
+	}
+	totalFen := int64(5000)
+	if got := calculateOrderAmount(CommerceOrder{Items: items, TotalFen: &totalFen}); got != 5000 {
+		t.Fatalf("expected discounted total 5000, got %d", got)
+	}
 }
 
 func newPaymentStoreStub() *paymentStoreStub {
@@ -422,6 +487,7 @@ func newPaymentStoreStub() *paymentStoreStub {
 		idempotency: make(map[string]uuid.UUID),
 		webhooks:    []db.PaymentWebhook{},
 		audits:      []db.PaymentAuditLog{},
+		refunds:     make(map[uuid.UUID]db.Refund),
 	}
 }
 
@@ -430,7 +496,10 @@ func (s *paymentStoreStub) CreatePayment(_ context.Context, arg db.CreatePayment
 	defer s.mu.Unlock()
 
 	s.createCalls++
-	id := uuid.New()
+	id := arg.ID
+	if id == uuid.Nil {
+		id = uuid.New()
+	}
 	now := time.Now().UTC()
 	payment := db.Payment{
 		ID:               id,
@@ -448,6 +517,7 @@ func (s *paymentStoreStub) CreatePayment(_ context.Context, arg db.CreatePayment
 		FailureMessage:   arg.FailureMessage,
 		PaidAt:           arg.PaidAt,
 		ClosedAt:         arg.ClosedAt,
+		OutTradeNo:       arg.OutTradeNo,
 		CreatedAt:        pgtype.Timestamptz{Time: now, Valid: true},
 		UpdatedAt:        pgtype.Timestamptz{Time: now, Valid: true},
 	}
@@ -508,6 +578,32 @@ func (s *paymentStoreStub) ListPayments(context.Context, db.ListPaymentsParams)
 	return nil, nil
 }
 
+func (s *paymentStoreStub) ListPaymentsByOrder(_ context.Context, orderID uuid.UUID) ([]db.Payment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := []db.Payment{}
+	for _, payment := range s.payments {
+		if payment.OrderID == orderID {
+			items = append(items, payment)
+		}
+	}
+	return items, nil
+}
+
+func (s *paymentStoreStub) ListPaymentsByOutTradeNo(_ context.Context, outTradeNo *string) ([]db.Payment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := []db.Payment{}
+	for _, payment := range s.payments {
+		if payment.OutTradeNo != nil && outTradeNo != nil && *payment.OutTradeNo == *outTradeNo {
+			items = append(items, payment)
+		}
+	}
+	return items, nil
+}
+
 func (s *paymentStoreStub) CountPayments(context.Context, db.CountPaymentsParams) (int64, error) {
 	return 0, nil
 }
@@ -517,16 +613,17 @@ func (s *paymentStoreStub) CreatePaymentWebhook(_ context.Context, arg db.Create
 	defer s.mu.Unlock()
 
 	webhook := db.PaymentWebhook{
-		ID:             uuid.New(),
-		PaymentID:      arg.PaymentID,
-		Provider:       arg.Provider,
-		EventType:      arg.EventType,
-		DeliveryStatus: arg.DeliveryStatus,
-		RawBody:        arg.RawBody,
-		ReplayCount:    0,
-		ProcessedAt:    arg.ProcessedAt,
-		CreatedAt:      pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
-		UpdatedAt:      pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+		ID:              uuid.New(),
+		PaymentID:       arg.PaymentID,
+		Provider:        arg.Provider,
+		EventType:       arg.EventType,
+		DeliveryStatus:  arg.DeliveryStatus,
+		RawBody:         arg.RawBody,
+		ReplayCount:     0,
+		ProcessedAt:     arg.ProcessedAt,
+		CreatedAt:       pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+		UpdatedAt:       pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+		RejectionReason: arg.RejectionReason,
 	}
 	s.webhooks = append(s.webhooks, webhook)
 	return webhook, nil
@@ -572,7 +669,117 @@ func (s *paymentStoreStub) CountPaymentAuditLogs(context.Context, db.CountPaymen
 	return 0, nil
 }
 
+func (s *paymentStoreStub) CreateRefund(_ context.Context, arg db.CreateRefundParams) (db.Refund, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payment, ok := s.payments[arg.PaymentID]
+	if !ok || !slices.Contains(arg.RefundableStatuses, payment.Status) || payment.AmountFen-payment.RefundedFen < arg.AmountFen {
+		return db.Refund{}, pgx.ErrNoRows
+	}
+	payment.RefundedFen += arg.AmountFen
+	s.payments[payment.ID] = payment
+	now := pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true}
+	refund := db.Refund{
+		ID:             uuid.New(),
+		PaymentID:      payment.ID,
+		OrderID:        payment.OrderID,
+		Channel:        payment.Channel,
+		Status:         arg.Status,
+		AmountFen:      arg.AmountFen,
+		Reason:         arg.Reason,
+		IdempotencyKey: arg.IdempotencyKey,
+		RequestedBy:    arg.RequestedBy,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	s.refunds[refund.ID] = refund
+	return refund, nil
+}
+
+func (s *paymentStoreStub) GetRefund(_ context.Context, id uuid.UUID) (db.Refund, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	refund, ok := s.refunds[id]
+	if !ok {
+		return db.Refund{}, pgx.ErrNoRows
+	}
+	return refund, nil
+}
+
+func (s *paymentStoreStub) GetRefundByIdempotencyKey(_ context.Context, arg db.GetRefundByIdempotencyKeyParams) (db.Refund, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, refund := range s.refunds {
+		if refund.PaymentID == arg.PaymentID && refund.IdempotencyKey == arg.IdempotencyKey {
+			return refund, nil
+		}
+	}
+	return db.Refund{}, pgx.ErrNoRows
+}
+
+func (s *paymentStoreStub) ListRefundsByPayment(_ context.Context, paymentID uuid.UUID) ([]db.Refund, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := []db.Refund{}
+	for _, refund := range s.refunds {
+		if refund.PaymentID == paymentID {
+			items = append(items, refund)
+		}
+	}
+	return items, nil
+}
+
+func (s *paymentStoreStub) UpdateRefundState(_ context.Context, arg db.UpdateRefundStateParams) (db.Refund, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	refund, ok := s.refunds[arg.ID]
+	if !ok || refund.Status != arg.FromStatus {
+		return db.Refund{}, pgx.ErrNoRows
+	}
+	refund.Status = arg.Status
+	if arg.ProviderRefundNo != nil {
+		refund.ProviderRefundNo = arg.ProviderRefundNo
+	}
+	refund.FailureMessage = arg.FailureMessage
+	refund.RefundedAt = arg.RefundedAt
+	refund.UpdatedAt = pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true}
+	s.refunds[arg.ID] = refund
+	return refund, nil
+}
+
+func (s *paymentStoreStub) ReleaseRefundReservation(_ context.Context, arg db.ReleaseRefundReservationParams) (db.Payment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payment, ok := s.payments[arg.ID]
+	if !ok {
+		return db.Payment{}, pgx.ErrNoRows
+	}
+	payment.RefundedFen -= arg.AmountFen
+	s.payments[arg.ID] = payment
+	return payment, nil
+}
+
+func (s *paymentStoreStub) SumSucceededRefunds(_ context.Context, paymentID uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var total int64
+	for _, refund := range s.refunds {
+		if refund.PaymentID == paymentID && refund.Status == refundStatusSucceeded {
+			total += refund.AmountFen
+		}
+	}
+	return total, nil
+}
+
 type commerceServerStub struct {
+	mu                sync.Mutex
 	server            *httptest.Server
 	order             CommerceOrder
 	lastAuthorization string
@@ -592,7 +799,9 @@ func newCommerceServerStub(order CommerceOrder) *commerceServerStub {
 			stub.syncToken = r.Header.Get("X-Internal-Token")
 			var payload CommercePaymentSyncRequest
 			_ = json.NewDecoder(r.Body).Decode(&payload)
+			stub.mu.Lock()
 			stub.syncRequests = append(stub.syncRequests, payload)
+			stub.mu.Unlock()
 			w.WriteHeader(http.StatusOK)
 		default:
 			http.NotFound(w, r)
@@ -609,6 +818,16 @@ func (s *commerceServerStub) URL() string {
 	return s.server.URL
 }
 
+func (s *commerceServerStub) syncStatuses() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]string, 0, len(s.syncRequests))
+	for _, request := range s.syncRequests {
+		statuses = append(statuses, request.Status)
+	}
+	return statuses
+}
+
 func keyForIdempotency(orderID uuid.UUID, channel, idempotencyKey string) string {
 	return orderID.String() + "|" + channel + "|" + strings.TrimSpace(idempotencyKey)
 }
@@ -617,13 +836,144 @@ func strPtr(value string) *string {
 	return &value
 }
 
+func (s *paymentStoreStub) ListPendingPaymentsCreatedBefore(_ context.Context, arg db.ListPendingPaymentsCreatedBeforeParams) ([]db.Payment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := []db.Payment{}
+	for _, payment := range s.payments {
+		if payment.Status != arg.Status || !payment.CreatedAt.Time.Before(arg.CreatedBefore.Time) {
+			continue
+		}
+		if arg.CreatedAfter.Valid && comparePendingPaymentKey(payment, arg.CreatedAfter.Time, arg.AfterID) <= 0 {
+			continue
+		}
+		items = append(items, payment)
+	}
+	slices.SortFunc(items, func(a, b db.Payment) int { return comparePendingPaymentKey(a, b.CreatedAt.Time, b.ID) })
+	if len(items) > int(arg.Limit) {
+		items = items[:arg.Limit]
+	}
+	return items, nil
+}
+
+func comparePendingPaymentKey(payment db.Payment, createdAt time.Time, id uuid.UUID) int {
+	if c := payment.CreatedAt.Time.Compare(createdAt); c != 0 {
+		return c
+	}
+	return bytes.Compare(payment.ID[:], id[:])
+}
+
+func (s *paymentStoreStub) ListPaidPaymentsByChannel(_ context.Context, arg db.ListPaidPaymentsByChannelParams) ([]db.Payment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := []db.Payment{}
+	for _, payment := range s.payments {
+		if payment.Channel != arg.Channel || !payment.PaidAt.Valid {
+			continue
+		}
+		if payment.PaidAt.Time.Before(arg.PaidFrom.Time) || !payment.PaidAt.Time.Before(arg.PaidTo.Time) {
+			continue
+		}
+		items = append(items, payment)
+	}
+	return items, nil
+}
+
+func (s *paymentStoreStub) CreateReconciliationRun(_ context.Context, arg db.CreateReconciliationRunParams) (db.ReconciliationRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run := db.ReconciliationRun{
+		ID:               uuid.New(),
+		Channel:          arg.Channel,
+		StatementDate:    arg.StatementDate,
+		FileName:         arg.FileName,
+		StatementLines:   arg.StatementLines,
+		MatchedCount:     arg.MatchedCount,
+		DiscrepancyCount: arg.DiscrepancyCount,
+		CreatedBy:        arg.CreatedBy,
+		CreatedAt:        pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+	}
+	s.runs = append(s.runs, run)
+	return run, nil
+}
+
+func (s *paymentStoreStub) CreateReconciliationDiscrepancy(_ context.Context, arg db.CreateReconciliationDiscrepancyParams) (db.ReconciliationDiscrepancy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := db.ReconciliationDiscrepancy{
+		ID:                 uuid.New(),
+		RunID:              arg.RunID,
+		Kind:               arg.Kind,
+		PaymentID:          arg.PaymentID,
+		OutTradeNo:         arg.OutTradeNo,
+		ProviderTradeNo:    arg.ProviderTradeNo,
+		LedgerAmountFen:    arg.LedgerAmountFen,
+		StatementAmountFen: arg.StatementAmountFen,
+		LedgerStatus:       arg.LedgerStatus,
+		StatementStatus:    arg.StatementStatus,
+		CreatedAt:          pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+	}
+	s.mismatches = append(s.mismatches, item)
+	return item, nil
+}
+
+func (s *paymentStoreStub) GetReconciliationRun(_ context.Context, id uuid.UUID) (db.ReconciliationRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, run := range s.runs {
+		if run.ID == id {
+			return run, nil
+		}
+	}
+	return db.ReconciliationRun{}, pgx.ErrNoRows
+}
+
+func (s *paymentStoreStub) ListReconciliationRuns(context.Context, db.ListReconciliationRunsParams) ([]db.ReconciliationRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.runs), nil
+}
+
+func (s *paymentStoreStub) CountReconciliationRuns(context.Context, *string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return int64(len(s.runs)), nil
+}
+
+func (s *paymentStoreStub) ListReconciliationDiscrepancies(_ context.Context, runID uuid.UUID) ([]db.ReconciliationDiscrepancy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := []db.ReconciliationDiscrepancy{}
+	for _, item := range s.mismatches {
+		if item.RunID == runID {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
 func newTestRouter(handler *Handler) *gin.Engine {
 	router := gin.New()
 	oapi.RegisterHandlers(router, handler)
 	router.GET("/admin/payments/transactions", handler.GetAdminPaymentsTransactions)
 	router.GET("/admin/payments/transactions/:id", handler.GetAdminPaymentsTransactionsId)
+	router.GET("/admin/payments/transactions/:id/refunds", handler.GetAdminPaymentsTransactionsIdRefunds)
+	router.POST("/admin/payments/transactions/:id/refunds", handler.PostAdminPaymentsTransactionsIdRefunds)
 	router.GET("/admin/payments/audit-logs", handler.GetAdminPaymentsAuditLogs)
 	router.GET("/admin/payments/webhooks", handler.GetAdminPaymentsWebhooks)
 	router.POST("/admin/payments/webhooks/:id/replay", handler.PostAdminPaymentsWebhooksIdReplay)
+	router.GET("/admin/payments/reconciliations", handler.GetAdminPaymentsReconciliations)
+	router.POST("/admin/payments/reconciliations", handler.PostAdminPaymentsReconciliations)
+	router.GET("/admin/payments/reconciliations/:id", handler.GetAdminPaymentsReconciliationsId)
+	router.POST("/internal/orders/:orderId/payments/close", handler.PostInternalOrdersOrderIdPaymentsClose)
+	router.POST("/internal/events", handler.PostInternalEvents)
 	return router
 }
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/payment/internal/http/oapi"
+)
+
+// ErrProviderUnsupported is returned by providers for operations their
+// channel does not offer. Callers treat it as "nothing to do" rather than a
+// failure.
+var ErrProviderUnsupported = errors.New("operation is not supported by the payment provider")
+
+// Provider integrates one payment channel. Handlers look providers up by
+// channel and never talk to a channel directly.
+type Provider interface {
+	RefundProvider
+	// CreateSession prepares the parameters the client needs to start paying.
+	CreateSession(ctx context.Context, request SessionRequest) (Session, error)
+	// Query asks the channel for the current state of a payment.
+	Query(ctx context.Context, payment ProviderPayment) (QueryResult, error)
+	// Close stops the channel from accepting a pending payment.
+	Close(ctx context.Context, payment ProviderPayment) error
+	// VerifyNotify authenticates an async notify and returns its decoded
+	// payload. Payloads that cannot be authenticated must be rejected.
+	VerifyNotify(ctx context.Context, request NotifyRequest) (map[string]interface{}, error)
+}
+
+type SessionRequest struct {
+	PaymentID uuid.UUID
+	OrderID   uuid.UUID
+	OrderNo   string
+	Channel   string
+	AmountFen int64
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	LoginCode string
+}
+
+// Session is the client-facing create response together with the channel
+// references stored on the payment.
+type Session struct {
+	Response         interface{}
+	ProviderTradeNo  *string
+	ProviderPrepayID *string
+	// OutTradeNo is the merchant order number sent to the channel when it is
+	// not the order id.
+	OutTradeNo *string
+}
+
+type ProviderPayment struct {
+	PaymentID       uuid.UUID
+	OrderID         uuid.UUID
+	Channel         string
+	AmountFen       int64
+	ProviderTradeNo *string
+}
+
+// QueryResult reports a payment status using the payment status constants.
+type QueryResult struct {
+	Status          string
+	ProviderTradeNo *string
+}
+
+type NotifyRequest struct {
+	Header http.Header
+	Query  url.Values
+	Body   []byte
+}
+
+// Providers registers one provider per payment channel.
+type Providers map[string]Provider
+
+func (p Providers) Lookup(channel string) (Provider, bool) {
+	provider, ok := p[strings.ToUpper(strings.TrimSpace(channel))]
+	return provider, ok && provider != nil
+}
+
+func (h *Handler) provider(channel string) (Provider, error) {
+	provider, ok := h.Providers.Lookup(channel)
+	if !ok {
+		return nil, errConflict(fmt.Sprintf("%s provider is not configured", strings.ToLower(channel)))
+	}
+	return provider, nil
+}
+
+// notifyChannels lists the channels whose notifies arrive on each notify
+// endpoint. WeChat delivers both JSAPI and B2B notifies to the same URL.
+var notifyChannels = map[string][]string{
+	paymentChannelWechat: {paymentChannelWechat, paymentChannelWechatB2B},
+	paymentChannelAlipay: {paymentChannelAlipay},
+}
+
+func notifyChannelAccepts(endpoint, channel string) bool {
+	for _, candidate := range notifyChannels[endpoint] {
+		if candidate == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// notifyProviderChannels returns the channels whose providers may
+// authenticate notifies delivered to the endpoint of channel. Real providers
+// come first, so a body a real channel signed is read the way that channel
+// reads it rather than as plain mock JSON.
+func (h *Handler) notifyProviderChannels(channel string) []string {
+	var live, mock []string
+	for _, candidate := range notifyChannels[channel] {
+		provider, ok := h.Providers.Lookup(candidate)
+		switch {
+		case !ok:
+		case isMockProvider(provider):
+			mock = append(mock, candidate)
+		default:
+			live = append(live, candidate)
+		}
+	}
+	return append(live, mock...)
+}
+
+// isMockChannel reports whether channel is served by MockProvider, whose
+// notifies carry no signature.
+func (h *Handler) isMockChannel(channel string) bool {
+	provider, ok := h.Providers.Lookup(channel)
+	return ok && isMockProvider(provider)
+}
+
+func isMockProvider(provider Provider) bool {
+	_, ok := provider.(MockProvider)
+	return ok
+}
+
+// MockProvider answers every call locally with placeholder parameters. It
+// backs the mock provider mode used for local development and unit tests;
+// clients report the payment result themselves through recheck.
+type MockProvider struct{}
+
+func (MockProvider) CreateSession(_ context.Context, request SessionRequest) (Session, error) {
+	switch request.Channel {
+	case paymentChannelWechat:
+		prepayID := "prepay_" + uuid.NewString()
+		return Session{Response: oapi.WechatPayCreateResponse{
+			OrderId:   request.OrderID,
+			Channel:   oapi.PaymentChannel(paymentChannelWechat),
+			Status:    oapi.PaymentStatus(paymentStatusPending),
+			ExpiresAt: request.ExpiresAt,
+			PrepayId:  prepayID,
+			Package:   "prepay_id=" + prepayID,
+			NonceStr:  uuid.NewString(),
+			TimeStamp: strconv.FormatInt(request.CreatedAt.Unix(), 10),
+			SignType:  "RSA",
+			PaySign:   uuid.NewString(),
+		}, ProviderPrepayID: &prepayID}, nil
+	case paymentChannelAlipay:
+		tradeNo := "trade_" + uuid.NewString()
+		return Session{Response: oapi.AlipayPayCreateResponse{
+			OrderId:   request.OrderID,
+			Channel:   oapi.PaymentChannel(paymentChannelAlipay),
+			Status:    oapi.PaymentStatus(paymentStatusPending),
+			ExpiresAt: request.ExpiresAt,
+			TradeNo:   tradeNo,
+			PayParams: map[string]interface{}{"tradeNO": tradeNo},
+		}, ProviderTradeNo: &tradeNo}, nil
+	default:
+		return Session{}, fmt.Errorf("%w: mock %s sessions", ErrProviderUnsupported, strings.ToLower(request.Channel))
+	}
+}
+
+func (MockProvider) Query(context.Context, ProviderPayment) (QueryResult, error) {
+	return QueryResult{}, ErrProviderUnsupported
+}
+
+func (MockProvider) Close(context.Context, ProviderPayment) error {
+	return nil
+}
+
+func (MockProvider) Refund(_ context.Context, request RefundRequest) (RefundResult, error) {
+	refundNo := "mock_refund_" + request.RefundID.String()
+	return RefundResult{Status: refundStatusSucceeded, ProviderRefundNo: &refundNo}, nil
+}
+
+// VerifyNotify trusts the payload as is; mock mode has no channel to sign it.
+func (MockProvider) VerifyNotify(_ context.Context, request NotifyRequest) (map[string]interface{}, error) {
+	return decodeNotifyJSON(request.Body)
+}
+
+func decodeNotifyJSON(body []byte) (map[string]interface{}, error) {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(body, &payload); err != nil || payload == nil {
+		return nil, errors.New("invalid request body")
+	}
+	return payload, nil
+}
@@ -0,0 +1,391 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+	apierrors "github.com/teamdsb/tmo/packages/go-shared/errors"
+	"github.com/teamdsb/tmo/services/payment/internal/db"
+	"github.com/teamdsb/tmo/services/payment/internal/modules/reconcile"
+)
+
+const maxStatementSize = 20 << 20
+
+// Provider statements are cut by China Standard Time days.
+var statementLocation = time.FixedZone("CST", 8*60*60)
+
+type adminReconciliationRun struct {
+	ID               string `json:"id"`
+	Channel          string `json:"channel"`
+	StatementDate    string `json:"statementDate"`
+	FileName         string `json:"fileName"`
+	StatementLines   int    `json:"statementLines"`
+	MatchedCount     int    `json:"matchedCount"`
+	DiscrepancyCount int    `json:"discrepancyCount"`
+	CreatedBy        string `json:"createdBy"`
+	CreatedAt        string `json:"createdAt"`
+}
+
+type adminReconciliationDiscrepancy struct {
+	ID                 string `json:"id"`
+	Kind               string `json:"kind"`
+	TransactionID      string `json:"transactionId,omitempty"`
+	OutTradeNo         string `json:"outTradeNo,omitempty"`
+	ProviderTradeNo    string `json:"providerTradeNo,omitempty"`
+	LedgerAmountFen    *int64 `json:"ledgerAmountFen,omitempty"`
+	StatementAmountFen *int64 `json:"statementAmountFen,omitempty"`
+	LedgerStatus       string `json:"ledgerStatus,omitempty"`
+	StatementStatus    string `json:"statementStatus,omitempty"`
+}
+
+type adminReconciliationDetail struct {
+	adminReconciliationRun
+	Discrepancies []adminReconciliationDiscrepancy `json:"discrepancies"`
+}
+
+type pagedAdminReconciliationRuns struct {
+	Items    []adminReconciliationRun `json:"items"`
+	Page     int                      `json:"page"`
+	PageSize int                      `json:"pageSize"`
+	Total    int                      `json:"total"`
+}
+
+// SettlePendingPayments queries the channel for every payment still pending
+// since before createdBefore, batchSize payments at a time, and applies the
+// final results. Sessions the channel cannot report on stay pending; commerce
+// closes them with the order.
+func (h *Handler) SettlePendingPayments(ctx context.Context, createdBefore time.Time, batchSize int) (int, error) {
+	if h.Store == nil {
+		return 0, errors.New("payment store is not configured")
+	}
+	settled := 0
+	after := pgtype.Timestamptz{}
+	afterID := uuid.Nil
+	for {
+		payments, err := h.Store.ListPendingPaymentsCreatedBefore(ctx, db.ListPendingPaymentsCreatedBeforeParams{
+			Status:        paymentStatusPending,
+			CreatedBefore: pgtype.Timestamptz{Time: createdBefore, Valid: true},
+			CreatedAfter:  after,
+			AfterID:       afterID,
+			Limit:         int32(batchSize),
+		})
+		if err != nil {
+			return settled, err
+		}
+		for _, payment := range payments {
+			updated, err := h.resolvePaymentFromProvider(ctx, payment, "system")
+			if err != nil {
+				h.logError("settle pending payment failed", err)
+				continue
+			}
+			if updated.Status != payment.Status {
+				settled++
+			}
+		}
+		if len(payments) < batchSize {
+			return settled, nil
+		}
+		last := payments[len(payments)-1]
+		after, afterID = last.CreatedAt, last.ID
+	}
+}
+
+func (h *Handler) PostAdminPaymentsReconciliations(c *gin.Context) {
+	claims, _, ok := h.requirePermission(c, authz.PermissionPaymentManage, authz.ScopeAll)
+	if !ok {
+		return
+	}
+	if h.Store == nil {
+		apierrors.Write(c, http.StatusInternalServerError, apierrors.APIError{Code: "internal_error", Message: "payment store is not configured"})
+		return
+	}
+
+	channel := strings.ToUpper(strings.TrimSpace(c.PostForm("channel")))
+	switch channel {
+	case paymentChannelWechat, paymentChannelWechatB2B, paymentChannelAlipay:
+	default:
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: "channel must be WECHAT, WECHAT_B2B or ALIPAY"})
+		return
+	}
+	statementDate, err := time.ParseInLocation(time.DateOnly, strings.TrimSpace(c.PostForm("statementDate")), statementLocation)
+	if err != nil {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: "statementDate must be YYYY-MM-DD"})
+		return
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: "missing file"})
+		return
+	}
+	if fileHeader.Size > maxStatementSize {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: "file exceeds 20MB limit"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: "failed to read file"})
+		return
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	lines, err := reconcile.ParseStatement(file)
+	if err != nil {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_statement", Message: err.Error()})
+		return
+	}
+
+	ledger, err := h.reconciliationLedger(c.Request.Context(), channel, statementDate, lines)
+	if err != nil {
+		h.logError("load reconciliation ledger failed", err)
+		apierrors.Write(c, http.StatusInternalServerError, apierrors.APIError{Code: "internal_error", Message: "failed to load payments"})
+		return
+	}
+	result := reconcile.Compare(lines, ledger)
+
+	actor := "admin"
+	if claims.UserID != uuid.Nil {
+		actor = claims.UserID.String()
+	}
+	run, err := h.Store.CreateReconciliationRun(c.Request.Context(), db.CreateReconciliationRunParams{
+		Channel:          channel,
+		StatementDate:    pgtype.Date{Time: time.Date(statementDate.Year(), statementDate.Month(), statementDate.Day(), 0, 0, 0, 0, time.UTC), Valid: true},
+		FileName:         filepath.Base(fileHeader.Filename),
+		StatementLines:   int32(len(lines)),
+		MatchedCount:     int32(result.Matched),
+		DiscrepancyCount: int32(len(result.Discrepancies)),
+		CreatedBy:        actor,
+	})
+	if err != nil {
+		h.logError("create reconciliation run failed", err)
+		apierrors.Write(c, http.StatusInternalServerError, apierrors.APIError{Code: "internal_error", Message: "failed to save reconciliation"})
+		return
+	}
+	discrepancies := make([]db.ReconciliationDiscrepancy, 0, len(result.Discrepancies))
+	for _, item := range result.Discrepancies {
+		saved, err := h.Store.CreateReconciliationDiscrepancy(c.Request.Context(), discrepancyParams(run.ID, item))
+		if err != nil {
+			h.logError("create reconciliation discrepancy failed", err)
+			apierrors.Write(c, http.StatusInternalServerError, apierrors.APIError{Code: "internal_error", Message: "failed to save reconciliation"})
+			return
+		}
+		discrepancies = append(discrepancies, saved)
+	}
+
+	c.JSON(http.StatusCreated, adminReconciliationDetailFromModel(run, discrepancies))
+}
+
+func (h *Handler) GetAdminPaymentsReconciliations(c *gin.Context) {
+	if _, _, ok := h.requirePermission(c, authz.PermissionPaymentRead, authz.ScopeAll); !ok {
+		return
+	}
+	if h.Store == nil {
+		apierrors.Write(c, http.StatusInternalServerError, apierrors.APIError{Code: "internal_error", Message: "payment store is not configured"})
+		return
+	}
+
+	page, pageSize := parsePageParams(c)
+	offset := (page - 1) * pageSize
+	channel := normalizeNullableText(strings.ToUpper(normalizeKeyword(c.Query("channel"))))
+
+	runs, err := h.Store.ListReconciliationRuns(c.Request.Context(), db.ListReconciliationRunsParams{
+		Channel: channel,
+		Offset:  int32(offset),
+		Limit:   int32(pageSize),
+	})
+	if err != nil {
+		h.logError("list reconciliation runs failed", err)
+		apierrors.Write(c, http.StatusInternalServerError, apierrors.APIError{Code: "internal_error", Message: "failed to list reconciliations"})
+		return
+	}
+	total, err := h.Store.CountReconciliationRuns(c.Request.Context(), channel)
+	if err != nil {
+		h.logError("count reconciliation runs failed", err)
+		apierrors.Write(c, http.StatusInternalServerError, apierrors.APIError{Code: "internal_error", Message: "failed to list reconciliations"})
+		return
+	}
+
+	items := make([]adminReconciliationRun, 0, len(runs))
+	for _, run := range runs {
+		items = append(items, adminReconciliationRunFromModel(run))
+	}
+	c.JSON(http.StatusOK, pagedAdminReconciliationRuns{
+		Items:    items,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    int(total),
+	})
+}
+
+func (h *Handler) GetAdminPaymentsReconciliationsId(c *gin.Context) {
+	if _, _, ok := h.requirePermission(c, authz.PermissionPaymentRead, authz.ScopeAll); !ok {
+		return
+	}
+	if h.Store == nil {
+		apierrors.Write(c, http.StatusInternalServerError, apierrors.APIError{Code: "internal_error", Message: "payment store is not configured"})
+		return
+	}
+	runID, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: "id is required"})
+		return
+	}
+	run, err := h.Store.GetReconciliationRun(c.Request.Context(), runID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			apierrors.Write(c, http.StatusNotFound, apierrors.APIError{Code: "not_found", Message: "reconciliation not found"})
+			return
+		}
+		h.logError("get reconciliation run failed", err)
+		apierrors.Write(c, http.StatusInternalServerError, apierrors.APIError{Code: "internal_error", Message: "failed to load reconciliation"})
+		return
+	}
+	discrepancies, err := h.Store.ListReconciliationDiscrepancies(c.Request.Context(), run.ID)
+	if err != nil {
+		h.logError("list reconciliation discrepancies failed", err)
+		apierrors.Write(c, http.StatusInternalServerError, apierrors.APIError{Code: "internal_error", Message: "failed to load reconciliation"})
+		return
+	}
+	c.JSON(http.StatusOK, adminReconciliationDetailFromModel(run, discrepancies))
+}
+
+// reconciliationLedger collects the payments a statement is compared with: the
+// payments of the channel paid during the statement day, plus the payments of
+// statement orders that were not paid that day, so late or missing notifies
+// show up as status mismatches rather than missing payments.
+func (h *Handler) reconciliationLedger(ctx context.Context, channel string, statementDate time.Time, lines []reconcile.StatementLine) ([]reconcile.LedgerEntry, error) {
+	paid, err := h.Store.ListPaidPaymentsByChannel(ctx, db.ListPaidPaymentsByChannelParams{
+		Channel:  channel,
+		PaidFrom: pgtype.Timestamptz{Time: statementDate, Valid: true},
+		PaidTo:   pgtype.Timestamptz{Time: statementDate.AddDate(0, 0, 1), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	ledger := make([]reconcile.LedgerEntry, 0, len(paid))
+	seen := make(map[uuid.UUID]bool, len(paid))
+	known := make(map[string]bool, len(paid))
+	for _, payment := range paid {
+		ledger = append(ledger, ledgerEntryFromModel(payment))
+		seen[payment.ID] = true
+		known[paymentOutTradeNo(payment)] = true
+	}
+	for _, line := range lines {
+		if known[line.OutTradeNo] {
+			continue
+		}
+		known[line.OutTradeNo] = true
+		var payments []db.Payment
+		if orderID, err := uuid.Parse(line.OutTradeNo); err == nil {
+			payments, err = h.Store.ListPaymentsByOrder(ctx, orderID)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			outTradeNo := line.OutTradeNo
+			payments, err = h.Store.ListPaymentsByOutTradeNo(ctx, &outTradeNo)
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, payment := range payments {
+			if seen[payment.ID] || payment.Channel != channel {
+				continue
+			}
+			seen[payment.ID] = true
+			ledger = append(ledger, ledgerEntryFromModel(payment))
+		}
+	}
+	return ledger, nil
+}
+
+func ledgerEntryFromModel(payment db.Payment) reconcile.LedgerEntry {
+	entry := reconcile.LedgerEntry{
+		PaymentID:  payment.ID,
+		OutTradeNo: paymentOutTradeNo(payment),
+		AmountFen:  payment.AmountFen,
+		Status:     payment.Status,
+	}
+	if payment.ProviderTradeNo != nil {
+		entry.ProviderTradeNo = *payment.ProviderTradeNo
+	}
+	return entry
+}
+
+// paymentOutTradeNo is the merchant order number the channel knows the
+// payment by.
+func paymentOutTradeNo(payment db.Payment) string {
+	if payment.OutTradeNo != nil && *payment.OutTradeNo != "" {
+		return *payment.OutTradeNo
+	}
+	return payment.OrderID.String()
+}
+
+func discrepancyParams(runID uuid.UUID, item reconcile.Discrepancy) db.CreateReconciliationDiscrepancyParams {
+	params := db.CreateReconciliationDiscrepancyParams{
+		RunID:              runID,
+		Kind:               item.Kind,
+		OutTradeNo:         normalizeNullableText(item.OutTradeNo),
+		ProviderTradeNo:    normalizeNullableText(item.ProviderTradeNo),
+		LedgerAmountFen:    item.LedgerAmountFen,
+		StatementAmountFen: item.StatementAmountFen,
+		LedgerStatus:       normalizeNullableText(item.LedgerStatus),
+		StatementStatus:    normalizeNullableText(item.StatementStatus),
+	}
+	if item.PaymentID != uuid.Nil {
+		params.PaymentID = pgtype.UUID{Bytes: item.PaymentID, Valid: true}
+	}
+	return params
+}
+
+func adminReconciliationRunFromModel(run db.ReconciliationRun) adminReconciliationRun {
+	statementDate := ""
+	if run.StatementDate.Valid {
+		statementDate = run.StatementDate.Time.Format(time.DateOnly)
+	}
+	return adminReconciliationRun{
+		ID:               run.ID.String(),
+		Channel:          run.Channel,
+		StatementDate:    statementDate,
+		FileName:         run.FileName,
+		StatementLines:   int(run.StatementLines),
+		MatchedCount:     int(run.MatchedCount),
+		DiscrepancyCount: int(run.DiscrepancyCount),
+		CreatedBy:        run.CreatedBy,
+		CreatedAt:        timestampString(run.CreatedAt),
+	}
+}
+
+func adminReconciliationDetailFromModel(run db.ReconciliationRun, discrepancies []db.ReconciliationDiscrepancy) adminReconciliationDetail {
+	items := make([]adminReconciliationDiscrepancy, 0, len(discrepancies))
+	for _, item := range discrepancies {
+		payload := adminReconciliationDiscrepancy{
+			ID:                 item.ID.String(),
+			Kind:               item.Kind,
+			OutTradeNo:         nullableString(item.OutTradeNo),
+			ProviderTradeNo:    nullableString(item.ProviderTradeNo),
+			LedgerAmountFen:    item.LedgerAmountFen,
+			StatementAmountFen: item.StatementAmountFen,
+			LedgerStatus:       nullableString(item.LedgerStatus),
+			StatementStatus:    nullableString(item.StatementStatus),
+		}
+		if item.PaymentID.Valid {
+			payload.TransactionID = uuid.UUID(item.PaymentID.Bytes).String()
+		}
+		items = append(items, payload)
+	}
+	return adminReconciliationDetail{
+		adminReconciliationRun: adminReconciliationRunFromModel(run),
+		Discrepancies:          items,
+	}
+}
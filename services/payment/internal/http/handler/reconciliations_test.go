@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/payment/internal/db"
+	"github.com/teamdsb/tmo/services/payment/internal/modules/reconcile"
+)
+
+type queryProviderStub struct {
+	MockProvider
+	results map[uuid.UUID]QueryResult
+}
+
+func (s queryProviderStub) Query(_ context.Context, payment ProviderPayment) (QueryResult, error) {
+	result, ok := s.results[payment.PaymentID]
+	if !ok {
+		return QueryResult{}, ErrProviderUnsupported
+	}
+	return result, nil
+}
+
+func TestSettlePendingPaymentsQueriesEveryBatch(t *testing.T) {
+	store := newPaymentStoreStub()
+	base := time.Now().UTC().Add(-time.Hour)
+	createPending := func(offset time.Duration) db.Payment {
+		payment, _ := store.CreatePayment(context.Background(), db.CreatePaymentParams{OrderID: uuid.New(), Channel: paymentChannelWechat, Status: paymentStatusPending, AmountFen: 1000, Currency: "CNY"})
+		payment.CreatedAt = pgtype.Timestamptz{Time: base.Add(offset), Valid: true}
+		store.payments[payment.ID] = payment
+		return payment
+	}
+	unknown := createPending(0)
+	stillPending := createPending(time.Minute)
+	paid := createPending(2 * time.Minute)
+	recent := createPending(time.Hour)
+
+	tradeNo := "4200000001"
+	h := &Handler{Store: store, Providers: Providers{paymentChannelWechat: queryProviderStub{results: map[uuid.UUID]QueryResult{
+		stillPending.ID: {Status: paymentStatusPending},
+		paid.ID:         {Status: paymentStatusPaid, ProviderTradeNo: &tradeNo},
+		recent.ID:       {Status: paymentStatusPaid},
+	}}}}
+
+	settled, err := h.SettlePendingPayments(context.Background(), base.Add(30*time.Minute), 1)
+	if err != nil {
+		t.Fatalf("settle pending payments: %v", err)
+	}
+	if settled != 1 {
+		t.Fatalf("expected one settled payment, got %d", settled)
+	}
+	if got := store.payments[paid.ID]; got.Status != paymentStatusPaid || got.ProviderTradeNo == nil || *got.ProviderTradeNo != tradeNo {
+		t.Fatalf("expected payment to be paid with the channel trade number, got %#v", got)
+	}
+	for _, payment := range []db.Payment{unknown, stillPending, recent} {
+		if got := store.payments[payment.ID].Status; got != paymentStatusPending {
+			t.Fatalf("expected payment %s to stay pending, got %s", payment.ID, got)
+		}
+	}
+}
+
+func TestSettlePendingPaymentsPagesPastSharedCreationTimes(t *testing.T) {
+	store := newPaymentStoreStub()
+	createdAt := pgtype.Timestamptz{Time: time.Now().UTC().Add(-time.Hour), Valid: true}
+	tradeNo := "4200000002"
+	results := map[uuid.UUID]QueryResult{}
+	for range 3 {
+		payment, _ := store.CreatePayment(context.Background(), db.CreatePaymentParams{OrderID: uuid.New(), Channel: paymentChannelWechat, Status: paymentStatusPending, AmountFen: 1000, Currency: "CNY"})
+		payment.CreatedAt = createdAt
+		store.payments[payment.ID] = payment
+		results[payment.ID] = QueryResult{Status: paymentStatusPaid, ProviderTradeNo: &tradeNo}
+	}
+
+	h := &Handler{Store: store, Providers: Providers{paymentChannelWechat: queryProviderStub{results: results}}}
+	settled, err := h.SettlePendingPayments(context.Background(), createdAt.Time.Add(time.Minute), 1)
+	if err != nil {
+		t.Fatalf("settle pending payments: %v", err)
+	}
+	if settled != len(results) {
+		t.Fatalf("expected every payment sharing a creation time to settle, got %d of %d", settled, len(results))
+	}
+}
+
+func TestPostAdminPaymentsReconciliationsReportsDiscrepancies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newPaymentStoreStub()
+	paidAt := time.Date(2026, 3, 1, 10, 0, 0, 0, statementLocation)
+	createPayment := func(status string, amountFen int64, tradeNo string) db.Payment {
+		params := db.CreatePaymentParams{OrderID: uuid.New(), Channel: paymentChannelAlipay, Status: status, AmountFen: amountFen, Currency: "CNY"}
+		if status == paymentStatusPaid {
+			params.PaidAt = pgtype.Timestamptz{Time: paidAt, Valid: true}
+			params.ProviderTradeNo = &tradeNo
+		}
+		payment, _ := store.CreatePayment(context.Background(), params)
+		return payment
+	}
+	matched := createPayment(paymentStatusPaid, 1000, "2026030122001")
+	shortPaid := createPayment(paymentStatusPaid, 2000, "2026030122002")
+	missing := createPayment(paymentStatusPaid, 3000, "2026030122003")
+	pending := createPayment(paymentStatusPending, 4000, "")
+	// Paid the next day, so it belongs to the next statement.
+	nextDay := createPayment(paymentStatusPaid, 5000, "2026030222005")
+	nextDay.PaidAt = pgtype.Timestamptz{Time: paidAt.AddDate(0, 0, 1), Valid: true}
+	store.payments[nextDay.ID] = nextDay
+	unknownOrder := uuid.New()
+
+	statement := "#支付宝业务明细查询\n" +
+		"#账号：[20880000000000000156]\n" +
+		"支付宝交易号,商户订单号,业务类型,商品名称,订单金额（元）\n" +
+		"2026030122001," + matched.OrderID.String() + ",交易,直采订单,10.00\n" +
+		"2026030122002," + shortPaid.OrderID.String() + ",交易,直采订单,19.00\n" +
+		"2026030122004," + pending.OrderID.String() + ",交易,直采订单,40.00\n" +
+		"2026030122006," + unknownOrder.String() + ",交易,直采订单,60.00\n" +
+		"2026030122001," + matched.OrderID.String() + ",退款,直采订单,10.00\n" +
+		"#-----------------------------------------业务明细列表结束------------------------------------\n"
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	_ = writer.WriteField("channel", "alipay")
+	_ = writer.WriteField("statementDate", "2026-03-01")
+	part, _ := writer.CreateFormFile("file", "20880000000000000156_20260301.csv")
+	_, _ = part.Write([]byte(statement))
+	_ = writer.Close()
+
+	router := newTestRouter(&Handler{Store: store})
+	req := httptest.NewRequest(http.MethodPost, "/admin/payments/reconciliations", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var created adminReconciliationDetail
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if created.Channel != paymentChannelAlipay || created.StatementDate != "2026-03-01" || created.StatementLines != 5 || created.MatchedCount != 1 || created.DiscrepancyCount != 4 {
+		t.Fatalf("unexpected run: %#v", created.adminReconciliationRun)
+	}
+	kinds := map[string]adminReconciliationDiscrepancy{}
+	for _, item := range created.Discrepancies {
+		kinds[item.Kind] = item
+	}
+	if item := kinds[reconcile.KindAmountMismatch]; item.TransactionID != shortPaid.ID.String() || *item.LedgerAmountFen != 2000 || *item.StatementAmountFen != 1900 {
+		t.Fatalf("unexpected amount mismatch: %#v", item)
+	}
+	if item := kinds[reconcile.KindStatusMismatch]; item.TransactionID != pending.ID.String() || item.LedgerStatus != paymentStatusPending || item.StatementStatus != reconcile.StatementStatusPaid {
+		t.Fatalf("unexpected status mismatch: %#v", item)
+	}
+	if item := kinds[reconcile.KindMissingInStatement]; item.TransactionID != missing.ID.String() {
+		t.Fatalf("unexpected missing in statement: %#v", item)
+	}
+	if item := kinds[reconcile.KindMissingInLedger]; item.OutTradeNo != unknownOrder.String() || item.TransactionID != "" || *item.StatementAmountFen != 6000 {
+		t.Fatalf("unexpected missing in ledger: %#v", item)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/payments/reconciliations/"+created.ID, nil)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	var detail adminReconciliationDetail
+	if err := json.Unmarshal(rec.Body.Bytes(), &detail); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("expected stored detail, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(detail.Discrepancies) != 4 {
+		t.Fatalf("expected 4 stored discrepancies, got %d", len(detail.Discrepancies))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/admin/payments/reconciliations", nil)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	var page pagedAdminReconciliationRuns
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil || page.Total != 1 || len(page.Items) != 1 || page.Items[0].ID != created.ID {
+		t.Fatalf("unexpected reconciliation list: %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestPostAdminPaymentsReconciliationsRejectsInvalidInput(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := newTestRouter(&Handler{Store: newPaymentStoreStub()})
+	post := func(channel, date, content string) int {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		_ = writer.WriteField("channel", channel)
+		_ = writer.WriteField("statementDate", date)
+		part, _ := writer.CreateFormFile("file", "statement.csv")
+		_, _ = part.Write([]byte(content))
+		_ = writer.Close()
+		req := httptest.NewRequest(http.MethodPost, "/admin/payments/reconciliations", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := post("PAYPAL", "2026-03-01", "orderId,amountFen\n"); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown channel, got %d", code)
+	}
+	if code := post("WECHAT", "03/01/2026", "orderId,amountFen\n"); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid date, got %d", code)
+	}
+	if code := post("WECHAT", "2026-03-01", "foo,bar\n1,2\n"); code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for a statement without a header, got %d", code)
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// RefundProvider submits a refund to the channel that collected the payment.
+// A provider either settles the refund right away or reports it as pending
+// and delivers the outcome later through the channel's refund notify.
+// Providers must treat RefundID as the merchant refund number so that a
+// resubmitted refund is deduplicated by the channel.
+type RefundProvider interface {
+	Refund(ctx context.Context, request RefundRequest) (RefundResult, error)
+}
+
+type RefundRequest struct {
+	RefundID        uuid.UUID
+	PaymentID       uuid.UUID
+	OrderID         uuid.UUID
+	OutTradeNo      *string
+	ProviderTradeNo *string
+	TotalFen        int64
+	AmountFen       int64
+	Reason          string
+}
+
+type RefundResult struct {
+	Status           string
+	ProviderRefundNo *string
+	FailureMessage   *string
+}
+
+// normalizeRefundStatus maps channel refund states onto refund statuses.
+func normalizeRefundStatus(status string) string {
+	switch strings.ToUpper(strings.TrimSpace(status)) {
+	case "SUCCESS", refundStatusSucceeded, "REFUND_SUCCESS":
+		return refundStatusSucceeded
+	case "PROCESSING", refundStatusPending, "REFUND_PROCESSING", "INIT":
+		return refundStatusPending
+	default:
+		return refundStatusFailed
+	}
+}
+
+func signSHA256WithRSA(content string, key *rsa.PrivateKey) (string, error) {
+	hash := sha256.Sum256([]byte(content))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+func parseRSAPrivateKey(pemData string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemData))
+	if block == nil {
+		return nil, errors.New("invalid private key")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err == nil {
+		if rsaKey, ok := key.(*rsa.PrivateKey); ok {
+			return rsaKey, nil
+		}
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
@@ -0,0 +1,509 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
+	shareddb "github.com/teamdsb/tmo/packages/go-shared/db"
+	apierrors "github.com/teamdsb/tmo/packages/go-shared/errors"
+	"github.com/teamdsb/tmo/services/payment/internal/db"
+)
+
+const (
+	refundStatusPending   = "PENDING"
+	refundStatusSucceeded = "SUCCEEDED"
+	refundStatusFailed    = "FAILED"
+)
+
+var refundablePaymentStatuses = []string{paymentStatusPaid, paymentStatusPartiallyRefunded}
+
+type adminRefundRequest struct {
+	AmountFen *int64  `json:"amountFen"`
+	Reason    *string `json:"reason"`
+}
+
+type adminPaymentRefund struct {
+	ID               string `json:"id"`
+	TransactionID    string `json:"transactionId"`
+	OrderID          string `json:"orderId"`
+	Channel          string `json:"channel"`
+	Status           string `json:"status"`
+	AmountFen        int64  `json:"amountFen"`
+	Reason           string `json:"reason,omitempty"`
+	ProviderRefundNo string `json:"providerRefundNo,omitempty"`
+	FailureMessage   string `json:"failureMessage,omitempty"`
+	RequestedBy      string `json:"requestedBy"`
+	RefundedAt       string `json:"refundedAt,omitempty"`
+	CreatedAt        string `json:"createdAt"`
+	UpdatedAt        string `json:"updatedAt"`
+}
+
+type adminPaymentRefundList struct {
+	Items []adminPaymentRefund `json:"items"`
+}
+
+type normalizedRefundNotifyPayload struct {
+	RefundID         string
+	Status           string
+	ProviderRefundNo *string
+	FailureMessage   *string
+	EventType        string
+}
+
+func (h *Handler) GetAdminPaymentsTransactionsIdRefunds(c *gin.Context) {
+	if _, _, ok := h.requirePermission(c, authz.PermissionPaymentRead, authz.ScopeAll); !ok {
+		return
+	}
+	paymentID, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: "id is required"})
+		return
+	}
+	payment, err := h.loadPayment(c, paymentID)
+	if err != nil {
+		h.writePaymentError(c, err)
+		return
+	}
+	refunds, err := h.Store.ListRefundsByPayment(c.Request.Context(), payment.ID)
+	if err != nil {
+		h.logError("list payment refunds failed", err)
+		apierrors.Write(c, http.StatusInternalServerError, apierrors.APIError{Code: "internal_error", Message: "failed to list payment refunds"})
+		return
+	}
+	items := make([]adminPaymentRefund, 0, len(refunds))
+	for _, refund := range refunds {
+		items = append(items, adminRefundFromModel(refund))
+	}
+	c.JSON(http.StatusOK, adminPaymentRefundList{Items: items})
+}
+
+// PostAdminPaymentsTransactionsIdRefunds refunds all or part of a paid
+// payment. The requested amount is reserved on the payment before the
+// provider is called, and the Idempotency-Key doubles as the retry handle:
+// replaying it resubmits a refund the provider has not acknowledged yet.
+func (h *Handler) PostAdminPaymentsTransactionsIdRefunds(c *gin.Context) {
+	claims, _, ok := h.requirePermission(c, authz.PermissionPaymentManage, authz.ScopeAll)
+	if !ok {
+		return
+	}
+	paymentID, err := uuid.Parse(strings.TrimSpace(c.Param("id")))
+	if err != nil {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: "id is required"})
+		return
+	}
+	idempotencyKey := strings.TrimSpace(c.GetHeader("Idempotency-Key"))
+	if idempotencyKey == "" {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: "Idempotency-Key header is required"})
+		return
+	}
+	var request adminRefundRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: "invalid request body"})
+			return
+		}
+	}
+
+	actor := "admin"
+	if claims.UserID != uuid.Nil {
+		actor = claims.UserID.String()
+	}
+	refund, created, err := h.createRefund(c, paymentID, idempotencyKey, request, actor)
+	if err != nil {
+		h.writePaymentError(c, err)
+		return
+	}
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, adminRefundFromModel(refund))
+}
+
+func (h *Handler) createRefund(c *gin.Context, paymentID uuid.UUID, idempotencyKey string, request adminRefundRequest, actor string) (db.Refund, bool, error) {
+	payment, err := h.loadPayment(c, paymentID)
+	if err != nil {
+		return db.Refund{}, false, err
+	}
+	provider, ok := h.Providers.Lookup(payment.Channel)
+	if !ok {
+		return db.Refund{}, false, errConflict(fmt.Sprintf("refunds are not configured for %s", strings.ToLower(payment.Channel)))
+	}
+
+	existing, err := h.Store.GetRefundByIdempotencyKey(c.Request.Context(), db.GetRefundByIdempotencyKeyParams{PaymentID: payment.ID, IdempotencyKey: idempotencyKey})
+	if err == nil {
+		if existing.Status != refundStatusPending || existing.ProviderRefundNo != nil {
+			return existing, false, nil
+		}
+		refund, err := h.submitRefund(c, provider, payment, existing, actor)
+		return refund, false, err
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return db.Refund{}, false, errInternal("check refund idempotency failed")
+	}
+
+	if !isRefundablePaymentStatus(payment.Status) {
+		return db.Refund{}, false, errConflict("payment is not refundable")
+	}
+	refundable := payment.AmountFen - payment.RefundedFen
+	amount := refundable
+	if request.AmountFen != nil {
+		amount = *request.AmountFen
+	}
+	if amount <= 0 {
+		return db.Refund{}, false, errBadRequest("amountFen must be positive")
+	}
+	if amount > refundable {
+		return db.Refund{}, false, errConflict(fmt.Sprintf("refund amount exceeds the refundable balance of %d fen", refundable))
+	}
+
+	refund, err := h.Store.CreateRefund(c.Request.Context(), db.CreateRefundParams{
+		AmountFen:          amount,
+		PaymentID:          payment.ID,
+		RefundableStatuses: refundablePaymentStatuses,
+		Status:             refundStatusPending,
+		Reason:             normalizeOptionalString(request.Reason),
+		IdempotencyKey:     idempotencyKey,
+		RequestedBy:        actor,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Refund{}, false, errConflict("refund amount exceeds the refundable balance")
+		}
+		// A concurrent request with the same key won the unique index.
+		if existing, lookupErr := h.Store.GetRefundByIdempotencyKey(c.Request.Context(), db.GetRefundByIdempotencyKeyParams{PaymentID: payment.ID, IdempotencyKey: idempotencyKey}); lookupErr == nil {
+			return existing, false, nil
+		}
+		return db.Refund{}, false, errInternal("create refund failed")
+	}
+	if err := h.recordAudit(c.Request.Context(), payment.ID, "refund_requested", actor, fmt.Sprintf("refund %s of %d fen requested", refund.ID, refund.AmountFen)); err != nil {
+		h.logError("create payment audit log failed", err)
+	}
+
+	refund, err = h.submitRefund(c, provider, payment, refund, actor)
+	return refund, true, err
+}
+
+// submitRefund calls the provider and applies its answer. A transport error
+// keeps the refund pending with its amount reserved, because the provider may
+// have accepted it; the caller retries with the same idempotency key.
+func (h *Handler) submitRefund(c *gin.Context, provider RefundProvider, payment db.Payment, refund db.Refund, actor string) (db.Refund, error) {
+	reason := ""
+	if refund.Reason != nil {
+		reason = *refund.Reason
+	}
+	result, err := provider.Refund(c.Request.Context(), RefundRequest{
+		RefundID:        refund.ID,
+		PaymentID:       payment.ID,
+		OrderID:         payment.OrderID,
+		OutTradeNo:      payment.OutTradeNo,
+		ProviderTradeNo: payment.ProviderTradeNo,
+		TotalFen:        payment.AmountFen,
+		AmountFen:       refund.AmountFen,
+		Reason:          reason,
+	})
+	if err != nil {
+		h.logError("submit refund failed", err)
+		return db.Refund{}, paymentHTTPError{status: http.StatusBadGateway, code: "provider_error", message: "refund provider request failed; retry with the same Idempotency-Key"}
+	}
+	return h.settleRefund(c.Request.Context(), refund, result, actor)
+}
+
+// settleRefund stores the outcome of a pending refund. Once a refund succeeds
+// the payment becomes PARTIALLY_REFUNDED or REFUNDED and commerce is told; a
+// failed refund releases its reserved amount. Outcomes for a refund that is no
+// longer pending are ignored so repeated notifies are harmless.
+func (h *Handler) settleRefund(ctx context.Context, refund db.Refund, result RefundResult, actor string) (db.Refund, error) {
+	status := normalizeRefundStatus(result.Status)
+	if status == refundStatusPending {
+		if result.ProviderRefundNo == nil || refund.ProviderRefundNo != nil {
+			return refund, nil
+		}
+		updated, err := h.Store.UpdateRefundState(ctx, db.UpdateRefundStateParams{
+			Status: refundStatusPending, ProviderRefundNo: result.ProviderRefundNo, ID: refund.ID, FromStatus: refundStatusPending,
+		})
+		if err != nil {
+			return h.reloadSettledRefund(ctx, refund, err)
+		}
+		return updated, nil
+	}
+
+	refundedAt := pgtype.Timestamptz{}
+	if status == refundStatusSucceeded {
+		refundedAt = pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true}
+	}
+	// The refund status, the reservation and the payment status move
+	// together; a crash between them would leave the balance out of step.
+	var updated db.Refund
+	var payment db.Payment
+	paymentChanged, published := false, false
+	err := h.withStoreTx(ctx, func(store PaymentStore, tx pgx.Tx) error {
+		var err error
+		updated, err = store.UpdateRefundState(ctx, db.UpdateRefundStateParams{
+			Status:           status,
+			ProviderRefundNo: result.ProviderRefundNo,
+			FailureMessage:   result.FailureMessage,
+			RefundedAt:       refundedAt,
+			ID:               refund.ID,
+			FromStatus:       refundStatusPending,
+		})
+		if err != nil {
+			return err
+		}
+		if status == refundStatusFailed {
+			if _, err := store.ReleaseRefundReservation(ctx, db.ReleaseRefundReservationParams{AmountFen: updated.AmountFen, ID: updated.PaymentID}); err != nil {
+				return errInternal("release refund reservation failed")
+			}
+			return nil
+		}
+		payment, paymentChanged, err = h.markPaymentRefunded(ctx, store, updated.PaymentID)
+		if err != nil || !paymentChanged || tx == nil || h.Outbox == nil {
+			return err
+		}
+		published = true
+		return h.publishPaymentStatus(ctx, tx, payment)
+	})
+	if err != nil {
+		var httpErr paymentHTTPError
+		if errors.As(err, &httpErr) {
+			return db.Refund{}, err
+		}
+		return h.reloadSettledRefund(ctx, refund, err)
+	}
+
+	if status == refundStatusFailed {
+		if err := h.recordAudit(ctx, updated.PaymentID, "refund_failed", actor, fmt.Sprintf("refund %s failed: %s", updated.ID, nullableString(updated.FailureMessage))); err != nil {
+			h.logError("create payment audit log failed", err)
+		}
+		return updated, nil
+	}
+
+	if paymentChanged && !published {
+		h.syncRefundedPayment(ctx, payment)
+	}
+	if err := h.recordAudit(ctx, updated.PaymentID, "refunded", actor, fmt.Sprintf("refund %s of %d fen succeeded", updated.ID, updated.AmountFen)); err != nil {
+		h.logError("create payment audit log failed", err)
+	}
+	return updated, nil
+}
+
+// withStoreTx runs fn against a store bound to one transaction. Without a
+// database pool fn gets the handler's store and a nil transaction.
+func (h *Handler) withStoreTx(ctx context.Context, fn func(store PaymentStore, tx pgx.Tx) error) error {
+	if h.DB == nil {
+		return fn(h.Store, nil)
+	}
+	return shareddb.WithTx(ctx, h.DB, func(tx pgx.Tx) error {
+		return fn(db.New(tx), tx)
+	})
+}
+
+func (h *Handler) reloadSettledRefund(ctx context.Context, refund db.Refund, err error) (db.Refund, error) {
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return db.Refund{}, errInternal("update refund failed")
+	}
+	current, err := h.Store.GetRefund(ctx, refund.ID)
+	if err != nil {
+		return db.Refund{}, errInternal("load refund failed")
+	}
+	return current, nil
+}
+
+// markPaymentRefunded moves the payment to PARTIALLY_REFUNDED or REFUNDED
+// from its succeeded refunds, and reports whether its status changed.
+func (h *Handler) markPaymentRefunded(ctx context.Context, store PaymentStore, paymentID uuid.UUID) (db.Payment, bool, error) {
+	payment, err := store.GetPayment(ctx, paymentID)
+	if err != nil {
+		return db.Payment{}, false, errInternal("load payment failed")
+	}
+	refunded, err := store.SumSucceededRefunds(ctx, paymentID)
+	if err != nil {
+		return db.Payment{}, false, errInternal("sum refunds failed")
+	}
+	status := paymentStatusPartiallyRefunded
+	if refunded >= payment.AmountFen {
+		status = paymentStatusRefunded
+	}
+	if payment.Status == status {
+		return payment, false, nil
+	}
+	updated, err := store.UpdatePaymentState(ctx, db.UpdatePaymentStateParams{
+		ID:               payment.ID,
+		Status:           status,
+		ProviderTradeNo:  payment.ProviderTradeNo,
+		ProviderPrepayID: payment.ProviderPrepayID,
+		ProviderPayload:  payment.ProviderPayload,
+		FailureCode:      payment.FailureCode,
+		FailureMessage:   payment.FailureMessage,
+		PaidAt:           payment.PaidAt,
+		ClosedAt:         payment.ClosedAt,
+	})
+	if err != nil {
+		return db.Payment{}, false, errInternal("update payment failed")
+	}
+	return updated, true, nil
+}
+
+// syncRefundedPayment pushes a refunded payment to commerce when no outbox
+// carried the change. The money has already moved, so a failed sync is only
+// logged; replaying the refund webhook from the admin console pushes the
+// status again.
+func (h *Handler) syncRefundedPayment(ctx context.Context, payment db.Payment) {
+	if h.Commerce == nil {
+		return
+	}
+	var paidAt *time.Time
+	if payment.PaidAt.Valid {
+		value := payment.PaidAt.Time
+		paidAt = &value
+	}
+	if err := h.Commerce.SyncOrderPayment(ctx, payment.OrderID.String(), CommercePaymentSyncRequest{
+		PaymentID:       payment.ID.String(),
+		Channel:         payment.Channel,
+		Status:          payment.Status,
+		ProviderTradeNo: payment.ProviderTradeNo,
+		PaidAt:          paidAt,
+	}); err != nil {
+		h.logError("sync order refund status failed", err)
+	}
+}
+
+func (h *Handler) PostPaymentsWechatRefundNotify(c *gin.Context) {
+	h.handleRefundNotify(c, paymentChannelWechat)
+}
+
+func (h *Handler) PostPaymentsAlipayRefundNotify(c *gin.Context) {
+	h.handleRefundNotify(c, paymentChannelAlipay)
+}
+
+func (h *Handler) handleRefundNotify(c *gin.Context, channel string) {
+	verified, ok := h.verifyNotify(c, channel)
+	if !ok {
+		return
+	}
+	payload := verified.payload
+	normalized, err := normalizeRefundNotifyPayload(payload)
+	if err != nil {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: err.Error()})
+		return
+	}
+	refundID, err := uuid.Parse(normalized.RefundID)
+	if err != nil {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: "invalid refundId"})
+		return
+	}
+	if h.Store == nil {
+		h.writePaymentError(c, errInternal("payment store is not configured"))
+		return
+	}
+	refund, err := h.Store.GetRefund(c.Request.Context(), refundID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			h.writePaymentError(c, errNotFound("refund not found"))
+			return
+		}
+		h.writePaymentError(c, errInternal("load refund failed"))
+		return
+	}
+	if !notifyChannelAccepts(channel, refund.Channel) {
+		apierrors.Write(c, http.StatusBadRequest, apierrors.APIError{Code: "invalid_request", Message: "refund belongs to another channel"})
+		return
+	}
+
+	rawBody, _ := json.Marshal(payload)
+	if !verified.by(refund.Channel) {
+		reason := fmt.Sprintf("notify was not verified by the %s provider", strings.ToLower(refund.Channel))
+		h.recordRejectedNotify(c.Request.Context(), channel, toNullableUUID(refund.PaymentID), rawBody, reason)
+		apierrors.Write(c, http.StatusConflict, apierrors.APIError{Code: "notify_rejected", Message: reason})
+		return
+	}
+	eventType := strings.TrimSpace(normalized.EventType)
+	if eventType == "" {
+		eventType = "refund." + strings.ToLower(normalized.Status)
+	}
+	if _, err := h.Store.CreatePaymentWebhook(c.Request.Context(), db.CreatePaymentWebhookParams{
+		PaymentID:      toNullableUUID(refund.PaymentID),
+		Provider:       strings.ToLower(channel),
+		EventType:      eventType,
+		DeliveryStatus: normalized.Status,
+		RawBody:        rawBody,
+		ProcessedAt:    pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+	}); err != nil {
+		h.logError("create payment webhook failed", err)
+	}
+
+	updated, err := h.settleRefund(c.Request.Context(), refund, RefundResult{
+		Status:           normalized.Status,
+		ProviderRefundNo: normalized.ProviderRefundNo,
+		FailureMessage:   normalized.FailureMessage,
+	}, "system")
+	if err != nil {
+		h.writePaymentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, adminRefundFromModel(updated))
+}
+
+func normalizeRefundNotifyPayload(payload map[string]interface{}) (normalizedRefundNotifyPayload, error) {
+	refundID := strings.TrimSpace(readString(payload, "refundId", "out_refund_no", "out_request_no"))
+	if refundID == "" {
+		return normalizedRefundNotifyPayload{}, fmt.Errorf("refundId is required")
+	}
+	status := strings.TrimSpace(readString(payload, "status", "refund_status"))
+	if status == "" {
+		return normalizedRefundNotifyPayload{}, fmt.Errorf("status is required")
+	}
+	return normalizedRefundNotifyPayload{
+		RefundID:         refundID,
+		Status:           normalizeRefundStatus(status),
+		ProviderRefundNo: normalizeOptionalString(stringPointer(readString(payload, "providerRefundNo", "refund_id"))),
+		FailureMessage:   normalizeOptionalString(stringPointer(readString(payload, "failureMessage", "message"))),
+		EventType:        readString(payload, "eventType", "event_type"),
+	}, nil
+}
+
+func isRefundablePaymentStatus(status string) bool {
+	for _, candidate := range refundablePaymentStatuses {
+		if status == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+func isRefundedPaymentStatus(status string) bool {
+	return status == paymentStatusPartiallyRefunded || status == paymentStatusRefunded
+}
+
+func stringPointer(value string) *string {
+	return &value
+}
+
+func adminRefundFromModel(item db.Refund) adminPaymentRefund {
+	return adminPaymentRefund{
+		ID:               item.ID.String(),
+		TransactionID:    item.PaymentID.String(),
+		OrderID:          item.OrderID.String(),
+		Channel:          strings.ToLower(item.Channel),
+		Status:           strings.ToLower(item.Status),
+		AmountFen:        item.AmountFen,
+		Reason:           nullableString(item.Reason),
+		ProviderRefundNo: nullableString(item.ProviderRefundNo),
+		FailureMessage:   nullableString(item.FailureMessage),
+		RequestedBy:      item.RequestedBy,
+		RefundedAt:       timestampString(item.RefundedAt),
+		CreatedAt:        item.CreatedAt.Time.Format(time.RFC3339),
+		UpdatedAt:        item.UpdatedAt.Time.Format(time.RFC3339),
+	}
+}
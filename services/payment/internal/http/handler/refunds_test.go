@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/payment/internal/db"
+)
+
+type refundProviderStub struct {
+	MockProvider
+	results []RefundResult
+	err     error
+	calls   []RefundRequest
+}
+
+func (s *refundProviderStub) Refund(_ context.Context, request RefundRequest) (RefundResult, error) {
+	s.calls = append(s.calls, request)
+	if s.err != nil {
+		return RefundResult{}, s.err
+	}
+	result := s.results[0]
+	if len(s.results) > 1 {
+		s.results = s.results[1:]
+	}
+	return result, nil
+}
+
+func TestPostAdminPaymentsRefundsPartialThenFull(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	orderID := uuid.New()
+	store := newPaymentStoreStub()
+	payment, _ := store.CreatePayment(context.Background(), db.CreatePaymentParams{OrderID: orderID, Channel: paymentChannelAlipay, Status: paymentStatusPaid, AmountFen: 3000, Currency: "CNY"})
+	commerce := newCommerceServerStub(CommerceOrder{ID: orderID.String(), Status: "PAID", PaymentStatus: "PAID"})
+	defer commerce.Close()
+	router := newTestRouter(&Handler{Store: store, Commerce: NewCommerceClient(commerce.URL(), "sync-token"), Providers: Providers{paymentChannelAlipay: MockProvider{}}})
+
+	refund := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/payments/transactions/"+payment.ID.String()+"/refunds", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", key)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := refund("refund-1", `{"amountFen":1000,"reason":"damaged item"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := refund("refund-1", `{"amountFen":1000}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected replay to return 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := store.payments[payment.ID]; got.Status != paymentStatusPartiallyRefunded || got.RefundedFen != 1000 || len(store.refunds) != 1 {
+		t.Fatalf("expected one partial refund, got %s/%d with %d refunds", got.Status, got.RefundedFen, len(store.refunds))
+	}
+	if rec := refund("refund-2", `{"amountFen":2500}`); rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 when refunding more than the balance, got %d", rec.Code)
+	}
+
+	rec := refund("refund-3", "")
+	if rec.Code != http.StatusCreated || !strings.Contains(rec.Body.String(), `"amountFen":2000`) {
+		t.Fatalf("expected the remaining balance to be refunded, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := store.payments[payment.ID]; got.Status != paymentStatusRefunded || got.RefundedFen != 3000 {
+		t.Fatalf("expected payment to be fully refunded, got %s/%d", got.Status, got.RefundedFen)
+	}
+	if len(commerce.syncRequests) != 2 || commerce.syncRequests[0].Status != paymentStatusPartiallyRefunded || commerce.syncRequests[1].Status != paymentStatusRefunded {
+		t.Fatalf("unexpected sync requests: %#v", commerce.syncRequests)
+	}
+	if rec := refund("refund-4", `{"amountFen":1}`); rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for a fully refunded payment, got %d", rec.Code)
+	}
+}
+
+func TestPostAdminPaymentsRefundsRejectsUnpaidPayment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newPaymentStoreStub()
+	payment, _ := store.CreatePayment(context.Background(), db.CreatePaymentParams{OrderID: uuid.New(), Channel: paymentChannelWechat, Status: paymentStatusPending, AmountFen: 100, Currency: "CNY"})
+	router := newTestRouter(&Handler{Store: store, Providers: mockProviders()})
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/payments/transactions/"+payment.ID.String()+"/refunds", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without Idempotency-Key, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/admin/payments/transactions/"+payment.ID.String()+"/refunds", nil)
+	req.Header.Set("Idempotency-Key", "refund-1")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusConflict || len(store.refunds) != 0 {
+		t.Fatalf("expected 409 for a pending payment, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestRefundNotifySettlesPendingRefunds(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	orderID := uuid.New()
+	store := newPaymentStoreStub()
+	payment, _ := store.CreatePayment(context.Background(), db.CreatePaymentParams{OrderID: orderID, Channel: paymentChannelWechatB2B, Status: paymentStatusPaid, AmountFen: 5000, Currency: "CNY", OutTradeNo: strPtr("TMO20261017-000123")})
+	commerce := newCommerceServerStub(CommerceOrder{ID: orderID.String(), Status: "PAID", PaymentStatus: "PAID"})
+	defer commerce.Close()
+	provider := &refundProviderStub{results: []RefundResult{{Status: refundStatusPending, ProviderRefundNo: strPtr("wx-refund-1")}}}
+	router := newTestRouter(&Handler{Store: store, Commerce: NewCommerceClient(commerce.URL(), "sync-token"), Providers: Providers{paymentChannelWechatB2B: provider, paymentChannelAlipay: MockProvider{}}})
+
+	createRefund := func(key string) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/payments/transactions/"+payment.ID.String()+"/refunds", strings.NewReader(`{"amountFen":2000}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Idempotency-Key", key)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusCreated || !strings.Contains(rec.Body.String(), `"status":"pending"`) {
+			t.Fatalf("expected a pending refund, got %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+	notify := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/payments/wechat/refund-notify", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	createRefund("refund-1")
+	createRefund("refund-2")
+	refunds, _ := store.ListRefundsByPayment(context.Background(), payment.ID)
+	var succeeded, failed db.Refund
+	for _, refund := range refunds {
+		if refund.IdempotencyKey == "refund-1" {
+			succeeded = refund
+		} else {
+			failed = refund
+		}
+	}
+	if provider.calls[0].OutTradeNo == nil || *provider.calls[0].OutTradeNo != "TMO20261017-000123" {
+		t.Fatalf("expected the refund to use the payment's out_trade_no, got %v", provider.calls[0].OutTradeNo)
+	}
+	if store.payments[payment.ID].RefundedFen != 4000 || succeeded.ProviderRefundNo == nil {
+		t.Fatalf("expected both pending refunds to be reserved, got %d", store.payments[payment.ID].RefundedFen)
+	}
+
+	if rec := notify(`{"out_refund_no":"` + succeeded.ID.String() + `","refund_status":"SUCCESS"}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := notify(`{"out_refund_no":"` + succeeded.ID.String() + `","refund_status":"SUCCESS"}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected a repeated notify to be accepted, got %d", rec.Code)
+	}
+	if rec := notify(`{"out_refund_no":"` + failed.ID.String() + `","refund_status":"ABNORMAL","message":"account frozen"}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	got := store.payments[payment.ID]
+	if got.Status != paymentStatusPartiallyRefunded || got.RefundedFen != 2000 {
+		t.Fatalf("expected the failed refund to release its reservation, got %s/%d", got.Status, got.RefundedFen)
+	}
+	if store.refunds[failed.ID].Status != refundStatusFailed || store.refunds[succeeded.ID].Status != refundStatusSucceeded {
+		t.Fatalf("unexpected refund states: %#v", store.refunds)
+	}
+	if len(store.webhooks) != 3 || len(commerce.syncRequests) != 1 {
+		t.Fatalf("expected three webhooks and one sync, got %d and %d", len(store.webhooks), len(commerce.syncRequests))
+	}
+	if rec := notify(`{"out_request_no":"` + succeeded.ID.String() + `","status":"SUCCESS"}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected the alipay-style payload to parse, got %d", rec.Code)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/payments/alipay/refund-notify", strings.NewReader(`{"refundId":"`+succeeded.ID.String()+`","status":"SUCCESS"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected a wechat refund to be rejected on the alipay notify, got %d", rec.Code)
+	}
+}
+
+func TestPostAdminPaymentsRefundsRetriesAfterProviderError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newPaymentStoreStub()
+	payment, _ := store.CreatePayment(context.Background(), db.CreatePaymentParams{OrderID: uuid.New(), Channel: paymentChannelWechat, Status: paymentStatusPaid, AmountFen: 800, Currency: "CNY"})
+	provider := &refundProviderStub{err: errors.New("timeout")}
+	router := newTestRouter(&Handler{Store: store, Providers: Providers{paymentChannelWechat: provider}})
+
+	refund := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/payments/transactions/"+payment.ID.String()+"/refunds", nil)
+		req.Header.Set("Idempotency-Key", "refund-1")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := refund(); rec.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := store.payments[payment.ID]; got.RefundedFen != 800 || got.Status != paymentStatusPaid {
+		t.Fatalf("expected the amount to stay reserved, got %s/%d", got.Status, got.RefundedFen)
+	}
+
+	provider.err = nil
+	provider.results = []RefundResult{{Status: refundStatusSucceeded, ProviderRefundNo: strPtr("wx-refund-9")}}
+	if rec := refund(); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"status":"succeeded"`) {
+		t.Fatalf("expected the retry to settle the refund, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(provider.calls) != 2 || provider.calls[0].RefundID != provider.calls[1].RefundID {
+		t.Fatalf("expected the same refund to be resubmitted, got %#v", provider.calls)
+	}
+	if store.payments[payment.ID].Status != paymentStatusRefunded {
+		t.Fatalf("expected payment to be refunded, got %s", store.payments[payment.ID].Status)
+	}
+}
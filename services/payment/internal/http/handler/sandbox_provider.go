@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/payment/internal/http/oapi"
+)
+
+const (
+	SandboxResultSuccess = "SUCCESS"
+	SandboxResultFailed  = "FAILED"
+	// SandboxResultNone never sends a notify; the payment stays pending until
+	// it is rechecked or closed.
+	SandboxResultNone = "NONE"
+
+	sandboxSignatureHeader = "X-Sandbox-Signature"
+	sandboxNotifyAttempts  = 5
+)
+
+// SandboxConfig controls the local sandbox channel. NotifyBaseURL is the base
+// URL of this payment service; the sandbox posts its notifies there exactly
+// like a real channel would.
+type SandboxConfig struct {
+	NotifyBaseURL string
+	NotifyDelay   time.Duration
+	Result        string
+	Secret        string
+	Logger        *slog.Logger
+}
+
+// SandboxProvider simulates a payment channel without any external service.
+// Every session settles after NotifyDelay with the configured result, which
+// is delivered as a signed async notify to the regular notify endpoints.
+// Refunds are settled the same way through the refund notify endpoints.
+type SandboxProvider struct {
+	config SandboxConfig
+	client *http.Client
+
+	mu     sync.Mutex
+	trades map[uuid.UUID]*sandboxTrade
+}
+
+type sandboxTrade struct {
+	channel   string
+	tradeNo   string
+	status    string
+	amountFen int64
+}
+
+func NewSandboxProvider(config SandboxConfig) (*SandboxProvider, error) {
+	config.NotifyBaseURL = strings.TrimRight(strings.TrimSpace(config.NotifyBaseURL), "/")
+	if config.NotifyBaseURL == "" {
+		return nil, fmt.Errorf("sandbox notify base URL is required")
+	}
+	if strings.TrimSpace(config.Secret) == "" {
+		return nil, fmt.Errorf("sandbox secret is required")
+	}
+	config.Result = strings.ToUpper(strings.TrimSpace(config.Result))
+	switch config.Result {
+	case "":
+		config.Result = SandboxResultSuccess
+	case SandboxResultSuccess, SandboxResultFailed, SandboxResultNone:
+	default:
+		return nil, fmt.Errorf("sandbox result must be SUCCESS, FAILED or NONE")
+	}
+	if config.NotifyDelay < 0 {
+		config.NotifyDelay = 0
+	}
+	return &SandboxProvider{config: config, client: &http.Client{Timeout: 5 * time.Second}, trades: make(map[uuid.UUID]*sandboxTrade)}, nil
+}
+
+func (p *SandboxProvider) CreateSession(_ context.Context, request SessionRequest) (Session, error) {
+	tradeNo := "sandbox_" + uuid.NewString()
+	session := Session{ProviderTradeNo: &tradeNo}
+	switch request.Channel {
+	case paymentChannelWechat:
+		prepayID := "sandbox_prepay_" + uuid.NewString()
+		session.ProviderPrepayID = &prepayID
+		session.Response = oapi.WechatPayCreateResponse{
+			OrderId:   request.OrderID,
+			Channel:   oapi.PaymentChannel(paymentChannelWechat),
+			Status:    oapi.PaymentStatus(paymentStatusPending),
+			ExpiresAt: request.ExpiresAt,
+			PrepayId:  prepayID,
+			Package:   "prepay_id=" + prepayID,
+			NonceStr:  uuid.NewString(),
+			TimeStamp: fmt.Sprint(request.CreatedAt.Unix()),
+			SignType:  "HMAC-SHA256",
+			PaySign:   hmacSHA256Hex(p.config.Secret, prepayID),
+		}
+	case paymentChannelWechatB2B:
+		session.Response = oapi.WechatB2BPayCreateResponse{
+			OrderId:         request.OrderID,
+			Channel:         oapi.WECHATB2B,
+			Status:          oapi.PaymentStatus(paymentStatusPending),
+			ExpiresAt:       request.ExpiresAt,
+			CommonPayParams: map[string]interface{}{"mode": "sandbox", "tradeNo": tradeNo},
+		}
+	case paymentChannelAlipay:
+		session.Response = oapi.AlipayPayCreateResponse{
+			OrderId:   request.OrderID,
+			Channel:   oapi.PaymentChannel(paymentChannelAlipay),
+			Status:    oapi.PaymentStatus(paymentStatusPending),
+			ExpiresAt: request.ExpiresAt,
+			TradeNo:   tradeNo,
+			PayParams: map[string]interface{}{"tradeNO": tradeNo},
+		}
+	default:
+		return Session{}, fmt.Errorf("%w: sandbox %s sessions", ErrProviderUnsupported, strings.ToLower(request.Channel))
+	}
+
+	p.mu.Lock()
+	p.trades[request.PaymentID] = &sandboxTrade{channel: request.Channel, tradeNo: tradeNo, status: paymentStatusPending, amountFen: request.AmountFen}
+	p.mu.Unlock()
+	if p.config.Result != SandboxResultNone {
+		time.AfterFunc(p.config.NotifyDelay, func() { p.settlePayment(request.PaymentID) })
+	}
+	return session, nil
+}
+
+func (p *SandboxProvider) Query(_ context.Context, payment ProviderPayment) (QueryResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	trade, ok := p.trades[payment.PaymentID]
+	if !ok {
+		return QueryResult{}, fmt.Errorf("sandbox trade %s not found", payment.PaymentID)
+	}
+	return QueryResult{Status: trade.status, ProviderTradeNo: &trade.tradeNo}, nil
+}
+
+func (p *SandboxProvider) Close(_ context.Context, payment ProviderPayment) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if trade, ok := p.trades[payment.PaymentID]; ok && trade.status == paymentStatusPending {
+		trade.status = paymentStatusCancelled
+	}
+	return nil
+}
+
+// Refund accepts every refund and settles it through the refund notify.
+func (p *SandboxProvider) Refund(_ context.Context, request RefundRequest) (RefundResult, error) {
+	p.mu.Lock()
+	trade, ok := p.trades[request.PaymentID]
+	p.mu.Unlock()
+	if !ok {
+		return RefundResult{}, fmt.Errorf("sandbox trade %s not found", request.PaymentID)
+	}
+	refundNo := "sandbox_refund_" + request.RefundID.String()
+	if p.config.Result != SandboxResultNone {
+		channel := trade.channel
+		time.AfterFunc(p.config.NotifyDelay, func() {
+			p.deliver(sandboxNotifyPath(channel, "refund-notify"), map[string]interface{}{
+				"refundId":         request.RefundID.String(),
+				"status":           p.config.Result,
+				"providerRefundNo": refundNo,
+				"eventType":        "refund.sandbox",
+			})
+		})
+	}
+	return RefundResult{Status: refundStatusPending, ProviderRefundNo: &refundNo}, nil
+}
+
+func (p *SandboxProvider) VerifyNotify(_ context.Context, request NotifyRequest) (map[string]interface{}, error) {
+	expected := hmacSHA256Hex(p.config.Secret, string(request.Body))
+	if !hmac.Equal([]byte(expected), []byte(request.Header.Get(sandboxSignatureHeader))) {
+		return nil, errors.New("sandbox signature mismatch")
+	}
+	return decodeNotifyJSON(request.Body)
+}
+
+func (p *SandboxProvider) settlePayment(paymentID uuid.UUID) {
+	p.mu.Lock()
+	trade, ok := p.trades[paymentID]
+	if !ok || trade.status != paymentStatusPending {
+		p.mu.Unlock()
+		return
+	}
+	trade.status = paymentStatusPaid
+	if p.config.Result == SandboxResultFailed {
+		trade.status = paymentStatusFailed
+	}
+	channel, tradeNo, amountFen := trade.channel, trade.tradeNo, trade.amountFen
+	p.mu.Unlock()
+
+	p.deliver(sandboxNotifyPath(channel, "notify"), map[string]interface{}{
+		"paymentId":       paymentID.String(),
+		"status":          p.config.Result,
+		"providerTradeNo": tradeNo,
+		"amountFen":       amountFen,
+		"eventType":       "payment.sandbox",
+	})
+}
+
+// deliver posts a signed notify and retries like a real channel until the
+// payment service acknowledges it. The first attempt may race the insert of
+// the payment row, which the retry absorbs.
+func (p *SandboxProvider) deliver(path string, payload map[string]interface{}) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	backoff := 200 * time.Millisecond
+	for attempt := 1; attempt <= sandboxNotifyAttempts; attempt++ {
+		status, err := p.post(path, body)
+		if err == nil && status >= 200 && status < 300 {
+			return
+		}
+		if p.config.Logger != nil {
+			p.config.Logger.Warn("sandbox notify not acknowledged", "path", path, "attempt", attempt, "status", status, "error", err)
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (p *SandboxProvider) post(path string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, p.config.NotifyBaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(sandboxSignatureHeader, hmacSHA256Hex(p.config.Secret, string(body)))
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func sandboxNotifyPath(channel, kind string) string {
+	if channel == paymentChannelAlipay {
+		return "/payments/alipay/" + kind
+	}
+	return "/payments/wechat/" + kind
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/teamdsb/tmo/services/payment/internal/db"
+)
+
+func TestSandboxProviderSettlesPaymentThroughNotify(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	orderID := uuid.New()
+	store := newPaymentStoreStub()
+	commerce := newCommerceServerStub(CommerceOrder{
+		ID:            orderID.String(),
+		Status:        "SUBMITTED",
+		PaymentStatus: "UNPAID",
+		Items:         []CommerceOrderItem{{Qty: 1, UnitPriceFen: 4200}},
+	})
+	defer commerce.Close()
+
+	h := &Handler{
+		Flags:        StaticFlagsProvider{Flags: FeatureFlags{PaymentEnabled: true, WechatPayEnabled: true, AlipayPayEnabled: true}},
+		Store:        store,
+		Commerce:     NewCommerceClient(commerce.URL(), "sync-token"),
+		ProviderMode: "sandbox",
+	}
+	server := httptest.NewServer(newTestRouter(h))
+	defer server.Close()
+	sandbox, err := NewSandboxProvider(SandboxConfig{NotifyBaseURL: server.URL, Secret: "sandbox-secret"})
+	if err != nil {
+		t.Fatalf("new sandbox provider: %v", err)
+	}
+	h.Providers = Providers{paymentChannelAlipay: sandbox}
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/payments/alipay/create", strings.NewReader(`{"orderId":"`+orderID.String()+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "sandbox-1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("create payment: %v", err)
+	}
+	defer resp.Body.Close()
+	var created struct {
+		PaymentID string `json:"paymentId"`
+		TradeNo   string `json:"tradeNo"`
+	}
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&created) != nil {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if !strings.HasPrefix(created.TradeNo, "sandbox_") {
+		t.Fatalf("expected a sandbox trade number, got %q", created.TradeNo)
+	}
+
+	paymentID := uuid.MustParse(created.PaymentID)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		payment, _ := store.GetPayment(context.Background(), paymentID)
+		if payment.Status == paymentStatusPaid {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the sandbox notify to mark the payment paid, got %s", payment.Status)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	for len(commerce.syncStatuses()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if statuses := commerce.syncStatuses(); len(statuses) != 2 || statuses[1] != paymentStatusPaid {
+		t.Fatalf("unexpected sync statuses: %v", statuses)
+	}
+}
+
+func TestSandboxProviderRejectsUnsignedNotify(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	paymentID := uuid.New()
+	store := newPaymentStoreStub()
+	store.payments[paymentID] = db.Payment{ID: paymentID, OrderID: uuid.New(), Channel: paymentChannelWechat, Status: paymentStatusPending, AmountFen: 100, Currency: "CNY"}
+	sandbox, err := NewSandboxProvider(SandboxConfig{NotifyBaseURL: "http://127.0.0.1:1", Secret: "sandbox-secret"})
+	if err != nil {
+		t.Fatalf("new sandbox provider: %v", err)
+	}
+	router := newTestRouter(&Handler{Store: store, ProviderMode: "sandbox", Providers: Providers{paymentChannelWechat: sandbox}})
+
+	body := `{"paymentId":"` + paymentID.String() + `","status":"SUCCESS"}`
+	req := httptest.NewRequest(http.MethodPost, "/payments/wechat/notify", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(sandboxSignatureHeader, hmacSHA256Hex("wrong-secret", body))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if store.payments[paymentID].Status != paymentStatusPending {
+		t.Fatalf("expected the notify to be ignored, got %s", store.payments[paymentID].Status)
+	}
+	if len(store.webhooks) != 1 || store.webhooks[0].DeliveryStatus != webhookStatusRejected || store.webhooks[0].RejectionReason == nil {
+		t.Fatalf("expected one rejected webhook, got %#v", store.webhooks)
+	}
+}
+
+func TestRecheckQueriesSandboxProvider(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	orderID := uuid.New()
+	paymentID := uuid.New()
+	store := newPaymentStoreStub()
+	store.payments[paymentID] = db.Payment{
+		ID:        paymentID,
+		OrderID:   orderID,
+		Channel:   paymentChannelWechat,
+		Status:    paymentStatusPending,
+		AmountFen: 900,
+		Currency:  "CNY",
+		CreatedAt: pgtype.Timestamptz{Time: time.Now().UTC(), Valid: true},
+	}
+	commerce := newCommerceServerStub(CommerceOrder{ID: orderID.String(), Status: "PAY_PENDING", PaymentStatus: "PAY_PENDING"})
+	defer commerce.Close()
+	sandbox, err := NewSandboxProvider(SandboxConfig{NotifyBaseURL: "http://127.0.0.1:1", Result: SandboxResultNone, Secret: "sandbox-secret"})
+	if err != nil {
+		t.Fatalf("new sandbox provider: %v", err)
+	}
+	if _, err := sandbox.CreateSession(context.Background(), SessionRequest{PaymentID: paymentID, OrderID: orderID, Channel: paymentChannelWechat}); err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	router := newTestRouter(&Handler{
+		Flags:        StaticFlagsProvider{Flags: FeatureFlags{PaymentEnabled: true, WechatPayEnabled: true}},
+		Store:        store,
+		Commerce:     NewCommerceClient(commerce.URL(), "sync-token"),
+		ProviderMode: "sandbox",
+		Providers:    Providers{paymentChannelWechat: sandbox},
+	})
+
+	recheck := func() {
+		req := httptest.NewRequest(http.MethodPost, "/payments/"+paymentID.String()+"/recheck", strings.NewReader(`{"clientResult":"SUCCESS"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	recheck()
+	if store.payments[paymentID].Status != paymentStatusPending {
+		t.Fatalf("expected the client result to be ignored outside mock mode, got %s", store.payments[paymentID].Status)
+	}
+	if err := sandbox.Close(context.Background(), ProviderPayment{PaymentID: paymentID}); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	recheck()
+	if store.payments[paymentID].Status != paymentStatusCancelled {
+		t.Fatalf("expected the closed sandbox trade to cancel the payment, got %s", store.payments[paymentID].Status)
+	}
+}
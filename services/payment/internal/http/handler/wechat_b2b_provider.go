@@ -3,26 +3,63 @@ package handler
 import (
 	"context"
 	"crypto/hmac"
+	"crypto/sha1"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/teamdsb/tmo/services/payment/internal/http/oapi"
+)
+
+const (
+	defaultWechatTokenURL     = "https://api.weixin.qq.com/cgi-bin/token"
+	defaultWechatB2BRefundURL = "https://api.weixin.qq.com/retail/B2b/refund"
+	wechatB2BRefundURI        = "/retail/B2b/refund"
 )
 
 // WechatB2BConfig contains server-only B2B credentials. Store these values in
 // deployment secrets, never in miniapp code or a committed env file.
+// NotifyToken and NotifyAESKey are the message push token and EncodingAESKey
+// of the mini program; B2B notifies are only accepted in safe mode.
 type WechatB2BConfig struct {
 	AppID, AppSecret, MchID, AppKey, SessionURL string
+	TokenURL, RefundURL                         string
+	NotifyToken, NotifyAESKey                   string
 	Environment                                 int
 }
 
+// WechatB2BPaymentRequest describes a B2B trade. OrderNo is sent as
+// out_trade_no; trades of orders without one fall back to the order id.
+type WechatB2BPaymentRequest struct {
+	OrderID   uuid.UUID
+	OrderNo   string
+	AmountFen int64
+	ExpiresAt time.Time
+	LoginCode string
+}
+
+// WechatB2BDirectProvider creates the signed payload required by the WeChat
+// B2B Store Assistant API. It runs on the server because the protocol
+// credentials must never reach a miniapp.
 type WechatB2BDirectProvider struct {
 	config WechatB2BConfig
 	client *http.Client
+
+	tokenMu        sync.Mutex
+	accessToken    string
+	tokenExpiresAt time.Time
 }
 
 func NewWechatB2BDirectProvider(config WechatB2BConfig) (*WechatB2BDirectProvider, error) {
@@ -32,9 +69,99 @@ func NewWechatB2BDirectProvider(config WechatB2BConfig) (*WechatB2BDirectProvide
 	if config.Environment != 0 && config.Environment != 1 {
 		return nil, fmt.Errorf("wechat b2b environment must be 0 or 1")
 	}
+	if strings.TrimSpace(config.TokenURL) == "" {
+		config.TokenURL = defaultWechatTokenURL
+	}
+	if strings.TrimSpace(config.RefundURL) == "" {
+		config.RefundURL = defaultWechatB2BRefundURL
+	}
 	return &WechatB2BDirectProvider{config: config, client: http.DefaultClient}, nil
 }
 
+func (p *WechatB2BDirectProvider) CreateSession(ctx context.Context, request SessionRequest) (Session, error) {
+	params, err := p.CreateCommonPayParams(ctx, WechatB2BPaymentRequest{OrderID: request.OrderID, OrderNo: request.OrderNo, AmountFen: request.AmountFen, ExpiresAt: request.ExpiresAt, LoginCode: request.LoginCode})
+	if err != nil {
+		return Session{}, fmt.Errorf("create wechat b2b parameters: %w", err)
+	}
+	if len(params) == 0 {
+		return Session{}, fmt.Errorf("wechat b2b provider returned empty payment parameters")
+	}
+	outTradeNo := wechatB2BOutTradeNo(request.OrderID, request.OrderNo)
+	return Session{Response: oapi.WechatB2BPayCreateResponse{
+		OrderId:         request.OrderID,
+		Channel:         oapi.WECHATB2B,
+		Status:          oapi.PaymentStatus(paymentStatusPending),
+		ExpiresAt:       request.ExpiresAt,
+		CommonPayParams: params,
+	}, OutTradeNo: &outTradeNo}, nil
+}
+
+// Query and Close are not offered for B2B trades; B2B payments settle through
+// notifies and expire on the WeChat side.
+func (p *WechatB2BDirectProvider) Query(context.Context, ProviderPayment) (QueryResult, error) {
+	return QueryResult{}, ErrProviderUnsupported
+}
+
+func (p *WechatB2BDirectProvider) Close(context.Context, ProviderPayment) error {
+	return ErrProviderUnsupported
+}
+
+// VerifyNotify authenticates a safe-mode message push: msg_signature is the
+// SHA1 of the sorted token, timestamp, nonce and ciphertext, and the message
+// itself is decrypted with the EncodingAESKey. Plaintext pushes carry no
+// signature over the body and are rejected.
+func (p *WechatB2BDirectProvider) VerifyNotify(_ context.Context, request NotifyRequest) (map[string]interface{}, error) {
+	if strings.TrimSpace(p.config.NotifyToken) == "" || strings.TrimSpace(p.config.NotifyAESKey) == "" {
+		return nil, errors.New("wechat b2b notify verification is not configured")
+	}
+	var envelope struct {
+		Encrypt string `json:"Encrypt"`
+	}
+	if err := json.Unmarshal(request.Body, &envelope); err != nil || envelope.Encrypt == "" {
+		return nil, errors.New("wechat b2b notify is not encrypted")
+	}
+	parts := []string{p.config.NotifyToken, request.Query.Get("timestamp"), request.Query.Get("nonce"), envelope.Encrypt}
+	sort.Strings(parts)
+	digest := sha1.Sum([]byte(strings.Join(parts, "")))
+	if subtle.ConstantTimeCompare([]byte(hex.EncodeToString(digest[:])), []byte(request.Query.Get("msg_signature"))) != 1 {
+		return nil, errors.New("wechat b2b notify signature mismatch")
+	}
+	message, err := decryptWechatMessage(p.config.NotifyAESKey, p.config.AppID, envelope.Encrypt)
+	if err != nil {
+		return nil, fmt.Errorf("wechat b2b notify: %w", err)
+	}
+	payload, err := decodeNotifyJSON(message)
+	if err != nil {
+		return nil, err
+	}
+	if mchID := readString(payload, "mchid"); mchID != "" && mchID != p.config.MchID {
+		return nil, fmt.Errorf("wechat b2b notify is for merchant %s", mchID)
+	}
+
+	payload["eventType"] = readString(payload, "Event")
+	switch readString(payload, "Event") {
+	case "retail_pay_notify":
+		// attach carries the order id; out_trade_no is the order number.
+		payload["orderId"] = readString(payload, "attach", "out_trade_no")
+		payload["status"] = paymentStatusPending
+		if readString(payload, "pay_status") == "ORDER_PAY_SUCC" {
+			payload["status"] = paymentStatusPaid
+		}
+		payload["providerTradeNo"] = readString(payload, "order_id")
+		if amount, ok := payload["amount"].(map[string]interface{}); ok {
+			payload["amountFen"] = amount["order_amount"]
+		}
+	case "retail_refund_notify":
+		switch readString(payload, "refund_status") {
+		case "REFUND_SUCC":
+			payload["refund_status"] = refundStatusSucceeded
+		case "REFUND_FAIL":
+			payload["refund_status"] = refundStatusFailed
+		}
+	}
+	return payload, nil
+}
+
 func (p *WechatB2BDirectProvider) CreateCommonPayParams(ctx context.Context, request WechatB2BPaymentRequest) (map[string]interface{}, error) {
 	if strings.TrimSpace(request.LoginCode) == "" {
 		return nil, fmt.Errorf("wechat login code is required")
@@ -44,7 +171,7 @@ func (p *WechatB2BDirectProvider) CreateCommonPayParams(ctx context.Context, req
 		return nil, err
 	}
 	signDataBytes, err := json.Marshal(map[string]interface{}{
-		"mchid": p.config.MchID, "out_trade_no": request.OrderID.String(), "description": "云互惠直采订单",
+		"mchid": p.config.MchID, "out_trade_no": wechatB2BOutTradeNo(request.OrderID, request.OrderNo), "description": "云互惠直采订单",
 		"amount": map[string]interface{}{"order_amount": request.AmountFen, "currency": "CNY"}, "attach": request.OrderID.String(), "env": p.config.Environment,
 	})
 	if err != nil {
@@ -54,6 +181,13 @@ func (p *WechatB2BDirectProvider) CreateCommonPayParams(ctx context.Context, req
 	return map[string]interface{}{"signData": signData, "mode": "retail_pay_goods", "paySig": hmacSHA256Hex(p.config.AppKey, "requestCommonPayment&"+signData), "signature": hmacSHA256Hex(sessionKey, signData)}, nil
 }
 
+func wechatB2BOutTradeNo(orderID uuid.UUID, orderNo string) string {
+	if orderNo = strings.TrimSpace(orderNo); orderNo != "" {
+		return orderNo
+	}
+	return orderID.String()
+}
+
 func (p *WechatB2BDirectProvider) sessionKey(ctx context.Context, code string) (string, error) {
 	u, err := url.Parse(p.config.SessionURL)
 	if err != nil {
@@ -92,6 +226,109 @@ func (p *WechatB2BDirectProvider) sessionKey(ctx context.Context, code string) (
 	return payload.SessionKey, nil
 }
 
+// Refund submits a B2B refund. WeChat settles it asynchronously and reports
+// the outcome through the refund notify, so an accepted refund stays pending.
+func (p *WechatB2BDirectProvider) Refund(ctx context.Context, request RefundRequest) (RefundResult, error) {
+	accessToken, err := p.token(ctx)
+	if err != nil {
+		return RefundResult{}, err
+	}
+	var orderNo string
+	if request.OutTradeNo != nil {
+		orderNo = *request.OutTradeNo
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"mchid": p.config.MchID, "out_trade_no": wechatB2BOutTradeNo(request.OrderID, orderNo), "out_refund_no": request.RefundID.String(),
+		"refund_amount": request.AmountFen, "refund_from": 1, "refund_reason": 0,
+	})
+	if err != nil {
+		return RefundResult{}, err
+	}
+	u, err := url.Parse(p.config.RefundURL)
+	if err != nil {
+		return RefundResult{}, fmt.Errorf("parse wechat b2b refund URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("access_token", accessToken)
+	q.Set("pay_sig", hmacSHA256Hex(p.config.AppKey, wechatB2BRefundURI+"&"+string(body)))
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), strings.NewReader(string(body)))
+	if err != nil {
+		return RefundResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var payload struct {
+		RefundID string `json:"refund_id"`
+		ErrCode  int    `json:"errcode"`
+		ErrMsg   string `json:"errmsg"`
+	}
+	if err := p.doJSON(req, &payload); err != nil {
+		return RefundResult{}, fmt.Errorf("call wechat b2b refund: %w", err)
+	}
+	if payload.ErrCode != 0 {
+		message := fmt.Sprintf("%d: %s", payload.ErrCode, payload.ErrMsg)
+		return RefundResult{Status: refundStatusFailed, FailureMessage: &message}, nil
+	}
+	result := RefundResult{Status: refundStatusPending}
+	if payload.RefundID != "" {
+		result.ProviderRefundNo = &payload.RefundID
+	}
+	return result, nil
+}
+
+func (p *WechatB2BDirectProvider) token(ctx context.Context) (string, error) {
+	p.tokenMu.Lock()
+	defer p.tokenMu.Unlock()
+	if p.accessToken != "" && time.Now().Before(p.tokenExpiresAt) {
+		return p.accessToken, nil
+	}
+	u, err := url.Parse(p.config.TokenURL)
+	if err != nil {
+		return "", fmt.Errorf("parse wechat token URL: %w", err)
+	}
+	q := u.Query()
+	q.Set("grant_type", "client_credential")
+	q.Set("appid", p.config.AppID)
+	q.Set("secret", p.config.AppSecret)
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	var payload struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		ErrCode     int    `json:"errcode"`
+		ErrMsg      string `json:"errmsg"`
+	}
+	if err := p.doJSON(req, &payload); err != nil {
+		return "", fmt.Errorf("call wechat access token: %w", err)
+	}
+	if payload.ErrCode != 0 || payload.AccessToken == "" {
+		return "", fmt.Errorf("wechat access token failed: %d %s", payload.ErrCode, payload.ErrMsg)
+	}
+	// Refresh a minute early so a token never expires mid-request.
+	p.accessToken = payload.AccessToken
+	p.tokenExpiresAt = time.Now().Add(time.Duration(payload.ExpiresIn)*time.Second - time.Minute)
+	return p.accessToken, nil
+}
+
+func (p *WechatB2BDirectProvider) doJSON(req *http.Request, out interface{}) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return json.Unmarshal(body, out)
+}
+
 func hmacSHA256Hex(key, message string) string {
 	mac := hmac.New(sha256.New, []byte(key))
 	_, _ = mac.Write([]byte(message))
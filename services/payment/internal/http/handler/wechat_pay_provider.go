@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultWechatPayBaseURL = "https://api.mch.weixin.qq.com"
+	wechatPayRefundPath     = "/v3/refund/domestic/refunds"
+	// wechatNotifyMaxSkew bounds the age of a notify timestamp so captured
+	// deliveries cannot be replayed later.
+	wechatNotifyMaxSkew = 5 * time.Minute
+)
+
+// WechatPayConfig contains the merchant credentials used to sign WeChat Pay
+// API v3 requests. Keep the private key and the APIv3 key in deployment
+// secrets. Notifies are verified with the platform certificate or public key
+// and decrypted with the APIv3 key; without them every notify is rejected.
+type WechatPayConfig struct {
+	MchID, SerialNo, PrivateKeyPEM, BaseURL, RefundNotifyURL string
+	APIv3Key, PlatformKeyPEM, PlatformSerial                 string
+}
+
+// WechatPayProvider talks to the WeChat Pay API v3 merchant endpoints. JSAPI
+// sessions need the payer openid, which the payment service does not hold, so
+// miniapp payments go through the B2B channel and this provider only covers
+// query, close and refund.
+type WechatPayProvider struct {
+	config      WechatPayConfig
+	privateKey  *rsa.PrivateKey
+	platformKey *rsa.PublicKey
+	client      *http.Client
+}
+
+func NewWechatPayProvider(config WechatPayConfig) (*WechatPayProvider, error) {
+	if strings.TrimSpace(config.MchID) == "" || strings.TrimSpace(config.SerialNo) == "" || strings.TrimSpace(config.PrivateKeyPEM) == "" {
+		return nil, fmt.Errorf("wechat pay credentials are incomplete")
+	}
+	privateKey, err := parseRSAPrivateKey(config.PrivateKeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parse wechat pay private key: %w", err)
+	}
+	if strings.TrimSpace(config.BaseURL) == "" {
+		config.BaseURL = defaultWechatPayBaseURL
+	}
+	config.BaseURL = strings.TrimRight(strings.TrimSpace(config.BaseURL), "/")
+	provider := &WechatPayProvider{config: config, privateKey: privateKey, client: &http.Client{Timeout: 10 * time.Second}}
+	if strings.TrimSpace(config.PlatformKeyPEM) != "" {
+		platformKey, err := parseRSAPublicKey(config.PlatformKeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("parse wechat pay platform key: %w", err)
+		}
+		provider.platformKey = platformKey
+	}
+	if config.APIv3Key != "" && len(config.APIv3Key) != 32 {
+		return nil, fmt.Errorf("wechat pay apiv3 key must be 32 bytes")
+	}
+	return provider, nil
+}
+
+func (p *WechatPayProvider) CreateSession(context.Context, SessionRequest) (Session, error) {
+	return Session{}, fmt.Errorf("%w: wechat pay jsapi sessions", ErrProviderUnsupported)
+}
+
+func (p *WechatPayProvider) Query(ctx context.Context, payment ProviderPayment) (QueryResult, error) {
+	path := "/v3/pay/transactions/out-trade-no/" + url.PathEscape(payment.OrderID.String())
+	if payment.ProviderTradeNo != nil && strings.TrimSpace(*payment.ProviderTradeNo) != "" {
+		path = "/v3/pay/transactions/id/" + url.PathEscape(strings.TrimSpace(*payment.ProviderTradeNo))
+	}
+	path += "?mchid=" + url.QueryEscape(p.config.MchID)
+	status, raw, err := p.do(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return QueryResult{}, fmt.Errorf("call wechat pay query: %w", err)
+	}
+	if status < 200 || status >= 300 {
+		return QueryResult{}, fmt.Errorf("wechat pay query failed with status %d", status)
+	}
+	var response struct {
+		TransactionID string `json:"transaction_id"`
+		TradeState    string `json:"trade_state"`
+	}
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return QueryResult{}, fmt.Errorf("decode wechat pay query response: %w", err)
+	}
+	result := QueryResult{Status: normalizeWechatTradeState(response.TradeState)}
+	if response.TransactionID != "" {
+		result.ProviderTradeNo = &response.TransactionID
+	}
+	return result, nil
+}
+
+func (p *WechatPayProvider) Close(ctx context.Context, payment ProviderPayment) error {
+	body, err := json.Marshal(map[string]string{"mchid": p.config.MchID})
+	if err != nil {
+		return err
+	}
+	status, _, err := p.do(ctx, http.MethodPost, "/v3/pay/transactions/out-trade-no/"+url.PathEscape(payment.OrderID.String())+"/close", body)
+	if err != nil {
+		return fmt.Errorf("call wechat pay close: %w", err)
+	}
+	if status < 200 || status >= 300 {
+		return fmt.Errorf("wechat pay close failed with status %d", status)
+	}
+	return nil
+}
+
+func (p *WechatPayProvider) Refund(ctx context.Context, request RefundRequest) (RefundResult, error) {
+	payload := map[string]interface{}{
+		"out_refund_no": request.RefundID.String(),
+		"amount":        map[string]interface{}{"refund": request.AmountFen, "total": request.TotalFen, "currency": "CNY"},
+	}
+	if request.ProviderTradeNo != nil && strings.TrimSpace(*request.ProviderTradeNo) != "" {
+		payload["transaction_id"] = strings.TrimSpace(*request.ProviderTradeNo)
+	} else {
+		payload["out_trade_no"] = request.OrderID.String()
+	}
+	if strings.TrimSpace(request.Reason) != "" {
+		payload["reason"] = request.Reason
+	}
+	if strings.TrimSpace(p.config.RefundNotifyURL) != "" {
+		payload["notify_url"] = strings.TrimSpace(p.config.RefundNotifyURL)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return RefundResult{}, err
+	}
+	status, raw, err := p.do(ctx, http.MethodPost, wechatPayRefundPath, body)
+	if err != nil {
+		return RefundResult{}, fmt.Errorf("call wechat pay refund: %w", err)
+	}
+	var response struct {
+		RefundID string `json:"refund_id"`
+		Status   string `json:"status"`
+		Code     string `json:"code"`
+		Message  string `json:"message"`
+	}
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return RefundResult{}, fmt.Errorf("decode wechat pay refund response: %w", err)
+	}
+	if status >= 500 {
+		return RefundResult{}, fmt.Errorf("wechat pay refund failed with status %d", status)
+	}
+	if status < 200 || status >= 300 {
+		message := fmt.Sprintf("%s: %s", response.Code, response.Message)
+		return RefundResult{Status: refundStatusFailed, FailureMessage: &message}, nil
+	}
+	result := RefundResult{Status: normalizeRefundStatus(response.Status)}
+	if response.RefundID != "" {
+		result.ProviderRefundNo = &response.RefundID
+	}
+	return result, nil
+}
+
+// VerifyNotify checks the Wechatpay-Signature over timestamp, nonce and body,
+// then decrypts the AEAD_AES_256_GCM resource with the APIv3 key. The returned
+// payload is the decrypted resource; payment notifies also carry the order,
+// status, trade number and amount under the keys handleNotify reads.
+func (p *WechatPayProvider) VerifyNotify(_ context.Context, request NotifyRequest) (map[string]interface{}, error) {
+	if p.platformKey == nil || p.config.APIv3Key == "" {
+		return nil, errors.New("wechat pay notify verification is not configured")
+	}
+	serial := strings.TrimSpace(request.Header.Get("Wechatpay-Serial"))
+	if p.config.PlatformSerial != "" && serial != p.config.PlatformSerial {
+		return nil, fmt.Errorf("unknown wechat pay platform serial %q", serial)
+	}
+	timestamp := strings.TrimSpace(request.Header.Get("Wechatpay-Timestamp"))
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid wechat pay notify timestamp")
+	}
+	if skew := time.Since(time.Unix(seconds, 0)); skew > wechatNotifyMaxSkew || skew < -wechatNotifyMaxSkew {
+		return nil, errors.New("wechat pay notify timestamp is outside the allowed window")
+	}
+	message := timestamp + "\n" + request.Header.Get("Wechatpay-Nonce") + "\n" + string(request.Body) + "\n"
+	if err := verifySHA256WithRSA(message, request.Header.Get("Wechatpay-Signature"), p.platformKey); err != nil {
+		return nil, fmt.Errorf("wechat pay notify: %w", err)
+	}
+
+	var envelope struct {
+		EventType string `json:"event_type"`
+		Resource  struct {
+			Algorithm      string `json:"algorithm"`
+			Ciphertext     string `json:"ciphertext"`
+			AssociatedData string `json:"associated_data"`
+			Nonce          string `json:"nonce"`
+		} `json:"resource"`
+	}
+	if err := json.Unmarshal(request.Body, &envelope); err != nil {
+		return nil, errors.New("invalid wechat pay notify body")
+	}
+	if envelope.Resource.Algorithm != "AEAD_AES_256_GCM" {
+		return nil, fmt.Errorf("unsupported wechat pay resource algorithm %q", envelope.Resource.Algorithm)
+	}
+	plaintext, err := decryptAESGCM(p.config.APIv3Key, envelope.Resource.Nonce, envelope.Resource.AssociatedData, envelope.Resource.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("wechat pay notify: %w", err)
+	}
+	resource, err := decodeNotifyJSON(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	if mchID := readString(resource, "mchid", "sp_mchid"); mchID != "" && mchID != p.config.MchID {
+		return nil, fmt.Errorf("wechat pay notify is for merchant %s", mchID)
+	}
+	resource["eventType"] = envelope.EventType
+	if tradeState := readString(resource, "trade_state"); tradeState != "" {
+		resource["orderId"] = readString(resource, "out_trade_no")
+		resource["status"] = normalizeWechatTradeState(tradeState)
+		resource["providerTradeNo"] = readString(resource, "transaction_id")
+		if amount, ok := resource["amount"].(map[string]interface{}); ok {
+			resource["amountFen"] = amount["total"]
+		}
+	}
+	return resource, nil
+}
+
+// do sends one signed API v3 request and returns the status and raw body.
+func (p *WechatPayProvider) do(ctx context.Context, method, path string, body []byte) (int, []byte, error) {
+	authorization, err := p.authorization(method, path, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.config.BaseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", authorization)
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, raw, nil
+}
+
+// authorization builds the WECHATPAY2-SHA256-RSA2048 header for one request.
+func (p *WechatPayProvider) authorization(method, path string, body []byte) (string, error) {
+	nonce := strings.ReplaceAll(uuid.NewString(), "-", "")
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	message := method + "\n" + path + "\n" + timestamp + "\n" + nonce + "\n" + string(body) + "\n"
+	signature, err := signSHA256WithRSA(message, p.privateKey)
+	if err != nil {
+		return "", fmt.Errorf("sign wechat pay request: %w", err)
+	}
+	return fmt.Sprintf(`WECHATPAY2-SHA256-RSA2048 mchid="%s",nonce_str="%s",signature="%s",timestamp="%s",serial_no="%s"`,
+		p.config.MchID, nonce, signature, timestamp, p.config.SerialNo), nil
+}
+
+// normalizeWechatTradeState maps WeChat trade states onto payment statuses.
+func normalizeWechatTradeState(state string) string {
+	switch strings.ToUpper(strings.TrimSpace(state)) {
+	case "SUCCESS", "REFUND":
+		return paymentStatusPaid
+	case "CLOSED", "REVOKED":
+		return paymentStatusCancelled
+	case "PAYERROR":
+		return paymentStatusFailed
+	default:
+		return paymentStatusPending
+	}
+}
@@ -9,7 +9,10 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 
+	"github.com/teamdsb/tmo/packages/go-shared/authz"
 	apierrors "github.com/teamdsb/tmo/packages/go-shared/errors"
+	"github.com/teamdsb/tmo/packages/go-shared/jwks"
+	"github.com/teamdsb/tmo/packages/go-shared/revocation"
 )
 
 type Claims struct {
@@ -18,15 +21,19 @@ type Claims struct {
 }
 
 type Authenticator struct {
-	enabled bool
-	secret  []byte
-	issuer  string
+	enabled     bool
+	keys        *jwks.Cache
+	issuer      string
+	authorizer  *authz.Authorizer
+	revocations *revocation.Registry
 }
 
-func NewAuthenticator(enabled bool, secret, issuer string) *Authenticator {
+// NewAuthenticator verifies access tokens against the public keys identity
+// publishes in keys; the service never holds anything that can sign a token.
+func NewAuthenticator(enabled bool, keys *jwks.Cache, issuer string) *Authenticator {
 	return &Authenticator{
 		enabled: enabled,
-		secret:  []byte(secret),
+		keys:    keys,
 		issuer:  issuer,
 	}
 }
@@ -39,6 +46,37 @@ func (a *Authenticator) RequireUser(c *gin.Context) (Claims, bool) {
 	return claims, true
 }
 
+// WithAuthorizer makes RequirePermission resolve grants through authorizer.
+// Without one the built-in default matrix is used.
+func (a *Authenticator) WithAuthorizer(authorizer *authz.Authorizer) *Authenticator {
+	a.authorizer = authorizer
+	return a
+}
+
+// WithRevocations makes the authenticator reject access tokens whose token
+// version identity has since bumped (logout from all devices, disabled
+// accounts).
+func (a *Authenticator) WithRevocations(revocations *revocation.Registry) *Authenticator {
+	a.revocations = revocations
+	return a
+}
+
+// RequirePermission authenticates the caller and checks that their role holds
+// permission with at least the required scope. It returns the scope the role
+// holds so handlers can narrow what the caller sees.
+func (a *Authenticator) RequirePermission(c *gin.Context, permission string, required authz.Scope) (Claims, authz.Scope, bool) {
+	claims, ok := a.parseClaims(c)
+	if !ok {
+		return Claims{}, "", false
+	}
+	scope, ok := a.authorizer.Allows(claims.Role, permission, required)
+	if !ok {
+		writeError(c, http.StatusForbidden, "forbidden", "permission denied")
+		return Claims{}, "", false
+	}
+	return claims, scope, true
+}
+
 func (a *Authenticator) parseClaims(c *gin.Context) (Claims, bool) {
 	if !a.enabled {
 		return Claims{UserID: uuid.Nil, Role: "ADMIN"}, true
@@ -55,11 +93,16 @@ func (a *Authenticator) parseClaims(c *gin.Context) (Claims, bool) {
 	}
 
 	token, err := jwt.Parse(parts[1], func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unsupported signing method")
+		kid, _ := token.Header["kid"].(string)
+		key, err := a.keys.Key(c.Request.Context(), kid)
+		if err != nil {
+			return nil, err
 		}
-		return a.secret, nil
-	})
+		if token.Method.Alg() != key.Algorithm {
+			return nil, errors.New("signing method does not match key")
+		}
+		return key.Public, nil
+	}, jwt.WithValidMethods(jwks.Algorithms))
 	if err != nil || !token.Valid {
 		writeError(c, http.StatusUnauthorized, "unauthorized", "invalid token")
 		return Claims{}, false
@@ -86,6 +129,14 @@ func (a *Authenticator) parseClaims(c *gin.Context) (Claims, bool) {
 		writeError(c, http.StatusUnauthorized, "unauthorized", "invalid subject")
 		return Claims{}, false
 	}
+	tokenVersion := 0
+	if rawVersion, ok := mapClaims["tv"].(float64); ok {
+		tokenVersion = int(rawVersion)
+	}
+	if a.revocations.Revoked(userID, tokenVersion) {
+		writeError(c, http.StatusUnauthorized, "unauthorized", "token revoked")
+		return Claims{}, false
+	}
 	role, _ := mapClaims["role"].(string)
 	return Claims{UserID: userID, Role: role}, true
 }
@@ -34,10 +34,12 @@ const (
 
 // Defines values for PaymentStatus.
 const (
-	PaymentStatusCANCELLED  PaymentStatus = "CANCELLED"
-	PaymentStatusPAID       PaymentStatus = "PAID"
-	PaymentStatusPAYFAILED  PaymentStatus = "PAY_FAILED"
-	PaymentStatusPAYPENDING PaymentStatus = "PAY_PENDING"
+	PaymentStatusCANCELLED         PaymentStatus = "CANCELLED"
+	PaymentStatusPAID              PaymentStatus = "PAID"
+	PaymentStatusPARTIALLYREFUNDED PaymentStatus = "PARTIALLY_REFUNDED"
+	PaymentStatusPAYFAILED         PaymentStatus = "PAY_FAILED"
+	PaymentStatusPAYPENDING        PaymentStatus = "PAY_PENDING"
+	PaymentStatusREFUNDED          PaymentStatus = "REFUNDED"
 )
 
 // AlipayPayCreateResponse defines model for AlipayPayCreateResponse.
@@ -136,6 +138,9 @@ type PostPaymentsAlipayCreateParams struct {
 // PostPaymentsAlipayNotifyJSONBody defines parameters for PostPaymentsAlipayNotify.
 type PostPaymentsAlipayNotifyJSONBody map[string]interface{}
 
+// PostPaymentsAlipayRefundNotifyJSONBody defines parameters for PostPaymentsAlipayRefundNotify.
+type PostPaymentsAlipayRefundNotifyJSONBody map[string]interface{}
+
 // PostPaymentsWechatB2bCreateJSONBody defines parameters for PostPaymentsWechatB2bCreate.
 type PostPaymentsWechatB2bCreateJSONBody struct {
 	OrderId         openapi_types.UUID `json:"orderId"`
@@ -160,12 +165,18 @@ type PostPaymentsWechatCreateParams struct {
 // PostPaymentsWechatNotifyJSONBody defines parameters for PostPaymentsWechatNotify.
 type PostPaymentsWechatNotifyJSONBody map[string]interface{}
 
+// PostPaymentsWechatRefundNotifyJSONBody defines parameters for PostPaymentsWechatRefundNotify.
+type PostPaymentsWechatRefundNotifyJSONBody map[string]interface{}
+
 // PostPaymentsAlipayCreateJSONRequestBody defines body for PostPaymentsAlipayCreate for application/json ContentType.
 type PostPaymentsAlipayCreateJSONRequestBody PostPaymentsAlipayCreateJSONBody
 
 // PostPaymentsAlipayNotifyJSONRequestBody defines body for PostPaymentsAlipayNotify for application/json ContentType.
 type PostPaymentsAlipayNotifyJSONRequestBody PostPaymentsAlipayNotifyJSONBody
 
+// PostPaymentsAlipayRefundNotifyJSONRequestBody defines body for PostPaymentsAlipayRefundNotify for application/json ContentType.
+type PostPaymentsAlipayRefundNotifyJSONRequestBody PostPaymentsAlipayRefundNotifyJSONBody
+
 // PostPaymentsWechatB2bCreateJSONRequestBody defines body for PostPaymentsWechatB2bCreate for application/json ContentType.
 type PostPaymentsWechatB2bCreateJSONRequestBody PostPaymentsWechatB2bCreateJSONBody
 
@@ -175,6 +186,9 @@ type PostPaymentsWechatCreateJSONRequestBody PostPaymentsWechatCreateJSONBody
 // PostPaymentsWechatNotifyJSONRequestBody defines body for PostPaymentsWechatNotify for application/json ContentType.
 type PostPaymentsWechatNotifyJSONRequestBody PostPaymentsWechatNotifyJSONBody
 
+// PostPaymentsWechatRefundNotifyJSONRequestBody defines body for PostPaymentsWechatRefundNotify for application/json ContentType.
+type PostPaymentsWechatRefundNotifyJSONRequestBody PostPaymentsWechatRefundNotifyJSONBody
+
 // PostPaymentsPaymentIdRecheckJSONRequestBody defines body for PostPaymentsPaymentIdRecheck for application/json ContentType.
 type PostPaymentsPaymentIdRecheckJSONRequestBody = PaymentRecheckRequest
 
@@ -186,6 +200,9 @@ type ServerInterface interface {
 	// Alipay callback (no auth, signature verified)
 	// (POST /payments/alipay/notify)
 	PostPaymentsAlipayNotify(c *gin.Context)
+	// Alipay refund callback (no auth, signature verified)
+	// (POST /payments/alipay/refund-notify)
+	PostPaymentsAlipayRefundNotify(c *gin.Context)
 	// Create a WeChat B2B store-assistant payment for an order
 	// (POST /payments/wechat/b2b/create)
 	PostPaymentsWechatB2bCreate(c *gin.Context, params PostPaymentsWechatB2bCreateParams)
@@ -195,6 +212,9 @@ type ServerInterface interface {
 	// WeChat pay callback (no auth, signature verified)
 	// (POST /payments/wechat/notify)
 	PostPaymentsWechatNotify(c *gin.Context)
+	// WeChat refund callback (no auth, signature verified)
+	// (POST /payments/wechat/refund-notify)
+	PostPaymentsWechatRefundNotify(c *gin.Context)
 	// Get payment detail
 	// (GET /payments/{paymentId})
 	GetPaymentsPaymentId(c *gin.Context, paymentId openapi_types.UUID)
@@ -266,6 +286,19 @@ func (siw *ServerInterfaceWrapper) PostPaymentsAlipayNotify(c *gin.Context) {
 	siw.Handler.PostPaymentsAlipayNotify(c)
 }
 
+// PostPaymentsAlipayRefundNotify operation middleware
+func (siw *ServerInterfaceWrapper) PostPaymentsAlipayRefundNotify(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostPaymentsAlipayRefundNotify(c)
+}
+
 // PostPaymentsWechatB2bCreate operation middleware
 func (siw *ServerInterfaceWrapper) PostPaymentsWechatB2bCreate(c *gin.Context) {
 
@@ -361,6 +394,19 @@ func (siw *ServerInterfaceWrapper) PostPaymentsWechatNotify(c *gin.Context) {
 	siw.Handler.PostPaymentsWechatNotify(c)
 }
 
+// PostPaymentsWechatRefundNotify operation middleware
+func (siw *ServerInterfaceWrapper) PostPaymentsWechatRefundNotify(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostPaymentsWechatRefundNotify(c)
+}
+
 // GetPaymentsPaymentId operation middleware
 func (siw *ServerInterfaceWrapper) GetPaymentsPaymentId(c *gin.Context) {
 
@@ -442,9 +488,11 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 
 	router.POST(options.BaseURL+"/payments/alipay/create", wrapper.PostPaymentsAlipayCreate)
 	router.POST(options.BaseURL+"/payments/alipay/notify", wrapper.PostPaymentsAlipayNotify)
+	router.POST(options.BaseURL+"/payments/alipay/refund-notify", wrapper.PostPaymentsAlipayRefundNotify)
 	router.POST(options.BaseURL+"/payments/wechat/b2b/create", wrapper.PostPaymentsWechatB2bCreate)
 	router.POST(options.BaseURL+"/payments/wechat/create", wrapper.PostPaymentsWechatCreate)
 	router.POST(options.BaseURL+"/payments/wechat/notify", wrapper.PostPaymentsWechatNotify)
+	router.POST(options.BaseURL+"/payments/wechat/refund-notify", wrapper.PostPaymentsWechatRefundNotify)
 	router.GET(options.BaseURL+"/payments/:paymentId", wrapper.GetPaymentsPaymentId)
 	router.POST(options.BaseURL+"/payments/:paymentId/recheck", wrapper.PostPaymentsPaymentIdRecheck)
 }
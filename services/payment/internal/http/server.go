@@ -24,9 +24,16 @@ func NewRouter(handler *handler.Handler, logger *slog.Logger, readyCheck func(co
 	oapi.RegisterHandlers(router, handler)
 	router.GET("/admin/payments/transactions", handler.GetAdminPaymentsTransactions)
 	router.GET("/admin/payments/transactions/:id", handler.GetAdminPaymentsTransactionsId)
+	router.GET("/admin/payments/transactions/:id/refunds", handler.GetAdminPaymentsTransactionsIdRefunds)
+	router.POST("/admin/payments/transactions/:id/refunds", handler.PostAdminPaymentsTransactionsIdRefunds)
 	router.GET("/admin/payments/audit-logs", handler.GetAdminPaymentsAuditLogs)
 	router.GET("/admin/payments/webhooks", handler.GetAdminPaymentsWebhooks)
 	router.POST("/admin/payments/webhooks/:id/replay", handler.PostAdminPaymentsWebhooksIdReplay)
+	router.GET("/admin/payments/reconciliations", handler.GetAdminPaymentsReconciliations)
+	router.POST("/admin/payments/reconciliations", handler.PostAdminPaymentsReconciliations)
+	router.GET("/admin/payments/reconciliations/:id", handler.GetAdminPaymentsReconciliationsId)
+	router.POST("/internal/orders/:orderId/payments/close", handler.PostInternalOrdersOrderIdPaymentsClose)
+	router.POST("/internal/events", handler.PostInternalEvents)
 
 	return router
 }
@@ -0,0 +1,141 @@
+package reconcile
+
+import (
+	"github.com/google/uuid"
+)
+
+// Discrepancy kinds reported by Compare.
+const (
+	KindMissingInLedger    = "MISSING_IN_LEDGER"
+	KindMissingInStatement = "MISSING_IN_STATEMENT"
+	KindAmountMismatch     = "AMOUNT_MISMATCH"
+	KindStatusMismatch     = "STATUS_MISMATCH"
+)
+
+// LedgerEntry is the side of a payment the statement is checked against.
+type LedgerEntry struct {
+	PaymentID       uuid.UUID
+	OutTradeNo      string
+	ProviderTradeNo string
+	AmountFen       int64
+	Status          string
+}
+
+// Discrepancy describes one difference between the ledger and a statement.
+// Fields of a side that has no record are left empty.
+type Discrepancy struct {
+	Kind               string
+	PaymentID          uuid.UUID
+	OutTradeNo         string
+	ProviderTradeNo    string
+	LedgerAmountFen    *int64
+	StatementAmountFen *int64
+	LedgerStatus       string
+	StatementStatus    string
+}
+
+type Result struct {
+	Matched       int
+	Discrepancies []Discrepancy
+}
+
+// Compare matches statement lines with ledger entries by merchant order number,
+// falling back to the provider trade number. Refund lines are skipped; refunds
+// are settled through their own notifies.
+func Compare(lines []StatementLine, ledger []LedgerEntry) Result {
+	byOutTradeNo := make(map[string]int, len(ledger))
+	byProviderTradeNo := make(map[string]int, len(ledger))
+	for i, entry := range ledger {
+		// An order can hold several payments; the paid one is the one the
+		// statement refers to.
+		if existing, ok := byOutTradeNo[entry.OutTradeNo]; !ok || (!IsPaidStatus(ledger[existing].Status) && IsPaidStatus(entry.Status)) {
+			byOutTradeNo[entry.OutTradeNo] = i
+		}
+		if entry.ProviderTradeNo != "" {
+			byProviderTradeNo[entry.ProviderTradeNo] = i
+		}
+	}
+
+	result := Result{Discrepancies: []Discrepancy{}}
+	seen := make(map[int]bool, len(ledger))
+	for _, line := range lines {
+		if line.Status == StatementStatusRefund {
+			continue
+		}
+		index, ok := byOutTradeNo[line.OutTradeNo]
+		if !ok && line.ProviderTradeNo != "" {
+			index, ok = byProviderTradeNo[line.ProviderTradeNo]
+		}
+		statementAmount := line.AmountFen
+		if !ok {
+			if line.Status == StatementStatusPaid {
+				result.Discrepancies = append(result.Discrepancies, Discrepancy{
+					Kind:               KindMissingInLedger,
+					OutTradeNo:         line.OutTradeNo,
+					ProviderTradeNo:    line.ProviderTradeNo,
+					StatementAmountFen: &statementAmount,
+					StatementStatus:    line.Status,
+				})
+			}
+			continue
+		}
+
+		seen[index] = true
+		entry := ledger[index]
+		ledgerPaid := IsPaidStatus(entry.Status)
+		statementPaid := line.Status == StatementStatusPaid
+		kind := ""
+		switch {
+		case ledgerPaid != statementPaid:
+			kind = KindStatusMismatch
+		case statementPaid && entry.AmountFen != line.AmountFen:
+			kind = KindAmountMismatch
+		}
+		if kind == "" {
+			result.Matched++
+			continue
+		}
+		ledgerAmount := entry.AmountFen
+		providerTradeNo := line.ProviderTradeNo
+		if providerTradeNo == "" {
+			providerTradeNo = entry.ProviderTradeNo
+		}
+		result.Discrepancies = append(result.Discrepancies, Discrepancy{
+			Kind:               kind,
+			PaymentID:          entry.PaymentID,
+			OutTradeNo:         line.OutTradeNo,
+			ProviderTradeNo:    providerTradeNo,
+			LedgerAmountFen:    &ledgerAmount,
+			StatementAmountFen: &statementAmount,
+			LedgerStatus:       entry.Status,
+			StatementStatus:    line.Status,
+		})
+	}
+
+	for i, entry := range ledger {
+		if seen[i] || !IsPaidStatus(entry.Status) {
+			continue
+		}
+		ledgerAmount := entry.AmountFen
+		result.Discrepancies = append(result.Discrepancies, Discrepancy{
+			Kind:            KindMissingInStatement,
+			PaymentID:       entry.PaymentID,
+			OutTradeNo:      entry.OutTradeNo,
+			ProviderTradeNo: entry.ProviderTradeNo,
+			LedgerAmountFen: &ledgerAmount,
+			LedgerStatus:    entry.Status,
+		})
+	}
+	return result
+}
+
+// IsPaidStatus reports whether a ledger status means the money was collected.
+// Refunded payments were paid once and still appear on the day's statement.
+func IsPaidStatus(status string) bool {
+	switch status {
+	case "PAID", "PARTIALLY_REFUNDED", "REFUNDED":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,79 @@
+package reconcile
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParseStatementReadsWechatBill(t *testing.T) {
+	bill := "\ufeff交易时间,公众账号ID,商户号,微信订单号,商户订单号,交易类型,交易状态,应结订单总金额,订单金额\n" +
+		"`2026-03-01 10:00:00,`wx0001,`1900000001,`4200000001,`order-1,`JSAPI,`SUCCESS,`12.30,`12.30\n" +
+		"`2026-03-01 11:00:00,`wx0001,`1900000001,`4200000002,`order-2,`JSAPI,`REFUND,`0.00,`5.00\n" +
+		"`2026-03-01 12:00:00,`wx0001,`1900000001,`4200000003,`order-3,`JSAPI,`REVOKED,`0.00,`8\n" +
+		"总交易单数,应结订单总金额,退款总金额\n" +
+		"`3,`12.30,`5.00\n"
+
+	lines, err := ParseStatement(strings.NewReader(bill))
+	if err != nil {
+		t.Fatalf("parse statement: %v", err)
+	}
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %#v", lines)
+	}
+	first := lines[0]
+	if first.OutTradeNo != "order-1" || first.ProviderTradeNo != "4200000001" || first.AmountFen != 1230 || first.Status != StatementStatusPaid || first.Line != 2 {
+		t.Fatalf("unexpected first line: %#v", first)
+	}
+	if lines[1].Status != StatementStatusRefund || lines[2].Status != StatementStatusClosed || lines[2].AmountFen != 800 {
+		t.Fatalf("unexpected statuses: %#v", lines[1:])
+	}
+}
+
+func TestParseStatementReadsPlainFile(t *testing.T) {
+	lines, err := ParseStatement(strings.NewReader("orderId,providerTradeNo,amountFen\norder-1,T1,1500\n,,\n"))
+	if err != nil {
+		t.Fatalf("parse statement: %v", err)
+	}
+	if len(lines) != 1 || lines[0].AmountFen != 1500 || lines[0].Status != StatementStatusPaid {
+		t.Fatalf("unexpected lines: %#v", lines)
+	}
+}
+
+func TestParseStatementRejectsBadInput(t *testing.T) {
+	if _, err := ParseStatement(strings.NewReader("a,b\n1,2\n")); !errors.Is(err, ErrStatementHeader) {
+		t.Fatalf("expected header error, got %v", err)
+	}
+	_, err := ParseStatement(strings.NewReader("out_trade_no,total_amount\norder-1,12.345\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected amount error on line 2, got %v", err)
+	}
+}
+
+func TestCompareMatchesByOrderThenTradeNumber(t *testing.T) {
+	paidID := uuid.New()
+	ledger := []LedgerEntry{
+		{PaymentID: uuid.New(), OutTradeNo: "order-1", AmountFen: 1000, Status: "PAY_FAILED"},
+		{PaymentID: paidID, OutTradeNo: "order-1", ProviderTradeNo: "T1", AmountFen: 1000, Status: "PAID"},
+		{PaymentID: uuid.New(), OutTradeNo: "order-2", ProviderTradeNo: "T2", AmountFen: 2000, Status: "REFUNDED"},
+		{PaymentID: uuid.New(), OutTradeNo: "order-3", AmountFen: 3000, Status: "PAY_PENDING"},
+	}
+	lines := []StatementLine{
+		{OutTradeNo: "order-1", ProviderTradeNo: "T1", AmountFen: 1000, Status: StatementStatusPaid},
+		{OutTradeNo: "renamed", ProviderTradeNo: "T2", AmountFen: 2000, Status: StatementStatusPaid},
+		{OutTradeNo: "order-2", ProviderTradeNo: "T2", AmountFen: 2000, Status: StatementStatusRefund},
+		{OutTradeNo: "order-3", AmountFen: 3000, Status: StatementStatusClosed},
+	}
+
+	result := Compare(lines, ledger)
+	if result.Matched != 3 || len(result.Discrepancies) != 0 {
+		t.Fatalf("expected every line to match, got %#v", result)
+	}
+
+	result = Compare(lines[:1], ledger)
+	if len(result.Discrepancies) != 1 || result.Discrepancies[0].Kind != KindMissingInStatement || result.Discrepancies[0].OutTradeNo != "order-2" {
+		t.Fatalf("expected the refunded payment to be missing from the statement, got %#v", result.Discrepancies)
+	}
+}
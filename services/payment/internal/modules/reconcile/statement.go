@@ -0,0 +1,190 @@
+package reconcile
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Statement line statuses. Provider specific values are folded into these so
+// WeChat Pay, Alipay and hand-made statements compare the same way.
+const (
+	StatementStatusPaid   = "PAID"
+	StatementStatusRefund = "REFUND"
+	StatementStatusClosed = "CLOSED"
+)
+
+var ErrStatementHeader = errors.New("statement header not found")
+
+// StatementLine is one transaction of a provider statement.
+type StatementLine struct {
+	Line            int
+	OutTradeNo      string
+	ProviderTradeNo string
+	AmountFen       int64
+	Status          string
+}
+
+var (
+	outTradeNoColumns      = []string{"商户订单号", "out_trade_no", "orderid"}
+	providerTradeNoColumns = []string{"微信订单号", "支付宝交易号", "transaction_id", "trade_no", "providertradeno"}
+	amountFenColumns       = []string{"amountfen", "amount_fen"}
+	amountYuanColumns      = []string{"订单金额", "订单金额（元）", "订单金额(元)", "应结订单总金额", "total_amount", "amount"}
+	statusColumns          = []string{"交易状态", "业务类型", "trade_state", "status"}
+)
+
+type statementColumns struct {
+	outTradeNo      int
+	providerTradeNo int
+	amountFen       int
+	amountYuan      int
+	status          int
+}
+
+// ParseStatement reads a daily statement in CSV form. It understands the
+// WeChat Pay and Alipay bill downloads (UTF-8) as well as a plain file with
+// orderId, providerTradeNo, amountFen and status columns. Comment lines, the
+// rows before the header and the summary after the transactions are skipped.
+func ParseStatement(r io.Reader) ([]StatementLine, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	var columns *statementColumns
+	lines := []StatementLine{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read statement: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		for i := range record {
+			record[i] = cleanField(record[i])
+		}
+		if columns == nil {
+			columns = findColumns(record)
+			continue
+		}
+		// WeChat Pay appends a summary block starting with 总交易单数.
+		if strings.HasPrefix(record[0], "总") {
+			break
+		}
+		outTradeNo := field(record, columns.outTradeNo)
+		if outTradeNo == "" {
+			continue
+		}
+		amount, err := columns.amount(record)
+		if err != nil {
+			return nil, fmt.Errorf("statement line %d: %w", line, err)
+		}
+		status := StatementStatusPaid
+		if columns.status >= 0 {
+			status = normalizeStatementStatus(field(record, columns.status))
+		}
+		lines = append(lines, StatementLine{
+			Line:            line,
+			OutTradeNo:      outTradeNo,
+			ProviderTradeNo: field(record, columns.providerTradeNo),
+			AmountFen:       amount,
+			Status:          status,
+		})
+	}
+	if columns == nil {
+		return nil, ErrStatementHeader
+	}
+	return lines, nil
+}
+
+func findColumns(header []string) *statementColumns {
+	columns := &statementColumns{
+		outTradeNo:      indexOf(header, outTradeNoColumns),
+		providerTradeNo: indexOf(header, providerTradeNoColumns),
+		amountFen:       indexOf(header, amountFenColumns),
+		amountYuan:      indexOf(header, amountYuanColumns),
+		status:          indexOf(header, statusColumns),
+	}
+	if columns.outTradeNo < 0 || (columns.amountFen < 0 && columns.amountYuan < 0) {
+		return nil
+	}
+	return columns
+}
+
+func (c *statementColumns) amount(record []string) (int64, error) {
+	if c.amountFen >= 0 {
+		value, err := strconv.ParseInt(field(record, c.amountFen), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid amountFen %q", field(record, c.amountFen))
+		}
+		return value, nil
+	}
+	return parseYuan(field(record, c.amountYuan))
+}
+
+func indexOf(header []string, names []string) int {
+	for _, name := range names {
+		for i, column := range header {
+			if strings.EqualFold(column, name) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func field(record []string, index int) string {
+	if index < 0 || index >= len(record) {
+		return ""
+	}
+	return record[index]
+}
+
+// cleanField drops the byte order mark and the backtick WeChat Pay puts in
+// front of every value to keep spreadsheets from reformatting numbers.
+func cleanField(value string) string {
+	value = strings.TrimPrefix(value, "\ufeff")
+	value = strings.TrimSpace(value)
+	return strings.TrimSpace(strings.TrimPrefix(value, "`"))
+}
+
+func normalizeStatementStatus(value string) string {
+	switch strings.ToUpper(strings.TrimSpace(value)) {
+	case "", "SUCCESS", "PAID", "TRADE_SUCCESS", "TRADE_FINISHED", "交易":
+		return StatementStatusPaid
+	case "REFUND", "REFUNDED", "退款":
+		return StatementStatusRefund
+	case "REVOKED", "CLOSED", "TRADE_CLOSED":
+		return StatementStatusClosed
+	default:
+		return strings.ToUpper(strings.TrimSpace(value))
+	}
+}
+
+// parseYuan converts a yuan amount such as "12.30" to fen without going
+// through floating point.
+func parseYuan(value string) (int64, error) {
+	cleaned := strings.TrimPrefix(strings.ReplaceAll(value, ",", ""), "¥")
+	whole, fraction, _ := strings.Cut(cleaned, ".")
+	if whole == "" || len(fraction) > 2 {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	for len(fraction) < 2 {
+		fraction += "0"
+	}
+	yuan, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || yuan < 0 {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	fen, err := strconv.ParseInt(fraction, 10, 64)
+	if err != nil || fen < 0 {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	return yuan*100 + fen, nil
+}
@@ -0,0 +1,78 @@
+package reconcile
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+const defaultPendingBatch = 100
+
+// PendingSettler asks the channels for the result of payments that are still
+// pending, batchSize payments at a time, and applies final results. It returns
+// how many payments it settled.
+type PendingSettler interface {
+	SettlePendingPayments(ctx context.Context, createdBefore time.Time, batchSize int) (int, error)
+}
+
+// PendingWorker settles payments that stay PAY_PENDING for longer than After,
+// covering notifies the channel never delivered or the service dropped.
+type PendingWorker struct {
+	Settler       PendingSettler
+	After         time.Duration
+	CheckInterval time.Duration
+	BatchSize     int
+	Logger        *slog.Logger
+}
+
+func (w *PendingWorker) Start(ctx context.Context) {
+	if w.Settler == nil {
+		return
+	}
+
+	interval := w.CheckInterval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	after := w.After
+	if after <= 0 {
+		after = 15 * time.Minute
+	}
+
+	go func() {
+		w.runOnce(ctx, after)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.runOnce(ctx, after)
+			}
+		}
+	}()
+}
+
+func (w *PendingWorker) runOnce(ctx context.Context, after time.Duration) {
+	batchSize := w.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultPendingBatch
+	}
+	cutoff := time.Now().UTC().Add(-after)
+	settled, err := w.Settler.SettlePendingPayments(ctx, cutoff, batchSize)
+	if err != nil {
+		w.logError("settle pending payments failed", err)
+		return
+	}
+	if settled > 0 && w.Logger != nil {
+		w.Logger.Info("settled pending payments", "count", settled, "cutoff", cutoff.Format(time.RFC3339))
+	}
+}
+
+func (w *PendingWorker) logError(message string, err error) {
+	if w.Logger != nil {
+		w.Logger.Error(message, "error", err)
+	}
+}
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- refunded_fen reserves the amount of every refund that has not failed, so
+-- concurrent refund requests cannot exceed the paid amount.
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS refunded_fen bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS refunds (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    payment_id uuid NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+    order_id uuid NOT NULL,
+    channel text NOT NULL,
+    status text NOT NULL,
+    amount_fen bigint NOT NULL CHECK (amount_fen > 0),
+    reason text,
+    idempotency_key text NOT NULL,
+    provider_refund_no text,
+    failure_message text,
+    requested_by text NOT NULL,
+    refunded_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS refunds_payment_idempotency_idx ON refunds(payment_id, idempotency_key);
+CREATE INDEX IF NOT EXISTS refunds_order_idx ON refunds(order_id);
+CREATE INDEX IF NOT EXISTS refunds_status_idx ON refunds(status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS refunds;
+ALTER TABLE payments DROP COLUMN IF EXISTS refunded_fen;
+-- +goose StatementEnd
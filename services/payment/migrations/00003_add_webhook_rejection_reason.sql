@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Notifies that fail signature or amount checks are kept for investigation
+-- with delivery_status REJECTED and the reason they were refused.
+ALTER TABLE payment_webhooks ADD COLUMN IF NOT EXISTS rejection_reason text;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE payment_webhooks DROP COLUMN IF EXISTS rejection_reason;
+-- +goose StatementEnd
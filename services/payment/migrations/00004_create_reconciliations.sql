@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A reconciliation run compares one provider statement (a channel's bill for
+-- one day) with the ledger; every difference is kept as a discrepancy.
+CREATE TABLE IF NOT EXISTS reconciliation_runs (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    channel text NOT NULL,
+    statement_date date NOT NULL,
+    file_name text NOT NULL,
+    statement_lines integer NOT NULL,
+    matched_count integer NOT NULL,
+    discrepancy_count integer NOT NULL,
+    created_by text NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS reconciliation_runs_channel_date_idx ON reconciliation_runs(channel, statement_date DESC);
+
+CREATE TABLE IF NOT EXISTS reconciliation_discrepancies (
+    id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    run_id uuid NOT NULL REFERENCES reconciliation_runs(id) ON DELETE CASCADE,
+    kind text NOT NULL,
+    payment_id uuid,
+    out_trade_no text,
+    provider_trade_no text,
+    ledger_amount_fen bigint,
+    statement_amount_fen bigint,
+    ledger_status text,
+    statement_status text,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS reconciliation_discrepancies_run_idx ON reconciliation_discrepancies(run_id);
+CREATE INDEX IF NOT EXISTS payments_status_created_idx ON payments(status, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS payments_status_created_idx;
+DROP TABLE IF EXISTS reconciliation_discrepancies;
+DROP TABLE IF EXISTS reconciliation_runs;
+-- +goose StatementEnd
@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Domain events are written here in the same transaction as the change they
+-- describe; the relay delivers each event once per subscriber.
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id uuid PRIMARY KEY,
+    event_type text NOT NULL,
+    source text NOT NULL,
+    aggregate_id text NOT NULL,
+    payload jsonb NOT NULL,
+    occurred_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS outbox_events_created_idx ON outbox_events(created_at);
+
+CREATE TABLE IF NOT EXISTS outbox_deliveries (
+    event_id uuid NOT NULL REFERENCES outbox_events(id) ON DELETE CASCADE,
+    subscriber text NOT NULL,
+    status text NOT NULL DEFAULT 'PENDING',
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz NOT NULL DEFAULT now(),
+    last_error text,
+    delivered_at timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (event_id, subscriber),
+    CONSTRAINT outbox_deliveries_status_valid CHECK (status IN ('PENDING', 'DELIVERED', 'DEAD'))
+);
+
+CREATE INDEX IF NOT EXISTS outbox_deliveries_due_idx
+    ON outbox_deliveries(next_attempt_at)
+    WHERE status = 'PENDING';
+
+-- Consumers record every event they applied so a redelivery is a no-op.
+CREATE TABLE IF NOT EXISTS processed_events (
+    event_id uuid NOT NULL,
+    consumer text NOT NULL,
+    event_type text NOT NULL,
+    processed_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (event_id, consumer)
+);
+
+CREATE INDEX IF NOT EXISTS processed_events_processed_idx ON processed_events(processed_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS processed_events;
+DROP TABLE IF EXISTS outbox_deliveries;
+DROP TABLE IF EXISTS outbox_events;
+-- +goose StatementEnd
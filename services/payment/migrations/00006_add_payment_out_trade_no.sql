@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The merchant order number sent to the channel. Channels that take the
+-- commerce order number store it here; NULL means the order id was used.
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS out_trade_no text;
+
+CREATE INDEX IF NOT EXISTS payments_out_trade_no_idx
+    ON payments(out_trade_no)
+    WHERE out_trade_no IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS payments_out_trade_no_idx;
+ALTER TABLE payments DROP COLUMN IF EXISTS out_trade_no;
+-- +goose StatementEnd
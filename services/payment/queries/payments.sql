@@ -1,5 +1,6 @@
 -- name: CreatePayment :one
 INSERT INTO payments (
+    id,
     order_id,
     payer_user_id,
     channel,
@@ -13,7 +14,8 @@ INSERT INTO payments (
     failure_code,
     failure_message,
     paid_at,
-    closed_at
+    closed_at,
+    out_trade_no
 ) VALUES (
     $1,
     $2,
@@ -28,7 +30,9 @@ INSERT INTO payments (
     $11,
     $12,
     $13,
-    $14
+    $14,
+    $15,
+    $16
 )
 RETURNING *;
 
@@ -93,14 +97,16 @@ INSERT INTO payment_webhooks (
     event_type,
     delivery_status,
     raw_body,
-    processed_at
+    processed_at,
+    rejection_reason
 ) VALUES (
     $1,
     $2,
     $3,
     $4,
     $5,
-    $6
+    $6,
+    $7
 )
 RETURNING *;
 
@@ -177,3 +183,35 @@ WHERE (
     OR COALESCE(payment_id::text, '') ILIKE '%' || sqlc.narg('q') || '%'
 )
   AND (sqlc.narg('action')::text IS NULL OR action = sqlc.narg('action'));
+
+-- name: ListPaymentsByOrder :many
+SELECT *
+FROM payments
+WHERE order_id = $1
+ORDER BY created_at DESC;
+
+-- name: ListPaymentsByOutTradeNo :many
+SELECT *
+FROM payments
+WHERE out_trade_no = $1
+ORDER BY created_at DESC;
+
+-- name: ListPendingPaymentsCreatedBefore :many
+SELECT *
+FROM payments
+WHERE status = sqlc.arg('status')
+  AND created_at < sqlc.arg('created_before')
+  AND (
+    sqlc.narg('created_after')::timestamptz IS NULL
+    OR (created_at, id) > (sqlc.narg('created_after'), sqlc.arg('after_id')::uuid)
+  )
+ORDER BY created_at, id
+LIMIT sqlc.arg('limit');
+
+-- name: ListPaidPaymentsByChannel :many
+SELECT *
+FROM payments
+WHERE channel = sqlc.arg('channel')
+  AND paid_at >= sqlc.arg('paid_from')
+  AND paid_at < sqlc.arg('paid_to')
+ORDER BY paid_at;
@@ -0,0 +1,52 @@
+-- name: CreateReconciliationRun :one
+INSERT INTO reconciliation_runs (
+    channel,
+    statement_date,
+    file_name,
+    statement_lines,
+    matched_count,
+    discrepancy_count,
+    created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: CreateReconciliationDiscrepancy :one
+INSERT INTO reconciliation_discrepancies (
+    run_id,
+    kind,
+    payment_id,
+    out_trade_no,
+    provider_trade_no,
+    ledger_amount_fen,
+    statement_amount_fen,
+    ledger_status,
+    statement_status
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+)
+RETURNING *;
+
+-- name: GetReconciliationRun :one
+SELECT *
+FROM reconciliation_runs
+WHERE id = $1;
+
+-- name: ListReconciliationRuns :many
+SELECT *
+FROM reconciliation_runs
+WHERE sqlc.narg('channel')::text IS NULL OR channel = sqlc.narg('channel')
+ORDER BY statement_date DESC, created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountReconciliationRuns :one
+SELECT count(*)
+FROM reconciliation_runs
+WHERE sqlc.narg('channel')::text IS NULL OR channel = sqlc.narg('channel');
+
+-- name: ListReconciliationDiscrepancies :many
+SELECT *
+FROM reconciliation_discrepancies
+WHERE run_id = $1
+ORDER BY created_at, id;
@@ -0,0 +1,72 @@
+-- name: CreateRefund :one
+WITH reserved AS (
+    UPDATE payments
+    SET refunded_fen = refunded_fen + sqlc.arg('amount_fen'),
+        updated_at = now()
+    WHERE payments.id = sqlc.arg('payment_id')
+      AND payments.status = ANY(sqlc.arg('refundable_statuses')::text[])
+      AND payments.amount_fen - payments.refunded_fen >= sqlc.arg('amount_fen')
+    RETURNING payments.id, payments.order_id, payments.channel
+)
+INSERT INTO refunds (
+    payment_id,
+    order_id,
+    channel,
+    status,
+    amount_fen,
+    reason,
+    idempotency_key,
+    requested_by
+)
+SELECT
+    reserved.id,
+    reserved.order_id,
+    reserved.channel,
+    sqlc.arg('status'),
+    sqlc.arg('amount_fen'),
+    sqlc.narg('reason'),
+    sqlc.arg('idempotency_key'),
+    sqlc.arg('requested_by')
+FROM reserved
+RETURNING *;
+
+-- name: GetRefund :one
+SELECT *
+FROM refunds
+WHERE id = $1;
+
+-- name: GetRefundByIdempotencyKey :one
+SELECT *
+FROM refunds
+WHERE payment_id = $1
+  AND idempotency_key = $2;
+
+-- name: ListRefundsByPayment :many
+SELECT *
+FROM refunds
+WHERE payment_id = $1
+ORDER BY created_at DESC;
+
+-- name: UpdateRefundState :one
+UPDATE refunds
+SET status = sqlc.arg('status'),
+    provider_refund_no = COALESCE(sqlc.narg('provider_refund_no'), provider_refund_no),
+    failure_message = sqlc.narg('failure_message'),
+    refunded_at = sqlc.narg('refunded_at'),
+    updated_at = now()
+WHERE id = sqlc.arg('id')
+  AND status = sqlc.arg('from_status')
+RETURNING *;
+
+-- name: ReleaseRefundReservation :one
+UPDATE payments
+SET refunded_fen = refunded_fen - sqlc.arg('amount_fen'),
+    updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: SumSucceededRefunds :one
+SELECT COALESCE(sum(amount_fen), 0)::bigint
+FROM refunds
+WHERE payment_id = $1
+  AND status = 'SUCCEEDED';
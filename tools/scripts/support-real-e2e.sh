@@ -38,7 +38,6 @@ export SUPPORT_REAL_E2E_REPLY_TEXT="$reply_text"
 export IDENTITY_ENABLE_PHONE_PROOF_SIMULATION="${IDENTITY_ENABLE_PHONE_PROOF_SIMULATION:-true}"
 export TARO_APP_WEAPP_PHONE_PROOF_SIMULATION="${TARO_APP_WEAPP_PHONE_PROOF_SIMULATION:-true}"
 export COMMERCE_AUTH_ENABLED="${COMMERCE_AUTH_ENABLED:-true}"
-export COMMERCE_JWT_SECRET="${COMMERCE_JWT_SECRET:-${IDENTITY_JWT_SECRET:-dev-secret}}"
 export COMMERCE_JWT_ISSUER="${COMMERCE_JWT_ISSUER:-${IDENTITY_JWT_ISSUER:-tmo-identity}}"
 
 phase "artifact file: $artifact_file"